@@ -0,0 +1,178 @@
+// Package audit keeps the append-only log of the changes made to the users,
+// lists, groups and tasks, whoever makes them: a user through the API, or the
+// server itself through its background jobs.
+//
+// The changes are recorded by the services that make them, which know what
+// they change and how, into a Trail. The services learn where a change comes
+// from through an Origin; the handlers take it from the request.
+package audit
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"noda/data/model"
+	"noda/data/types"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Log keeps the entries of the audit log.
+type Log interface {
+	Save(entry *model.AuditEntry) (insertedID string, err error)
+}
+
+// redacted replaces the values of the fields that must never be logged.
+const redacted = "[REDACTED]"
+
+// Origin is where a change comes from: the user that makes it and the client
+// it is made with. The changes made by the server itself have a zero Origin.
+type Origin struct {
+	ActorUUID *uuid.UUID
+	IP        string
+	UserAgent string
+}
+
+// OriginOf tells where the changes made while serving the request come from.
+// The actor is the user of its access token, if it was authorized.
+func OriginOf(r *http.Request) Origin {
+	var origin = Origin{IP: extractIP(r), UserAgent: r.UserAgent()}
+	if payload, ok := r.Context().Value(types.ContextKey{}).(types.JWTPayload); ok && uuid.Nil != payload.UserID {
+		origin.ActorUUID = &payload.UserID
+	}
+	return origin
+}
+
+// As returns the origin with the given actor, for the changes whose actor is
+// known better by the service than by the request.
+func (o Origin) As(actorID uuid.UUID) Origin {
+	if uuid.Nil != actorID {
+		o.ActorUUID = &actorID
+	}
+	return o
+}
+
+// Trail records the changes into a Log. A failure to record a change is
+// logged, and never undoes nor fails the change. A nil Trail records nothing.
+type Trail struct {
+	log Log
+	now func() time.Time
+}
+
+func NewTrail(log Log) *Trail {
+	return &Trail{log: log, now: time.Now}
+}
+
+// Record records that the action was done on the entity with the given kind
+// and identifier, e.g. "task", which was in the state before and is in the
+// state after; before is nil for a creation and after is nil for a removal.
+// When both are objects, only their fields that differ are kept. Passwords,
+// tokens and secrets are redacted.
+func (t *Trail) Record(origin Origin, entity string, entityID uuid.UUID, action string, before, after any) {
+	if nil == t {
+		return
+	}
+	var entry = &model.AuditEntry{
+		ActorUUID:  origin.ActorUUID,
+		Action:     action,
+		Entity:     entity,
+		IP:         origin.IP,
+		UserAgent:  origin.UserAgent,
+		OccurredAt: t.now(),
+	}
+	if uuid.Nil != entityID {
+		entry.EntityUUID = &entityID
+	}
+	entry.Before, entry.After = diff(marshal(before), marshal(after))
+	if _, err := t.log.Save(entry); nil != err {
+		log.Printf("could not audit %s %s %q: %v", entity, action, entityID, err)
+	}
+}
+
+// marshal encodes a state as a redacted JSON document. It returns nil for a
+// nil state, including a nil pointer.
+func marshal(state any) json.RawMessage {
+	if nil == state {
+		return nil
+	}
+	if value := reflect.ValueOf(state); reflect.Pointer == value.Kind() && value.IsNil() {
+		return nil
+	}
+	document, err := json.Marshal(state)
+	if nil != err {
+		log.Printf("could not encode an audited state: %v", err)
+		return nil
+	}
+	return redact(document)
+}
+
+// redact hides the values of the passwords, tokens and secrets in a JSON
+// document. It returns nil if the document is not valid JSON.
+func redact(document []byte) json.RawMessage {
+	var value any
+	if nil != json.Unmarshal(document, &value) {
+		return nil
+	}
+	var walk func(value any)
+	walk = func(value any) {
+		switch value := value.(type) {
+		case map[string]any:
+			for key, field := range value {
+				var lowered = strings.ToLower(key)
+				if strings.Contains(lowered, "password") ||
+					strings.Contains(lowered, "token") ||
+					strings.Contains(lowered, "secret") {
+					value[key] = redacted
+					continue
+				}
+				walk(field)
+			}
+		case []any:
+			for _, item := range value {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	redactedDocument, _ := json.Marshal(value)
+	return redactedDocument
+}
+
+// diff keeps only the fields that differ between two JSON objects, and
+// returns nil for both if none does. Anything other than two objects is kept
+// as it is.
+func diff(before, after json.RawMessage) (json.RawMessage, json.RawMessage) {
+	var previous, current map[string]any
+	if nil != json.Unmarshal(before, &previous) || nil != json.Unmarshal(after, &current) || nil == previous || nil == current {
+		return before, after
+	}
+	var changedBefore, changedAfter = make(map[string]any), make(map[string]any)
+	for key, value := range previous {
+		if other, ok := current[key]; !ok || !reflect.DeepEqual(value, other) {
+			changedBefore[key] = value
+		}
+	}
+	for key, value := range current {
+		if other, ok := previous[key]; !ok || !reflect.DeepEqual(value, other) {
+			changedAfter[key] = value
+		}
+	}
+	if 0 == len(changedBefore) && 0 == len(changedAfter) {
+		return nil, nil
+	}
+	before, _ = json.Marshal(changedBefore)
+	after, _ = json.Marshal(changedAfter)
+	return before, after
+}
+
+func extractIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if nil != err {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/types"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func beQuiet() func() {
+	log.SetOutput(io.Discard)
+	return func() { log.SetOutput(os.Stderr) }
+}
+
+type memoryLog struct {
+	entries []*model.AuditEntry
+	err     error
+}
+
+func (l *memoryLog) Save(entry *model.AuditEntry) (string, error) {
+	if nil != l.err {
+		return "", l.err
+	}
+	l.entries = append(l.entries, entry)
+	return uuid.NewString(), nil
+}
+
+type state struct {
+	Title    string `json:"title"`
+	Priority string `json:"priority"`
+}
+
+func TestTrail_Record(t *testing.T) {
+	defer beQuiet()()
+	var (
+		actorID, taskID = uuid.New(), uuid.New()
+		now             = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+		origin          = Origin{ActorUUID: &actorID, IP: "10.0.0.7", UserAgent: "curl/8.0"}
+	)
+	var newTrail = func(l Log) *Trail {
+		var trail = NewTrail(l)
+		trail.now = func() time.Time { return now }
+		return trail
+	}
+
+	t.Run("keeps the fields that changed", func(t *testing.T) {
+		var l = new(memoryLog)
+		newTrail(l).Record(origin, "task", taskID, "updated",
+			&state{Title: "old", Priority: "low"}, &state{Title: "new", Priority: "low"})
+		require.Len(t, l.entries, 1)
+		var entry = l.entries[0]
+		assert.Equal(t, &actorID, entry.ActorUUID)
+		assert.Equal(t, "updated", entry.Action)
+		assert.Equal(t, "task", entry.Entity)
+		assert.Equal(t, &taskID, entry.EntityUUID)
+		assert.JSONEq(t, `{"title":"old"}`, string(entry.Before))
+		assert.JSONEq(t, `{"title":"new"}`, string(entry.After))
+		assert.Equal(t, "10.0.0.7", entry.IP)
+		assert.Equal(t, "curl/8.0", entry.UserAgent)
+		assert.Equal(t, now, entry.OccurredAt)
+	})
+
+	t.Run("keeps the created and the removed thing", func(t *testing.T) {
+		var l = new(memoryLog)
+		var trail = newTrail(l)
+		var missing *state
+		trail.Record(origin, "task", taskID, "created", nil, &state{Title: "new"})
+		trail.Record(origin, "task", taskID, "deleted", &state{Title: "new"}, missing)
+		require.Len(t, l.entries, 2)
+		assert.Nil(t, l.entries[0].Before)
+		assert.JSONEq(t, `{"title":"new","priority":""}`, string(l.entries[0].After))
+		assert.JSONEq(t, `{"title":"new","priority":""}`, string(l.entries[1].Before))
+		assert.Nil(t, l.entries[1].After)
+	})
+
+	t.Run("redacts the secrets", func(t *testing.T) {
+		var l = new(memoryLog)
+		newTrail(l).Record(Origin{}, "user", uuid.Nil, "purged", nil,
+			map[string]any{"password": "a", "nested": map[string]any{"access_token": "b"}, "purged": 2})
+		require.Len(t, l.entries, 1)
+		assert.Nil(t, l.entries[0].ActorUUID)
+		assert.Nil(t, l.entries[0].EntityUUID)
+		assert.JSONEq(t, `{"password":"[REDACTED]","nested":{"access_token":"[REDACTED]"},"purged":2}`, string(l.entries[0].After))
+	})
+
+	t.Run("a failure to record is only logged", func(t *testing.T) {
+		var l = &memoryLog{err: errors.New("unexpected error")}
+		assert.NotPanics(t, func() {
+			newTrail(l).Record(origin, "task", taskID, "trashed", nil, nil)
+		})
+	})
+
+	t.Run("a nil trail records nothing", func(t *testing.T) {
+		var trail *Trail
+		assert.NotPanics(t, func() {
+			trail.Record(origin, "task", taskID, "trashed", nil, nil)
+		})
+	})
+}
+
+func TestOriginOf(t *testing.T) {
+	var userID = uuid.New()
+
+	t.Run("authorized request", func(t *testing.T) {
+		var request = httptest.NewRequest("PUT", "/users/"+uuid.NewString()+"/block", nil)
+		request.RemoteAddr = "10.0.0.7:52114"
+		request.Header.Set("User-Agent", "curl/8.0")
+		request = request.WithContext(context.WithValue(request.Context(), types.ContextKey{}, types.JWTPayload{UserID: userID}))
+		assert.Equal(t, Origin{ActorUUID: &userID, IP: "10.0.0.7", UserAgent: "curl/8.0"}, OriginOf(request))
+	})
+
+	t.Run("anonymous request", func(t *testing.T) {
+		var request = httptest.NewRequest("POST", "/signup", nil)
+		request.RemoteAddr = "10.0.0.7"
+		var origin = OriginOf(request)
+		assert.Nil(t, origin.ActorUUID)
+		assert.Equal(t, "10.0.0.7", origin.IP)
+	})
+
+	t.Run("actor known by the service", func(t *testing.T) {
+		var origin = Origin{IP: "10.0.0.7"}.As(userID)
+		assert.Equal(t, &userID, origin.ActorUUID)
+		assert.Equal(t, "10.0.0.7", origin.IP)
+		assert.Nil(t, Origin{}.As(uuid.Nil).ActorUUID)
+	})
+}
@@ -0,0 +1,343 @@
+// Package caldav reads the requests and writes the responses of WebDAV (RFC
+// 4918) and CalDAV (RFC 4791), as far as serving tasks as calendars needs.
+//
+// PROPFIND and REPORT requests are read into the names of the properties they
+// ask for; the answer is a multistatus made of one response per resource, with
+// the properties found and the ones that were not.
+package caldav
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// The namespaces of the properties.
+const (
+	NamespaceDAV       = "DAV:"
+	NamespaceCalDAV    = "urn:ietf:params:xml:ns:caldav"
+	NamespaceCalServer = "http://calendarserver.org/ns/"
+)
+
+// prefixes are the prefixes the namespaces are written with. Names in other
+// namespaces get theirs where they are written.
+var prefixes = map[string]string{
+	NamespaceDAV:       "d",
+	NamespaceCalDAV:    "c",
+	NamespaceCalServer: "cs",
+}
+
+// Names of the properties and elements in use.
+var (
+	ResourceType         = xml.Name{Space: NamespaceDAV, Local: "resourcetype"}
+	DisplayName          = xml.Name{Space: NamespaceDAV, Local: "displayname"}
+	GetETag              = xml.Name{Space: NamespaceDAV, Local: "getetag"}
+	GetContentType       = xml.Name{Space: NamespaceDAV, Local: "getcontenttype"}
+	CurrentUserPrincipal = xml.Name{Space: NamespaceDAV, Local: "current-user-principal"}
+	PrincipalURL         = xml.Name{Space: NamespaceDAV, Local: "principal-URL"}
+	Owner                = xml.Name{Space: NamespaceDAV, Local: "owner"}
+	Href                 = xml.Name{Space: NamespaceDAV, Local: "href"}
+	Collection           = xml.Name{Space: NamespaceDAV, Local: "collection"}
+	Principal            = xml.Name{Space: NamespaceDAV, Local: "principal"}
+	CalendarHomeSet      = xml.Name{Space: NamespaceCalDAV, Local: "calendar-home-set"}
+	Calendar             = xml.Name{Space: NamespaceCalDAV, Local: "calendar"}
+	CalendarData         = xml.Name{Space: NamespaceCalDAV, Local: "calendar-data"}
+	CalendarDescription  = xml.Name{Space: NamespaceCalDAV, Local: "calendar-description"}
+	SupportedComponents  = xml.Name{Space: NamespaceCalDAV, Local: "supported-calendar-component-set"}
+	Comp                 = xml.Name{Space: NamespaceCalDAV, Local: "comp"}
+	GetCTag              = xml.Name{Space: NamespaceCalServer, Local: "getctag"}
+)
+
+// Depth is the Depth header of a request: how far below the resource of the
+// request it goes.
+type Depth int
+
+const (
+	DepthZero Depth = iota
+	DepthOne
+	DepthInfinity
+)
+
+// ParseDepth reads a Depth header. A missing one is infinity, as RFC 4918
+// says for PROPFIND.
+func ParseDepth(header string) (Depth, error) {
+	switch strings.ToLower(strings.TrimSpace(header)) {
+	case "0":
+		return DepthZero, nil
+	case "1":
+		return DepthOne, nil
+	case "", "infinity":
+		return DepthInfinity, nil
+	}
+	return DepthZero, fmt.Errorf("invalid Depth %q", header)
+}
+
+// Props are the names of the properties a request asks for, or all of them if
+// All is set.
+type Props struct {
+	All   bool
+	Names []xml.Name
+}
+
+// Wants tells whether the property is asked for. Properties that are costly,
+// such as calendar-data, are only given when asked for by name.
+func (p *Props) Wants(name xml.Name) bool {
+	for _, wanted := range p.Names {
+		if wanted == name {
+			return true
+		}
+	}
+	return p.All && CalendarData != name
+}
+
+// names collects the names of the elements of a prop element.
+type names []xml.Name
+
+func (n *names) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		token, err := d.Token()
+		if nil != err {
+			return err
+		}
+		switch token := token.(type) {
+		case xml.StartElement:
+			*n = append(*n, token.Name)
+			if err := d.Skip(); nil != err {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type propfind struct {
+	XMLName  xml.Name  `xml:"DAV: propfind"`
+	AllProp  *struct{} `xml:"DAV: allprop"`
+	PropName *struct{} `xml:"DAV: propname"`
+	Prop     *names    `xml:"DAV: prop"`
+}
+
+// ParsePropfind reads the body of a PROPFIND. An empty body asks for all the
+// properties; so does propname, whose answer then has values too.
+func ParsePropfind(r io.Reader) (*Props, error) {
+	var request propfind
+	err := xml.NewDecoder(r).Decode(&request)
+	switch {
+	case errors.Is(err, io.EOF):
+		return &Props{All: true}, nil
+	case nil != err:
+		return nil, err
+	case nil != request.Prop:
+		return &Props{Names: *request.Prop}, nil
+	case nil != request.AllProp || nil != request.PropName:
+		return &Props{All: true}, nil
+	}
+	return nil, errors.New("propfind asks for nothing")
+}
+
+// ReportKind tells the reports apart.
+type ReportKind string
+
+const (
+	ReportCalendarQuery    ReportKind = "calendar-query"
+	ReportCalendarMultiget ReportKind = "calendar-multiget"
+)
+
+// Report is a calendar-query or a calendar-multiget REPORT. A query gives the
+// names of the components it filters on, outermost first, e.g. VCALENDAR then
+// VTODO; other filters are not read. A multiget gives the hrefs of the
+// resources it asks for.
+type Report struct {
+	Kind       ReportKind
+	Props      Props
+	Components []string
+	Hrefs      []string
+}
+
+type compFilter struct {
+	Name   string      `xml:"name,attr"`
+	Filter *compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+type report struct {
+	XMLName xml.Name
+	AllProp *struct{} `xml:"DAV: allprop"`
+	Prop    *names    `xml:"DAV: prop"`
+	Hrefs   []string  `xml:"DAV: href"`
+	Filter  *struct {
+		Comp *compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// ParseReport reads the body of a REPORT.
+func ParseReport(r io.Reader) (*Report, error) {
+	var request report
+	err := xml.NewDecoder(r).Decode(&request)
+	if nil != err {
+		return nil, err
+	}
+	if NamespaceCalDAV != request.XMLName.Space {
+		return nil, fmt.Errorf("unsupported report %s", request.XMLName.Local)
+	}
+	var result = &Report{Kind: ReportKind(request.XMLName.Local)}
+	switch result.Kind {
+	default:
+		return nil, fmt.Errorf("unsupported report %s", request.XMLName.Local)
+	case ReportCalendarQuery:
+		if nil != request.Filter {
+			for comp := request.Filter.Comp; nil != comp; comp = comp.Filter {
+				result.Components = append(result.Components, strings.ToUpper(comp.Name))
+			}
+		}
+	case ReportCalendarMultiget:
+		for _, href := range request.Hrefs {
+			result.Hrefs = append(result.Hrefs, strings.TrimSpace(href))
+		}
+	}
+	switch {
+	case nil != request.Prop:
+		result.Props.Names = *request.Prop
+	default:
+		result.Props.All = true
+	}
+	return result, nil
+}
+
+// Property is a property of a resource: a name with either a text value or
+// other elements within.
+type Property struct {
+	Name     xml.Name
+	Attrs    []xml.Attr
+	Value    string
+	Children []Property
+}
+
+// TextProperty makes a property with a text value.
+func TextProperty(name xml.Name, value string) Property {
+	return Property{Name: name, Value: value}
+}
+
+// HrefProperty makes a property that refers to another resource.
+func HrefProperty(name xml.Name, href string) Property {
+	return Property{Name: name, Children: []Property{{Name: Href, Value: href}}}
+}
+
+// ElementProperty makes a property made of empty elements, as resourcetype is.
+func ElementProperty(name xml.Name, elements ...xml.Name) Property {
+	var property = Property{Name: name}
+	for _, element := range elements {
+		property.Children = append(property.Children, Property{Name: element})
+	}
+	return property
+}
+
+// Response is the part of a multistatus about one resource. A response with a
+// Status is about a resource that could not be reached, such as a missing one,
+// and has no properties.
+type Response struct {
+	Href     string
+	Status   int
+	Found    []Property
+	NotFound []xml.Name
+}
+
+// NewResponse answers a request for props of a resource that has the given
+// properties.
+func NewResponse(href string, props *Props, properties ...Property) *Response {
+	var response = &Response{Href: href, Found: make([]Property, 0)}
+	for _, property := range properties {
+		if props.Wants(property.Name) {
+			response.Found = append(response.Found, property)
+		}
+	}
+	for _, name := range props.Names {
+		var found = false
+		for _, property := range properties {
+			found = found || property.Name == name
+		}
+		if !found {
+			response.NotFound = append(response.NotFound, name)
+		}
+	}
+	return response
+}
+
+// Multistatus is the answer to a PROPFIND or a REPORT.
+type Multistatus struct {
+	Responses []*Response
+}
+
+// Encode writes the multistatus, with the namespaces in use declared once at
+// the top.
+func (m *Multistatus) Encode(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, response := range m.Responses {
+		b.WriteString("<d:response>")
+		writeProperty(&b, Property{Name: Href, Value: response.Href})
+		if 0 != response.Status {
+			writeStatus(&b, response.Status)
+		}
+		if 0 < len(response.Found) || 0 == response.Status && 0 == len(response.NotFound) {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, property := range response.Found {
+				writeProperty(&b, property)
+			}
+			b.WriteString("</d:prop>")
+			writeStatus(&b, http.StatusOK)
+			b.WriteString("</d:propstat>")
+		}
+		if 0 < len(response.NotFound) {
+			b.WriteString("<d:propstat><d:prop>")
+			for _, name := range response.NotFound {
+				writeProperty(&b, Property{Name: name})
+			}
+			b.WriteString("</d:prop>")
+			writeStatus(&b, http.StatusNotFound)
+			b.WriteString("</d:propstat>")
+		}
+		b.WriteString("</d:response>")
+	}
+	b.WriteString("</d:multistatus>")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeStatus(b *strings.Builder, status int) {
+	fmt.Fprintf(b, "<d:status>HTTP/1.1 %d %s</d:status>", status, http.StatusText(status))
+}
+
+func writeProperty(b *strings.Builder, property Property) {
+	var name, declaration = property.Name.Local, ""
+	if prefix, ok := prefixes[property.Name.Space]; ok {
+		name = prefix + ":" + name
+	} else if "" != property.Name.Space {
+		name = "x:" + name
+		declaration = ` xmlns:x="` + escape(property.Name.Space) + `"`
+	}
+	b.WriteString("<" + name + declaration)
+	for _, attr := range property.Attrs {
+		b.WriteString(" " + attr.Name.Local + `="` + escape(attr.Value) + `"`)
+	}
+	if "" == property.Value && 0 == len(property.Children) {
+		b.WriteString("/>")
+		return
+	}
+	b.WriteString(">")
+	b.WriteString(escape(property.Value))
+	for _, child := range property.Children {
+		writeProperty(b, child)
+	}
+	b.WriteString("</" + name + ">")
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
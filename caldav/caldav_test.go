@@ -0,0 +1,115 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDepth(t *testing.T) {
+	for header, expected := range map[string]Depth{
+		"0":        DepthZero,
+		" 1 ":      DepthOne,
+		"":         DepthInfinity,
+		"Infinity": DepthInfinity,
+	} {
+		got, err := ParseDepth(header)
+		assert.NoError(t, err, "header: %q", header)
+		assert.Equal(t, expected, got, "header: %q", header)
+	}
+	_, err := ParseDepth("2")
+	assert.Error(t, err)
+}
+
+func TestParsePropfind(t *testing.T) {
+	t.Run("named properties", func(t *testing.T) {
+		props, err := ParsePropfind(strings.NewReader(`<?xml version="1.0"?>
+			<propfind xmlns="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+				<prop><displayname/><C:calendar-home-set/><getctag xmlns="http://calendarserver.org/ns/"/></prop>
+			</propfind>`))
+		assert.NoError(t, err)
+		assert.Equal(t, &Props{Names: []xml.Name{DisplayName, CalendarHomeSet, GetCTag}}, props)
+	})
+
+	t.Run("empty body and allprop", func(t *testing.T) {
+		for _, body := range []string{"", `<d:propfind xmlns:d="DAV:"><d:allprop/></d:propfind>`} {
+			props, err := ParsePropfind(strings.NewReader(body))
+			assert.NoError(t, err, "body: %q", body)
+			assert.Equal(t, &Props{All: true}, props, "body: %q", body)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, body := range []string{"<d:propfind", `<d:propfind xmlns:d="DAV:"/>`, `<lock xmlns="DAV:"/>`} {
+			_, err := ParsePropfind(strings.NewReader(body))
+			assert.Error(t, err, "body: %q", body)
+		}
+	})
+}
+
+func TestParseReport(t *testing.T) {
+	t.Run("calendar-query", func(t *testing.T) {
+		report, err := ParseReport(strings.NewReader(`<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+			<d:prop><d:getetag/><c:calendar-data/></d:prop>
+			<c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="vtodo"/></c:comp-filter></c:filter>
+		</c:calendar-query>`))
+		assert.NoError(t, err)
+		assert.Equal(t, &Report{
+			Kind:       ReportCalendarQuery,
+			Props:      Props{Names: []xml.Name{GetETag, CalendarData}},
+			Components: []string{"VCALENDAR", "VTODO"},
+		}, report)
+	})
+
+	t.Run("calendar-multiget", func(t *testing.T) {
+		report, err := ParseReport(strings.NewReader(`<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+			<d:prop><d:getetag/></d:prop>
+			<d:href>/dav/calendars/a/1.ics</d:href>
+			<d:href> /dav/calendars/a/2.ics </d:href>
+		</c:calendar-multiget>`))
+		assert.NoError(t, err)
+		assert.Equal(t, &Report{
+			Kind:  ReportCalendarMultiget,
+			Props: Props{Names: []xml.Name{GetETag}},
+			Hrefs: []string{"/dav/calendars/a/1.ics", "/dav/calendars/a/2.ics"},
+		}, report)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := ParseReport(strings.NewReader(`<d:sync-collection xmlns:d="DAV:"/>`))
+		assert.Error(t, err)
+	})
+}
+
+func TestMultistatus_Encode(t *testing.T) {
+	var (
+		props = &Props{Names: []xml.Name{ResourceType, GetCTag, {Space: "urn:x", Local: "color"}}}
+		found = NewResponse("/dav/calendars/a/", props,
+			ElementProperty(ResourceType, Collection, Calendar),
+			TextProperty(DisplayName, "Groceries & co"),
+			TextProperty(GetCTag, `"42"`))
+		missing = &Response{Href: "/dav/calendars/a/gone.ics", Status: http.StatusNotFound}
+		buf     bytes.Buffer
+	)
+	assert.NoError(t, (&Multistatus{Responses: []*Response{found, missing}}).Encode(&buf))
+	assert.Equal(t, xml.Header+
+		`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`+
+		`<d:response><d:href>/dav/calendars/a/</d:href>`+
+		`<d:propstat><d:prop><d:resourcetype><d:collection/><c:calendar/></d:resourcetype><cs:getctag>&#34;42&#34;</cs:getctag></d:prop>`+
+		`<d:status>HTTP/1.1 200 OK</d:status></d:propstat>`+
+		`<d:propstat><d:prop><x:color xmlns:x="urn:x"/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>`+
+		`</d:response>`+
+		`<d:response><d:href>/dav/calendars/a/gone.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>`+
+		`</d:multistatus>`, buf.String())
+	var decoded struct {
+		Responses []struct {
+			Href string `xml:"DAV: href"`
+		} `xml:"DAV: response"`
+	}
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &decoded))
+	assert.Len(t, decoded.Responses, 2)
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* A user who is expected to work on a task.  */
+type Assignee struct {
+	TaskUUID       uuid.UUID `json:"task_uuid"`
+	UserUUID       uuid.UUID `json:"user_uuid"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	Email          string    `json:"email"`
+	AssignedByUUID uuid.UUID `json:"assigned_by_uuid"`
+	AssignedAt     time.Time `json:"assigned_at"`
+}
+
+func (a *Assignee) String() string {
+	bytes, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		log.Printf("could not convert assignee object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
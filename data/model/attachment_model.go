@@ -10,13 +10,15 @@ import (
 
 /* Abstracts a file attached to a task.  */
 type Attachment struct {
-	UUID      uuid.UUID `json:"attachment_uuid"`
-	OwnerUUID uuid.UUID `json:"owner_uuid"`
-	TaskUUID  uuid.UUID `json:"task_uuid"`
-	FileName  string    `json:"file_name"`
-	FileURL   string    `json:"file_url"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	UUID        uuid.UUID `json:"attachment_uuid"`
+	OwnerUUID   uuid.UUID `json:"owner_uuid"`
+	TaskUUID    uuid.UUID `json:"task_uuid"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	BlobKey     string    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 func (a *Attachment) String() string {
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* A change made to a user, a list, a group or a task, as kept in the append-only audit log.  */
+type AuditEntry struct {
+	UUID       uuid.UUID       `json:"audit_uuid"`
+	ActorUUID  *uuid.UUID      `json:"actor_uuid"`
+	Action     string          `json:"action"`
+	Entity     string          `json:"entity"`
+	EntityUUID *uuid.UUID      `json:"entity_uuid"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IP         string          `json:"ip"`
+	UserAgent  string          `json:"user_agent"`
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+func (a *AuditEntry) String() string {
+	bytes, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		log.Printf("could not convert audit entry object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
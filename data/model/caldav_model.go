@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+/* The name and the UID a CalDAV client gave to a task it made, so that it finds the task under them.  */
+type CalendarHref struct {
+	TaskUUID uuid.UUID `json:"task_uuid"`
+	Name     string    `json:"name"`
+	UID      string    `json:"uid"`
+}
+
+func (h *CalendarHref) String() string {
+	bytes, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		log.Printf("could not convert calendar href object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* A list served as a CalDAV calendar, with a tag that changes whenever one of its tasks does.  */
+type Calendar struct {
+	List *List
+	CTag string
+}
+
+/* A task served as a CalDAV calendar object resource, under the name it is reached by and with the UID of its VTODO.  */
+type CalendarObject struct {
+	Task *Task
+	Name string
+	UID  string
+	ETag string
+	Data []byte
+}
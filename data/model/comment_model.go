@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"noda/data/types"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* A comment on a task, optionally in reply to another comment of the same task.  */
+type Comment struct {
+	UUID       uuid.UUID  `json:"comment_uuid"`
+	TaskUUID   uuid.UUID  `json:"task_uuid"`
+	ParentUUID *uuid.UUID `json:"parent_uuid"`
+	AuthorUUID uuid.UUID  `json:"author_uuid"`
+	Content    string     `json:"content"`
+	Mentions   []string   `json:"mentions"`
+	CreatedAt  time.Time  `json:"created_at"`
+	EditedAt   *time.Time `json:"edited_at"`
+}
+
+func (c *Comment) String() string {
+	bytes, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		log.Printf("could not convert comment object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* An entry in the activity of a task: a comment or a change made to the task.  */
+type Activity struct {
+	Kind        types.ActivityKind `json:"kind"`
+	TaskUUID    uuid.UUID          `json:"task_uuid"`
+	ActorUUID   uuid.UUID          `json:"actor_uuid"`
+	CommentUUID *uuid.UUID         `json:"comment_uuid"`
+	Content     *string            `json:"content"`
+	Previous    *string            `json:"previous"`
+	Current     *string            `json:"current"`
+	OccurredAt  time.Time          `json:"occurred_at"`
+}
+
+func (a *Activity) String() string {
+	bytes, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		log.Printf("could not convert activity object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
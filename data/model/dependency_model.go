@@ -0,0 +1,36 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"noda/data/types"
+
+	"github.com/google/uuid"
+)
+
+/* A task in a dependency graph, with the tasks that must be finished before it.  */
+type DependencyNode struct {
+	TaskUUID  uuid.UUID        `json:"task_uuid"`
+	ListUUID  uuid.UUID        `json:"list_uuid"`
+	Title     string           `json:"title"`
+	Status    types.TaskStatus `json:"status"`
+	BlockedBy []uuid.UUID      `json:"blocked_by"`
+}
+
+/* The tasks linked to a task by dependencies, in an order they can be done in.  */
+type DependencyGraph struct {
+	TaskUUID uuid.UUID         `json:"task_uuid"`
+	Nodes    []*DependencyNode `json:"nodes"`
+	Order    []uuid.UUID       `json:"order"`
+	Cyclic   []uuid.UUID       `json:"cyclic"`
+	Ready    []uuid.UUID       `json:"ready"`
+}
+
+func (g *DependencyGraph) String() string {
+	bytes, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		log.Printf("could not convert dependency graph object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"noda/data/types"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* Something that happened to a task, a list or a group, as the change feed tells it. The UUIDs of what it happened to are nil when they are unknown. Audience holds who can see it besides its actor when that is known beforehand, as for what was removed.  */
+type Event struct {
+	ID         uint64          `json:"event_id"`
+	Kind       types.EventKind `json:"kind"`
+	ActorUUID  uuid.UUID       `json:"actor_uuid"`
+	GroupUUID  *uuid.UUID      `json:"group_uuid"`
+	ListUUID   *uuid.UUID      `json:"list_uuid"`
+	TaskUUID   *uuid.UUID      `json:"task_uuid"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Audience   []uuid.UUID     `json:"-"`
+}
+
+func (e *Event) String() string {
+	bytes, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		log.Printf("could not convert event object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
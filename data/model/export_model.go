@@ -0,0 +1,31 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"noda/data/types"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* An archive of all the data of a user, built in the background so that it can be downloaded.  */
+type Export struct {
+	UUID        uuid.UUID          `json:"export_uuid"`
+	OwnerUUID   uuid.UUID          `json:"owner_uuid"`
+	Status      types.ExportStatus `json:"status"`
+	Size        int64              `json:"size"`
+	BlobKey     *string            `json:"-"`
+	RequestedAt time.Time          `json:"requested_at"`
+	ReadyAt     *time.Time         `json:"ready_at"`
+	ExpiresAt   *time.Time         `json:"expires_at"`
+}
+
+func (e *Export) String() string {
+	bytes, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		log.Printf("could not convert export object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
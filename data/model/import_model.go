@@ -0,0 +1,59 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+/* Something an import made, so that importing the same data again skips it.  */
+type ImportRecord struct {
+	Kind       string    `json:"kind"`
+	Key        string    `json:"key"`
+	EntityUUID uuid.UUID `json:"entity_uuid"`
+}
+
+func (r *ImportRecord) String() string {
+	bytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		log.Printf("could not convert import record object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* What an import made, or would make in a dry run, and what it skipped because it already exists.  */
+type ImportReport struct {
+	DryRun   bool          `json:"dry_run"`
+	Created  ImportCounts  `json:"created"`
+	Skipped  ImportCounts  `json:"skipped"`
+	Items    []*ImportItem `json:"items"`
+	Problems []string      `json:"problems,omitempty"`
+}
+
+func (r *ImportReport) String() string {
+	bytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		log.Printf("could not convert import report object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* How many things of each kind an import made or skipped.  */
+type ImportCounts struct {
+	Groups int `json:"groups"`
+	Lists  int `json:"lists"`
+	Tasks  int `json:"tasks"`
+	Steps  int `json:"steps"`
+	Tags   int `json:"tags"`
+}
+
+/* A group, list, task or tag an import made or skipped. Its UUID is nil if it is yet to be made.  */
+type ImportItem struct {
+	Kind    string     `json:"kind"`
+	Name    string     `json:"name"`
+	Skipped bool       `json:"skipped"`
+	UUID    *uuid.UUID `json:"uuid"`
+}
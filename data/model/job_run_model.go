@@ -0,0 +1,28 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* One run of a background job, by the instance of the server that led the scheduler.  */
+type JobRun struct {
+	UUID       uuid.UUID  `json:"run_uuid"`
+	Job        string     `json:"job"`
+	Instance   string     `json:"instance"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Error      *string    `json:"error"`
+}
+
+func (r *JobRun) String() string {
+	bytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		log.Printf("could not convert job run object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
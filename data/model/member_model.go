@@ -0,0 +1,77 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"noda/data/types"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* What a user can do with a list, as its owner or as a member of it or its group.  */
+type ListAccess struct {
+	ListUUID  uuid.UUID        `json:"list_uuid"`
+	OwnerUUID uuid.UUID        `json:"owner_uuid"`
+	GroupUUID *uuid.UUID       `json:"group_uuid"`
+	Role      types.MemberRole `json:"role"`
+}
+
+/* What a user can do with a group, either as its owner or as a member.  */
+type GroupAccess struct {
+	GroupUUID uuid.UUID        `json:"group_uuid"`
+	OwnerUUID uuid.UUID        `json:"owner_uuid"`
+	Role      types.MemberRole `json:"role"`
+}
+
+/* A user that a list or a group is shared with.  */
+type Member struct {
+	UserUUID  uuid.UUID        `json:"user_uuid"`
+	FirstName string           `json:"first_name"`
+	LastName  string           `json:"last_name"`
+	Email     string           `json:"email"`
+	Role      types.MemberRole `json:"role"`
+	JoinedAt  time.Time        `json:"joined_at"`
+}
+
+func (m *Member) String() string {
+	bytes, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		log.Printf("could not convert member object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* An invitation to become a member of a list or a group.  */
+type Invitation struct {
+	UUID         uuid.UUID        `json:"invitation_uuid"`
+	Kind         types.ShareKind  `json:"kind"`
+	TargetUUID   uuid.UUID        `json:"target_uuid"`
+	TargetName   string           `json:"target_name"`
+	InviterUUID  uuid.UUID        `json:"inviter_uuid"`
+	InviterEmail string           `json:"inviter_email"`
+	Role         types.MemberRole `json:"role"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+func (i *Invitation) String() string {
+	bytes, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		log.Printf("could not convert invitation object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* A list of another user that is shared with the current one.  */
+type SharedList struct {
+	List
+	Role types.MemberRole `json:"role"`
+}
+
+/* A group of another user that is shared with the current one.  */
+type SharedGroup struct {
+	Group
+	Role types.MemberRole `json:"role"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* An in-app notification of the reminder of a task.  */
+type Notification struct {
+	UUID        uuid.UUID  `json:"notification_uuid"`
+	OwnerUUID   uuid.UUID  `json:"owner_uuid"`
+	TaskUUID    uuid.UUID  `json:"task_uuid"`
+	Title       string     `json:"title"`
+	Message     string     `json:"message"`
+	RemindAt    time.Time  `json:"remind_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DismissedAt *time.Time `json:"dismissed_at"`
+}
+
+func (n *Notification) String() string {
+	bytes, err := json.MarshalIndent(n, "", "  ")
+	if err != nil {
+		log.Printf("could not convert notification object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
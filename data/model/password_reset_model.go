@@ -0,0 +1,28 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* A request to reset the password of a user.  Only the hash of the token is stored.  */
+type PasswordReset struct {
+	UUID      uuid.UUID  `json:"reset_uuid"`
+	UserUUID  uuid.UUID  `json:"user_uuid"`
+	Hash      string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (p *PasswordReset) String() string {
+	bytes, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		log.Printf("could not convert password reset object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* A reminder of a task that is due and not fully delivered yet.  */
+type DueReminder struct {
+	TaskUUID          uuid.UUID  `json:"task_uuid"`
+	OwnerUUID         uuid.UUID  `json:"owner_uuid"`
+	Title             string     `json:"title"`
+	Headline          string     `json:"headline"`
+	DueDate           *time.Time `json:"due_date"`
+	RemindAt          time.Time  `json:"remind_at"`
+	Email             string     `json:"email"`
+	WebhookURL        string     `json:"webhook_url"`
+	DeliveredChannels []string   `json:"delivered_channels"`
+	FailedAttempts    int        `json:"failed_attempts"`
+}
+
+func (r *DueReminder) String() string {
+	bytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		log.Printf("could not convert due reminder object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* One attempt to deliver the reminder of a task through a channel.  */
+type ReminderDelivery struct {
+	UUID        uuid.UUID `json:"delivery_uuid"`
+	TaskUUID    uuid.UUID `json:"task_uuid"`
+	Channel     string    `json:"channel"`
+	RemindAt    time.Time `json:"remind_at"`
+	Delivered   bool      `json:"delivered"`
+	Error       *string   `json:"error"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+func (d *ReminderDelivery) String() string {
+	bytes, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		log.Printf("could not convert reminder delivery object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
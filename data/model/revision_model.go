@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"noda/data/types"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* The state of a task right before one of its changes, and what the change did.  */
+type Revision struct {
+	UUID      uuid.UUID            `json:"revision_uuid"`
+	TaskUUID  uuid.UUID            `json:"task_uuid"`
+	OwnerUUID uuid.UUID            `json:"owner_uuid"`
+	ListUUID  uuid.UUID            `json:"list_uuid"`
+	ActorUUID uuid.UUID            `json:"actor_uuid"`
+	Action    types.RevisionAction `json:"action"`
+	Before    json.RawMessage      `json:"-"`
+	After     json.RawMessage      `json:"-"`
+	Changes   []*RevisionChange    `json:"changes"`
+	IsUndone  bool                 `json:"is_undone"`
+	RevisedAt time.Time            `json:"revised_at"`
+}
+
+func (r *Revision) String() string {
+	bytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		log.Printf("could not convert revision object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* A field of a task changed by a revision, with its values before and after.  */
+type RevisionChange struct {
+	Field    string          `json:"field"`
+	Previous json.RawMessage `json:"previous"`
+	Current  json.RawMessage `json:"current"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* A user whose today and tomorrow lists roll over at its local midnight.  LastRolloverOn is the local date of the last rollover, or the date the user signed up on if there was none.  */
+type RolloverCandidate struct {
+	OwnerUUID      uuid.UUID `json:"user_uuid"`
+	Timezone       string    `json:"timezone"`
+	LastRolloverOn time.Time `json:"last_rollover_on"`
+}
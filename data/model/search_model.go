@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"noda/data/types"
+
+	"github.com/google/uuid"
+)
+
+/* Something that matched a search, with the text around where it matched.  */
+type SearchHit struct {
+	Entity     types.SearchEntity `json:"entity"`
+	EntityUUID uuid.UUID          `json:"entity_uuid"`
+	ParentUUID *uuid.UUID         `json:"parent_uuid"`
+	Title      string             `json:"title"`
+	Snippet    string             `json:"snippet"`
+	Rank       float64            `json:"rank"`
+}
+
+func (h *SearchHit) String() string {
+	bytes, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		log.Printf("could not convert search hit object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* A page of search hits, with how many hits were found of each kind.  */
+type SearchResult struct {
+	Page      int64                        `json:"page"`
+	RPP       int64                        `json:"rpp"`
+	Retrieved int64                        `json:"retrieved"`
+	Facets    map[types.SearchEntity]int64 `json:"facets"`
+	Payload   []*SearchHit                 `json:"payload"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* A named filter whose tasks are found when it is opened, like a list that fills itself.  */
+type SmartList struct {
+	UUID       uuid.UUID `json:"smart_list_uuid"`
+	OwnerUUID  uuid.UUID `json:"owner_uuid"`
+	Name       string    `json:"name"`
+	Expression string    `json:"expression"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (l *SmartList) String() string {
+	bytes, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		log.Printf("could not convert smart list object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
+
+/* A task along with the name of its list and of its tags, for filters to match against.  */
+type FilterableTask struct {
+	Task     *Task
+	ListName string
+	TagNames []string
+}
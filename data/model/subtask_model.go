@@ -0,0 +1,22 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+)
+
+/* A task with the subtasks below it and how much of it is done, from 0 to 1.  */
+type TaskTree struct {
+	Task     *Task       `json:"task"`
+	Progress float64     `json:"progress"`
+	Subtasks []*TaskTree `json:"subtasks"`
+}
+
+func (t *TaskTree) String() string {
+	bytes, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		log.Printf("could not convert task tree object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/* A refresh token issued for a session, i.e., one sign-in of a user.  Only the hash of the token is stored.  */
+type RefreshToken struct {
+	UUID        uuid.UUID  `json:"token_uuid"`
+	UserUUID    uuid.UUID  `json:"user_uuid"`
+	SessionUUID uuid.UUID  `json:"session_uuid"`
+	Hash        string     `json:"-"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (t *RefreshToken) String() string {
+	bytes, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		log.Printf("could not convert refresh token object into string: %s", err)
+		return ""
+	}
+	return string(bytes)
+}
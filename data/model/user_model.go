@@ -32,3 +32,24 @@ func (u *User) String() string {
 	}
 	return string(bytes)
 }
+
+/* The status of a user account that decides whether it can access the system.  */
+type UserStatus struct {
+	Role         types.Role `json:"role_id"`
+	IsBlocked    bool       `json:"is_blocked"`
+	BlockReason  *string    `json:"block_reason"`
+	BlockedUntil *time.Time `json:"blocked_until"`
+	DeletedAt    *time.Time `json:"deleted_at"`
+	PurgeAt      *time.Time `json:"purge_at"`
+}
+
+// BlockedAt reports whether the user is blocked at the given time. A block
+// without an expiry lasts until the user is unblocked.
+func (s *UserStatus) BlockedAt(t time.Time) bool {
+	return s.IsBlocked && (nil == s.BlockedUntil || t.Before(*s.BlockedUntil))
+}
+
+// Deleted reports whether the account was deleted and waits to be purged.
+func (s *UserStatus) Deleted() bool {
+	return nil != s.DeletedAt
+}
@@ -1,9 +1,11 @@
 package transfer
 
-/* Transfers an attachment creation request.  */
+/* Transfers an attachment creation request once its content has been stored.  */
 type AttachmentCreation struct {
-	FileName string `json:"file_name" validate:"required"`
-	FileURL  string `json:"file_url" validate:"required"`
+	FileName    string `json:"file_name" validate:"required"`
+	ContentType string `json:"content_type" validate:"required"`
+	Size        int64  `json:"size" validate:"min=0"`
+	BlobKey     string `json:"-" validate:"required"`
 }
 
 func (a *AttachmentCreation) Validate() error {
@@ -0,0 +1,22 @@
+package transfer
+
+import "github.com/google/uuid"
+
+/* Transfers a comment creation request.  */
+type CommentCreation struct {
+	Content    string     `json:"content" validate:"required"`
+	ParentUUID *uuid.UUID `json:"parent_uuid"`
+}
+
+func (c *CommentCreation) Validate() error {
+	return validate(c)
+}
+
+/* Transfers a comment update request.  */
+type CommentUpdate struct {
+	Content string `json:"content" validate:"required"`
+}
+
+func (c *CommentUpdate) Validate() error {
+	return validate(c)
+}
@@ -0,0 +1,22 @@
+package transfer
+
+import "noda/data/types"
+
+/* Transfers an invitation to share a list or a group with a user.  */
+type Invitation struct {
+	Email string           `json:"email" validate:"required,email"`
+	Role  types.MemberRole `json:"role" validate:"required,oneof=viewer editor admin"`
+}
+
+func (i *Invitation) Validate() error {
+	return validate(i)
+}
+
+/* Transfers a change of the role of a member.  */
+type MemberRoleUpdate struct {
+	Role types.MemberRole `json:"role" validate:"required,oneof=viewer editor admin"`
+}
+
+func (m *MemberRoleUpdate) Validate() error {
+	return validate(m)
+}
@@ -0,0 +1,12 @@
+package transfer
+
+import "time"
+
+/* Transfers until when to snooze a notification.  */
+type NotificationSnooze struct {
+	Until time.Time `json:"until" validate:"required"`
+}
+
+func (n *NotificationSnooze) Validate() error {
+	return validate(n)
+}
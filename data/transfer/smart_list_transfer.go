@@ -0,0 +1,17 @@
+package transfer
+
+/* Transfers a smart list creation request.  */
+type SmartListCreation struct {
+	Name       string `json:"name" validate:"required"`
+	Expression string `json:"expression" validate:"required"`
+}
+
+func (l *SmartListCreation) Validate() error {
+	return validate(l)
+}
+
+/* Transfers a smart list update request.  */
+type SmartListUpdate struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
@@ -13,3 +13,12 @@ func (s *StepCreation) Validate() error {
 type StepUpdate struct {
 	Description string `json:"description"`
 }
+
+/* Transfers a step reordering request.  */
+type StepReorder struct {
+	Position *uint64 `json:"position" validate:"required"`
+}
+
+func (s *StepReorder) Validate() error {
+	return validate(s)
+}
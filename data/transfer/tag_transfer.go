@@ -4,7 +4,7 @@ package transfer
 type TagCreation struct {
 	Name        string `json:"name" validate:"required"`
 	Description string `json:"description" validate:"required"`
-	Color       string `json:"color" validate:"required"`
+	Color       string `json:"color" validate:"required,hexcolor"`
 }
 
 func (t *TagCreation) Validate() error {
@@ -15,5 +15,9 @@ func (t *TagCreation) Validate() error {
 type TagUpdate struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
-	Color       string `json:"color"`
+	Color       string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+func (t *TagUpdate) Validate() error {
+	return validate(t)
 }
@@ -26,3 +26,48 @@ type TaskUpdate struct {
 	Headline    string `json:"headline"`
 	Description string `json:"description"`
 }
+
+/* Transfers a task reordering request.  */
+type TaskReorder struct {
+	Position *uint64 `json:"position" validate:"required"`
+}
+
+func (t *TaskReorder) Validate() error {
+	return validate(t)
+}
+
+/* Transfers a task priority update request.  */
+type TaskPriorityUpdate struct {
+	Priority types.TaskPriority `json:"priority" validate:"required,oneof=urgent high medium normal low"`
+}
+
+func (t *TaskPriorityUpdate) Validate() error {
+	return validate(t)
+}
+
+/* Transfers a task due date update request.  */
+type TaskDueDateUpdate struct {
+	DueDate time.Time `json:"due_date" validate:"required"`
+}
+
+func (t *TaskDueDateUpdate) Validate() error {
+	return validate(t)
+}
+
+/* Transfers a task reminder update request.  */
+type TaskReminderUpdate struct {
+	RemindAt time.Time `json:"remind_at" validate:"required"`
+}
+
+func (t *TaskReminderUpdate) Validate() error {
+	return validate(t)
+}
+
+/* Transfers a task recurrence update request.  */
+type TaskRecurrenceUpdate struct {
+	Rule string `json:"rule" validate:"required"`
+}
+
+func (t *TaskRecurrenceUpdate) Validate() error {
+	return validate(t)
+}
@@ -47,6 +47,23 @@ type User struct {
 	UpdatedAt  time.Time  `json:"updated_at"`
 }
 
+/* Transfers a deleted user that can be restored until it is purged.  */
+type DeletedUser struct {
+	User
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+/* Transfers why and until when a user is blocked.  */
+type UserBlock struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until"`
+}
+
+func (u *UserBlock) Validate() error {
+	return validate(u)
+}
+
 /* Transfers the credentials for a user to sign in.  */
 type UserCredentials struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -56,3 +73,41 @@ type UserCredentials struct {
 func (u *UserCredentials) Validate() error {
 	return validate(u)
 }
+
+/* Transfers a password change request for the logged in user.  */
+type PasswordChange struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+func (p *PasswordChange) Validate() error {
+	return validate(p)
+}
+
+/* Transfers a request to reset a forgotten password.  */
+type PasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+func (p *PasswordResetRequest) Validate() error {
+	return validate(p)
+}
+
+/* Transfers a new password along with the token that allows to set it.  */
+type PasswordReset struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+func (p *PasswordReset) Validate() error {
+	return validate(p)
+}
+
+/* Transfers a refresh token to exchange for a new pair of tokens.  */
+type TokenRefresh struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func (t *TokenRefresh) Validate() error {
+	return validate(t)
+}
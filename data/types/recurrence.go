@@ -0,0 +1,401 @@
+package types
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is how often a recurrence rule repeats.
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+// RecurrenceWeekday is a BYDAY entry: a weekday with an optional position
+// within the month (e.g. 2 for the second Monday, -1 for the last Friday).
+type RecurrenceWeekday struct {
+	Position int          // Position is 0 for every such weekday in the period.
+	Day      time.Weekday // Day is the weekday.
+}
+
+// RecurrenceRule is the subset of RFC 5545 RRULE supported for tasks: DAILY,
+// WEEKLY and MONTHLY frequencies with INTERVAL, BYDAY, BYMONTHDAY and either
+// an UNTIL or a COUNT limit. Weeks start on Monday.
+//
+// The first occurrence of the series is the start passed to Next and
+// Occurrences, usually the due date of the task, and it counts towards COUNT.
+type RecurrenceRule struct {
+	Frequency  Frequency           // Frequency is required.
+	Interval   int                 // Interval is 1 if not set.
+	ByDay      []RecurrenceWeekday // ByDay limits the weekdays of the occurrences.
+	ByMonthDay []int               // ByMonthDay limits the days of the month; negatives count from the end.
+	Until      *time.Time          // Until is the last instant an occurrence may fall on.
+	Count      int                 // Count is the number of occurrences; 0 means no limit.
+}
+
+// maxIdlePeriods bounds how many consecutive periods without occurrences the
+// expansion goes through before giving up, as in "FREQ=MONTHLY;INTERVAL=12;
+// BYMONTHDAY=30" starting in February.
+const maxIdlePeriods = 1000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRecurrenceRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,FR".
+// The "RRULE:" prefix is optional.
+func ParseRecurrenceRule(s string) (*RecurrenceRule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	if "" == s {
+		return nil, errors.New("the rule is empty")
+	}
+	var (
+		rule = new(RecurrenceRule)
+		seen = make(map[string]bool)
+	)
+	for _, part := range strings.Split(s, ";") {
+		key, value, found := strings.Cut(part, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !found || "" == value {
+			return nil, fmt.Errorf("%q is not a KEY=VALUE pair", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%s is given more than once", key)
+		}
+		seen[key] = true
+		var err error
+		switch key {
+		default:
+			return nil, fmt.Errorf("%s is not supported", key)
+		case "FREQ":
+			rule.Frequency = Frequency(value)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+			if nil != err || rule.Interval < 1 {
+				return nil, errors.New("INTERVAL must be a positive number")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+			if nil != err || rule.Count < 1 {
+				return nil, errors.New("COUNT must be a positive number")
+			}
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if nil != err {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, err := parseWeekday(day)
+				if nil != err {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(day)
+				if nil != err || 0 == n || n < -31 || 31 < n {
+					return nil, fmt.Errorf("%q is not a valid BYMONTHDAY", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		}
+	}
+	if 0 == rule.Interval {
+		rule.Interval = 1
+	}
+	return rule, rule.Validate()
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if until, err := time.Parse(layout, value); nil == err {
+			if "20060102" == layout {
+				/* A date-only UNTIL includes the whole day.  */
+				until = until.Add(24*time.Hour - time.Nanosecond)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("UNTIL %q must be a UTC date-time like 20240131T235959Z or a date like 20240131", value)
+}
+
+func parseWeekday(s string) (RecurrenceWeekday, error) {
+	var weekday RecurrenceWeekday
+	if len(s) < 2 {
+		return weekday, fmt.Errorf("%q is not a valid BYDAY", s)
+	}
+	day, ok := weekdays[s[len(s)-2:]]
+	if !ok {
+		return weekday, fmt.Errorf("%q is not a valid BYDAY", s)
+	}
+	weekday.Day = day
+	if position := s[:len(s)-2]; "" != position {
+		n, err := strconv.Atoi(position)
+		if nil != err || 0 == n || n < -5 || 5 < n {
+			return weekday, fmt.Errorf("%q is not a valid BYDAY", s)
+		}
+		weekday.Position = n
+	}
+	return weekday, nil
+}
+
+// Validate reports whether the combination of parts in the rule is supported.
+func (r *RecurrenceRule) Validate() error {
+	switch r.Frequency {
+	default:
+		return fmt.Errorf("FREQ must be one of DAILY, WEEKLY or MONTHLY, got %q", r.Frequency)
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+	}
+	switch {
+	case r.Interval < 1:
+		return errors.New("INTERVAL must be a positive number")
+	case r.Count < 0:
+		return errors.New("COUNT must be a positive number")
+	case 0 < r.Count && nil != r.Until:
+		return errors.New("UNTIL and COUNT cannot be used together")
+	case 0 < len(r.ByMonthDay) && FrequencyMonthly != r.Frequency:
+		return errors.New("BYMONTHDAY can only be used with FREQ=MONTHLY")
+	}
+	if FrequencyMonthly != r.Frequency {
+		for _, weekday := range r.ByDay {
+			if 0 != weekday.Position {
+				return errors.New("BYDAY positions can only be used with FREQ=MONTHLY")
+			}
+		}
+	}
+	return nil
+}
+
+// String formats the rule as an RRULE value, without the "RRULE:" prefix.
+func (r *RecurrenceRule) String() string {
+	var parts = []string{"FREQ=" + string(r.Frequency)}
+	if 1 < r.Interval {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if 0 < len(r.ByDay) {
+		var days = make([]string, len(r.ByDay))
+		for i, weekday := range r.ByDay {
+			days[i] = weekdayNames[weekday.Day]
+			if 0 != weekday.Position {
+				days[i] = strconv.Itoa(weekday.Position) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if 0 < len(r.ByMonthDay) {
+		var days = make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if nil != r.Until {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if 0 < r.Count {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence of the series that starts at start which
+// falls strictly after after. If the series ends before that, ok is false.
+func (r *RecurrenceRule) Next(start, after time.Time) (next time.Time, ok bool) {
+	r.expand(start, func(occurrence time.Time) bool {
+		if occurrence.After(after) {
+			next, ok = occurrence, true
+			return false
+		}
+		return true
+	})
+	return next, ok
+}
+
+// Occurrences returns up to n occurrences of the series that starts at start,
+// start included.
+func (r *RecurrenceRule) Occurrences(start time.Time, n int) []time.Time {
+	var occurrences = make([]time.Time, 0, n)
+	if n <= 0 {
+		return occurrences
+	}
+	r.expand(start, func(occurrence time.Time) bool {
+		occurrences = append(occurrences, occurrence)
+		return len(occurrences) < n
+	})
+	return occurrences
+}
+
+// expand calls yield with each occurrence of the series, in order, until the
+// series ends or yield returns false. Occurrences keep the clock time and
+// location of start.
+func (r *RecurrenceRule) expand(start time.Time, yield func(time.Time) bool) {
+	var (
+		interval = max(r.Interval, 1)
+		emitted  = 0
+		idle     = 0
+	)
+	for period := 0; idle < maxIdlePeriods; period += interval {
+		var candidates = r.candidates(start, period)
+		if 0 == len(candidates) {
+			idle++
+			continue
+		}
+		idle = 0
+		for _, occurrence := range candidates {
+			if occurrence.Before(start) {
+				continue
+			}
+			if nil != r.Until && occurrence.After(*r.Until) {
+				return
+			}
+			if !yield(occurrence) {
+				return
+			}
+			emitted++
+			if 0 < r.Count && emitted >= r.Count {
+				return
+			}
+		}
+	}
+}
+
+// candidates returns the sorted occurrences within the period-th day, week or
+// month after the one that contains start, disregarding the limits.
+func (r *RecurrenceRule) candidates(start time.Time, period int) []time.Time {
+	var at = func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	}
+	switch r.Frequency {
+	case FrequencyDaily:
+		var day = at(start.Year(), start.Month(), start.Day()+period)
+		if 0 < len(r.ByDay) && !r.hasWeekday(day.Weekday()) {
+			return nil
+		}
+		return []time.Time{day}
+	case FrequencyWeekly:
+		var (
+			offset = (int(start.Weekday()) + 6) % 7
+			monday = at(start.Year(), start.Month(), start.Day()-offset+7*period)
+			days   []time.Time
+		)
+		for i := 0; i < 7; i++ {
+			var day = monday.AddDate(0, 0, i)
+			if 0 == len(r.ByDay) && day.Weekday() == start.Weekday() || r.hasWeekday(day.Weekday()) {
+				days = append(days, day)
+			}
+		}
+		return days
+	case FrequencyMonthly:
+		var (
+			first    = at(start.Year(), start.Month()+time.Month(period), 1)
+			length   = first.AddDate(0, 1, -1).Day()
+			byDay    = make(map[int]bool)
+			monthDay = make(map[int]bool)
+			days     []time.Time
+		)
+		for _, weekday := range r.ByDay {
+			for day := 1; day <= length; day++ {
+				if weekday.Day != first.AddDate(0, 0, day-1).Weekday() {
+					continue
+				}
+				var (
+					nth     = (day-1)/7 + 1
+					nthLast = -((length-day)/7 + 1)
+				)
+				if 0 == weekday.Position || nth == weekday.Position || nthLast == weekday.Position {
+					byDay[day] = true
+				}
+			}
+		}
+		var byMonthDay = r.ByMonthDay
+		if 0 == len(byMonthDay) && 0 == len(r.ByDay) {
+			byMonthDay = []int{start.Day()}
+		}
+		for _, day := range byMonthDay {
+			if day < 0 {
+				day = length + day + 1
+			}
+			/* Days that do not exist in the month are skipped, as RFC 5545
+			   requires, e.g. the 31st in April.  */
+			if 1 <= day && day <= length {
+				monthDay[day] = true
+			}
+		}
+		for day := 1; day <= length; day++ {
+			var matches bool
+			switch {
+			case 0 < len(r.ByDay) && 0 < len(byMonthDay):
+				matches = byDay[day] && monthDay[day]
+			case 0 < len(r.ByDay):
+				matches = byDay[day]
+			default:
+				matches = monthDay[day]
+			}
+			if matches {
+				days = append(days, first.AddDate(0, 0, day-1))
+			}
+		}
+		return days
+	}
+	return nil
+}
+
+func (r *RecurrenceRule) hasWeekday(day time.Weekday) bool {
+	return slices.ContainsFunc(r.ByDay, func(weekday RecurrenceWeekday) bool {
+		return weekday.Day == day
+	})
+}
+
+// MarshalText encodes the rule as its RRULE value.
+func (r RecurrenceRule) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes an RRULE value.
+func (r *RecurrenceRule) UnmarshalText(text []byte) error {
+	rule, err := ParseRecurrenceRule(string(text))
+	if nil != err {
+		return err
+	}
+	*r = *rule
+	return nil
+}
+
+// Value stores the rule as its RRULE value.
+func (r RecurrenceRule) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Scan reads a rule stored as its RRULE value.
+func (r *RecurrenceRule) Scan(src any) error {
+	switch src := src.(type) {
+	case string:
+		return r.UnmarshalText([]byte(src))
+	case []byte:
+		return r.UnmarshalText(src)
+	}
+	return fmt.Errorf("cannot scan %T into a recurrence rule", src)
+}
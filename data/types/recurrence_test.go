@@ -0,0 +1,225 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var until = time.Date(2024, time.March, 31, 23, 59, 59, 0, time.UTC)
+		var cases = []struct {
+			in       string
+			expected *RecurrenceRule
+			out      string
+		}{
+			{"FREQ=DAILY", &RecurrenceRule{Frequency: FrequencyDaily, Interval: 1}, "FREQ=DAILY"},
+			{"RRULE:freq=weekly;byday=mo,we", &RecurrenceRule{
+				Frequency: FrequencyWeekly,
+				Interval:  1,
+				ByDay:     []RecurrenceWeekday{{Day: time.Monday}, {Day: time.Wednesday}},
+			}, "FREQ=WEEKLY;BYDAY=MO,WE"},
+			{"FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR,2MO;COUNT=6", &RecurrenceRule{
+				Frequency: FrequencyMonthly,
+				Interval:  2,
+				ByDay:     []RecurrenceWeekday{{Position: -1, Day: time.Friday}, {Position: 2, Day: time.Monday}},
+				Count:     6,
+			}, "FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR,2MO;COUNT=6"},
+			{"FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20240331T235959Z", &RecurrenceRule{
+				Frequency:  FrequencyMonthly,
+				Interval:   1,
+				ByMonthDay: []int{1, -1},
+				Until:      &until,
+			}, "FREQ=MONTHLY;BYMONTHDAY=1,-1;UNTIL=20240331T235959Z"},
+		}
+		for _, c := range cases {
+			rule, err := ParseRecurrenceRule(c.in)
+			require.NoError(t, err, c.in)
+			assert.Equal(t, c.expected, rule, c.in)
+			assert.Equal(t, c.out, rule.String(), c.in)
+		}
+	})
+
+	t.Run("date-only UNTIL includes the whole day", func(t *testing.T) {
+		rule, err := ParseRecurrenceRule("FREQ=DAILY;UNTIL=20240131")
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, time.January, 31, 23, 59, 59, 999999999, time.UTC), *rule.Until)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		for _, in := range []string{
+			"",
+			"RRULE:",
+			"FREQ=YEARLY",
+			"FREQ=HOURLY",
+			"INTERVAL=2",
+			"FREQ=DAILY;FREQ=WEEKLY",
+			"FREQ=DAILY;INTERVAL=0",
+			"FREQ=DAILY;COUNT=-1",
+			"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+			"FREQ=DAILY;UNTIL=tomorrow",
+			"FREQ=WEEKLY;BYDAY=XX",
+			"FREQ=WEEKLY;BYDAY=1MO",
+			"FREQ=WEEKLY;BYMONTHDAY=1",
+			"FREQ=MONTHLY;BYDAY=6MO",
+			"FREQ=MONTHLY;BYMONTHDAY=32",
+			"FREQ=MONTHLY;BYMONTHDAY=0",
+			"FREQ=DAILY;WKST=SU",
+			"FREQ=DAILY;COUNT",
+		} {
+			_, err := ParseRecurrenceRule(in)
+			assert.Error(t, err, in)
+		}
+	})
+}
+
+func TestRecurrenceRule_Occurrences(t *testing.T) {
+	var cases = []struct {
+		name     string
+		rule     string
+		start    time.Time
+		n        int
+		expected []time.Time
+	}{
+		{
+			name:     "every other day",
+			rule:     "FREQ=DAILY;INTERVAL=2",
+			start:    date(2024, time.January, 30),
+			n:        3,
+			expected: []time.Time{date(2024, time.January, 30), date(2024, time.February, 1), date(2024, time.February, 3)},
+		},
+		{
+			name:     "on weekdays",
+			rule:     "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			start:    date(2024, time.January, 5), // Friday
+			n:        3,
+			expected: []time.Time{date(2024, time.January, 5), date(2024, time.January, 8), date(2024, time.January, 9)},
+		},
+		{
+			name:     "weekly on the day of the start",
+			rule:     "FREQ=WEEKLY",
+			start:    date(2024, time.January, 3),
+			n:        2,
+			expected: []time.Time{date(2024, time.January, 3), date(2024, time.January, 10)},
+		},
+		{
+			name:  "every other week on Monday and Thursday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TH,MO",
+			start: date(2024, time.January, 4), // Thursday
+			n:     4,
+			expected: []time.Time{
+				date(2024, time.January, 4),
+				date(2024, time.January, 15),
+				date(2024, time.January, 18),
+				date(2024, time.January, 29),
+			},
+		},
+		{
+			name:     "monthly on the day of the start skips short months",
+			rule:     "FREQ=MONTHLY",
+			start:    date(2024, time.January, 31),
+			n:        3,
+			expected: []time.Time{date(2024, time.January, 31), date(2024, time.March, 31), date(2024, time.May, 31)},
+		},
+		{
+			name:     "monthly on the last day",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start:    date(2024, time.January, 31),
+			n:        3,
+			expected: []time.Time{date(2024, time.January, 31), date(2024, time.February, 29), date(2024, time.March, 31)},
+		},
+		{
+			name:     "monthly on the second Tuesday",
+			rule:     "FREQ=MONTHLY;BYDAY=2TU",
+			start:    date(2024, time.January, 1),
+			n:        3,
+			expected: []time.Time{date(2024, time.January, 9), date(2024, time.February, 13), date(2024, time.March, 12)},
+		},
+		{
+			name:     "monthly on the last Friday",
+			rule:     "FREQ=MONTHLY;BYDAY=-1FR",
+			start:    date(2024, time.January, 1),
+			n:        2,
+			expected: []time.Time{date(2024, time.January, 26), date(2024, time.February, 23)},
+		},
+		{
+			name:     "monthly on Friday the 13th",
+			rule:     "FREQ=MONTHLY;BYDAY=FR;BYMONTHDAY=13",
+			start:    date(2024, time.January, 1),
+			n:        2,
+			expected: []time.Time{date(2024, time.September, 13), date(2024, time.December, 13)},
+		},
+		{
+			name:     "count includes the start",
+			rule:     "FREQ=DAILY;COUNT=2",
+			start:    date(2024, time.January, 1),
+			n:        10,
+			expected: []time.Time{date(2024, time.January, 1), date(2024, time.January, 2)},
+		},
+		{
+			name:     "until is inclusive",
+			rule:     "FREQ=WEEKLY;UNTIL=20240115T093000Z",
+			start:    date(2024, time.January, 1),
+			n:        10,
+			expected: []time.Time{date(2024, time.January, 1), date(2024, time.January, 8), date(2024, time.January, 15)},
+		},
+		{
+			name:     "never matches",
+			rule:     "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30",
+			start:    date(2024, time.February, 1),
+			n:        1,
+			expected: []time.Time{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(c.rule)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, rule.Occurrences(c.start, c.n))
+		})
+	}
+}
+
+func TestRecurrenceRule_Next(t *testing.T) {
+	t.Run("keeps the phase of the interval", func(t *testing.T) {
+		rule, err := ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE")
+		require.NoError(t, err)
+		var wednesday = date(2024, time.January, 3)
+		next, ok := rule.Next(wednesday, wednesday)
+		assert.True(t, ok)
+		assert.Equal(t, date(2024, time.January, 15), next)
+	})
+
+	t.Run("the series ended", func(t *testing.T) {
+		rule, err := ParseRecurrenceRule("FREQ=DAILY;COUNT=1")
+		require.NoError(t, err)
+		var start = date(2024, time.January, 3)
+		_, ok := rule.Next(start, start)
+		assert.False(t, ok)
+	})
+}
+
+func TestRecurrenceRule_JSON(t *testing.T) {
+	type wrapper struct {
+		Recurrence *RecurrenceRule `json:"recurrence"`
+	}
+	data, err := json.Marshal(wrapper{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"recurrence":null}`, string(data))
+	rule, err := ParseRecurrenceRule("FREQ=MONTHLY;BYDAY=-1FR")
+	require.NoError(t, err)
+	data, err = json.Marshal(wrapper{rule})
+	require.NoError(t, err)
+	assert.Equal(t, `{"recurrence":"FREQ=MONTHLY;BYDAY=-1FR"}`, string(data))
+	var decoded wrapper
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, rule, decoded.Recurrence)
+	assert.Error(t, json.Unmarshal([]byte(`{"recurrence":"FREQ=SOMETIMES"}`), &decoded))
+}
@@ -35,6 +35,130 @@ const (
 	TaskStatusDeferred   TaskStatus = "decayed"
 )
 
+// MemberRole represents what a user can do with a list or a group that is
+// shared with it. Every role can do what the roles before it can.
+type MemberRole string
+
+const (
+	// MemberRoleViewer can see the list or group and its tasks.
+	MemberRoleViewer MemberRole = "viewer"
+	// MemberRoleEditor can also add, change and remove tasks.
+	MemberRoleEditor MemberRole = "editor"
+	// MemberRoleAdmin can also change the list or group and manage its members.
+	MemberRoleAdmin MemberRole = "admin"
+	// MemberRoleOwner is the role of the user that made the list or group.
+	MemberRoleOwner MemberRole = "owner"
+)
+
+var memberRoleRanks = map[MemberRole]int{
+	MemberRoleViewer: 1,
+	MemberRoleEditor: 2,
+	MemberRoleAdmin:  3,
+	MemberRoleOwner:  4,
+}
+
+// Grants tells whether the role allows what needs the other role. An unknown
+// role grants nothing.
+func (r MemberRole) Grants(needed MemberRole) bool {
+	var rank, known = memberRoleRanks[r]
+	return known && memberRoleRanks[needed] <= rank
+}
+
+// ShareKind is the kind of thing that can be shared with other users.
+type ShareKind string
+
+const (
+	ShareKindList  ShareKind = "list"
+	ShareKindGroup ShareKind = "group"
+)
+
+// ActivityKind is the kind of an entry in the activity of a task, either a
+// comment or something that happened to the task.
+type ActivityKind string
+
+const (
+	ActivityKindComment         ActivityKind = "comment"
+	ActivityKindCreated         ActivityKind = "created"
+	ActivityKindCompleted       ActivityKind = "completed"
+	ActivityKindResumed         ActivityKind = "resumed"
+	ActivityKindMoved           ActivityKind = "moved"
+	ActivityKindPriorityChanged ActivityKind = "priority_changed"
+	ActivityKindDueDateSet      ActivityKind = "due_date_set"
+)
+
+// RevisionAction is the change to a task that a revision was taken before.
+type RevisionAction string
+
+const (
+	RevisionActionUpdated           RevisionAction = "updated"
+	RevisionActionReordered         RevisionAction = "reordered"
+	RevisionActionReminderSet       RevisionAction = "reminder_set"
+	RevisionActionPriorityChanged   RevisionAction = "priority_changed"
+	RevisionActionDueDateSet        RevisionAction = "due_date_set"
+	RevisionActionRecurrenceSet     RevisionAction = "recurrence_set"
+	RevisionActionRecurrenceRemoved RevisionAction = "recurrence_removed"
+	RevisionActionCompleted         RevisionAction = "completed"
+	RevisionActionResumed           RevisionAction = "resumed"
+	RevisionActionPinned            RevisionAction = "pinned"
+	RevisionActionUnpinned          RevisionAction = "unpinned"
+	RevisionActionMoved             RevisionAction = "moved"
+	RevisionActionTrashed           RevisionAction = "trashed"
+	RevisionActionRestoredFromTrash RevisionAction = "restored_from_trash"
+	RevisionActionRestored          RevisionAction = "restored"
+	RevisionActionUndone            RevisionAction = "undone"
+)
+
+// SearchEntity is the kind of thing a search can find.
+type SearchEntity string
+
+const (
+	SearchEntityTask    SearchEntity = "task"
+	SearchEntityStep    SearchEntity = "step"
+	SearchEntityList    SearchEntity = "list"
+	SearchEntityGroup   SearchEntity = "group"
+	SearchEntityTag     SearchEntity = "tag"
+	SearchEntityComment SearchEntity = "comment"
+)
+
+// ExportStatus is where an export of the data of a user stands.
+type ExportStatus string
+
+const (
+	// ExportStatusPending is an export whose archive is yet to be built.
+	ExportStatusPending ExportStatus = "pending"
+	// ExportStatusReady is an export whose archive can be downloaded.
+	ExportStatusReady ExportStatus = "ready"
+	// ExportStatusFailed is an export whose archive could not be built.
+	ExportStatusFailed ExportStatus = "failed"
+	// ExportStatusExpired is an export whose archive has been removed.
+	ExportStatusExpired ExportStatus = "expired"
+)
+
+// EventKind is what happened to a task, a list or a group, as the change feed
+// tells it.
+type EventKind string
+
+const (
+	EventKindTaskCreated   EventKind = "task.created"
+	EventKindTaskUpdated   EventKind = "task.updated"
+	EventKindTaskMoved     EventKind = "task.moved"
+	EventKindTaskCompleted EventKind = "task.completed"
+	EventKindTaskResumed   EventKind = "task.resumed"
+	EventKindTaskTrashed   EventKind = "task.trashed"
+	EventKindTaskRestored  EventKind = "task.restored"
+	EventKindTaskRemoved   EventKind = "task.removed"
+	EventKindListCreated   EventKind = "list.created"
+	EventKindListUpdated   EventKind = "list.updated"
+	EventKindListMoved     EventKind = "list.moved"
+	EventKindListRemoved   EventKind = "list.removed"
+	EventKindGroupCreated  EventKind = "group.created"
+	EventKindGroupUpdated  EventKind = "group.updated"
+	EventKindGroupRemoved  EventKind = "group.removed"
+	// EventKindReset tells that some events were missed, so that everything
+	// must be retrieved again.
+	EventKindReset EventKind = "reset"
+)
+
 // Position represents a position in a sequence.
 type Position uint32
 
@@ -42,8 +166,10 @@ type Position uint32
 type ContextKey struct{}
 
 type JWTPayload struct {
-	UserID   uuid.UUID // UserID is the unique identifier for a user.
-	UserRole Role      // UserRole represents the role of the user.
+	UserID    uuid.UUID // UserID is the unique identifier for a user.
+	UserRole  Role      // UserRole represents the role of the user.
+	SessionID uuid.UUID // SessionID identifies the sign-in the token was issued for.
+	ExpiresAt time.Time // ExpiresAt is when the token expires, if it does.
 }
 
 // TokenExpires represents the expiration details of a token.
@@ -61,6 +187,9 @@ type TokenPayload struct {
 	Issuer   string       `json:"issuer"`    // Issuer is the entity that issued the token.
 	IssuedAt time.Time    `json:"issued_at"` // IssuedAt is the time when the token was issued.
 	Expires  TokenExpires `json:"expires"`   // Expires represents the expiration details of the token.
+
+	RefreshToken     string    `json:"refresh_token"`      // RefreshToken is exchanged for a new token before Expires.
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"` // RefreshExpiresAt is when RefreshToken can no longer be used.
 }
 
 // Pagination represents the pagination parameters for querying a collection.
@@ -71,8 +200,35 @@ type Pagination struct {
 
 // Result represents the result of a paginated query.
 type Result[T any] struct {
-	Page      int64 `json:"page"`      // Page is the current page number.
-	RPP       int64 `json:"rpp"`       // RPP is the number of Records Per Page for the query.
-	Retrieved int64 `json:"retrieved"` // Retrieved is the total number of items retrieved.
-	Payload   []*T  `json:"payload"`   // Payload is the actual data payload.
+	Page      int64 `json:"page"`                // Page is the current page number.
+	RPP       int64 `json:"rpp"`                 // RPP is the number of Records Per Page for the query.
+	Retrieved int64 `json:"retrieved"`           // Retrieved is the total number of items retrieved.
+	Payload   []*T  `json:"payload"`             // Payload is the actual data payload.
+	Truncated bool  `json:"truncated,omitempty"` // Truncated tells that not every item could be looked through, so some may be missing.
+}
+
+// TagFilter represents a filter over the tags attached to a task.
+type TagFilter struct {
+	Tags     []uuid.UUID // Tags are the unique identifiers of the tags to filter by.
+	MatchAll bool        // MatchAll requires a task to have all the tags instead of any of them.
+}
+
+// AuditFilter narrows down the entries of the audit log. Zero values do not
+// filter.
+type AuditFilter struct {
+	ActorUUID  uuid.UUID  // ActorUUID is the user who made the changes.
+	Entity     string     // Entity is the kind of the changed things, e.g. "task".
+	EntityUUID uuid.UUID  // EntityUUID is the changed thing.
+	From       *time.Time // From is the earliest time of the changes, inclusive.
+	To         *time.Time // To is the latest time of the changes, exclusive.
+}
+
+// CandidateFilter narrows down the tasks a smart list can hold to those that can
+// match its expression. Nil values do not filter.
+type CandidateFilter struct {
+	Status  *TaskStatus // Status is the status of the tasks.
+	Pinned  *bool       // Pinned tells whether the tasks are pinned.
+	List    *string     // List is the name, regardless of case, or the UUID of the list of the tasks.
+	DueFrom *time.Time  // DueFrom is the earliest due date of the tasks, inclusive.
+	DueTo   *time.Time  // DueTo is the latest due date of the tasks, inclusive.
 }
@@ -0,0 +1,241 @@
+// Package events carries the changes made to the tasks, the lists and the
+// groups to the users who can see them, as they happen.
+//
+// A Broker numbers the events it is given, keeps the latest of them so that a
+// subscriber that comes back can catch up from the last one it got, and hands
+// each of them to the subscriptions of the users who can see it. The events
+// only live in the memory of the instance of the server they were published
+// on.
+package events
+
+import (
+	"context"
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Publisher is given the events as they happen.
+type Publisher interface {
+	Publish(event *model.Event)
+}
+
+// Audience tells which users, besides the one who acted, can see an event.
+// When it fails, only the user who acted is told of the event.
+type Audience func(event *model.Event) (userIDs []uuid.UUID, err error)
+
+// queueSize is how many events can wait to be dispatched before Publish
+// drops them.
+const queueSize = 1024
+
+type record struct {
+	event    *model.Event
+	audience map[uuid.UUID]struct{}
+}
+
+// Broker dispatches the events to the subscriptions of their audience. Its
+// methods are safe for concurrent use.
+type Broker struct {
+	audience    Audience
+	historySize int
+	bufferSize  int
+	queue       chan *model.Event
+	dropped     atomic.Bool
+	mu          sync.Mutex
+	lastID      uint64
+	history     []*record
+	subscribers map[uuid.UUID]map[*Subscription]struct{}
+}
+
+// NewBroker returns a broker that keeps the last historySize events, and
+// gives up on a subscription that has bufferSize events it did not take yet.
+//
+// The events are numbered from the time the broker is created, in
+// microseconds, so that the numbers keep growing when the server restarts.
+func NewBroker(audience Audience, historySize, bufferSize int) *Broker {
+	return &Broker{
+		audience:    audience,
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		queue:       make(chan *model.Event, queueSize),
+		lastID:      uint64(time.Now().UnixMicro()),
+		history:     make([]*record, 0, historySize),
+		subscribers: make(map[uuid.UUID]map[*Subscription]struct{}),
+	}
+}
+
+// Publish queues an event to be dispatched by Run. The event must not be
+// changed afterwards. Publish never blocks the change that made the event:
+// when the queue is full, the event is dropped, and Run then closes every
+// subscription as lagging and forgets the events it kept, so that the
+// subscribers retrieve everything again rather than miss the event.
+func (b *Broker) Publish(event *model.Event) {
+	select {
+	case b.queue <- event:
+	default:
+		log.Printf("dropped event %q: the queue is full", event.Kind)
+		b.dropped.Store(true)
+	}
+}
+
+// Run dispatches the published events until ctx is done, and then closes the
+// subscriptions.
+func (b *Broker) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			b.closeAll()
+			return
+		case event := <-b.queue:
+			if b.dropped.Swap(false) {
+				b.forget()
+			}
+			b.dispatch(event)
+		}
+	}
+}
+
+// dispatch numbers the event, keeps it and hands it to the subscriptions of
+// its audience, which is found unless the event came with it. A subscription
+// that cannot take it is closed as lagging.
+func (b *Broker) dispatch(event *model.Event) {
+	var audience = map[uuid.UUID]struct{}{event.ActorUUID: {}}
+	var userIDs = event.Audience
+	var err error
+	if nil == userIDs {
+		userIDs, err = b.audience(event)
+	}
+	if nil != err {
+		log.Printf("could not find who can see event %q: %v", event.Kind, err)
+	}
+	for _, userID := range userIDs {
+		audience[userID] = struct{}{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	event.ID = b.lastID
+	if 0 < b.historySize {
+		if len(b.history) == b.historySize {
+			b.history = b.history[1:]
+		}
+		b.history = append(b.history, &record{event: event, audience: audience})
+	}
+	for userID := range audience {
+		for s := range b.subscribers[userID] {
+			select {
+			case s.events <- event:
+			default:
+				s.lagged = true
+				b.remove(s)
+			}
+		}
+	}
+}
+
+// Subscribe starts handing the events the user can see to a subscription.
+//
+// With lastEventID, missed holds the events the user can see that came after
+// it. When the broker no longer has some of them, or never had them, missed
+// only holds a reset event numbered as the last event, so that the subscriber
+// retrieves everything again and catches up from there next time.
+func (b *Broker) Subscribe(userID uuid.UUID, lastEventID *uint64) (s *Subscription, missed []*model.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s = &Subscription{broker: b, userID: userID, events: make(chan *model.Event, b.bufferSize)}
+	if nil == b.subscribers[userID] {
+		b.subscribers[userID] = make(map[*Subscription]struct{})
+	}
+	b.subscribers[userID][s] = struct{}{}
+	missed = make([]*model.Event, 0)
+	if nil == lastEventID {
+		return s, missed
+	}
+	var oldestID = b.lastID + 1
+	if 0 < len(b.history) {
+		oldestID = b.history[0].event.ID
+	}
+	if *lastEventID > b.lastID || *lastEventID+1 < oldestID {
+		var reset = &model.Event{ID: b.lastID, Kind: types.EventKindReset, OccurredAt: time.Now().UTC()}
+		return s, append(missed, reset)
+	}
+	for _, r := range b.history {
+		if _, ok := r.audience[userID]; ok && r.event.ID > *lastEventID {
+			missed = append(missed, r.event)
+		}
+	}
+	return s, missed
+}
+
+// Unsubscribe stops a subscription and closes its channel. Stopping a stopped
+// subscription does nothing.
+func (b *Broker) Unsubscribe(s *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(s)
+}
+
+func (b *Broker) remove(s *Subscription) {
+	var subscriptions = b.subscribers[s.userID]
+	if _, ok := subscriptions[s]; !ok {
+		return
+	}
+	delete(subscriptions, s)
+	if 0 == len(subscriptions) {
+		delete(b.subscribers, s.userID)
+	}
+	close(s.events)
+}
+
+// forget makes up for dropped events: it skips a number for them, forgets the
+// events it kept, so that any subscriber that comes back is reset, and closes
+// every subscription as lagging.
+func (b *Broker) forget() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	b.history = b.history[:0]
+	for _, subscriptions := range b.subscribers {
+		for s := range subscriptions {
+			s.lagged = true
+			b.remove(s)
+		}
+	}
+}
+
+func (b *Broker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, subscriptions := range b.subscribers {
+		for s := range subscriptions {
+			b.remove(s)
+		}
+	}
+}
+
+// Subscription hands the events one user can see, in order.
+type Subscription struct {
+	broker *Broker
+	userID uuid.UUID
+	events chan *model.Event
+	lagged bool
+}
+
+// Events is closed when the subscription stops.
+func (s *Subscription) Events() <-chan *model.Event {
+	return s.events
+}
+
+// Lagged tells whether the subscription was stopped because it did not take
+// its events fast enough. Its subscriber may catch up by subscribing again
+// from the last event it got.
+func (s *Subscription) Lagged() bool {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	return s.lagged
+}
@@ -0,0 +1,244 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func beQuiet() func() {
+	log.SetOutput(io.Discard)
+	return func() { log.SetOutput(os.Stderr) }
+}
+
+var (
+	actorID  = uuid.New()
+	memberID = uuid.New()
+	listID   = uuid.New()
+)
+
+func sharedWithMember(event *model.Event) ([]uuid.UUID, error) {
+	return []uuid.UUID{memberID}, nil
+}
+
+func taskEvent(kind types.EventKind) *model.Event {
+	var taskID = uuid.New()
+	return &model.Event{Kind: kind, ActorUUID: actorID, ListUUID: &listID, TaskUUID: &taskID}
+}
+
+func receive(t *testing.T, s *Subscription) *model.Event {
+	t.Helper()
+	select {
+	case event, ok := <-s.Events():
+		require.True(t, ok, "the subscription is closed")
+		return event
+	case <-time.After(time.Second):
+		require.FailNow(t, "no event was received")
+		return nil
+	}
+}
+
+func TestBroker_Run(t *testing.T) {
+	defer beQuiet()()
+
+	t.Run("dispatches to the actor and the audience", func(t *testing.T) {
+		var b = NewBroker(sharedWithMember, 10, 10)
+		var ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		go b.Run(ctx)
+		var (
+			actor, _    = b.Subscribe(actorID, nil)
+			member, _   = b.Subscribe(memberID, nil)
+			stranger, _ = b.Subscribe(uuid.New(), nil)
+			created     = taskEvent(types.EventKindTaskCreated)
+			completed   = taskEvent(types.EventKindTaskCompleted)
+		)
+		b.Publish(created)
+		b.Publish(completed)
+		assert.Same(t, created, receive(t, actor))
+		assert.Same(t, completed, receive(t, actor))
+		assert.Same(t, created, receive(t, member))
+		assert.Same(t, completed, receive(t, member))
+		assert.Equal(t, created.ID+1, completed.ID)
+		assert.Empty(t, stranger.Events())
+	})
+
+	t.Run("audience could not be found", func(t *testing.T) {
+		var b = NewBroker(func(*model.Event) ([]uuid.UUID, error) {
+			return nil, errors.New("unexpected error")
+		}, 10, 10)
+		var ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		go b.Run(ctx)
+		var actor, _ = b.Subscribe(actorID, nil)
+		var member, _ = b.Subscribe(memberID, nil)
+		b.Publish(taskEvent(types.EventKindTaskUpdated))
+		assert.Equal(t, types.EventKindTaskUpdated, receive(t, actor).Kind)
+		assert.Empty(t, member.Events())
+	})
+
+	t.Run("audience came with the event", func(t *testing.T) {
+		var b = NewBroker(func(*model.Event) ([]uuid.UUID, error) {
+			return nil, errors.New("the list no longer exists")
+		}, 10, 10)
+		var ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		go b.Run(ctx)
+		var member, _ = b.Subscribe(memberID, nil)
+		b.Publish(&model.Event{Kind: types.EventKindListRemoved, ActorUUID: actorID, ListUUID: &listID, Audience: []uuid.UUID{memberID}})
+		assert.Equal(t, types.EventKindListRemoved, receive(t, member).Kind)
+	})
+
+	t.Run("drops the events it cannot queue", func(t *testing.T) {
+		var b = NewBroker(sharedWithMember, 10, queueSize+1)
+		var s, _ = b.Subscribe(actorID, nil)
+		var published = make(chan struct{})
+		go func() {
+			for i := 0; i <= queueSize; i++ {
+				b.Publish(taskEvent(types.EventKindTaskUpdated))
+			}
+			close(published)
+		}()
+		select {
+		case <-published:
+		case <-time.After(time.Second):
+			require.FailNow(t, "Publish blocked")
+		}
+		var lastID = b.lastID
+		var ctx, cancel = context.WithCancel(context.Background())
+		defer cancel()
+		go b.Run(ctx)
+		for range s.Events() {
+		}
+		assert.True(t, s.Lagged())
+		var resumed, missed = b.Subscribe(actorID, &lastID)
+		defer b.Unsubscribe(resumed)
+		require.Len(t, missed, 1)
+		assert.Equal(t, types.EventKindReset, missed[0].Kind)
+	})
+
+	t.Run("closes the subscriptions when done", func(t *testing.T) {
+		var b = NewBroker(sharedWithMember, 10, 10)
+		var ctx, cancel = context.WithCancel(context.Background())
+		var s, _ = b.Subscribe(actorID, nil)
+		var done = make(chan struct{})
+		go func() {
+			b.Run(ctx)
+			close(done)
+		}()
+		cancel()
+		<-done
+		_, ok := <-s.Events()
+		assert.False(t, ok)
+		assert.False(t, s.Lagged())
+	})
+}
+
+func TestBroker_dispatch(t *testing.T) {
+	defer beQuiet()()
+
+	t.Run("subscription lags", func(t *testing.T) {
+		var b = NewBroker(sharedWithMember, 10, 1)
+		var slow, _ = b.Subscribe(memberID, nil)
+		var fast, _ = b.Subscribe(actorID, nil)
+		b.dispatch(taskEvent(types.EventKindTaskCreated))
+		receive(t, fast)
+		b.dispatch(taskEvent(types.EventKindTaskUpdated))
+		receive(t, fast)
+		assert.True(t, slow.Lagged())
+		assert.False(t, fast.Lagged())
+		assert.Equal(t, types.EventKindTaskCreated, receive(t, slow).Kind)
+		_, ok := <-slow.Events()
+		assert.False(t, ok)
+	})
+}
+
+func TestBroker_dispatchWithoutHistory(t *testing.T) {
+	var b = NewBroker(sharedWithMember, 0, 10)
+	var s, _ = b.Subscribe(memberID, nil)
+	assert.NotPanics(t, func() {
+		b.dispatch(taskEvent(types.EventKindTaskCreated))
+		b.dispatch(taskEvent(types.EventKindTaskUpdated))
+	})
+	assert.Equal(t, types.EventKindTaskCreated, receive(t, s).Kind)
+	var lastID = b.lastID - 1
+	_, missed := b.Subscribe(memberID, &lastID)
+	require.Len(t, missed, 1)
+	assert.Equal(t, types.EventKindReset, missed[0].Kind)
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	defer beQuiet()()
+	var b = NewBroker(func(event *model.Event) ([]uuid.UUID, error) {
+		if types.EventKindTaskCreated == event.Kind {
+			return []uuid.UUID{memberID}, nil
+		}
+		return nil, nil
+	}, 3, 10)
+	var events = []*model.Event{
+		taskEvent(types.EventKindTaskCreated),
+		taskEvent(types.EventKindTaskUpdated),
+		taskEvent(types.EventKindTaskCreated),
+		taskEvent(types.EventKindTaskCreated),
+	}
+	for _, event := range events {
+		b.dispatch(event)
+	}
+
+	t.Run("without last event", func(t *testing.T) {
+		s, missed := b.Subscribe(actorID, nil)
+		defer b.Unsubscribe(s)
+		assert.Empty(t, missed)
+	})
+
+	t.Run("catches up with the events the user can see", func(t *testing.T) {
+		var lastID = events[0].ID
+		s, missed := b.Subscribe(memberID, &lastID)
+		defer b.Unsubscribe(s)
+		assert.Equal(t, []*model.Event{events[2], events[3]}, missed)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		var lastID = events[3].ID
+		s, missed := b.Subscribe(actorID, &lastID)
+		defer b.Unsubscribe(s)
+		assert.Empty(t, missed)
+	})
+
+	t.Run("events are no longer kept", func(t *testing.T) {
+		var lastID = events[0].ID - 1
+		s, missed := b.Subscribe(actorID, &lastID)
+		defer b.Unsubscribe(s)
+		require.Len(t, missed, 1)
+		assert.Equal(t, types.EventKindReset, missed[0].Kind)
+		assert.Equal(t, events[3].ID, missed[0].ID)
+	})
+
+	t.Run("event was never published", func(t *testing.T) {
+		var lastID = events[3].ID + 1
+		s, missed := b.Subscribe(actorID, &lastID)
+		defer b.Unsubscribe(s)
+		require.Len(t, missed, 1)
+		assert.Equal(t, types.EventKindReset, missed[0].Kind)
+	})
+}
+
+func TestBroker_Unsubscribe(t *testing.T) {
+	var b = NewBroker(sharedWithMember, 10, 10)
+	var s, _ = b.Subscribe(actorID, nil)
+	b.Unsubscribe(s)
+	b.Unsubscribe(s)
+	_, ok := <-s.Events()
+	assert.False(t, ok)
+	b.dispatch(taskEvent(types.EventKindTaskCreated))
+}
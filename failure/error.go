@@ -95,6 +95,55 @@ var (
 		hint:    "",
 		status:  http.StatusUnauthorized,
 	}
+	ErrInvalidRefreshToken = &Error{
+		code:    ErrorCode("A0005"),
+		message: "Refresh token refused.",
+		details: "This refresh token is invalid, has expired or has been revoked.",
+		hint:    "Try signing in again.",
+		status:  http.StatusUnauthorized,
+	}
+	ErrRevokedToken = &Error{
+		code:    ErrorCode("A0006"),
+		message: "JSON Web Token failure.",
+		details: "This token has been revoked.",
+		hint:    "Try signing in again.",
+		status:  http.StatusUnauthorized,
+	}
+	ErrInvalidResetToken = &Error{
+		code:    ErrorCode("A0007"),
+		message: "Password reset refused.",
+		details: "This reset token is invalid, has expired or has already been used.",
+		hint:    "Ask for another password reset.",
+		status:  http.StatusBadRequest,
+	}
+	ErrInsufficientRole = &Error{
+		code:    ErrorCode("A0008"),
+		message: "Authorization refused.",
+		details: "Your role in this list or group does not allow this action.",
+		hint:    "Ask an admin of it for a higher role.",
+		status:  http.StatusForbidden,
+	}
+	ErrNotAuthor = &Error{
+		code:    ErrorCode("A0009"),
+		message: "Authorization refused.",
+		details: "Only the author of this comment can change or remove it.",
+		hint:    "",
+		status:  http.StatusForbidden,
+	}
+	ErrInvalidFeedToken = &Error{
+		code:    ErrorCode("A0010"),
+		message: "Calendar feed refused.",
+		details: "This feed token is invalid or has been revoked.",
+		hint:    "Subscribe again with the URL of a new feed token.",
+		status:  http.StatusUnauthorized,
+	}
+	ErrCrossOwnerMove = &Error{
+		code:    ErrorCode("A0011"),
+		message: "Authorization refused.",
+		details: "Tasks can only be moved between lists of the same owner.",
+		hint:    "Duplicate the task in the other list instead.",
+		status:  http.StatusForbidden,
+	}
 )
 
 /* Service details.  */
@@ -114,6 +163,41 @@ var (
 		hint:    "",
 		status:  http.StatusBadRequest,
 	}
+	ErrDependencyCycle = &Error{
+		code:    ErrorCode("S0003"),
+		message: "Dependency refused.",
+		details: "This task already blocks the other one, directly or through other tasks.",
+		hint:    "Remove one of the dependencies between them first.",
+		status:  http.StatusConflict,
+	}
+	ErrTaskBlocked = &Error{
+		code:    ErrorCode("S0004"),
+		message: "Task is blocked.",
+		details: "This task is blocked by %d task(s) that are not finished yet.",
+		hint:    "Finish its blockers first, or remove its dependencies on them.",
+		status:  http.StatusConflict,
+	}
+	ErrSubtaskTooDeep = &Error{
+		code:    ErrorCode("S0005"),
+		message: "Subtask refused.",
+		details: "Subtasks cannot be nested more than %d level(s) deep.",
+		hint:    "Add it to a task that is higher up instead.",
+		status:  http.StatusConflict,
+	}
+	ErrCalendarUIDConflict = &Error{
+		code:    ErrorCode("S0006"),
+		message: "Calendar object refused.",
+		details: "Another calendar object of this calendar already has this UID.",
+		hint:    "Change that calendar object instead.",
+		status:  http.StatusConflict,
+	}
+	ErrNestedTaskMove = &Error{
+		code:    ErrorCode("S0007"),
+		message: "Move refused.",
+		details: "Subtasks, and the tasks that have subtasks, cannot leave their list.",
+		hint:    "Move a task that is neither a subtask nor has subtasks instead.",
+		status:  http.StatusConflict,
+	}
 )
 
 /* Request details.  */
@@ -147,6 +231,69 @@ var (
 		hint:    "",
 		status:  http.StatusBadRequest,
 	}
+	ErrFileTooLarge = &Error{
+		code:    ErrorCode("RQ005"),
+		message: "Uploaded file is too large.",
+		details: "The file must not be larger than %d bytes.",
+		hint:    "",
+		status:  http.StatusRequestEntityTooLarge,
+	}
+	ErrMissingFile = &Error{
+		code:    ErrorCode("RQ006"),
+		message: "Missing file in request.",
+		details: "Body must be a multipart form with a file in the \"file\" field.",
+		hint:    "",
+		status:  http.StatusBadRequest,
+	}
+	ErrBadRecurrence = &Error{
+		code:    ErrorCode("RQ007"),
+		message: "Invalid recurrence rule.",
+		details: "%s",
+		hint:    "Use an RRULE such as \"FREQ=WEEKLY;BYDAY=MO,WE\" or \"FREQ=MONTHLY;BYDAY=-1FR;COUNT=6\".",
+		status:  http.StatusBadRequest,
+	}
+	ErrBadFilter = &Error{
+		code:    ErrorCode("RQ008"),
+		message: "Invalid filter.",
+		details: "%s",
+		hint:    "Use an expression such as \"priority>=high and due<7d and not completed\".",
+		status:  http.StatusBadRequest,
+	}
+	ErrBadCalendarObject = &Error{
+		code:    ErrorCode("RQ009"),
+		message: "Invalid calendar object.",
+		details: "%s",
+		hint:    "Send a VCALENDAR with a single VTODO.",
+		status:  http.StatusBadRequest,
+	}
+	ErrPreconditionFailed = &Error{
+		code:    ErrorCode("RQ010"),
+		message: "Precondition failed.",
+		details: "The resource has changed since it was last retrieved, or does not exist as expected.",
+		hint:    "Retrieve it again before changing it.",
+		status:  http.StatusPreconditionFailed,
+	}
+	ErrBadImport = &Error{
+		code:    ErrorCode("RQ011"),
+		message: "Invalid import.",
+		details: "%s",
+		hint:    "Fix the file and import it again; nothing was imported.",
+		status:  http.StatusBadRequest,
+	}
+	ErrIncompleteImport = &Error{
+		code:    ErrorCode("RQ012"),
+		message: "Import incomplete.",
+		details: "%s",
+		hint:    "Fix what is listed and import the file again; what was imported is skipped.",
+		status:  http.StatusMultiStatus,
+	}
+	ErrBadWebSocketHandshake = &Error{
+		code:    ErrorCode("RQ013"),
+		message: "Invalid WebSocket handshake.",
+		details: "%s",
+		hint:    "Connect with a WebSocket client, or without the \"Upgrade\" header to get Server-Sent Events.",
+		status:  http.StatusBadRequest,
+	}
 )
 
 /* Repository details.  */
@@ -187,6 +334,111 @@ var (
 		hint:    "",
 		status:  http.StatusNotFound,
 	}
+	ErrStepNotFound = &Error{
+		code:    ErrorCode("R0009"),
+		message: "Not found.",
+		details: "Could not find any step with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrTagNotFound = &Error{
+		code:    ErrorCode("R0010"),
+		message: "Not found.",
+		details: "Could not find any tag with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrAttachmentNotFound = &Error{
+		code:    ErrorCode("R0011"),
+		message: "Not found.",
+		details: "Could not find any attachment with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrNotificationNotFound = &Error{
+		code:    ErrorCode("R0014"),
+		message: "Not found.",
+		details: "Could not find any notification with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrInvitationNotFound = &Error{
+		code:    ErrorCode("R0015"),
+		message: "Not found.",
+		details: "Could not find any pending invitation with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrMemberNotFound = &Error{
+		code:    ErrorCode("R0016"),
+		message: "Not found.",
+		details: "Could not find any member with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrAlreadyMember = &Error{
+		code:    ErrorCode("R0017"),
+		message: "Conflicting membership.",
+		details: "This user is already a member or has a pending invitation.",
+		hint:    "",
+		status:  http.StatusConflict,
+	}
+	ErrCommentNotFound = &Error{
+		code:    ErrorCode("R0018"),
+		message: "Not found.",
+		details: "Could not find any comment with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrRevisionNotFound = &Error{
+		code:    ErrorCode("R0019"),
+		message: "Not found.",
+		details: "Could not find any revision of this task with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrNothingToUndo = &Error{
+		code:    ErrorCode("R0020"),
+		message: "Nothing to undo.",
+		details: "You have made no change to a task recently enough to undo it.",
+		hint:    "",
+		status:  http.StatusConflict,
+	}
+	ErrSmartListNotFound = &Error{
+		code:    ErrorCode("R0021"),
+		message: "Not found.",
+		details: "Could not find any smart list with this UUID.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrCalendarObjectNotFound = &Error{
+		code:    ErrorCode("R0022"),
+		message: "Not found.",
+		details: "Could not find any calendar object with this name.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrExportNotFound = &Error{
+		code:    ErrorCode("R0023"),
+		message: "Not found.",
+		details: "You have not asked for an export of your data yet.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
+	ErrExportNotReady = &Error{
+		code:    ErrorCode("R0024"),
+		message: "Export not ready.",
+		details: "The archive of your last export is %s.",
+		hint:    "Wait for the email telling that it is ready, or ask for another export.",
+		status:  http.StatusConflict,
+	}
+	ErrFeedNotFound = &Error{
+		code:    ErrorCode("R0025"),
+		message: "Not found.",
+		details: "Could not find any calendar feed for this token.",
+		hint:    "",
+		status:  http.StatusNotFound,
+	}
 	ErrSettingNotFound = &Error{
 		code:    ErrorCode("R0004"),
 		message: "Not found.",
@@ -215,6 +467,20 @@ var (
 		hint:    "",
 		status:  http.StatusForbidden,
 	}
+	ErrUserDeleted = &Error{
+		code:    ErrorCode("R0012"),
+		message: "Authentication refused.",
+		details: "This user account has been deleted.",
+		hint:    "",
+		status:  http.StatusForbidden,
+	}
+	ErrUserNotDeleted = &Error{
+		code:    ErrorCode("R0013"),
+		message: "Restoration refused.",
+		details: "This user account is not pending deletion.",
+		hint:    "",
+		status:  http.StatusBadRequest,
+	}
 	ErrDeadlineExceeded = errors.New("context deadline exceeded")
 )
 
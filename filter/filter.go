@@ -0,0 +1,557 @@
+// Package filter implements the expression language of smart lists, such as
+// "priority>=high and due<7d and not completed".
+//
+// An expression is made of conditions joined with "and" and "or", negated
+// with "not" and grouped with parentheses; "and" binds tighter than "or". A
+// condition compares a field with a value:
+//
+//	priority  =, !=, <, <=, >, >=  low, normal, medium, high or urgent
+//	status    =, !=                open, completed or deferred
+//	due       =, !=, <, <=, >, >=  today, tomorrow, a date such as 2024-12-31,
+//	                               a time from now such as 7d, -12h or 2w,
+//	                               or none (only with = and !=)
+//	tag       =, !=                the name of a tag the task has
+//	list      =, !=                the name or the UUID of the list of the task
+//	pinned    =, !=                true or false
+//	text      =, !=                text in the title, headline or description
+//
+// ":" can be used instead of "=". A condition can also be one of the words
+// "completed", "pinned" or "overdue", or a quoted text to look for. Values
+// with spaces are quoted, and names and text are matched regardless of case.
+package filter
+
+import (
+	"fmt"
+	"noda/data/model"
+	"noda/data/types"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Filter is a parsed expression that tells which tasks it matches.
+type Filter struct {
+	expression string
+	match      node
+	narrow     narrowing
+}
+
+// node tells whether a task matches a part of the expression at a given time.
+type node func(task *model.FilterableTask, now time.Time) bool
+
+// narrowing narrows down the candidates to the tasks that can match a part of
+// the expression at a given time, as far as a CandidateFilter can tell them
+// apart. A nil narrowing leaves them all.
+type narrowing func(now time.Time, candidates *types.CandidateFilter)
+
+// Parse parses an expression. The error tells where the expression is wrong.
+func Parse(expression string) (*Filter, error) {
+	tokens, err := tokenize(expression)
+	if nil != err {
+		return nil, err
+	}
+	var p = &parser{tokens: tokens}
+	match, narrow, err := p.parseOr()
+	if nil != err {
+		return nil, err
+	}
+	if next := p.peek(); tokenEnd != next.kind {
+		return nil, next.errorf("expected \"and\", \"or\" or the end of the filter, found %q", next.text)
+	}
+	return &Filter{expression: expression, match: match, narrow: narrow}, nil
+}
+
+// Match tells whether the task matches the filter, with relative dates such
+// as "7d" taken from now. Days such as "today" or "2024-12-31" are the
+// calendar days of the location of now, which should thus be the time zone of
+// the user.
+func (f *Filter) Match(task *model.FilterableTask, now time.Time) bool {
+	return f.match(task, now)
+}
+
+// Candidates tells which tasks can match the filter at the time now, so that
+// only those are looked through with Match. It narrows them down by the
+// conditions every matching task meets, such as "status" or "due" outside of
+// "or" and "not".
+func (f *Filter) Candidates(now time.Time) *types.CandidateFilter {
+	var candidates = new(types.CandidateFilter)
+	if nil != f.narrow {
+		f.narrow(now, candidates)
+	}
+	return candidates
+}
+
+func (f *Filter) String() string {
+	return f.expression
+}
+
+type tokenKind uint8
+
+const (
+	tokenEnd tokenKind = iota
+	tokenWord
+	tokenText
+	tokenOperator
+	tokenOpening
+	tokenClosing
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int // position is the 1-based position of the token in the expression.
+}
+
+func (t token) errorf(format string, args ...any) error {
+	return fmt.Errorf("at position %d: %s", t.position, fmt.Sprintf(format, args...))
+}
+
+// isKeyword tells whether the token is the given word, regardless of case.
+func (t token) isKeyword(keyword string) bool {
+	return tokenWord == t.kind && strings.EqualFold(t.text, keyword)
+}
+
+func tokenize(expression string) (tokens []token, err error) {
+	var runes = []rune(expression)
+	for i := 0; i < len(runes); {
+		var r = runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case '(' == r:
+			tokens = append(tokens, token{tokenOpening, "(", i + 1})
+			i++
+		case ')' == r:
+			tokens = append(tokens, token{tokenClosing, ")", i + 1})
+			i++
+		case '"' == r:
+			var end = i + 1
+			for end < len(runes) && '"' != runes[end] {
+				end++
+			}
+			if len(runes) == end {
+				return nil, fmt.Errorf("at position %d: the quote is never closed", i+1)
+			}
+			tokens = append(tokens, token{tokenText, string(runes[i+1 : end]), i + 1})
+			i = end + 1
+		case strings.ContainsRune("=!<>:", r):
+			var end = i + 1
+			if end < len(runes) && '=' == runes[end] && '=' != r && ':' != r {
+				end++
+			}
+			var operator = string(runes[i:end])
+			if "!" == operator {
+				return nil, fmt.Errorf("at position %d: expected \"!=\"", i+1)
+			}
+			tokens = append(tokens, token{tokenOperator, operator, i + 1})
+			i = end
+		default:
+			var end = i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("()\"=!<>:", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{tokenWord, string(runes[i:end]), i + 1})
+			i = end
+		}
+	}
+	return append(tokens, token{tokenEnd, "", len(runes) + 1}), nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) take() token {
+	var t = p.tokens[p.next]
+	if tokenEnd != t.kind {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, narrowing, error) {
+	left, narrow, err := p.parseAnd()
+	if nil != err {
+		return nil, nil, err
+	}
+	for p.peek().isKeyword("or") {
+		p.take()
+		right, _, err := p.parseAnd()
+		if nil != err {
+			return nil, nil, err
+		}
+		left, narrow = or(left, right), nil
+	}
+	return left, narrow, nil
+}
+
+func (p *parser) parseAnd() (node, narrowing, error) {
+	left, narrow, err := p.parseUnary()
+	if nil != err {
+		return nil, nil, err
+	}
+	for p.peek().isKeyword("and") {
+		p.take()
+		right, narrowRight, err := p.parseUnary()
+		if nil != err {
+			return nil, nil, err
+		}
+		left, narrow = and(left, right), both(narrow, narrowRight)
+	}
+	return left, narrow, nil
+}
+
+func (p *parser) parseUnary() (node, narrowing, error) {
+	var t = p.take()
+	switch {
+	case t.isKeyword("not"):
+		operand, _, err := p.parseUnary()
+		if nil != err {
+			return nil, nil, err
+		}
+		return not(operand), nil, nil
+	case tokenOpening == t.kind:
+		inner, narrow, err := p.parseOr()
+		if nil != err {
+			return nil, nil, err
+		}
+		if closing := p.take(); tokenClosing != closing.kind {
+			return nil, nil, closing.errorf("expected \")\"")
+		}
+		return inner, narrow, nil
+	case tokenText == t.kind:
+		return matchText("=", t.text), nil, nil
+	case tokenWord == t.kind && !t.isKeyword("and") && !t.isKeyword("or"):
+		if tokenOperator == p.peek().kind {
+			var operator = p.take()
+			var value = p.take()
+			if tokenWord != value.kind && tokenText != value.kind {
+				return nil, nil, value.errorf("expected a value after %q", operator.text)
+			}
+			return parseCondition(t, operator, value)
+		}
+		return parseFlag(t)
+	case tokenEnd == t.kind:
+		return nil, nil, t.errorf("expected a condition, found the end of the filter")
+	default:
+		return nil, nil, t.errorf("expected a condition, found %q", t.text)
+	}
+}
+
+func parseFlag(word token) (node, narrowing, error) {
+	switch strings.ToLower(word.text) {
+	case "completed":
+		return matchStatus("=", types.TaskStatusComplete), narrowStatus("=", types.TaskStatusComplete), nil
+	case "pinned":
+		return matchPinned("=", true), narrowPinned("=", true), nil
+	case "overdue":
+		var match = func(task *model.FilterableTask, now time.Time) bool {
+			return nil != task.Task.DueDate &&
+				task.Task.DueDate.Before(now) &&
+				types.TaskStatusComplete != task.Task.Status
+		}
+		var narrow = func(now time.Time, candidates *types.CandidateFilter) {
+			narrowDue(candidates, nil, &now)
+		}
+		return match, narrow, nil
+	default:
+		return nil, nil, word.errorf("unknown condition %q", word.text)
+	}
+}
+
+func parseCondition(field, operator, value token) (node, narrowing, error) {
+	var op = operator.text
+	if ":" == op {
+		op = "="
+	}
+	var equality = "=" == op || "!=" == op
+	var name = strings.ToLower(field.text)
+	if !equality && "priority" != name && "due" != name {
+		if _, known := fields[name]; known {
+			return nil, nil, operator.errorf("%q can only be compared with \"=\" or \"!=\"", field.text)
+		}
+	}
+	switch name {
+	case "priority":
+		var rank, known = priorityRanks[types.TaskPriority(strings.ToLower(value.text))]
+		if !known {
+			return nil, nil, value.errorf("unknown priority %q", value.text)
+		}
+		return func(task *model.FilterableTask, _ time.Time) bool {
+			return compare(priorityRanks[task.Task.Priority], rank, op)
+		}, nil, nil
+	case "status":
+		var status, known = statuses[strings.ToLower(value.text)]
+		if !known {
+			return nil, nil, value.errorf("unknown status %q", value.text)
+		}
+		return matchStatus(op, status), narrowStatus(op, status), nil
+	case "due":
+		return parseDue(op, operator, value)
+	case "tag":
+		var wanted = value.text
+		return func(task *model.FilterableTask, _ time.Time) bool {
+			var has = false
+			for _, tag := range task.TagNames {
+				has = has || strings.EqualFold(tag, wanted)
+			}
+			return has == ("=" == op)
+		}, nil, nil
+	case "list":
+		var wanted = value.text
+		var id, isID = uuid.Parse(wanted)
+		var narrow narrowing
+		if "=" == op {
+			narrow = func(_ time.Time, candidates *types.CandidateFilter) {
+				candidates.List = &wanted
+			}
+		}
+		return func(task *model.FilterableTask, _ time.Time) bool {
+			var in = strings.EqualFold(task.ListName, wanted) || (nil == isID && id == task.Task.ListUUID)
+			return in == ("=" == op)
+		}, narrow, nil
+	case "pinned":
+		pinned, err := strconv.ParseBool(strings.ToLower(value.text))
+		if nil != err {
+			return nil, nil, value.errorf("expected true or false, found %q", value.text)
+		}
+		return matchPinned(op, pinned), narrowPinned(op, pinned), nil
+	case "text":
+		return matchText(op, value.text), nil, nil
+	default:
+		return nil, nil, field.errorf("unknown field %q", field.text)
+	}
+}
+
+func parseDue(op string, operator, value token) (node, narrowing, error) {
+	if strings.EqualFold("none", value.text) {
+		if "=" != op && "!=" != op {
+			return nil, nil, operator.errorf("\"due\" can only be compared with none using \"=\" or \"!=\"")
+		}
+		return func(task *model.FilterableTask, _ time.Time) bool {
+			return (nil == task.Task.DueDate) == ("=" == op)
+		}, nil, nil
+	}
+	reference, isDay, err := parseMoment(value.text)
+	if nil != err {
+		return nil, nil, value.errorf("%s", err)
+	}
+	// bounds is the moment or the day a due date is compared with.
+	var bounds = func(now time.Time) (start, end time.Time) {
+		start = reference(now)
+		if !isDay {
+			return start, start
+		}
+		return start, start.AddDate(0, 0, 1)
+	}
+	var match = func(task *model.FilterableTask, now time.Time) bool {
+		if nil == task.Task.DueDate {
+			return false
+		}
+		var due = *task.Task.DueDate
+		var start, end = bounds(now)
+		if !isDay {
+			return compare(due.Compare(start), 0, op)
+		}
+		switch op {
+		case "=":
+			return !due.Before(start) && due.Before(end)
+		case "!=":
+			return due.Before(start) || !due.Before(end)
+		case "<":
+			return due.Before(start)
+		case "<=":
+			return due.Before(end)
+		case ">":
+			return !due.Before(end)
+		default:
+			return !due.Before(start)
+		}
+	}
+	var narrow = func(now time.Time, candidates *types.CandidateFilter) {
+		var start, end = bounds(now)
+		switch op {
+		case "=":
+			narrowDue(candidates, &start, &end)
+		case "<":
+			narrowDue(candidates, nil, &start)
+		case "<=":
+			narrowDue(candidates, nil, &end)
+		case ">":
+			narrowDue(candidates, &end, nil)
+		case ">=":
+			narrowDue(candidates, &start, nil)
+		}
+	}
+	return match, narrow, nil
+}
+
+// parseMoment parses a value of "due" into a function that finds the moment it
+// stands for at a given time. A day, such as today or 2024-12-31, stands for
+// its midnight in the location of that time, and isDay tells that it is to be
+// compared as the whole day up to the next midnight.
+func parseMoment(value string) (reference func(now time.Time) time.Time, isDay bool, err error) {
+	var lowered = strings.ToLower(value)
+	switch lowered {
+	case "today":
+		return func(now time.Time) time.Time { return startOfDay(now) }, true, nil
+	case "tomorrow":
+		return func(now time.Time) time.Time { return startOfDay(now).AddDate(0, 0, 1) }, true, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); nil == err {
+		var year, month, day = date.Date()
+		return func(now time.Time) time.Time { return time.Date(year, month, day, 0, 0, 0, 0, now.Location()) }, true, nil
+	}
+	if 2 <= len(lowered) {
+		var amount, err = strconv.Atoi(lowered[:len(lowered)-1])
+		if nil == err {
+			switch lowered[len(lowered)-1] {
+			case 'h':
+				return func(now time.Time) time.Time { return now.Add(time.Duration(amount) * time.Hour) }, false, nil
+			case 'd':
+				return func(now time.Time) time.Time { return now.AddDate(0, 0, amount) }, false, nil
+			case 'w':
+				return func(now time.Time) time.Time { return now.AddDate(0, 0, 7*amount) }, false, nil
+			}
+		}
+	}
+	return nil, false, fmt.Errorf("expected today, tomorrow, none, a date such as 2024-12-31 or a time from now such as 7d, found %q", value)
+}
+
+func startOfDay(t time.Time) time.Time {
+	var year, month, day = t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func matchStatus(op string, status types.TaskStatus) node {
+	return func(task *model.FilterableTask, _ time.Time) bool {
+		return (task.Task.Status == status) == ("=" == op)
+	}
+}
+
+func matchPinned(op string, pinned bool) node {
+	return func(task *model.FilterableTask, _ time.Time) bool {
+		return (task.Task.IsPinned == pinned) == ("=" == op)
+	}
+}
+
+func matchText(op, text string) node {
+	var needle = strings.ToLower(text)
+	return func(task *model.FilterableTask, _ time.Time) bool {
+		var found = strings.Contains(strings.ToLower(task.Task.Title), needle) ||
+			strings.Contains(strings.ToLower(task.Task.Headline), needle) ||
+			strings.Contains(strings.ToLower(task.Task.Description), needle)
+		return found == ("=" == op)
+	}
+}
+
+func narrowStatus(op string, status types.TaskStatus) narrowing {
+	if "=" != op {
+		return nil
+	}
+	return func(_ time.Time, candidates *types.CandidateFilter) {
+		candidates.Status = &status
+	}
+}
+
+func narrowPinned(op string, pinned bool) narrowing {
+	pinned = pinned == ("=" == op)
+	return func(_ time.Time, candidates *types.CandidateFilter) {
+		candidates.Pinned = &pinned
+	}
+}
+
+// narrowDue keeps the due dates of the candidates between from and to, both
+// inclusive, within those they are already kept between.
+func narrowDue(candidates *types.CandidateFilter, from, to *time.Time) {
+	if nil != from && (nil == candidates.DueFrom || from.After(*candidates.DueFrom)) {
+		candidates.DueFrom = from
+	}
+	if nil != to && (nil == candidates.DueTo || to.Before(*candidates.DueTo)) {
+		candidates.DueTo = to
+	}
+}
+
+// both narrows down the candidates to the tasks that can match two parts of
+// the expression joined with "and". Where they disagree, as with two
+// statuses, no task can match, so keeping the last one is as good.
+func both(left, right narrowing) narrowing {
+	switch {
+	case nil == left:
+		return right
+	case nil == right:
+		return left
+	}
+	return func(now time.Time, candidates *types.CandidateFilter) {
+		left(now, candidates)
+		right(now, candidates)
+	}
+}
+
+func and(left, right node) node {
+	return func(task *model.FilterableTask, now time.Time) bool {
+		return left(task, now) && right(task, now)
+	}
+}
+
+func or(left, right node) node {
+	return func(task *model.FilterableTask, now time.Time) bool {
+		return left(task, now) || right(task, now)
+	}
+}
+
+func not(operand node) node {
+	return func(task *model.FilterableTask, now time.Time) bool {
+		return !operand(task, now)
+	}
+}
+
+// compare applies the operator to a and b.
+func compare(a, b int, op string) bool {
+	switch op {
+	case "=":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+var fields = map[string]struct{}{
+	"priority": {},
+	"status":   {},
+	"due":      {},
+	"tag":      {},
+	"list":     {},
+	"pinned":   {},
+	"text":     {},
+}
+
+var priorityRanks = map[types.TaskPriority]int{
+	types.TaskPriorityLow:    1,
+	types.TaskPriorityNormal: 2,
+	types.TaskPriorityMedium: 3,
+	types.TaskPriorityHigh:   4,
+	types.TaskPriorityUrgent: 5,
+}
+
+var statuses = map[string]types.TaskStatus{
+	"open":      types.TaskStatusIncomplete,
+	"completed": types.TaskStatusComplete,
+	"deferred":  types.TaskStatusDeferred,
+}
@@ -0,0 +1,242 @@
+package filter
+
+import (
+	"noda/data/model"
+	"noda/data/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2024, time.March, 14, 9, 30, 0, 0, time.UTC)
+
+func due(days int) *time.Time {
+	var t = now.AddDate(0, 0, days)
+	return &t
+}
+
+// groceries is a list holding an urgent task due in two days, tagged with
+// "errands", and a finished low priority task without due date.
+var (
+	groceries = uuid.New()
+	milk      = &model.FilterableTask{
+		Task: &model.Task{
+			ListUUID:    groceries,
+			Title:       "Buy milk",
+			Description: "Oat milk, not the sweet one",
+			Priority:    types.TaskPriorityUrgent,
+			Status:      types.TaskStatusIncomplete,
+			IsPinned:    true,
+			DueDate:     due(2),
+		},
+		ListName: "Groceries",
+		TagNames: []string{"Errands"},
+	}
+	receipts = &model.FilterableTask{
+		Task: &model.Task{
+			ListUUID: groceries,
+			Title:    "File the receipts",
+			Priority: types.TaskPriorityLow,
+			Status:   types.TaskStatusComplete,
+		},
+		ListName: "Groceries",
+		TagNames: []string{},
+	}
+	report = &model.FilterableTask{
+		Task: &model.Task{
+			ListUUID: uuid.New(),
+			Title:    "Quarterly report",
+			Priority: types.TaskPriorityHigh,
+			Status:   types.TaskStatusIncomplete,
+			DueDate:  due(-1),
+		},
+		ListName: "Work",
+		TagNames: []string{"finance", "errands"},
+	}
+)
+
+func TestFilter_Match(t *testing.T) {
+	var cases = []struct {
+		expression string
+		expected   []*model.FilterableTask
+	}{
+		{`priority>=high and due<7d and not completed`, []*model.FilterableTask{milk, report}},
+		{`priority=urgent`, []*model.FilterableTask{milk}},
+		{`priority<medium`, []*model.FilterableTask{receipts}},
+		{`status:completed`, []*model.FilterableTask{receipts}},
+		{`status!=open`, []*model.FilterableTask{receipts}},
+		{`completed or pinned`, []*model.FilterableTask{milk, receipts}},
+		{`overdue`, []*model.FilterableTask{report}},
+		{`due=none`, []*model.FilterableTask{receipts}},
+		{`due>=today and due<tomorrow`, []*model.FilterableTask{}},
+		{`due>2024-03-15`, []*model.FilterableTask{milk}},
+		{`due<-12h`, []*model.FilterableTask{report}},
+		{`tag=errands and not tag:finance`, []*model.FilterableTask{milk}},
+		{`list="groceries"`, []*model.FilterableTask{milk, receipts}},
+		{`list=` + groceries.String(), []*model.FilterableTask{milk, receipts}},
+		{`pinned=false and text!=receipts`, []*model.FilterableTask{report}},
+		{`"OAT MILK"`, []*model.FilterableTask{milk}},
+		{`text="the receipts" OR (priority=high AND NOT due=none)`, []*model.FilterableTask{receipts, report}},
+		{`not (completed or overdue)`, []*model.FilterableTask{milk}},
+	}
+	for _, c := range cases {
+		f, err := Parse(c.expression)
+		if !assert.NoError(t, err, c.expression) {
+			continue
+		}
+		var matched = make([]*model.FilterableTask, 0)
+		for _, task := range []*model.FilterableTask{milk, receipts, report} {
+			if f.Match(task, now) {
+				matched = append(matched, task)
+			}
+		}
+		assert.Equal(t, c.expected, matched, c.expression)
+	}
+}
+
+func TestFilter_MatchDay(t *testing.T) {
+	var managua, err = time.LoadLocation("America/Managua")
+	if !assert.NoError(t, err) {
+		return
+	}
+	// The task is due late on March 14 in Managua, which is already March 15
+	// in UTC, as is the evening of March 14 in Managua.
+	var (
+		dueAt = time.Date(2024, time.March, 14, 23, 30, 0, 0, managua).UTC()
+		task  = &model.FilterableTask{Task: &model.Task{DueDate: &dueAt}}
+		local = time.Date(2024, time.March, 14, 20, 0, 0, 0, managua)
+	)
+	var cases = []struct {
+		expression string
+		now        time.Time
+		expected   bool
+	}{
+		{`due=today`, local, true},
+		{`due:2024-03-14`, local, true},
+		{`due!=today`, local, false},
+		{`due<today`, local, false},
+		{`due<=today`, local, true},
+		{`due>today`, local, false},
+		{`due>=today`, local, true},
+		{`due=tomorrow`, local, false},
+		{`due<2024-03-15`, local, true},
+		{`due>2024-03-13`, local, true},
+		{`due=2024-03-15`, local, false},
+		{`due=today`, local.UTC(), true},
+		{`due=2024-03-14`, local.UTC(), false},
+		{`due=2024-03-15`, local.UTC(), true},
+	}
+	for _, c := range cases {
+		f, err := Parse(c.expression)
+		if !assert.NoError(t, err, c.expression) {
+			continue
+		}
+		assert.Equal(t, c.expected, f.Match(task, c.now), "%s at %s", c.expression, c.now)
+	}
+}
+
+func TestFilter_Candidates(t *testing.T) {
+	var (
+		open     = types.TaskStatusIncomplete
+		complete = types.TaskStatusComplete
+		yes, no  = true, false
+		work     = "Work"
+		today    = time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC)
+		tomorrow = today.AddDate(0, 0, 1)
+		inAWeek  = now.AddDate(0, 0, 7)
+	)
+	var cases = []struct {
+		expression string
+		expected   *types.CandidateFilter
+	}{
+		{`priority>=high and due<7d and not completed`, &types.CandidateFilter{DueTo: &inAWeek}},
+		{`status=open and pinned`, &types.CandidateFilter{Status: &open, Pinned: &yes}},
+		{`completed and pinned!=true`, &types.CandidateFilter{Status: &complete, Pinned: &no}},
+		{`status!=open`, &types.CandidateFilter{}},
+		{`list=Work and (tag=finance or text=report)`, &types.CandidateFilter{List: &work}},
+		{`list!=Work`, &types.CandidateFilter{}},
+		{`due=today`, &types.CandidateFilter{DueFrom: &today, DueTo: &tomorrow}},
+		{`due>=today and due<7d and overdue`, &types.CandidateFilter{DueFrom: &today, DueTo: &now}},
+		{`(due<today and pinned) or completed`, &types.CandidateFilter{}},
+		{`not (status=open and pinned)`, &types.CandidateFilter{}},
+	}
+	for _, c := range cases {
+		f, err := Parse(c.expression)
+		if !assert.NoError(t, err, c.expression) {
+			continue
+		}
+		assert.Equal(t, c.expected, f.Candidates(now), c.expression)
+	}
+}
+
+// TestFilter_CandidatesMatch makes sure that the candidates of a filter hold
+// every task it matches.
+func TestFilter_CandidatesMatch(t *testing.T) {
+	var admits = func(c *types.CandidateFilter, task *model.FilterableTask) bool {
+		var due = task.Task.DueDate
+		switch {
+		case nil != c.Status && *c.Status != task.Task.Status:
+			return false
+		case nil != c.Pinned && *c.Pinned != task.Task.IsPinned:
+			return false
+		case nil != c.List && !strings.EqualFold(*c.List, task.ListName) && *c.List != task.Task.ListUUID.String():
+			return false
+		case nil != c.DueFrom && (nil == due || due.Before(*c.DueFrom)):
+			return false
+		case nil != c.DueTo && (nil == due || due.After(*c.DueTo)):
+			return false
+		}
+		return true
+	}
+	for _, expression := range []string{
+		`priority>=high and due<7d and not completed`,
+		`completed and pinned=false`,
+		`pinned and status=open and list=groceries`,
+		`list=` + groceries.String() + ` and due>today`,
+		`overdue and tag=finance`,
+		`due<=2024-03-16 and due>=-1d`,
+		`due>2024-03-15 and due<3d`,
+		`status=open and status=completed`,
+	} {
+		f, err := Parse(expression)
+		if !assert.NoError(t, err, expression) {
+			continue
+		}
+		var candidates = f.Candidates(now)
+		for _, task := range []*model.FilterableTask{milk, receipts, report} {
+			if f.Match(task, now) {
+				assert.True(t, admits(candidates, task), "%s leaves %q out", expression, task.Task.Title)
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	var cases = []struct {
+		expression string
+		expected   string
+	}{
+		{``, `at position 1: expected a condition, found the end of the filter`},
+		{`priority>=highest`, `at position 11: unknown priority "highest"`},
+		{`priority>=`, `at position 11: expected a value after ">="`},
+		{`colour=red`, `at position 1: unknown field "colour"`},
+		{`tag<work`, `at position 4: "tag" can only be compared with "=" or "!="`},
+		{`due<soon`, `at position 5: expected today, tomorrow, none, a date such as 2024-12-31 or a time from now such as 7d, found "soon"`},
+		{`due>none`, `at position 4: "due" can only be compared with none using "=" or "!="`},
+		{`pinned=maybe`, `at position 8: expected true or false, found "maybe"`},
+		{`(completed or pinned`, `at position 21: expected ")"`},
+		{`completed pinned`, `at position 11: expected "and", "or" or the end of the filter, found "pinned"`},
+		{`completed and`, `at position 14: expected a condition, found the end of the filter`},
+		{`text="milk`, `at position 6: the quote is never closed`},
+		{`priority!high`, `at position 9: expected "!="`},
+		{`soon`, `at position 1: unknown condition "soon"`},
+	}
+	for _, c := range cases {
+		f, err := Parse(c.expression)
+		assert.Nil(t, f, c.expression)
+		assert.EqualError(t, err, c.expected, c.expression)
+	}
+}
@@ -3,18 +3,77 @@ package global
 import (
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
-var secret string
+var (
+	secret              string
+	deletionGracePeriod = 30 * 24 * time.Hour
+	auditLogRetention   = 365 * 24 * time.Hour
+	exportRetention     = 7 * 24 * time.Hour
+	subtaskMaxDepth     = 5
+)
 
 func init() {
 	secret = strings.TrimSpace(os.Getenv("JWT_SECRET"))
 	if "" == secret {
 		log.Fatal("could not load env var: JWT_SECRET")
 	}
+	if period := strings.TrimSpace(os.Getenv("ACCOUNT_DELETION_GRACE_PERIOD")); "" != period {
+		var err error
+		deletionGracePeriod, err = time.ParseDuration(period)
+		if nil != err || 0 > deletionGracePeriod {
+			log.Fatalf("could not parse env var ACCOUNT_DELETION_GRACE_PERIOD: %q", period)
+		}
+	}
+	if retention := strings.TrimSpace(os.Getenv("AUDIT_LOG_RETENTION")); "" != retention {
+		var err error
+		auditLogRetention, err = time.ParseDuration(retention)
+		if nil != err || 0 > auditLogRetention {
+			log.Fatalf("could not parse env var AUDIT_LOG_RETENTION: %q", retention)
+		}
+	}
+	if retention := strings.TrimSpace(os.Getenv("EXPORT_RETENTION")); "" != retention {
+		var err error
+		exportRetention, err = time.ParseDuration(retention)
+		if nil != err || 0 >= exportRetention {
+			log.Fatalf("could not parse env var EXPORT_RETENTION: %q", retention)
+		}
+	}
+	if depth := strings.TrimSpace(os.Getenv("SUBTASK_MAX_DEPTH")); "" != depth {
+		var err error
+		subtaskMaxDepth, err = strconv.Atoi(depth)
+		if nil != err || 1 > subtaskMaxDepth {
+			log.Fatalf("could not parse env var SUBTASK_MAX_DEPTH: %q", depth)
+		}
+	}
 }
 
 func Secret() []byte {
 	return []byte(secret)
 }
+
+// AccountDeletionGracePeriod is how long a deleted account can be restored
+// before it is purged.
+func AccountDeletionGracePeriod() time.Duration {
+	return deletionGracePeriod
+}
+
+// AuditLogRetention is how long the entries of the audit log are kept before
+// they are purged. Zero keeps them forever.
+func AuditLogRetention() time.Duration {
+	return auditLogRetention
+}
+
+// ExportRetention is how long the archive of an export can be downloaded
+// before it is removed.
+func ExportRetention() time.Duration {
+	return exportRetention
+}
+
+// SubtaskMaxDepth is how many levels of subtasks a task can have below it.
+func SubtaskMaxDepth() int {
+	return subtaskMaxDepth
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/service"
+)
+
+type AssignmentHandler struct {
+	s service.AssignmentService
+}
+
+func NewAssignmentHandler(service service.AssignmentService) *AssignmentHandler {
+	return &AssignmentHandler{s: service}
+}
+
+func (h *AssignmentHandler) HandleAssigneesRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	assignees, err := h.s.Fetch(userID, listID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(assignees)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *AssignmentHandler) HandleTaskAssignment(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var assigneeID = parseParameterToUUID(w, r, "user_uuid")
+	if didNotParse(assigneeID) {
+		return
+	}
+	ok, err := h.s.Assign(userID, listID, taskID, assigneeID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, "/me/lists/"+listID.String()+"/tasks/"+taskID.String()+"/assignees")
+}
+
+func (h *AssignmentHandler) HandleTaskUnassignment(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var assigneeID = parseParameterToUUID(w, r, "user_uuid")
+	if didNotParse(assigneeID) {
+		return
+	}
+	_, err := h.s.Unassign(userID, listID, taskID, assigneeID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AssignmentHandler) HandleRetrievalOfAssignedTasks(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	var search, sortExpr = extractQueryParameter(r, "search", ""), extractSorting(w, r)
+	if "?" == sortExpr {
+		return
+	}
+	result, err := h.s.FetchAssigned(userID, pagination, search, sortExpr)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAssignmentHandler_HandleAssigneesRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/assignees"
+		serviceMethod = "Fetch"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			assignees            = []*model.Assignee{{TaskUUID: taskID, UserUUID: uuid.New(), Email: "member@noda.com"}}
+			expectedResponseBody = marshal(t, assignees)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewAssignmentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID).Return(assignees, nil)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleAssigneesRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("could not parse task UUID", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": "x"})
+		var m = mocks.NewAssignmentServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleAssigneesRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestAssignmentHandler_HandleTaskAssignment(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/assignees/{user_uuid}"
+		serviceMethod = "Assign"
+	)
+	var listID, taskID, memberID = uuid.New(), uuid.New(), uuid.New()
+	var pathParameters = parameters{"list_uuid": listID.String(), "task_uuid": taskID.String(), "user_uuid": memberID.String()}
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewAssignmentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, memberID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleTaskAssignment(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("already assigned? take me to the assignees", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewAssignmentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, memberID).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleTaskAssignment(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/lists/"+listID.String()+"/tasks/"+taskID.String()+"/assignees")
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrInsufficientRole
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewAssignmentServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleTaskAssignment(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestAssignmentHandler_HandleTaskUnassignment(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/assignees/{user_uuid}"
+		serviceMethod = "Unassign"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String(), "user_uuid": userID.String()})
+		var m = mocks.NewAssignmentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, userID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleTaskUnassignment(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("could not parse user UUID", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String(), "user_uuid": "x"})
+		var m = mocks.NewAssignmentServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleTaskUnassignment(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestAssignmentHandler_HandleRetrievalOfAssignedTasks(t *testing.T) {
+	const (
+		method        = "GET"
+		serviceMethod = "FetchAssigned"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination           = types.Pagination{Page: 1, RPP: 10}
+			tasks                = []*model.Task{{UUID: uuid.New(), Title: "Write the report"}}
+			result               = &types.Result[model.Task]{Page: 1, RPP: 10, Retrieved: 1, Payload: tasks}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, "/me/assigned?search=report&sort_by=-due_date", nil)
+		withLoggedUser(&request)
+		var m = mocks.NewAssignmentServiceMock()
+		m.On(serviceMethod, userID, &pagination, "report", "-due_date").Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleRetrievalOfAssignedTasks(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("could not parse sorting", func(t *testing.T) {
+		var request = httptest.NewRequest(method, "/me/assigned?sort_by=due_date", nil)
+		withLoggedUser(&request)
+		var m = mocks.NewAssignmentServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleRetrievalOfAssignedTasks(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, "/me/assigned", nil)
+		withLoggedUser(&request)
+		var m = mocks.NewAssignmentServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewAssignmentHandler(m).HandleRetrievalOfAssignedTasks(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"noda/failure"
+	"noda/service"
+	"strconv"
+	"time"
+)
+
+// transferTimeout is how long an upload or a download may take, which is
+// much longer than what the server allows for the rest of the requests.
+const transferTimeout = 5 * time.Minute
+
+type AttachmentHandler struct {
+	s service.AttachmentService
+}
+
+func NewAttachmentHandler(service service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{s: service}
+}
+
+// extendDeadlines gives the current request transferTimeout to complete.
+func extendDeadlines(w http.ResponseWriter) {
+	var controller = http.NewResponseController(w)
+	var deadline = time.Now().Add(transferTimeout)
+	if err := controller.SetReadDeadline(deadline); nil != err && !errors.Is(err, http.ErrNotSupported) {
+		log.Println(err)
+	}
+	if err := controller.SetWriteDeadline(deadline); nil != err && !errors.Is(err, http.ErrNotSupported) {
+		log.Println(err)
+	}
+}
+
+// nextFilePart advances reader up to the "file" field of the form.
+func nextFilePart(reader *multipart.Reader) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if nil != err {
+			return nil, err
+		}
+		if "file" == part.FormName() && "" != part.FileName() {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+func (h *AttachmentHandler) HandleAttachmentUpload(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	extendDeadlines(w)
+	/* Leave some room for the multipart boundaries and headers; the file
+	   itself is limited by the service.  */
+	r.Body = http.MaxBytesReader(w, r.Body, service.MaxAttachmentSize+1<<20)
+	reader, err := r.MultipartReader()
+	if nil != err {
+		failure.EmitError(w, failure.ErrMissingFile)
+		return
+	}
+	part, err := nextFilePart(reader)
+	if nil != err {
+		var tooLarge *http.MaxBytesError
+		switch {
+		default:
+			failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		case errors.Is(err, io.EOF):
+			failure.EmitError(w, failure.ErrMissingFile)
+		case errors.As(err, &tooLarge):
+			failure.EmitError(w, failure.ErrFileTooLarge.Clone().FormatDetails(service.MaxAttachmentSize))
+		}
+		return
+	}
+	defer part.Close()
+	insertedID, err := h.s.Save(userID, taskID, part.FileName(), part)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		failure.EmitError(w, failure.ErrFileTooLarge.Clone().FormatDetails(service.MaxAttachmentSize))
+		return
+	}
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": insertedID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *AttachmentHandler) HandleAttachmentsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	attachments, err := h.s.Fetch(userID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(attachments)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *AttachmentHandler) HandleAttachmentRetrievalByID(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var attachmentID = parseParameterToUUID(w, r, "attachment_uuid")
+	if didNotParse(attachmentID) {
+		return
+	}
+	attachment, err := h.s.FetchByID(userID, taskID, attachmentID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(attachment)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *AttachmentHandler) HandleAttachmentDownload(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var attachmentID = parseParameterToUUID(w, r, "attachment_uuid")
+	if didNotParse(attachmentID) {
+		return
+	}
+	attachment, content, err := h.s.Open(userID, taskID, attachmentID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	defer content.Close()
+	extendDeadlines(w)
+	var header = w.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": attachment.FileName,
+	}))
+	/* Uploaded files are never rendered by the browser as something else.  */
+	header.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, content)
+	if nil != err {
+		log.Println(err)
+	}
+}
+
+func (h *AttachmentHandler) HandleAttachmentDeletion(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var attachmentID = parseParameterToUUID(w, r, "attachment_uuid")
+	if didNotParse(attachmentID) {
+		return
+	}
+	err := h.s.Delete(userID, taskID, attachmentID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
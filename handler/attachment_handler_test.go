@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/failure"
+	"noda/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// multipartBody builds a multipart form with a single file in field.
+func multipartBody(t *testing.T, field, fileName, content string) (body *bytes.Buffer, contentType string) {
+	body = new(bytes.Buffer)
+	var writer = multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, fileName)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestAttachmentHandler_HandleAttachmentUpload(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/tasks/{task_uuid}/attachments"
+		serviceMethod = "Save"
+	)
+	var taskID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			insertedID           = uuid.New()
+			body, contentType    = multipartBody(t, "file", "notes.txt", "some notes")
+			expectedResponseBody = marshal(t, JSON{"inserted_id": insertedID.String()})
+			uploaded             string
+		)
+		var request = httptest.NewRequest(method, target, body)
+		request.Header.Set("Content-Type", contentType)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.On(serviceMethod, userID, taskID, "notes.txt", mock.Anything).
+			Run(func(args mock.Arguments) {
+				data, _ := io.ReadAll(args.Get(3).(io.Reader))
+				uploaded = string(data)
+			}).
+			Return(insertedID, nil)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentUpload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+		assert.Equal(t, "some notes", uploaded)
+	})
+
+	t.Run("body is not a multipart form", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, strings.NewReader("{}"))
+		request.Header.Set("Content-Type", "application/json")
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentUpload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, failure.ErrMissingFile.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), failure.ErrMissingFile.Message())
+	})
+
+	t.Run("missing \"file\" field", func(t *testing.T) {
+		var body, contentType = multipartBody(t, "document", "notes.txt", "some notes")
+		var request = httptest.NewRequest(method, target, body)
+		request.Header.Set("Content-Type", contentType)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentUpload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, failure.ErrMissingFile.Status(), response.StatusCode)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var (
+			expectedError     = failure.ErrTaskNotFound
+			body, contentType = multipartBody(t, "file", "notes.txt", "some notes")
+		)
+		var request = httptest.NewRequest(method, target, body)
+		request.Header.Set("Content-Type", contentType)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentUpload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestAttachmentHandler_HandleAttachmentsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/tasks/{task_uuid}/attachments"
+		serviceMethod = "Fetch"
+	)
+	var taskID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			attachments = []*model.Attachment{
+				{UUID: uuid.New(), TaskUUID: taskID, FileName: "a.txt", ContentType: "text/plain", Size: 1, CreatedAt: time.Now()},
+			}
+			expectedResponseBody = marshal(t, attachments)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.On(serviceMethod, userID, taskID).Return(attachments, nil)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+		assert.NotContains(t, string(responseBody), "blob_key")
+	})
+}
+
+func TestAttachmentHandler_HandleAttachmentDownload(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/tasks/{task_uuid}/attachments/{attachment_uuid}/content"
+		serviceMethod = "Open"
+	)
+	var taskID, attachmentID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var attachment = &model.Attachment{
+			UUID:        attachmentID,
+			FileName:    "informe año.pdf",
+			ContentType: "application/pdf",
+			Size:        7,
+		}
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "attachment_uuid": attachmentID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.On(serviceMethod, userID, taskID, attachmentID).
+			Return(attachment, io.NopCloser(strings.NewReader("%PDF-1.")), nil)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentDownload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "%PDF-1.", string(responseBody))
+		assert.Equal(t, "application/pdf", response.Header.Get("Content-Type"))
+		assert.Equal(t, "7", response.Header.Get("Content-Length"))
+		assert.Equal(t, "nosniff", response.Header.Get("X-Content-Type-Options"))
+		assert.Equal(t, "attachment; filename*=utf-8''informe%20a%C3%B1o.pdf", response.Header.Get("Content-Disposition"))
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrAttachmentNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "attachment_uuid": attachmentID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentDownload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+
+	t.Run("parsing \"attachment_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "attachment_uuid": "x"})
+		var m = mocks.NewAttachmentServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentDownload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestAttachmentHandler_HandleAttachmentDeletion(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/tasks/{task_uuid}/attachments/{attachment_uuid}"
+		serviceMethod = "Delete"
+	)
+	var taskID, attachmentID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "attachment_uuid": attachmentID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.On(serviceMethod, userID, taskID, attachmentID).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrAttachmentNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "attachment_uuid": attachmentID.String()})
+		var m = mocks.NewAttachmentServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
+		var recorder = httptest.NewRecorder()
+		NewAttachmentHandler(m).HandleAttachmentDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+	})
+}
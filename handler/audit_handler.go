@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/service"
+)
+
+type AuditHandler struct {
+	s service.AuditService
+}
+
+func NewAuditHandler(service service.AuditService) *AuditHandler {
+	return &AuditHandler{service}
+}
+
+func (h *AuditHandler) HandleAuditLogRetrieval(w http.ResponseWriter, r *http.Request) {
+	pagination := parsePagination(w, r)
+	if pagination == nil {
+		return
+	}
+	filter, ok := parseAuditFilter(w, r)
+	if !ok {
+		return
+	}
+	res, err := h.s.Fetch(pagination, filter)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(res)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+	"time"
+)
+
+func TestAuditHandler_HandleAuditLogRetrieval(t *testing.T) {
+	const (
+		method  = "GET"
+		target  = "/audit"
+		routine = "Fetch"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			actorID    = uuid.New()
+			from, _    = time.Parse(time.RFC3339, "2024-01-01T00:00:00Z")
+			pagination = types.Pagination{Page: 1, RPP: 10}
+			values     = url.Values{
+				"actor":  []string{actorID.String()},
+				"entity": []string{"user"},
+				"from":   []string{"2024-01-01T00:00:00Z"},
+			}
+			filter        = &types.AuditFilter{ActorUUID: actorID, Entity: "user", From: &from}
+			serviceResult = &types.Result[model.AuditEntry]{
+				Page:      pagination.Page,
+				RPP:       pagination.RPP,
+				Payload:   []*model.AuditEntry{{UUID: uuid.New(), ActorUUID: &actorID, Action: "blocked", Entity: "user"}},
+				Retrieved: 1,
+			}
+			expectedResponseBody = string(marshal(t, serviceResult))
+		)
+		var request = httptest.NewRequest(method, target+"?"+values.Encode(), nil)
+		withLoggedUser(&request)
+		var s = mocks.NewAuditServiceMock()
+		s.On(routine, &pagination, filter).Return(serviceResult, nil)
+		var recorder = httptest.NewRecorder()
+		NewAuditHandler(s).HandleAuditLogRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedResponseBody, string(responseBody))
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("bad filter", func(t *testing.T) {
+		for _, query := range []string{
+			"actor=x",
+			"entity_uuid=x",
+			"from=yesterday",
+			"to=2024-01-01",
+			"from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z",
+		} {
+			var request = httptest.NewRequest(method, target+"?"+query, nil)
+			withLoggedUser(&request)
+			var recorder = httptest.NewRecorder()
+			NewAuditHandler(mocks.NewAuditServiceMock()).HandleAuditLogRetrieval(recorder, request)
+			var response = recorder.Result()
+			response.Body.Close()
+			assert.Equal(t, failure.ErrBadQueryParameter.Status(), response.StatusCode, query)
+		}
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var s = mocks.NewAuditServiceMock()
+		s.On(routine, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewAuditHandler(s).HandleAuditLogRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"noda/data/transfer"
 	"noda/failure"
 	"noda/service"
+
+	"github.com/google/uuid"
 )
 
 type AuthenticationHandler struct {
@@ -90,3 +92,73 @@ func (h *AuthenticationHandler) HandleSignIn(w http.ResponseWriter, r *http.Requ
 	}
 	w.Write(data)
 }
+
+func (h *AuthenticationHandler) HandleTokenRefresh(w http.ResponseWriter, r *http.Request) {
+	var refresh = &transfer.TokenRefresh{}
+	var err = parseRequestBody(w, r, refresh)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = refresh.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	res, err := h.s.Refresh(refresh.RefreshToken)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(res)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Write(data)
+}
+
+func (h *AuthenticationHandler) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	var (
+		userID, _ = extractUserPayload(r)
+		sessionID = extractSessionID(r)
+	)
+	if uuid.Nil == sessionID {
+		/* Tokens issued before sessions existed cannot be revoked, they
+		   expire by themselves.  */
+		failure.EmitError(w, failure.ErrJSONWebToken.Clone().SetDetails("This token does not belong to any session."))
+		return
+	}
+	var err = h.s.Logout(userID, sessionID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthenticationHandler) HandleAccountRestoration(w http.ResponseWriter, r *http.Request) {
+	credentials := &transfer.UserCredentials{}
+	var err = parseRequestBody(w, r, credentials)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = h.s.RestoreAccount(credentials)
+	if err != nil {
+		var e *failure.Error
+		switch {
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		case errors.Is(err, failure.ErrUserNotFound):
+			failure.EmitError(w, failure.ErrUserNotFound.
+				Clone().
+				SetDetails("Could not find any user with the email %q.").
+				FormatDetails(credentials.Email).
+				SetHint("The account may have already been purged."))
+		case errors.As(err, &e):
+			failure.EmitError(w, e)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,13 +2,16 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
 	"noda/data/transfer"
 	"noda/data/types"
+	"noda/failure"
 	"noda/mocks"
 	"testing"
 	"time"
@@ -180,3 +183,175 @@ func TestAuthenticationHandler_HandleSignIn(t *testing.T) {
 		assert.Empty(t, string(responseBody), "No response body is expected.")
 	})
 }
+
+func TestAuthenticationHandler_HandleTokenRefresh(t *testing.T) {
+	const (
+		method  = "POST"
+		target  = "/token/refresh"
+		routine = "Refresh"
+	)
+	var refresh = &transfer.TokenRefresh{RefreshToken: "Nq3nQGa0yVt0cJxk1Vf2mXo5c7cS8oA6PzS3zqkFh1E"}
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			tokenPayload = &types.TokenPayload{
+				ID:               uuid.NewString(),
+				Token:            "token",
+				IssuedAt:         time.Now(),
+				RefreshToken:     "refresh",
+				RefreshExpiresAt: time.Now().Add(24 * time.Hour),
+			}
+			expectedStatusCode   = http.StatusOK
+			expectedResponseBody = marshal(t, tokenPayload)
+			requestBody          = marshal(t, refresh)
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		var s = mocks.NewAuthenticationServiceMock()
+		s.On(routine, refresh.RefreshToken).Return(tokenPayload, nil)
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleTokenRefresh(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+	})
+
+	t.Run("missing refresh token", func(t *testing.T) {
+		var (
+			expectedStatusCode = http.StatusBadRequest
+			requestBody        = []byte(`{}`)
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		var s = mocks.NewAuthenticationServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleTokenRefresh(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		s.AssertNotCalled(t, routine, mock.Anything)
+	})
+
+	t.Run("refresh token refused", func(t *testing.T) {
+		var (
+			expectedStatusCode     = http.StatusUnauthorized
+			expectedInResponseBody = failure.ErrInvalidRefreshToken.Details()
+			requestBody            = marshal(t, refresh)
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		var s = mocks.NewAuthenticationServiceMock()
+		s.On(routine, refresh.RefreshToken).Return(nil, failure.ErrInvalidRefreshToken)
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleTokenRefresh(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+	})
+}
+
+func TestAuthenticationHandler_HandleLogout(t *testing.T) {
+	const (
+		method  = "POST"
+		target  = "/me/logout"
+		routine = "Logout"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var s = mocks.NewAuthenticationServiceMock()
+		s.On(routine, userID, sessionID).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleLogout(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		assert.Empty(t, string(responseBody), "No response body is expected.")
+	})
+
+	t.Run("token without session", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		request = request.Clone(context.WithValue(request.Context(), types.ContextKey{}, types.JWTPayload{UserID: userID}))
+		var s = mocks.NewAuthenticationServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleLogout(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+		s.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("got service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var s = mocks.NewAuthenticationServiceMock()
+		s.On(routine, userID, sessionID).Return(errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleLogout(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
+
+func TestAuthenticationHandler_HandleAccountRestoration(t *testing.T) {
+	const (
+		method  = "POST"
+		target  = "/account/restore"
+		routine = "RestoreAccount"
+	)
+	var credentials = &transfer.UserCredentials{Email: "foo@bar.com", Password: "Xxxxxx*0"}
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, credentials)))
+		var s = mocks.NewAuthenticationServiceMock()
+		s.On(routine, credentials).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleAccountRestoration(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		assert.Empty(t, string(responseBody), "No response body is expected.")
+	})
+
+	t.Run("the account was not deleted", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, credentials)))
+		var s = mocks.NewAuthenticationServiceMock()
+		s.On(routine, credentials).Return(failure.ErrUserNotDeleted)
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleAccountRestoration(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, failure.ErrUserNotDeleted.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), failure.ErrUserNotDeleted.Details())
+	})
+
+	t.Run("the account was already purged", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, credentials)))
+		var s = mocks.NewAuthenticationServiceMock()
+		s.On(routine, credentials).Return(failure.ErrUserNotFound)
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleAccountRestoration(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+		assert.Contains(t, string(responseBody), credentials.Email)
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		var s = mocks.NewAuthenticationServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewAuthenticationHandler(s).HandleAccountRestoration(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		s.AssertNotCalled(t, routine, mock.Anything)
+	})
+}
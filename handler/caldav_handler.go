@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"noda/caldav"
+	"noda/data/model"
+	"noda/failure"
+	"noda/service"
+	"path"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// The paths of the CalDAV resources: the principal of the signed in user, its
+// calendar home, and under it a calendar per list.
+const (
+	calDAVPrincipalPath = "/dav/"
+	calDAVHomePath      = "/dav/calendars/"
+)
+
+// calendarContentType is the media type of the calendar objects.
+const calendarContentType = "text/calendar; charset=utf-8"
+
+type CalDAVHandler struct {
+	s service.CalDAVService
+}
+
+func NewCalDAVHandler(service service.CalDAVService) *CalDAVHandler {
+	return &CalDAVHandler{service}
+}
+
+// HandleOptions tells CalDAV clients what the server supports.
+func (h *CalDAVHandler) HandleOptions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+	w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleWellKnown sends the clients that look for the server, as RFC 6764
+// describes, to the principal.
+func (h *CalDAVHandler) HandleWellKnown(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Location", calDAVPrincipalPath)
+	w.WriteHeader(http.StatusMovedPermanently)
+}
+
+func (h *CalDAVHandler) HandlePrincipalPropfind(w http.ResponseWriter, r *http.Request) {
+	var props, _, ok = parsePropfind(w, r)
+	if !ok {
+		return
+	}
+	var response = caldav.NewResponse(calDAVPrincipalPath, props,
+		caldav.ElementProperty(caldav.ResourceType, caldav.Collection, caldav.Principal),
+		caldav.HrefProperty(caldav.CurrentUserPrincipal, calDAVPrincipalPath),
+		caldav.HrefProperty(caldav.PrincipalURL, calDAVPrincipalPath),
+		caldav.HrefProperty(caldav.CalendarHomeSet, calDAVHomePath))
+	writeMultistatus(w, response)
+}
+
+// HandleHomePropfind describes the calendar home and, unless the Depth is 0,
+// the calendars in it.
+func (h *CalDAVHandler) HandleHomePropfind(w http.ResponseWriter, r *http.Request) {
+	var props, depth, ok = parsePropfind(w, r)
+	if !ok {
+		return
+	}
+	var responses = []*caldav.Response{caldav.NewResponse(calDAVHomePath, props,
+		caldav.ElementProperty(caldav.ResourceType, caldav.Collection),
+		caldav.HrefProperty(caldav.CurrentUserPrincipal, calDAVPrincipalPath),
+		caldav.HrefProperty(caldav.Owner, calDAVPrincipalPath))}
+	if caldav.DepthZero != depth {
+		var userID, _ = extractUserPayload(r)
+		calendars, err := h.s.FetchCalendars(userID)
+		if gotAndHandledServiceError(w, err) {
+			return
+		}
+		for _, calendar := range calendars {
+			responses = append(responses, describeCalendar(calendar, props))
+		}
+	}
+	writeMultistatus(w, responses...)
+}
+
+// HandleCalendarPropfind describes a calendar and, unless the Depth is 0, the
+// objects in it.
+func (h *CalDAVHandler) HandleCalendarPropfind(w http.ResponseWriter, r *http.Request) {
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var props, depth, ok = parsePropfind(w, r)
+	if !ok {
+		return
+	}
+	var userID, _ = extractUserPayload(r)
+	calendar, err := h.s.FetchCalendar(userID, listID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var responses = []*caldav.Response{describeCalendar(calendar, props)}
+	if caldav.DepthZero != depth {
+		objects, err := h.s.FetchObjects(userID, listID)
+		if gotAndHandledServiceError(w, err) {
+			return
+		}
+		for _, object := range objects {
+			responses = append(responses, describeObject(listID, object, props))
+		}
+	}
+	writeMultistatus(w, responses...)
+}
+
+func (h *CalDAVHandler) HandleObjectPropfind(w http.ResponseWriter, r *http.Request) {
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var props, _, ok = parsePropfind(w, r)
+	if !ok {
+		return
+	}
+	var userID, _ = extractUserPayload(r)
+	object, err := h.s.FetchObject(userID, listID, r.PathValue("object"))
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	writeMultistatus(w, describeObject(listID, object, props))
+}
+
+// HandleReport answers the calendar-query and the calendar-multiget reports
+// on a calendar. A query for components other than VTODO finds nothing, and
+// the hrefs of a multiget that are not in the calendar are not found.
+func (h *CalDAVHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	report, err := caldav.ParseReport(r.Body)
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	var userID, _ = extractUserPayload(r)
+	var responses = make([]*caldav.Response, 0)
+	switch report.Kind {
+	case caldav.ReportCalendarQuery:
+		var components = report.Components
+		if (0 < len(components) && "VCALENDAR" != components[0]) || (1 < len(components) && "VTODO" != components[1]) {
+			break
+		}
+		objects, err := h.s.FetchObjects(userID, listID)
+		if gotAndHandledServiceError(w, err) {
+			return
+		}
+		for _, object := range objects {
+			responses = append(responses, describeObject(listID, object, &report.Props))
+		}
+	case caldav.ReportCalendarMultiget:
+		var calendarPath = calDAVHomePath + listID.String() + "/"
+		for _, href := range report.Hrefs {
+			var response = &caldav.Response{Href: href, Status: http.StatusNotFound}
+			target, err := url.Parse(href)
+			if nil == err && calendarPath == path.Dir(target.Path)+"/" {
+				object, err := h.s.FetchObject(userID, listID, path.Base(target.Path))
+				switch {
+				case nil == err:
+					response = describeObject(listID, object, &report.Props)
+				case !errors.Is(err, failure.ErrCalendarObjectNotFound):
+					gotAndHandledServiceError(w, err)
+					return
+				}
+			}
+			responses = append(responses, response)
+		}
+	}
+	writeMultistatus(w, responses...)
+}
+
+func (h *CalDAVHandler) HandleObjectRetrieval(w http.ResponseWriter, r *http.Request) {
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var userID, _ = extractUserPayload(r)
+	object, err := h.s.FetchObject(userID, listID, r.PathValue("object"))
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var header = w.Header()
+	header.Set("ETag", object.ETag)
+	header.Set("Content-Type", calendarContentType)
+	header.Set("Content-Length", strconv.Itoa(len(object.Data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(object.Data)
+}
+
+// HandleObjectPut makes or updates the task of a calendar object. The
+// If-Match and If-None-Match headers are honored, so that clients do not
+// overwrite changes they have not seen.
+func (h *CalDAVHandler) HandleObjectPut(w http.ResponseWriter, r *http.Request) {
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	data, err := io.ReadAll(r.Body)
+	if nil != err {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			failure.EmitError(w, failure.ErrFileTooLarge.Clone().FormatDetails(tooLarge.Limit))
+			return
+		}
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var userID, _ = extractUserPayload(r)
+	created, err := audited(h.s, r).PutObject(userID, listID, r.PathValue("object"),
+		r.Header.Get("If-Match"), r.Header.Get("If-None-Match"), data)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if created {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CalDAVHandler) HandleObjectDeletion(w http.ResponseWriter, r *http.Request) {
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var userID, _ = extractUserPayload(r)
+	err := audited(h.s, r).DeleteObject(userID, listID, r.PathValue("object"), r.Header.Get("If-Match"))
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parsePropfind reads the properties asked for by a PROPFIND and its Depth.
+// If ok is false, an error has already been emitted.
+func parsePropfind(w http.ResponseWriter, r *http.Request) (props *caldav.Props, depth caldav.Depth, ok bool) {
+	depth, err := caldav.ParseDepth(r.Header.Get("Depth"))
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return nil, depth, false
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+	props, err = caldav.ParsePropfind(r.Body)
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return nil, depth, false
+	}
+	return props, depth, true
+}
+
+func describeCalendar(calendar *model.Calendar, props *caldav.Props) *caldav.Response {
+	var list = calendar.List
+	return caldav.NewResponse(calDAVHomePath+list.UUID.String()+"/", props,
+		caldav.ElementProperty(caldav.ResourceType, caldav.Collection, caldav.Calendar),
+		caldav.TextProperty(caldav.DisplayName, list.Name),
+		caldav.TextProperty(caldav.CalendarDescription, list.Description),
+		caldav.Property{
+			Name: caldav.SupportedComponents,
+			Children: []caldav.Property{{
+				Name:  caldav.Comp,
+				Attrs: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: "VTODO"}},
+			}},
+		},
+		caldav.TextProperty(caldav.GetCTag, calendar.CTag),
+		caldav.HrefProperty(caldav.CurrentUserPrincipal, calDAVPrincipalPath),
+		caldav.HrefProperty(caldav.Owner, calDAVPrincipalPath))
+}
+
+func describeObject(listID uuid.UUID, object *model.CalendarObject, props *caldav.Props) *caldav.Response {
+	var href = calDAVHomePath + listID.String() + "/" + url.PathEscape(object.Name)
+	return caldav.NewResponse(href, props,
+		caldav.ElementProperty(caldav.ResourceType),
+		caldav.TextProperty(caldav.GetETag, object.ETag),
+		caldav.TextProperty(caldav.GetContentType, calendarContentType+"; component=VTODO"),
+		caldav.TextProperty(caldav.CalendarData, string(object.Data)))
+}
+
+func writeMultistatus(w http.ResponseWriter, responses ...*caldav.Response) {
+	var buf bytes.Buffer
+	err := (&caldav.Multistatus{Responses: responses}).Encode(&buf)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	w.Write(buf.Bytes())
+}
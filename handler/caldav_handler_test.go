@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/failure"
+	"noda/mocks"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCalDAVHandler_HandleCalendarPropfind(t *testing.T) {
+	const (
+		method = "PROPFIND"
+		target = "/dav/calendars/{list_uuid}/"
+		body   = `<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/"><d:prop><d:displayname/><cs:getctag/><d:getetag/></d:prop></d:propfind>`
+	)
+	var (
+		list     = &model.List{UUID: uuid.New(), Name: "Chores"}
+		calendar = &model.Calendar{List: list, CTag: `"1-1733134500000000"`}
+		object   = &model.CalendarObject{Task: &model.Task{UUID: uuid.New()}, Name: "0C5D1F8E.ics", ETag: `"1733134500000000"`}
+	)
+
+	t.Run("depth 1", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, strings.NewReader(body))
+		request.Header.Set("Depth", "1")
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": list.UUID.String()})
+		var m = mocks.NewCalDAVServiceMock()
+		m.On("FetchCalendar", userID, list.UUID).Return(calendar, nil)
+		m.On("FetchObjects", userID, list.UUID).Return([]*model.CalendarObject{object}, nil)
+		var recorder = httptest.NewRecorder()
+		NewCalDAVHandler(m).HandleCalendarPropfind(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusMultiStatus, response.StatusCode)
+		assert.Equal(t, "application/xml; charset=utf-8", response.Header.Get("Content-Type"))
+		assert.Contains(t, responseBody, "<d:href>/dav/calendars/"+list.UUID.String()+"/</d:href>")
+		assert.Contains(t, responseBody, "<d:displayname>Chores</d:displayname>")
+		assert.Contains(t, responseBody, "<cs:getctag>&#34;1-1733134500000000&#34;</cs:getctag>")
+		assert.Contains(t, responseBody, "<d:href>/dav/calendars/"+list.UUID.String()+"/0C5D1F8E.ics</d:href>")
+		assert.Contains(t, responseBody, "<d:getetag>&#34;1733134500000000&#34;</d:getetag>")
+	})
+
+	t.Run("depth 0", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, strings.NewReader(body))
+		request.Header.Set("Depth", "0")
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": list.UUID.String()})
+		var m = mocks.NewCalDAVServiceMock()
+		m.On("FetchCalendar", userID, list.UUID).Return(calendar, nil)
+		var recorder = httptest.NewRecorder()
+		NewCalDAVHandler(m).HandleCalendarPropfind(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusMultiStatus, response.StatusCode)
+		m.AssertNotCalled(t, "FetchObjects", mock.Anything, mock.Anything)
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, strings.NewReader("<d:propfind"))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": list.UUID.String()})
+		var m = mocks.NewCalDAVServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewCalDAVHandler(m).HandleCalendarPropfind(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestCalDAVHandler_HandleReport(t *testing.T) {
+	const method = "REPORT"
+	var (
+		listID = uuid.New()
+		target = "/dav/calendars/" + listID.String() + "/"
+		object = &model.CalendarObject{
+			Task: &model.Task{UUID: uuid.New()},
+			Name: "0C5D1F8E.ics",
+			ETag: `"1733134500000000"`,
+			Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"),
+		}
+	)
+
+	t.Run("calendar-multiget", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, strings.NewReader(`<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+			<d:prop><d:getetag/><c:calendar-data/></d:prop>
+			<d:href>`+target+`0C5D1F8E.ics</d:href>
+			<d:href>`+target+`gone.ics</d:href>
+		</c:calendar-multiget>`))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewCalDAVServiceMock()
+		m.On("FetchObject", userID, listID, "0C5D1F8E.ics").Return(object, nil)
+		m.On("FetchObject", userID, listID, "gone.ics").Return(nil, failure.ErrCalendarObjectNotFound)
+		var recorder = httptest.NewRecorder()
+		NewCalDAVHandler(m).HandleReport(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusMultiStatus, response.StatusCode)
+		m.AssertNotCalled(t, "FetchObjects", mock.Anything, mock.Anything)
+		assert.Contains(t, responseBody, "<c:calendar-data>BEGIN:VCALENDAR&#xD;&#xA;END:VCALENDAR&#xD;&#xA;</c:calendar-data>")
+		assert.Contains(t, responseBody, "<d:href>"+target+"gone.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status>")
+	})
+
+	t.Run("calendar-query for events", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, strings.NewReader(`<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+			<d:prop><d:getetag/></d:prop>
+			<c:filter><c:comp-filter name="VCALENDAR"><c:comp-filter name="VEVENT"/></c:comp-filter></c:filter>
+		</c:calendar-query>`))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewCalDAVServiceMock()
+		m.On("FetchObjects", userID, listID).Return([]*model.CalendarObject{object}, nil)
+		var recorder = httptest.NewRecorder()
+		NewCalDAVHandler(m).HandleReport(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusMultiStatus, response.StatusCode)
+		assert.NotContains(t, responseBody, "<d:response>")
+	})
+}
+
+func TestCalDAVHandler_HandleObjectPut(t *testing.T) {
+	const method = "PUT"
+	var (
+		listID = uuid.New()
+		target = "/dav/calendars/" + listID.String() + "/0C5D1F8E.ics"
+		data   = "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:0C5D1F8E\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+	)
+
+	for _, tc := range []struct {
+		name    string
+		created bool
+		err     error
+		status  int
+	}{
+		{"created", true, nil, http.StatusCreated},
+		{"updated", false, nil, http.StatusNoContent},
+		{"stale", false, failure.ErrPreconditionFailed, http.StatusPreconditionFailed},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var request = httptest.NewRequest(method, target, strings.NewReader(data))
+			request.Header.Set("If-Match", `"1733134500000000"`)
+			withLoggedUser(&request)
+			withPathParameters(&request, parameters{"list_uuid": listID.String(), "object": "0C5D1F8E.ics"})
+			var m = mocks.NewCalDAVServiceMock()
+			m.On("PutObject", userID, listID, "0C5D1F8E.ics", `"1733134500000000"`, "", []byte(data)).Return(tc.created, tc.err)
+			var recorder = httptest.NewRecorder()
+			NewCalDAVHandler(m).HandleObjectPut(recorder, request)
+			var response = recorder.Result()
+			defer response.Body.Close()
+			assert.Equal(t, tc.status, response.StatusCode)
+		})
+	}
+}
+
+func TestCalDAVHandler_HandleObjectRetrieval(t *testing.T) {
+	var (
+		listID = uuid.New()
+		object = &model.CalendarObject{
+			Task: &model.Task{UUID: uuid.New()},
+			Name: "0C5D1F8E.ics",
+			ETag: `"1733134500000000"`,
+			Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"),
+		}
+	)
+	var request = httptest.NewRequest("GET", "/dav/calendars/"+listID.String()+"/0C5D1F8E.ics", nil)
+	withLoggedUser(&request)
+	withPathParameters(&request, parameters{"list_uuid": listID.String(), "object": "0C5D1F8E.ics"})
+	var m = mocks.NewCalDAVServiceMock()
+	m.On("FetchObject", userID, listID, "0C5D1F8E.ics").Return(object, nil)
+	var recorder = httptest.NewRecorder()
+	NewCalDAVHandler(m).HandleObjectRetrieval(recorder, request)
+	var response = recorder.Result()
+	defer response.Body.Close()
+	var responseBody = extractResponseBody(t, response.Body)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, object.ETag, response.Header.Get("ETag"))
+	assert.Equal(t, "text/calendar; charset=utf-8", response.Header.Get("Content-Type"))
+	assert.Equal(t, object.Data, responseBody)
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/service"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type CalendarHandler struct {
+	s service.CalendarService
+}
+
+func NewCalendarHandler(service service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service}
+}
+
+func (h *CalendarHandler) HandleFeedTokenIssue(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	token, err := h.s.IssueFeedToken(userID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(map[string]string{"token": token})
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *CalendarHandler) HandleFeedTokenRevocation(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	err := h.s.RevokeFeedToken(userID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUserFeed serves the calendar feed of every list of the owner of the
+// token given in the "token" query parameter.
+func (h *CalendarHandler) HandleUserFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, uuid.Nil)
+}
+
+// HandleListFeed serves the calendar feed of a list of the owner of the token
+// given in the "token" query parameter.
+func (h *CalendarHandler) HandleListFeed(w http.ResponseWriter, r *http.Request) {
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	h.serveFeed(w, r, listID)
+}
+
+// serveFeed writes the feed with an ETag, and only the ETag if the calendar
+// already has this version of the feed.
+func (h *CalendarHandler) serveFeed(w http.ResponseWriter, r *http.Request, listID uuid.UUID) {
+	feed, err := h.s.RenderFeed(r.URL.Query().Get("token"), listID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var sum = sha256.Sum256(feed)
+	var etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	var header = w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "private, no-cache")
+	if matchesETag(r.Header.Get("If-None-Match"), etag) {
+		header.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", "text/calendar; charset=utf-8")
+	header.Set("Content-Length", strconv.Itoa(len(feed)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(feed)
+}
+
+// matchesETag tells whether an If-None-Match header matches the ETag, weakly
+// as RFC 9110 requires.
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if "*" == candidate || etag == candidate {
+			return true
+		}
+	}
+	return false
+}
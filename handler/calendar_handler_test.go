@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarHandler_HandleFeedTokenIssue(t *testing.T) {
+	var request = httptest.NewRequest("POST", "/me/calendar/token", nil)
+	withLoggedUser(&request)
+	var m = mocks.NewCalendarServiceMock()
+	m.On("IssueFeedToken", userID).Return("q9Vb0WnA1yZ3lH8x", nil)
+	var recorder = httptest.NewRecorder()
+	NewCalendarHandler(m).HandleFeedTokenIssue(recorder, request)
+	var response = recorder.Result()
+	defer response.Body.Close()
+	var responseBody = extractResponseBody(t, response.Body)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, string(marshal(t, JSON{"token": "q9Vb0WnA1yZ3lH8x"})), string(responseBody))
+}
+
+func TestCalendarHandler_HandleListFeed(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/calendar.ics?token=q9Vb0WnA1yZ3lH8x"
+		serviceMethod = "RenderFeed"
+	)
+	var (
+		listID = uuid.New()
+		feed   = []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n")
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewCalendarServiceMock()
+		m.On(serviceMethod, "q9Vb0WnA1yZ3lH8x", listID).Return(feed, nil)
+		var recorder = httptest.NewRecorder()
+		NewCalendarHandler(m).HandleListFeed(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "text/calendar; charset=utf-8", response.Header.Get("Content-Type"))
+		assert.NotEmpty(t, response.Header.Get("ETag"))
+		assert.Equal(t, string(feed), string(responseBody))
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		var m = mocks.NewCalendarServiceMock()
+		m.On(serviceMethod, "q9Vb0WnA1yZ3lH8x", listID).Return(feed, nil)
+		var first = httptest.NewRequest(method, target, nil)
+		withPathParameters(&first, parameters{"list_uuid": listID.String()})
+		var recorder = httptest.NewRecorder()
+		NewCalendarHandler(m).HandleListFeed(recorder, first)
+		var etag = recorder.Result().Header.Get("ETag")
+
+		for _, ifNoneMatch := range []string{etag, `"other", W/` + etag, "*"} {
+			var request = httptest.NewRequest(method, target, nil)
+			request.Header.Set("If-None-Match", ifNoneMatch)
+			withPathParameters(&request, parameters{"list_uuid": listID.String()})
+			recorder = httptest.NewRecorder()
+			NewCalendarHandler(m).HandleListFeed(recorder, request)
+			var response = recorder.Result()
+			var responseBody = extractResponseBody(t, response.Body)
+			response.Body.Close()
+			assert.Equal(t, http.StatusNotModified, response.StatusCode, "If-None-Match: %s", ifNoneMatch)
+			assert.Equal(t, etag, response.Header.Get("ETag"))
+			assert.Empty(t, responseBody)
+		}
+	})
+
+	t.Run("feed changed", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		request.Header.Set("If-None-Match", `"0123456789abcdef0123456789abcdef"`)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewCalendarServiceMock()
+		m.On(serviceMethod, "q9Vb0WnA1yZ3lH8x", listID).Return(feed, nil)
+		var recorder = httptest.NewRecorder()
+		NewCalendarHandler(m).HandleListFeed(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewCalendarServiceMock()
+		m.On(serviceMethod, "q9Vb0WnA1yZ3lH8x", listID).Return(nil, failure.ErrInvalidFeedToken)
+		var recorder = httptest.NewRecorder()
+		NewCalendarHandler(m).HandleListFeed(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	})
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/service"
+)
+
+type CommentHandler struct {
+	s service.CommentService
+}
+
+func NewCommentHandler(service service.CommentService) *CommentHandler {
+	return &CommentHandler{s: service}
+}
+
+func (h *CommentHandler) HandleCommentCreation(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var creation = new(transfer.CommentCreation)
+	var err = parseRequestBody(w, r, creation)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = creation.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	insertedID, err := h.s.Save(userID, listID, taskID, creation)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": insertedID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *CommentHandler) HandleCommentsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	result, err := h.s.Fetch(userID, listID, taskID, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *CommentHandler) HandlePartialUpdateOfComment(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var commentID = parseParameterToUUID(w, r, "comment_uuid")
+	if didNotParse(commentID) {
+		return
+	}
+	var update = new(transfer.CommentUpdate)
+	var err = parseRequestBody(w, r, update)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = update.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	ok, err := h.s.Update(userID, listID, taskID, commentID, update)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, "/me/lists/"+listID.String()+"/tasks/"+taskID.String()+"/comments")
+}
+
+func (h *CommentHandler) HandleCommentDeletion(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var commentID = parseParameterToUUID(w, r, "comment_uuid")
+	if didNotParse(commentID) {
+		return
+	}
+	var err = h.s.Delete(userID, listID, taskID, commentID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CommentHandler) HandleTaskActivityRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	result, err := h.s.FetchActivity(userID, listID, taskID, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
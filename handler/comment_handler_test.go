@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCommentHandler_HandleCommentCreation(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/comments"
+		serviceMethod = "Save"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			parentID    = uuid.New()
+			creation    = &transfer.CommentCreation{Content: "Agreed, @jane@noda.com.", ParentUUID: &parentID}
+			requestBody = marshal(t, creation)
+			commentID   = uuid.New()
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, creation).Return(commentID, nil)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandleCommentCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, `{"inserted_id":"`+commentID.String()+`"}`, string(responseBody))
+	})
+
+	t.Run("missing content", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, &transfer.CommentCreation{})))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewCommentServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandleCommentCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrCommentNotFound
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, &transfer.CommentCreation{Content: "Agreed."})))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandleCommentCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestCommentHandler_HandleCommentsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/comments?page=2&rpp=5"
+		serviceMethod = "Fetch"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination           = types.Pagination{Page: 2, RPP: 5}
+			comments             = []*model.Comment{{UUID: uuid.New(), TaskUUID: taskID, AuthorUUID: userID, Content: "Agreed."}}
+			result               = &types.Result[model.Comment]{Page: 2, RPP: 5, Retrieved: 1, Payload: comments}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, &pagination).Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandleCommentsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+}
+
+func TestCommentHandler_HandlePartialUpdateOfComment(t *testing.T) {
+	const (
+		method        = "PATCH"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/comments/{comment_uuid}"
+		serviceMethod = "Update"
+	)
+	var (
+		listID, taskID, commentID = uuid.New(), uuid.New(), uuid.New()
+		update                    = &transfer.CommentUpdate{Content: "Agreed!"}
+		pathParameters            = parameters{"list_uuid": listID.String(), "task_uuid": taskID.String(), "comment_uuid": commentID.String()}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, update)))
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, commentID, update).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandlePartialUpdateOfComment(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("nothing changed? take me to the comments", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, update)))
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, commentID, update).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandlePartialUpdateOfComment(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/lists/"+listID.String()+"/tasks/"+taskID.String()+"/comments")
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		var expectedError = failure.ErrNotAuthor
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, update)))
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandlePartialUpdateOfComment(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestCommentHandler_HandleCommentDeletion(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/comments/{comment_uuid}"
+		serviceMethod = "Delete"
+	)
+	var listID, taskID, commentID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String(), "comment_uuid": commentID.String()})
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, commentID).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandleCommentDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("could not parse comment UUID", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String(), "comment_uuid": "x"})
+		var m = mocks.NewCommentServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandleCommentDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestCommentHandler_HandleTaskActivityRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/activity"
+		serviceMethod = "FetchActivity"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination           = types.Pagination{Page: 1, RPP: 10}
+			activity             = []*model.Activity{{Kind: types.ActivityKindCompleted, TaskUUID: taskID, ActorUUID: userID}}
+			result               = &types.Result[model.Activity]{Page: 1, RPP: 10, Retrieved: 1, Payload: activity}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, &pagination).Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandleTaskActivityRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewCommentServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewCommentHandler(m).HandleTaskActivityRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/service"
+)
+
+type DependencyHandler struct {
+	s service.DependencyService
+}
+
+func NewDependencyHandler(service service.DependencyService) *DependencyHandler {
+	return &DependencyHandler{s: service}
+}
+
+func (h *DependencyHandler) HandleDependencyGraphRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	graph, err := h.s.FetchGraph(userID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(graph)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *DependencyHandler) HandleDependencyCreation(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var blockerID = parseParameterToUUID(w, r, "blocker_uuid")
+	if didNotParse(blockerID) {
+		return
+	}
+	ok, err := h.s.Save(userID, taskID, blockerID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, "/me/tasks/"+taskID.String()+"/dependencies")
+}
+
+func (h *DependencyHandler) HandleDependencyRemoval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var blockerID = parseParameterToUUID(w, r, "blocker_uuid")
+	if didNotParse(blockerID) {
+		return
+	}
+	ok, err := h.s.Remove(userID, taskID, blockerID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, "/me/tasks/"+taskID.String()+"/dependencies")
+}
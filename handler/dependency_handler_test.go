@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyHandler_HandleDependencyGraphRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/tasks/{task_uuid}/dependencies"
+		serviceMethod = "FetchGraph"
+	)
+	var taskID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			graph = &model.DependencyGraph{
+				TaskUUID: taskID,
+				Nodes:    []*model.DependencyNode{{TaskUUID: taskID, Title: "Build", BlockedBy: []uuid.UUID{}}},
+				Order:    []uuid.UUID{taskID},
+				Ready:    []uuid.UUID{taskID},
+			}
+			expectedResponseBody = marshal(t, graph)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewDependencyServiceMock()
+		m.On(serviceMethod, userID, taskID).Return(graph, nil)
+		var recorder = httptest.NewRecorder()
+		NewDependencyHandler(m).HandleDependencyGraphRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+}
+
+func TestDependencyHandler_HandleDependencyCreation(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/tasks/{task_uuid}/dependencies/{blocker_uuid}"
+		serviceMethod = "Save"
+	)
+	var (
+		taskID, blockerID = uuid.New(), uuid.New()
+		pathParameters    = parameters{"task_uuid": taskID.String(), "blocker_uuid": blockerID.String()}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewDependencyServiceMock()
+		m.On(serviceMethod, userID, taskID, blockerID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewDependencyHandler(m).HandleDependencyCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("already blocked? take me to the dependencies", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewDependencyServiceMock()
+		m.On(serviceMethod, userID, taskID, blockerID).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewDependencyHandler(m).HandleDependencyCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/tasks/"+taskID.String()+"/dependencies")
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrDependencyCycle
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewDependencyServiceMock()
+		m.On(serviceMethod, userID, taskID, blockerID).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewDependencyHandler(m).HandleDependencyCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestDependencyHandler_HandleDependencyRemoval(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/tasks/{task_uuid}/dependencies/{blocker_uuid}"
+		serviceMethod = "Remove"
+	)
+	var taskID, blockerID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "blocker_uuid": blockerID.String()})
+		var m = mocks.NewDependencyServiceMock()
+		m.On(serviceMethod, userID, taskID, blockerID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewDependencyHandler(m).HandleDependencyRemoval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+}
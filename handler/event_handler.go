@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/events"
+	"noda/failure"
+	"noda/service"
+	"noda/websocket"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// eventHeartbeat is how often a stream is written to when nothing happens,
+	// so that the proxies in between keep it open and a client that left is
+	// noticed.
+	eventHeartbeat = 15 * time.Second
+	// eventWriteTimeout is how long a client may take to receive a write of
+	// its stream before it is dropped.
+	eventWriteTimeout = 10 * time.Second
+)
+
+type EventHandler struct {
+	s         service.EventService
+	heartbeat time.Duration
+}
+
+func NewEventHandler(service service.EventService) *EventHandler {
+	return &EventHandler{s: service, heartbeat: eventHeartbeat}
+}
+
+// HandleEventStream streams the events the user can see, over WebSocket when
+// the request asks to switch to it, and as Server-Sent Events otherwise. The
+// stream resumes after the event given in the "Last-Event-ID" header, which
+// browsers send when they reconnect to Server-Sent Events, or else in the
+// "last_event_id" query parameter.
+//
+// The stream ends when the access token it was opened with expires, and, as
+// it is checked on every heartbeat, when its session is logged out or its
+// user is blocked or deleted.
+func (h *EventHandler) HandleEventStream(w http.ResponseWriter, r *http.Request) {
+	var payload = r.Context().Value(types.ContextKey{}).(types.JWTPayload)
+	lastEventID, ok := parseLastEventID(w, r)
+	if !ok {
+		return
+	}
+	subscription, missed, err := h.s.Subscribe(payload.UserID, lastEventID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	defer h.s.Unsubscribe(subscription)
+	var stream = &eventStream{subscription: subscription, missed: missed, payload: payload}
+	if !payload.ExpiresAt.IsZero() {
+		var expiry = time.NewTimer(time.Until(payload.ExpiresAt))
+		defer expiry.Stop()
+		stream.expired = expiry.C
+	}
+	if websocket.IsUpgrade(r) {
+		h.streamOverWebSocket(w, r, stream)
+	} else {
+		h.streamServerSentEvents(w, r, stream)
+	}
+}
+
+// eventStream is what a stream hands, and to whom. expired is nil if the
+// access token of the stream does not expire.
+type eventStream struct {
+	subscription *events.Subscription
+	missed       []*model.Event
+	payload      types.JWTPayload
+	expired      <-chan time.Time
+}
+
+// holds tells whether the session of the stream still holds. A failure to
+// tell is only logged, so that the streams outlive an outage of the database.
+func (h *EventHandler) holds(stream *eventStream) bool {
+	err := h.s.Recheck(stream.payload.UserID, stream.payload.SessionID)
+	if nil == err {
+		return true
+	}
+	var e *failure.Error
+	if !errors.As(err, &e) {
+		log.Println(err)
+		return true
+	}
+	return false
+}
+
+// parseLastEventID parses the "Last-Event-ID" header, or else the
+// "last_event_id" query parameter. If ok is false, an error has already been
+// emitted.
+func parseLastEventID(w http.ResponseWriter, r *http.Request) (lastEventID *uint64, ok bool) {
+	var value, e = strings.TrimSpace(r.Header.Get("Last-Event-ID")), failure.ErrBadRequest.Clone()
+	if "" == value {
+		value, e = extractQueryParameter(r, "last_event_id", ""), failure.ErrBadQueryParameter.Clone()
+	}
+	if "" == value {
+		return nil, true
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if nil != err {
+		failure.EmitError(w, e.SetDetails(fmt.Sprintf("%q is not the ID of an event.", value)))
+		return nil, false
+	}
+	return &id, true
+}
+
+func (h *EventHandler) streamServerSentEvents(w http.ResponseWriter, r *http.Request, stream *eventStream) {
+	var controller = http.NewResponseController(w)
+	if err := controller.SetReadDeadline(time.Time{}); nil != err && !errors.Is(err, http.ErrNotSupported) {
+		log.Println(err)
+	}
+	var header = w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	var send = func(format string, a ...any) bool {
+		if err := controller.SetWriteDeadline(time.Now().Add(eventWriteTimeout)); nil != err && !errors.Is(err, http.ErrNotSupported) {
+			log.Println(err)
+		}
+		_, err := fmt.Fprintf(w, format, a...)
+		if nil == err {
+			err = controller.Flush()
+		}
+		return nil == err
+	}
+	var sendEvent = func(event *model.Event) bool {
+		data, err := json.Marshal(event)
+		if nil != err {
+			log.Println(err)
+			return false
+		}
+		return send("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Kind, data)
+	}
+	for _, event := range stream.missed {
+		if !sendEvent(event) {
+			return
+		}
+	}
+	if !send(": connected\n\n") {
+		return
+	}
+	var ticker = time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-stream.expired:
+			return
+		case event, ok := <-stream.subscription.Events():
+			/* A client that lags reconnects from the last event it got.  */
+			if !ok || !sendEvent(event) {
+				return
+			}
+		case <-ticker.C:
+			if !h.holds(stream) || !send(": heartbeat\n\n") {
+				return
+			}
+		}
+	}
+}
+
+func (h *EventHandler) streamOverWebSocket(w http.ResponseWriter, r *http.Request, stream *eventStream) {
+	conn, err := websocket.Upgrade(w, r)
+	if nil != err {
+		if errors.Is(err, websocket.ErrBadHandshake) {
+			w.Header().Set("Sec-WebSocket-Version", "13")
+			failure.EmitError(w, failure.ErrBadWebSocketHandshake.Clone().FormatDetails(err.Error()))
+		} else {
+			log.Println(err)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	conn.ReadTimeout = 2 * h.heartbeat
+	conn.WriteTimeout = eventWriteTimeout
+	/* The messages of the client are not expected; reading them answers its
+	   pings and notices when it leaves.  */
+	var left = make(chan struct{})
+	go func() {
+		defer close(left)
+		for {
+			if _, err := conn.ReadMessage(); nil != err {
+				return
+			}
+		}
+	}()
+	var sendEvent = func(event *model.Event) bool {
+		data, err := json.Marshal(event)
+		if nil == err {
+			err = conn.WriteText(data)
+		}
+		return nil == err
+	}
+	defer conn.Close(websocket.CloseNormal, "")
+	for _, event := range stream.missed {
+		if !sendEvent(event) {
+			return
+		}
+	}
+	var ticker = time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-left:
+			return
+		case <-stream.expired:
+			conn.Close(websocket.ClosePolicyViolation, "token expired")
+			return
+		case event, ok := <-stream.subscription.Events():
+			if !ok {
+				if stream.subscription.Lagged() {
+					conn.Close(websocket.CloseTryAgainLater, "lagged")
+				} else {
+					conn.Close(websocket.CloseGoingAway, "")
+				}
+				return
+			}
+			if !sendEvent(event) {
+				return
+			}
+		case <-ticker.C:
+			if !h.holds(stream) {
+				conn.Close(websocket.ClosePolicyViolation, "session revoked")
+				return
+			}
+			if err := conn.Ping(); nil != err {
+				return
+			}
+		}
+	}
+}
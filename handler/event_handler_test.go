@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/events"
+	"noda/failure"
+	"noda/mocks"
+	"noda/service"
+	"noda/websocket"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionDenylist denies the sessions once told to.
+type sessionDenylist struct {
+	denied atomic.Bool
+}
+
+func (d *sessionDenylist) Add(string, time.Time) error {
+	d.denied.Store(true)
+	return nil
+}
+
+func (d *sessionDenylist) Contains(string) (bool, error) {
+	return d.denied.Load(), nil
+}
+
+// newEventServer serves the event stream of the logged user, with a heartbeat
+// every 10 ms, from a broker whose events are all seen by their actor only.
+// Whether the user is active is told by active, and the access token expires
+// at expiresAt unless it is zero.
+func newEventServer(t *testing.T, active error, expiresAt time.Time) (*httptest.Server, *events.Broker, *sessionDenylist) {
+	t.Helper()
+	var broker = events.NewBroker(func(*model.Event) ([]uuid.UUID, error) { return nil, nil }, 10, 10)
+	var ctx, cancel = context.WithCancel(context.Background())
+	go broker.Run(ctx)
+	var (
+		denylist = new(sessionDenylist)
+		users    = mocks.NewUserServiceMock()
+	)
+	users.On("AssertActive", userID).Return(types.RoleUser, active)
+	var h = NewEventHandler(service.NewEventService(broker, denylist, users))
+	h.heartbeat = 10 * time.Millisecond
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		withLoggedUser(&r)
+		var payload = r.Context().Value(types.ContextKey{}).(types.JWTPayload)
+		payload.ExpiresAt = expiresAt
+		r = r.WithContext(context.WithValue(r.Context(), types.ContextKey{}, payload))
+		h.HandleEventStream(w, r)
+	}))
+	t.Cleanup(func() {
+		server.CloseClientConnections()
+		server.Close()
+		cancel()
+	})
+	return server, broker, denylist
+}
+
+// readUntil reads the lines of an event stream up to the one that has the
+// given prefix, which is returned.
+func readUntil(t *testing.T, reader *bufio.Reader, prefix string) string {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSuffix(line, "\n")
+		}
+	}
+}
+
+func TestEventHandler_HandleEventStream(t *testing.T) {
+	const method, target = "GET", "/me/events"
+	var taskID = uuid.New()
+
+	t.Run("server-sent events", func(t *testing.T) {
+		server, broker, _ := newEventServer(t, nil, time.Time{})
+		response, err := http.Get(server.URL + target)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+		var reader = bufio.NewReader(response.Body)
+		readUntil(t, reader, ": connected")
+		broker.Publish(&model.Event{Kind: types.EventKindTaskCompleted, ActorUUID: userID, TaskUUID: &taskID})
+		var id = readUntil(t, reader, "id: ")
+		assert.Equal(t, "event: task.completed", readUntil(t, reader, "event: "))
+		assert.Contains(t, readUntil(t, reader, "data: "), `"task_uuid":"`+taskID.String()+`"`)
+		assert.Equal(t, ": heartbeat", readUntil(t, reader, ": heartbeat"))
+
+		var request, _ = http.NewRequest(method, server.URL+target, nil)
+		request.Header.Set("Last-Event-ID", strings.TrimPrefix(id, "id: "))
+		broker.Publish(&model.Event{Kind: types.EventKindTaskRemoved, ActorUUID: userID, TaskUUID: &taskID})
+		readUntil(t, reader, "event: task.removed")
+		resumed, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer resumed.Body.Close()
+		assert.Equal(t, "event: task.removed", readUntil(t, bufio.NewReader(resumed.Body), "event: "))
+	})
+
+	t.Run("events were missed", func(t *testing.T) {
+		server, _, _ := newEventServer(t, nil, time.Time{})
+		response, err := http.Get(server.URL + target + "?last_event_id=1")
+		require.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, "event: reset", readUntil(t, bufio.NewReader(response.Body), "event: "))
+	})
+
+	t.Run("bad last event ID", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target+"?last_event_id=last", nil)
+		withLoggedUser(&request)
+		var recorder = httptest.NewRecorder()
+		NewEventHandler(nil).HandleEventStream(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, responseBody, `\"last\" is not the ID of an event.`)
+	})
+
+	t.Run("websocket", func(t *testing.T) {
+		server, broker, _ := newEventServer(t, nil, time.Time{})
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = io.WriteString(conn, "GET "+target+" HTTP/1.1\r\n"+
+			"Host: "+server.Listener.Addr().String()+"\r\n"+
+			"Connection: Upgrade\r\n"+
+			"Upgrade: websocket\r\n"+
+			"Sec-WebSocket-Version: 13\r\n"+
+			"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+		require.NoError(t, err)
+		var reader = bufio.NewReader(conn)
+		response, err := http.ReadResponse(reader, nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+		broker.Publish(&model.Event{Kind: types.EventKindTaskCreated, ActorUUID: userID, TaskUUID: &taskID})
+		for {
+			var header [2]byte
+			_, err = io.ReadFull(reader, header[:])
+			require.NoError(t, err)
+			var payload = make([]byte, header[1]&0x7F)
+			if 126 == len(payload) {
+				var extended [2]byte
+				_, err = io.ReadFull(reader, extended[:])
+				require.NoError(t, err)
+				payload = make([]byte, binary.BigEndian.Uint16(extended[:]))
+			}
+			_, err = io.ReadFull(reader, payload)
+			require.NoError(t, err)
+			if 0x81 == header[0] {
+				assert.Contains(t, string(payload), `"kind":"task.created"`)
+				return
+			}
+		}
+	})
+
+	t.Run("ends when the token expires", func(t *testing.T) {
+		server, _, _ := newEventServer(t, nil, time.Now().Add(50*time.Millisecond))
+		response, err := http.Get(server.URL + target)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		var done = make(chan error, 1)
+		go func() {
+			_, err := io.ReadAll(response.Body)
+			done <- err
+		}()
+		select {
+		case err = <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			assert.Fail(t, "the stream outlived its token")
+		}
+	})
+
+	t.Run("ends when the session is logged out", func(t *testing.T) {
+		server, _, denylist := newEventServer(t, nil, time.Time{})
+		response, err := http.Get(server.URL + target)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		var reader = bufio.NewReader(response.Body)
+		readUntil(t, reader, ": heartbeat")
+		require.NoError(t, denylist.Add(sessionID.String(), time.Now().Add(time.Hour)))
+		var done = make(chan error, 1)
+		go func() {
+			_, err := io.ReadAll(reader)
+			done <- err
+		}()
+		select {
+		case err = <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			assert.Fail(t, "the stream outlived its session")
+		}
+	})
+
+	t.Run("ends when the user is blocked", func(t *testing.T) {
+		server, _, _ := newEventServer(t, failure.ErrUserBlocked.Clone(), time.Time{})
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = io.WriteString(conn, "GET "+target+" HTTP/1.1\r\n"+
+			"Host: "+server.Listener.Addr().String()+"\r\n"+
+			"Connection: Upgrade\r\n"+
+			"Upgrade: websocket\r\n"+
+			"Sec-WebSocket-Version: 13\r\n"+
+			"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+		require.NoError(t, err)
+		var reader = bufio.NewReader(conn)
+		response, err := http.ReadResponse(reader, nil)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		var header [2]byte
+		_, err = io.ReadFull(reader, header[:])
+		require.NoError(t, err)
+		var payload = make([]byte, header[1]&0x7F)
+		_, err = io.ReadFull(reader, payload)
+		require.NoError(t, err)
+		assert.Equal(t, byte(0x88), header[0])
+		assert.Equal(t, uint16(websocket.ClosePolicyViolation), binary.BigEndian.Uint16(payload))
+		assert.Equal(t, "session revoked", string(payload[2:]))
+	})
+
+	t.Run("bad websocket handshake", func(t *testing.T) {
+		server, _, _ := newEventServer(t, nil, time.Time{})
+		var request, _ = http.NewRequest(method, server.URL+target, nil)
+		request.Header.Set("Connection", "Upgrade")
+		request.Header.Set("Upgrade", "websocket")
+		request.Header.Set("Sec-WebSocket-Version", "8")
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, failure.ErrBadWebSocketHandshake.Status(), response.StatusCode)
+		assert.Equal(t, "13", response.Header.Get("Sec-WebSocket-Version"))
+	})
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"noda/service"
+	"strconv"
+)
+
+type ExportHandler struct {
+	s service.ExportService
+}
+
+func NewExportHandler(service service.ExportService) *ExportHandler {
+	return &ExportHandler{service}
+}
+
+// HandleExportRequest asks for an archive of all the data of the user, which
+// is built in the background.
+func (h *ExportHandler) HandleExportRequest(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	export, err := h.s.Request(userID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(export)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(data)
+}
+
+func (h *ExportHandler) HandleExportRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	export, err := h.s.FetchLatest(userID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(export)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *ExportHandler) HandleArchiveDownload(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	export, content, err := h.s.Open(userID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	defer content.Close()
+	extendDeadlines(w)
+	var header = w.Header()
+	header.Set("Content-Type", "application/zip")
+	header.Set("Content-Length", strconv.FormatInt(export.Size, 10))
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": fmt.Sprintf("noda-export-%s.zip", export.RequestedAt.UTC().Format("2006-01-02")),
+	}))
+	w.WriteHeader(http.StatusOK)
+	_, err = io.Copy(w, content)
+	if nil != err {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportHandler_HandleExportRequest(t *testing.T) {
+	const method, target = "POST", "/me/export"
+
+	t.Run("success", func(t *testing.T) {
+		var exportID = uuid.New()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewExportServiceMock()
+		m.On("Request", userID).Return(&model.Export{UUID: exportID, Status: types.ExportStatusPending}, nil)
+		var recorder = httptest.NewRecorder()
+		NewExportHandler(m).HandleExportRequest(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusAccepted, response.StatusCode)
+		assert.Contains(t, responseBody, `"export_uuid":"`+exportID.String()+`"`)
+		assert.Contains(t, responseBody, `"status":"pending"`)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewExportServiceMock()
+		m.On("Request", userID).Return(nil, failure.ErrUserNoLongerExists)
+		var recorder = httptest.NewRecorder()
+		NewExportHandler(m).HandleExportRequest(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, failure.ErrUserNoLongerExists.Status(), response.StatusCode)
+	})
+}
+
+func TestExportHandler_HandleExportRetrieval(t *testing.T) {
+	const method, target = "GET", "/me/export"
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewExportServiceMock()
+		m.On("FetchLatest", userID).Return(&model.Export{Status: types.ExportStatusReady, Size: 2048}, nil)
+		var recorder = httptest.NewRecorder()
+		NewExportHandler(m).HandleExportRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Contains(t, responseBody, `"status":"ready","size":2048`)
+	})
+
+	t.Run("no export yet", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewExportServiceMock()
+		m.On("FetchLatest", userID).Return(nil, failure.ErrExportNotFound)
+		var recorder = httptest.NewRecorder()
+		NewExportHandler(m).HandleExportRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	})
+}
+
+func TestExportHandler_HandleArchiveDownload(t *testing.T) {
+	const method, target = "GET", "/me/export/archive"
+
+	t.Run("success", func(t *testing.T) {
+		var export = &model.Export{
+			Status:      types.ExportStatusReady,
+			Size:        2,
+			RequestedAt: time.Date(2024, 12, 31, 9, 0, 0, 0, time.UTC),
+		}
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewExportServiceMock()
+		m.On("Open", userID).Return(export, io.NopCloser(strings.NewReader("PK")), nil)
+		var recorder = httptest.NewRecorder()
+		NewExportHandler(m).HandleArchiveDownload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "PK", string(responseBody))
+		assert.Equal(t, "application/zip", response.Header.Get("Content-Type"))
+		assert.Equal(t, "2", response.Header.Get("Content-Length"))
+		assert.Equal(t, "attachment; filename=noda-export-2024-12-31.zip", response.Header.Get("Content-Disposition"))
+	})
+
+	t.Run("archive not ready", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewExportServiceMock()
+		m.On("Open", userID).Return(nil, nil, failure.ErrExportNotReady.Clone().FormatDetails("pending"))
+		var recorder = httptest.NewRecorder()
+		NewExportHandler(m).HandleArchiveDownload(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+		assert.Contains(t, responseBody, "The archive of your last export is pending.")
+	})
+}
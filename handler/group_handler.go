@@ -30,7 +30,7 @@ func (h *GroupHandler) HandleGroupCreation(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	userID, _ := extractUserPayload(r)
-	insertedID, err := h.s.Save(userID, group)
+	insertedID, err := audited(h.s, r).Save(userID, group)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -95,7 +95,7 @@ func (h *GroupHandler) HandleGroupUpdate(w http.ResponseWriter, r *http.Request)
 	if didNotParse(groupID) {
 		return
 	}
-	ok, err := h.s.Update(userID, groupID, up)
+	ok, err := audited(h.s, r).Update(userID, groupID, up)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -112,7 +112,7 @@ func (h *GroupHandler) HandleGroupDeletion(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	userID, _ := extractUserPayload(r)
-	_, err := h.s.Remove(userID, groupID)
+	_, err := audited(h.s, r).Remove(userID, groupID)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -30,12 +30,16 @@ func extractResponseBody(t *testing.T, body io.Reader) []byte {
 	return b
 }
 
-var userID = uuid.New()
+var (
+	userID    = uuid.New()
+	sessionID = uuid.New()
+)
 
 func withLoggedUser(request **http.Request) {
 	var ctx = context.WithValue((*request).Context(), types.ContextKey{}, types.JWTPayload{
-		UserID:   userID,
-		UserRole: types.RoleUser,
+		UserID:    userID,
+		UserRole:  types.RoleUser,
+		SessionID: sessionID,
 	})
 	*request = (*request).Clone(ctx)
 }
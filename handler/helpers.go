@@ -8,11 +8,14 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"noda/audit"
 	"noda/data/types"
 	"noda/failure"
+	"noda/service"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func extractQueryParameter(r *http.Request, key, fallback string) string {
@@ -105,6 +108,73 @@ func extractSorting(w http.ResponseWriter, r *http.Request) string {
 	return "?"
 }
 
+// parseTagFilter parses the "tags" query parameter, a comma-separated list of
+// tag UUIDs, and the "match" query parameter, which is either "any" (default)
+// or "all". It returns a nil filter if no tags were given. If ok is false, an
+// error has already been emitted.
+func parseTagFilter(w http.ResponseWriter, r *http.Request) (filter *types.TagFilter, ok bool) {
+	for _, key := range []string{"tags", "match"} {
+		if len(r.URL.Query()[key]) > 1 {
+			failure.EmitError(w, failure.ErrMultipleValuesForQueryParameter.
+				Clone().
+				FormatDetails(key))
+			return nil, false
+		}
+	}
+	var tags = extractQueryParameter(r, "tags", "")
+	if "" == tags {
+		return nil, true
+	}
+	var match = extractQueryParameter(r, "match", "any")
+	if "any" != match && "all" != match {
+		failure.EmitError(w, failure.ErrBadQueryParameter.
+			Clone().
+			SetDetails("The parameter \"match\" must be either \"any\" or \"all\"."))
+		return nil, false
+	}
+	filter = &types.TagFilter{MatchAll: "all" == match}
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		if "" == tag {
+			continue
+		}
+		id, err := uuid.Parse(tag)
+		if nil != err {
+			var details = fmt.Sprintf("The parameter \"tags\" contains an invalid UUID: %q.", tag)
+			failure.EmitError(w, failure.ErrBadQueryParameter.Clone().SetDetails(details))
+			return nil, false
+		}
+		filter.Tags = append(filter.Tags, id)
+	}
+	return filter, true
+}
+
+// parseAssignee parses the "assignee" query parameter, either the UUID of a
+// user or "me" for the logged user. It returns uuid.Nil if no assignee was
+// given. If ok is false, an error has already been emitted.
+func parseAssignee(w http.ResponseWriter, r *http.Request, userID uuid.UUID) (assigneeID uuid.UUID, ok bool) {
+	if len(r.URL.Query()["assignee"]) > 1 {
+		failure.EmitError(w, failure.ErrMultipleValuesForQueryParameter.
+			Clone().
+			FormatDetails("assignee"))
+		return uuid.Nil, false
+	}
+	var assignee = extractQueryParameter(r, "assignee", "")
+	switch assignee {
+	case "":
+		return uuid.Nil, true
+	case "me":
+		return userID, true
+	}
+	assigneeID, err := uuid.Parse(assignee)
+	if nil != err {
+		var details = fmt.Sprintf("The parameter \"assignee\" must be either \"me\" or a valid UUID, but got %q.", assignee)
+		failure.EmitError(w, failure.ErrBadQueryParameter.Clone().SetDetails(details))
+		return uuid.Nil, false
+	}
+	return assigneeID, true
+}
+
 func parseRequestBody(w http.ResponseWriter, r *http.Request, target any) error {
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
 	var decoder = json.NewDecoder(r.Body)
@@ -168,6 +238,11 @@ func extractUserPayload(r *http.Request) (userID uuid.UUID, userRole types.Role)
 	return payload.UserID, payload.UserRole
 }
 
+func extractSessionID(r *http.Request) uuid.UUID {
+	payload := r.Context().Value(types.ContextKey{}).(types.JWTPayload)
+	return payload.SessionID
+}
+
 func redirect(w http.ResponseWriter, r *http.Request, to string) {
 	var (
 		scheme = "http://"
@@ -196,3 +271,97 @@ func gotAndHandledServiceError(w http.ResponseWriter, err error) bool {
 	}
 	return false
 }
+
+// parseAuditFilter parses the "actor" and "entity_uuid" query parameters, both
+// UUIDs, the "entity" query parameter, and the "from" and "to" query parameters,
+// both RFC 3339 timestamps. Missing parameters do not filter. If ok is false, an
+// error has already been emitted.
+func parseAuditFilter(w http.ResponseWriter, r *http.Request) (filter *types.AuditFilter, ok bool) {
+	for _, key := range []string{"actor", "entity", "entity_uuid", "from", "to"} {
+		if len(r.URL.Query()[key]) > 1 {
+			failure.EmitError(w, failure.ErrMultipleValuesForQueryParameter.
+				Clone().
+				FormatDetails(key))
+			return nil, false
+		}
+	}
+	filter = &types.AuditFilter{Entity: extractQueryParameter(r, "entity", "")}
+	var agg = failure.AggregateDetails{}
+	var ids = []struct {
+		key string
+		id  *uuid.UUID
+	}{{"actor", &filter.ActorUUID}, {"entity_uuid", &filter.EntityUUID}}
+	for _, p := range ids {
+		var key, value = p.key, extractQueryParameter(r, p.key, "")
+		if "" == value {
+			continue
+		}
+		parsed, err := uuid.Parse(value)
+		if nil != err {
+			agg.Append(fmt.Sprintf("The parameter %q must be a valid UUID.", key))
+			continue
+		}
+		*p.id = parsed
+	}
+	var times = []struct {
+		key string
+		at  **time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}}
+	for _, p := range times {
+		var key, value = p.key, extractQueryParameter(r, p.key, "")
+		if "" == value {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if nil != err {
+			agg.Append(fmt.Sprintf("The parameter %q must be an RFC 3339 timestamp.", key))
+			continue
+		}
+		*p.at = &parsed
+	}
+	if nil != filter.From && nil != filter.To && filter.To.Before(*filter.From) {
+		agg.Append("The parameter \"to\" must not be before the parameter \"from\".")
+	}
+	if agg.Has() {
+		failure.EmitError(w, failure.ErrBadQueryParameter.Clone().SetDetails(agg.Error()))
+		return nil, false
+	}
+	return filter, true
+}
+
+// parseSearchEntities parses the "type" query parameter, a comma-separated
+// list of the kinds of things to search for. It returns nil if none was
+// given. If ok is false, an error has already been emitted.
+func parseSearchEntities(w http.ResponseWriter, r *http.Request) (entities []types.SearchEntity, ok bool) {
+	if len(r.URL.Query()["type"]) > 1 {
+		failure.EmitError(w, failure.ErrMultipleValuesForQueryParameter.
+			Clone().
+			FormatDetails("type"))
+		return nil, false
+	}
+	var kinds = extractQueryParameter(r, "type", "")
+	if "" == kinds {
+		return nil, true
+	}
+	for _, kind := range strings.Split(kinds, ",") {
+		var entity = types.SearchEntity(strings.TrimSpace(kind))
+		switch entity {
+		case "":
+			continue
+		case types.SearchEntityTask, types.SearchEntityStep, types.SearchEntityList,
+			types.SearchEntityGroup, types.SearchEntityTag, types.SearchEntityComment:
+			entities = append(entities, entity)
+		default:
+			var details = fmt.Sprintf("The parameter \"type\" contains an unknown kind: %q.", kind)
+			failure.EmitError(w, failure.ErrBadQueryParameter.Clone().SetDetails(details))
+			return nil, false
+		}
+	}
+	return entities, true
+}
+
+// audited returns the service recording the changes it makes while serving the
+// request as coming from the user and the client of the request.
+func audited[S any](s S, r *http.Request) S {
+	return service.Within(s, audit.OriginOf(r))
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"noda/failure"
+	"noda/importer"
+	"noda/service"
+	"strconv"
+)
+
+// maxImportSize is the size in bytes of the largest file that can be imported.
+const maxImportSize = 10 << 20
+
+type ImportHandler struct {
+	s service.ImportService
+}
+
+func NewImportHandler(service service.ImportService) *ImportHandler {
+	return &ImportHandler{service}
+}
+
+// HandleImport imports the file in the request body, in the format of the
+// "format" query parameter. With "dry_run=true", nothing is made, and the
+// report tells what would be. An import that is only partly made answers with
+// its report, problems included, and the status of the error.
+func (h *ImportHandler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	format, err := importer.ParseFormat(extractQueryParameter(r, "format", ""))
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadQueryParameter.
+			Clone().
+			SetDetails("The parameter \"format\" must be one of ical, csv, todoist or trello."))
+		return
+	}
+	dryRun, err := strconv.ParseBool(extractQueryParameter(r, "dry_run", "false"))
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadQueryParameter.
+			Clone().
+			SetDetails("The parameter \"dry_run\" must be true or false."))
+		return
+	}
+	/* Reading a large file and making what it holds take longer than the
+	   server gives to the other requests.  */
+	extendDeadlines(w)
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	data, err := io.ReadAll(r.Body)
+	if nil != err {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			failure.EmitError(w, failure.ErrFileTooLarge.Clone().FormatDetails(tooLarge.Limit))
+			return
+		}
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var userID, _ = extractUserPayload(r)
+	report, err := audited(h.s, r).Import(userID, format, bytes.NewReader(data), dryRun)
+	var status = http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	var e *failure.Error
+	switch {
+	case nil != err && nil != report && errors.As(err, &e):
+		status = e.Status()
+	case gotAndHandledServiceError(w, err):
+		return
+	}
+	data, err = json.Marshal(report)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(status)
+	w.Write(data)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/failure"
+	"noda/importer"
+	"noda/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportHandler_HandleImport(t *testing.T) {
+	const (
+		method = "POST"
+		file   = "title\nPaint the fence\n"
+	)
+	var readFile = mock.MatchedBy(func(r io.Reader) bool {
+		data, err := io.ReadAll(r)
+		return nil == err && file == string(data)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, "/me/imports?format=csv", strings.NewReader(file))
+		withLoggedUser(&request)
+		var m = mocks.NewImportServiceMock()
+		m.On("Import", userID, importer.FormatCSV, readFile, false).
+			Return(&model.ImportReport{Created: model.ImportCounts{Lists: 1, Tasks: 1}}, nil)
+		var recorder = httptest.NewRecorder()
+		NewImportHandler(m).HandleImport(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Contains(t, responseBody, `"created":{"groups":0,"lists":1,"tasks":1,"steps":0,"tags":0}`)
+	})
+
+	t.Run("outlasts the deadlines of the server", func(t *testing.T) {
+		var m = mocks.NewImportServiceMock()
+		m.On("Import", userID, importer.FormatCSV, readFile, false).
+			After(300*time.Millisecond).
+			Return(&model.ImportReport{Created: model.ImportCounts{Tasks: 1}}, nil)
+		var server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			withLoggedUser(&r)
+			NewImportHandler(m).HandleImport(w, r)
+		}))
+		server.Config.ReadTimeout = 100 * time.Millisecond
+		server.Config.WriteTimeout = 100 * time.Millisecond
+		server.Start()
+		defer server.Close()
+		response, err := server.Client().Post(server.URL+"/me/imports?format=csv", "text/csv", strings.NewReader(file))
+		require.NoError(t, err)
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Contains(t, string(extractResponseBody(t, response.Body)), `"tasks":1`)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		var request = httptest.NewRequest(method, "/me/imports?format=csv&dry_run=true", strings.NewReader(file))
+		withLoggedUser(&request)
+		var m = mocks.NewImportServiceMock()
+		m.On("Import", userID, importer.FormatCSV, readFile, true).
+			Return(&model.ImportReport{DryRun: true}, nil)
+		var recorder = httptest.NewRecorder()
+		NewImportHandler(m).HandleImport(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		var request = httptest.NewRequest(method, "/me/imports?format=csv", strings.NewReader(file))
+		withLoggedUser(&request)
+		var m = mocks.NewImportServiceMock()
+		m.On("Import", userID, importer.FormatCSV, readFile, false).
+			Return(&model.ImportReport{Problems: []string{"Row 2: The task has no title."}},
+				failure.ErrIncompleteImport.Clone().SetDetails(`["Row 2: The task has no title."]`))
+		var recorder = httptest.NewRecorder()
+		NewImportHandler(m).HandleImport(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusMultiStatus, response.StatusCode)
+		assert.Contains(t, responseBody, `"problems":["Row 2: The task has no title."]`)
+	})
+
+	t.Run("bad rows", func(t *testing.T) {
+		var request = httptest.NewRequest(method, "/me/imports?format=csv", strings.NewReader(file))
+		withLoggedUser(&request)
+		var m = mocks.NewImportServiceMock()
+		m.On("Import", userID, importer.FormatCSV, readFile, false).
+			Return(nil, failure.ErrBadImport.Clone().SetDetails(`["Row 2: The task has no title."]`))
+		var recorder = httptest.NewRecorder()
+		NewImportHandler(m).HandleImport(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = string(extractResponseBody(t, response.Body))
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, responseBody, `"details":["Row 2: The task has no title."]`)
+	})
+
+	t.Run("bad query parameters", func(t *testing.T) {
+		for _, target := range []string{"/me/imports", "/me/imports?format=xlsx", "/me/imports?format=csv&dry_run=maybe"} {
+			var request = httptest.NewRequest(method, target, strings.NewReader(file))
+			withLoggedUser(&request)
+			var m = mocks.NewImportServiceMock()
+			var recorder = httptest.NewRecorder()
+			NewImportHandler(m).HandleImport(recorder, request)
+			var response = recorder.Result()
+			response.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode, "target: %q", target)
+			m.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/service"
+)
+
+type JobRunHandler struct {
+	s service.JobRunService
+}
+
+func NewJobRunHandler(service service.JobRunService) *JobRunHandler {
+	return &JobRunHandler{service}
+}
+
+func (h *JobRunHandler) HandleJobRunsRetrieval(w http.ResponseWriter, r *http.Request) {
+	pagination := parsePagination(w, r)
+	if pagination == nil {
+		return
+	}
+	var job = extractQueryParameter(r, "job", "")
+	res, err := h.s.Fetch(pagination, job)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(res)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/mocks"
+	"testing"
+	"time"
+)
+
+func TestJobRunHandler_HandleJobRunsRetrieval(t *testing.T) {
+	const (
+		method  = "GET"
+		target  = "/jobs/runs"
+		routine = "Fetch"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination    = types.Pagination{Page: 1, RPP: 10}
+			values        = url.Values{"page": []string{"1"}, "rpp": []string{"10"}, "job": []string{"rollover"}}
+			serviceResult = &types.Result[model.JobRun]{
+				Page:      pagination.Page,
+				RPP:       pagination.RPP,
+				Payload:   []*model.JobRun{{UUID: uuid.New(), Job: "rollover", StartedAt: time.Now()}},
+				Retrieved: 1,
+			}
+			expectedResponseBody = string(marshal(t, serviceResult))
+		)
+		var request = httptest.NewRequest(method, target+"?"+values.Encode(), nil)
+		withLoggedUser(&request)
+		var s = mocks.NewJobRunServiceMock()
+		s.On(routine, &pagination, "rollover").Return(serviceResult, nil)
+		var recorder = httptest.NewRecorder()
+		NewJobRunHandler(s).HandleJobRunsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedResponseBody, string(responseBody))
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var s = mocks.NewJobRunServiceMock()
+		s.On(routine, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewJobRunHandler(s).HandleJobRunsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
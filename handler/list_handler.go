@@ -48,12 +48,12 @@ func (h *ListHandler) doCreateList(t listType, w http.ResponseWriter, r *http.Re
 		if didNotParse(groupID) {
 			return
 		}
-		insertedID, err = h.s.Save(userID, groupID, next)
+		insertedID, err = audited(h.s, r).Save(userID, groupID, next)
 		if gotAndHandledServiceError(w, err) {
 			return
 		}
 	} else {
-		insertedID, err = h.s.Save(userID, uuid.Nil, next)
+		insertedID, err = audited(h.s, r).Save(userID, uuid.Nil, next)
 		if gotAndHandledServiceError(w, err) {
 			return
 		}
@@ -207,7 +207,7 @@ func (h *ListHandler) doUpdateList(t listType, w http.ResponseWriter, r *http.Re
 		redirect(w, r, target)
 		return
 	}
-	ok, err := h.s.Update(ownerID, groupID, listID, up)
+	ok, err := audited(h.s, r).Update(ownerID, groupID, listID, up)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -241,7 +241,7 @@ func (h *ListHandler) doDeleteList(t listType, w http.ResponseWriter, r *http.Re
 	if didNotParse(listID) {
 		return
 	}
-	err := h.s.Remove(userID, groupID, listID)
+	err := audited(h.s, r).Remove(userID, groupID, listID)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
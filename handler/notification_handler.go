@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/service"
+)
+
+type NotificationHandler struct {
+	s service.NotificationService
+}
+
+func NewNotificationHandler(service service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{s: service}
+}
+
+func (h *NotificationHandler) HandleNotificationsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	result, err := h.s.Fetch(userID, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *NotificationHandler) HandleNotificationDismissal(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var notificationID = parseParameterToUUID(w, r, "notification_uuid")
+	if didNotParse(notificationID) {
+		return
+	}
+	ok, err := h.s.Dismiss(userID, notificationID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, "/me/notifications")
+}
+
+func (h *NotificationHandler) HandleNotificationSnooze(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var notificationID = parseParameterToUUID(w, r, "notification_uuid")
+	if didNotParse(notificationID) {
+		return
+	}
+	var snooze = new(transfer.NotificationSnooze)
+	var err = parseRequestBody(w, r, snooze)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = snooze.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	ok, err := h.s.Snooze(userID, notificationID, snooze)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, "/me/notifications")
+}
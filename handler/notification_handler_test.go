@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotificationHandler_HandleNotificationsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/notifications"
+		serviceMethod = "Fetch"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination    = types.Pagination{Page: 1, RPP: 10}
+			notifications = []*model.Notification{
+				{UUID: uuid.New(), OwnerUUID: userID, TaskUUID: uuid.New(), Title: "Pay the rent", RemindAt: time.Now()},
+			}
+			result               = &types.Result[model.Notification]{Page: 1, RPP: 10, Retrieved: 1, Payload: notifications}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewNotificationServiceMock()
+		m.On(serviceMethod, userID, &pagination).Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewNotificationServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
+
+func TestNotificationHandler_HandleNotificationDismissal(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/notifications/{notification_uuid}"
+		serviceMethod = "Dismiss"
+	)
+	var notificationID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"notification_uuid": notificationID.String()})
+		var m = mocks.NewNotificationServiceMock()
+		m.On(serviceMethod, userID, notificationID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationDismissal(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("nothing changed? take me to the notifications", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"notification_uuid": notificationID.String()})
+		var m = mocks.NewNotificationServiceMock()
+		m.On(serviceMethod, userID, notificationID).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationDismissal(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/notifications")
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrNotificationNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"notification_uuid": notificationID.String()})
+		var m = mocks.NewNotificationServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationDismissal(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+
+	t.Run("parsing \"notification_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"notification_uuid": "x"})
+		var m = mocks.NewNotificationServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationDismissal(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestNotificationHandler_HandleNotificationSnooze(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/notifications/{notification_uuid}/snooze"
+		serviceMethod = "Snooze"
+	)
+	var notificationID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			snooze      = &transfer.NotificationSnooze{Until: time.Now().Add(time.Hour).Truncate(time.Second)}
+			requestBody = marshal(t, snooze)
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"notification_uuid": notificationID.String()})
+		var m = mocks.NewNotificationServiceMock()
+		m.On(serviceMethod, userID, notificationID, mock.MatchedBy(func(s *transfer.NotificationSnooze) bool {
+			return s.Until.Equal(snooze.Until)
+		})).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationSnooze(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("\"until\" is required", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader([]byte("{}")))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"notification_uuid": notificationID.String()})
+		var m = mocks.NewNotificationServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationSnooze(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrNotificationNotFound
+		var requestBody = marshal(t, &transfer.NotificationSnooze{Until: time.Now().Add(time.Hour)})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"notification_uuid": notificationID.String()})
+		var m = mocks.NewNotificationServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewNotificationHandler(m).HandleNotificationSnooze(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/service"
+)
+
+type PasswordResetHandler struct {
+	s service.PasswordResetService
+}
+
+func NewPasswordResetHandler(s service.PasswordResetService) *PasswordResetHandler {
+	return &PasswordResetHandler{s}
+}
+
+func (h *PasswordResetHandler) HandlePasswordResetRequest(w http.ResponseWriter, r *http.Request) {
+	var request = &transfer.PasswordResetRequest{}
+	var err = parseRequestBody(w, r, request)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = request.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = h.s.Request(request.Email)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *PasswordResetHandler) HandlePasswordReset(w http.ResponseWriter, r *http.Request) {
+	var reset = &transfer.PasswordReset{}
+	var err = parseRequestBody(w, r, reset)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = reset.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = h.s.Reset(reset.Token, reset.NewPassword)
+	if err != nil {
+		var a *failure.AggregateDetails
+		if errors.As(err, &a) {
+			failure.EmitError(w, failure.ErrPasswordRestrictions.Clone().SetDetails(a.Error()))
+			return
+		}
+		gotAndHandledServiceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
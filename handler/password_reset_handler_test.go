@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+)
+
+func TestPasswordResetHandler_HandlePasswordResetRequest(t *testing.T) {
+	const (
+		method  = "POST"
+		target  = "/password/forgot"
+		routine = "Request"
+	)
+	var request = &transfer.PasswordResetRequest{Email: "es09911@zbock.com"}
+
+	t.Run("success", func(t *testing.T) {
+		var req = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, request)))
+		var s = mocks.NewPasswordResetServiceMock()
+		s.On(routine, request.Email).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewPasswordResetHandler(s).HandlePasswordResetRequest(recorder, req)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusAccepted, response.StatusCode)
+		assert.Empty(t, string(extractResponseBody(t, response.Body)), "No response body is expected.")
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		var req = httptest.NewRequest(method, target, bytes.NewReader([]byte(`{"email": "wrong"}`)))
+		var s = mocks.NewPasswordResetServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewPasswordResetHandler(s).HandlePasswordResetRequest(recorder, req)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		s.AssertNotCalled(t, routine, mock.Anything)
+	})
+
+	t.Run("got service error", func(t *testing.T) {
+		var req = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, request)))
+		var s = mocks.NewPasswordResetServiceMock()
+		s.On(routine, request.Email).Return(errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewPasswordResetHandler(s).HandlePasswordResetRequest(recorder, req)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
+
+func TestPasswordResetHandler_HandlePasswordReset(t *testing.T) {
+	const (
+		method  = "POST"
+		target  = "/password/reset"
+		routine = "Reset"
+	)
+	var reset = &transfer.PasswordReset{Token: "Nq3nQGa0yVt0cJxk1Vf2mXo5c7cS8oA6PzS3zqkFh1E", NewPassword: "Gq7!vR2#pLk9@wZ"}
+
+	t.Run("success", func(t *testing.T) {
+		var req = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, reset)))
+		var s = mocks.NewPasswordResetServiceMock()
+		s.On(routine, reset.Token, reset.NewPassword).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewPasswordResetHandler(s).HandlePasswordReset(recorder, req)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		var req = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, reset)))
+		var s = mocks.NewPasswordResetServiceMock()
+		s.On(routine, reset.Token, reset.NewPassword).Return(failure.ErrInvalidResetToken)
+		var recorder = httptest.NewRecorder()
+		NewPasswordResetHandler(s).HandlePasswordReset(recorder, req)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(extractResponseBody(t, response.Body)), failure.ErrInvalidResetToken.Details())
+	})
+
+	t.Run("password restrictions not met", func(t *testing.T) {
+		var (
+			req        = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, reset)))
+			violations = new(failure.AggregateDetails)
+		)
+		violations.Append("Password must be at least 8 characters long.")
+		var s = mocks.NewPasswordResetServiceMock()
+		s.On(routine, reset.Token, reset.NewPassword).Return(violations)
+		var recorder = httptest.NewRecorder()
+		NewPasswordResetHandler(s).HandlePasswordReset(recorder, req)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(extractResponseBody(t, response.Body)), "Password must be at least 8 characters long.")
+	})
+}
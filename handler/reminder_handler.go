@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/service"
+)
+
+type ReminderHandler struct {
+	s service.ReminderService
+}
+
+func NewReminderHandler(service service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{s: service}
+}
+
+func (h *ReminderHandler) HandleRetrievalOfReminderDeliveries(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	result, err := h.s.FetchDeliveries(userID, taskID, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReminderHandler_HandleRetrievalOfReminderDeliveries(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/tasks/{task_uuid}/reminder/deliveries"
+		serviceMethod = "FetchDeliveries"
+	)
+	var taskID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination = types.Pagination{Page: 1, RPP: 10}
+			deliveries = []*model.ReminderDelivery{
+				{UUID: uuid.New(), TaskUUID: taskID, Channel: "email", RemindAt: time.Now(), Delivered: true, AttemptedAt: time.Now()},
+			}
+			result               = &types.Result[model.ReminderDelivery]{Page: 1, RPP: 10, Retrieved: 1, Payload: deliveries}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewReminderServiceMock()
+		m.On(serviceMethod, userID, taskID, &pagination).Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewReminderHandler(m).HandleRetrievalOfReminderDeliveries(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrTaskNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewReminderServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewReminderHandler(m).HandleRetrievalOfReminderDeliveries(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+
+	t.Run("parsing \"task_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": "x"})
+		var m = mocks.NewReminderServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewReminderHandler(m).HandleRetrievalOfReminderDeliveries(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/service"
+)
+
+type RevisionHandler struct {
+	s service.RevisionService
+}
+
+func NewRevisionHandler(service service.RevisionService) *RevisionHandler {
+	return &RevisionHandler{s: service}
+}
+
+func (h *RevisionHandler) HandleTaskRevisionsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	result, err := h.s.Fetch(userID, listID, taskID, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *RevisionHandler) HandleRestorationToRevision(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var revisionID = parseParameterToUUID(w, r, "revision_uuid")
+	if didNotParse(revisionID) {
+		return
+	}
+	ok, err := audited(h.s, r).Restore(userID, listID, taskID, revisionID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, "/me/lists/"+listID.String()+"/tasks/"+taskID.String())
+}
+
+func (h *RevisionHandler) HandleUndo(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	revision, err := audited(h.s, r).Undo(userID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(revision)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisionHandler_HandleTaskRevisionsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/revisions?page=2&rpp=5"
+		serviceMethod = "Fetch"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination = types.Pagination{Page: 2, RPP: 5}
+			revisions  = []*model.Revision{{
+				UUID:      uuid.New(),
+				TaskUUID:  taskID,
+				ActorUUID: userID,
+				Action:    types.RevisionActionUpdated,
+				Changes:   []*model.RevisionChange{{Field: "title", Previous: []byte(`"old"`), Current: []byte(`"new"`)}},
+			}}
+			result               = &types.Result[model.Revision]{Page: 2, RPP: 5, Retrieved: 1, Payload: revisions}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewRevisionServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, &pagination).Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewRevisionHandler(m).HandleTaskRevisionsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+}
+
+func TestRevisionHandler_HandleRestorationToRevision(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/revisions/{revision_uuid}/restore"
+		serviceMethod = "Restore"
+	)
+	var (
+		listID, taskID, revisionID = uuid.New(), uuid.New(), uuid.New()
+		pathParameters             = parameters{"list_uuid": listID.String(), "task_uuid": taskID.String(), "revision_uuid": revisionID.String()}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewRevisionServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, revisionID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewRevisionHandler(m).HandleRestorationToRevision(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("nothing changed? take me to the task", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewRevisionServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, revisionID).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewRevisionHandler(m).HandleRestorationToRevision(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/lists/"+listID.String()+"/tasks/"+taskID.String())
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrRevisionNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewRevisionServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, revisionID).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewRevisionHandler(m).HandleRestorationToRevision(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestRevisionHandler_HandleUndo(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/undo"
+		serviceMethod = "Undo"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			revision             = &model.Revision{UUID: uuid.New(), ActorUUID: userID, Action: types.RevisionActionTrashed, IsUndone: true}
+			expectedResponseBody = marshal(t, revision)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewRevisionServiceMock()
+		m.On(serviceMethod, userID).Return(revision, nil)
+		var recorder = httptest.NewRecorder()
+		NewRevisionHandler(m).HandleUndo(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("nothing to undo", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewRevisionServiceMock()
+		m.On(serviceMethod, userID).Return(nil, failure.ErrNothingToUndo)
+		var recorder = httptest.NewRecorder()
+		NewRevisionHandler(m).HandleUndo(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+	})
+}
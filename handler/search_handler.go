@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/failure"
+	"noda/service"
+)
+
+type SearchHandler struct {
+	s service.SearchService
+}
+
+func NewSearchHandler(service service.SearchService) *SearchHandler {
+	return &SearchHandler{service}
+}
+
+func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var needle = extractQueryParameter(r, "q", "")
+	if "" == needle {
+		failure.EmitError(w, failure.ErrBadQueryParameter.
+			Clone().
+			SetDetails("The parameter \"q\" is required."))
+		return
+	}
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	entities, ok := parseSearchEntities(w, r)
+	if !ok {
+		return
+	}
+	res, err := h.s.Search(userID, needle, entities, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(res)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchHandler_HandleSearch(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/search"
+		serviceMethod = "Search"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination    = types.Pagination{Page: 1, RPP: 10}
+			values        = url.Values{"q": []string{"fence"}, "type": []string{"task, tag"}}
+			entities      = []types.SearchEntity{types.SearchEntityTask, types.SearchEntityTag}
+			serviceResult = &model.SearchResult{
+				Page:      pagination.Page,
+				RPP:       pagination.RPP,
+				Retrieved: 1,
+				Facets:    map[types.SearchEntity]int64{types.SearchEntityTask: 1, types.SearchEntityList: 2},
+				Payload: []*model.SearchHit{{
+					Entity:     types.SearchEntityTask,
+					EntityUUID: uuid.New(),
+					Title:      "Paint the fence",
+					Snippet:    "Paint the <mark>fence</mark>",
+					Rank:       0.9,
+				}},
+			}
+			expectedResponseBody = string(marshal(t, serviceResult))
+		)
+		var request = httptest.NewRequest(method, target+"?"+values.Encode(), nil)
+		withLoggedUser(&request)
+		var s = mocks.NewSearchServiceMock()
+		s.On(serviceMethod, userID, "fence", entities, &pagination).Return(serviceResult, nil)
+		var recorder = httptest.NewRecorder()
+		NewSearchHandler(s).HandleSearch(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, expectedResponseBody, string(responseBody))
+	})
+
+	t.Run("bad query parameters", func(t *testing.T) {
+		for _, query := range []string{
+			"",
+			"q=%20",
+			"q=fence&type=task,person",
+			"q=fence&type=task&type=tag",
+			"q=fence&page=0",
+		} {
+			var request = httptest.NewRequest(method, target+"?"+query, nil)
+			withLoggedUser(&request)
+			var s = mocks.NewSearchServiceMock()
+			var recorder = httptest.NewRecorder()
+			NewSearchHandler(s).HandleSearch(recorder, request)
+			var response = recorder.Result()
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode, "query: %q", query)
+			response.Body.Close()
+			s.AssertNotCalled(t, serviceMethod)
+		}
+	})
+}
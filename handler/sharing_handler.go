@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/service"
+)
+
+type SharingHandler struct {
+	s service.SharingService
+}
+
+func NewSharingHandler(service service.SharingService) *SharingHandler {
+	return &SharingHandler{s: service}
+}
+
+func (h *SharingHandler) HandleListInvitation(w http.ResponseWriter, r *http.Request) {
+	h.handleInvitation(w, r, types.ShareKindList, "list_uuid")
+}
+
+func (h *SharingHandler) HandleGroupInvitation(w http.ResponseWriter, r *http.Request) {
+	h.handleInvitation(w, r, types.ShareKindGroup, "group_uuid")
+}
+
+func (h *SharingHandler) HandleListMembersRetrieval(w http.ResponseWriter, r *http.Request) {
+	h.handleMembersRetrieval(w, r, types.ShareKindList, "list_uuid")
+}
+
+func (h *SharingHandler) HandleGroupMembersRetrieval(w http.ResponseWriter, r *http.Request) {
+	h.handleMembersRetrieval(w, r, types.ShareKindGroup, "group_uuid")
+}
+
+func (h *SharingHandler) HandleListMemberRoleUpdate(w http.ResponseWriter, r *http.Request) {
+	h.handleMemberRoleUpdate(w, r, types.ShareKindList, "list_uuid")
+}
+
+func (h *SharingHandler) HandleGroupMemberRoleUpdate(w http.ResponseWriter, r *http.Request) {
+	h.handleMemberRoleUpdate(w, r, types.ShareKindGroup, "group_uuid")
+}
+
+func (h *SharingHandler) HandleListMemberRemoval(w http.ResponseWriter, r *http.Request) {
+	h.handleMemberRemoval(w, r, types.ShareKindList, "list_uuid")
+}
+
+func (h *SharingHandler) HandleGroupMemberRemoval(w http.ResponseWriter, r *http.Request) {
+	h.handleMemberRemoval(w, r, types.ShareKindGroup, "group_uuid")
+}
+
+func (h *SharingHandler) handleInvitation(w http.ResponseWriter, r *http.Request, kind types.ShareKind, parameter string) {
+	var userID, _ = extractUserPayload(r)
+	var targetID = parseParameterToUUID(w, r, parameter)
+	if didNotParse(targetID) {
+		return
+	}
+	var invitation = new(transfer.Invitation)
+	var err = parseRequestBody(w, r, invitation)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = invitation.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	insertedID, err := h.s.Invite(userID, kind, targetID, invitation)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": insertedID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *SharingHandler) handleMembersRetrieval(w http.ResponseWriter, r *http.Request, kind types.ShareKind, parameter string) {
+	var userID, _ = extractUserPayload(r)
+	var targetID = parseParameterToUUID(w, r, parameter)
+	if didNotParse(targetID) {
+		return
+	}
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	result, err := h.s.FetchMembers(userID, kind, targetID, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *SharingHandler) handleMemberRoleUpdate(w http.ResponseWriter, r *http.Request, kind types.ShareKind, parameter string) {
+	var userID, _ = extractUserPayload(r)
+	var targetID = parseParameterToUUID(w, r, parameter)
+	if didNotParse(targetID) {
+		return
+	}
+	var memberID = parseParameterToUUID(w, r, "user_uuid")
+	if didNotParse(memberID) {
+		return
+	}
+	var update = new(transfer.MemberRoleUpdate)
+	var err = parseRequestBody(w, r, update)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = update.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	ok, err := h.s.SetMemberRole(userID, kind, targetID, memberID, update)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, "/me/"+string(kind)+"s/"+targetID.String()+"/members")
+}
+
+func (h *SharingHandler) handleMemberRemoval(w http.ResponseWriter, r *http.Request, kind types.ShareKind, parameter string) {
+	var userID, _ = extractUserPayload(r)
+	var targetID = parseParameterToUUID(w, r, parameter)
+	if didNotParse(targetID) {
+		return
+	}
+	var memberID = parseParameterToUUID(w, r, "user_uuid")
+	if didNotParse(memberID) {
+		return
+	}
+	_, err := h.s.RemoveMember(userID, kind, targetID, memberID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SharingHandler) HandleInvitationsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	result, err := h.s.FetchInvitations(userID, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *SharingHandler) HandleInvitationAcceptance(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var invitationID = parseParameterToUUID(w, r, "invitation_uuid")
+	if didNotParse(invitationID) {
+		return
+	}
+	_, err := h.s.AcceptInvitation(userID, invitationID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SharingHandler) HandleInvitationDecline(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var invitationID = parseParameterToUUID(w, r, "invitation_uuid")
+	if didNotParse(invitationID) {
+		return
+	}
+	_, err := h.s.DeclineInvitation(userID, invitationID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SharingHandler) HandleSharedListsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	var search = extractQueryParameter(r, "search", "")
+	result, err := h.s.FetchSharedLists(userID, pagination, search)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *SharingHandler) HandleSharedGroupsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	var search = extractQueryParameter(r, "search", "")
+	result, err := h.s.FetchSharedGroups(userID, pagination, search)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
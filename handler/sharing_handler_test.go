@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSharingHandler_HandleListInvitation(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/lists/{list_uuid}/members"
+		serviceMethod = "Invite"
+	)
+	var listID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			invitation   = &transfer.Invitation{Email: "member@noda.com", Role: types.MemberRoleEditor}
+			requestBody  = marshal(t, invitation)
+			invitationID = uuid.New()
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, types.ShareKindList, listID, invitation).Return(invitationID, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleListInvitation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, `{"inserted_id":"`+invitationID.String()+`"}`, string(responseBody))
+	})
+
+	t.Run("cannot invite as owner", func(t *testing.T) {
+		var requestBody = marshal(t, &transfer.Invitation{Email: "member@noda.com", Role: types.MemberRoleOwner})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleListInvitation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrInsufficientRole
+		var requestBody = marshal(t, &transfer.Invitation{Email: "member@noda.com", Role: types.MemberRoleViewer})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(uuid.Nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleListInvitation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestSharingHandler_HandleGroupMembersRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/groups/{group_uuid}/members"
+		serviceMethod = "FetchMembers"
+	)
+	var groupID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination           = types.Pagination{Page: 1, RPP: 10}
+			members              = []*model.Member{{UserUUID: uuid.New(), Email: "member@noda.com", Role: types.MemberRoleAdmin}}
+			result               = &types.Result[model.Member]{Page: 1, RPP: 10, Retrieved: 1, Payload: members}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"group_uuid": groupID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, types.ShareKindGroup, groupID, &pagination).Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleGroupMembersRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("parsing \"group_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"group_uuid": "x"})
+		var m = mocks.NewSharingServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleGroupMembersRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestSharingHandler_HandleListMemberRoleUpdate(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/lists/{list_uuid}/members/{user_uuid}"
+		serviceMethod = "SetMemberRole"
+	)
+	var listID, memberID = uuid.New(), uuid.New()
+	var update = &transfer.MemberRoleUpdate{Role: types.MemberRoleAdmin}
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, update)))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "user_uuid": memberID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, types.ShareKindList, listID, memberID, update).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleListMemberRoleUpdate(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("nothing changed? take me to the members", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, update)))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "user_uuid": memberID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, types.ShareKindList, listID, memberID, update).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleListMemberRoleUpdate(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/lists/"+listID.String()+"/members")
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrMemberNotFound
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, update)))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "user_uuid": memberID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleListMemberRoleUpdate(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestSharingHandler_HandleGroupMemberRemoval(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/groups/{group_uuid}/members/{user_uuid}"
+		serviceMethod = "RemoveMember"
+	)
+	var groupID, memberID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"group_uuid": groupID.String(), "user_uuid": memberID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, types.ShareKindGroup, groupID, memberID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleGroupMemberRemoval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("parsing \"user_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"group_uuid": groupID.String(), "user_uuid": "x"})
+		var m = mocks.NewSharingServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleGroupMemberRemoval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestSharingHandler_HandleInvitationsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/invitations"
+		serviceMethod = "FetchInvitations"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination           = types.Pagination{Page: 1, RPP: 10}
+			invitations          = []*model.Invitation{{UUID: uuid.New(), Kind: types.ShareKindList, Role: types.MemberRoleViewer}}
+			result               = &types.Result[model.Invitation]{Page: 1, RPP: 10, Retrieved: 1, Payload: invitations}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, &pagination).Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleInvitationsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleInvitationsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
+
+func TestSharingHandler_HandleInvitationAcceptance(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/invitations/{invitation_uuid}/accept"
+		serviceMethod = "AcceptInvitation"
+	)
+	var invitationID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"invitation_uuid": invitationID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, invitationID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleInvitationAcceptance(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrInvitationNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"invitation_uuid": invitationID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleInvitationAcceptance(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestSharingHandler_HandleInvitationDecline(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/invitations/{invitation_uuid}/decline"
+		serviceMethod = "DeclineInvitation"
+	)
+	var invitationID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"invitation_uuid": invitationID.String()})
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, invitationID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleInvitationDecline(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+}
+
+func TestSharingHandler_HandleSharedListsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/shared/lists?search=groceries"
+		serviceMethod = "FetchSharedLists"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination           = types.Pagination{Page: 1, RPP: 10}
+			lists                = []*model.SharedList{{List: model.List{UUID: uuid.New(), Name: "groceries"}, Role: types.MemberRoleEditor}}
+			result               = &types.Result[model.SharedList]{Page: 1, RPP: 10, Retrieved: 1, Payload: lists}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, userID, &pagination, "groceries").Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleSharedListsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+}
+
+func TestSharingHandler_HandleSharedGroupsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/shared/groups"
+		serviceMethod = "FetchSharedGroups"
+	)
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var m = mocks.NewSharingServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewSharingHandler(m).HandleSharedGroupsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/service"
+)
+
+type SmartListHandler struct {
+	s service.SmartListService
+}
+
+func NewSmartListHandler(service service.SmartListService) *SmartListHandler {
+	return &SmartListHandler{s: service}
+}
+
+func (h *SmartListHandler) HandleSmartListCreation(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var smartList = new(transfer.SmartListCreation)
+	var err = parseRequestBody(w, r, smartList)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = smartList.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	insertedID, err := h.s.Save(userID, smartList)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": insertedID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *SmartListHandler) HandleSmartListsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	smartLists, err := h.s.Fetch(userID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(smartLists)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *SmartListHandler) HandleSmartListRetrievalByID(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var smartListID = parseParameterToUUID(w, r, "smart_list_uuid")
+	if didNotParse(smartListID) {
+		return
+	}
+	smartList, err := h.s.FetchByID(userID, smartListID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(smartList)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *SmartListHandler) HandleRetrievalOfSmartListTasks(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var smartListID = parseParameterToUUID(w, r, "smart_list_uuid")
+	if didNotParse(smartListID) {
+		return
+	}
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	result, err := h.s.FetchTasks(userID, smartListID, pagination)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *SmartListHandler) HandlePartialUpdateOfSmartList(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var smartListID = parseParameterToUUID(w, r, "smart_list_uuid")
+	if didNotParse(smartListID) {
+		return
+	}
+	var target = "/me/smart_lists/" + smartListID.String()
+	var up = new(transfer.SmartListUpdate)
+	var err = parseRequestBody(w, r, up)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	if "" == up.Name && "" == up.Expression {
+		redirect(w, r, target)
+		return
+	}
+	ok, err := h.s.Update(userID, smartListID, up)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, target)
+}
+
+func (h *SmartListHandler) HandleSmartListDeletion(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var smartListID = parseParameterToUUID(w, r, "smart_list_uuid")
+	if didNotParse(smartListID) {
+		return
+	}
+	err := h.s.Delete(userID, smartListID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmartListHandler_HandleSmartListCreation(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/smart_lists"
+		serviceMethod = "Save"
+	)
+	var creation = &transfer.SmartListCreation{Name: "Urgent", Expression: "priority>=high and not completed"}
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			insertedID           = uuid.New()
+			expectedResponseBody = marshal(t, JSON{"inserted_id": insertedID.String()})
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, creation)))
+		withLoggedUser(&request)
+		var m = mocks.NewSmartListServiceMock()
+		m.On(serviceMethod, userID, creation).Return(insertedID, nil)
+		var recorder = httptest.NewRecorder()
+		NewSmartListHandler(m).HandleSmartListCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("missing expression", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, JSON{"name": "Urgent"})))
+		withLoggedUser(&request)
+		var m = mocks.NewSmartListServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewSmartListHandler(m).HandleSmartListCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		m.AssertNotCalled(t, serviceMethod)
+	})
+
+	t.Run("invalid expression", func(t *testing.T) {
+		var expectedError = failure.ErrBadFilter.Clone().FormatDetails("at position 9: unknown priority highest")
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, creation)))
+		withLoggedUser(&request)
+		var m = mocks.NewSmartListServiceMock()
+		m.On(serviceMethod, userID, creation).Return(uuid.Nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewSmartListHandler(m).HandleSmartListCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestSmartListHandler_HandleRetrievalOfSmartListTasks(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/smart_lists/{smart_list_uuid}/tasks"
+		serviceMethod = "FetchTasks"
+	)
+	var smartListID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination    = types.Pagination{Page: 1, RPP: 10}
+			serviceResult = &types.Result[model.Task]{
+				Page:      pagination.Page,
+				RPP:       pagination.RPP,
+				Retrieved: 1,
+				Payload:   []*model.Task{{UUID: uuid.New(), Title: "Buy milk", Priority: types.TaskPriorityUrgent}},
+			}
+			expectedResponseBody = string(marshal(t, serviceResult))
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"smart_list_uuid": smartListID.String()})
+		var m = mocks.NewSmartListServiceMock()
+		m.On(serviceMethod, userID, smartListID, &pagination).Return(serviceResult, nil)
+		var recorder = httptest.NewRecorder()
+		NewSmartListHandler(m).HandleRetrievalOfSmartListTasks(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, expectedResponseBody, string(responseBody))
+	})
+
+	t.Run("smart list not found", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"smart_list_uuid": smartListID.String()})
+		var m = mocks.NewSmartListServiceMock()
+		m.On(serviceMethod, userID, smartListID, &types.Pagination{Page: 1, RPP: 10}).Return(nil, failure.ErrSmartListNotFound)
+		var recorder = httptest.NewRecorder()
+		NewSmartListHandler(m).HandleRetrievalOfSmartListTasks(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	})
+}
+
+func TestSmartListHandler_HandlePartialUpdateOfSmartList(t *testing.T) {
+	const (
+		method        = "PATCH"
+		target        = "/me/smart_lists/{smart_list_uuid}"
+		serviceMethod = "Update"
+	)
+	var smartListID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var up = &transfer.SmartListUpdate{Expression: "overdue"}
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, up)))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"smart_list_uuid": smartListID.String()})
+		var m = mocks.NewSmartListServiceMock()
+		m.On(serviceMethod, userID, smartListID, up).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewSmartListHandler(m).HandlePartialUpdateOfSmartList(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("body = {}? take me to the already existent smart list", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader([]byte(" { } ")))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"smart_list_uuid": smartListID.String()})
+		var m = mocks.NewSmartListServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewSmartListHandler(m).HandlePartialUpdateOfSmartList(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/smart_lists/"+smartListID.String())
+		m.AssertNotCalled(t, serviceMethod)
+	})
+}
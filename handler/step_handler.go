@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/service"
+
+	"github.com/google/uuid"
+)
+
+type StepHandler struct {
+	s service.StepService
+}
+
+func NewStepHandler(service service.StepService) *StepHandler {
+	return &StepHandler{s: service}
+}
+
+func (h *StepHandler) HandleStepCreation(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var step = new(transfer.StepCreation)
+	var err = parseRequestBody(w, r, step)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = step.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	insertedID, err := h.s.Save(userID, taskID, step)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": insertedID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *StepHandler) HandleStepsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	steps, err := h.s.Fetch(userID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(steps)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// stepsTarget returns the location of all the steps of a task.
+func stepsTarget(taskID uuid.UUID) string {
+	return fmt.Sprintf("/me/tasks/%s/steps", taskID)
+}
+
+// doChangeStep performs a step mutation and responds with No Content if the
+// step changed, otherwise it redirects to the steps of the task.
+func (h *StepHandler) doChangeStep(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ownerID, taskID, stepID uuid.UUID) (ok bool, err error),
+) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var stepID = parseParameterToUUID(w, r, "step_uuid")
+	if didNotParse(stepID) {
+		return
+	}
+	ok, err := change(userID, taskID, stepID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, stepsTarget(taskID))
+}
+
+func (h *StepHandler) HandlePartialUpdateOfStep(w http.ResponseWriter, r *http.Request) {
+	var up = new(transfer.StepUpdate)
+	var err = parseRequestBody(w, r, up)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	h.doChangeStep(w, r, func(ownerID, taskID, stepID uuid.UUID) (bool, error) {
+		return h.s.Update(ownerID, taskID, stepID, up)
+	})
+}
+
+func (h *StepHandler) HandleStepAccomplishment(w http.ResponseWriter, r *http.Request) {
+	h.doChangeStep(w, r, h.s.Accomplish)
+}
+
+func (h *StepHandler) HandleStepUnaccomplishment(w http.ResponseWriter, r *http.Request) {
+	h.doChangeStep(w, r, h.s.Unaccomplish)
+}
+
+func (h *StepHandler) HandleStepReordering(w http.ResponseWriter, r *http.Request) {
+	var reorder = new(transfer.StepReorder)
+	var err = parseRequestBody(w, r, reorder)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = reorder.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	h.doChangeStep(w, r, func(ownerID, taskID, stepID uuid.UUID) (bool, error) {
+		return h.s.Reorder(ownerID, taskID, stepID, *reorder.Position)
+	})
+}
+
+func (h *StepHandler) HandleStepDeletion(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var stepID = parseParameterToUUID(w, r, "step_uuid")
+	if didNotParse(stepID) {
+		return
+	}
+	err := h.s.Delete(userID, taskID, stepID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStepHandler_HandleStepCreation(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/tasks/{task_uuid}/steps"
+		serviceMethod = "Save"
+	)
+	var taskID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			insertedID           = uuid.New()
+			creation             = &transfer.StepCreation{Description: "description"}
+			requestBody          = marshal(t, creation)
+			expectedStatusCode   = http.StatusCreated
+			expectedResponseBody = marshal(t, JSON{"inserted_id": insertedID.String()})
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, userID, taskID, creation).Return(insertedID, nil)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("parsing \"task_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var (
+			requestBody            = marshal(t, &transfer.StepCreation{Description: "description"})
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "Invalid UUID length."
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": "x"})
+		var m = mocks.NewStepServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+
+	t.Run("step validation failed on required fields", func(t *testing.T) {
+		var (
+			requestBody            = []byte("{}")
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "[\"Validation for \\\"description\\\" failed on: required.\"]"
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var (
+			expectedError = failure.ErrTaskNotFound
+			requestBody   = marshal(t, &transfer.StepCreation{Description: "description"})
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestStepHandler_HandleStepsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/tasks/{task_uuid}/steps"
+		serviceMethod = "Fetch"
+	)
+	var taskID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			steps = []*model.Step{
+				{UUID: uuid.New(), TaskUUID: taskID, Order: 1, Description: "a", CreatedAt: time.Now()},
+				{UUID: uuid.New(), TaskUUID: taskID, Order: 2, Description: "b", CreatedAt: time.Now()},
+			}
+			expectedStatusCode   = http.StatusOK
+			expectedResponseBody = marshal(t, steps)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, userID, taskID).Return(steps, nil)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+		assert.Empty(t, string(responseBody), "No response body is expected.")
+	})
+}
+
+func TestStepHandler_HandlePartialUpdateOfStep(t *testing.T) {
+	const (
+		method        = "PATCH"
+		target        = "/me/tasks/{task_uuid}/steps/{step_uuid}"
+		serviceMethod = "Update"
+	)
+	var taskID, stepID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			up          = &transfer.StepUpdate{Description: "new description"}
+			requestBody = marshal(t, up)
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": stepID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, userID, taskID, stepID, up).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandlePartialUpdateOfStep(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("nothing changed? take me to the steps", func(t *testing.T) {
+		var requestBody = []byte("{}")
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": stepID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, userID, taskID, stepID, mock.Anything).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandlePartialUpdateOfStep(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/tasks/"+taskID.String()+"/steps")
+	})
+
+	t.Run("parsing \"step_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var requestBody = marshal(t, &transfer.StepUpdate{Description: "new description"})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": "x"})
+		var m = mocks.NewStepServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandlePartialUpdateOfStep(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(responseBody), "Invalid UUID length.")
+	})
+}
+
+func TestStepHandler_StepAccomplishment(t *testing.T) {
+	var taskID, stepID = uuid.New(), uuid.New()
+
+	var cases = []struct {
+		serviceMethod string
+		handle        func(h *StepHandler) http.HandlerFunc
+	}{
+		{"Accomplish", func(h *StepHandler) http.HandlerFunc { return h.HandleStepAccomplishment }},
+		{"Unaccomplish", func(h *StepHandler) http.HandlerFunc { return h.HandleStepUnaccomplishment }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.serviceMethod, func(t *testing.T) {
+			t.Run("success", func(t *testing.T) {
+				var request = httptest.NewRequest("PUT", "/me/tasks/{task_uuid}/steps/{step_uuid}/accomplish", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": stepID.String()})
+				var m = mocks.NewStepServiceMock()
+				m.On(c.serviceMethod, userID, taskID, stepID).Return(true, nil)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewStepHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				assert.Equal(t, http.StatusNoContent, response.StatusCode)
+			})
+
+			t.Run("got an expected service error", func(t *testing.T) {
+				var expectedError = failure.ErrStepNotFound
+				var request = httptest.NewRequest("PUT", "/me/tasks/{task_uuid}/steps/{step_uuid}/accomplish", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": stepID.String()})
+				var m = mocks.NewStepServiceMock()
+				m.On(c.serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewStepHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				var responseBody = extractResponseBody(t, response.Body)
+				assert.Equal(t, expectedError.Status(), response.StatusCode)
+				assert.Contains(t, string(responseBody), expectedError.Details())
+			})
+		})
+	}
+}
+
+func TestStepHandler_HandleStepReordering(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/tasks/{task_uuid}/steps/{step_uuid}/reorder"
+		serviceMethod = "Reorder"
+	)
+	var taskID, stepID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var requestBody = []byte(`{"position": 2}`)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": stepID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, userID, taskID, stepID, uint64(2)).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepReordering(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("reorder validation failed on required fields", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader([]byte("{}")))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": stepID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepReordering(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(responseBody), "[\"Validation for \\\"position\\\" failed on: required.\"]")
+	})
+}
+
+func TestStepHandler_HandleStepDeletion(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/tasks/{task_uuid}/steps/{step_uuid}"
+		serviceMethod = "Delete"
+	)
+	var taskID, stepID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": stepID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, userID, taskID, stepID).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		assert.Empty(t, responseBody)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrStepNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String(), "step_uuid": stepID.String()})
+		var m = mocks.NewStepServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(expectedError)
+		var recorder = httptest.NewRecorder()
+		NewStepHandler(m).HandleStepDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
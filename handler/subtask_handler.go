@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/service"
+)
+
+type SubtaskHandler struct {
+	s service.SubtaskService
+}
+
+func NewSubtaskHandler(service service.SubtaskService) *SubtaskHandler {
+	return &SubtaskHandler{s: service}
+}
+
+func (h *SubtaskHandler) HandleSubtaskCreation(w http.ResponseWriter, r *http.Request) {
+	var task = new(transfer.TaskCreation)
+	var err = parseRequestBody(w, r, task)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = task.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var parentID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(parentID) {
+		return
+	}
+	insertedID, err := h.s.Save(userID, listID, parentID, task)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": insertedID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *SubtaskHandler) HandleSubtaskTreeRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	tree, err := h.s.FetchTree(userID, listID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(tree)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *SubtaskHandler) HandleStepPromotion(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var stepID = parseParameterToUUID(w, r, "step_uuid")
+	if didNotParse(stepID) {
+		return
+	}
+	insertedID, err := h.s.PromoteStep(userID, listID, taskID, stepID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": insertedID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
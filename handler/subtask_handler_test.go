@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtaskHandler_HandleSubtaskCreation(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/subtasks"
+		serviceMethod = "Save"
+	)
+	var (
+		listID, parentID = uuid.New(), uuid.New()
+		creation         = &transfer.TaskCreation{Title: "Buy paint"}
+		pathParameters   = parameters{"list_uuid": listID.String(), "task_uuid": parentID.String()}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			insertedID           = uuid.New()
+			expectedResponseBody = marshal(t, JSON{"inserted_id": insertedID.String()})
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, creation)))
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewSubtaskServiceMock()
+		m.On(serviceMethod, userID, listID, parentID, creation).Return(insertedID, nil)
+		var recorder = httptest.NewRecorder()
+		NewSubtaskHandler(m).HandleSubtaskCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrSubtaskTooDeep.Clone().FormatDetails(5)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, creation)))
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewSubtaskServiceMock()
+		m.On(serviceMethod, userID, listID, parentID, creation).Return(uuid.Nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewSubtaskHandler(m).HandleSubtaskCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestSubtaskHandler_HandleSubtaskTreeRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/subtasks"
+		serviceMethod = "FetchTree"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			tree = &model.TaskTree{
+				Task:     &model.Task{UUID: taskID, ListUUID: listID, Title: "Paint the fence"},
+				Progress: 0.5,
+				Subtasks: []*model.TaskTree{},
+			}
+			expectedResponseBody = marshal(t, tree)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewSubtaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID).Return(tree, nil)
+		var recorder = httptest.NewRecorder()
+		NewSubtaskHandler(m).HandleSubtaskTreeRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+}
+
+func TestSubtaskHandler_HandleStepPromotion(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/steps/{step_uuid}/promote"
+		serviceMethod = "PromoteStep"
+	)
+	var (
+		listID, taskID, stepID = uuid.New(), uuid.New(), uuid.New()
+		pathParameters         = parameters{"list_uuid": listID.String(), "task_uuid": taskID.String(), "step_uuid": stepID.String()}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			insertedID           = uuid.New()
+			expectedResponseBody = marshal(t, JSON{"inserted_id": insertedID.String()})
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewSubtaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, stepID).Return(insertedID, nil)
+		var recorder = httptest.NewRecorder()
+		NewSubtaskHandler(m).HandleStepPromotion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("step not found", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, pathParameters)
+		var m = mocks.NewSubtaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, stepID).Return(uuid.Nil, failure.ErrStepNotFound)
+		var recorder = httptest.NewRecorder()
+		NewSubtaskHandler(m).HandleStepPromotion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	})
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/service"
+
+	"github.com/google/uuid"
+)
+
+type TagHandler struct {
+	s service.TagService
+}
+
+func NewTagHandler(service service.TagService) *TagHandler {
+	return &TagHandler{s: service}
+}
+
+func (h *TagHandler) HandleTagCreation(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var tag = new(transfer.TagCreation)
+	var err = parseRequestBody(w, r, tag)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = tag.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	insertedID, err := h.s.Save(userID, tag)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": insertedID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *TagHandler) HandleTagsRetrieval(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	var search, sortExpr = extractQueryParameter(r, "search", ""), extractSorting(w, r)
+	if "?" == sortExpr {
+		return
+	}
+	result, err := h.s.Fetch(userID, pagination, search, sortExpr)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *TagHandler) HandleTagRetrievalByID(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var tagID = parseParameterToUUID(w, r, "tag_uuid")
+	if didNotParse(tagID) {
+		return
+	}
+	tag, err := h.s.FetchByID(userID, tagID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(tag)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *TagHandler) HandlePartialUpdateOfTag(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var tagID = parseParameterToUUID(w, r, "tag_uuid")
+	if didNotParse(tagID) {
+		return
+	}
+	var target = "/me/tags/" + tagID.String()
+	var up = new(transfer.TagUpdate)
+	var err = parseRequestBody(w, r, up)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = up.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	ok, err := h.s.Update(userID, tagID, up)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, target)
+}
+
+func (h *TagHandler) HandleTagDeletion(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var tagID = parseParameterToUUID(w, r, "tag_uuid")
+	if didNotParse(tagID) {
+		return
+	}
+	err := h.s.Delete(userID, tagID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TagHandler) HandleRetrievalOfTaskTags(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	tags, err := h.s.FetchFromTask(userID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(tags)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// doTagTask attaches or detaches a tag from a task and responds with No Content
+// if anything changed, otherwise it redirects to the tags of the task.
+func (h *TagHandler) doTagTask(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ownerID, taskID, tagID uuid.UUID) (ok bool, err error),
+) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var tagID = parseParameterToUUID(w, r, "tag_uuid")
+	if didNotParse(tagID) {
+		return
+	}
+	ok, err := change(userID, taskID, tagID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, fmt.Sprintf("/me/tasks/%s/tags", taskID))
+}
+
+func (h *TagHandler) HandleTagAttachment(w http.ResponseWriter, r *http.Request) {
+	h.doTagTask(w, r, h.s.Attach)
+}
+
+func (h *TagHandler) HandleTagDetachment(w http.ResponseWriter, r *http.Request) {
+	h.doTagTask(w, r, h.s.Detach)
+}
@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTagHandler_HandleTagCreation(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/tags"
+		serviceMethod = "Save"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			insertedID           = uuid.New()
+			creation             = &transfer.TagCreation{Name: "work", Description: "work stuff", Color: "#ff0000"}
+			requestBody          = marshal(t, creation)
+			expectedStatusCode   = http.StatusCreated
+			expectedResponseBody = marshal(t, JSON{"inserted_id": insertedID.String()})
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		var m = mocks.NewTagServiceMock()
+		m.On(serviceMethod, userID, creation).Return(insertedID, nil)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("tag validation failed on hexcolor", func(t *testing.T) {
+		var (
+			requestBody            = marshal(t, &transfer.TagCreation{Name: "work", Description: "work stuff", Color: "red"})
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "[\"Validation for \\\"color\\\" failed on: hexcolor.\"]"
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		var m = mocks.NewTagServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var requestBody = marshal(t, &transfer.TagCreation{Name: "work", Description: "work stuff", Color: "#ff0000"})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		var m = mocks.NewTagServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagCreation(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
+
+func TestTagHandler_HandleTagsRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/tags"
+		serviceMethod = "Fetch"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination = types.Pagination{Page: 1, RPP: 10}
+			tags       = []*model.Tag{
+				{UUID: uuid.New(), Name: "work", Color: "#ff0000", CreatedAt: time.Now()},
+				{UUID: uuid.New(), Name: "home", Color: "#00ff00", CreatedAt: time.Now()},
+			}
+			result               = &types.Result[model.Tag]{Page: 1, RPP: 10, Retrieved: 2, Payload: tags}
+			expectedResponseBody = marshal(t, result)
+		)
+		var request = httptest.NewRequest(method, target+"?"+url.Values{"search": {"w"}, "sort_by": {"+name"}}.Encode(), nil)
+		withLoggedUser(&request)
+		var m = mocks.NewTagServiceMock()
+		m.On(serviceMethod, userID, &pagination, "w", "+name").Return(result, nil)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("bad pagination", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target+"?page=x", nil)
+		withLoggedUser(&request)
+		var m = mocks.NewTagServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagsRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestTagHandler_HandleTagRetrievalByID(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/tags/{tag_uuid}"
+		serviceMethod = "FetchByID"
+	)
+	var tagID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			tag                  = &model.Tag{UUID: tagID, Name: "work", Color: "#ff0000", CreatedAt: time.Now()}
+			expectedResponseBody = marshal(t, tag)
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"tag_uuid": tagID.String()})
+		var m = mocks.NewTagServiceMock()
+		m.On(serviceMethod, userID, tagID).Return(tag, nil)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagRetrievalByID(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrTagNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"tag_uuid": tagID.String()})
+		var m = mocks.NewTagServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything).Return(nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagRetrievalByID(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestTagHandler_HandlePartialUpdateOfTag(t *testing.T) {
+	const (
+		method        = "PATCH"
+		target        = "/me/tags/{tag_uuid}"
+		serviceMethod = "Update"
+	)
+	var tagID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			up          = &transfer.TagUpdate{Name: "home"}
+			requestBody = marshal(t, up)
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"tag_uuid": tagID.String()})
+		var m = mocks.NewTagServiceMock()
+		m.On(serviceMethod, userID, tagID, up).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandlePartialUpdateOfTag(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("nothing changed? take me to the tag", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader([]byte("{}")))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"tag_uuid": tagID.String()})
+		var m = mocks.NewTagServiceMock()
+		m.On(serviceMethod, userID, tagID, mock.Anything).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandlePartialUpdateOfTag(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), "/me/tags/"+tagID.String())
+	})
+
+	t.Run("tag validation failed on hexcolor", func(t *testing.T) {
+		var requestBody = marshal(t, &transfer.TagUpdate{Color: "#zz"})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"tag_uuid": tagID.String()})
+		var m = mocks.NewTagServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandlePartialUpdateOfTag(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestTagHandler_HandleTagDeletion(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/tags/{tag_uuid}"
+		serviceMethod = "Delete"
+	)
+	var tagID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"tag_uuid": tagID.String()})
+		var m = mocks.NewTagServiceMock()
+		m.On(serviceMethod, userID, tagID).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("parsing \"tag_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"tag_uuid": "x"})
+		var m = mocks.NewTagServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTagHandler(m).HandleTagDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(responseBody), "Invalid UUID length.")
+	})
+}
+
+func TestTagHandler_TaskTagging(t *testing.T) {
+	var taskID, tagID = uuid.New(), uuid.New()
+
+	var cases = []struct {
+		serviceMethod string
+		method        string
+		handle        func(h *TagHandler) http.HandlerFunc
+	}{
+		{"Attach", "PUT", func(h *TagHandler) http.HandlerFunc { return h.HandleTagAttachment }},
+		{"Detach", "DELETE", func(h *TagHandler) http.HandlerFunc { return h.HandleTagDetachment }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.serviceMethod, func(t *testing.T) {
+			t.Run("success", func(t *testing.T) {
+				var request = httptest.NewRequest(c.method, "/me/tasks/{task_uuid}/tags/{tag_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"task_uuid": taskID.String(), "tag_uuid": tagID.String()})
+				var m = mocks.NewTagServiceMock()
+				m.On(c.serviceMethod, userID, taskID, tagID).Return(true, nil)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTagHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				assert.Equal(t, http.StatusNoContent, response.StatusCode)
+			})
+
+			t.Run("nothing changed? take me to the task tags", func(t *testing.T) {
+				var request = httptest.NewRequest(c.method, "/me/tasks/{task_uuid}/tags/{tag_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"task_uuid": taskID.String(), "tag_uuid": tagID.String()})
+				var m = mocks.NewTagServiceMock()
+				m.On(c.serviceMethod, userID, taskID, tagID).Return(false, nil)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTagHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+				assert.Contains(t, response.Header.Get("Location"), "/me/tasks/"+taskID.String()+"/tags")
+			})
+
+			t.Run("got an expected service error", func(t *testing.T) {
+				var expectedError = failure.ErrTagNotFound
+				var request = httptest.NewRequest(c.method, "/me/tasks/{task_uuid}/tags/{tag_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"task_uuid": taskID.String(), "tag_uuid": tagID.String()})
+				var m = mocks.NewTagServiceMock()
+				m.On(c.serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTagHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				var responseBody = extractResponseBody(t, response.Body)
+				assert.Equal(t, expectedError.Status(), response.StatusCode)
+				assert.Contains(t, string(responseBody), expectedError.Details())
+			})
+		})
+	}
+}
@@ -2,9 +2,13 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"noda/data/model"
+	"noda/data/types"
 	"noda/failure"
+	"strconv"
 
 	"github.com/google/uuid"
 
@@ -40,7 +44,7 @@ func (h *TaskHandler) doCreateTask(belongsToAList bool, w http.ResponseWriter, r
 			return
 		}
 	}
-	insertedTaskID, err := h.s.Save(userID, listID, task)
+	insertedTaskID, err := audited(h.s, r).Save(userID, listID, task)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -62,3 +66,478 @@ func (h *TaskHandler) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
 func (h *TaskHandler) HandleCreateTaskForTodayList(w http.ResponseWriter, r *http.Request) {
 	h.doCreateTask(false, w, r)
 }
+
+func (h *TaskHandler) HandleTaskDuplication(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	replicaID, err := audited(h.s, r).Duplicate(userID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	var result = map[string]string{"inserted_id": replicaID.String()}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (h *TaskHandler) HandleTaskRetrievalByID(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return
+	}
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	task, err := h.s.FetchByID(userID, listID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(task)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type specialList uint8
+
+const (
+	none      specialList = 0
+	today     specialList = 1
+	tomorrow  specialList = 2
+	deferred  specialList = 3
+	all       specialList = 4
+	searched  specialList = 5
+	completed specialList = 6
+	archived  specialList = 7
+	trashed   specialList = 8
+)
+
+func (h *TaskHandler) doRetrieveTasks(l specialList, w http.ResponseWriter, r *http.Request) {
+	var (
+		userID, _ = extractUserPayload(r)
+		listID    = uuid.Nil
+	)
+	if none == l {
+		listID = parseParameterToUUID(w, r, "list_uuid")
+		if didNotParse(listID) {
+			return
+		}
+	}
+	var pagination = parsePagination(w, r)
+	if nil == pagination {
+		return
+	}
+	var search = extractQueryParameter(r, "search", "")
+	if searched == l {
+		search = extractQueryParameter(r, "q", "")
+		if "" == search {
+			failure.EmitError(w, failure.ErrBadQueryParameter.
+				Clone().
+				SetDetails("The parameter \"q\" is required."))
+			return
+		}
+	}
+	var sortExpr = extractSorting(w, r)
+	if "?" == sortExpr {
+		return
+	}
+	var filter, ok = parseTagFilter(w, r)
+	if !ok {
+		return
+	}
+	var assigneeID = uuid.Nil
+	if none == l {
+		assigneeID, ok = parseAssignee(w, r, userID)
+		if !ok {
+			return
+		}
+	}
+	var (
+		result *types.Result[model.Task]
+		err    error
+	)
+	switch l {
+	case today:
+		result, err = h.s.FetchFromToday(userID, pagination, search, sortExpr, filter)
+	case tomorrow:
+		result, err = h.s.FetchFromTomorrow(userID, pagination, search, sortExpr, filter)
+	case deferred:
+		result, err = h.s.FetchFromDeferred(userID, pagination, search, sortExpr)
+	case all, searched:
+		result, err = h.s.FetchAll(userID, pagination, search, sortExpr)
+	case completed:
+		result, err = h.s.FetchCompleted(userID, pagination, search, sortExpr)
+	case archived:
+		result, err = h.s.FetchArchived(userID, pagination, search, sortExpr)
+	case trashed:
+		result, err = h.s.FetchTrashed(userID, pagination, search, sortExpr)
+	default:
+		result, err = h.s.Fetch(userID, listID, pagination, search, sortExpr, filter, assigneeID)
+	}
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(result)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *TaskHandler) HandleTasksRetrieval(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(none, w, r)
+}
+
+func (h *TaskHandler) HandleRetrievalOfTasksFromToday(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(today, w, r)
+}
+
+func (h *TaskHandler) HandleRetrievalOfTasksFromTomorrow(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(tomorrow, w, r)
+}
+
+func (h *TaskHandler) HandleRetrievalOfDeferredTasks(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(deferred, w, r)
+}
+
+func (h *TaskHandler) HandleRetrievalOfAllTasks(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(all, w, r)
+}
+
+func (h *TaskHandler) HandleTaskSearch(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(searched, w, r)
+}
+
+func (h *TaskHandler) HandleRetrievalOfCompletedTasks(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(completed, w, r)
+}
+
+func (h *TaskHandler) HandleRetrievalOfArchivedTasks(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(archived, w, r)
+}
+
+func (h *TaskHandler) HandleRetrievalOfTrashedTasks(w http.ResponseWriter, r *http.Request) {
+	h.doRetrieveTasks(trashed, w, r)
+}
+
+// WithListOfTask serves a route that addresses a task by its ID alone with a
+// handler of the routes under its list: the list of the task is looked up and
+// set as the "list_uuid" path parameter.
+func (h *TaskHandler) WithListOfTask(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userID, _ = extractUserPayload(r)
+		var taskID = parseParameterToUUID(w, r, "task_uuid")
+		if didNotParse(taskID) {
+			return
+		}
+		listID, err := h.s.Locate(userID, taskID)
+		if gotAndHandledServiceError(w, err) {
+			return
+		}
+		r.SetPathValue("list_uuid", listID.String())
+		next(w, r)
+	}
+}
+
+// taskTarget returns the canonical location of a task within a list.
+func taskTarget(listID, taskID uuid.UUID) string {
+	return fmt.Sprintf("/me/lists/%s/tasks/%s", listID, taskID)
+}
+
+// parseListAndTaskIDs parses the "list_uuid" and "task_uuid" path parameters.
+// If any of them could not be parsed, an error is emitted and ok is false.
+func parseListAndTaskIDs(w http.ResponseWriter, r *http.Request) (listID, taskID uuid.UUID, ok bool) {
+	listID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(listID) {
+		return uuid.Nil, uuid.Nil, false
+	}
+	taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return listID, taskID, true
+}
+
+// doChangeTask performs a task mutation and responds with No Content if the
+// task changed, otherwise it redirects to the task.
+func (h *TaskHandler) doChangeTask(
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ownerID, listID, taskID uuid.UUID) (ok bool, err error),
+) {
+	var userID, _ = extractUserPayload(r)
+	listID, taskID, parsed := parseListAndTaskIDs(w, r)
+	if !parsed {
+		return
+	}
+	ok, err := change(userID, listID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, taskTarget(listID, taskID))
+}
+
+func (h *TaskHandler) HandlePartialUpdateOfTask(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	listID, taskID, parsed := parseListAndTaskIDs(w, r)
+	if !parsed {
+		return
+	}
+	var up = new(transfer.TaskUpdate)
+	var err = parseRequestBody(w, r, up)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	if "" == up.Title && "" == up.Headline && "" == up.Description {
+		redirect(w, r, taskTarget(listID, taskID))
+		return
+	}
+	ok, err := audited(h.s, r).Update(userID, listID, taskID, up)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, taskTarget(listID, taskID))
+}
+
+func (h *TaskHandler) HandleTaskReordering(w http.ResponseWriter, r *http.Request) {
+	var reorder = new(transfer.TaskReorder)
+	var err = parseRequestBody(w, r, reorder)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = reorder.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	h.doChangeTask(w, r, func(ownerID, listID, taskID uuid.UUID) (bool, error) {
+		return audited(h.s, r).Reorder(ownerID, listID, taskID, *reorder.Position)
+	})
+}
+
+func (h *TaskHandler) HandleSetTaskReminder(w http.ResponseWriter, r *http.Request) {
+	var up = new(transfer.TaskReminderUpdate)
+	var err = parseRequestBody(w, r, up)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = up.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	h.doChangeTask(w, r, func(ownerID, listID, taskID uuid.UUID) (bool, error) {
+		return audited(h.s, r).SetReminder(ownerID, listID, taskID, up.RemindAt)
+	})
+}
+
+func (h *TaskHandler) HandleSetTaskPriority(w http.ResponseWriter, r *http.Request) {
+	var up = new(transfer.TaskPriorityUpdate)
+	var err = parseRequestBody(w, r, up)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = up.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	h.doChangeTask(w, r, func(ownerID, listID, taskID uuid.UUID) (bool, error) {
+		return audited(h.s, r).SetPriority(ownerID, listID, taskID, up.Priority)
+	})
+}
+
+func (h *TaskHandler) HandleSetTaskDueDate(w http.ResponseWriter, r *http.Request) {
+	var up = new(transfer.TaskDueDateUpdate)
+	var err = parseRequestBody(w, r, up)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = up.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	h.doChangeTask(w, r, func(ownerID, listID, taskID uuid.UUID) (bool, error) {
+		return audited(h.s, r).SetDueDate(ownerID, listID, taskID, up.DueDate)
+	})
+}
+
+func (h *TaskHandler) HandleSetTaskRecurrence(w http.ResponseWriter, r *http.Request) {
+	var up = new(transfer.TaskRecurrenceUpdate)
+	var err = parseRequestBody(w, r, up)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	err = up.Validate()
+	if nil != err {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	h.doChangeTask(w, r, func(ownerID, listID, taskID uuid.UUID) (bool, error) {
+		return audited(h.s, r).SetRecurrence(ownerID, listID, taskID, up.Rule)
+	})
+}
+
+func (h *TaskHandler) HandleTaskRecurrenceRemoval(w http.ResponseWriter, r *http.Request) {
+	h.doChangeTask(w, r, audited(h.s, r).RemoveRecurrence)
+}
+
+func (h *TaskHandler) HandleRetrievalOfTaskOccurrences(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	listID, taskID, parsed := parseListAndTaskIDs(w, r)
+	if !parsed {
+		return
+	}
+	count, err := strconv.Atoi(extractQueryParameter(r, "count", "10"))
+	if nil != err || count < 1 || service.MaxOccurrences < count {
+		var details = fmt.Sprintf("The parameter \"count\" must be a number between 1 and %d.", service.MaxOccurrences)
+		failure.EmitError(w, failure.ErrBadQueryParameter.Clone().SetDetails(details))
+		return
+	}
+	occurrences, err := h.s.Occurrences(userID, listID, taskID, count)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(occurrences)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h *TaskHandler) HandleTaskCompletion(w http.ResponseWriter, r *http.Request) {
+	h.doChangeTask(w, r, audited(h.s, r).Complete)
+}
+
+func (h *TaskHandler) HandleTaskResumption(w http.ResponseWriter, r *http.Request) {
+	h.doChangeTask(w, r, audited(h.s, r).Resume)
+}
+
+func (h *TaskHandler) HandleTaskPinning(w http.ResponseWriter, r *http.Request) {
+	h.doChangeTask(w, r, audited(h.s, r).Pin)
+}
+
+func (h *TaskHandler) HandleTaskUnpinning(w http.ResponseWriter, r *http.Request) {
+	h.doChangeTask(w, r, audited(h.s, r).Unpin)
+}
+
+func (h *TaskHandler) HandleTaskTrashing(w http.ResponseWriter, r *http.Request) {
+	h.doChangeTask(w, r, audited(h.s, r).Trash)
+}
+
+func (h *TaskHandler) HandleTaskRestorationFromTrash(w http.ResponseWriter, r *http.Request) {
+	h.doChangeTask(w, r, audited(h.s, r).RestoreFromTrash)
+}
+
+func (h *TaskHandler) HandleTaskDeletion(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	listID, taskID, parsed := parseListAndTaskIDs(w, r)
+	if !parsed {
+		return
+	}
+	err := audited(h.s, r).Delete(userID, listID, taskID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TaskHandler) HandleTaskMovement(w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var targetListID = parseParameterToUUID(w, r, "list_uuid")
+	if didNotParse(targetListID) {
+		return
+	}
+	ok, err := audited(h.s, r).Move(userID, taskID, targetListID)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, taskTarget(targetListID, taskID))
+}
+
+func (h *TaskHandler) doMoveTaskToSpecialList(l specialList, w http.ResponseWriter, r *http.Request) {
+	var userID, _ = extractUserPayload(r)
+	var taskID = parseParameterToUUID(w, r, "task_uuid")
+	if didNotParse(taskID) {
+		return
+	}
+	var (
+		ok     bool
+		err    error
+		target string
+	)
+	switch l {
+	case tomorrow:
+		ok, err = audited(h.s, r).Tomorrow(userID, taskID)
+		target = "/me/tomorrow"
+	case deferred:
+		ok, err = audited(h.s, r).Defer(userID, taskID)
+		target = "/me/deferred"
+	default:
+		ok, err = audited(h.s, r).Today(userID, taskID)
+		target = "/me/today"
+	}
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	if ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	redirect(w, r, target)
+}
+
+func (h *TaskHandler) HandleMoveTaskToToday(w http.ResponseWriter, r *http.Request) {
+	h.doMoveTaskToSpecialList(today, w, r)
+}
+
+func (h *TaskHandler) HandleMoveTaskToTomorrow(w http.ResponseWriter, r *http.Request) {
+	h.doMoveTaskToSpecialList(tomorrow, w, r)
+}
+
+func (h *TaskHandler) HandleTaskDeferral(w http.ResponseWriter, r *http.Request) {
+	h.doMoveTaskToSpecialList(deferred, w, r)
+}
@@ -8,9 +8,13 @@ import (
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"noda/data/model"
 	"noda/data/transfer"
 	"noda/data/types"
+	"noda/failure"
 	"noda/mocks"
+	"strconv"
 	"testing"
 	"time"
 )
@@ -133,3 +137,1109 @@ func TestTaskHandler_HandleCreateTaskForTodayList(t *testing.T) {
 		assert.Empty(t, string(responseBody))
 	})
 }
+
+func TestTaskHandler_HandleTaskDuplication(t *testing.T) {
+	const (
+		method        = "POST"
+		target        = "/me/tasks/{task_uuid}/duplicate"
+		serviceMethod = "Duplicate"
+	)
+	var taskID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			replicaID            = uuid.New()
+			expectedStatusCode   = http.StatusCreated
+			expectedResponseBody = marshal(t, JSON{"inserted_id": replicaID.String()})
+		)
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, taskID).Return(replicaID, nil)
+		NewTaskHandler(m).HandleTaskDuplication(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("parsing \"task_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var (
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "Invalid UUID length."
+		)
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": "x"})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		NewTaskHandler(m).HandleTaskDuplication(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var (
+			expectedError      = failure.ErrTaskNotFound
+			expectedStatusCode = expectedError.Status()
+		)
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything).Return(nil, expectedError)
+		NewTaskHandler(m).HandleTaskDuplication(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestTaskHandler_HandleTaskRetrievalByID(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}"
+		serviceMethod = "FetchByID"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			task = &model.Task{
+				UUID:      taskID,
+				OwnerUUID: userID,
+				ListUUID:  listID,
+				Title:     "Title",
+				Priority:  types.TaskPriorityHigh,
+				Status:    types.TaskStatusIncomplete,
+			}
+			expectedStatusCode   = http.StatusOK
+			expectedResponseBody = marshal(t, task)
+		)
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID).Return(task, nil)
+		NewTaskHandler(m).HandleTaskRetrievalByID(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+		assert.Empty(t, response.Header, "No header is expected, but got: %d.", len(response.Header))
+	})
+
+	t.Run("parsing \"list_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var (
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "Invalid UUID length."
+		)
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": "x", "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		NewTaskHandler(m).HandleTaskRetrievalByID(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+
+	t.Run("parsing \"task_uuid\" failed: UUID is too short", func(t *testing.T) {
+		var (
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "Invalid UUID length."
+		)
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": "x"})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		NewTaskHandler(m).HandleTaskRetrievalByID(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var (
+			expectedError      = failure.ErrTaskNotFound
+			expectedStatusCode = expectedError.Status()
+		)
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(nil, expectedError)
+		NewTaskHandler(m).HandleTaskRetrievalByID(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
+		NewTaskHandler(m).HandleTaskRetrievalByID(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+		assert.Empty(t, string(responseBody), "No response body is expected.")
+	})
+}
+
+func TestTaskHandler_HandleTasksRetrieval(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/tasks"
+		serviceMethod = "Fetch"
+	)
+	var listID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination = types.Pagination{Page: 2, RPP: 5}
+			search     = "a"
+			sortExpr   = "-title"
+			values     = url.Values{
+				"search":  []string{search},
+				"sort_by": []string{sortExpr},
+				"page":    []string{strconv.FormatInt(pagination.Page, 10)},
+				"rpp":     []string{strconv.FormatInt(pagination.RPP, 10)},
+			}
+			serviceResult = &types.Result[model.Task]{
+				Page:      pagination.Page,
+				RPP:       pagination.RPP,
+				Retrieved: 2,
+				Payload:   make([]*model.Task, 2),
+			}
+			expectedStatusCode   = http.StatusOK
+			expectedResponseBody = string(marshal(t, serviceResult))
+		)
+		var request = httptest.NewRequest(method, target+"?"+values.Encode(), nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, &pagination, search, sortExpr, (*types.TagFilter)(nil), uuid.Nil).Return(serviceResult, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTasksRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Equal(t, expectedResponseBody, string(responseBody))
+		assert.Empty(t, response.Header, "No header is expected, but got: %d.", len(response.Header))
+	})
+
+	t.Run("could not parse pagination: negative number", func(t *testing.T) {
+		var (
+			values               = url.Values{"rpp": []string{"-1"}}
+			expectedStatusCode   = http.StatusBadRequest
+			expectedResponseBody = "The parameter \\\"rpp\\\" must be a positive number."
+		)
+		var request = httptest.NewRequest(method, target+"?"+values.Encode(), nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTasksRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedResponseBody)
+	})
+
+	t.Run("could not parse sort expression", func(t *testing.T) {
+		var (
+			values             = url.Values{"sort_by": []string{"title"}}
+			expectedStatusCode = http.StatusBadRequest
+		)
+		var request = httptest.NewRequest(method, target+"?"+values.Encode(), nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTasksRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+	})
+
+	t.Run("filters by tags", func(t *testing.T) {
+		var (
+			pagination = types.Pagination{Page: 1, RPP: 10}
+			tags       = []uuid.UUID{uuid.New(), uuid.New()}
+			values     = url.Values{
+				"tags":  []string{tags[0].String() + "," + tags[1].String()},
+				"match": []string{"all"},
+			}
+			filter             = &types.TagFilter{Tags: tags, MatchAll: true}
+			serviceResult      = &types.Result[model.Task]{Page: 1, RPP: 10}
+			expectedStatusCode = http.StatusOK
+		)
+		var request = httptest.NewRequest(method, target+"?"+values.Encode(), nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, &pagination, "", "", filter, uuid.Nil).Return(serviceResult, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTasksRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+	})
+
+	t.Run("filters by assignee", func(t *testing.T) {
+		var assigneeID = uuid.New()
+		var cases = []struct {
+			assignee string
+			expected uuid.UUID
+		}{
+			{"me", userID},
+			{assigneeID.String(), assigneeID},
+		}
+		for _, c := range cases {
+			var request = httptest.NewRequest(method, target+"?assignee="+c.assignee, nil)
+			withLoggedUser(&request)
+			withPathParameters(&request, parameters{"list_uuid": listID.String()})
+			var m = mocks.NewTaskServiceMock()
+			m.On(serviceMethod, userID, listID, &types.Pagination{Page: 1, RPP: 10}, "", "", (*types.TagFilter)(nil), c.expected).
+				Return(&types.Result[model.Task]{Page: 1, RPP: 10}, nil)
+			var recorder = httptest.NewRecorder()
+			NewTaskHandler(m).HandleTasksRetrieval(recorder, request)
+			var response = recorder.Result()
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			response.Body.Close()
+		}
+	})
+
+	t.Run("could not parse assignee", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target+"?assignee=x", nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTasksRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(responseBody), "must be either")
+	})
+
+	t.Run("could not parse tag filter", func(t *testing.T) {
+		var cases = []struct {
+			name                   string
+			values                 url.Values
+			expectedInResponseBody string
+		}{
+			{"invalid UUID", url.Values{"tags": []string{"x"}}, "contains an invalid UUID"},
+			{"bad match", url.Values{"tags": []string{uuid.NewString()}, "match": []string{"some"}}, "must be either"},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				var request = httptest.NewRequest(method, target+"?"+c.values.Encode(), nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"list_uuid": listID.String()})
+				var m = mocks.NewTaskServiceMock()
+				m.AssertNotCalled(t, serviceMethod)
+				var recorder = httptest.NewRecorder()
+				NewTaskHandler(m).HandleTasksRetrieval(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				var responseBody = extractResponseBody(t, response.Body)
+				assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+				assert.Contains(t, string(responseBody), c.expectedInResponseBody)
+			})
+		}
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var (
+			expectedError      = failure.ErrListNotFound
+			expectedStatusCode = expectedError.Status()
+		)
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTasksRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestTaskHandler_HandleRetrievalOfTasksFromSpecialLists(t *testing.T) {
+	var (
+		pagination    = types.Pagination{Page: 1, RPP: 10}
+		serviceResult = &types.Result[model.Task]{
+			Page:      pagination.Page,
+			RPP:       pagination.RPP,
+			Retrieved: 1,
+			Payload:   make([]*model.Task, 1),
+		}
+	)
+
+	var cases = []struct {
+		target        string
+		serviceMethod string
+		filterable    bool
+		needle        string
+		handle        func(h *TaskHandler) http.HandlerFunc
+	}{
+		{"/me/today", "FetchFromToday", true, "", func(h *TaskHandler) http.HandlerFunc { return h.HandleRetrievalOfTasksFromToday }},
+		{"/me/tomorrow", "FetchFromTomorrow", true, "", func(h *TaskHandler) http.HandlerFunc { return h.HandleRetrievalOfTasksFromTomorrow }},
+		{"/me/deferred", "FetchFromDeferred", false, "", func(h *TaskHandler) http.HandlerFunc { return h.HandleRetrievalOfDeferredTasks }},
+		{"/me/tasks", "FetchAll", false, "", func(h *TaskHandler) http.HandlerFunc { return h.HandleRetrievalOfAllTasks }},
+		{"/me/tasks/search?q=report", "FetchAll", false, "report", func(h *TaskHandler) http.HandlerFunc { return h.HandleTaskSearch }},
+		{"/me/tasks/completed", "FetchCompleted", false, "", func(h *TaskHandler) http.HandlerFunc { return h.HandleRetrievalOfCompletedTasks }},
+		{"/me/tasks/archived", "FetchArchived", false, "", func(h *TaskHandler) http.HandlerFunc { return h.HandleRetrievalOfArchivedTasks }},
+		{"/me/tasks/trashed", "FetchTrashed", false, "", func(h *TaskHandler) http.HandlerFunc { return h.HandleRetrievalOfTrashedTasks }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.target, func(t *testing.T) {
+			var expectedResponseBody = string(marshal(t, serviceResult))
+			var request = httptest.NewRequest("GET", c.target, nil)
+			withLoggedUser(&request)
+			var m = mocks.NewTaskServiceMock()
+			var arguments = []any{userID, &pagination, c.needle, ""}
+			if c.filterable {
+				arguments = append(arguments, (*types.TagFilter)(nil))
+			}
+			m.On(c.serviceMethod, arguments...).Return(serviceResult, nil)
+			var recorder = httptest.NewRecorder()
+			c.handle(NewTaskHandler(m))(recorder, request)
+			var response = recorder.Result()
+			defer response.Body.Close()
+			var responseBody = extractResponseBody(t, response.Body)
+			assert.Equal(t, http.StatusOK, response.StatusCode)
+			assert.Equal(t, expectedResponseBody, string(responseBody))
+		})
+	}
+
+	t.Run("search without needle", func(t *testing.T) {
+		var request = httptest.NewRequest("GET", "/me/tasks/search", nil)
+		withLoggedUser(&request)
+		var m = mocks.NewTaskServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskSearch(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		m.AssertNotCalled(t, "FetchAll")
+	})
+}
+
+func TestTaskHandler_WithListOfTask(t *testing.T) {
+	const target = "/me/tasks/{task_uuid}"
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest("GET", target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On("Locate", userID, taskID).Return(listID, nil)
+		var located string
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).WithListOfTask(func(w http.ResponseWriter, r *http.Request) {
+			located = r.PathValue("list_uuid")
+			w.WriteHeader(http.StatusNoContent)
+		})(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		assert.Equal(t, listID.String(), located)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		var request = httptest.NewRequest("GET", target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On("Locate", userID, taskID).Return(nil, failure.ErrTaskNotFound)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).WithListOfTask(func(w http.ResponseWriter, r *http.Request) {
+			assert.Fail(t, "the handler of the list should not be reached")
+		})(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	})
+}
+
+func TestTaskHandler_HandlePartialUpdateOfTask(t *testing.T) {
+	const (
+		method        = "PATCH"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}"
+		serviceMethod = "Update"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			up                 = &transfer.TaskUpdate{Title: "new title"}
+			requestBody        = marshal(t, up)
+			expectedStatusCode = http.StatusNoContent
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, up).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandlePartialUpdateOfTask(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Empty(t, string(responseBody), "No response body is expected.")
+		assert.Empty(t, response.Header, "No header is expected, but got: %d.", len(response.Header))
+	})
+
+	t.Run("body = {}? take me to the already existent task", func(t *testing.T) {
+		var (
+			requestBody        = []byte(" { } ")
+			expectedStatusCode = http.StatusSeeOther
+			expectedLocation   = "http://example.com/me/lists/" + listID.String() + "/tasks/" + taskID.String()
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandlePartialUpdateOfTask(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Equal(t, expectedLocation, response.Header.Get("Location"))
+	})
+
+	t.Run("could not decode JSON body: il-formed JSON", func(t *testing.T) {
+		var (
+			requestBody            = []byte("{")
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "Body contains ill-formed JSON."
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandlePartialUpdateOfTask(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var requestBody = marshal(t, &transfer.TaskUpdate{Title: "new title"})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(false, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandlePartialUpdateOfTask(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+		assert.Empty(t, string(responseBody), "No response body is expected.")
+	})
+}
+
+func TestTaskHandler_HandleTaskReordering(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/reorder"
+		serviceMethod = "Reorder"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var requestBody = []byte(`{"position": 3}`)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, uint64(3)).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskReordering(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("first position", func(t *testing.T) {
+		var requestBody = []byte(`{"position": 0}`)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, uint64(0)).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskReordering(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("reorder validation failed on required fields", func(t *testing.T) {
+		var (
+			requestBody            = []byte("{}")
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "[\"Validation for \\\"position\\\" failed on: required.\"]"
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskReordering(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+}
+
+func TestTaskHandler_HandleSetTaskPriority(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/priority"
+		serviceMethod = "SetPriority"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var requestBody = marshal(t, JSON{"priority": "urgent"})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, types.TaskPriorityUrgent).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskPriority(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("unknown priority", func(t *testing.T) {
+		var (
+			requestBody            = marshal(t, JSON{"priority": "whenever"})
+			expectedStatusCode     = http.StatusBadRequest
+			expectedInResponseBody = "[\"Validation for \\\"priority\\\" failed on: oneof.\"]"
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskPriority(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedStatusCode, response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedInResponseBody)
+	})
+}
+
+func TestTaskHandler_HandleSetTaskDueDate(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/due_date"
+		serviceMethod = "SetDueDate"
+	)
+	var (
+		listID, taskID = uuid.New(), uuid.New()
+		dueDate        = time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var requestBody = marshal(t, &transfer.TaskDueDateUpdate{DueDate: dueDate})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, dueDate).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskDueDate(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("due date validation failed on required fields", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader([]byte("{}")))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskDueDate(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+func TestTaskHandler_HandleSetTaskRecurrence(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/recurrence"
+		serviceMethod = "SetRecurrence"
+	)
+	var (
+		listID, taskID = uuid.New(), uuid.New()
+		rule           = "FREQ=WEEKLY;BYDAY=MO"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var requestBody = marshal(t, &transfer.TaskRecurrenceUpdate{Rule: rule})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, rule).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskRecurrence(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("recurrence validation failed on required fields", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader([]byte("{}")))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskRecurrence(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var (
+			expectedError = failure.ErrBadRecurrence.Clone().FormatDetails("the task must have a due date to recur")
+			requestBody   = marshal(t, &transfer.TaskRecurrenceUpdate{Rule: rule})
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskRecurrence(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestTaskHandler_HandleTaskRecurrenceRemoval(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/recurrence"
+		serviceMethod = "RemoveRecurrence"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskRecurrenceRemoval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("did not recur", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskRecurrenceRemoval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+		assert.Contains(t, response.Header.Get("Location"), taskTarget(listID, taskID))
+	})
+}
+
+func TestTaskHandler_HandleRetrievalOfTaskOccurrences(t *testing.T) {
+	const (
+		method        = "GET"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/occurrences"
+		serviceMethod = "Occurrences"
+	)
+	var (
+		listID, taskID = uuid.New(), uuid.New()
+		dueDate        = time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+		occurrences    = []time.Time{dueDate, dueDate.AddDate(0, 0, 7)}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var expectedResponseBody = marshal(t, occurrences)
+		var request = httptest.NewRequest(method, target+"?count=2", nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, 2).Return(occurrences, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleRetrievalOfTaskOccurrences(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, string(expectedResponseBody), string(responseBody))
+	})
+
+	t.Run("defaults to 10 occurrences", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, 10).Return(occurrences, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleRetrievalOfTaskOccurrences(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("\"count\" is out of range", func(t *testing.T) {
+		for _, count := range []string{"0", "-1", "101", "many"} {
+			var request = httptest.NewRequest(method, target+"?count="+count, nil)
+			withLoggedUser(&request)
+			withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+			var m = mocks.NewTaskServiceMock()
+			m.AssertNotCalled(t, serviceMethod)
+			var recorder = httptest.NewRecorder()
+			NewTaskHandler(m).HandleRetrievalOfTaskOccurrences(recorder, request)
+			var response = recorder.Result()
+			response.Body.Close()
+			assert.Equal(t, failure.ErrBadQueryParameter.Status(), response.StatusCode, count)
+		}
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var expectedError = failure.ErrTaskNotFound
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleRetrievalOfTaskOccurrences(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestTaskHandler_HandleSetTaskReminder(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}/reminder"
+		serviceMethod = "SetReminder"
+	)
+	var (
+		listID, taskID = uuid.New(), uuid.New()
+		remindAt       = time.Date(2024, time.May, 1, 8, 30, 0, 0, time.UTC)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var requestBody = marshal(t, &transfer.TaskReminderUpdate{RemindAt: remindAt})
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID, remindAt).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskReminder(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("got an expected service error", func(t *testing.T) {
+		var (
+			expectedError = failure.ErrTaskNotFound
+			requestBody   = marshal(t, &transfer.TaskReminderUpdate{RemindAt: remindAt})
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(requestBody))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleSetTaskReminder(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedError.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), expectedError.Details())
+	})
+}
+
+func TestTaskHandler_TaskStateChanges(t *testing.T) {
+	var listID, taskID = uuid.New(), uuid.New()
+
+	var cases = []struct {
+		name          string
+		serviceMethod string
+		handle        func(h *TaskHandler) http.HandlerFunc
+	}{
+		{"complete", "Complete", func(h *TaskHandler) http.HandlerFunc { return h.HandleTaskCompletion }},
+		{"resume", "Resume", func(h *TaskHandler) http.HandlerFunc { return h.HandleTaskResumption }},
+		{"pin", "Pin", func(h *TaskHandler) http.HandlerFunc { return h.HandleTaskPinning }},
+		{"unpin", "Unpin", func(h *TaskHandler) http.HandlerFunc { return h.HandleTaskUnpinning }},
+		{"trash", "Trash", func(h *TaskHandler) http.HandlerFunc { return h.HandleTaskTrashing }},
+		{"restore from trash", "RestoreFromTrash", func(h *TaskHandler) http.HandlerFunc { return h.HandleTaskRestorationFromTrash }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Run("success", func(t *testing.T) {
+				var request = httptest.NewRequest("PUT", "/me/lists/{list_uuid}/tasks/{task_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+				var m = mocks.NewTaskServiceMock()
+				m.On(c.serviceMethod, userID, listID, taskID).Return(true, nil)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTaskHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				assert.Equal(t, http.StatusNoContent, response.StatusCode)
+				assert.Empty(t, response.Header, "No header is expected, but got: %d.", len(response.Header))
+			})
+
+			t.Run("nothing changed? take me to the task", func(t *testing.T) {
+				var request = httptest.NewRequest("PUT", "/me/lists/{list_uuid}/tasks/{task_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+				var m = mocks.NewTaskServiceMock()
+				m.On(c.serviceMethod, userID, listID, taskID).Return(false, nil)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTaskHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+				assert.Contains(t, response.Header.Get("Location"), "/me/lists/"+listID.String()+"/tasks/"+taskID.String())
+			})
+
+			t.Run("parsing \"task_uuid\" failed: UUID is too short", func(t *testing.T) {
+				var request = httptest.NewRequest("PUT", "/me/lists/{list_uuid}/tasks/{task_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": "x"})
+				var m = mocks.NewTaskServiceMock()
+				m.AssertNotCalled(t, c.serviceMethod)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTaskHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				var responseBody = extractResponseBody(t, response.Body)
+				assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+				assert.Contains(t, string(responseBody), "Invalid UUID length.")
+			})
+
+			t.Run("got an expected service error", func(t *testing.T) {
+				var expectedError = failure.ErrTaskNotFound
+				var request = httptest.NewRequest("PUT", "/me/lists/{list_uuid}/tasks/{task_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+				var m = mocks.NewTaskServiceMock()
+				m.On(c.serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(false, expectedError)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTaskHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				var responseBody = extractResponseBody(t, response.Body)
+				assert.Equal(t, expectedError.Status(), response.StatusCode)
+				assert.Contains(t, string(responseBody), expectedError.Details())
+			})
+		})
+	}
+}
+
+func TestTaskHandler_HandleTaskDeletion(t *testing.T) {
+	const (
+		method        = "DELETE"
+		target        = "/me/lists/{list_uuid}/tasks/{task_uuid}"
+		serviceMethod = "Delete"
+	)
+	var listID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, listID, taskID).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		assert.Empty(t, responseBody)
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": listID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskDeletion(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+		assert.Empty(t, string(responseBody), "No response body is expected.")
+	})
+}
+
+func TestTaskHandler_HandleTaskMovement(t *testing.T) {
+	const (
+		method        = "PUT"
+		target        = "/me/tasks/{task_uuid}/move/{list_uuid}"
+		serviceMethod = "Move"
+	)
+	var targetListID, taskID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": targetListID.String(), "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.On(serviceMethod, userID, taskID, targetListID).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskMovement(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("parsing \"list_uuid\" failed: invalid UUID format", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"list_uuid": "2ae4ab6c-dd06-4a8a-bb1b-b2b6e8a7d3zz", "task_uuid": taskID.String()})
+		var m = mocks.NewTaskServiceMock()
+		m.AssertNotCalled(t, serviceMethod)
+		var recorder = httptest.NewRecorder()
+		NewTaskHandler(m).HandleTaskMovement(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(responseBody), "Invalid UUID format.")
+	})
+}
+
+func TestTaskHandler_MoveTaskToSpecialLists(t *testing.T) {
+	var taskID = uuid.New()
+
+	var cases = []struct {
+		serviceMethod string
+		location      string
+		handle        func(h *TaskHandler) http.HandlerFunc
+	}{
+		{"Today", "/me/today", func(h *TaskHandler) http.HandlerFunc { return h.HandleMoveTaskToToday }},
+		{"Tomorrow", "/me/tomorrow", func(h *TaskHandler) http.HandlerFunc { return h.HandleMoveTaskToTomorrow }},
+		{"Defer", "/me/deferred", func(h *TaskHandler) http.HandlerFunc { return h.HandleTaskDeferral }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.serviceMethod, func(t *testing.T) {
+			t.Run("success", func(t *testing.T) {
+				var request = httptest.NewRequest("PUT", "/me/tasks/{task_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+				var m = mocks.NewTaskServiceMock()
+				m.On(c.serviceMethod, userID, taskID).Return(true, nil)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTaskHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				assert.Equal(t, http.StatusNoContent, response.StatusCode)
+			})
+
+			t.Run("already there? take me to the list", func(t *testing.T) {
+				var request = httptest.NewRequest("PUT", "/me/tasks/{task_uuid}", nil)
+				withLoggedUser(&request)
+				withPathParameters(&request, parameters{"task_uuid": taskID.String()})
+				var m = mocks.NewTaskServiceMock()
+				m.On(c.serviceMethod, userID, taskID).Return(false, nil)
+				var recorder = httptest.NewRecorder()
+				c.handle(NewTaskHandler(m))(recorder, request)
+				var response = recorder.Result()
+				defer response.Body.Close()
+				assert.Equal(t, http.StatusSeeOther, response.StatusCode)
+				assert.Contains(t, response.Header.Get("Location"), c.location)
+			})
+		})
+	}
+}
@@ -83,6 +83,27 @@ func (h *UserHandler) HandleBlockedUsersRetrieval(w http.ResponseWriter, r *http
 	w.Write(data)
 }
 
+func (h *UserHandler) HandleDeletedUsersRetrieval(w http.ResponseWriter, r *http.Request) {
+	pagination := parsePagination(w, r)
+	if pagination == nil {
+		return
+	}
+	var sortExpr = extractSorting(w, r)
+	var needle = extractQueryParameter(r, "search", "")
+	res, err := h.s.FetchDeleted(pagination, needle, sortExpr)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	data, err := json.Marshal(res)
+	if nil != err {
+		log.Println(err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 func (h *UserHandler) HandleRetrievalOfUserByID(w http.ResponseWriter, r *http.Request) {
 	var userID = parseParameterToUUID(w, r, "user_uuid")
 	if didNotParse(userID) {
@@ -107,7 +128,7 @@ func (h *UserHandler) HandleAdminPromotion(w http.ResponseWriter, r *http.Reques
 	if didNotParse(userID) {
 		return
 	}
-	userWasPromoted, err := h.s.PromoteToAdmin(userID)
+	userWasPromoted, err := audited(h.s, r).PromoteToAdmin(userID)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -123,7 +144,7 @@ func (h *UserHandler) HandleDegradeAdminToUser(w http.ResponseWriter, r *http.Re
 	if didNotParse(userID) {
 		return
 	}
-	userWasPromoted, err := h.s.DegradeToUser(userID)
+	userWasPromoted, err := audited(h.s, r).DegradeToUser(userID)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -144,7 +165,15 @@ func (h *UserHandler) HandleBlockUser(w http.ResponseWriter, r *http.Request) {
 		failure.EmitError(w, failure.ErrSelfOperation)
 		return
 	}
-	userWasBlocked, err := h.s.Block(userToBlock)
+	var block = &transfer.UserBlock{}
+	if 0 != r.ContentLength {
+		var err = parseRequestBody(w, r, block)
+		if nil != err {
+			failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+			return
+		}
+	}
+	userWasBlocked, err := audited(h.s, r).Block(userToBlock, block)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -165,7 +194,7 @@ func (h *UserHandler) HandleUnblockUser(w http.ResponseWriter, r *http.Request)
 		failure.EmitError(w, failure.ErrSelfOperation)
 		return
 	}
-	userWasUnblocked, err := h.s.Unblock(userToUnblock)
+	userWasUnblocked, err := audited(h.s, r).Unblock(userToUnblock)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
@@ -185,10 +214,26 @@ func (h *UserHandler) HandleUserDeletion(w http.ResponseWriter, r *http.Request)
 		failure.EmitError(w, failure.ErrSelfOperation)
 		return
 	}
-	err := h.s.RemoveHardly(userToDelete)
+	err := audited(h.s, r).RemoveHardly(userToDelete)
+	if gotAndHandledServiceError(w, err) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) HandleUserRestoration(w http.ResponseWriter, r *http.Request) {
+	var userToRestore = parseParameterToUUID(w, r, "user_uuid")
+	if didNotParse(userToRestore) {
+		return
+	}
+	userWasRestored, err := audited(h.s, r).Restore(userToRestore)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
+	if !userWasRestored {
+		failure.EmitError(w, failure.ErrUserNotDeleted)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -287,7 +332,7 @@ func (h *UserHandler) HandleUpdateOneSettingForLoggedUser(w http.ResponseWriter,
 	}
 	userID, _ := extractUserPayload(r)
 	settingKey := r.PathValue("setting_key")
-	wasUpdated, err := h.s.UpdateUserSetting(userID, settingKey, up)
+	wasUpdated, err := audited(h.s, r).UpdateUserSetting(userID, settingKey, up)
 	if err != nil {
 		var e *failure.Error
 		if errors.As(err, &e) {
@@ -321,7 +366,7 @@ func (h *UserHandler) HandleUpdateForLoggedUser(w http.ResponseWriter, r *http.R
 		return
 	}
 	userID, _ := extractUserPayload(r)
-	userWasUpdated, err := h.s.Update(userID, up)
+	userWasUpdated, err := audited(h.s, r).Update(userID, up)
 	if err != nil {
 		var e *failure.Error
 		if errors.As(err, &e) {
@@ -343,10 +388,44 @@ func (h *UserHandler) HandleUpdateForLoggedUser(w http.ResponseWriter, r *http.R
 	redirect(w, r, r.URL.Path)
 }
 
+func (h *UserHandler) HandlePasswordChangeForLoggedUser(w http.ResponseWriter, r *http.Request) {
+	change := &transfer.PasswordChange{}
+	var err = parseRequestBody(w, r, change)
+	if nil != err {
+		failure.EmitError(w, failure.ErrMalformedRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	if err = change.Validate(); err != nil {
+		failure.EmitError(w, failure.ErrBadRequest.Clone().SetDetails(err.Error()))
+		return
+	}
+	userID, _ := extractUserPayload(r)
+	_, err = audited(h.s, r).ChangePassword(userID, change)
+	if err != nil {
+		var (
+			a *failure.AggregateDetails
+			e *failure.Error
+		)
+		switch {
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		case errors.As(err, &a):
+			failure.EmitError(w, failure.ErrPasswordRestrictions.Clone().SetDetails(a.Error()))
+		case errors.Is(err, failure.ErrUserNotFound):
+			failure.EmitError(w, failure.ErrUserNoLongerExists)
+		case errors.As(err, &e):
+			failure.EmitError(w, e)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *UserHandler) HandleRemovalOfLoggedUser(w http.ResponseWriter, r *http.Request) {
 	userID, _ := extractUserPayload(r)
-	err := h.s.RemoveSoftly(userID)
+	err := audited(h.s, r).RemoveSoftly(userID)
 	if gotAndHandledServiceError(w, err) {
 		return
 	}
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"bytes"
 	"errors"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
@@ -9,9 +11,11 @@ import (
 	"net/url"
 	"noda/data/transfer"
 	"noda/data/types"
+	"noda/failure"
 	"noda/mocks"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestUserHandler_HandleUsersRetrieval(t *testing.T) {
@@ -136,6 +140,136 @@ func TestUserHandler_HandleBlockedUsersRetrieval(t *testing.T) {
 	})
 }
 
+func TestUserHandler_HandleDeletedUsersRetrieval(t *testing.T) {
+	const (
+		method  = "GET"
+		target  = "/users/deleted"
+		routine = "FetchDeleted"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pagination    = types.Pagination{Page: 1, RPP: 10}
+			values        = url.Values{"page": []string{"1"}, "rpp": []string{"10"}}
+			serviceResult = &types.Result[transfer.DeletedUser]{
+				Page:      pagination.Page,
+				RPP:       pagination.RPP,
+				Payload:   []*transfer.DeletedUser{{User: transfer.User{UUID: uuid.New()}, PurgeAt: time.Now()}},
+				Retrieved: 1,
+			}
+			expectedResponseBody = string(marshal(t, serviceResult))
+		)
+		var request = httptest.NewRequest(method, target+"?"+values.Encode(), nil)
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, &pagination, "", mock.Anything).Return(serviceResult, nil)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleDeletedUsersRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, expectedResponseBody, string(responseBody))
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleDeletedUsersRetrieval(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
+
+func TestUserHandler_HandleUserRestoration(t *testing.T) {
+	const (
+		method  = "POST"
+		routine = "Restore"
+	)
+	var (
+		userToRestore = uuid.New()
+		target        = "/users/" + userToRestore.String() + "/restore"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"user_uuid": userToRestore.String()})
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userToRestore).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleUserRestoration(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("the user was not deleted", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"user_uuid": userToRestore.String()})
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userToRestore).Return(false, nil)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleUserRestoration(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		var responseBody = extractResponseBody(t, response.Body)
+		assert.Equal(t, failure.ErrUserNotDeleted.Status(), response.StatusCode)
+		assert.Contains(t, string(responseBody), failure.ErrUserNotDeleted.Details())
+	})
+
+	t.Run("the user was already purged", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"user_uuid": userToRestore.String()})
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userToRestore).Return(false, failure.ErrUserNotFound)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleUserRestoration(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	})
+}
+
+func TestUserHandler_HandleRemovalOfLoggedUser(t *testing.T) {
+	const (
+		method  = "DELETE"
+		target  = "/me"
+		routine = "RemoveSoftly"
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userID).Return(nil)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleRemovalOfLoggedUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		s.AssertNotCalled(t, "RemoveHardly", mock.Anything)
+	})
+
+	t.Run("got an unexpected service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userID).Return(errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleRemovalOfLoggedUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
+
 func TestUserHandler_HandleRetrievalOfLoggedUserSettings(t *testing.T) {
 	const (
 		method  = "GET"
@@ -245,3 +379,148 @@ func TestUserHandler_HandleRetrievalOfUserByID(t *testing.T) {
 		assert.Empty(t, string(responseBody), "No response body is expected.")
 	})
 }
+
+func TestUserHandler_HandlePasswordChangeForLoggedUser(t *testing.T) {
+	defer beQuiet()()
+	const (
+		method  = "POST"
+		target  = "/me/change_password"
+		routine = "ChangePassword"
+	)
+	var change = &transfer.PasswordChange{OldPassword: "x@e8[a+*GAUsKBZ!d}>3&", NewPassword: "Gq7!vR2#pLk9@wZ"}
+
+	t.Run("success", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, change)))
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userID, change).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandlePasswordChangeForLoggedUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+		assert.Empty(t, string(extractResponseBody(t, response.Body)), "No response body is expected.")
+	})
+
+	t.Run("missing new password", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader([]byte(`{"old_password": "x"}`)))
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandlePasswordChangeForLoggedUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		s.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("password restrictions not met", func(t *testing.T) {
+		var (
+			request    = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, change)))
+			violations = new(failure.AggregateDetails)
+		)
+		violations.Append("Password must contain at least one digit.")
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userID, change).Return(false, violations)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandlePasswordChangeForLoggedUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(extractResponseBody(t, response.Body)), "Password must contain at least one digit.")
+	})
+
+	t.Run("incorrect old password", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, change)))
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userID, change).Return(false, failure.ErrIncorrectPassword)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandlePasswordChangeForLoggedUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		assert.Contains(t, string(extractResponseBody(t, response.Body)), failure.ErrIncorrectPassword.Details())
+	})
+
+	t.Run("got service error", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, change)))
+		withLoggedUser(&request)
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userID, change).Return(false, errors.New("unexpected error"))
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandlePasswordChangeForLoggedUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	})
+}
+
+func TestUserHandler_HandleBlockUser(t *testing.T) {
+	defer beQuiet()()
+	const (
+		method  = "PUT"
+		routine = "Block"
+	)
+	var (
+		userToBlock = uuid.New()
+		target      = "/users/" + userToBlock.String() + "/block"
+	)
+
+	t.Run("success with reason and expiry", func(t *testing.T) {
+		var (
+			until = time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+			block = &transfer.UserBlock{Reason: "spam", Until: &until}
+		)
+		var request = httptest.NewRequest(method, target, bytes.NewReader(marshal(t, block)))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"user_uuid": userToBlock.String()})
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userToBlock, block).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleBlockUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("success without body", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"user_uuid": userToBlock.String()})
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, userToBlock, &transfer.UserBlock{}).Return(true, nil)
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleBlockUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	})
+
+	t.Run("cannot block oneself", func(t *testing.T) {
+		var request = httptest.NewRequest(method, "/users/"+userID.String()+"/block", nil)
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"user_uuid": userID.String()})
+		var s = mocks.NewUserServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleBlockUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		s.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("could not parse JSON body", func(t *testing.T) {
+		var request = httptest.NewRequest(method, target, bytes.NewReader([]byte(`{"reason": 1}`)))
+		withLoggedUser(&request)
+		withPathParameters(&request, parameters{"user_uuid": userToBlock.String()})
+		var s = mocks.NewUserServiceMock()
+		var recorder = httptest.NewRecorder()
+		NewUserHandler(s).HandleBlockUser(recorder, request)
+		var response = recorder.Result()
+		defer response.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		s.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+}
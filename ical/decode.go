@@ -0,0 +1,270 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Date is the layout of the DATE values of iCalendar.
+const Date = "20060102"
+
+// localDateTime is the layout of the DATE-TIME values that are not in UTC,
+// which are either floating or in the time zone of their TZID parameter.
+const localDateTime = "20060102T150405"
+
+// Decode reads a component, usually a VCALENDAR, and the components nested in
+// it. Folded lines are unfolded and parameter values unquoted, but property
+// values are kept as they are written; see Property.Text for TEXT values.
+func Decode(r io.Reader) (*Component, error) {
+	var (
+		scanner = bufio.NewScanner(r)
+		lines   = make([]string, 0)
+	)
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+	for scanner.Scan() {
+		var line = strings.TrimSuffix(scanner.Text(), "\r")
+		if "" == line {
+			continue
+		}
+		if (' ' == line[0] || '\t' == line[0]) && 0 < len(lines) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); nil != err {
+		return nil, err
+	}
+	var (
+		root  *Component
+		stack = make([]*Component, 0)
+	)
+	for i, line := range lines {
+		property, err := parseLine(line)
+		if nil != err {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		switch {
+		case "BEGIN" == property.Name:
+			if nil != root && 0 == len(stack) {
+				return nil, fmt.Errorf("line %d: content after END:%s", i+1, root.Name)
+			}
+			var component = NewComponent(strings.ToUpper(property.Value))
+			if 0 == len(stack) {
+				root = component
+			} else {
+				stack[len(stack)-1].Append(component)
+			}
+			stack = append(stack, component)
+		case "END" == property.Name:
+			if 0 == len(stack) || stack[len(stack)-1].Name != strings.ToUpper(property.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", i+1, property.Value)
+			}
+			stack = stack[:len(stack)-1]
+		case 0 == len(stack):
+			return nil, fmt.Errorf("line %d: property %s outside of a component", i+1, property.Name)
+		default:
+			var current = stack[len(stack)-1]
+			current.Properties = append(current.Properties, property)
+		}
+	}
+	switch {
+	case nil == root:
+		return nil, errors.New("no component")
+	case 0 < len(stack):
+		return nil, fmt.Errorf("missing END:%s", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+// parseLine parses an unfolded content line: a name, the parameters, each
+// after a semicolon, and the value after the first colon that is not quoted.
+func parseLine(line string) (*Property, error) {
+	var i = strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return nil, errors.New("malformed content line")
+	}
+	var property = &Property{Name: strings.ToUpper(line[:i])}
+	for ';' == line[i] {
+		line = line[i+1:]
+		var eq = strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("malformed parameter of %s", property.Name)
+		}
+		var param = Param{Name: strings.ToUpper(line[:eq])}
+		line = line[eq+1:]
+		var values = make([]string, 0, 1)
+		for {
+			var value string
+			if strings.HasPrefix(line, `"`) {
+				var end = strings.IndexByte(line[1:], '"')
+				if -1 == end {
+					return nil, fmt.Errorf("unterminated quote in %s", property.Name)
+				}
+				value, line = line[1:end+1], line[end+2:]
+			} else {
+				var end = strings.IndexAny(line, ",;:")
+				if -1 == end {
+					return nil, fmt.Errorf("missing value of %s", property.Name)
+				}
+				value, line = line[:end], line[end:]
+			}
+			values = append(values, value)
+			if !strings.HasPrefix(line, ",") {
+				break
+			}
+			line = line[1:]
+		}
+		param.Value = strings.Join(values, ",")
+		property.Params = append(property.Params, param)
+		if "" == line {
+			return nil, fmt.Errorf("missing value of %s", property.Name)
+		}
+		i = 0
+	}
+	if ':' != line[i] {
+		return nil, fmt.Errorf("malformed parameter of %s", property.Name)
+	}
+	property.Value = line[i+1:]
+	return property, nil
+}
+
+// Get returns the first property with the given name, or nil.
+func (c *Component) Get(name string) *Property {
+	for _, p := range c.Properties {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Set replaces the value of the first property with the given name, or adds
+// the property if the component has none.
+func (c *Component) Set(name, value string, params ...Param) {
+	if p := c.Get(name); nil != p {
+		p.Params, p.Value = params, value
+		return
+	}
+	c.Add(name, value, params...)
+}
+
+// Find returns the first component with the given name nested in the
+// component, or nil.
+func (c *Component) Find(name string) *Component {
+	for _, child := range c.Components {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// Param returns the value of a parameter of the property, or "".
+func (p *Property) Param(name string) string {
+	for _, param := range p.Params {
+		if strings.EqualFold(param.Name, name) {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+var textUnescaper = strings.NewReplacer(
+	`\\`, `\`,
+	`\;`, ";",
+	`\,`, ",",
+	`\n`, "\n",
+	`\N`, "\n",
+)
+
+// Text returns the value of a TEXT property, unescaped.
+func (p *Property) Text() string {
+	return textUnescaper.Replace(p.Value)
+}
+
+// Time returns the value of a DATE-TIME or DATE property. Times that are not
+// in UTC are taken in the time zone of the TZID parameter if it is a known
+// one, else in UTC; dates are taken at midnight UTC.
+func (p *Property) Time() (time.Time, error) {
+	switch {
+	case "DATE" == strings.ToUpper(p.Param("VALUE")) || len(Date) == len(p.Value):
+		return time.Parse(Date, p.Value)
+	case strings.HasSuffix(p.Value, "Z"):
+		return time.Parse(DateTime, p.Value)
+	}
+	var location = time.UTC
+	if tzid := strings.TrimPrefix(p.Param("TZID"), "/"); "" != tzid {
+		if loaded, err := time.LoadLocation(tzid); nil == err {
+			location = loaded
+		}
+	}
+	return time.ParseInLocation(localDateTime, p.Value, location)
+}
+
+// ParseDuration parses a DURATION value, such as "-PT15M" or "P1DT12H".
+func ParseDuration(value string) (time.Duration, error) {
+	var (
+		s        = value
+		sign     = time.Duration(1)
+		duration time.Duration
+		inTime   bool
+		units    int // the units read since the start or the T
+	)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || 1 == len(s) {
+		return 0, fmt.Errorf("malformed duration %q", value)
+	}
+	s = s[1:]
+	for "" != s {
+		if 'T' == s[0] {
+			if inTime {
+				return 0, fmt.Errorf("malformed duration %q", value)
+			}
+			inTime, units, s = true, 0, s[1:]
+			continue
+		}
+		var i = 0
+		for i < len(s) && '0' <= s[i] && s[i] <= '9' {
+			i++
+		}
+		if 0 == i || len(s) == i {
+			return 0, fmt.Errorf("malformed duration %q", value)
+		}
+		n, err := strconv.Atoi(s[:i])
+		if nil != err {
+			return 0, fmt.Errorf("malformed duration %q", value)
+		}
+		var unit time.Duration
+		switch {
+		case !inTime && 'W' == s[i]:
+			unit = 7 * 24 * time.Hour
+		case !inTime && 'D' == s[i]:
+			unit = 24 * time.Hour
+		case inTime && 'H' == s[i]:
+			unit = time.Hour
+		case inTime && 'M' == s[i]:
+			unit = time.Minute
+		case inTime && 'S' == s[i]:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("malformed duration %q", value)
+		}
+		duration += time.Duration(n) * unit
+		units, s = units+1, s[i+1:]
+	}
+	if 0 == units {
+		return 0, fmt.Errorf("malformed duration %q", value)
+	}
+	return sign * duration, nil
+}
@@ -0,0 +1,144 @@
+// Package ical reads and writes iCalendar (RFC 5545) objects, such as the
+// calendar feeds of the tasks.
+//
+// A calendar is a tree of components made of properties, written as content
+// lines of at most 75 octets ended by CRLF; longer lines are folded. The values
+// of TEXT properties are escaped, and so are parameter values when needed.
+package ical
+
+import (
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DateTime is the layout of the UTC date-times of iCalendar.
+const DateTime = "20060102T150405Z"
+
+// maxLineLength is the length in octets after which content lines are folded,
+// not counting the CRLF.
+const maxLineLength = 75
+
+// Component is a calendar component such as VCALENDAR, VTODO or VALARM.
+type Component struct {
+	Name       string
+	Properties []*Property
+	Components []*Component
+}
+
+// Property is a content line of a component. Its value is written as is, so a
+// TEXT value must be escaped first, as AddText does.
+type Property struct {
+	Name   string
+	Params []Param
+	Value  string
+}
+
+// Param is a parameter of a property, such as VALUE=DATE-TIME.
+type Param struct {
+	Name  string
+	Value string
+}
+
+func NewComponent(name string) *Component {
+	return &Component{Name: name}
+}
+
+// Add adds a property with a value that needs no escaping.
+func (c *Component) Add(name, value string, params ...Param) {
+	c.Properties = append(c.Properties, &Property{Name: name, Params: params, Value: value})
+}
+
+// AddText adds a TEXT property.
+func (c *Component) AddText(name, text string, params ...Param) {
+	c.Add(name, EscapeText(text), params...)
+}
+
+// AddTime adds a DATE-TIME property, in UTC.
+func (c *Component) AddTime(name string, t time.Time, params ...Param) {
+	c.Add(name, t.UTC().Format(DateTime), params...)
+}
+
+// Append nests other components in the component.
+func (c *Component) Append(components ...*Component) {
+	c.Components = append(c.Components, components...)
+}
+
+// Encode writes the component and the components nested in it.
+func (c *Component) Encode(w io.Writer) error {
+	var b strings.Builder
+	c.encode(&b)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (c *Component) encode(b *strings.Builder) {
+	writeLine(b, "BEGIN:"+c.Name)
+	for _, p := range c.Properties {
+		var line strings.Builder
+		line.WriteString(p.Name)
+		for _, param := range p.Params {
+			line.WriteString(";")
+			line.WriteString(param.Name)
+			line.WriteString("=")
+			line.WriteString(quoteParamValue(param.Value))
+		}
+		line.WriteString(":")
+		line.WriteString(p.Value)
+		writeLine(b, line.String())
+	}
+	for _, child := range c.Components {
+		child.encode(b)
+	}
+	writeLine(b, "END:"+c.Name)
+}
+
+// writeLine writes a content line, folded so that no line is longer than
+// maxLineLength octets. Lines are only folded between characters.
+func writeLine(b *strings.Builder, line string) {
+	var limit = maxLineLength
+	for limit < len(line) {
+		var cut = limit
+		for 0 < cut && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineLength - 1 // the leading space counts
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// EscapeText escapes a TEXT value: backslashes, semicolons, commas and line
+// breaks.
+func EscapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// quoteParamValue quotes a parameter value that holds a colon, a semicolon
+// or a comma. Double quotes and line breaks cannot be written, so they are
+// dropped.
+func quoteParamValue(value string) string {
+	value = strings.Map(func(r rune) rune {
+		if '"' == r || '\r' == r || '\n' == r {
+			return -1
+		}
+		return r
+	}, value)
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + value + `"`
+	}
+	return value
+}
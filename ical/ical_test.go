@@ -0,0 +1,148 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeText(t *testing.T) {
+	for text, expected := range map[string]string{
+		"Buy milk":               "Buy milk",
+		`C:\Users`:               `C:\\Users`,
+		"eggs, milk; bread":      `eggs\, milk\; bread`,
+		"first\nsecond\r\nthird": `first\nsecond\nthird`,
+		"colon: stays":           "colon: stays",
+	} {
+		assert.Equal(t, expected, EscapeText(text), "text: %q", text)
+	}
+}
+
+func TestComponent_Encode(t *testing.T) {
+	t.Run("writes nested components with CRLF", func(t *testing.T) {
+		var (
+			calendar = NewComponent("VCALENDAR")
+			todo     = NewComponent("VTODO")
+			buf      bytes.Buffer
+		)
+		calendar.Add("VERSION", "2.0")
+		todo.AddText("SUMMARY", "Eggs, milk")
+		todo.AddTime("DUE", time.Date(2024, 12, 31, 18, 30, 0, 0, time.FixedZone("", 3600)))
+		todo.Add("X-NOTE", "ok", Param{Name: "X-WHERE", Value: "home; garden"}, Param{Name: "X-QUOTE", Value: `say "hi"`})
+		calendar.Append(todo)
+		assert.NoError(t, calendar.Encode(&buf))
+		assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+			"VERSION:2.0\r\n"+
+			"BEGIN:VTODO\r\n"+
+			"SUMMARY:Eggs\\, milk\r\n"+
+			"DUE:20241231T173000Z\r\n"+
+			"X-NOTE;X-WHERE=\"home; garden\";X-QUOTE=say hi:ok\r\n"+
+			"END:VTODO\r\n"+
+			"END:VCALENDAR\r\n", buf.String())
+	})
+
+	t.Run("folds long lines between characters", func(t *testing.T) {
+		var (
+			todo    = NewComponent("VTODO")
+			summary = strings.Repeat("é", 100)
+			buf     bytes.Buffer
+		)
+		todo.AddText("SUMMARY", summary)
+		assert.NoError(t, todo.Encode(&buf))
+		var lines = strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+		assert.Equal(t, "BEGIN:VTODO", lines[0])
+		assert.Equal(t, "END:VTODO", lines[len(lines)-1])
+		var unfolded strings.Builder
+		for i, line := range lines[1 : len(lines)-1] {
+			assert.LessOrEqual(t, len(line), 75, "line %d is too long", i)
+			assert.True(t, strings.ToValidUTF8(line, "?") == line, "line %d splits a character", i)
+			if 0 < i {
+				assert.True(t, strings.HasPrefix(line, " "), "line %d is not a continuation", i)
+				line = line[1:]
+			}
+			unfolded.WriteString(line)
+		}
+		assert.Equal(t, "SUMMARY:"+summary, unfolded.String())
+		assert.Len(t, lines, 5)
+	})
+
+	t.Run("does not fold lines of 75 octets", func(t *testing.T) {
+		var (
+			todo = NewComponent("VTODO")
+			buf  bytes.Buffer
+		)
+		todo.Add("SUMMARY", strings.Repeat("a", 75-len("SUMMARY:")))
+		assert.NoError(t, todo.Encode(&buf))
+		assert.Equal(t, 3, strings.Count(buf.String(), "\r\n"))
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("reads what Encode writes", func(t *testing.T) {
+		var (
+			calendar = NewComponent("VCALENDAR")
+			todo     = NewComponent("VTODO")
+			summary  = "Eggs, milk; " + strings.Repeat("é", 60)
+			buf      bytes.Buffer
+		)
+		calendar.Add("VERSION", "2.0")
+		todo.AddText("SUMMARY", summary)
+		todo.Add("X-NOTE", "ok", Param{Name: "X-WHERE", Value: "home; garden"})
+		calendar.Append(todo)
+		assert.NoError(t, calendar.Encode(&buf))
+		decoded, err := Decode(&buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "VCALENDAR", decoded.Name)
+		assert.Equal(t, "2.0", decoded.Get("VERSION").Value)
+		var got = decoded.Find("VTODO")
+		assert.NotNil(t, got)
+		assert.Equal(t, summary, got.Get("SUMMARY").Text())
+		assert.Equal(t, "home; garden", got.Get("X-NOTE").Param("X-WHERE"))
+		assert.Equal(t, "ok", got.Get("X-NOTE").Value)
+	})
+
+	t.Run("accepts bare line feeds and tab continuations", func(t *testing.T) {
+		decoded, err := Decode(strings.NewReader("BEGIN:VTODO\nSUMMARY:Paint\n\tthe fence\nDUE;TZID=Europe/Paris:20241231T180000\nEND:VTODO\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Paintthe fence", decoded.Get("SUMMARY").Text())
+		due, err := decoded.Get("DUE").Time()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC), due.UTC())
+	})
+
+	t.Run("refuses malformed calendars", func(t *testing.T) {
+		for _, data := range []string{
+			"",
+			"SUMMARY:Paint\r\n",
+			"BEGIN:VTODO\r\nSUMMARY:Paint\r\n",
+			"BEGIN:VTODO\r\nEND:VEVENT\r\n",
+			"BEGIN:VTODO\r\nSUMMARY\r\nEND:VTODO\r\n",
+			"BEGIN:VTODO\r\nX-NOTE;X-WHERE=\"home:ok\r\nEND:VTODO\r\n",
+			"BEGIN:VTODO\r\nEND:VTODO\r\nBEGIN:VTODO\r\nEND:VTODO\r\n",
+		} {
+			_, err := Decode(strings.NewReader(data))
+			assert.Error(t, err, "data: %q", data)
+		}
+	})
+}
+
+func TestParseDuration(t *testing.T) {
+	for value, expected := range map[string]time.Duration{
+		"-PT15M":   -15 * time.Minute,
+		"PT0S":     0,
+		"P1DT12H":  36 * time.Hour,
+		"+P1W":     7 * 24 * time.Hour,
+		"-P1DT30S": -24*time.Hour - 30*time.Second,
+	} {
+		got, err := ParseDuration(value)
+		assert.NoError(t, err, "value: %q", value)
+		assert.Equal(t, expected, got, "value: %q", value)
+	}
+	for _, value := range []string{"", "P", "15M", "PT", "P1H", "PT1D", "P1DT2HT3M"} {
+		_, err := ParseDuration(value)
+		assert.Error(t, err, "value: %q", value)
+	}
+}
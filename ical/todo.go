@@ -0,0 +1,193 @@
+package ical
+
+import (
+	"fmt"
+	"noda/data/model"
+	"noda/data/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProductID identifies Noda as the maker of the calendars.
+const ProductID = "-//Noda//Noda Tasks//EN"
+
+// priorities maps the priorities of the tasks to the PRIORITY of iCalendar,
+// where 1 is the highest, 9 the lowest and 0 is no priority. Most calendars
+// show 1 to 4 as high, 5 as medium and 6 to 9 as low, and normal tasks are
+// not worth singling out.
+var priorities = map[types.TaskPriority]string{
+	types.TaskPriorityUrgent: "1",
+	types.TaskPriorityHigh:   "3",
+	types.TaskPriorityMedium: "5",
+	types.TaskPriorityNormal: "0",
+	types.TaskPriorityLow:    "9",
+}
+
+// statuses maps the statuses of the tasks to the STATUS of a VTODO. Deferred
+// tasks still need to be done.
+var statuses = map[types.TaskStatus]string{
+	types.TaskStatusIncomplete: "NEEDS-ACTION",
+	types.TaskStatusComplete:   "COMPLETED",
+	types.TaskStatusDeferred:   "NEEDS-ACTION",
+}
+
+// NewCalendar makes a VCALENDAR to publish tasks in, named as given.
+func NewCalendar(name string) *Component {
+	var calendar = NewComponent("VCALENDAR")
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", ProductID)
+	calendar.Add("CALSCALE", "GREGORIAN")
+	calendar.Add("METHOD", "PUBLISH")
+	calendar.AddText("X-WR-CALNAME", name)
+	return calendar
+}
+
+// NewTodo makes a VTODO out of a task, with a VALARM for its reminder. The
+// VTODO is stamped with the last update of the task, so that it is the same
+// as long as the task does not change.
+func NewTodo(task *model.Task) *Component {
+	var todo = NewComponent("VTODO")
+	todo.Add("UID", task.UUID.String())
+	todo.AddTime("DTSTAMP", task.UpdatedAt)
+	todo.AddTime("CREATED", task.CreatedAt)
+	todo.AddTime("LAST-MODIFIED", task.UpdatedAt)
+	todo.AddText("SUMMARY", task.Title)
+	var description = make([]string, 0, 2)
+	for _, text := range []string{task.Headline, task.Description} {
+		if text = strings.TrimSpace(text); "" != text {
+			description = append(description, text)
+		}
+	}
+	if 0 < len(description) {
+		todo.AddText("DESCRIPTION", strings.Join(description, "\n\n"))
+	}
+	if nil != task.DueDate {
+		todo.AddTime("DUE", *task.DueDate)
+	}
+	if priority, ok := priorities[task.Priority]; ok {
+		todo.Add("PRIORITY", priority)
+	}
+	if status, ok := statuses[task.Status]; ok {
+		todo.Add("STATUS", status)
+	}
+	if types.TaskStatusComplete == task.Status {
+		if nil != task.CompletedAt {
+			todo.AddTime("COMPLETED", *task.CompletedAt)
+		}
+		todo.Add("PERCENT-COMPLETE", "100")
+	}
+	if nil != task.ParentUUID {
+		todo.Add("RELATED-TO", task.ParentUUID.String())
+	}
+	if nil != task.RemindAt {
+		var alarm = NewComponent("VALARM")
+		alarm.Add("ACTION", "DISPLAY")
+		alarm.AddText("DESCRIPTION", task.Title)
+		alarm.AddTime("TRIGGER", *task.RemindAt, Param{Name: "VALUE", Value: "DATE-TIME"})
+		todo.Append(alarm)
+	}
+	return todo
+}
+
+// NewResource makes a VCALENDAR to hold a single calendar object, as CalDAV
+// serves them. Unlike a feed, it has no METHOD.
+func NewResource() *Component {
+	var calendar = NewComponent("VCALENDAR")
+	calendar.Add("VERSION", "2.0")
+	calendar.Add("PRODID", ProductID)
+	return calendar
+}
+
+// ReadTodo reads the fields of a task out of a VTODO, the other way around
+// from NewTodo: the title, the whole DESCRIPTION as description, the priority,
+// whether it is complete, the due date and the reminder of the first VALARM.
+// An alarm relative to the start or the end of the VTODO is taken relative to
+// DTSTART or DUE, whichever it refers to; one that cannot be placed is ignored.
+func ReadTodo(todo *Component) (task *model.Task, err error) {
+	task = &model.Task{
+		Priority: types.TaskPriorityNormal,
+		Status:   types.TaskStatusIncomplete,
+	}
+	if p := todo.Get("SUMMARY"); nil != p {
+		task.Title = strings.TrimSpace(p.Text())
+	}
+	if p := todo.Get("DESCRIPTION"); nil != p {
+		task.Description = strings.TrimSpace(p.Text())
+	}
+	if p := todo.Get("PRIORITY"); nil != p {
+		n, err := strconv.Atoi(strings.TrimSpace(p.Value))
+		if nil != err || n < 0 || 9 < n {
+			return nil, fmt.Errorf("invalid PRIORITY %q", p.Value)
+		}
+		task.Priority = readPriority(n)
+	}
+	if p := todo.Get("STATUS"); nil != p && "COMPLETED" == strings.ToUpper(p.Value) || nil != todo.Get("COMPLETED") {
+		task.Status = types.TaskStatusComplete
+		if p := todo.Get("COMPLETED"); nil != p {
+			completedAt, err := p.Time()
+			if nil != err {
+				return nil, fmt.Errorf("invalid COMPLETED %q", p.Value)
+			}
+			task.CompletedAt = &completedAt
+		}
+	}
+	var start *time.Time
+	for name, field := range map[string]**time.Time{"DUE": &task.DueDate, "DTSTART": &start} {
+		if p := todo.Get(name); nil != p {
+			t, err := p.Time()
+			if nil != err {
+				return nil, fmt.Errorf("invalid %s %q", name, p.Value)
+			}
+			*field = &t
+		}
+	}
+	for _, alarm := range todo.Components {
+		var trigger *Property
+		if "VALARM" == alarm.Name {
+			trigger = alarm.Get("TRIGGER")
+		}
+		if nil == trigger {
+			continue
+		}
+		if "DATE-TIME" == strings.ToUpper(trigger.Param("VALUE")) {
+			remindAt, err := trigger.Time()
+			if nil != err {
+				return nil, fmt.Errorf("invalid TRIGGER %q", trigger.Value)
+			}
+			task.RemindAt = &remindAt
+			break
+		}
+		offset, err := ParseDuration(trigger.Value)
+		if nil != err {
+			return nil, fmt.Errorf("invalid TRIGGER %q", trigger.Value)
+		}
+		var anchor = start
+		if "END" == strings.ToUpper(trigger.Param("RELATED")) || nil == anchor {
+			anchor = task.DueDate
+		}
+		if nil != anchor {
+			var remindAt = anchor.Add(offset)
+			task.RemindAt = &remindAt
+		}
+		break
+	}
+	return task, nil
+}
+
+// readPriority maps the PRIORITY of iCalendar to the priorities of the tasks,
+// the other way around from priorities.
+func readPriority(n int) types.TaskPriority {
+	switch {
+	case 0 == n:
+		return types.TaskPriorityNormal
+	case n <= 2:
+		return types.TaskPriorityUrgent
+	case n <= 4:
+		return types.TaskPriorityHigh
+	case 5 == n:
+		return types.TaskPriorityMedium
+	default:
+		return types.TaskPriorityLow
+	}
+}
@@ -0,0 +1,182 @@
+package ical
+
+import (
+	"bytes"
+	"noda/data/model"
+	"noda/data/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTodo(t *testing.T) {
+	var (
+		created   = time.Date(2024, 12, 1, 9, 0, 0, 0, time.UTC)
+		updated   = time.Date(2024, 12, 2, 10, 15, 0, 0, time.UTC)
+		due       = time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+		remindAt  = time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC)
+		completed = time.Date(2024, 12, 30, 8, 0, 0, 0, time.UTC)
+		taskID    = uuid.MustParse("8a2f3e1b-6c4d-4e5f-9a0b-1c2d3e4f5a6b")
+		parentID  = uuid.MustParse("1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e")
+	)
+
+	t.Run("open task with a due date and a reminder", func(t *testing.T) {
+		var buf bytes.Buffer
+		var task = &model.Task{
+			UUID:        taskID,
+			Title:       "Paint the fence",
+			Headline:    "Before the guests come",
+			Description: "White, two coats; the gate too.",
+			Priority:    types.TaskPriorityUrgent,
+			Status:      types.TaskStatusIncomplete,
+			DueDate:     &due,
+			RemindAt:    &remindAt,
+			CreatedAt:   created,
+			UpdatedAt:   updated,
+		}
+		assert.NoError(t, NewTodo(task).Encode(&buf))
+		assert.Equal(t, "BEGIN:VTODO\r\n"+
+			"UID:8a2f3e1b-6c4d-4e5f-9a0b-1c2d3e4f5a6b\r\n"+
+			"DTSTAMP:20241202T101500Z\r\n"+
+			"CREATED:20241201T090000Z\r\n"+
+			"LAST-MODIFIED:20241202T101500Z\r\n"+
+			"SUMMARY:Paint the fence\r\n"+
+			"DESCRIPTION:Before the guests come\\n\\nWhite\\, two coats\\; the gate too.\r\n"+
+			"DUE:20241231T180000Z\r\n"+
+			"PRIORITY:1\r\n"+
+			"STATUS:NEEDS-ACTION\r\n"+
+			"BEGIN:VALARM\r\n"+
+			"ACTION:DISPLAY\r\n"+
+			"DESCRIPTION:Paint the fence\r\n"+
+			"TRIGGER;VALUE=DATE-TIME:20241231T170000Z\r\n"+
+			"END:VALARM\r\n"+
+			"END:VTODO\r\n", buf.String())
+	})
+
+	t.Run("completed subtask", func(t *testing.T) {
+		var buf bytes.Buffer
+		var task = &model.Task{
+			UUID:        taskID,
+			ParentUUID:  &parentID,
+			Title:       "Buy paint",
+			Priority:    types.TaskPriorityNormal,
+			Status:      types.TaskStatusComplete,
+			CompletedAt: &completed,
+			CreatedAt:   created,
+			UpdatedAt:   updated,
+		}
+		assert.NoError(t, NewTodo(task).Encode(&buf))
+		assert.Equal(t, "BEGIN:VTODO\r\n"+
+			"UID:8a2f3e1b-6c4d-4e5f-9a0b-1c2d3e4f5a6b\r\n"+
+			"DTSTAMP:20241202T101500Z\r\n"+
+			"CREATED:20241201T090000Z\r\n"+
+			"LAST-MODIFIED:20241202T101500Z\r\n"+
+			"SUMMARY:Buy paint\r\n"+
+			"PRIORITY:0\r\n"+
+			"STATUS:COMPLETED\r\n"+
+			"COMPLETED:20241230T080000Z\r\n"+
+			"PERCENT-COMPLETE:100\r\n"+
+			"RELATED-TO:1b2c3d4e-5f6a-4b7c-8d9e-0f1a2b3c4d5e\r\n"+
+			"END:VTODO\r\n", buf.String())
+	})
+
+	t.Run("maps every priority and status", func(t *testing.T) {
+		for priority, expected := range map[types.TaskPriority]string{
+			types.TaskPriorityUrgent: "PRIORITY:1",
+			types.TaskPriorityHigh:   "PRIORITY:3",
+			types.TaskPriorityMedium: "PRIORITY:5",
+			types.TaskPriorityNormal: "PRIORITY:0",
+			types.TaskPriorityLow:    "PRIORITY:9",
+		} {
+			var buf bytes.Buffer
+			assert.NoError(t, NewTodo(&model.Task{Priority: priority}).Encode(&buf))
+			assert.Contains(t, buf.String(), "\r\n"+expected+"\r\n")
+		}
+		for status, expected := range map[types.TaskStatus]string{
+			types.TaskStatusIncomplete: "STATUS:NEEDS-ACTION",
+			types.TaskStatusComplete:   "STATUS:COMPLETED",
+			types.TaskStatusDeferred:   "STATUS:NEEDS-ACTION",
+		} {
+			var buf bytes.Buffer
+			assert.NoError(t, NewTodo(&model.Task{Status: status}).Encode(&buf))
+			assert.Contains(t, buf.String(), "\r\n"+expected+"\r\n")
+		}
+	})
+}
+
+func TestNewCalendar(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, NewCalendar("Home, garden").Encode(&buf))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"PRODID:-//Noda//Noda Tasks//EN\r\n"+
+		"CALSCALE:GREGORIAN\r\n"+
+		"METHOD:PUBLISH\r\n"+
+		"X-WR-CALNAME:Home\\, garden\r\n"+
+		"END:VCALENDAR\r\n", buf.String())
+}
+
+func TestReadTodo(t *testing.T) {
+	var due = time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+
+	t.Run("reads what NewTodo writes", func(t *testing.T) {
+		var (
+			remindAt = due.Add(-time.Hour)
+			buf      bytes.Buffer
+		)
+		var task = &model.Task{
+			UUID:        uuid.New(),
+			Title:       "Paint the fence",
+			Description: "White, two coats; the gate too.",
+			Priority:    types.TaskPriorityHigh,
+			Status:      types.TaskStatusIncomplete,
+			DueDate:     &due,
+			RemindAt:    &remindAt,
+		}
+		var calendar = NewResource()
+		calendar.Append(NewTodo(task))
+		assert.NoError(t, calendar.Encode(&buf))
+		decoded, err := Decode(&buf)
+		assert.NoError(t, err)
+		got, err := ReadTodo(decoded.Find("VTODO"))
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Task{
+			Title:       task.Title,
+			Description: task.Description,
+			Priority:    task.Priority,
+			Status:      task.Status,
+			DueDate:     task.DueDate,
+			RemindAt:    task.RemindAt,
+		}, got)
+	})
+
+	t.Run("places relative alarms and maps priorities", func(t *testing.T) {
+		decoded, err := Decode(strings.NewReader("BEGIN:VTODO\r\n" +
+			"SUMMARY:Paint the fence\r\n" +
+			"PRIORITY:7\r\n" +
+			"STATUS:COMPLETED\r\n" +
+			"DUE:20241231T180000Z\r\n" +
+			"BEGIN:VALARM\r\n" +
+			"TRIGGER;RELATED=END:-PT15M\r\n" +
+			"END:VALARM\r\n" +
+			"END:VTODO\r\n"))
+		assert.NoError(t, err)
+		got, err := ReadTodo(decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, types.TaskPriorityLow, got.Priority)
+		assert.Equal(t, types.TaskStatusComplete, got.Status)
+		assert.Equal(t, due.Add(-15*time.Minute), *got.RemindAt)
+	})
+
+	t.Run("refuses invalid values", func(t *testing.T) {
+		for _, property := range []string{"PRIORITY:12", "DUE:tomorrow", "COMPLETED:yes"} {
+			decoded, err := Decode(strings.NewReader("BEGIN:VTODO\r\n" + property + "\r\nEND:VTODO\r\n"))
+			assert.NoError(t, err)
+			_, err = ReadTodo(decoded)
+			assert.Error(t, err, "property: %s", property)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"noda/data/types"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns are the columns a CSV file can have, in any order, named in its
+// first row. Only "title" is required:
+//
+//	id           a key for the task; without it, the group, the list and the
+//	             title of the task are its key
+//	group        the name of the group of the list
+//	list         the name of the list, or defaultListName
+//	title        the title of the task
+//	description  the description of the task
+//	priority     urgent, high, medium, normal or low
+//	completed    true or false
+//	due_date     a date such as 2024-12-31, or an RFC 3339 date-time
+//	remind_at    the same
+//	tags         the names of the tags of the task, separated by ";"
+//	steps        the steps of the task, separated by ";"; the steps that are
+//	             done start with "[x] "
+var csvColumns = []string{
+	"id", "group", "list", "title", "description", "priority", "completed", "due_date", "remind_at", "tags", "steps",
+}
+
+// csvTimeLayouts are the layouts of the dates and date-times of a CSV file.
+var csvTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02"}
+
+func parseCSV(b *builder, r io.Reader) error {
+	var reader = csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if nil != err {
+		if errors.Is(err, io.EOF) {
+			return errors.New("the CSV file is empty")
+		}
+		return err
+	}
+	var columns = make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; ok {
+			return fmt.Errorf("the column %q is repeated", name)
+		}
+		if !slices.Contains(csvColumns, name) {
+			return fmt.Errorf("unknown column %q; the columns are %s", name, strings.Join(csvColumns, ", "))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return errors.New("the CSV file has no \"title\" column")
+	}
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var ref = fmt.Sprintf("Row %d", row)
+		if nil != err {
+			var parseError *csv.ParseError
+			if !errors.As(err, &parseError) {
+				return err
+			}
+			b.problem(ref, "The row is malformed: %s.", parseError.Err)
+			continue
+		}
+		var field = func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		parseCSVRow(b, ref, field)
+	}
+}
+
+func parseCSVRow(b *builder, ref string, field func(name string) string) {
+	var group *Group
+	if name := field("group"); "" != name {
+		group = b.group(name, name, "")
+	}
+	var listName = field("list")
+	if "" == listName {
+		listName = defaultListName
+	}
+	var listKey = listName
+	if nil != group {
+		listKey = group.Key + "/" + listName
+	}
+	var task = &Task{
+		Key:         field("id"),
+		Ref:         ref,
+		List:        b.list(listKey, listName, "", group),
+		Title:       field("title"),
+		Description: field("description"),
+	}
+	if "" == task.Key {
+		task.Key = listKey + "/" + task.Title
+	}
+	var ok = true
+	switch priority := types.TaskPriority(strings.ToLower(field("priority"))); priority {
+	case "":
+	case types.TaskPriorityUrgent, types.TaskPriorityHigh, types.TaskPriorityMedium, types.TaskPriorityNormal, types.TaskPriorityLow:
+		task.Priority = priority
+	default:
+		b.problem(ref, "The priority %q is not one of urgent, high, medium, normal or low.", field("priority"))
+		ok = false
+	}
+	if completed := field("completed"); "" != completed {
+		var err error
+		task.Completed, err = strconv.ParseBool(completed)
+		if nil != err {
+			b.problem(ref, "The value %q of \"completed\" is not true or false.", completed)
+			ok = false
+		}
+	}
+	var times = []struct {
+		name string
+		at   **time.Time
+	}{{"due_date", &task.DueDate}, {"remind_at", &task.RemindAt}}
+	for _, p := range times {
+		if value := field(p.name); "" != value {
+			t, err := parseTime(value, csvTimeLayouts...)
+			if nil != err {
+				b.problem(ref, "The value %q of %q is not a date.", value, p.name)
+				ok = false
+				continue
+			}
+			*p.at = &t
+		}
+	}
+	for _, name := range strings.Split(field("tags"), ";") {
+		if tag := b.tag(name, ""); nil != tag {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+	for _, description := range strings.Split(field("steps"), ";") {
+		var step = &Step{Description: strings.TrimSpace(description)}
+		if rest, done := strings.CutPrefix(step.Description, "[x] "); done {
+			step.Description, step.Done = strings.TrimSpace(rest), true
+		}
+		if "" != step.Description {
+			task.Steps = append(task.Steps, step)
+		}
+	}
+	if ok {
+		b.task(task)
+	}
+}
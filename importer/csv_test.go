@@ -0,0 +1,67 @@
+package importer
+
+import (
+	"noda/data/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCSV(t *testing.T) {
+	t.Run("every column", func(t *testing.T) {
+		batch, err := Parse(FormatCSV, strings.NewReader("\ufeffTitle,group,list,id,description,priority,completed,due_date,remind_at,tags,steps\n"+
+			`Paint the fence,Home,Chores,F-1,"White, two coats",urgent,true,2024-12-31,2024-12-31T09:00:00+01:00,paint;outside,"[x] Buy paint; Sand"`+"\n"+
+			"Water the plants,,,,,,,,,,\n"))
+		assert.NoError(t, err)
+		var (
+			home     = &Group{Key: "Home", Name: "Home"}
+			chores   = &List{Key: "Home/Chores", Name: "Chores", Group: home}
+			imported = &List{Key: defaultListName, Name: defaultListName}
+			due      = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+			remindAt = time.Date(2024, 12, 31, 8, 0, 0, 0, time.UTC)
+		)
+		assert.Equal(t, []*Group{home}, batch.Groups)
+		assert.Equal(t, []*List{chores, imported}, batch.Lists)
+		assert.Len(t, batch.Tasks, 2)
+		var fence = batch.Tasks[0]
+		assert.Equal(t, "F-1", fence.Key)
+		assert.Equal(t, "Row 2", fence.Ref)
+		assert.Equal(t, chores, fence.List)
+		assert.Equal(t, "White, two coats", fence.Description)
+		assert.Equal(t, types.TaskPriorityUrgent, fence.Priority)
+		assert.True(t, fence.Completed)
+		assert.True(t, due.Equal(*fence.DueDate))
+		assert.True(t, remindAt.Equal(*fence.RemindAt))
+		assert.Equal(t, []*Step{{Description: "Buy paint", Done: true}, {Description: "Sand"}}, fence.Steps)
+		assert.Len(t, fence.Tags, 2)
+		var plants = batch.Tasks[1]
+		assert.Equal(t, defaultListName+"/Water the plants", plants.Key)
+		assert.Equal(t, imported, plants.List)
+		assert.Nil(t, plants.DueDate)
+		assert.Empty(t, plants.Steps)
+		assert.Empty(t, plants.Tags)
+	})
+
+	t.Run("bad rows", func(t *testing.T) {
+		_, err := Parse(FormatCSV, strings.NewReader("title,completed,due_date\n"+
+			"Paint the fence,maybe,tomorrow\n"+
+			"Buy paint,\"no\n"))
+		assert.EqualError(t, err, `["Row 2: The value \"maybe\" of \"completed\" is not true or false.",`+
+			`"Row 2: The value \"tomorrow\" of \"due_date\" is not a date.",`+
+			`"Row 3: The row is malformed: extraneous or missing \" in quoted-field."]`)
+	})
+
+	t.Run("bad header", func(t *testing.T) {
+		for header, message := range map[string]string{
+			"":                   "the CSV file is empty",
+			"name,list\n":        `unknown column "name"; the columns are id, group, list, title, description, priority, completed, due_date, remind_at, tags, steps`,
+			"title,list,Title\n": `the column "title" is repeated`,
+			"list\n":             `the CSV file has no "title" column`,
+		} {
+			_, err := Parse(FormatCSV, strings.NewReader(header))
+			assert.EqualError(t, err, message, "header: %q", header)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"noda/data/types"
+	"noda/ical"
+	"strings"
+)
+
+// parseICalendar reads the VTODOs of a calendar into a list named after the
+// calendar, or defaultListName. Their UID is their key and their CATEGORIES
+// are their tags; the other VCALENDAR components are left out.
+func parseICalendar(b *builder, r io.Reader) error {
+	calendar, err := ical.Decode(r)
+	if nil != err {
+		return err
+	}
+	if "VCALENDAR" != calendar.Name {
+		return fmt.Errorf("expected a VCALENDAR, got a %s", calendar.Name)
+	}
+	var name = defaultListName
+	if p := calendar.Get("X-WR-CALNAME"); nil != p && "" != strings.TrimSpace(p.Text()) {
+		name = p.Text()
+	}
+	var list = b.list(name, name, "", nil)
+	var n = 0
+	for _, todo := range calendar.Components {
+		if "VTODO" != todo.Name {
+			continue
+		}
+		n++
+		var ref = fmt.Sprintf("VTODO %d", n)
+		var uid = todo.Get("UID")
+		if nil == uid || "" == strings.TrimSpace(uid.Value) {
+			b.problem(ref, "The VTODO has no UID.")
+			continue
+		}
+		read, err := ical.ReadTodo(todo)
+		if nil != err {
+			b.problem(ref, "The VTODO has an %s.", err)
+			continue
+		}
+		var task = &Task{
+			Key:         strings.TrimSpace(uid.Value),
+			Ref:         ref,
+			List:        list,
+			Title:       read.Title,
+			Description: read.Description,
+			Priority:    read.Priority,
+			Completed:   types.TaskStatusComplete == read.Status,
+			DueDate:     read.DueDate,
+			RemindAt:    read.RemindAt,
+		}
+		for _, p := range todo.Properties {
+			if "CATEGORIES" != p.Name {
+				continue
+			}
+			for _, category := range splitTextList(p.Value) {
+				if tag := b.tag((&ical.Property{Value: category}).Text(), ""); nil != tag {
+					task.Tags = append(task.Tags, tag)
+				}
+			}
+		}
+		b.task(task)
+	}
+	return nil
+}
+
+// splitTextList splits a list of escaped TEXT values, such as the value of
+// CATEGORIES, on the commas that are not escaped.
+func splitTextList(value string) (values []string) {
+	var start = 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(values, value[start:])
+}
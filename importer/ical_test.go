@@ -0,0 +1,58 @@
+package importer
+
+import (
+	"noda/data/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseICalendar(t *testing.T) {
+	t.Run("VTODOs", func(t *testing.T) {
+		batch, err := Parse(FormatICalendar, strings.NewReader("BEGIN:VCALENDAR\r\n"+
+			"VERSION:2.0\r\n"+
+			"X-WR-CALNAME:Chores\r\n"+
+			"BEGIN:VEVENT\r\nUID:e\r\nSUMMARY:Party\r\nEND:VEVENT\r\n"+
+			"BEGIN:VTODO\r\n"+
+			"UID:0C5D1F8E\r\n"+
+			"SUMMARY:Paint the fence\r\n"+
+			"PRIORITY:1\r\n"+
+			"DUE:20241231T180000Z\r\n"+
+			"STATUS:COMPLETED\r\n"+
+			"CATEGORIES:Home,Paint\\, brushes\r\n"+
+			"CATEGORIES:home\r\n"+
+			"END:VTODO\r\n"+
+			"END:VCALENDAR\r\n"))
+		assert.NoError(t, err)
+		var chores = &List{Key: "Chores", Name: "Chores"}
+		assert.Empty(t, batch.Groups)
+		assert.Equal(t, []*List{chores}, batch.Lists)
+		assert.Equal(t, []*Tag{{Name: "Home", Color: defaultTagColor}, {Name: "Paint, brushes", Color: defaultTagColor}}, batch.Tags)
+		var due = time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+		assert.Equal(t, []*Task{{
+			Key:       "0C5D1F8E",
+			Ref:       "VTODO 1",
+			List:      chores,
+			Title:     "Paint the fence",
+			Priority:  types.TaskPriorityUrgent,
+			Completed: true,
+			DueDate:   &due,
+			Tags:      batch.Tags,
+		}}, batch.Tasks)
+	})
+
+	t.Run("bad VTODOs", func(t *testing.T) {
+		_, err := Parse(FormatICalendar, strings.NewReader("BEGIN:VCALENDAR\r\n"+
+			"BEGIN:VTODO\r\nSUMMARY:Paint the fence\r\nEND:VTODO\r\n"+
+			"BEGIN:VTODO\r\nUID:b\r\nSUMMARY:Buy paint\r\nPRIORITY:high\r\nEND:VTODO\r\n"+
+			"END:VCALENDAR\r\n"))
+		assert.EqualError(t, err, `["VTODO 1: The VTODO has no UID.","VTODO 2: The VTODO has an invalid PRIORITY \"high\"."]`)
+	})
+
+	t.Run("not a calendar", func(t *testing.T) {
+		_, err := Parse(FormatICalendar, strings.NewReader("BEGIN:VTODO\r\nEND:VTODO\r\n"))
+		assert.EqualError(t, err, "expected a VCALENDAR, got a VTODO")
+	})
+}
@@ -0,0 +1,224 @@
+// Package importer reads the tasks exported by other apps, so that they can be
+// made in Noda: the VTODOs of iCalendar files, CSV files, and the JSON exports
+// of Todoist and Trello.
+//
+// Every parser gives a Batch with the groups, lists and tags the tasks belong
+// to, and the tasks with their steps. Groups, lists and tasks have a key that
+// stays the same across exports of the same data, so that importing a file
+// again can skip what was already made. The rows that cannot be read do not
+// stop the parser: each of them becomes a detail of a failure.AggregateDetails.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"noda/data/types"
+	"noda/failure"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Format is the kind of file tasks are imported from.
+type Format string
+
+const (
+	FormatICalendar Format = "ical"
+	FormatCSV       Format = "csv"
+	FormatTodoist   Format = "todoist"
+	FormatTrello    Format = "trello"
+)
+
+// defaultTagColor is the color of the tags whose color is unknown.
+const defaultTagColor = "#808080"
+
+// defaultListName is the name of the list of the tasks that do not say which
+// list they belong to.
+const defaultListName = "Imported"
+
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(s))); format {
+	case FormatICalendar, FormatCSV, FormatTodoist, FormatTrello:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown format %q", s)
+}
+
+type Group struct {
+	Key         string
+	Name        string
+	Description string
+}
+
+// List is a list to import. Its Group is nil if it belongs to none.
+type List struct {
+	Key         string
+	Name        string
+	Description string
+	Group       *Group
+}
+
+// Tag is a tag to attach to tasks. Tags are told apart by their name,
+// regardless of case, so they have no key.
+type Tag struct {
+	Name  string
+	Color string
+}
+
+type Step struct {
+	Description string
+	Done        bool
+}
+
+// Task is a task to import. Ref tells where the task is in the file, such as
+// "Row 3", for the problems reported about it.
+type Task struct {
+	Key         string
+	Ref         string
+	List        *List
+	Title       string
+	Description string
+	Priority    types.TaskPriority
+	Completed   bool
+	DueDate     *time.Time
+	RemindAt    *time.Time
+	Steps       []*Step
+	Tags        []*Tag
+}
+
+// Batch is what a file holds, in the order it is to be made in: the groups
+// before their lists, and the lists before their tasks.
+type Batch struct {
+	Groups []*Group
+	Lists  []*List
+	Tags   []*Tag
+	Tasks  []*Task
+}
+
+// Parse reads a file in the given format. If some rows cannot be read, err is
+// a *failure.AggregateDetails with a detail per row and batch is nil; any
+// other error means that the file could not be read at all.
+func Parse(format Format, r io.Reader) (batch *Batch, err error) {
+	var b = newBuilder()
+	switch format {
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	case FormatICalendar:
+		err = parseICalendar(b, r)
+	case FormatCSV:
+		err = parseCSV(b, r)
+	case FormatTodoist:
+		err = parseTodoist(b, r)
+	case FormatTrello:
+		err = parseTrello(b, r)
+	}
+	if nil != err {
+		return nil, err
+	}
+	if b.problems.Has() {
+		return nil, &b.problems
+	}
+	return &b.batch, nil
+}
+
+// builder gathers a Batch, making each group, list and tag once.
+type builder struct {
+	batch    Batch
+	groups   map[string]*Group
+	lists    map[string]*List
+	tags     map[string]*Tag
+	tasks    map[string]string // The refs of the tasks by key.
+	problems failure.AggregateDetails
+}
+
+func newBuilder() *builder {
+	return &builder{
+		groups: make(map[string]*Group),
+		lists:  make(map[string]*List),
+		tags:   make(map[string]*Tag),
+		tasks:  make(map[string]string),
+	}
+}
+
+func (b *builder) group(key, name, description string) *Group {
+	if group, ok := b.groups[key]; ok {
+		return group
+	}
+	var group = &Group{Key: key, Name: strings.TrimSpace(name), Description: strings.TrimSpace(description)}
+	b.groups[key] = group
+	b.batch.Groups = append(b.batch.Groups, group)
+	return group
+}
+
+func (b *builder) list(key, name, description string, group *Group) *List {
+	if list, ok := b.lists[key]; ok {
+		return list
+	}
+	var list = &List{Key: key, Name: strings.TrimSpace(name), Description: strings.TrimSpace(description), Group: group}
+	b.lists[key] = list
+	b.batch.Lists = append(b.batch.Lists, list)
+	return list
+}
+
+// tag returns the tag with the given name, made with the given color the first
+// time, or with defaultTagColor if the color is empty. Blank names give nil.
+func (b *builder) tag(name, color string) *Tag {
+	name = strings.TrimSpace(name)
+	if "" == name {
+		return nil
+	}
+	var key = strings.ToLower(name)
+	if tag, ok := b.tags[key]; ok {
+		return tag
+	}
+	if "" == color {
+		color = defaultTagColor
+	}
+	var tag = &Tag{Name: name, Color: color}
+	b.tags[key] = tag
+	b.batch.Tags = append(b.batch.Tags, tag)
+	return tag
+}
+
+// task adds a task, unless it has no title or another task has its key, which
+// are reported as problems. A tag given twice is attached once.
+func (b *builder) task(task *Task) {
+	task.Title = strings.TrimSpace(task.Title)
+	task.Description = strings.TrimSpace(task.Description)
+	if "" == task.Priority {
+		task.Priority = types.TaskPriorityNormal
+	}
+	var tags = task.Tags[:0]
+	for _, tag := range task.Tags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	task.Tags = tags
+	switch ref, taken := b.tasks[task.Key]; {
+	case "" == task.Title:
+		b.problem(task.Ref, "The task has no title.")
+	case taken:
+		b.problem(task.Ref, "The task has the same key as another task (%s).", ref)
+	default:
+		b.tasks[task.Key] = task.Ref
+		b.batch.Tasks = append(b.batch.Tasks, task)
+	}
+}
+
+func (b *builder) problem(ref, format string, a ...any) {
+	b.problems.Append(ref + ": " + fmt.Sprintf(format, a...))
+}
+
+// parseTime reads a date-time in one of the given layouts. Layouts without a
+// zone are read in UTC.
+func parseTime(value string, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); nil == err {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("unknown layout")
+}
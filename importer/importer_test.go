@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"noda/data/types"
+	"noda/failure"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	for value, expected := range map[string]Format{
+		"ical":    FormatICalendar,
+		" CSV ":   FormatCSV,
+		"todoist": FormatTodoist,
+		"Trello":  FormatTrello,
+	} {
+		got, err := ParseFormat(value)
+		assert.NoError(t, err, "value: %q", value)
+		assert.Equal(t, expected, got, "value: %q", value)
+	}
+	_, err := ParseFormat("xlsx")
+	assert.Error(t, err)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("one problem per row", func(t *testing.T) {
+		batch, err := Parse(FormatCSV, strings.NewReader("id,title,priority\n"+
+			"1,Buy paint,high\n"+
+			"2,,\n"+
+			"1,Paint the fence,\n"+
+			"3,Clean the brushes,soon\n"))
+		assert.Nil(t, batch)
+		var aggregate *failure.AggregateDetails
+		assert.ErrorAs(t, err, &aggregate)
+		assert.Equal(t, `["Row 3: The task has no title.",`+
+			`"Row 4: The task has the same key as another task (Row 2).",`+
+			`"Row 5: The priority \"soon\" is not one of urgent, high, medium, normal or low."]`, aggregate.Error())
+	})
+
+	t.Run("tags by name", func(t *testing.T) {
+		batch, err := Parse(FormatCSV, strings.NewReader("title,tags\n"+
+			"Buy paint,Errands;errands\n"+
+			"Paint the fence,errands; Home\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, []*Tag{{Name: "Errands", Color: defaultTagColor}, {Name: "Home", Color: defaultTagColor}}, batch.Tags)
+		assert.Equal(t, []*Tag{batch.Tags[0]}, batch.Tasks[0].Tags)
+		assert.Equal(t, []*Tag{batch.Tags[0], batch.Tags[1]}, batch.Tasks[1].Tags)
+		assert.Equal(t, types.TaskPriorityNormal, batch.Tasks[0].Priority)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := Parse("xlsx", strings.NewReader(""))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,215 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"noda/data/types"
+	"time"
+)
+
+// todoistExport is the part of a Todoist export, as the Sync API gives it,
+// that tasks are made from.
+type todoistExport struct {
+	Projects []struct {
+		ID         todoistID  `json:"id"`
+		Name       string     `json:"name"`
+		ParentID   *todoistID `json:"parent_id"`
+		IsArchived bool       `json:"is_archived"`
+		IsDeleted  bool       `json:"is_deleted"`
+	} `json:"projects"`
+	Items []struct {
+		ID          todoistID  `json:"id"`
+		ProjectID   todoistID  `json:"project_id"`
+		ParentID    *todoistID `json:"parent_id"`
+		Content     string     `json:"content"`
+		Description string     `json:"description"`
+		Priority    int        `json:"priority"`
+		Due         *struct {
+			Date string `json:"date"`
+		} `json:"due"`
+		Checked   bool     `json:"checked"`
+		IsDeleted bool     `json:"is_deleted"`
+		Labels    []string `json:"labels"`
+	} `json:"items"`
+	Labels []struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Reminders []struct {
+		ItemID todoistID `json:"item_id"`
+		Type   string    `json:"type"`
+		Due    *struct {
+			Date string `json:"date"`
+		} `json:"due"`
+		IsDeleted bool `json:"is_deleted"`
+	} `json:"reminders"`
+}
+
+// todoistID is an ID of a Todoist export, which older exports give as numbers.
+type todoistID string
+
+func (id *todoistID) UnmarshalJSON(data []byte) error {
+	if 0 < len(data) && '"' == data[0] {
+		var s string
+		if err := json.Unmarshal(data, &s); nil != err {
+			return err
+		}
+		*id = todoistID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); nil != err {
+		return err
+	}
+	*id = todoistID(n)
+	return nil
+}
+
+// todoistColors are the hexadecimal values of the colors of Todoist.
+var todoistColors = map[string]string{
+	"berry_red":   "#b8256f",
+	"red":         "#db4035",
+	"orange":      "#ff9933",
+	"yellow":      "#fad000",
+	"olive_green": "#afb83b",
+	"lime_green":  "#7ecc49",
+	"green":       "#299438",
+	"mint_green":  "#6accbc",
+	"teal":        "#158fad",
+	"sky_blue":    "#14aaf5",
+	"light_blue":  "#96c3eb",
+	"blue":        "#4073ff",
+	"grape":       "#884dff",
+	"violet":      "#af38eb",
+	"lavender":    "#eb96eb",
+	"magenta":     "#e05194",
+	"salmon":      "#ff8d85",
+	"charcoal":    "#808080",
+	"grey":        "#b8b8b8",
+	"taupe":       "#ccac93",
+}
+
+// todoistPriorities are the priorities of Todoist, from 1 for normal tasks to 4
+// for urgent ones.
+var todoistPriorities = map[int]types.TaskPriority{
+	1: types.TaskPriorityNormal,
+	2: types.TaskPriorityMedium,
+	3: types.TaskPriorityHigh,
+	4: types.TaskPriorityUrgent,
+}
+
+// todoistTimeLayouts are the layouts of the due dates of Todoist: a whole day,
+// a floating date-time, and a date-time in UTC.
+var todoistTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// parseTodoist reads the items of a Todoist export. Each project is a list,
+// in a group named after its parent if it has one, and the subtasks of an item
+// are steps of the task it is. The archived projects and the deleted items are
+// left out.
+func parseTodoist(b *builder, r io.Reader) error {
+	var export todoistExport
+	if err := json.NewDecoder(r).Decode(&export); nil != err {
+		return fmt.Errorf("the Todoist export is malformed: %w", err)
+	}
+	var names = make(map[todoistID]string, len(export.Projects))
+	for _, project := range export.Projects {
+		names[project.ID] = project.Name
+	}
+	var lists = make(map[todoistID]*List, len(export.Projects))
+	for _, project := range export.Projects {
+		if project.IsArchived || project.IsDeleted {
+			continue
+		}
+		var group *Group
+		if nil != project.ParentID {
+			if name, ok := names[*project.ParentID]; ok {
+				group = b.group(string(*project.ParentID), name, "")
+			}
+		}
+		lists[project.ID] = b.list(string(project.ID), project.Name, "", group)
+	}
+	var colors = make(map[string]string, len(export.Labels))
+	for _, label := range export.Labels {
+		colors[label.Name] = todoistColors[label.Color]
+	}
+	var reminders = make(map[todoistID]string, len(export.Reminders))
+	for _, reminder := range export.Reminders {
+		if !reminder.IsDeleted && "absolute" == reminder.Type && nil != reminder.Due {
+			if _, ok := reminders[reminder.ItemID]; !ok {
+				reminders[reminder.ItemID] = reminder.Due.Date
+			}
+		}
+	}
+	var parents = make(map[todoistID]todoistID, len(export.Items))
+	for _, item := range export.Items {
+		if nil != item.ParentID {
+			parents[item.ID] = *item.ParentID
+		}
+	}
+	var tasks = make(map[todoistID]*Task, len(export.Items))
+	var steps = make(map[todoistID][]*Step, len(export.Items))
+	for _, item := range export.Items {
+		if item.IsDeleted {
+			continue
+		}
+		var ref = fmt.Sprintf("Item %s", item.ID)
+		if nil != item.ParentID {
+			var root = *item.ParentID
+			for i := 0; i < len(parents); i++ { /* Bounded, in case of a cycle.  */
+				parent, ok := parents[root]
+				if !ok {
+					break
+				}
+				root = parent
+			}
+			steps[root] = append(steps[root], &Step{Description: item.Content, Done: item.Checked})
+			continue
+		}
+		var list, ok = lists[item.ProjectID]
+		if !ok {
+			continue
+		}
+		var task = &Task{
+			Key:         string(item.ID),
+			Ref:         ref,
+			List:        list,
+			Title:       item.Content,
+			Description: item.Description,
+			Completed:   item.Checked,
+		}
+		if task.Priority, ok = todoistPriorities[item.Priority]; !ok && 0 != item.Priority {
+			b.problem(ref, "The priority %d is not between 1 and 4.", item.Priority)
+			continue
+		}
+		if nil != item.Due && "" != item.Due.Date {
+			due, err := parseTime(item.Due.Date, todoistTimeLayouts...)
+			if nil != err {
+				b.problem(ref, "The due date %q is not a date.", item.Due.Date)
+				continue
+			}
+			task.DueDate = &due
+		}
+		if date, ok := reminders[item.ID]; ok {
+			remindAt, err := parseTime(date, todoistTimeLayouts...)
+			if nil != err {
+				b.problem(ref, "The reminder %q is not a date.", date)
+				continue
+			}
+			task.RemindAt = &remindAt
+		}
+		for _, name := range item.Labels {
+			if tag := b.tag(name, colors[name]); nil != tag {
+				task.Tags = append(task.Tags, tag)
+			}
+		}
+		tasks[item.ID] = task
+		b.task(task)
+	}
+	for id, found := range steps {
+		if task, ok := tasks[id]; ok {
+			task.Steps = found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package importer
+
+import (
+	"noda/data/types"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTodoist(t *testing.T) {
+	t.Run("projects and items", func(t *testing.T) {
+		batch, err := Parse(FormatTodoist, strings.NewReader(`{
+			"projects": [
+				{"id": "100", "name": "Home", "parent_id": null},
+				{"id": "101", "name": "Chores", "parent_id": "100"},
+				{"id": 102, "name": "Old", "parent_id": null, "is_archived": true}
+			],
+			"items": [
+				{"id": "1", "project_id": "101", "content": "Paint the fence", "priority": 4,
+				 "due": {"date": "2024-12-31T18:00:00Z"}, "labels": ["outside"]},
+				{"id": "2", "project_id": "101", "parent_id": "1", "content": "Buy paint", "checked": true},
+				{"id": "3", "project_id": "101", "parent_id": "2", "content": "Find a shop"},
+				{"id": "4", "project_id": "100", "content": "Water the plants", "due": {"date": "2024-12-24"}, "checked": true},
+				{"id": "5", "project_id": "102", "content": "Archived"},
+				{"id": "6", "project_id": "101", "content": "Deleted", "is_deleted": true}
+			],
+			"labels": [{"id": "9", "name": "outside", "color": "green"}],
+			"reminders": [{"id": "8", "item_id": "1", "type": "absolute", "due": {"date": "2024-12-31T09:00:00"}}]
+		}`))
+		assert.NoError(t, err)
+		var (
+			home   = &Group{Key: "100", Name: "Home"}
+			chores = &List{Key: "101", Name: "Chores", Group: home}
+		)
+		assert.Equal(t, []*Group{home}, batch.Groups)
+		assert.Equal(t, []*List{{Key: "100", Name: "Home"}, chores}, batch.Lists)
+		assert.Equal(t, []*Tag{{Name: "outside", Color: "#299438"}}, batch.Tags)
+		assert.Len(t, batch.Tasks, 2)
+		var (
+			fence    = batch.Tasks[0]
+			due      = time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+			remindAt = time.Date(2024, 12, 31, 9, 0, 0, 0, time.UTC)
+		)
+		assert.Equal(t, &Task{
+			Key:      "1",
+			Ref:      "Item 1",
+			List:     chores,
+			Title:    "Paint the fence",
+			Priority: types.TaskPriorityUrgent,
+			DueDate:  &due,
+			RemindAt: &remindAt,
+			Steps:    []*Step{{Description: "Buy paint", Done: true}, {Description: "Find a shop"}},
+			Tags:     batch.Tags,
+		}, fence)
+		var plants = batch.Tasks[1]
+		assert.Equal(t, "Water the plants", plants.Title)
+		assert.Equal(t, types.TaskPriorityNormal, plants.Priority)
+		assert.True(t, plants.Completed)
+		assert.True(t, time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC).Equal(*plants.DueDate))
+	})
+
+	t.Run("bad items", func(t *testing.T) {
+		_, err := Parse(FormatTodoist, strings.NewReader(`{
+			"projects": [{"id": "100", "name": "Home"}],
+			"items": [
+				{"id": "1", "project_id": "100", "content": "Paint the fence", "priority": 7},
+				{"id": "2", "project_id": "100", "content": "Buy paint", "due": {"date": "soon"}}
+			]
+		}`))
+		assert.EqualError(t, err, `["Item 1: The priority 7 is not between 1 and 4.","Item 2: The due date \"soon\" is not a date."]`)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		_, err := Parse(FormatTodoist, strings.NewReader(`{"items": {}}`))
+		assert.ErrorContains(t, err, "the Todoist export is malformed")
+	})
+}
@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// trelloExport is the part of the JSON export of a Trello board that tasks
+// are made from.
+type trelloExport struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Desc  string `json:"desc"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID           string   `json:"id"`
+		Name         string   `json:"name"`
+		Desc         string   `json:"desc"`
+		IDList       string   `json:"idList"`
+		Closed       bool     `json:"closed"`
+		Due          *string  `json:"due"`
+		DueComplete  bool     `json:"dueComplete"`
+		DueReminder  *int     `json:"dueReminder"`
+		IDLabels     []string `json:"idLabels"`
+		IDChecklists []string `json:"idChecklists"`
+	} `json:"cards"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Checklists []struct {
+		ID         string `json:"id"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// trelloColors are the hexadecimal values of the colors of Trello labels.
+var trelloColors = map[string]string{
+	"green":  "#61bd4f",
+	"yellow": "#f2d600",
+	"orange": "#ff9f1a",
+	"red":    "#eb5a46",
+	"purple": "#c377e0",
+	"blue":   "#0079bf",
+	"sky":    "#00c2e0",
+	"lime":   "#51e898",
+	"pink":   "#ff78cb",
+	"black":  "#344563",
+}
+
+// parseTrello reads the cards of a Trello board. The board is a group, each of
+// its lists is a list, and the items of the checklists of a card are the steps
+// of its task. A card is completed when its due date is, and its labels are
+// its tags; the labels with no name are named after their color. The closed
+// lists and cards are left out.
+func parseTrello(b *builder, r io.Reader) error {
+	var export trelloExport
+	if err := json.NewDecoder(r).Decode(&export); nil != err {
+		return fmt.Errorf("the Trello export is malformed: %w", err)
+	}
+	if "" == export.ID {
+		return errors.New("the Trello export has no board")
+	}
+	var group = b.group(export.ID, export.Name, export.Desc)
+	var lists = make(map[string]*List, len(export.Lists))
+	for _, list := range export.Lists {
+		if !list.Closed {
+			lists[list.ID] = b.list(list.ID, list.Name, "", group)
+		}
+	}
+	var labels = make(map[string]*Tag, len(export.Labels))
+	for _, label := range export.Labels {
+		var name = label.Name
+		if "" == name {
+			name = label.Color
+		}
+		labels[label.ID] = &Tag{Name: name, Color: trelloColors[label.Color]}
+	}
+	var checklists = make(map[string][]*Step, len(export.Checklists))
+	for _, checklist := range export.Checklists {
+		var items = checklist.CheckItems
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+		for _, item := range items {
+			checklists[checklist.ID] = append(checklists[checklist.ID],
+				&Step{Description: item.Name, Done: "complete" == item.State})
+		}
+	}
+	for _, card := range export.Cards {
+		var list, ok = lists[card.IDList]
+		if card.Closed || !ok {
+			continue
+		}
+		var ref = fmt.Sprintf("Card %s", card.ID)
+		var task = &Task{
+			Key:         card.ID,
+			Ref:         ref,
+			List:        list,
+			Title:       card.Name,
+			Description: card.Desc,
+			Completed:   card.DueComplete,
+		}
+		if nil != card.Due {
+			due, err := time.Parse(time.RFC3339, *card.Due)
+			if nil != err {
+				b.problem(ref, "The due date %q is not a date.", *card.Due)
+				continue
+			}
+			task.DueDate = &due
+			if nil != card.DueReminder && 0 <= *card.DueReminder {
+				var remindAt = due.Add(-time.Duration(*card.DueReminder) * time.Minute)
+				task.RemindAt = &remindAt
+			}
+		}
+		for _, id := range card.IDLabels {
+			if label, ok := labels[id]; ok {
+				if tag := b.tag(label.Name, label.Color); nil != tag {
+					task.Tags = append(task.Tags, tag)
+				}
+			}
+		}
+		for _, id := range card.IDChecklists {
+			task.Steps = append(task.Steps, checklists[id]...)
+		}
+		b.task(task)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrello(t *testing.T) {
+	t.Run("board", func(t *testing.T) {
+		batch, err := Parse(FormatTrello, strings.NewReader(`{
+			"id": "b1", "name": "House", "desc": "Things to fix",
+			"lists": [
+				{"id": "l1", "name": "To do"},
+				{"id": "l2", "name": "Done", "closed": true}
+			],
+			"cards": [
+				{"id": "c1", "name": "Paint the fence", "desc": "White", "idList": "l1",
+				 "due": "2024-12-31T18:00:00.000Z", "dueComplete": true, "dueReminder": 60,
+				 "idLabels": ["t1", "t2"], "idChecklists": ["k1"]},
+				{"id": "c2", "name": "Sweep", "idList": "l2"},
+				{"id": "c3", "name": "Closed", "idList": "l1", "closed": true}
+			],
+			"labels": [{"id": "t1", "name": "Outside", "color": "green"}, {"id": "t2", "name": "", "color": "red"}],
+			"checklists": [{"id": "k1", "checkItems": [
+				{"name": "Sand", "state": "incomplete", "pos": 2},
+				{"name": "Buy paint", "state": "complete", "pos": 1}
+			]}]
+		}`))
+		assert.NoError(t, err)
+		var (
+			house    = &Group{Key: "b1", Name: "House", Description: "Things to fix"}
+			toDo     = &List{Key: "l1", Name: "To do", Group: house}
+			due      = time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+			remindAt = time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC)
+		)
+		assert.Equal(t, []*Group{house}, batch.Groups)
+		assert.Equal(t, []*List{toDo}, batch.Lists)
+		assert.Equal(t, []*Tag{{Name: "Outside", Color: "#61bd4f"}, {Name: "red", Color: "#eb5a46"}}, batch.Tags)
+		assert.Len(t, batch.Tasks, 1)
+		var fence = batch.Tasks[0]
+		assert.Equal(t, "c1", fence.Key)
+		assert.Equal(t, "Card c1", fence.Ref)
+		assert.Equal(t, toDo, fence.List)
+		assert.Equal(t, "White", fence.Description)
+		assert.True(t, fence.Completed)
+		assert.True(t, due.Equal(*fence.DueDate))
+		assert.True(t, remindAt.Equal(*fence.RemindAt))
+		assert.Equal(t, []*Step{{Description: "Buy paint", Done: true}, {Description: "Sand"}}, fence.Steps)
+		assert.Equal(t, batch.Tags, fence.Tags)
+	})
+
+	t.Run("bad cards", func(t *testing.T) {
+		_, err := Parse(FormatTrello, strings.NewReader(`{
+			"id": "b1", "name": "House",
+			"lists": [{"id": "l1", "name": "To do"}],
+			"cards": [{"id": "c1", "name": "Paint the fence", "idList": "l1", "due": "tomorrow"}, {"id": "c2", "idList": "l1"}]
+		}`))
+		assert.EqualError(t, err, `["Card c1: The due date \"tomorrow\" is not a date.","Card c2: The task has no title."]`)
+	})
+
+	t.Run("not a board", func(t *testing.T) {
+		_, err := Parse(FormatTrello, strings.NewReader(`{}`))
+		assert.EqualError(t, err, "the Trello export has no board")
+	})
+}
@@ -0,0 +1,40 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrNoRecipients is returned when a message has nobody to be sent to.
+var ErrNoRecipients = errors.New("message has no recipients")
+
+// ErrInvalidHeader is returned when an address or the subject of a message
+// contains a line break, which would let it inject headers.
+var ErrInvalidHeader = errors.New("invalid message header")
+
+// Message is a plain text email.
+type Message struct {
+	To      []string // To are the addresses of the recipients.
+	Subject string
+	Body    string
+}
+
+// Mailer abstracts the way emails are delivered.
+type Mailer interface {
+	// Send delivers message to all its recipients.
+	Send(ctx context.Context, message *Message) error
+}
+
+// checkMessage makes sure message can be safely written as an email.
+func checkMessage(message *Message) error {
+	if nil == message || 0 == len(message.To) {
+		return ErrNoRecipients
+	}
+	for _, header := range append([]string{message.Subject}, message.To...) {
+		if strings.ContainsAny(header, "\r\n") {
+			return ErrInvalidHeader
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink is a local SMTP server that accepts every message and keeps it.
+type smtpSink struct {
+	listener net.Listener
+	mu       sync.Mutex
+	commands []string
+	data     []string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	var sink = &smtpSink{listener: listener}
+	go sink.serve()
+	t.Cleanup(func() { listener.Close() })
+	return sink
+}
+
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpSink) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if nil != err {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpSink) handle(conn net.Conn) {
+	defer conn.Close()
+	var (
+		r     = bufio.NewReader(conn)
+		reply = func(line string) { io.WriteString(conn, line+"\r\n") }
+	)
+	reply("220 localhost ESMTP sink")
+	for {
+		line, err := r.ReadString('\n')
+		if nil != err {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+		switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+		default:
+			reply("250 OK")
+		case "EHLO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 Authenticated")
+		case "DATA":
+			reply("354 Go ahead")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if nil != err {
+					return
+				}
+				if ".\r\n" == line {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.mu.Lock()
+			s.data = append(s.data, data.String())
+			s.mu.Unlock()
+			reply("250 Queued")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		}
+	}
+}
+
+func (s *smtpSink) received() (commands, data []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...), append([]string(nil), s.data...)
+}
+
+func TestSMTPMailer(t *testing.T) {
+	var (
+		sink   = newSMTPSink(t)
+		mailer = NewSMTPMailer(SMTPConfig{
+			Host:     "127.0.0.1",
+			Port:     sink.port(),
+			Username: "noda",
+			Password: "secret",
+			From:     "Noda <no-reply@noda.test>",
+		})
+		message = &Message{
+			To:      []string{"Ada <ada@example.com>", "grace@example.com"},
+			Subject: "Réinitialisation du mot de passe",
+			Body:    "Hello,\n\nUse this link to reset your password.\n",
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	)
+	defer cancel()
+
+	t.Run("success", func(t *testing.T) {
+		require.NoError(t, mailer.Send(ctx, message))
+		commands, data := sink.received()
+		assert.Contains(t, commands, "MAIL FROM:<no-reply@noda.test>")
+		assert.Contains(t, commands, "RCPT TO:<ada@example.com>")
+		assert.Contains(t, commands, "RCPT TO:<grace@example.com>")
+		assert.Contains(t, commands, "AUTH PLAIN AG5vZGEAc2VjcmV0")
+		require.Len(t, data, 1)
+		parsed, err := mail.ReadMessage(strings.NewReader(data[0]))
+		require.NoError(t, err)
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		assert.NoError(t, err)
+		assert.Equal(t, message.Subject, subject)
+		assert.Equal(t, "\"Noda\" <no-reply@noda.test>", parsed.Header.Get("From"))
+		assert.NotEmpty(t, parsed.Header.Get("Message-ID"))
+		body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+		assert.NoError(t, err)
+		assert.Equal(t, strings.ReplaceAll(message.Body, "\n", "\r\n"), string(body))
+	})
+
+	t.Run("invalid recipient", func(t *testing.T) {
+		var err = mailer.Send(ctx, &Message{To: []string{"not an address"}})
+		assert.ErrorContains(t, err, "invalid recipient")
+	})
+
+	t.Run("server not reachable", func(t *testing.T) {
+		var mailer = NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: 1, From: "no-reply@noda.test"})
+		assert.Error(t, mailer.Send(ctx, message))
+	})
+}
+
+func TestMemoryMailer(t *testing.T) {
+	var (
+		mailer  = NewMemoryMailer()
+		ctx     = context.Background()
+		message = &Message{To: []string{"ada@example.com"}, Subject: "Hello", Body: "Hi."}
+	)
+	require.NoError(t, mailer.Send(ctx, message))
+	message.To[0] = "changed@example.com"
+	var messages = mailer.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "ada@example.com", messages[0].To[0])
+	assert.Equal(t, "Hello", messages[0].Subject)
+}
+
+func TestCheckMessage(t *testing.T) {
+	var mailer = NewMemoryMailer()
+	var ctx = context.Background()
+	assert.ErrorIs(t, mailer.Send(ctx, nil), ErrNoRecipients)
+	assert.ErrorIs(t, mailer.Send(ctx, &Message{}), ErrNoRecipients)
+	assert.ErrorIs(t, mailer.Send(ctx, &Message{To: []string{"a@b.c"}, Subject: "Hi\r\nBcc: x@y.z"}), ErrInvalidHeader)
+	assert.ErrorIs(t, mailer.Send(ctx, &Message{To: []string{"a@b.c\nBcc: x@y.z"}}), ErrInvalidHeader)
+	assert.Empty(t, mailer.Messages())
+}
@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryMailer is a Mailer that keeps the messages instead of delivering
+// them. It is meant for tests and local development.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return new(MemoryMailer)
+}
+
+func (m *MemoryMailer) Send(_ context.Context, message *Message) error {
+	if err := checkMessage(message); nil != err {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sent = *message
+	sent.To = slices.Clone(message.To)
+	m.messages = append(m.messages, sent)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.messages)
+}
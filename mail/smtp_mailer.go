@@ -0,0 +1,145 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig holds what is needed to deliver emails through an SMTP server.
+type SMTPConfig struct {
+	Host     string // Host is the name of the server.
+	Port     int    // Port is 587 if not set.
+	Username string // Username is used to authenticate if it is not empty.
+	Password string
+	From     string // From is the address the emails are sent from.
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+	now    func() time.Time
+}
+
+// NewSMTPMailer returns a Mailer that delivers the messages through an SMTP
+// server. STARTTLS is used whenever the server supports it, and credentials
+// are only sent over TLS or to a server running on localhost.
+func NewSMTPMailer(config SMTPConfig) Mailer {
+	if 0 == config.Port {
+		config.Port = 587
+	}
+	return &smtpMailer{config: config, now: time.Now}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message *Message) error {
+	if err := checkMessage(message); nil != err {
+		return err
+	}
+	var recipients = make([]string, 0, len(message.To))
+	for _, to := range message.To {
+		address, err := mail.ParseAddress(to)
+		if nil != err {
+			return fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		recipients = append(recipients, address.Address)
+	}
+	from, err := mail.ParseAddress(m.config.From)
+	if nil != err {
+		return fmt.Errorf("invalid sender %q: %w", m.config.From, err)
+	}
+	data, err := m.compose(from, message)
+	if nil != err {
+		return err
+	}
+
+	var (
+		address = net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+		dialer  net.Dialer
+	)
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if nil != err {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if nil != err {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: m.config.Host})
+		if nil != err {
+			return err
+		}
+	}
+	if "" != m.config.Username {
+		var auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err = client.Auth(auth); nil != err {
+			return err
+		}
+	}
+	if err = client.Mail(from.Address); nil != err {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err = client.Rcpt(recipient); nil != err {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if nil != err {
+		return err
+	}
+	if _, err = w.Write(data); nil != err {
+		w.Close()
+		return err
+	}
+	if err = w.Close(); nil != err {
+		return err
+	}
+	return client.Quit()
+}
+
+// compose writes the message in the Internet Message Format, with the body
+// encoded as quoted-printable UTF-8 text.
+func (m *smtpMailer) compose(from *mail.Address, message *Message) ([]byte, error) {
+	var id = make([]byte, 16)
+	if _, err := rand.Read(id); nil != err {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	var header = func(key, value string) {
+		buf.WriteString(key + ": " + value + "\r\n")
+	}
+	header("From", from.String())
+	header("To", strings.Join(message.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	header("Date", m.now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+hex.EncodeToString(id)+"@"+m.config.Host+">")
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "quoted-printable")
+	buf.WriteString("\r\n")
+	var body = quotedprintable.NewWriter(&buf)
+	var text = strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n")
+	if _, err := body.Write([]byte(text)); nil != err {
+		return nil, err
+	}
+	if err := body.Close(); nil != err {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
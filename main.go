@@ -10,16 +10,24 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"noda/audit"
+	"noda/data/transfer"
 	"noda/data/types"
+	"noda/events"
 	"noda/failure"
 	"noda/global"
 	"noda/handler"
+	"noda/mail"
+	"noda/notify"
 	"noda/repository"
+	"noda/scheduler"
 	"noda/service"
+	"noda/storage"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -35,11 +43,82 @@ func mustGetEnv(key string) string {
 	return value
 }
 
+// getEnv gets an env var or returns fallback if it is not set.
+func getEnv(key, fallback string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if "" == value {
+		return fallback
+	}
+	return value
+}
+
+// newBlobStore builds the storage for uploaded files according to the
+// BLOB_STORE env var, which is either "local" (default) or "s3".
+func newBlobStore() storage.BlobStore {
+	switch backend := getEnv("BLOB_STORE", "local"); backend {
+	default:
+		log.Fatalf("unknown blob store: %q", backend)
+		return nil
+	case "local":
+		store, err := storage.NewLocalBlobStore(getEnv("BLOB_STORE_ROOT", "uploads"))
+		if nil != err {
+			log.Fatalf("could not create local blob store: %v", err)
+		}
+		return store
+	case "s3":
+		pathStyle, _ := strconv.ParseBool(getEnv("S3_PATH_STYLE", "false"))
+		return storage.NewS3BlobStore(storage.S3Config{
+			Endpoint:        mustGetEnv("S3_ENDPOINT"),
+			Region:          mustGetEnv("S3_REGION"),
+			Bucket:          mustGetEnv("S3_BUCKET"),
+			AccessKeyID:     mustGetEnv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: mustGetEnv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       pathStyle,
+		}, nil)
+	}
+}
+
+// denylist holds the sessions that were logged out while their access tokens
+// are still valid. It is set up in main.
+var denylist service.Denylist
+
+// newMailer builds the mail delivery according to the MAILER env var, which is
+// either "memory" (default) or "smtp".
+func newMailer() mail.Mailer {
+	switch backend := getEnv("MAILER", "memory"); backend {
+	default:
+		log.Fatalf("unknown mailer: %q", backend)
+		return nil
+	case "memory":
+		log.Println("emails are kept in memory and will not be delivered; set MAILER=smtp to deliver them")
+		return mail.NewMemoryMailer()
+	case "smtp":
+		port, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+		if nil != err {
+			log.Fatalf("invalid SMTP_PORT: %v", err)
+		}
+		return mail.NewSMTPMailer(mail.SMTPConfig{
+			Host:     mustGetEnv("SMTP_HOST"),
+			Port:     port,
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     mustGetEnv("SMTP_FROM"),
+		})
+	}
+}
+
+// users is consulted by withAuthorization for the current status of the users.
+// It is set up in main.
+var users service.UserService
+
 // withAuthorization returns a middleware that performs JWT-based authorization.
 // It verifies the token's validity and parses its claims. If the token is
 // invalid or malformed, it responds with an appropriate error. If the token is
 // valid, it extracts user information from the claims and adds it to the request
-// context.
+// context. Tokens without a session, or of sessions that were logged out, are
+// refused, and so are the users that were blocked or deleted since the token was issued. The role
+// put in the context is the current one of the user, not the one in the
+// token.
 func withAuthorization(next http.HandlerFunc) http.HandlerFunc {
 	secret := global.Secret()
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -92,14 +171,104 @@ func withAuthorization(next http.HandlerFunc) http.HandlerFunc {
 			failure.EmitError(w, failure.ErrCorruptedClaim)
 			return
 		}
+		sid, _ := claims["sid"].(string)
+		sessionID, err := uuid.Parse(sid)
+		if err != nil {
+			failure.EmitError(w, failure.ErrCorruptedClaim)
+			return
+		}
+		denied, err := denylist.Contains(sessionID.String())
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if denied {
+			failure.EmitError(w, failure.ErrRevokedToken)
+			return
+		}
+		role, err := users.AssertActive(id)
+		if err != nil {
+			var e *failure.Error
+			switch {
+			default:
+				log.Println(err)
+				w.WriteHeader(http.StatusInternalServerError)
+			case errors.Is(err, failure.ErrUserNotFound):
+				failure.EmitError(w, failure.ErrUserNoLongerExists)
+			case errors.As(err, &e):
+				failure.EmitError(w, e)
+			}
+			return
+		}
+		var payload = types.JWTPayload{
+			UserID:    id,
+			UserRole:  role,
+			SessionID: sessionID}
+		if expiresAt, _ := claims.GetExpirationTime(); nil != expiresAt {
+			payload.ExpiresAt = expiresAt.Time
+		}
+		ctx := context.WithValue(r.Context(), types.ContextKey{}, payload)
+		r = r.Clone(ctx)
+		next.ServeHTTP(w, r)
+	}
+}
+
+// authenticator is consulted by withBasicAuthorization for the credentials of
+// the users. It is set up in main.
+var authenticator service.AuthenticationService
+
+// withBasicAuthorization returns a middleware that performs HTTP Basic
+// authorization with the email address and the password of an active user,
+// for clients that cannot hold a JSON Web Token, such as CalDAV ones. The user
+// is added to the request context as withAuthorization does, without a
+// session.
+func withBasicAuthorization(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		email, password, ok := r.BasicAuth()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Basic realm=\"Noda\", charset=\"UTF-8\"")
+			failure.EmitError(w, failure.ErrMissingAuthorizationHeader)
+			return
+		}
+		id, role, err := authenticator.Authenticate(&transfer.UserCredentials{Email: email, Password: password})
+		if err != nil {
+			var e *failure.Error
+			switch {
+			default:
+				log.Println(err)
+				w.WriteHeader(http.StatusInternalServerError)
+			case errors.Is(err, failure.ErrUserNotFound), errors.Is(err, failure.ErrIncorrectPassword):
+				w.Header().Set("WWW-Authenticate", "Basic realm=\"Noda\", charset=\"UTF-8\"")
+				failure.EmitError(w, failure.ErrIncorrectPassword.Clone().SetStatus(http.StatusUnauthorized))
+			case errors.As(err, &e):
+				failure.EmitError(w, e)
+			}
+			return
+		}
 		ctx := context.WithValue(r.Context(), types.ContextKey{}, types.JWTPayload{
 			UserID:   id,
-			UserRole: types.Role(claims["user_role"].(float64))})
+			UserRole: role})
 		r = r.Clone(ctx)
 		next.ServeHTTP(w, r)
 	}
 }
 
+// withAccessTokenParameter returns a middleware that lets the clients that
+// cannot set the Authorization header, such as the EventSource and the
+// WebSocket of browsers, give their JSON Web Token in the "access_token" query
+// parameter instead (RFC 6750, section 2.3). The header wins when both are
+// given.
+func withAccessTokenParameter(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("access_token")
+		if "" != token && "" == strings.TrimSpace(r.Header.Get("Authorization")) {
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
 // withAdminPrivileges returns a middleware that checks if the user has admin
 // privileges.
 func withAdminPrivileges(next http.HandlerFunc) http.HandlerFunc {
@@ -132,11 +301,26 @@ func withHeader(key, value string) func(http.Handler) http.Handler {
 
 // withAllowedContentTypes returns a middleware that enforces a whitelist of allowed
 // request Content-Types. If the Content-Type is not in the whitelist, it
-// responds with a statusCode Unsupported Media Type (415).
-func withAllowedContentTypes(contentTypes ...string) func(http.Handler) http.Handler {
-	allowedContentTypes := make(map[string]struct{}, len(contentTypes))
-	for _, contentType := range contentTypes {
-		allowedContentTypes[strings.TrimSpace(strings.ToLower(contentType))] = struct{}{}
+// responds with a statusCode Unsupported Media Type (415). The routes of mux
+// found in overrides, under the same pattern they were registered with, use
+// their own whitelist instead.
+func withAllowedContentTypes(
+	mux *http.ServeMux,
+	overrides map[string][]string,
+	contentTypes ...string,
+) func(http.Handler) http.Handler {
+	whitelist := func(contentTypes []string) map[string]struct{} {
+		allowed := make(map[string]struct{}, len(contentTypes))
+		for _, contentType := range contentTypes {
+			allowed[strings.TrimSpace(strings.ToLower(contentType))] = struct{}{}
+		}
+		return allowed
+	}
+
+	allowedContentTypes := whitelist(contentTypes)
+	overridden := make(map[string]map[string]struct{}, len(overrides))
+	for pattern, contentTypes := range overrides {
+		overridden[pattern] = whitelist(contentTypes)
 	}
 
 	return func(next http.Handler) http.Handler {
@@ -148,10 +332,19 @@ func withAllowedContentTypes(contentTypes ...string) func(http.Handler) http.Han
 
 			requestContentType := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Type")))
 			if i := strings.Index(requestContentType, ";"); i > -1 {
-				requestContentType = requestContentType[:i]
+				requestContentType = strings.TrimSpace(requestContentType[:i])
+			}
+
+			allowed := allowedContentTypes
+			if nil != mux && 0 < len(overridden) {
+				if _, pattern := mux.Handler(r); "" != pattern {
+					if contentTypes, ok := overridden[pattern]; ok {
+						allowed = contentTypes
+					}
+				}
 			}
 
-			if _, ok := allowedContentTypes[requestContentType]; ok {
+			if _, ok := allowed[requestContentType]; ok {
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -183,6 +376,12 @@ func newResponseRecorder(w http.ResponseWriter) *responseRecorder {
 	return &responseRecorder{w, status, written}
 }
 
+// Unwrap returns the original http.ResponseWriter so that an
+// http.ResponseController can reach it.
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func (w *responseRecorder) statusCode() int {
 	if 0 == w.status {
 		return http.StatusOK
@@ -296,6 +495,10 @@ func withRequestLoggerTo(targets ...io.Writer) func(http.Handler) http.Handler {
 	}
 }
 
+// schedulerLockKey is the key of the PostgreSQL advisory lock that elects the
+// instance of the server that runs the background jobs.
+const schedulerLockKey int64 = 0x6e6f6461
+
 // registerMiddlewares applies a series of middleware functions to a ServeMux and
 // returns an http.Handler that chains the provided middleware functions to the
 // ServeMux parameter.
@@ -338,12 +541,23 @@ func main() {
 
 	mux := http.NewServeMux()
 
+	var (
+		auditRepository = repository.NewAuditRepository(db)
+		auditTrail      = audit.NewTrail(auditRepository)
+		auditService    = service.NewAuditService(auditRepository, global.AuditLogRetention())
+		auditHandler    = handler.NewAuditHandler(auditService)
+	)
+
+	mux.Handle("GET /audit", withAdminPrivileges(auditHandler.HandleAuditLogRetrieval))
+
 	var (
 		userRepository = repository.NewUserRepository(db)
-		userService    = service.NewUserService(userRepository)
+		userService    = service.NewAuditedUserService(service.NewUserService(userRepository), auditTrail)
 		userHandler    = handler.NewUserHandler(userService)
 	)
 
+	users = userService
+
 	mux.Handle("GET /me", withAuthorization(userHandler.HandleRetrievalOfLoggedInUser))
 	mux.Handle("PATCH /me", withAuthorization(userHandler.HandleUpdateForLoggedUser))
 	mux.Handle("DELETE /me", withAuthorization(userHandler.HandleRemovalOfLoggedUser))
@@ -358,20 +572,72 @@ func main() {
 	mux.Handle("PUT /users/{user_uuid}/block", withAdminPrivileges(userHandler.HandleBlockUser))
 	mux.Handle("DELETE /users/{user_uuid}/block", withAdminPrivileges(userHandler.HandleUnblockUser))
 	mux.Handle("GET /users/blocked", withAdminPrivileges(userHandler.HandleBlockedUsersRetrieval))
+	mux.Handle("GET /users/deleted", withAdminPrivileges(userHandler.HandleDeletedUsersRetrieval))
+	mux.Handle("POST /users/{user_uuid}/restore", withAdminPrivileges(userHandler.HandleUserRestoration))
 	mux.Handle("PUT /users/{user_uuid}/make_admin", withAdminPrivileges(userHandler.HandleAdminPromotion))
 	mux.Handle("DELETE /users/{user_uuid}/make_admin", withAdminPrivileges(userHandler.HandleDegradeAdminToUser))
 
 	var (
-		authenticationService = service.NewAuthenticationService(userService)
+		tokenRepository       = repository.NewTokenRepository(db)
+		sessionDenylist       = service.NewDenylist(tokenRepository)
+		authenticationService = service.NewAuthenticationService(userService, tokenRepository, sessionDenylist)
 		authenticationHandler = handler.NewAuthenticationHandler(authenticationService)
 	)
 
+	denylist = sessionDenylist
+	authenticator = authenticationService
+
 	mux.HandleFunc("POST /signup", authenticationHandler.HandleSignUp)
 	mux.HandleFunc("POST /login", authenticationHandler.HandleSignIn)
+	mux.HandleFunc("POST /token/refresh", authenticationHandler.HandleTokenRefresh)
+	mux.HandleFunc("POST /account/restore", authenticationHandler.HandleAccountRestoration)
+	mux.Handle("POST /me/logout", withAuthorization(authenticationHandler.HandleLogout))
+	mux.Handle("POST /me/change_password", withAuthorization(userHandler.HandlePasswordChangeForLoggedUser))
+
+	var mailer = newMailer()
+
+	var (
+		passwordResetRepository = repository.NewPasswordResetRepository(db)
+		passwordResetService    = service.NewPasswordResetService(userService, passwordResetRepository, tokenRepository, mailer, getEnv("PASSWORD_RESET_URL", ""))
+		passwordResetHandler    = handler.NewPasswordResetHandler(passwordResetService)
+	)
+
+	mux.HandleFunc("POST /password/forgot", passwordResetHandler.HandlePasswordResetRequest)
+	mux.HandleFunc("POST /password/reset", passwordResetHandler.HandlePasswordReset)
+
+	var (
+		memberRepository = repository.NewMemberRepository(db)
+		sharingService   = service.NewSharingService(memberRepository)
+		sharingHandler   = handler.NewSharingHandler(sharingService)
+	)
+
+	mux.Handle("GET /me/invitations", withAuthorization(sharingHandler.HandleInvitationsRetrieval))
+	mux.Handle("POST /me/invitations/{invitation_uuid}/accept", withAuthorization(sharingHandler.HandleInvitationAcceptance))
+	mux.Handle("POST /me/invitations/{invitation_uuid}/decline", withAuthorization(sharingHandler.HandleInvitationDecline))
+	mux.Handle("GET /me/shared/lists", withAuthorization(sharingHandler.HandleSharedListsRetrieval))
+	mux.Handle("GET /me/shared/groups", withAuthorization(sharingHandler.HandleSharedGroupsRetrieval))
+	mux.Handle("GET /me/lists/{list_uuid}/members", withAuthorization(sharingHandler.HandleListMembersRetrieval))
+	mux.Handle("POST /me/lists/{list_uuid}/members", withAuthorization(sharingHandler.HandleListInvitation))
+	mux.Handle("PUT /me/lists/{list_uuid}/members/{user_uuid}", withAuthorization(sharingHandler.HandleListMemberRoleUpdate))
+	mux.Handle("DELETE /me/lists/{list_uuid}/members/{user_uuid}", withAuthorization(sharingHandler.HandleListMemberRemoval))
+	mux.Handle("GET /me/groups/{group_uuid}/members", withAuthorization(sharingHandler.HandleGroupMembersRetrieval))
+	mux.Handle("POST /me/groups/{group_uuid}/members", withAuthorization(sharingHandler.HandleGroupInvitation))
+	mux.Handle("PUT /me/groups/{group_uuid}/members/{user_uuid}", withAuthorization(sharingHandler.HandleGroupMemberRoleUpdate))
+	mux.Handle("DELETE /me/groups/{group_uuid}/members/{user_uuid}", withAuthorization(sharingHandler.HandleGroupMemberRemoval))
+
+	var (
+		/* The last 1024 events are kept for the clients that come back, and a
+		   client that has 64 events to take is dropped.  */
+		eventBroker  = events.NewBroker(service.NewEventAudience(memberRepository), 1024, 64)
+		eventService = service.NewEventService(eventBroker, sessionDenylist, userService)
+		eventHandler = handler.NewEventHandler(eventService)
+	)
+
+	mux.Handle("GET /me/events", withAccessTokenParameter(withAuthorization(eventHandler.HandleEventStream)))
 
 	var (
 		groupRepository = repository.NewGroupRepository(db)
-		groupService    = service.NewGroupService(groupRepository)
+		groupService    = service.NewAuditedGroupService(service.NewObservableGroupService(service.NewGroupService(groupRepository, memberRepository), eventBroker, memberRepository), auditTrail)
 		groupHandler    = handler.NewGroupHandler(groupService)
 	)
 
@@ -383,7 +649,7 @@ func main() {
 
 	var (
 		listRepository = repository.NewListRepository(db)
-		listService    = service.NewListService(listRepository)
+		listService    = service.NewAuditedListService(service.NewObservableListService(service.NewListService(listRepository, memberRepository), eventBroker, memberRepository), auditTrail, memberRepository)
 		listHandler    = handler.NewListHandler(listService)
 	)
 
@@ -398,6 +664,325 @@ func main() {
 	mux.Handle("PATCH /me/groups/{group_uuid}/lists/{list_uuid}", withAuthorization(listHandler.HandlePartialUpdateOfGroupedList))
 	mux.Handle("DELETE /me/groups/{group_uuid}/lists/{list_uuid}", withAuthorization(listHandler.HandleGroupedListDeletion))
 
+	var (
+		taskRepository       = repository.NewTaskRepository(db)
+		revisionRepository   = repository.NewRevisionRepository(db)
+		dependencyRepository = repository.NewDependencyRepository(db)
+		revisedTaskService   = service.NewRevisedTaskService(service.NewTaskService(taskRepository, memberRepository), revisionRepository, memberRepository)
+		blockableTaskService = service.NewBlockableTaskService(revisedTaskService, dependencyRepository, memberRepository)
+		taskService          = service.NewAuditedTaskService(service.NewObservableTaskService(blockableTaskService, eventBroker, memberRepository), auditTrail)
+		taskHandler          = handler.NewTaskHandler(taskService)
+		revisionService      = service.NewAuditedRevisionService(service.NewObservableRevisionService(service.NewRevisionService(revisionRepository, memberRepository), eventBroker), taskService, auditTrail)
+		revisionHandler      = handler.NewRevisionHandler(revisionService)
+		dependencyService    = service.NewDependencyService(dependencyRepository)
+		dependencyHandler    = handler.NewDependencyHandler(dependencyService)
+	)
+
+	mux.Handle("GET /me/today", withAuthorization(taskHandler.HandleRetrievalOfTasksFromToday))
+	mux.Handle("GET /me/tomorrow", withAuthorization(taskHandler.HandleRetrievalOfTasksFromTomorrow))
+	mux.Handle("GET /me/deferred", withAuthorization(taskHandler.HandleRetrievalOfDeferredTasks))
+	mux.Handle("GET /me/tasks", withAuthorization(taskHandler.HandleRetrievalOfAllTasks))
+	mux.Handle("POST /me/tasks", withAuthorization(taskHandler.HandleCreateTaskForTodayList))
+	mux.Handle("GET /me/tasks/search", withAuthorization(taskHandler.HandleTaskSearch))
+	mux.Handle("GET /me/tasks/completed", withAuthorization(taskHandler.HandleRetrievalOfCompletedTasks))
+	mux.Handle("GET /me/tasks/archived", withAuthorization(taskHandler.HandleRetrievalOfArchivedTasks))
+	mux.Handle("GET /me/tasks/trashed", withAuthorization(taskHandler.HandleRetrievalOfTrashedTasks))
+	mux.Handle("GET /me/tasks/{task_uuid}", withAuthorization(taskHandler.WithListOfTask(taskHandler.HandleTaskRetrievalByID)))
+	mux.Handle("PATCH /me/tasks/{task_uuid}", withAuthorization(taskHandler.WithListOfTask(taskHandler.HandlePartialUpdateOfTask)))
+	mux.Handle("DELETE /me/tasks/{task_uuid}", withAuthorization(taskHandler.WithListOfTask(taskHandler.HandleTaskDeletion)))
+	mux.Handle("PUT /me/tasks/{task_uuid}/trash", withAuthorization(taskHandler.WithListOfTask(taskHandler.HandleTaskTrashing)))
+	mux.Handle("DELETE /me/tasks/{task_uuid}/trash", withAuthorization(taskHandler.WithListOfTask(taskHandler.HandleTaskRestorationFromTrash)))
+	mux.Handle("PUT /me/tasks/{task_uuid}/reorder", withAuthorization(taskHandler.WithListOfTask(taskHandler.HandleTaskReordering)))
+	mux.Handle("POST /me/tasks/{task_uuid}/duplicate", withAuthorization(taskHandler.HandleTaskDuplication))
+	mux.Handle("PUT /me/tasks/{task_uuid}/move/{list_uuid}", withAuthorization(taskHandler.HandleTaskMovement))
+	mux.Handle("PUT /me/tasks/{task_uuid}/today", withAuthorization(taskHandler.HandleMoveTaskToToday))
+	mux.Handle("PUT /me/tasks/{task_uuid}/tomorrow", withAuthorization(taskHandler.HandleMoveTaskToTomorrow))
+	mux.Handle("PUT /me/tasks/{task_uuid}/defer", withAuthorization(taskHandler.HandleTaskDeferral))
+	mux.Handle("GET /me/tasks/{task_uuid}/dependencies", withAuthorization(dependencyHandler.HandleDependencyGraphRetrieval))
+	mux.Handle("PUT /me/tasks/{task_uuid}/dependencies/{blocker_uuid}", withAuthorization(dependencyHandler.HandleDependencyCreation))
+	mux.Handle("DELETE /me/tasks/{task_uuid}/dependencies/{blocker_uuid}", withAuthorization(dependencyHandler.HandleDependencyRemoval))
+	mux.Handle("GET /me/lists/{list_uuid}/tasks", withAuthorization(taskHandler.HandleTasksRetrieval))
+	mux.Handle("POST /me/lists/{list_uuid}/tasks", withAuthorization(taskHandler.HandleCreateTask))
+	mux.Handle("GET /me/groups/{group_uuid}/lists/{list_uuid}/tasks", withAuthorization(taskHandler.HandleTasksRetrieval))
+	mux.Handle("POST /me/groups/{group_uuid}/lists/{list_uuid}/tasks", withAuthorization(taskHandler.HandleCreateTask))
+	mux.Handle("GET /me/lists/{list_uuid}/tasks/{task_uuid}", withAuthorization(taskHandler.HandleTaskRetrievalByID))
+	mux.Handle("PATCH /me/lists/{list_uuid}/tasks/{task_uuid}", withAuthorization(taskHandler.HandlePartialUpdateOfTask))
+	mux.Handle("DELETE /me/lists/{list_uuid}/tasks/{task_uuid}", withAuthorization(taskHandler.HandleTaskDeletion))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/reorder", withAuthorization(taskHandler.HandleTaskReordering))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/reminder", withAuthorization(taskHandler.HandleSetTaskReminder))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/priority", withAuthorization(taskHandler.HandleSetTaskPriority))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/due_date", withAuthorization(taskHandler.HandleSetTaskDueDate))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/recurrence", withAuthorization(taskHandler.HandleSetTaskRecurrence))
+	mux.Handle("DELETE /me/lists/{list_uuid}/tasks/{task_uuid}/recurrence", withAuthorization(taskHandler.HandleTaskRecurrenceRemoval))
+	mux.Handle("GET /me/lists/{list_uuid}/tasks/{task_uuid}/occurrences", withAuthorization(taskHandler.HandleRetrievalOfTaskOccurrences))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/complete", withAuthorization(taskHandler.HandleTaskCompletion))
+	mux.Handle("DELETE /me/lists/{list_uuid}/tasks/{task_uuid}/complete", withAuthorization(taskHandler.HandleTaskResumption))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/pin", withAuthorization(taskHandler.HandleTaskPinning))
+	mux.Handle("DELETE /me/lists/{list_uuid}/tasks/{task_uuid}/pin", withAuthorization(taskHandler.HandleTaskUnpinning))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/trash", withAuthorization(taskHandler.HandleTaskTrashing))
+	mux.Handle("DELETE /me/lists/{list_uuid}/tasks/{task_uuid}/trash", withAuthorization(taskHandler.HandleTaskRestorationFromTrash))
+	mux.Handle("GET /me/lists/{list_uuid}/tasks/{task_uuid}/revisions", withAuthorization(revisionHandler.HandleTaskRevisionsRetrieval))
+	mux.Handle("POST /me/lists/{list_uuid}/tasks/{task_uuid}/revisions/{revision_uuid}/restore", withAuthorization(revisionHandler.HandleRestorationToRevision))
+	mux.Handle("POST /me/undo", withAuthorization(revisionHandler.HandleUndo))
+
+	var (
+		stepRepository = repository.NewStepRepository(db)
+		stepService    = service.NewStepService(stepRepository)
+		stepHandler    = handler.NewStepHandler(stepService)
+	)
+
+	mux.Handle("GET /me/tasks/{task_uuid}/steps", withAuthorization(stepHandler.HandleStepsRetrieval))
+	mux.Handle("POST /me/tasks/{task_uuid}/steps", withAuthorization(stepHandler.HandleStepCreation))
+	mux.Handle("PATCH /me/tasks/{task_uuid}/steps/{step_uuid}", withAuthorization(stepHandler.HandlePartialUpdateOfStep))
+	mux.Handle("DELETE /me/tasks/{task_uuid}/steps/{step_uuid}", withAuthorization(stepHandler.HandleStepDeletion))
+	mux.Handle("PUT /me/tasks/{task_uuid}/steps/{step_uuid}/accomplish", withAuthorization(stepHandler.HandleStepAccomplishment))
+	mux.Handle("DELETE /me/tasks/{task_uuid}/steps/{step_uuid}/accomplish", withAuthorization(stepHandler.HandleStepUnaccomplishment))
+	mux.Handle("POST /me/tasks/{task_uuid}/steps/{step_uuid}/reorder", withAuthorization(stepHandler.HandleStepReordering))
+
+	var (
+		subtaskRepository = repository.NewSubtaskRepository(db)
+		subtaskService    = service.NewSubtaskService(subtaskRepository, memberRepository, global.SubtaskMaxDepth())
+		subtaskHandler    = handler.NewSubtaskHandler(subtaskService)
+	)
+
+	mux.Handle("GET /me/lists/{list_uuid}/tasks/{task_uuid}/subtasks", withAuthorization(subtaskHandler.HandleSubtaskTreeRetrieval))
+	mux.Handle("POST /me/lists/{list_uuid}/tasks/{task_uuid}/subtasks", withAuthorization(subtaskHandler.HandleSubtaskCreation))
+	mux.Handle("POST /me/lists/{list_uuid}/tasks/{task_uuid}/steps/{step_uuid}/promote", withAuthorization(subtaskHandler.HandleStepPromotion))
+
+	var (
+		tagRepository = repository.NewTagRepository(db)
+		tagService    = service.NewTagService(tagRepository)
+		tagHandler    = handler.NewTagHandler(tagService)
+	)
+
+	mux.Handle("GET /me/tags", withAuthorization(tagHandler.HandleTagsRetrieval))
+	mux.Handle("POST /me/tags", withAuthorization(tagHandler.HandleTagCreation))
+	mux.Handle("GET /me/tags/{tag_uuid}", withAuthorization(tagHandler.HandleTagRetrievalByID))
+	mux.Handle("PATCH /me/tags/{tag_uuid}", withAuthorization(tagHandler.HandlePartialUpdateOfTag))
+	mux.Handle("DELETE /me/tags/{tag_uuid}", withAuthorization(tagHandler.HandleTagDeletion))
+	mux.Handle("GET /me/tasks/{task_uuid}/tags", withAuthorization(tagHandler.HandleRetrievalOfTaskTags))
+	mux.Handle("PUT /me/tasks/{task_uuid}/tags/{tag_uuid}", withAuthorization(tagHandler.HandleTagAttachment))
+	mux.Handle("DELETE /me/tasks/{task_uuid}/tags/{tag_uuid}", withAuthorization(tagHandler.HandleTagDetachment))
+
+	var (
+		smartListRepository = repository.NewSmartListRepository(db)
+		smartListService    = service.NewSmartListService(smartListRepository, userService)
+		smartListHandler    = handler.NewSmartListHandler(smartListService)
+	)
+
+	mux.Handle("GET /me/smart_lists", withAuthorization(smartListHandler.HandleSmartListsRetrieval))
+	mux.Handle("POST /me/smart_lists", withAuthorization(smartListHandler.HandleSmartListCreation))
+	mux.Handle("GET /me/smart_lists/{smart_list_uuid}", withAuthorization(smartListHandler.HandleSmartListRetrievalByID))
+	mux.Handle("PATCH /me/smart_lists/{smart_list_uuid}", withAuthorization(smartListHandler.HandlePartialUpdateOfSmartList))
+	mux.Handle("DELETE /me/smart_lists/{smart_list_uuid}", withAuthorization(smartListHandler.HandleSmartListDeletion))
+	mux.Handle("GET /me/smart_lists/{smart_list_uuid}/tasks", withAuthorization(smartListHandler.HandleRetrievalOfSmartListTasks))
+
+	var (
+		searchRepository = repository.NewSearchRepository(db)
+		searchService    = service.NewSearchService(searchRepository)
+		searchHandler    = handler.NewSearchHandler(searchService)
+	)
+
+	mux.Handle("GET /me/search", withAuthorization(searchHandler.HandleSearch))
+
+	var (
+		calendarRepository = repository.NewCalendarRepository(db)
+		calendarService    = service.NewCalendarService(calendarRepository, memberRepository, userRepository)
+		calendarHandler    = handler.NewCalendarHandler(calendarService)
+	)
+
+	mux.Handle("POST /me/calendar/token", withAuthorization(calendarHandler.HandleFeedTokenIssue))
+	mux.Handle("DELETE /me/calendar/token", withAuthorization(calendarHandler.HandleFeedTokenRevocation))
+	/* Calendars cannot sign in, so the feeds are reached with their own token.  */
+	mux.HandleFunc("GET /me/calendar.ics", calendarHandler.HandleUserFeed)
+	mux.HandleFunc("GET /me/lists/{list_uuid}/calendar.ics", calendarHandler.HandleListFeed)
+
+	var (
+		calDAVRepository = repository.NewCalDAVRepository(db)
+		calDAVService    = service.NewCalDAVService(calDAVRepository, listService, taskService)
+		calDAVHandler    = handler.NewCalDAVHandler(calDAVService)
+	)
+
+	/* CalDAV clients sign in with HTTP Basic authorization.  */
+	mux.HandleFunc("/.well-known/caldav", calDAVHandler.HandleWellKnown)
+	mux.HandleFunc("OPTIONS /dav/", calDAVHandler.HandleOptions)
+	mux.Handle("PROPFIND /dav/{$}", withBasicAuthorization(calDAVHandler.HandlePrincipalPropfind))
+	mux.Handle("PROPFIND /dav/calendars/{$}", withBasicAuthorization(calDAVHandler.HandleHomePropfind))
+	mux.Handle("PROPFIND /dav/calendars/{list_uuid}/{$}", withBasicAuthorization(calDAVHandler.HandleCalendarPropfind))
+	mux.Handle("REPORT /dav/calendars/{list_uuid}/{$}", withBasicAuthorization(calDAVHandler.HandleReport))
+	mux.Handle("PROPFIND /dav/calendars/{list_uuid}/{object}", withBasicAuthorization(calDAVHandler.HandleObjectPropfind))
+	mux.Handle("GET /dav/calendars/{list_uuid}/{object}", withBasicAuthorization(calDAVHandler.HandleObjectRetrieval))
+	mux.Handle("PUT /dav/calendars/{list_uuid}/{object}", withBasicAuthorization(calDAVHandler.HandleObjectPut))
+	mux.Handle("DELETE /dav/calendars/{list_uuid}/{object}", withBasicAuthorization(calDAVHandler.HandleObjectDeletion))
+
+	var (
+		importRepository = repository.NewImportRepository(db)
+		importService    = service.NewImportService(importRepository, groupService, listService, taskService, stepService, tagService)
+		importHandler    = handler.NewImportHandler(importService)
+	)
+
+	mux.Handle("POST /me/imports", withAuthorization(importHandler.HandleImport))
+
+	var (
+		blobStore            = newBlobStore()
+		attachmentRepository = repository.NewAttachmentRepository(db)
+		attachmentService    = service.NewAttachmentService(attachmentRepository, blobStore)
+		attachmentHandler    = handler.NewAttachmentHandler(attachmentService)
+	)
+
+	mux.Handle("GET /me/tasks/{task_uuid}/attachments", withAuthorization(attachmentHandler.HandleAttachmentsRetrieval))
+	mux.Handle("POST /me/tasks/{task_uuid}/attachments", withAuthorization(attachmentHandler.HandleAttachmentUpload))
+	mux.Handle("GET /me/tasks/{task_uuid}/attachments/{attachment_uuid}", withAuthorization(attachmentHandler.HandleAttachmentRetrievalByID))
+	mux.Handle("GET /me/tasks/{task_uuid}/attachments/{attachment_uuid}/content", withAuthorization(attachmentHandler.HandleAttachmentDownload))
+	mux.Handle("DELETE /me/tasks/{task_uuid}/attachments/{attachment_uuid}", withAuthorization(attachmentHandler.HandleAttachmentDeletion))
+
+	var (
+		notificationRepository = repository.NewNotificationRepository(db)
+		notificationService    = service.NewNotificationService(notificationRepository)
+		notificationHandler    = handler.NewNotificationHandler(notificationService)
+		reminderService        = service.NewReminderService(
+			repository.NewReminderRepository(db),
+			notify.NewInboxNotifier(notificationRepository),
+			notify.NewEmailNotifier(mailer),
+			notify.NewWebhookNotifier(nil, getEnv("WEBHOOK_SECRET", "")),
+		)
+		reminderHandler = handler.NewReminderHandler(reminderService)
+	)
+
+	mux.Handle("GET /me/notifications", withAuthorization(notificationHandler.HandleNotificationsRetrieval))
+	mux.Handle("DELETE /me/notifications/{notification_uuid}", withAuthorization(notificationHandler.HandleNotificationDismissal))
+	mux.Handle("PUT /me/notifications/{notification_uuid}/snooze", withAuthorization(notificationHandler.HandleNotificationSnooze))
+	mux.Handle("GET /me/tasks/{task_uuid}/reminder/deliveries", withAuthorization(reminderHandler.HandleRetrievalOfReminderDeliveries))
+
+	var (
+		assignmentRepository = repository.NewAssignmentRepository(db)
+		assignmentService    = service.NewAssignmentService(assignmentRepository, taskRepository, memberRepository, notificationRepository)
+		assignmentHandler    = handler.NewAssignmentHandler(assignmentService)
+	)
+
+	mux.Handle("GET /me/assigned", withAuthorization(assignmentHandler.HandleRetrievalOfAssignedTasks))
+	mux.Handle("GET /me/lists/{list_uuid}/tasks/{task_uuid}/assignees", withAuthorization(assignmentHandler.HandleAssigneesRetrieval))
+	mux.Handle("PUT /me/lists/{list_uuid}/tasks/{task_uuid}/assignees/{user_uuid}", withAuthorization(assignmentHandler.HandleTaskAssignment))
+	mux.Handle("DELETE /me/lists/{list_uuid}/tasks/{task_uuid}/assignees/{user_uuid}", withAuthorization(assignmentHandler.HandleTaskUnassignment))
+
+	var (
+		commentRepository = repository.NewCommentRepository(db)
+		commentService    = service.NewCommentService(commentRepository, taskRepository, userRepository, memberRepository, notificationRepository)
+		commentHandler    = handler.NewCommentHandler(commentService)
+	)
+
+	mux.Handle("GET /me/lists/{list_uuid}/tasks/{task_uuid}/comments", withAuthorization(commentHandler.HandleCommentsRetrieval))
+	mux.Handle("POST /me/lists/{list_uuid}/tasks/{task_uuid}/comments", withAuthorization(commentHandler.HandleCommentCreation))
+	mux.Handle("PATCH /me/lists/{list_uuid}/tasks/{task_uuid}/comments/{comment_uuid}", withAuthorization(commentHandler.HandlePartialUpdateOfComment))
+	mux.Handle("DELETE /me/lists/{list_uuid}/tasks/{task_uuid}/comments/{comment_uuid}", withAuthorization(commentHandler.HandleCommentDeletion))
+	mux.Handle("GET /me/lists/{list_uuid}/tasks/{task_uuid}/activity", withAuthorization(commentHandler.HandleTaskActivityRetrieval))
+
+	var (
+		exportRepository = repository.NewExportRepository(db)
+		exportService    = service.NewExportService(
+			exportRepository,
+			blobStore,
+			mailer,
+			global.ExportRetention(),
+			userService,
+			groupService,
+			listService,
+			taskService,
+			stepService,
+			tagService,
+			attachmentService,
+		)
+		exportHandler = handler.NewExportHandler(exportService)
+	)
+
+	mux.Handle("POST /me/export", withAuthorization(exportHandler.HandleExportRequest))
+	mux.Handle("GET /me/export", withAuthorization(exportHandler.HandleExportRetrieval))
+	mux.Handle("GET /me/export/archive", withAuthorization(exportHandler.HandleArchiveDownload))
+
+	var (
+		jobRunRepository = repository.NewJobRunRepository(db)
+		jobRunService    = service.NewJobRunService(jobRunRepository)
+		jobRunHandler    = handler.NewJobRunHandler(jobRunService)
+		rolloverService  = service.NewRolloverService(taskRepository, auditTrail)
+		jobs             = scheduler.New(scheduler.NewAdvisoryLock(db, schedulerLockKey), jobRunRepository)
+	)
+
+	mux.Handle("GET /jobs/runs", withAdminPrivileges(jobRunHandler.HandleJobRunsRetrieval))
+
+	err = jobs.Register("rollover", "*/15 * * * *", func() error {
+		rolled, err := rolloverService.RollOver(time.Now())
+		if 0 < rolled {
+			log.Printf("rolled over the lists of %d user(s)", rolled)
+		}
+		return err
+	})
+	if nil != err {
+		log.Fatalf("could not register job: %v", err)
+	}
+	err = jobs.Register("reminders", "* * * * *", func() error {
+		delivered, err := reminderService.Dispatch(time.Now())
+		if 0 < delivered {
+			log.Printf("delivered %d reminder(s)", delivered)
+		}
+		return err
+	})
+	if nil != err {
+		log.Fatalf("could not register job: %v", err)
+	}
+	err = jobs.Register("purge-deleted-users", "0 * * * *", func() error {
+		purged, err := userService.PurgeDeleted()
+		if 0 < purged {
+			log.Printf("purged %d deleted user(s)", purged)
+		}
+		return err
+	})
+	if nil != err {
+		log.Fatalf("could not register job: %v", err)
+	}
+	err = jobs.Register("purge-session-denials", "15 * * * *", func() error {
+		purged, err := tokenRepository.PurgeSessionDenials()
+		if 0 < purged {
+			log.Printf("purged %d expired session denial(s)", purged)
+		}
+		return err
+	})
+	if nil != err {
+		log.Fatalf("could not register job: %v", err)
+	}
+	err = jobs.Register("purge-audit-log", "30 3 * * *", func() error {
+		purged, err := auditService.Purge(time.Now())
+		if 0 < purged {
+			log.Printf("purged %d audit log entries", purged)
+		}
+		return err
+	})
+	if nil != err {
+		log.Fatalf("could not register job: %v", err)
+	}
+	err = jobs.Register("exports", "* * * * *", func() error {
+		built, err := exportService.Build(time.Now())
+		if 0 < built {
+			log.Printf("built %d export(s)", built)
+		}
+		return err
+	})
+	if nil != err {
+		log.Fatalf("could not register job: %v", err)
+	}
+	err = jobs.Register("purge-exports", "15 * * * *", func() error {
+		purged, err := exportService.Purge(time.Now())
+		if 0 < purged {
+			log.Printf("purged %d export(s)", purged)
+		}
+		return err
+	})
+	if nil != err {
+		log.Fatalf("could not register job: %v", err)
+	}
+
+	go jobs.Run(context.Background())
+	go eventBroker.Run(context.Background())
+
 	serverLogFile, err := os.OpenFile("server.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if nil != err {
 		log.Fatalf("could not create/open file: %v", err)
@@ -411,10 +996,19 @@ func main() {
 		withRequestLoggerTo(os.Stdout, serverLogFile),
 		withHeader("Access-Control-Allow-Credentials", "true"),
 		withHeader("Access-Control-Allow-Headers", "*"),
-		withHeader("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS"),
+		withHeader("Access-Control-Allow-Methods", "GET, POST, PATCH, PUT, DELETE, OPTIONS, PROPFIND, REPORT"),
 		withHeader("Access-Control-Allow-Origin", "*"),
 		withHeader("Content-Type", "application/json"),
-		withAllowedContentTypes("application/json"),
+		withAllowedContentTypes(mux, map[string][]string{
+			"POST /me/tasks/{task_uuid}/attachments":       {"multipart/form-data"},
+			"PROPFIND /dav/{$}":                            {"application/xml", "text/xml"},
+			"PROPFIND /dav/calendars/{$}":                  {"application/xml", "text/xml"},
+			"PROPFIND /dav/calendars/{list_uuid}/{$}":      {"application/xml", "text/xml"},
+			"REPORT /dav/calendars/{list_uuid}/{$}":        {"application/xml", "text/xml"},
+			"PROPFIND /dav/calendars/{list_uuid}/{object}": {"application/xml", "text/xml"},
+			"PUT /dav/calendars/{list_uuid}/{object}":      {"text/calendar"},
+			"POST /me/imports":                             {"text/calendar", "text/csv", "application/json", "text/plain"},
+		}, "application/json"),
 	)
 
 	listener, err := net.Listen("tcp", ":"+serverPort)
@@ -0,0 +1,80 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+)
+
+type AssignmentRepository struct {
+	mock.Mock
+}
+
+func NewAssignmentRepositoryMock() *AssignmentRepository {
+	return new(AssignmentRepository)
+}
+
+func (o *AssignmentRepository) Assign(ownerID, listID, taskID, assigneeID, assignerID string) (ok bool, err error) {
+	var args = o.Called(ownerID, listID, taskID, assigneeID, assignerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *AssignmentRepository) Unassign(ownerID, listID, taskID, assigneeID string) (ok bool, err error) {
+	var args = o.Called(ownerID, listID, taskID, assigneeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *AssignmentRepository) Fetch(ownerID, listID, taskID string) (assignees []*model.Assignee, err error) {
+	var args = o.Called(ownerID, listID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		assignees = arg0.([]*model.Assignee)
+	}
+	return assignees, args.Error(1)
+}
+
+func (o *AssignmentRepository) FetchAssigned(userID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var args = o.Called(userID, page, rpp, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tasks = arg0.([]*model.Task)
+	}
+	return tasks, args.Error(1)
+}
+
+type AssignmentServiceMock struct {
+	mock.Mock
+}
+
+func NewAssignmentServiceMock() *AssignmentServiceMock {
+	return new(AssignmentServiceMock)
+}
+
+func (o *AssignmentServiceMock) Assign(userID, listID, taskID, assigneeID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(userID, listID, taskID, assigneeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *AssignmentServiceMock) Unassign(userID, listID, taskID, assigneeID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(userID, listID, taskID, assigneeID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *AssignmentServiceMock) Fetch(userID, listID, taskID uuid.UUID) (assignees []*model.Assignee, err error) {
+	var args = o.Called(userID, listID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		assignees = arg0.([]*model.Assignee)
+	}
+	return assignees, args.Error(1)
+}
+
+func (o *AssignmentServiceMock) FetchAssigned(userID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+	var args = o.Called(userID, pagination, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Task])
+	}
+	return result, args.Error(1)
+}
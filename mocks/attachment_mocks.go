@@ -0,0 +1,97 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"noda/data/model"
+	"noda/data/transfer"
+)
+
+type AttachmentRepository struct {
+	mock.Mock
+}
+
+func NewAttachmentRepositoryMock() *AttachmentRepository {
+	return new(AttachmentRepository)
+}
+
+func (o *AttachmentRepository) Save(ownerID, taskID string, creation *transfer.AttachmentCreation) (insertedID string, err error) {
+	var args = o.Called(ownerID, taskID, creation)
+	return args.String(0), args.Error(1)
+}
+
+func (o *AttachmentRepository) FetchByID(ownerID, taskID, attachmentID string) (attachment *model.Attachment, err error) {
+	var args = o.Called(ownerID, taskID, attachmentID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		attachment = arg0.(*model.Attachment)
+	}
+	return attachment, args.Error(1)
+}
+
+func (o *AttachmentRepository) Fetch(ownerID, taskID string) (attachments []*model.Attachment, err error) {
+	var args = o.Called(ownerID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		attachments = arg0.([]*model.Attachment)
+	}
+	return attachments, args.Error(1)
+}
+
+func (o *AttachmentRepository) Delete(ownerID, taskID, attachmentID string) error {
+	var args = o.Called(ownerID, taskID, attachmentID)
+	return args.Error(0)
+}
+
+type AttachmentServiceMock struct {
+	mock.Mock
+}
+
+func NewAttachmentServiceMock() *AttachmentServiceMock {
+	return new(AttachmentServiceMock)
+}
+
+func (o *AttachmentServiceMock) Save(ownerID, taskID uuid.UUID, fileName string, content io.Reader) (insertedID uuid.UUID, err error) {
+	var args = o.Called(ownerID, taskID, fileName, content)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		insertedID = arg0.(uuid.UUID)
+	}
+	return insertedID, args.Error(1)
+}
+
+func (o *AttachmentServiceMock) FetchByID(ownerID, taskID, attachmentID uuid.UUID) (attachment *model.Attachment, err error) {
+	var args = o.Called(ownerID, taskID, attachmentID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		attachment = arg0.(*model.Attachment)
+	}
+	return attachment, args.Error(1)
+}
+
+func (o *AttachmentServiceMock) Fetch(ownerID, taskID uuid.UUID) (attachments []*model.Attachment, err error) {
+	var args = o.Called(ownerID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		attachments = arg0.([]*model.Attachment)
+	}
+	return attachments, args.Error(1)
+}
+
+func (o *AttachmentServiceMock) Open(ownerID, taskID, attachmentID uuid.UUID) (attachment *model.Attachment, content io.ReadCloser, err error) {
+	var args = o.Called(ownerID, taskID, attachmentID)
+	var arg0, arg1 = args.Get(0), args.Get(1)
+	if nil != arg0 {
+		attachment = arg0.(*model.Attachment)
+	}
+	if nil != arg1 {
+		content = arg1.(io.ReadCloser)
+	}
+	return attachment, content, args.Error(2)
+}
+
+func (o *AttachmentServiceMock) Delete(ownerID, taskID, attachmentID uuid.UUID) error {
+	var args = o.Called(ownerID, taskID, attachmentID)
+	return args.Error(0)
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+	"time"
+)
+
+type AuditRepository struct {
+	mock.Mock
+}
+
+func NewAuditRepositoryMock() *AuditRepository {
+	return new(AuditRepository)
+}
+
+func (o *AuditRepository) Save(entry *model.AuditEntry) (insertedID string, err error) {
+	var args = o.Called(entry)
+	return args.String(0), args.Error(1)
+}
+
+func (o *AuditRepository) Fetch(filter *types.AuditFilter, page, rpp int64) (entries []*model.AuditEntry, err error) {
+	var args = o.Called(filter, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		entries = arg0.([]*model.AuditEntry)
+	}
+	return entries, args.Error(1)
+}
+
+func (o *AuditRepository) Purge(before time.Time) (purged int64, err error) {
+	var args = o.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type AuditServiceMock struct {
+	mock.Mock
+}
+
+func NewAuditServiceMock() *AuditServiceMock {
+	return new(AuditServiceMock)
+}
+
+func (o *AuditServiceMock) Fetch(pagination *types.Pagination, filter *types.AuditFilter) (result *types.Result[model.AuditEntry], err error) {
+	var args = o.Called(pagination, filter)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.AuditEntry])
+	}
+	return result, args.Error(1)
+}
+
+func (o *AuditServiceMock) Purge(now time.Time) (purged int64, err error) {
+	var args = o.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -28,3 +28,27 @@ func (m *AuthenticationServiceMock) SignIn(credentials *transfer.UserCredentials
 	}
 	return payload, args.Error(1)
 }
+
+func (m *AuthenticationServiceMock) Authenticate(credentials *transfer.UserCredentials) (userID uuid.UUID, role types.Role, err error) {
+	var args = m.Called(credentials)
+	return args.Get(0).(uuid.UUID), args.Get(1).(types.Role), args.Error(2)
+}
+
+func (m *AuthenticationServiceMock) Refresh(refreshToken string) (payload *types.TokenPayload, err error) {
+	var args = m.Called(refreshToken)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		payload = arg0.(*types.TokenPayload)
+	}
+	return payload, args.Error(1)
+}
+
+func (m *AuthenticationServiceMock) Logout(userID, sessionID uuid.UUID) error {
+	var args = m.Called(userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *AuthenticationServiceMock) RestoreAccount(credentials *transfer.UserCredentials) error {
+	var args = m.Called(credentials)
+	return args.Error(0)
+}
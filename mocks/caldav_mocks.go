@@ -0,0 +1,120 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"time"
+)
+
+type CalDAVRepository struct {
+	mock.Mock
+}
+
+func NewCalDAVRepositoryMock() *CalDAVRepository {
+	return new(CalDAVRepository)
+}
+
+func (o *CalDAVRepository) SaveHref(ownerID, listID, taskID, name, uid string) error {
+	var args = o.Called(ownerID, listID, taskID, name, uid)
+	return args.Error(0)
+}
+
+func (o *CalDAVRepository) FetchHrefs(ownerID, listID string) (hrefs []*model.CalendarHref, err error) {
+	var args = o.Called(ownerID, listID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		hrefs = arg0.([]*model.CalendarHref)
+	}
+	return hrefs, args.Error(1)
+}
+
+func (o *CalDAVRepository) FetchHrefByName(ownerID, listID, name string) (href *model.CalendarHref, err error) {
+	var args = o.Called(ownerID, listID, name)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		href = arg0.(*model.CalendarHref)
+	}
+	return href, args.Error(1)
+}
+
+func (o *CalDAVRepository) FetchHrefByUID(ownerID, listID, uid string) (href *model.CalendarHref, err error) {
+	var args = o.Called(ownerID, listID, uid)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		href = arg0.(*model.CalendarHref)
+	}
+	return href, args.Error(1)
+}
+
+func (o *CalDAVRepository) FetchHrefOfTask(ownerID, listID, taskID string) (href *model.CalendarHref, err error) {
+	var args = o.Called(ownerID, listID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		href = arg0.(*model.CalendarHref)
+	}
+	return href, args.Error(1)
+}
+
+func (o *CalDAVRepository) FetchLastChange(ownerID, listID string) (tasks int64, updatedAt *time.Time, err error) {
+	var args = o.Called(ownerID, listID)
+	var arg1 = args.Get(1)
+	if nil != arg1 {
+		updatedAt = arg1.(*time.Time)
+	}
+	return args.Get(0).(int64), updatedAt, args.Error(2)
+}
+
+type CalDAVServiceMock struct {
+	mock.Mock
+}
+
+func NewCalDAVServiceMock() *CalDAVServiceMock {
+	return new(CalDAVServiceMock)
+}
+
+func (o *CalDAVServiceMock) FetchCalendars(userID uuid.UUID) (calendars []*model.Calendar, err error) {
+	var args = o.Called(userID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		calendars = arg0.([]*model.Calendar)
+	}
+	return calendars, args.Error(1)
+}
+
+func (o *CalDAVServiceMock) FetchCalendar(userID, listID uuid.UUID) (calendar *model.Calendar, err error) {
+	var args = o.Called(userID, listID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		calendar = arg0.(*model.Calendar)
+	}
+	return calendar, args.Error(1)
+}
+
+func (o *CalDAVServiceMock) FetchObjects(userID, listID uuid.UUID) (objects []*model.CalendarObject, err error) {
+	var args = o.Called(userID, listID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		objects = arg0.([]*model.CalendarObject)
+	}
+	return objects, args.Error(1)
+}
+
+func (o *CalDAVServiceMock) FetchObject(userID, listID uuid.UUID, name string) (object *model.CalendarObject, err error) {
+	var args = o.Called(userID, listID, name)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		object = arg0.(*model.CalendarObject)
+	}
+	return object, args.Error(1)
+}
+
+func (o *CalDAVServiceMock) PutObject(userID, listID uuid.UUID, name, ifMatch, ifNoneMatch string, data []byte) (created bool, err error) {
+	var args = o.Called(userID, listID, name, ifMatch, ifNoneMatch, data)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *CalDAVServiceMock) DeleteObject(userID, listID uuid.UUID, name, ifMatch string) error {
+	var args = o.Called(userID, listID, name, ifMatch)
+	return args.Error(0)
+}
@@ -0,0 +1,71 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+)
+
+type CalendarRepository struct {
+	mock.Mock
+}
+
+func NewCalendarRepositoryMock() *CalendarRepository {
+	return new(CalendarRepository)
+}
+
+func (o *CalendarRepository) SaveFeedToken(userID, hash string) error {
+	var args = o.Called(userID, hash)
+	return args.Error(0)
+}
+
+func (o *CalendarRepository) FetchFeedOwner(hash string) (userID string, err error) {
+	var args = o.Called(hash)
+	return args.String(0), args.Error(1)
+}
+
+func (o *CalendarRepository) DeleteFeedToken(userID string) error {
+	var args = o.Called(userID)
+	return args.Error(0)
+}
+
+func (o *CalendarRepository) FetchListName(userID, listID string) (name string, err error) {
+	var args = o.Called(userID, listID)
+	return args.String(0), args.Error(1)
+}
+
+func (o *CalendarRepository) FetchTasks(userID, listID string) (tasks []*model.Task, err error) {
+	var args = o.Called(userID, listID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tasks = arg0.([]*model.Task)
+	}
+	return tasks, args.Error(1)
+}
+
+type CalendarServiceMock struct {
+	mock.Mock
+}
+
+func NewCalendarServiceMock() *CalendarServiceMock {
+	return new(CalendarServiceMock)
+}
+
+func (o *CalendarServiceMock) IssueFeedToken(userID uuid.UUID) (token string, err error) {
+	var args = o.Called(userID)
+	return args.String(0), args.Error(1)
+}
+
+func (o *CalendarServiceMock) RevokeFeedToken(userID uuid.UUID) error {
+	var args = o.Called(userID)
+	return args.Error(0)
+}
+
+func (o *CalendarServiceMock) RenderFeed(token string, listID uuid.UUID) (feed []byte, err error) {
+	var args = o.Called(token, listID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		feed = arg0.([]byte)
+	}
+	return feed, args.Error(1)
+}
@@ -0,0 +1,100 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+)
+
+type CommentRepository struct {
+	mock.Mock
+}
+
+func NewCommentRepositoryMock() *CommentRepository {
+	return new(CommentRepository)
+}
+
+func (o *CommentRepository) Save(ownerID, listID, taskID, authorID, parentID, content string, mentions []string) (insertedID string, err error) {
+	var args = o.Called(ownerID, listID, taskID, authorID, parentID, content, mentions)
+	return args.String(0), args.Error(1)
+}
+
+func (o *CommentRepository) FetchByID(ownerID, listID, taskID, commentID string) (comment *model.Comment, err error) {
+	var args = o.Called(ownerID, listID, taskID, commentID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		comment = arg0.(*model.Comment)
+	}
+	return comment, args.Error(1)
+}
+
+func (o *CommentRepository) Fetch(ownerID, listID, taskID string, page, rpp int64) (comments []*model.Comment, err error) {
+	var args = o.Called(ownerID, listID, taskID, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		comments = arg0.([]*model.Comment)
+	}
+	return comments, args.Error(1)
+}
+
+func (o *CommentRepository) Update(ownerID, listID, taskID, commentID, content string, mentions []string) (ok bool, err error) {
+	var args = o.Called(ownerID, listID, taskID, commentID, content, mentions)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *CommentRepository) Remove(ownerID, listID, taskID, commentID string) (ok bool, err error) {
+	var args = o.Called(ownerID, listID, taskID, commentID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *CommentRepository) FetchActivity(ownerID, listID, taskID string, page, rpp int64) (activity []*model.Activity, err error) {
+	var args = o.Called(ownerID, listID, taskID, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		activity = arg0.([]*model.Activity)
+	}
+	return activity, args.Error(1)
+}
+
+type CommentServiceMock struct {
+	mock.Mock
+}
+
+func NewCommentServiceMock() *CommentServiceMock {
+	return new(CommentServiceMock)
+}
+
+func (o *CommentServiceMock) Save(userID, listID, taskID uuid.UUID, creation *transfer.CommentCreation) (insertedID uuid.UUID, err error) {
+	var args = o.Called(userID, listID, taskID, creation)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (o *CommentServiceMock) Fetch(userID, listID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Comment], err error) {
+	var args = o.Called(userID, listID, taskID, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Comment])
+	}
+	return result, args.Error(1)
+}
+
+func (o *CommentServiceMock) Update(userID, listID, taskID, commentID uuid.UUID, update *transfer.CommentUpdate) (ok bool, err error) {
+	var args = o.Called(userID, listID, taskID, commentID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *CommentServiceMock) Delete(userID, listID, taskID, commentID uuid.UUID) error {
+	var args = o.Called(userID, listID, taskID, commentID)
+	return args.Error(0)
+}
+
+func (o *CommentServiceMock) FetchActivity(userID, listID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Activity], err error) {
+	var args = o.Called(userID, listID, taskID, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Activity])
+	}
+	return result, args.Error(1)
+}
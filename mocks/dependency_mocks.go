@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+)
+
+type DependencyRepository struct {
+	mock.Mock
+}
+
+func NewDependencyRepositoryMock() *DependencyRepository {
+	return new(DependencyRepository)
+}
+
+func (o *DependencyRepository) Save(ownerID, taskID, blockerID string) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, blockerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *DependencyRepository) Remove(ownerID, taskID, blockerID string) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, blockerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *DependencyRepository) FetchGraph(ownerID, taskID string) (nodes []*model.DependencyNode, err error) {
+	var args = o.Called(ownerID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		nodes = arg0.([]*model.DependencyNode)
+	}
+	return nodes, args.Error(1)
+}
+
+type DependencyServiceMock struct {
+	mock.Mock
+}
+
+func NewDependencyServiceMock() *DependencyServiceMock {
+	return new(DependencyServiceMock)
+}
+
+func (o *DependencyServiceMock) Save(ownerID, taskID, blockerID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, blockerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *DependencyServiceMock) Remove(ownerID, taskID, blockerID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, blockerID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *DependencyServiceMock) FetchGraph(ownerID, taskID uuid.UUID) (graph *model.DependencyGraph, err error) {
+	var args = o.Called(ownerID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		graph = arg0.(*model.DependencyGraph)
+	}
+	return graph, args.Error(1)
+}
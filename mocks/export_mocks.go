@@ -0,0 +1,112 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"noda/data/model"
+	"time"
+)
+
+type ExportRepository struct {
+	mock.Mock
+}
+
+func NewExportRepositoryMock() *ExportRepository {
+	return new(ExportRepository)
+}
+
+func (o *ExportRepository) Save(ownerID string) (insertedID string, err error) {
+	var args = o.Called(ownerID)
+	return args.String(0), args.Error(1)
+}
+
+func (o *ExportRepository) FetchLatest(ownerID string) (export *model.Export, err error) {
+	var args = o.Called(ownerID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		export = arg0.(*model.Export)
+	}
+	return export, args.Error(1)
+}
+
+func (o *ExportRepository) FetchPending(limit int) (exports []*model.Export, err error) {
+	var args = o.Called(limit)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		exports = arg0.([]*model.Export)
+	}
+	return exports, args.Error(1)
+}
+
+func (o *ExportRepository) FetchExpired(now time.Time, limit int) (exports []*model.Export, err error) {
+	var args = o.Called(now, limit)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		exports = arg0.([]*model.Export)
+	}
+	return exports, args.Error(1)
+}
+
+func (o *ExportRepository) MarkReady(exportID, blobKey string, size int64, expiresAt time.Time) (ok bool, err error) {
+	var args = o.Called(exportID, blobKey, size, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *ExportRepository) MarkFailed(exportID string) (ok bool, err error) {
+	var args = o.Called(exportID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *ExportRepository) MarkExpired(exportID string) (ok bool, err error) {
+	var args = o.Called(exportID)
+	return args.Bool(0), args.Error(1)
+}
+
+type ExportServiceMock struct {
+	mock.Mock
+}
+
+func NewExportServiceMock() *ExportServiceMock {
+	return new(ExportServiceMock)
+}
+
+func (o *ExportServiceMock) Request(ownerID uuid.UUID) (export *model.Export, err error) {
+	var args = o.Called(ownerID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		export = arg0.(*model.Export)
+	}
+	return export, args.Error(1)
+}
+
+func (o *ExportServiceMock) FetchLatest(ownerID uuid.UUID) (export *model.Export, err error) {
+	var args = o.Called(ownerID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		export = arg0.(*model.Export)
+	}
+	return export, args.Error(1)
+}
+
+func (o *ExportServiceMock) Open(ownerID uuid.UUID) (export *model.Export, content io.ReadCloser, err error) {
+	var args = o.Called(ownerID)
+	var arg0, arg1 = args.Get(0), args.Get(1)
+	if nil != arg0 {
+		export = arg0.(*model.Export)
+	}
+	if nil != arg1 {
+		content = arg1.(io.ReadCloser)
+	}
+	return export, content, args.Error(2)
+}
+
+func (o *ExportServiceMock) Build(now time.Time) (built int, err error) {
+	var args = o.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
+func (o *ExportServiceMock) Purge(now time.Time) (purged int, err error) {
+	var args = o.Called(now)
+	return args.Int(0), args.Error(1)
+}
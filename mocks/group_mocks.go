@@ -1,9 +1,11 @@
 package mocks
 
 import (
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"noda/data/model"
 	"noda/data/transfer"
+	"noda/data/types"
 )
 
 type GroupRepository struct {
@@ -48,3 +50,48 @@ func (o *GroupRepository) Remove(ownerID, groupID string) (ok bool, err error) {
 	args := o.Called(ownerID, groupID)
 	return args.Bool(0), args.Error(1)
 }
+
+type GroupServiceMock struct {
+	mock.Mock
+}
+
+func NewGroupServiceMock() *GroupServiceMock {
+	return new(GroupServiceMock)
+}
+
+func (o *GroupServiceMock) Save(ownerID uuid.UUID, creation *transfer.GroupCreation) (insertedID uuid.UUID, err error) {
+	var args = o.Called(ownerID, creation)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		insertedID = arg0.(uuid.UUID)
+	}
+	return insertedID, args.Error(1)
+}
+
+func (o *GroupServiceMock) FetchByID(ownerID, groupID uuid.UUID) (group *model.Group, err error) {
+	var args = o.Called(ownerID, groupID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		group = arg0.(*model.Group)
+	}
+	return group, args.Error(1)
+}
+
+func (o *GroupServiceMock) Fetch(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Group], err error) {
+	var args = o.Called(ownerID, pagination, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Group])
+	}
+	return result, args.Error(1)
+}
+
+func (o *GroupServiceMock) Update(ownerID, groupID uuid.UUID, update *transfer.GroupUpdate) (ok bool, err error) {
+	var args = o.Called(ownerID, groupID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *GroupServiceMock) Remove(ownerID, groupID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(ownerID, groupID)
+	return args.Bool(0), args.Error(1)
+}
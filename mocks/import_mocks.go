@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"noda/data/model"
+	"noda/importer"
+)
+
+type ImportRepository struct {
+	mock.Mock
+}
+
+func NewImportRepositoryMock() *ImportRepository {
+	return new(ImportRepository)
+}
+
+func (o *ImportRepository) SaveRecord(ownerID, format, kind, key, entityID string) error {
+	var args = o.Called(ownerID, format, kind, key, entityID)
+	return args.Error(0)
+}
+
+func (o *ImportRepository) FetchRecords(ownerID, format string) (records []*model.ImportRecord, err error) {
+	var args = o.Called(ownerID, format)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		records = arg0.([]*model.ImportRecord)
+	}
+	return records, args.Error(1)
+}
+
+type ImportServiceMock struct {
+	mock.Mock
+}
+
+func NewImportServiceMock() *ImportServiceMock {
+	return new(ImportServiceMock)
+}
+
+func (o *ImportServiceMock) Import(ownerID uuid.UUID, format importer.Format, data io.Reader, dryRun bool) (report *model.ImportReport, err error) {
+	var args = o.Called(ownerID, format, data, dryRun)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		report = arg0.(*model.ImportReport)
+	}
+	return report, args.Error(1)
+}
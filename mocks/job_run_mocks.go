@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+)
+
+type JobRunRepository struct {
+	mock.Mock
+}
+
+func NewJobRunRepositoryMock() *JobRunRepository {
+	return new(JobRunRepository)
+}
+
+func (o *JobRunRepository) Start(job, instance string) (insertedID string, err error) {
+	var args = o.Called(job, instance)
+	return args.String(0), args.Error(1)
+}
+
+func (o *JobRunRepository) Finish(runID, errMessage string) (ok bool, err error) {
+	var args = o.Called(runID, errMessage)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *JobRunRepository) Fetch(page, rpp int64, job string) (runs []*model.JobRun, err error) {
+	var args = o.Called(page, rpp, job)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		runs = arg0.([]*model.JobRun)
+	}
+	return runs, args.Error(1)
+}
+
+type JobRunServiceMock struct {
+	mock.Mock
+}
+
+func NewJobRunServiceMock() *JobRunServiceMock {
+	return new(JobRunServiceMock)
+}
+
+func (o *JobRunServiceMock) Fetch(pagination *types.Pagination, job string) (result *types.Result[model.JobRun], err error) {
+	var args = o.Called(pagination, job)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.JobRun])
+	}
+	return result, args.Error(1)
+}
@@ -0,0 +1,165 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+)
+
+type MemberRepository struct {
+	mock.Mock
+}
+
+func NewMemberRepositoryMock() *MemberRepository {
+	return new(MemberRepository)
+}
+
+func (o *MemberRepository) FetchListAccess(userID, listID string) (access *model.ListAccess, err error) {
+	var args = o.Called(userID, listID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		access = arg0.(*model.ListAccess)
+	}
+	return access, args.Error(1)
+}
+
+func (o *MemberRepository) FetchGroupAccess(userID, groupID string) (access *model.GroupAccess, err error) {
+	var args = o.Called(userID, groupID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		access = arg0.(*model.GroupAccess)
+	}
+	return access, args.Error(1)
+}
+
+func (o *MemberRepository) Invite(inviterID string, kind types.ShareKind, targetID, email string, role types.MemberRole) (insertedID string, err error) {
+	var args = o.Called(inviterID, kind, targetID, email, role)
+	return args.String(0), args.Error(1)
+}
+
+func (o *MemberRepository) FetchInvitations(userID string, page, rpp int64) (invitations []*model.Invitation, err error) {
+	var args = o.Called(userID, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		invitations = arg0.([]*model.Invitation)
+	}
+	return invitations, args.Error(1)
+}
+
+func (o *MemberRepository) Accept(userID, invitationID string) (ok bool, err error) {
+	var args = o.Called(userID, invitationID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *MemberRepository) Decline(userID, invitationID string) (ok bool, err error) {
+	var args = o.Called(userID, invitationID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *MemberRepository) FetchMembers(kind types.ShareKind, targetID string, page, rpp int64) (members []*model.Member, err error) {
+	var args = o.Called(kind, targetID, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		members = arg0.([]*model.Member)
+	}
+	return members, args.Error(1)
+}
+
+func (o *MemberRepository) SetRole(kind types.ShareKind, targetID, memberID string, role types.MemberRole) (ok bool, err error) {
+	var args = o.Called(kind, targetID, memberID, role)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *MemberRepository) Remove(kind types.ShareKind, targetID, memberID string) (ok bool, err error) {
+	var args = o.Called(kind, targetID, memberID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *MemberRepository) FetchSharedLists(userID string, page, rpp int64, needle string) (lists []*model.SharedList, err error) {
+	var args = o.Called(userID, page, rpp, needle)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		lists = arg0.([]*model.SharedList)
+	}
+	return lists, args.Error(1)
+}
+
+func (o *MemberRepository) FetchSharedGroups(userID string, page, rpp int64, needle string) (groups []*model.SharedGroup, err error) {
+	var args = o.Called(userID, page, rpp, needle)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		groups = arg0.([]*model.SharedGroup)
+	}
+	return groups, args.Error(1)
+}
+
+type SharingServiceMock struct {
+	mock.Mock
+}
+
+func NewSharingServiceMock() *SharingServiceMock {
+	return new(SharingServiceMock)
+}
+
+func (o *SharingServiceMock) Invite(inviterID uuid.UUID, kind types.ShareKind, targetID uuid.UUID, invitation *transfer.Invitation) (insertedID uuid.UUID, err error) {
+	var args = o.Called(inviterID, kind, targetID, invitation)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (o *SharingServiceMock) FetchMembers(userID uuid.UUID, kind types.ShareKind, targetID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Member], err error) {
+	var args = o.Called(userID, kind, targetID, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Member])
+	}
+	return result, args.Error(1)
+}
+
+func (o *SharingServiceMock) SetMemberRole(userID uuid.UUID, kind types.ShareKind, targetID, memberID uuid.UUID, update *transfer.MemberRoleUpdate) (ok bool, err error) {
+	var args = o.Called(userID, kind, targetID, memberID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *SharingServiceMock) RemoveMember(userID uuid.UUID, kind types.ShareKind, targetID, memberID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(userID, kind, targetID, memberID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *SharingServiceMock) FetchInvitations(userID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Invitation], err error) {
+	var args = o.Called(userID, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Invitation])
+	}
+	return result, args.Error(1)
+}
+
+func (o *SharingServiceMock) AcceptInvitation(userID, invitationID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(userID, invitationID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *SharingServiceMock) DeclineInvitation(userID, invitationID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(userID, invitationID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *SharingServiceMock) FetchSharedLists(userID uuid.UUID, pagination *types.Pagination, needle string) (result *types.Result[model.SharedList], err error) {
+	var args = o.Called(userID, pagination, needle)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.SharedList])
+	}
+	return result, args.Error(1)
+}
+
+func (o *SharingServiceMock) FetchSharedGroups(userID uuid.UUID, pagination *types.Pagination, needle string) (result *types.Result[model.SharedGroup], err error) {
+	var args = o.Called(userID, pagination, needle)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.SharedGroup])
+	}
+	return result, args.Error(1)
+}
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"time"
+)
+
+type NotificationRepository struct {
+	mock.Mock
+}
+
+func NewNotificationRepositoryMock() *NotificationRepository {
+	return new(NotificationRepository)
+}
+
+func (o *NotificationRepository) Save(ownerID, taskID, title, message string, remindAt time.Time) (insertedID string, err error) {
+	var args = o.Called(ownerID, taskID, title, message, remindAt)
+	return args.String(0), args.Error(1)
+}
+
+func (o *NotificationRepository) Fetch(ownerID string, page, rpp int64) (notifications []*model.Notification, err error) {
+	var args = o.Called(ownerID, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		notifications = arg0.([]*model.Notification)
+	}
+	return notifications, args.Error(1)
+}
+
+func (o *NotificationRepository) Dismiss(ownerID, notificationID string) (ok bool, err error) {
+	var args = o.Called(ownerID, notificationID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *NotificationRepository) Snooze(ownerID, notificationID string, until time.Time) (ok bool, err error) {
+	var args = o.Called(ownerID, notificationID, until)
+	return args.Bool(0), args.Error(1)
+}
+
+type NotificationServiceMock struct {
+	mock.Mock
+}
+
+func NewNotificationServiceMock() *NotificationServiceMock {
+	return new(NotificationServiceMock)
+}
+
+func (o *NotificationServiceMock) Fetch(ownerID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Notification], err error) {
+	var args = o.Called(ownerID, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Notification])
+	}
+	return result, args.Error(1)
+}
+
+func (o *NotificationServiceMock) Dismiss(ownerID, notificationID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(ownerID, notificationID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *NotificationServiceMock) Snooze(ownerID, notificationID uuid.UUID, snooze *transfer.NotificationSnooze) (ok bool, err error) {
+	var args = o.Called(ownerID, notificationID, snooze)
+	return args.Bool(0), args.Error(1)
+}
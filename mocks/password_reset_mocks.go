@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"time"
+)
+
+type PasswordResetRepository struct {
+	mock.Mock
+}
+
+func NewPasswordResetRepositoryMock() *PasswordResetRepository {
+	return new(PasswordResetRepository)
+}
+
+func (o *PasswordResetRepository) Save(userID, hash string, expiresAt time.Time) (insertedID string, err error) {
+	var args = o.Called(userID, hash, expiresAt)
+	return args.String(0), args.Error(1)
+}
+
+func (o *PasswordResetRepository) FetchByHash(hash string) (reset *model.PasswordReset, err error) {
+	var args = o.Called(hash)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		reset = arg0.(*model.PasswordReset)
+	}
+	return reset, args.Error(1)
+}
+
+func (o *PasswordResetRepository) Consume(resetID string) (ok bool, err error) {
+	var args = o.Called(resetID)
+	return args.Bool(0), args.Error(1)
+}
+
+type PasswordResetServiceMock struct {
+	mock.Mock
+}
+
+func NewPasswordResetServiceMock() *PasswordResetServiceMock {
+	return new(PasswordResetServiceMock)
+}
+
+func (m *PasswordResetServiceMock) Request(email string) error {
+	var args = m.Called(email)
+	return args.Error(0)
+}
+
+func (m *PasswordResetServiceMock) Reset(token, password string) error {
+	var args = m.Called(token, password)
+	return args.Error(0)
+}
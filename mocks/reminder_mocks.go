@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+	"time"
+)
+
+type ReminderRepository struct {
+	mock.Mock
+}
+
+func NewReminderRepositoryMock() *ReminderRepository {
+	return new(ReminderRepository)
+}
+
+func (o *ReminderRepository) FetchDue(before time.Time, limit int64) (reminders []*model.DueReminder, err error) {
+	var args = o.Called(before, limit)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		reminders = arg0.([]*model.DueReminder)
+	}
+	return reminders, args.Error(1)
+}
+
+func (o *ReminderRepository) RecordDelivery(taskID string, remindAt time.Time, channel, errMessage string) (ok bool, err error) {
+	var args = o.Called(taskID, remindAt, channel, errMessage)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *ReminderRepository) MarkAsFired(taskID string, remindAt time.Time) (ok bool, err error) {
+	var args = o.Called(taskID, remindAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *ReminderRepository) FetchDeliveries(ownerID, taskID string, page, rpp int64) (deliveries []*model.ReminderDelivery, err error) {
+	var args = o.Called(ownerID, taskID, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		deliveries = arg0.([]*model.ReminderDelivery)
+	}
+	return deliveries, args.Error(1)
+}
+
+type ReminderServiceMock struct {
+	mock.Mock
+}
+
+func NewReminderServiceMock() *ReminderServiceMock {
+	return new(ReminderServiceMock)
+}
+
+func (o *ReminderServiceMock) Dispatch(now time.Time) (delivered int, err error) {
+	var args = o.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
+func (o *ReminderServiceMock) FetchDeliveries(ownerID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.ReminderDelivery], err error) {
+	var args = o.Called(ownerID, taskID, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.ReminderDelivery])
+	}
+	return result, args.Error(1)
+}
@@ -0,0 +1,86 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+	"time"
+)
+
+type RevisionRepository struct {
+	mock.Mock
+}
+
+func NewRevisionRepositoryMock() *RevisionRepository {
+	return new(RevisionRepository)
+}
+
+func (o *RevisionRepository) Save(ownerID, listID, taskID, actorID string, action types.RevisionAction) (insertedID string, err error) {
+	var args = o.Called(ownerID, listID, taskID, actorID, action)
+	return args.String(0), args.Error(1)
+}
+
+func (o *RevisionRepository) Discard(revisionID string) error {
+	var args = o.Called(revisionID)
+	return args.Error(0)
+}
+
+func (o *RevisionRepository) Fetch(ownerID, listID, taskID string, page, rpp int64) (revisions []*model.Revision, err error) {
+	var args = o.Called(ownerID, listID, taskID, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		revisions = arg0.([]*model.Revision)
+	}
+	return revisions, args.Error(1)
+}
+
+func (o *RevisionRepository) FetchLast(actorID string, since time.Time) (revision *model.Revision, err error) {
+	var args = o.Called(actorID, since)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		revision = arg0.(*model.Revision)
+	}
+	return revision, args.Error(1)
+}
+
+func (o *RevisionRepository) Restore(ownerID, listID, taskID, revisionID, actorID string) (ok bool, err error) {
+	var args = o.Called(ownerID, listID, taskID, revisionID, actorID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *RevisionRepository) Undo(ownerID, revisionID, actorID string) (ok bool, err error) {
+	var args = o.Called(ownerID, revisionID, actorID)
+	return args.Bool(0), args.Error(1)
+}
+
+type RevisionServiceMock struct {
+	mock.Mock
+}
+
+func NewRevisionServiceMock() *RevisionServiceMock {
+	return new(RevisionServiceMock)
+}
+
+func (o *RevisionServiceMock) Fetch(userID, listID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Revision], err error) {
+	var args = o.Called(userID, listID, taskID, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Revision])
+	}
+	return result, args.Error(1)
+}
+
+func (o *RevisionServiceMock) Restore(userID, listID, taskID, revisionID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(userID, listID, taskID, revisionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *RevisionServiceMock) Undo(userID uuid.UUID) (revision *model.Revision, err error) {
+	var args = o.Called(userID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		revision = arg0.(*model.Revision)
+	}
+	return revision, args.Error(1)
+}
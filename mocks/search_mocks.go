@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+)
+
+type SearchRepository struct {
+	mock.Mock
+}
+
+func NewSearchRepositoryMock() *SearchRepository {
+	return new(SearchRepository)
+}
+
+func (o *SearchRepository) Search(userID, query string, entities []types.SearchEntity, limit int64) (hits []*model.SearchHit, err error) {
+	var args = o.Called(userID, query, entities, limit)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		hits = arg0.([]*model.SearchHit)
+	}
+	return hits, args.Error(1)
+}
+
+func (o *SearchRepository) CountHits(userID, query string) (facets map[types.SearchEntity]int64, err error) {
+	var args = o.Called(userID, query)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		facets = arg0.(map[types.SearchEntity]int64)
+	}
+	return facets, args.Error(1)
+}
+
+type SearchServiceMock struct {
+	mock.Mock
+}
+
+func NewSearchServiceMock() *SearchServiceMock {
+	return new(SearchServiceMock)
+}
+
+func (o *SearchServiceMock) Search(userID uuid.UUID, needle string, entities []types.SearchEntity, pagination *types.Pagination) (result *model.SearchResult, err error) {
+	var args = o.Called(userID, needle, entities, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*model.SearchResult)
+	}
+	return result, args.Error(1)
+}
@@ -0,0 +1,109 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+)
+
+type SmartListRepository struct {
+	mock.Mock
+}
+
+func NewSmartListRepositoryMock() *SmartListRepository {
+	return new(SmartListRepository)
+}
+
+func (o *SmartListRepository) Save(ownerID string, creation *transfer.SmartListCreation) (insertedID string, err error) {
+	var args = o.Called(ownerID, creation)
+	return args.String(0), args.Error(1)
+}
+
+func (o *SmartListRepository) FetchByID(ownerID, smartListID string) (smartList *model.SmartList, err error) {
+	var args = o.Called(ownerID, smartListID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		smartList = arg0.(*model.SmartList)
+	}
+	return smartList, args.Error(1)
+}
+
+func (o *SmartListRepository) Fetch(ownerID string) (smartLists []*model.SmartList, err error) {
+	var args = o.Called(ownerID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		smartLists = arg0.([]*model.SmartList)
+	}
+	return smartLists, args.Error(1)
+}
+
+func (o *SmartListRepository) FetchCandidates(ownerID string, candidates *types.CandidateFilter, page, rpp int64) (tasks []*model.FilterableTask, err error) {
+	var args = o.Called(ownerID, candidates, page, rpp)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tasks = arg0.([]*model.FilterableTask)
+	}
+	return tasks, args.Error(1)
+}
+
+func (o *SmartListRepository) Update(ownerID, smartListID string, update *transfer.SmartListUpdate) (ok bool, err error) {
+	var args = o.Called(ownerID, smartListID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *SmartListRepository) Delete(ownerID, smartListID string) error {
+	var args = o.Called(ownerID, smartListID)
+	return args.Error(0)
+}
+
+type SmartListServiceMock struct {
+	mock.Mock
+}
+
+func NewSmartListServiceMock() *SmartListServiceMock {
+	return new(SmartListServiceMock)
+}
+
+func (o *SmartListServiceMock) Save(ownerID uuid.UUID, creation *transfer.SmartListCreation) (insertedID uuid.UUID, err error) {
+	var args = o.Called(ownerID, creation)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (o *SmartListServiceMock) FetchByID(ownerID, smartListID uuid.UUID) (smartList *model.SmartList, err error) {
+	var args = o.Called(ownerID, smartListID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		smartList = arg0.(*model.SmartList)
+	}
+	return smartList, args.Error(1)
+}
+
+func (o *SmartListServiceMock) Fetch(ownerID uuid.UUID) (smartLists []*model.SmartList, err error) {
+	var args = o.Called(ownerID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		smartLists = arg0.([]*model.SmartList)
+	}
+	return smartLists, args.Error(1)
+}
+
+func (o *SmartListServiceMock) FetchTasks(ownerID, smartListID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Task], err error) {
+	var args = o.Called(ownerID, smartListID, pagination)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Task])
+	}
+	return result, args.Error(1)
+}
+
+func (o *SmartListServiceMock) Update(ownerID, smartListID uuid.UUID, update *transfer.SmartListUpdate) (ok bool, err error) {
+	var args = o.Called(ownerID, smartListID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *SmartListServiceMock) Delete(ownerID, smartListID uuid.UUID) error {
+	var args = o.Called(ownerID, smartListID)
+	return args.Error(0)
+}
@@ -0,0 +1,106 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+)
+
+type StepRepository struct {
+	mock.Mock
+}
+
+func NewStepRepositoryMock() *StepRepository {
+	return new(StepRepository)
+}
+
+func (o *StepRepository) Save(ownerID, taskID string, creation *transfer.StepCreation) (insertedID string, err error) {
+	var args = o.Called(ownerID, taskID, creation)
+	return args.String(0), args.Error(1)
+}
+
+func (o *StepRepository) Fetch(ownerID, taskID string) (steps []*model.Step, err error) {
+	var args = o.Called(ownerID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		steps = arg0.([]*model.Step)
+	}
+	return steps, args.Error(1)
+}
+
+func (o *StepRepository) Update(ownerID, taskID, stepID string, update *transfer.StepUpdate) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, stepID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *StepRepository) Accomplish(ownerID, taskID, stepID string) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, stepID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *StepRepository) Unaccomplish(ownerID, taskID, stepID string) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, stepID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *StepRepository) Reorder(ownerID, taskID, stepID string, position uint64) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, stepID, position)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *StepRepository) Delete(ownerID, taskID, stepID string) error {
+	var args = o.Called(ownerID, taskID, stepID)
+	return args.Error(0)
+}
+
+type StepServiceMock struct {
+	mock.Mock
+}
+
+func NewStepServiceMock() *StepServiceMock {
+	return new(StepServiceMock)
+}
+
+func (o *StepServiceMock) Save(ownerID, taskID uuid.UUID, creation *transfer.StepCreation) (insertedID uuid.UUID, err error) {
+	var args = o.Called(ownerID, taskID, creation)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		insertedID = arg0.(uuid.UUID)
+	}
+	return insertedID, args.Error(1)
+}
+
+func (o *StepServiceMock) Fetch(ownerID, taskID uuid.UUID) (steps []*model.Step, err error) {
+	var args = o.Called(ownerID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		steps = arg0.([]*model.Step)
+	}
+	return steps, args.Error(1)
+}
+
+func (o *StepServiceMock) Update(ownerID, taskID, stepID uuid.UUID, update *transfer.StepUpdate) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, stepID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *StepServiceMock) Accomplish(ownerID, taskID, stepID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, stepID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *StepServiceMock) Unaccomplish(ownerID, taskID, stepID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, stepID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *StepServiceMock) Reorder(ownerID, taskID, stepID uuid.UUID, position uint64) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, stepID, position)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *StepServiceMock) Delete(ownerID, taskID, stepID uuid.UUID) error {
+	var args = o.Called(ownerID, taskID, stepID)
+	return args.Error(0)
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+)
+
+type SubtaskRepository struct {
+	mock.Mock
+}
+
+func NewSubtaskRepositoryMock() *SubtaskRepository {
+	return new(SubtaskRepository)
+}
+
+func (o *SubtaskRepository) Save(ownerID, listID, parentID string, creation *transfer.TaskCreation) (insertedID string, err error) {
+	var args = o.Called(ownerID, listID, parentID, creation)
+	return args.String(0), args.Error(1)
+}
+
+func (o *SubtaskRepository) FetchTree(ownerID, listID, taskID string) (tasks []*model.Task, err error) {
+	var args = o.Called(ownerID, listID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tasks = arg0.([]*model.Task)
+	}
+	return tasks, args.Error(1)
+}
+
+func (o *SubtaskRepository) FetchDepth(ownerID, listID, taskID string) (depth int, err error) {
+	var args = o.Called(ownerID, listID, taskID)
+	return args.Int(0), args.Error(1)
+}
+
+func (o *SubtaskRepository) PromoteStep(ownerID, listID, taskID, stepID string) (insertedID string, err error) {
+	var args = o.Called(ownerID, listID, taskID, stepID)
+	return args.String(0), args.Error(1)
+}
+
+type SubtaskServiceMock struct {
+	mock.Mock
+}
+
+func NewSubtaskServiceMock() *SubtaskServiceMock {
+	return new(SubtaskServiceMock)
+}
+
+func (o *SubtaskServiceMock) Save(userID, listID, parentID uuid.UUID, creation *transfer.TaskCreation) (insertedID uuid.UUID, err error) {
+	var args = o.Called(userID, listID, parentID, creation)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
+
+func (o *SubtaskServiceMock) FetchTree(userID, listID, taskID uuid.UUID) (tree *model.TaskTree, err error) {
+	var args = o.Called(userID, listID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tree = arg0.(*model.TaskTree)
+	}
+	return tree, args.Error(1)
+}
+
+func (o *SubtaskServiceMock) PromoteStep(userID, listID, taskID, stepID uuid.UUID) (insertedID uuid.UUID, err error) {
+	var args = o.Called(userID, listID, taskID, stepID)
+	return args.Get(0).(uuid.UUID), args.Error(1)
+}
@@ -0,0 +1,133 @@
+package mocks
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+)
+
+type TagRepository struct {
+	mock.Mock
+}
+
+func NewTagRepositoryMock() *TagRepository {
+	return new(TagRepository)
+}
+
+func (o *TagRepository) Save(ownerID string, creation *transfer.TagCreation) (insertedID string, err error) {
+	var args = o.Called(ownerID, creation)
+	return args.String(0), args.Error(1)
+}
+
+func (o *TagRepository) FetchByID(ownerID, tagID string) (tag *model.Tag, err error) {
+	var args = o.Called(ownerID, tagID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tag = arg0.(*model.Tag)
+	}
+	return tag, args.Error(1)
+}
+
+func (o *TagRepository) Fetch(ownerID string, page, rpp int64, needle, sortExpr string) (tags []*model.Tag, err error) {
+	var args = o.Called(ownerID, page, rpp, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tags = arg0.([]*model.Tag)
+	}
+	return tags, args.Error(1)
+}
+
+func (o *TagRepository) FetchFromTask(ownerID, taskID string) (tags []*model.Tag, err error) {
+	var args = o.Called(ownerID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tags = arg0.([]*model.Tag)
+	}
+	return tags, args.Error(1)
+}
+
+func (o *TagRepository) Update(ownerID, tagID string, update *transfer.TagUpdate) (ok bool, err error) {
+	var args = o.Called(ownerID, tagID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TagRepository) Attach(ownerID, taskID, tagID string) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, tagID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TagRepository) Detach(ownerID, taskID, tagID string) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, tagID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TagRepository) Delete(ownerID, tagID string) error {
+	var args = o.Called(ownerID, tagID)
+	return args.Error(0)
+}
+
+type TagServiceMock struct {
+	mock.Mock
+}
+
+func NewTagServiceMock() *TagServiceMock {
+	return new(TagServiceMock)
+}
+
+func (o *TagServiceMock) Save(ownerID uuid.UUID, creation *transfer.TagCreation) (insertedID uuid.UUID, err error) {
+	var args = o.Called(ownerID, creation)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		insertedID = arg0.(uuid.UUID)
+	}
+	return insertedID, args.Error(1)
+}
+
+func (o *TagServiceMock) FetchByID(ownerID, tagID uuid.UUID) (tag *model.Tag, err error) {
+	var args = o.Called(ownerID, tagID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tag = arg0.(*model.Tag)
+	}
+	return tag, args.Error(1)
+}
+
+func (o *TagServiceMock) Fetch(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Tag], err error) {
+	var args = o.Called(ownerID, pagination, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Tag])
+	}
+	return result, args.Error(1)
+}
+
+func (o *TagServiceMock) FetchFromTask(ownerID, taskID uuid.UUID) (tags []*model.Tag, err error) {
+	var args = o.Called(ownerID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tags = arg0.([]*model.Tag)
+	}
+	return tags, args.Error(1)
+}
+
+func (o *TagServiceMock) Update(ownerID, tagID uuid.UUID, update *transfer.TagUpdate) (ok bool, err error) {
+	var args = o.Called(ownerID, tagID, update)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TagServiceMock) Attach(ownerID, taskID, tagID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, tagID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TagServiceMock) Detach(ownerID, taskID, tagID uuid.UUID) (ok bool, err error) {
+	var args = o.Called(ownerID, taskID, tagID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TagServiceMock) Delete(ownerID, tagID uuid.UUID) error {
+	var args = o.Called(ownerID, tagID)
+	return args.Error(0)
+}
@@ -36,8 +36,8 @@ func (o *TaskRepository) FetchByID(ownerID, listID, taskID string) (task *model.
 	return task, args.Error(1)
 }
 
-func (o *TaskRepository) Fetch(ownerID, listID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
-	var args = o.Called(ownerID, listID, page, rpp, needle, sortExpr)
+func (o *TaskRepository) Fetch(ownerID, listID string, page, rpp int64, needle, sortExpr string, tagIDs []string, matchAllTags bool, assigneeID string) (tasks []*model.Task, err error) {
+	var args = o.Called(ownerID, listID, page, rpp, needle, sortExpr, tagIDs, matchAllTags, assigneeID)
 	var arg0 = args.Get(0)
 	if nil != arg0 {
 		tasks = arg0.([]*model.Task)
@@ -45,7 +45,25 @@ func (o *TaskRepository) Fetch(ownerID, listID string, page, rpp int64, needle,
 	return tasks, args.Error(1)
 }
 
-func (o *TaskRepository) FetchFromToday(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+func (o *TaskRepository) FetchFromToday(ownerID string, page, rpp int64, needle, sortExpr string, tagIDs []string, matchAllTags bool) (tasks []*model.Task, err error) {
+	var args = o.Called(ownerID, page, rpp, needle, sortExpr, tagIDs, matchAllTags)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tasks = arg0.([]*model.Task)
+	}
+	return tasks, args.Error(1)
+}
+
+func (o *TaskRepository) FetchFromTomorrow(ownerID string, page, rpp int64, needle, sortExpr string, tagIDs []string, matchAllTags bool) (tasks []*model.Task, err error) {
+	var args = o.Called(ownerID, page, rpp, needle, sortExpr, tagIDs, matchAllTags)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tasks = arg0.([]*model.Task)
+	}
+	return tasks, args.Error(1)
+}
+
+func (o *TaskRepository) FetchFromDeferred(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
 	var args = o.Called(ownerID, page, rpp, needle, sortExpr)
 	var arg0 = args.Get(0)
 	if nil != arg0 {
@@ -54,7 +72,7 @@ func (o *TaskRepository) FetchFromToday(ownerID string, page, rpp int64, needle,
 	return tasks, args.Error(1)
 }
 
-func (o *TaskRepository) FetchFromTomorrow(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+func (o *TaskRepository) FetchAll(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
 	var args = o.Called(ownerID, page, rpp, needle, sortExpr)
 	var arg0 = args.Get(0)
 	if nil != arg0 {
@@ -63,7 +81,25 @@ func (o *TaskRepository) FetchFromTomorrow(ownerID string, page, rpp int64, need
 	return tasks, args.Error(1)
 }
 
-func (o *TaskRepository) FetchFromDeferred(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+func (o *TaskRepository) FetchCompleted(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var args = o.Called(ownerID, page, rpp, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tasks = arg0.([]*model.Task)
+	}
+	return tasks, args.Error(1)
+}
+
+func (o *TaskRepository) FetchArchived(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var args = o.Called(ownerID, page, rpp, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		tasks = arg0.([]*model.Task)
+	}
+	return tasks, args.Error(1)
+}
+
+func (o *TaskRepository) FetchTrashed(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
 	var args = o.Called(ownerID, page, rpp, needle, sortExpr)
 	var arg0 = args.Get(0)
 	if nil != arg0 {
@@ -72,6 +108,11 @@ func (o *TaskRepository) FetchFromDeferred(ownerID string, page, rpp int64, need
 	return tasks, args.Error(1)
 }
 
+func (o *TaskRepository) Locate(taskID string) (listID string, err error) {
+	var args = o.Called(taskID)
+	return args.String(0), args.Error(1)
+}
+
 func (o *TaskRepository) Update(ownerID, listID, taskID string, update *transfer.TaskUpdate) (ok bool, err error) {
 	var args = o.Called(ownerID, listID, taskID, update)
 	return args.Bool(0), args.Error(1)
@@ -97,6 +138,16 @@ func (o *TaskRepository) SetDueDate(ownerID, listID, taskID string, dueDate time
 	return args.Bool(0), args.Error(1)
 }
 
+func (o *TaskRepository) SetRecurrence(ownerID, listID, taskID string, rule *types.RecurrenceRule) (ok bool, err error) {
+	var args = o.Called(ownerID, listID, taskID, rule)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TaskRepository) SaveOccurrence(ownerID, listID, taskID string, dueDate time.Time, remindAt *time.Time, rule *types.RecurrenceRule) (insertedID string, err error) {
+	var args = o.Called(ownerID, listID, taskID, dueDate, remindAt, rule)
+	return args.String(0), args.Error(1)
+}
+
 func (o *TaskRepository) Complete(ownerID, listID, taskID string) (ok bool, err error) {
 	var args = o.Called(ownerID, listID, taskID)
 	return args.Bool(0), args.Error(1)
@@ -152,6 +203,20 @@ func (o *TaskRepository) Delete(ownerID, listID, taskID string) error {
 	return args.Error(0)
 }
 
+func (o *TaskRepository) FetchRolloverCandidates() (candidates []*model.RolloverCandidate, err error) {
+	var args = o.Called()
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		candidates = arg0.([]*model.RolloverCandidate)
+	}
+	return candidates, args.Error(1)
+}
+
+func (o *TaskRepository) RollOver(ownerID string, day time.Time) (ok bool, err error) {
+	var args = o.Called(ownerID, day)
+	return args.Bool(0), args.Error(1)
+}
+
 type TaskServiceMock struct {
 	mock.Mock
 }
@@ -183,8 +248,26 @@ func (o *TaskServiceMock) FetchByID(ownerID, listID, taskID uuid.UUID) (task *mo
 	return task, args.Error(1)
 }
 
-func (o *TaskServiceMock) Fetch(ownerID, listID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
-	var args = o.Called(ownerID, listID, pagination, needle, sortExpr)
+func (o *TaskServiceMock) Fetch(ownerID, listID uuid.UUID, pagination *types.Pagination, needle, sortExpr string, filter *types.TagFilter, assigneeID uuid.UUID) (result *types.Result[model.Task], err error) {
+	var args = o.Called(ownerID, listID, pagination, needle, sortExpr, filter, assigneeID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Task])
+	}
+	return result, args.Error(1)
+}
+
+func (o *TaskServiceMock) FetchFromToday(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string, filter *types.TagFilter) (result *types.Result[model.Task], err error) {
+	var args = o.Called(ownerID, pagination, needle, sortExpr, filter)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Task])
+	}
+	return result, args.Error(1)
+}
+
+func (o *TaskServiceMock) FetchFromTomorrow(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string, filter *types.TagFilter) (result *types.Result[model.Task], err error) {
+	var args = o.Called(ownerID, pagination, needle, sortExpr, filter)
 	var arg0 = args.Get(0)
 	if nil != arg0 {
 		result = arg0.(*types.Result[model.Task])
@@ -192,7 +275,7 @@ func (o *TaskServiceMock) Fetch(ownerID, listID uuid.UUID, pagination *types.Pag
 	return result, args.Error(1)
 }
 
-func (o *TaskServiceMock) FetchFromToday(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+func (o *TaskServiceMock) FetchFromDeferred(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
 	var args = o.Called(ownerID, pagination, needle, sortExpr)
 	var arg0 = args.Get(0)
 	if nil != arg0 {
@@ -201,7 +284,7 @@ func (o *TaskServiceMock) FetchFromToday(ownerID uuid.UUID, pagination *types.Pa
 	return result, args.Error(1)
 }
 
-func (o *TaskServiceMock) FetchFromTomorrow(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+func (o *TaskServiceMock) FetchAll(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
 	var args = o.Called(ownerID, pagination, needle, sortExpr)
 	var arg0 = args.Get(0)
 	if nil != arg0 {
@@ -210,7 +293,25 @@ func (o *TaskServiceMock) FetchFromTomorrow(ownerID uuid.UUID, pagination *types
 	return result, args.Error(1)
 }
 
-func (o *TaskServiceMock) FetchFromDeferred(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+func (o *TaskServiceMock) FetchCompleted(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+	var args = o.Called(ownerID, pagination, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Task])
+	}
+	return result, args.Error(1)
+}
+
+func (o *TaskServiceMock) FetchArchived(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+	var args = o.Called(ownerID, pagination, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[model.Task])
+	}
+	return result, args.Error(1)
+}
+
+func (o *TaskServiceMock) FetchTrashed(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
 	var args = o.Called(ownerID, pagination, needle, sortExpr)
 	var arg0 = args.Get(0)
 	if nil != arg0 {
@@ -219,31 +320,59 @@ func (o *TaskServiceMock) FetchFromDeferred(ownerID uuid.UUID, pagination *types
 	return result, args.Error(1)
 }
 
+func (o *TaskServiceMock) Locate(userID, taskID uuid.UUID) (listID uuid.UUID, err error) {
+	var args = o.Called(userID, taskID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		listID = arg0.(uuid.UUID)
+	}
+	return listID, args.Error(1)
+}
+
 func (o *TaskServiceMock) Update(ownerID, listID, taskID uuid.UUID, update *transfer.TaskUpdate) (ok bool, err error) {
-	var args = o.Called(ownerID, listID, taskID)
+	var args = o.Called(ownerID, listID, taskID, update)
 	return args.Bool(0), args.Error(1)
 }
 
 func (o *TaskServiceMock) Reorder(ownerID, listID, taskID uuid.UUID, position uint64) (ok bool, err error) {
-	var args = o.Called(ownerID, listID, taskID)
+	var args = o.Called(ownerID, listID, taskID, position)
 	return args.Bool(0), args.Error(1)
 }
 
 func (o *TaskServiceMock) SetReminder(ownerID, listID, taskID uuid.UUID, remindAt time.Time) (ok bool, err error) {
-	var args = o.Called(ownerID, listID, taskID)
+	var args = o.Called(ownerID, listID, taskID, remindAt)
 	return args.Bool(0), args.Error(1)
 }
 
 func (o *TaskServiceMock) SetPriority(ownerID, listID, taskID uuid.UUID, priority types.TaskPriority) (ok bool, err error) {
-	var args = o.Called(ownerID, listID, taskID)
+	var args = o.Called(ownerID, listID, taskID, priority)
 	return args.Bool(0), args.Error(1)
 }
 
 func (o *TaskServiceMock) SetDueDate(ownerID, listID, taskID uuid.UUID, dueDate time.Time) (ok bool, err error) {
+	var args = o.Called(ownerID, listID, taskID, dueDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TaskServiceMock) SetRecurrence(ownerID, listID, taskID uuid.UUID, rule string) (ok bool, err error) {
+	var args = o.Called(ownerID, listID, taskID, rule)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TaskServiceMock) RemoveRecurrence(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
 	var args = o.Called(ownerID, listID, taskID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (o *TaskServiceMock) Occurrences(ownerID, listID, taskID uuid.UUID, n int) (occurrences []time.Time, err error) {
+	var args = o.Called(ownerID, listID, taskID, n)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		occurrences = arg0.([]time.Time)
+	}
+	return occurrences, args.Error(1)
+}
+
 func (o *TaskServiceMock) Complete(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
 	var args = o.Called(ownerID, listID, taskID)
 	return args.Bool(0), args.Error(1)
@@ -265,7 +394,7 @@ func (o *TaskServiceMock) Unpin(ownerID, listID, taskID uuid.UUID) (ok bool, err
 }
 
 func (o *TaskServiceMock) Move(ownerID, taskID, targetListID uuid.UUID) (ok bool, err error) {
-	var args = o.Called(ownerID, taskID)
+	var args = o.Called(ownerID, taskID, targetListID)
 	return args.Bool(0), args.Error(1)
 }
 
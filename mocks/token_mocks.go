@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"time"
+)
+
+type TokenRepository struct {
+	mock.Mock
+}
+
+func NewTokenRepositoryMock() *TokenRepository {
+	return new(TokenRepository)
+}
+
+func (o *TokenRepository) Save(userID, sessionID, hash string, expiresAt time.Time) (insertedID string, err error) {
+	var args = o.Called(userID, sessionID, hash, expiresAt)
+	return args.String(0), args.Error(1)
+}
+
+func (o *TokenRepository) FetchByHash(hash string) (token *model.RefreshToken, err error) {
+	var args = o.Called(hash)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		token = arg0.(*model.RefreshToken)
+	}
+	return token, args.Error(1)
+}
+
+func (o *TokenRepository) Revoke(tokenID string) (ok bool, err error) {
+	var args = o.Called(tokenID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TokenRepository) RevokeSession(userID, sessionID string) (ok bool, err error) {
+	var args = o.Called(userID, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TokenRepository) RevokeAll(userID string) (ok bool, err error) {
+	var args = o.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *TokenRepository) DenySession(sessionID string, until time.Time) error {
+	var args = o.Called(sessionID, until)
+	return args.Error(0)
+}
+
+func (o *TokenRepository) FetchSessionDenial(sessionID string) (until *time.Time, err error) {
+	var args = o.Called(sessionID)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		until = arg0.(*time.Time)
+	}
+	return until, args.Error(1)
+}
+
+func (o *TokenRepository) PurgeSessionDenials() (purged int64, err error) {
+	var args = o.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
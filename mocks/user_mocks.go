@@ -6,6 +6,7 @@ import (
 	"noda/data/model"
 	"noda/data/transfer"
 	"noda/data/types"
+	"time"
 )
 
 type UserRepository struct {
@@ -112,8 +113,22 @@ func (o *UserRepository) UpdateUserSetting(userID, settingKey, newValue string)
 	return args.Bool(0), args.Error(1)
 }
 
-func (o *UserRepository) Block(id string) (ok bool, err error) {
+func (o *UserRepository) UpdatePassword(id, hashedPassword string) (ok bool, err error) {
+	var args = o.Called(id, hashedPassword)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *UserRepository) FetchStatus(id string) (status *model.UserStatus, err error) {
 	var args = o.Called(id)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		status = arg0.(*model.UserStatus)
+	}
+	return status, args.Error(1)
+}
+
+func (o *UserRepository) Block(id, reason string, until *time.Time) (ok bool, err error) {
+	var args = o.Called(id, reason, until)
 	return args.Bool(0), args.Error(1)
 }
 
@@ -137,11 +152,30 @@ func (o *UserRepository) RemoveHardly(id string) error {
 	return args.Error(0)
 }
 
-func (o *UserRepository) RemoveSoftly(id string) error {
-	var args = o.Called(id)
+func (o *UserRepository) RemoveSoftly(id string, purgeAt time.Time) error {
+	var args = o.Called(id, purgeAt)
 	return args.Error(0)
 }
 
+func (o *UserRepository) Restore(id string) (ok bool, err error) {
+	var args = o.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *UserRepository) FetchDeleted(page, rpp int64, needle, sortExpr string) (users []*transfer.DeletedUser, err error) {
+	var args = o.Called(page, rpp, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		users = arg0.([]*transfer.DeletedUser)
+	}
+	return users, args.Error(1)
+}
+
+func (o *UserRepository) PurgeDeleted() (purged int64, err error) {
+	var args = o.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type UserService struct {
 	mock.Mock
 }
@@ -237,8 +271,23 @@ func (o *UserService) UpdateUserSetting(userID uuid.UUID, settingKey string, upd
 	return args.Bool(0), args.Error(1)
 }
 
-func (o *UserService) Block(id uuid.UUID) (ok bool, err error) {
+func (o *UserService) ChangePassword(id uuid.UUID, change *transfer.PasswordChange) (ok bool, err error) {
+	var args = o.Called(id, change)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *UserService) SetPassword(id uuid.UUID, password string) (ok bool, err error) {
+	var args = o.Called(id, password)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *UserService) AssertActive(id uuid.UUID) (role types.Role, err error) {
 	var args = o.Called(id)
+	return args.Get(0).(types.Role), args.Error(1)
+}
+
+func (o *UserService) Block(id uuid.UUID, block *transfer.UserBlock) (ok bool, err error) {
+	var args = o.Called(id, block)
 	return args.Bool(0), args.Error(1)
 }
 
@@ -262,5 +311,24 @@ func (o *UserService) RemoveHardly(id uuid.UUID) error {
 }
 
 func (o *UserService) RemoveSoftly(id uuid.UUID) error {
-	return o.Called(id).Error(1)
+	return o.Called(id).Error(0)
+}
+
+func (o *UserService) Restore(id uuid.UUID) (ok bool, err error) {
+	var args = o.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (o *UserService) FetchDeleted(pagination *types.Pagination, needle, sortExpr string) (result *types.Result[transfer.DeletedUser], err error) {
+	var args = o.Called(pagination, needle, sortExpr)
+	var arg0 = args.Get(0)
+	if nil != arg0 {
+		result = arg0.(*types.Result[transfer.DeletedUser])
+	}
+	return result, args.Error(1)
+}
+
+func (o *UserService) PurgeDeleted() (purged int64, err error) {
+	var args = o.Called()
+	return args.Get(0).(int64), args.Error(1)
 }
@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"noda/mail"
+	"strings"
+	"time"
+)
+
+type emailNotifier struct {
+	mailer mail.Mailer
+}
+
+// NewEmailNotifier returns a Notifier that mails the reminders to their
+// owners.
+func NewEmailNotifier(mailer mail.Mailer) Notifier {
+	return &emailNotifier{mailer}
+}
+
+func (n *emailNotifier) Channel() string {
+	return "email"
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, reminder *Reminder) error {
+	if "" == reminder.Email {
+		return ErrNoRecipient
+	}
+	var body strings.Builder
+	body.WriteString("This is a reminder of your task:\n\n")
+	body.WriteString(reminder.Title + "\n")
+	if "" != reminder.Headline {
+		body.WriteString(reminder.Headline + "\n")
+	}
+	if nil != reminder.DueDate {
+		fmt.Fprintf(&body, "\nIt is due on %s.\n", reminder.DueDate.UTC().Format(time.RFC1123))
+	}
+	return n.mailer.Send(ctx, &mail.Message{
+		To: []string{reminder.Email},
+		/* A title with line breaks would be refused as a header.  */
+		Subject: "Reminder: " + strings.Join(strings.Fields(reminder.Title), " "),
+		Body:    body.String(),
+	})
+}
@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Inbox keeps the in-app notifications of the users. Saving the notification
+// of a reminder that is already in the inbox must not add it twice.
+type Inbox interface {
+	Save(ownerID, taskID, title, message string, remindAt time.Time) (insertedID string, err error)
+}
+
+type inboxNotifier struct {
+	inbox Inbox
+}
+
+// NewInboxNotifier returns a Notifier that puts the reminders in the in-app
+// inbox of their owners.
+func NewInboxNotifier(inbox Inbox) Notifier {
+	return &inboxNotifier{inbox}
+}
+
+func (n *inboxNotifier) Channel() string {
+	return "inbox"
+}
+
+func (n *inboxNotifier) Notify(_ context.Context, reminder *Reminder) error {
+	var message = reminder.Headline
+	if nil != reminder.DueDate {
+		if "" != message {
+			message += " "
+		}
+		message += fmt.Sprintf("Due on %s.", reminder.DueDate.UTC().Format(time.RFC1123))
+	}
+	_, err := n.inbox.Save(reminder.OwnerUUID.String(), reminder.TaskUUID.String(), reminder.Title, message, reminder.RemindAt)
+	return err
+}
@@ -0,0 +1,40 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNoRecipient is returned by a Notifier when the owner of the reminder has
+// no address for its channel, such as a user without a webhook URL. It is not
+// a failure: there is nothing to deliver.
+var ErrNoRecipient = errors.New("reminder has no recipient for this channel")
+
+// Reminder is what is delivered when the reminder of a task is due.
+type Reminder struct {
+	// Key identifies the reminder of the task at RemindAt. It is the same
+	// every time the delivery is attempted, so that the receivers can drop
+	// the duplicates.
+	Key        string
+	TaskUUID   uuid.UUID
+	OwnerUUID  uuid.UUID
+	Title      string
+	Headline   string
+	DueDate    *time.Time
+	RemindAt   time.Time
+	Email      string // Email is empty if the owner does not want reminders by email.
+	WebhookURL string // WebhookURL is empty if the owner has not set any.
+}
+
+// Notifier delivers reminders through one channel.
+type Notifier interface {
+	// Channel is the name of the channel, as recorded in the delivery history.
+	Channel() string
+
+	// Notify delivers reminder. Delivering the same reminder more than once
+	// must be harmless, for it is retried until it succeeds.
+	Notify(ctx context.Context, reminder *Reminder) error
+}
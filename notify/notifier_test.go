@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"noda/mail"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReminder() *Reminder {
+	var due = time.Date(2024, time.January, 2, 9, 0, 0, 0, time.UTC)
+	return &Reminder{
+		Key:       "key",
+		TaskUUID:  uuid.New(),
+		OwnerUUID: uuid.New(),
+		Title:     "Pay\nthe rent",
+		Headline:  "Before noon.",
+		DueDate:   &due,
+		RemindAt:  time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestEmailNotifier_Notify(t *testing.T) {
+	var (
+		mailer   = mail.NewMemoryMailer()
+		n        = NewEmailNotifier(mailer)
+		reminder = newReminder()
+	)
+	assert.Equal(t, "email", n.Channel())
+	assert.ErrorIs(t, n.Notify(context.Background(), reminder), ErrNoRecipient)
+
+	reminder.Email = "foo@bar.com"
+	require.NoError(t, n.Notify(context.Background(), reminder))
+	var messages = mailer.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, []string{"foo@bar.com"}, messages[0].To)
+	assert.Equal(t, "Reminder: Pay the rent", messages[0].Subject)
+	assert.Contains(t, messages[0].Body, "Before noon.")
+	assert.Contains(t, messages[0].Body, "Tue, 02 Jan 2024 09:00:00 UTC")
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var (
+		reminder = newReminder()
+		status   = http.StatusNoContent
+		received *http.Request
+		body     []byte
+	)
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	var n = NewWebhookNotifier(server.Client(), "secret")
+	assert.Equal(t, "webhook", n.Channel())
+
+	t.Run("no webhook URL", func(t *testing.T) {
+		assert.ErrorIs(t, n.Notify(context.Background(), reminder), ErrNoRecipient)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		reminder.WebhookURL = server.URL + "/hook"
+		require.NoError(t, n.Notify(context.Background(), reminder))
+		assert.Equal(t, http.MethodPost, received.Method)
+		assert.Equal(t, "/hook", received.URL.Path)
+		assert.Equal(t, "key", received.Header.Get("Idempotency-Key"))
+		var mac = hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), received.Header.Get("X-Noda-Signature"))
+		var payload map[string]any
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "task.reminder", payload["event"])
+		assert.Equal(t, reminder.TaskUUID.String(), payload["task_uuid"])
+	})
+
+	t.Run("the receiver failed", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		assert.ErrorContains(t, n.Notify(context.Background(), reminder), "status 503")
+	})
+
+	t.Run("not an HTTP URL", func(t *testing.T) {
+		reminder.WebhookURL = "file:///etc/passwd"
+		assert.Error(t, n.Notify(context.Background(), reminder))
+	})
+}
+
+func TestWebhookNotifier_NotifyNonPublicAddress(t *testing.T) {
+	var called bool
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+	var reminder = newReminder()
+	reminder.WebhookURL = server.URL + "/hook"
+
+	t.Run("refused when connecting", func(t *testing.T) {
+		var n = NewWebhookNotifier(nil, "")
+		assert.ErrorIs(t, n.Notify(context.Background(), reminder), ErrNonPublicAddress)
+		assert.False(t, called)
+	})
+
+	t.Run("refused once resolved", func(t *testing.T) {
+		/* What a host resolving to the loopback would be dialed to.  */
+		var dial = newWebhookClient().Transport.(*http.Transport).DialContext
+		conn, err := dial(context.Background(), "tcp", server.Listener.Addr().String())
+		assert.ErrorIs(t, err, ErrNonPublicAddress)
+		assert.Nil(t, conn)
+		assert.False(t, called)
+	})
+
+	t.Run("redirections are not followed", func(t *testing.T) {
+		var client = newWebhookClient()
+		assert.ErrorIs(t, client.CheckRedirect(nil, nil), http.ErrUseLastResponse)
+	})
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	assert.NoError(t, CheckWebhookURL("https://example.com/hooks/noda"))
+	assert.NoError(t, CheckWebhookURL("http://93.184.216.34:8080"))
+	for _, rawURL := range []string{"", "example.com", "ftp://example.com", "https://", ":"} {
+		assert.Error(t, CheckWebhookURL(rawURL), rawURL)
+	}
+	for _, rawURL := range []string{
+		"http://localhost:8080",
+		"http://api.localhost",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.7",
+		"http://192.168.1.1",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]:8080",
+		"http://[fd00::1]",
+		"http://0.0.0.0",
+		"http://100.64.0.1",
+	} {
+		assert.ErrorIs(t, CheckWebhookURL(rawURL), ErrNonPublicAddress, rawURL)
+	}
+}
+
+type inbox struct {
+	ownerID, taskID, title, message string
+	remindAt                        time.Time
+}
+
+func (i *inbox) Save(ownerID, taskID, title, message string, remindAt time.Time) (string, error) {
+	i.ownerID, i.taskID, i.title, i.message, i.remindAt = ownerID, taskID, title, message, remindAt
+	return uuid.NewString(), nil
+}
+
+func TestInboxNotifier_Notify(t *testing.T) {
+	var (
+		i        = new(inbox)
+		n        = NewInboxNotifier(i)
+		reminder = newReminder()
+	)
+	assert.Equal(t, "inbox", n.Channel())
+	require.NoError(t, n.Notify(context.Background(), reminder))
+	assert.Equal(t, reminder.OwnerUUID.String(), i.ownerID)
+	assert.Equal(t, reminder.TaskUUID.String(), i.taskID)
+	assert.Equal(t, reminder.Title, i.title)
+	assert.Equal(t, "Before noon. Due on Tue, 02 Jan 2024 09:00:00 UTC.", i.message)
+	assert.Equal(t, reminder.RemindAt, i.remindAt)
+}
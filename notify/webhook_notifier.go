@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type webhookNotifier struct {
+	client *http.Client
+	secret []byte
+}
+
+// NewWebhookNotifier returns a Notifier that posts the reminders as JSON to the
+// webhook URL of their owners. The key of the reminder is sent in the
+// "Idempotency-Key" header and, if secret is not empty, the HMAC-SHA256 of the
+// body in the "X-Noda-Signature" header as "sha256=<hex>". A nil client is
+// replaced with one that gives up after 10 seconds, connects to public
+// addresses only and does not follow redirects.
+func NewWebhookNotifier(client *http.Client, secret string) Notifier {
+	if nil == client {
+		client = newWebhookClient()
+	}
+	return &webhookNotifier{client: client, secret: []byte(secret)}
+}
+
+// ErrNonPublicAddress is returned when a webhook URL points to, or its host
+// resolves to, an address that is not reachable from the Internet, such as
+// a loopback or a private one: webhooks must not reach into the network of
+// the server.
+var ErrNonPublicAddress = errors.New("webhook URL does not point to a public address")
+
+// newWebhookClient returns a client that checks every address it connects to
+// once resolved, so that a host resolving to a public address when the URL
+// is set cannot later resolve to a private one. It goes through no proxy,
+// which would connect on its behalf.
+func newWebhookClient() *http.Client {
+	var dialer = &net.Dialer{
+		Timeout:   5 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if nil != err {
+				return err
+			}
+			if ip := net.ParseIP(host); nil == ip || !isPublic(ip) {
+				return ErrNonPublicAddress
+			}
+			return nil
+		},
+	}
+	var transport = http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport: transport,
+		Timeout:   10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			/* The redirection is answered as a failure of the webhook.  */
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// nonPublicNetworks are the special-purpose networks that net.IP has no
+// method for.
+var nonPublicNetworks = func() (networks []*net.IPNet) {
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // "This network"
+		"100.64.0.0/10", // Shared address space
+		"192.0.0.0/24",  // IETF protocol assignments
+		"198.18.0.0/15", // Benchmarking
+		"240.0.0.0/4",   // Reserved
+		"64:ff9b::/96",  // IPv4/IPv6 translation
+	} {
+		_, network, _ := net.ParseCIDR(cidr)
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+func isPublic(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *webhookNotifier) Channel() string {
+	return "webhook"
+}
+
+type webhookPayload struct {
+	Event    string     `json:"event"`
+	Key      string     `json:"key"`
+	TaskUUID uuid.UUID  `json:"task_uuid"`
+	Title    string     `json:"title"`
+	Headline string     `json:"headline"`
+	DueDate  *time.Time `json:"due_date"`
+	RemindAt time.Time  `json:"remind_at"`
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, reminder *Reminder) error {
+	if "" == reminder.WebhookURL {
+		return ErrNoRecipient
+	}
+	if err := checkWebhookForm(reminder.WebhookURL); nil != err {
+		return err
+	}
+	body, err := json.Marshal(&webhookPayload{
+		Event:    "task.reminder",
+		Key:      reminder.Key,
+		TaskUUID: reminder.TaskUUID,
+		Title:    reminder.Title,
+		Headline: reminder.Headline,
+		DueDate:  reminder.DueDate,
+		RemindAt: reminder.RemindAt,
+	})
+	if nil != err {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, reminder.WebhookURL, bytes.NewReader(body))
+	if nil != err {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Idempotency-Key", reminder.Key)
+	if 0 < len(n.secret) {
+		var mac = hmac.New(sha256.New, n.secret)
+		mac.Write(body)
+		request.Header.Set("X-Noda-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	response, err := n.client.Do(request)
+	if nil != err {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<16))
+	if 200 > response.StatusCode || 299 < response.StatusCode {
+		return fmt.Errorf("webhook answered with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// CheckWebhookURL makes sure rawURL is an absolute HTTP or HTTPS URL whose
+// host is not an address, nor a name, known not to be public. The addresses
+// the other names resolve to are checked when connecting to them.
+func CheckWebhookURL(rawURL string) error {
+	if err := checkWebhookForm(rawURL); nil != err {
+		return err
+	}
+	u, _ := url.Parse(rawURL)
+	var host = strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if ip := net.ParseIP(host); (nil != ip && !isPublic(ip)) ||
+		"localhost" == host || strings.HasSuffix(host, ".localhost") {
+		return ErrNonPublicAddress
+	}
+	return nil
+}
+
+// checkWebhookForm makes sure rawURL is an absolute HTTP or HTTPS URL.
+func checkWebhookForm(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if nil != err {
+		return err
+	}
+	if ("http" != u.Scheme && "https" != u.Scheme) || "" == u.Hostname() {
+		return fmt.Errorf("webhook URL must be an absolute HTTP or HTTPS URL: %q", rawURL)
+	}
+	return nil
+}
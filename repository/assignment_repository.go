@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+type AssignmentRepository interface {
+	Assign(ownerID, listID, taskID, assigneeID, assignerID string) (ok bool, err error)
+	Unassign(ownerID, listID, taskID, assigneeID string) (ok bool, err error)
+	Fetch(ownerID, listID, taskID string) (assignees []*model.Assignee, err error)
+	FetchAssigned(userID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
+}
+
+type assignmentRepository struct {
+	db *sql.DB
+}
+
+func NewAssignmentRepository(db *sql.DB) AssignmentRepository {
+	return &assignmentRepository{db: db}
+}
+
+// Assign assigns the task to the user. It is not ok if the task was already
+// assigned to it.
+func (r *assignmentRepository) Assign(ownerID, listID, taskID, assigneeID, assignerID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "assignments"."assign" ($1, $2, $3, $4, $5);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, assigneeID, assignerID).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return false, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// Unassign takes the task away from the user. It is not ok if the task was
+// not assigned to it.
+func (r *assignmentRepository) Unassign(ownerID, listID, taskID, assigneeID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "assignments"."unassign" ($1, $2, $3, $4);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, assigneeID).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return false, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// Fetch retrieves the assignees of a task, in the order they were assigned.
+func (r *assignmentRepository) Fetch(ownerID, listID, taskID string) (assignees []*model.Assignee, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "assignments"."fetch" ($1, $2, $3);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, listID, taskID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	assignees = make([]*model.Assignee, 0)
+	for rows.Next() {
+		var assignee = new(model.Assignee)
+		err = rows.Scan(
+			&assignee.TaskUUID,
+			&assignee.UserUUID,
+			&assignee.FirstName,
+			&assignee.LastName,
+			&assignee.Email,
+			&assignee.AssignedByUUID,
+			&assignee.AssignedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		assignees = append(assignees, assignee)
+	}
+	return assignees, nil
+}
+
+// FetchAssigned retrieves the tasks assigned to the user across all the lists
+// it can see, its own and the shared ones.
+func (r *assignmentRepository) FetchAssigned(userID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "assignments"."fetch_assigned" ($1, $2, $3, $4, $5);`
+	rows, err := r.db.QueryContext(ctx, query, userID, page, rpp, needle, sortExpr)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	tasks = make([]*model.Task, 0)
+	for rows.Next() {
+		var task = new(model.Task)
+		err = rows.Scan(
+			&task.UUID,
+			&task.OwnerUUID,
+			&task.ListUUID,
+			&task.ParentUUID,
+			&task.PositionInList,
+			&task.Title,
+			&task.Headline,
+			&task.Description,
+			&task.Priority,
+			&task.Status,
+			&task.IsPinned,
+			&task.DueDate,
+			&task.RemindAt,
+			&task.Recurrence,
+			&task.CompletedAt,
+			&task.CreatedAt,
+			&task.UpdatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
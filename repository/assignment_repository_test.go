@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAssignmentRepository_Assign(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewAssignmentRepository(db)
+		query = regexp.QuoteMeta(`SELECT "assignments"."assign" ($1, $2, $3, $4, $5);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, memberID, userID).
+			WillReturnRows(sqlmock.NewRows([]string{"assign"}).AddRow(true))
+		res, err = r.Assign(userID, listID, taskID, memberID, userID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Assign(userID, listID, taskID, memberID, userID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.False(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Assign(userID, listID, taskID, memberID, userID)
+		assert.Error(t, err)
+		assert.False(t, res)
+	})
+}
+
+func TestAssignmentRepository_Unassign(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewAssignmentRepository(db)
+		query = regexp.QuoteMeta(`SELECT "assignments"."unassign" ($1, $2, $3, $4);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, memberID).
+			WillReturnRows(sqlmock.NewRows([]string{"unassign"}).AddRow(true))
+		res, err = r.Unassign(userID, listID, taskID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		res, err = r.Unassign(userID, listID, taskID, memberID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestAssignmentRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewAssignmentRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "assignments"."fetch" ($1, $2, $3);`)
+		columns = []string{"task_uuid", "user_uuid", "first_name", "last_name", "email", "assigned_by_uuid", "assigned_at"}
+		now     = time.Now()
+		res     []*model.Assignee
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(taskID, memberID, "Jane", "Doe", "jane@noda.com", userID, now))
+		res, err = r.Fetch(userID, listID, taskID)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Assignee{{
+			TaskUUID:       uuid.MustParse(taskID),
+			UserUUID:       uuid.MustParse(memberID),
+			FirstName:      "Jane",
+			LastName:       "Doe",
+			Email:          "jane@noda.com",
+			AssignedByUUID: uuid.MustParse(userID),
+			AssignedAt:     now,
+		}}, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Fetch(userID, listID, taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestAssignmentRepository_FetchAssigned(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewAssignmentRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "assignments"."fetch_assigned" ($1, $2, $3, $4, $5);`)
+		task  = &model.Task{
+			UUID:      uuid.MustParse(taskID),
+			OwnerUUID: uuid.MustParse(userID),
+			ListUUID:  uuid.MustParse(listID),
+			Title:     "task title",
+			Priority:  types.TaskPriorityHigh,
+			Status:    types.TaskStatusIncomplete,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		res []*model.Task
+		err error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, int64(1), int64(10), "", "").
+			WillReturnRows(sqlmock.
+				NewRows(taskTableColumns).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+		res, err = r.FetchAssigned(memberID, 1, 10, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Task{task}, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + memberID + "\""})
+		res, err = r.FetchAssigned(memberID, 1, 10, "", "")
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"time"
+)
+
+type AttachmentRepository interface {
+	Save(ownerID, taskID string, creation *transfer.AttachmentCreation) (insertedID string, err error)
+	FetchByID(ownerID, taskID, attachmentID string) (attachment *model.Attachment, err error)
+	Fetch(ownerID, taskID string) (attachments []*model.Attachment, err error)
+	Delete(ownerID, taskID, attachmentID string) error
+}
+
+type attachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Save(ownerID, taskID string, creation *transfer.AttachmentCreation) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "attachments"."make" ($1, $2, $3, $4, $5, $6);`
+	var row = r.db.QueryRowContext(ctx, query,
+		ownerID, taskID, creation.FileName, creation.ContentType, creation.Size, creation.BlobKey)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return "", failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+func (r *attachmentRepository) FetchByID(ownerID, taskID, attachmentID string) (attachment *model.Attachment, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "attachments"."fetch_by_uuid" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, attachmentID)
+	attachment = new(model.Attachment)
+	err = row.Scan(
+		&attachment.UUID,
+		&attachment.OwnerUUID,
+		&attachment.TaskUUID,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.BlobKey,
+		&attachment.CreatedAt,
+		&attachment.UpdatedAt)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, failure.ErrAttachmentNotFound
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			case isNonexistentAttachmentError(pqerr):
+				return nil, failure.ErrAttachmentNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (r *attachmentRepository) Fetch(ownerID, taskID string) (attachments []*model.Attachment, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "attachments"."fetch" ($1, $2);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, taskID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	attachments = make([]*model.Attachment, 0)
+	for rows.Next() {
+		var attachment = new(model.Attachment)
+		err = rows.Scan(
+			&attachment.UUID,
+			&attachment.OwnerUUID,
+			&attachment.TaskUUID,
+			&attachment.FileName,
+			&attachment.ContentType,
+			&attachment.Size,
+			&attachment.BlobKey,
+			&attachment.CreatedAt,
+			&attachment.UpdatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepository) Delete(ownerID, taskID, attachmentID string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "attachments"."delete" ($1, $2, $3);`
+	_, err := r.db.ExecContext(ctx, query, ownerID, taskID, attachmentID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return failure.ErrTaskNotFound
+			case isNonexistentAttachmentError(pqerr):
+				return failure.ErrAttachmentNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const attachmentID = "0b6c5d5e-7a7b-4a0f-9b5e-3d1c2b4a6f8e"
+
+var attachmentTableColumns = []string{
+	"attachment_uuid",
+	"owner_uuid",
+	"task_uuid",
+	"file_name",
+	"content_type",
+	"size",
+	"blob_key",
+	"created_at",
+	"updated_at"}
+
+func TestAttachmentRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewAttachmentRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "attachments"."make" ($1, $2, $3, $4, $5, $6);`)
+		creation = &transfer.AttachmentCreation{
+			FileName:    "report.pdf",
+			ContentType: "application/pdf",
+			Size:        1024,
+			BlobKey:     userID + "/" + taskID + "/" + attachmentID,
+		}
+		res string
+		err error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, creation.FileName, creation.ContentType, creation.Size, creation.BlobKey).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"make"}).
+				AddRow(attachmentID))
+		res, err = r.Save(userID, taskID, creation)
+		assert.Equal(t, attachmentID, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent task error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Save(userID, taskID, creation)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(userID, taskID, creation)
+		assert.Error(t, err)
+		assert.Equal(t, "", res)
+	})
+}
+
+func TestAttachmentRepository_FetchByID(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r          = NewAttachmentRepository(db)
+		query      = regexp.QuoteMeta(`SELECT * FROM "attachments"."fetch_by_uuid" ($1, $2, $3);`)
+		attachment = &model.Attachment{
+			UUID:        uuid.MustParse(attachmentID),
+			OwnerUUID:   uuid.MustParse(userID),
+			TaskUUID:    uuid.MustParse(taskID),
+			FileName:    "report.pdf",
+			ContentType: "application/pdf",
+			Size:        1024,
+			BlobKey:     "key",
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+		res *model.Attachment
+		err error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, attachmentID).
+			WillReturnRows(sqlmock.
+				NewRows(attachmentTableColumns).
+				AddRow(
+					attachment.UUID,
+					attachment.OwnerUUID,
+					attachment.TaskUUID,
+					attachment.FileName,
+					attachment.ContentType,
+					attachment.Size,
+					attachment.BlobKey,
+					attachment.CreatedAt,
+					attachment.UpdatedAt))
+		res, err = r.FetchByID(userID, taskID, attachmentID)
+		assert.Equal(t, attachment, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, attachmentID).
+			WillReturnRows(sqlmock.NewRows(attachmentTableColumns))
+		res, err = r.FetchByID(userID, taskID, attachmentID)
+		assert.ErrorIs(t, err, failure.ErrAttachmentNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got a nonexistent attachment error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent attachment with UUID \"" + attachmentID + "\""})
+		res, err = r.FetchByID(userID, taskID, attachmentID)
+		assert.ErrorIs(t, err, failure.ErrAttachmentNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestAttachmentRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewAttachmentRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "attachments"."fetch" ($1, $2);`)
+		res   []*model.Attachment
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var now = time.Now()
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID).
+			WillReturnRows(sqlmock.
+				NewRows(attachmentTableColumns).
+				AddRow(attachmentID, userID, taskID, "a.txt", "text/plain", 1, "a", now, now).
+				AddRow(attachmentID, userID, taskID, "b.txt", "text/plain", 2, "b", now, now))
+		res, err = r.Fetch(userID, taskID)
+		assert.Len(t, res, 2)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent task error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Fetch(userID, taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestAttachmentRepository_Delete(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewAttachmentRepository(db)
+		query = regexp.QuoteMeta(`SELECT "attachments"."delete" ($1, $2, $3);`)
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(userID, taskID, attachmentID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = r.Delete(userID, taskID, attachmentID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent attachment error", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent attachment with UUID \"" + attachmentID + "\""})
+		err = r.Delete(userID, taskID, attachmentID)
+		assert.ErrorIs(t, err, failure.ErrAttachmentNotFound)
+	})
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"time"
+)
+
+// AuditRepository keeps the audit log. The log is append-only: the entries
+// are never changed, and they are only removed once they are past retention.
+type AuditRepository interface {
+	Save(entry *model.AuditEntry) (insertedID string, err error)
+	Fetch(filter *types.AuditFilter, page, rpp int64) (entries []*model.AuditEntry, err error)
+	Purge(before time.Time) (purged int64, err error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Save(entry *model.AuditEntry) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "audit"."save" ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+	var row = r.db.QueryRowContext(ctx, query,
+		nullableUUID(entry.ActorUUID),
+		entry.Action,
+		entry.Entity,
+		nullableUUID(entry.EntityUUID),
+		nullableJSON(entry.Before),
+		nullableJSON(entry.After),
+		entry.IP,
+		entry.UserAgent,
+		entry.OccurredAt)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+// Fetch retrieves the entries that match the filter, the most recent first.
+func (r *auditRepository) Fetch(filter *types.AuditFilter, page, rpp int64) (entries []*model.AuditEntry, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "audit"."fetch" ($1, $2, $3, $4, $5, $6, $7);`
+	var actorID, entityID string
+	if uuid.Nil != filter.ActorUUID {
+		actorID = filter.ActorUUID.String()
+	}
+	if uuid.Nil != filter.EntityUUID {
+		entityID = filter.EntityUUID.String()
+	}
+	rows, err := r.db.QueryContext(ctx, query, page, rpp, actorID, filter.Entity, entityID, filter.From, filter.To)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	entries = make([]*model.AuditEntry, 0)
+	for rows.Next() {
+		var entry = new(model.AuditEntry)
+		var before, after []byte
+		err = rows.Scan(
+			&entry.UUID,
+			&entry.ActorUUID,
+			&entry.Action,
+			&entry.Entity,
+			&entry.EntityUUID,
+			&before,
+			&after,
+			&entry.IP,
+			&entry.UserAgent,
+			&entry.OccurredAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		entry.Before, entry.After = before, after
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Purge removes the entries that occurred before the given time.
+func (r *auditRepository) Purge(before time.Time) (purged int64, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "audit"."purge" ($1);`
+	err = r.db.QueryRowContext(ctx, query, before).Scan(&purged)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return 0, err
+	}
+	return purged, nil
+}
+
+// nullableUUID stores a nil UUID as NULL.
+func nullableUUID(id *uuid.UUID) uuid.NullUUID {
+	if nil == id {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+// nullableJSON stores an empty JSON document as NULL, and any other as text
+// so that it can be cast to jsonb.
+func nullableJSON(raw json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(raw), Valid: 0 < len(raw)}
+}
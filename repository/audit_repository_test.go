@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAuditRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewAuditRepository(db)
+		query = regexp.QuoteMeta(`SELECT "audit"."save" ($1, $2, $3, $4, $5, $6, $7, $8, $9);`)
+		actor = uuid.MustParse(userID)
+		task  = uuid.MustParse(taskID)
+		entry = &model.AuditEntry{
+			ActorUUID:  &actor,
+			Action:     "updated",
+			Entity:     "task",
+			EntityUUID: &task,
+			Before:     json.RawMessage(`{"title":"old"}`),
+			After:      json.RawMessage(`{"title":"new"}`),
+			IP:         "127.0.0.1",
+			UserAgent:  "curl/8.0",
+			OccurredAt: time.Now(),
+		}
+		inserted = uuid.NewString()
+		res      string
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(
+				uuid.NullUUID{UUID: actor, Valid: true},
+				entry.Action,
+				entry.Entity,
+				uuid.NullUUID{UUID: task, Valid: true},
+				sql.NullString{String: `{"title":"old"}`, Valid: true},
+				sql.NullString{String: `{"title":"new"}`, Valid: true},
+				"127.0.0.1",
+				"curl/8.0",
+				entry.OccurredAt).
+			WillReturnRows(sqlmock.NewRows([]string{"save"}).AddRow(inserted))
+		res, err = r.Save(entry)
+		assert.NoError(t, err)
+		assert.Equal(t, inserted, res)
+	})
+
+	t.Run("without actor nor diff", func(t *testing.T) {
+		var anonymous = &model.AuditEntry{Action: "rolled_over", Entity: "user", OccurredAt: time.Now()}
+		mock.
+			ExpectQuery(query).
+			WithArgs(uuid.NullUUID{}, anonymous.Action, anonymous.Entity, uuid.NullUUID{}, sql.NullString{}, sql.NullString{},
+				"", "", anonymous.OccurredAt).
+			WillReturnRows(sqlmock.NewRows([]string{"save"}).AddRow(inserted))
+		res, err = r.Save(anonymous)
+		assert.NoError(t, err)
+		assert.Equal(t, inserted, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(entry)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestAuditRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewAuditRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "audit"."fetch" ($1, $2, $3, $4, $5, $6, $7);`)
+		columns = []string{"audit_uuid", "actor_uuid", "action", "entity", "entity_uuid", "before", "after", "ip", "user_agent", "occurred_at"}
+		now     = time.Now()
+		from    = now.Add(-time.Hour)
+		entryID = uuid.NewString()
+		res     []*model.AuditEntry
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var filter = &types.AuditFilter{ActorUUID: uuid.MustParse(userID), Entity: "user", From: &from}
+		mock.
+			ExpectQuery(query).
+			WithArgs(int64(1), int64(10), userID, "user", "", &from, nil).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(entryID, userID, "blocked", "user", memberID, nil, []byte(`{"reason":"spam"}`), "127.0.0.1", "curl/8.0", now))
+		res, err = r.Fetch(filter, 1, 10)
+		assert.NoError(t, err)
+		var actor, member = uuid.MustParse(userID), uuid.MustParse(memberID)
+		assert.Equal(t, []*model.AuditEntry{{
+			UUID:       uuid.MustParse(entryID),
+			ActorUUID:  &actor,
+			Action:     "blocked",
+			Entity:     "user",
+			EntityUUID: &member,
+			After:      json.RawMessage(`{"reason":"spam"}`),
+			IP:         "127.0.0.1",
+			UserAgent:  "curl/8.0",
+			OccurredAt: now,
+		}}, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Fetch(&types.AuditFilter{}, 1, 10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestAuditRepository_Purge(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r      = NewAuditRepository(db)
+		query  = regexp.QuoteMeta(`SELECT "audit"."purge" ($1);`)
+		before = time.Now()
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(before).
+			WillReturnRows(sqlmock.NewRows([]string{"purge"}).AddRow(3))
+		purged, err := r.Purge(before)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		purged, err := r.Purge(before)
+		assert.Error(t, err)
+		assert.Zero(t, purged)
+	})
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+// CalDAVRepository keeps the names and the UIDs CalDAV clients gave to the
+// tasks they made. The other tasks are served under their own UUID.
+type CalDAVRepository interface {
+	SaveHref(ownerID, listID, taskID, name, uid string) error
+	FetchHrefs(ownerID, listID string) (hrefs []*model.CalendarHref, err error)
+	FetchHrefByName(ownerID, listID, name string) (href *model.CalendarHref, err error)
+	FetchHrefByUID(ownerID, listID, uid string) (href *model.CalendarHref, err error)
+	FetchHrefOfTask(ownerID, listID, taskID string) (href *model.CalendarHref, err error)
+	FetchLastChange(ownerID, listID string) (tasks int64, updatedAt *time.Time, err error)
+}
+
+type calDAVRepository struct {
+	db *sql.DB
+}
+
+func NewCalDAVRepository(db *sql.DB) CalDAVRepository {
+	return &calDAVRepository{db: db}
+}
+
+func (r *calDAVRepository) SaveHref(ownerID, listID, taskID, name, uid string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "caldav"."save_href" ($1, $2, $3, $4, $5);`
+	_, err := r.db.ExecContext(ctx, query, ownerID, listID, taskID, name, uid)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// FetchHrefs retrieves the names and the UIDs of the tasks of the list that
+// were made by CalDAV clients.
+func (r *calDAVRepository) FetchHrefs(ownerID, listID string) (hrefs []*model.CalendarHref, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "caldav"."fetch_hrefs" ($1, $2);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, listID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	hrefs = make([]*model.CalendarHref, 0)
+	for rows.Next() {
+		var href = new(model.CalendarHref)
+		err = rows.Scan(&href.TaskUUID, &href.Name, &href.UID)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		hrefs = append(hrefs, href)
+	}
+	return hrefs, nil
+}
+
+// FetchHrefByName retrieves the href of the task of the list that a client
+// made under the given name.
+func (r *calDAVRepository) FetchHrefByName(ownerID, listID, name string) (href *model.CalendarHref, err error) {
+	return r.fetchHref(`SELECT * FROM "caldav"."fetch_href_by_name" ($1, $2, $3);`, ownerID, listID, name)
+}
+
+// FetchHrefByUID retrieves the href of the task of the list that a client
+// made with the given UID.
+func (r *calDAVRepository) FetchHrefByUID(ownerID, listID, uid string) (href *model.CalendarHref, err error) {
+	return r.fetchHref(`SELECT * FROM "caldav"."fetch_href_by_uid" ($1, $2, $3);`, ownerID, listID, uid)
+}
+
+// FetchHrefOfTask retrieves the href of the task, if a client made it.
+func (r *calDAVRepository) FetchHrefOfTask(ownerID, listID, taskID string) (href *model.CalendarHref, err error) {
+	return r.fetchHref(`SELECT * FROM "caldav"."fetch_href_of_task" ($1, $2, $3);`, ownerID, listID, taskID)
+}
+
+// fetchHref retrieves the one href the query finds, or fails with
+// failure.ErrCalendarObjectNotFound.
+func (r *calDAVRepository) fetchHref(query string, ownerID, listID, key string) (href *model.CalendarHref, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	href = new(model.CalendarHref)
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, key).Scan(&href.TaskUUID, &href.Name, &href.UID)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, failure.ErrCalendarObjectNotFound
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	return href, nil
+}
+
+// FetchLastChange counts the tasks of the list, outside the trash, and
+// retrieves the last time any task of the list changed, including by being
+// moved to the trash. updatedAt is nil if the list never had any task.
+func (r *calDAVRepository) FetchLastChange(ownerID, listID string) (tasks int64, updatedAt *time.Time, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "caldav"."fetch_last_change" ($1, $2);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID).Scan(&tasks, &updatedAt)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return 0, nil, failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return 0, nil, failure.ErrListNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return 0, nil, err
+	}
+	return tasks, updatedAt, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCalDAVRepository_SaveHref(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCalDAVRepository(db)
+		query = regexp.QuoteMeta(`SELECT "caldav"."save_href" ($1, $2, $3, $4, $5);`)
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(userID, listID, taskID, "0C5D1F8E.ics", "0C5D1F8E").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = r.SaveHref(userID, listID, taskID, "0C5D1F8E.ics", "0C5D1F8E")
+		assert.NoError(t, err)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		err = r.SaveHref(userID, listID, taskID, "0C5D1F8E.ics", "0C5D1F8E")
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+	})
+}
+
+func TestCalDAVRepository_FetchHrefs(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCalDAVRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "caldav"."fetch_hrefs" ($1, $2);`)
+		href  = &model.CalendarHref{TaskUUID: uuid.MustParse(taskID), Name: "0C5D1F8E.ics", UID: "0C5D1F8E"}
+		res   []*model.CalendarHref
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"task_uuid", "name", "uid"}).
+				AddRow(href.TaskUUID, href.Name, href.UID))
+		res, err = r.FetchHrefs(userID, listID)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.CalendarHref{href}, res)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		res, err = r.FetchHrefs(userID, listID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestCalDAVRepository_FetchHrefByName(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCalDAVRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "caldav"."fetch_href_by_name" ($1, $2, $3);`)
+		href  = &model.CalendarHref{TaskUUID: uuid.MustParse(taskID), Name: "0C5D1F8E.ics", UID: "0C5D1F8E"}
+		res   *model.CalendarHref
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, href.Name).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"task_uuid", "name", "uid"}).
+				AddRow(href.TaskUUID, href.Name, href.UID))
+		res, err = r.FetchHrefByName(userID, listID, href.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, href, res)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, "missing.ics").
+			WillReturnRows(sqlmock.NewRows([]string{"task_uuid", "name", "uid"}))
+		res, err = r.FetchHrefByName(userID, listID, "missing.ics")
+		assert.ErrorIs(t, err, failure.ErrCalendarObjectNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		res, err = r.FetchHrefByName(userID, listID, href.Name)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestCalDAVRepository_FetchLastChange(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewCalDAVRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "caldav"."fetch_last_change" ($1, $2);`)
+		updated = time.Date(2024, 12, 2, 10, 15, 0, 0, time.UTC)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID).
+			WillReturnRows(sqlmock.NewRows([]string{"tasks", "updated_at"}).AddRow(3, updated))
+		tasks, updatedAt, err := r.FetchLastChange(userID, listID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), tasks)
+		assert.Equal(t, &updated, updatedAt)
+	})
+
+	t.Run("no task", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID).
+			WillReturnRows(sqlmock.NewRows([]string{"tasks", "updated_at"}).AddRow(0, nil))
+		tasks, updatedAt, err := r.FetchLastChange(userID, listID)
+		assert.NoError(t, err)
+		assert.Zero(t, tasks)
+		assert.Nil(t, updatedAt)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		_, _, err := r.FetchLastChange(userID, listID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+	})
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+// CalendarRepository keeps the tokens of the calendar feeds, one per user, and
+// retrieves the tasks the feeds publish. Only the hash of a token is stored.
+type CalendarRepository interface {
+	SaveFeedToken(userID, hash string) error
+	FetchFeedOwner(hash string) (userID string, err error)
+	DeleteFeedToken(userID string) error
+	FetchListName(userID, listID string) (name string, err error)
+	FetchTasks(userID, listID string) (tasks []*model.Task, err error)
+}
+
+type calendarRepository struct {
+	db *sql.DB
+}
+
+func NewCalendarRepository(db *sql.DB) CalendarRepository {
+	return &calendarRepository{db: db}
+}
+
+// SaveFeedToken stores the feed token of the user, in place of the previous
+// one if any.
+func (r *calendarRepository) SaveFeedToken(userID, hash string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "calendar"."set_feed_token" ($1, $2);`
+	_, err := r.db.ExecContext(ctx, query, userID, hash)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// FetchFeedOwner retrieves the user whose feed token has the given hash,
+// whether or not the user is still active.
+func (r *calendarRepository) FetchFeedOwner(hash string) (userID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "calendar"."fetch_feed_owner" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, hash)
+	err = row.Scan(&userID)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", failure.ErrInvalidFeedToken
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return userID, nil
+}
+
+func (r *calendarRepository) DeleteFeedToken(userID string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "calendar"."delete_feed_token" ($1);`
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *calendarRepository) FetchListName(userID, listID string) (name string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "calendar"."fetch_list_name" ($1, $2);`
+	var row = r.db.QueryRowContext(ctx, query, userID, listID)
+	err = row.Scan(&name)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return "", failure.ErrListNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return name, nil
+}
+
+// FetchTasks retrieves the tasks the feed of the user publishes, that is, the
+// tasks with a due date or a reminder that are not in the trash, either of the
+// given list or, if listID is empty, of every list the user can see.
+func (r *calendarRepository) FetchTasks(userID, listID string) (tasks []*model.Task, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "calendar"."fetch_tasks" ($1, $2);`
+	rows, err := r.db.QueryContext(ctx, query, userID, listID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	tasks = make([]*model.Task, 0)
+	for rows.Next() {
+		var task = new(model.Task)
+		err = rows.Scan(
+			&task.UUID,
+			&task.OwnerUUID,
+			&task.ListUUID,
+			&task.ParentUUID,
+			&task.PositionInList,
+			&task.Title,
+			&task.Headline,
+			&task.Description,
+			&task.Priority,
+			&task.Status,
+			&task.IsPinned,
+			&task.DueDate,
+			&task.RemindAt,
+			&task.Recurrence,
+			&task.CompletedAt,
+			&task.CreatedAt,
+			&task.UpdatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
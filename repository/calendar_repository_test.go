@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCalendarRepository_SaveFeedToken(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCalendarRepository(db)
+		query = regexp.QuoteMeta(`SELECT "calendar"."set_feed_token" ($1, $2);`)
+		hash  = "4d967a30111bf29f0eba01c448b375c1629b2fed01cdfcc3aed91f1b57d5dd5e"
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(userID, hash).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = r.SaveFeedToken(userID, hash)
+		assert.NoError(t, err)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		err = r.SaveFeedToken(userID, hash)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+	})
+}
+
+func TestCalendarRepository_FetchFeedOwner(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCalendarRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "calendar"."fetch_feed_owner" ($1);`)
+		hash  = "4d967a30111bf29f0eba01c448b375c1629b2fed01cdfcc3aed91f1b57d5dd5e"
+		res   string
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(hash).
+			WillReturnRows(sqlmock.NewRows([]string{"user_uuid"}).AddRow(userID))
+		res, err = r.FetchFeedOwner(hash)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, res)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchFeedOwner(hash)
+		assert.ErrorIs(t, err, failure.ErrInvalidFeedToken)
+		assert.Equal(t, "", res)
+	})
+}
+
+func TestCalendarRepository_FetchTasks(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCalendarRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "calendar"."fetch_tasks" ($1, $2);`)
+		due   = time.Now().Add(24 * time.Hour)
+		task  = &model.Task{
+			UUID:      uuid.MustParse(taskID),
+			OwnerUUID: uuid.MustParse(userID),
+			ListUUID:  uuid.MustParse(listID),
+			Title:     "Paint the fence",
+			Priority:  types.TaskPriorityHigh,
+			Status:    types.TaskStatusIncomplete,
+			DueDate:   &due,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		res []*model.Task
+		err error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, "").
+			WillReturnRows(sqlmock.
+				NewRows(taskTableColumns).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+		res, err = r.FetchTasks(userID, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Task{task}, res)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		res, err = r.FetchTasks(userID, listID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+type CommentRepository interface {
+	Save(ownerID, listID, taskID, authorID, parentID, content string, mentions []string) (insertedID string, err error)
+	FetchByID(ownerID, listID, taskID, commentID string) (comment *model.Comment, err error)
+	Fetch(ownerID, listID, taskID string, page, rpp int64) (comments []*model.Comment, err error)
+	Update(ownerID, listID, taskID, commentID, content string, mentions []string) (ok bool, err error)
+	Remove(ownerID, listID, taskID, commentID string) (ok bool, err error)
+	FetchActivity(ownerID, listID, taskID string, page, rpp int64) (activity []*model.Activity, err error)
+}
+
+type commentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// Save saves a comment on a task. If parentID is not an empty string, the
+// comment is a reply to that comment, which must belong to the same task.
+func (r *commentRepository) Save(
+	ownerID, listID, taskID, authorID, parentID, content string,
+	mentions []string,
+) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "comments"."save" ($1, $2, $3, $4, $5, $6, $7);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, authorID, parentID, content, pq.Array(mentions)).
+		Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return "", failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return "", failure.ErrTaskNotFound
+			case isNonexistentCommentError(pqerr):
+				return "", failure.ErrCommentNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+func (r *commentRepository) FetchByID(ownerID, listID, taskID, commentID string) (comment *model.Comment, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "comments"."fetch_by_id" ($1, $2, $3, $4);`
+	comment = new(model.Comment)
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, commentID).
+		Scan(
+			&comment.UUID,
+			&comment.TaskUUID,
+			&comment.ParentUUID,
+			&comment.AuthorUUID,
+			&comment.Content,
+			pq.Array(&comment.Mentions),
+			&comment.CreatedAt,
+			&comment.EditedAt)
+	if nil != err {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, failure.ErrCommentNotFound
+		case errors.As(err, &pqerr):
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+// Fetch retrieves the comments of a task, oldest first. Replies come along
+// with the comments they answer, so the threads are built by the client.
+func (r *commentRepository) Fetch(ownerID, listID, taskID string, page, rpp int64) (comments []*model.Comment, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "comments"."fetch" ($1, $2, $3, $4, $5);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, listID, taskID, page, rpp)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	comments = make([]*model.Comment, 0)
+	for rows.Next() {
+		var comment = new(model.Comment)
+		err = rows.Scan(
+			&comment.UUID,
+			&comment.TaskUUID,
+			&comment.ParentUUID,
+			&comment.AuthorUUID,
+			&comment.Content,
+			pq.Array(&comment.Mentions),
+			&comment.CreatedAt,
+			&comment.EditedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, nil
+}
+
+// Update replaces the content and the mentions of a comment, and marks it as
+// edited. It is not ok if the content did not change.
+func (r *commentRepository) Update(ownerID, listID, taskID, commentID, content string, mentions []string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "comments"."update" ($1, $2, $3, $4, $5, $6);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, commentID, content, pq.Array(mentions)).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return false, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentCommentError(pqerr):
+				return false, failure.ErrCommentNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// Remove removes a comment along with all of its replies.
+func (r *commentRepository) Remove(ownerID, listID, taskID, commentID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "comments"."remove" ($1, $2, $3, $4);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, commentID).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return false, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentCommentError(pqerr):
+				return false, failure.ErrCommentNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// FetchActivity retrieves the comments of a task merged with the changes made
+// to it, as recorded by the database, latest first.
+func (r *commentRepository) FetchActivity(ownerID, listID, taskID string, page, rpp int64) (activity []*model.Activity, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "comments"."fetch_activity" ($1, $2, $3, $4, $5);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, listID, taskID, page, rpp)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	activity = make([]*model.Activity, 0)
+	for rows.Next() {
+		var entry = new(model.Activity)
+		err = rows.Scan(
+			&entry.Kind,
+			&entry.TaskUUID,
+			&entry.ActorUUID,
+			&entry.CommentUUID,
+			&entry.Content,
+			&entry.Previous,
+			&entry.Current,
+			&entry.OccurredAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		activity = append(activity, entry)
+	}
+	return activity, nil
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const commentID = "c0a2e4b6-8d1f-4c3e-9a5b-7d9f1b3d5e68"
+
+var commentTableColumns = []string{"comment_uuid", "task_uuid", "parent_uuid", "author_uuid", "content", "mentions", "created_at", "edited_at"}
+
+func TestCommentRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewCommentRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "comments"."save" ($1, $2, $3, $4, $5, $6, $7);`)
+		mentions = []string{memberID}
+		res      string
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, memberID, "", "Ping @jane@noda.com", pq.Array(mentions)).
+			WillReturnRows(sqlmock.NewRows([]string{"save"}).AddRow(commentID))
+		res, err = r.Save(userID, listID, taskID, memberID, "", "Ping @jane@noda.com", mentions)
+		assert.NoError(t, err)
+		assert.Equal(t, commentID, res)
+	})
+
+	t.Run("parent comment not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent comment with UUID \"" + commentID + "\""})
+		res, err = r.Save(userID, listID, taskID, memberID, commentID, "Agreed.", nil)
+		assert.ErrorIs(t, err, failure.ErrCommentNotFound)
+		assert.Empty(t, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Save(userID, listID, taskID, memberID, "", "Agreed.", nil)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Empty(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(userID, listID, taskID, memberID, "", "Agreed.", nil)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestCommentRepository_FetchByID(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCommentRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "comments"."fetch_by_id" ($1, $2, $3, $4);`)
+		now   = time.Now()
+		res   *model.Comment
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, commentID).
+			WillReturnRows(sqlmock.
+				NewRows(commentTableColumns).
+				AddRow(commentID, taskID, nil, memberID, "Agreed.", "{"+userID+"}", now, nil))
+		res, err = r.FetchByID(userID, listID, taskID, commentID)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Comment{
+			UUID:       uuid.MustParse(commentID),
+			TaskUUID:   uuid.MustParse(taskID),
+			AuthorUUID: uuid.MustParse(memberID),
+			Content:    "Agreed.",
+			Mentions:   []string{userID},
+			CreatedAt:  now,
+		}, res)
+	})
+
+	t.Run("comment not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchByID(userID, listID, taskID, commentID)
+		assert.ErrorIs(t, err, failure.ErrCommentNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		res, err = r.FetchByID(userID, listID, taskID, commentID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestCommentRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCommentRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "comments"."fetch" ($1, $2, $3, $4, $5);`)
+		now   = time.Now()
+		reply = "e1f3a5c7-9b2d-4e6f-8a0c-3b5d7f9a1c24"
+		res   []*model.Comment
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, int64(1), int64(10)).
+			WillReturnRows(sqlmock.
+				NewRows(commentTableColumns).
+				AddRow(commentID, taskID, nil, memberID, "Shall we?", "{}", now, nil).
+				AddRow(reply, taskID, commentID, userID, "Agreed.", "{}", now, now))
+		res, err = r.Fetch(userID, listID, taskID, 1, 10)
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		var parent = uuid.MustParse(commentID)
+		assert.Equal(t, &model.Comment{
+			UUID:       uuid.MustParse(reply),
+			TaskUUID:   uuid.MustParse(taskID),
+			ParentUUID: &parent,
+			AuthorUUID: uuid.MustParse(userID),
+			Content:    "Agreed.",
+			Mentions:   []string{},
+			CreatedAt:  now,
+			EditedAt:   &now,
+		}, res[1])
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Fetch(userID, listID, taskID, 1, 10)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestCommentRepository_Update(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCommentRepository(db)
+		query = regexp.QuoteMeta(`SELECT "comments"."update" ($1, $2, $3, $4, $5, $6);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, commentID, "Agreed!", pq.Array([]string(nil))).
+			WillReturnRows(sqlmock.NewRows([]string{"update"}).AddRow(true))
+		res, err = r.Update(userID, listID, taskID, commentID, "Agreed!", nil)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("comment not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent comment with UUID \"" + commentID + "\""})
+		res, err = r.Update(userID, listID, taskID, commentID, "Agreed!", nil)
+		assert.ErrorIs(t, err, failure.ErrCommentNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestCommentRepository_Remove(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewCommentRepository(db)
+		query = regexp.QuoteMeta(`SELECT "comments"."remove" ($1, $2, $3, $4);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, commentID).
+			WillReturnRows(sqlmock.NewRows([]string{"remove"}).AddRow(true))
+		res, err = r.Remove(userID, listID, taskID, commentID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("comment not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent comment with UUID \"" + commentID + "\""})
+		res, err = r.Remove(userID, listID, taskID, commentID)
+		assert.ErrorIs(t, err, failure.ErrCommentNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestCommentRepository_FetchActivity(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewCommentRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "comments"."fetch_activity" ($1, $2, $3, $4, $5);`)
+		columns = []string{"kind", "task_uuid", "actor_uuid", "comment_uuid", "content", "previous", "current", "occurred_at"}
+		now     = time.Now()
+		res     []*model.Activity
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, int64(1), int64(10)).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow("priority_changed", taskID, userID, nil, nil, "medium", "high", now).
+				AddRow("comment", taskID, memberID, commentID, "Agreed.", nil, nil, now))
+		res, err = r.FetchActivity(userID, listID, taskID, 1, 10)
+		assert.NoError(t, err)
+		var previous, current, content, comment = "medium", "high", "Agreed.", uuid.MustParse(commentID)
+		assert.Equal(t, []*model.Activity{
+			{
+				Kind:       types.ActivityKindPriorityChanged,
+				TaskUUID:   uuid.MustParse(taskID),
+				ActorUUID:  uuid.MustParse(userID),
+				Previous:   &previous,
+				Current:    &current,
+				OccurredAt: now,
+			},
+			{
+				Kind:        types.ActivityKindComment,
+				TaskUUID:    uuid.MustParse(taskID),
+				ActorUUID:   uuid.MustParse(memberID),
+				CommentUUID: &comment,
+				Content:     &content,
+				OccurredAt:  now,
+			},
+		}, res)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		res, err = r.FetchActivity(userID, listID, taskID, 1, 10)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+	})
+}
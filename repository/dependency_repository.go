@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+// DependencyRepository keeps the dependencies between the tasks of a user. A
+// task is blocked by another one until that other one is finished.
+type DependencyRepository interface {
+	Save(ownerID, taskID, blockerID string) (ok bool, err error)
+	Remove(ownerID, taskID, blockerID string) (ok bool, err error)
+	FetchGraph(ownerID, taskID string) (nodes []*model.DependencyNode, err error)
+}
+
+type dependencyRepository struct {
+	db *sql.DB
+}
+
+func NewDependencyRepository(db *sql.DB) DependencyRepository {
+	return &dependencyRepository{db: db}
+}
+
+// Save makes the task blocked by the blocker, unless the blocker is already
+// blocked by the task, directly or through other tasks, in which case it
+// fails with failure.ErrDependencyCycle. It is not ok if the task already was
+// blocked by the blocker.
+//
+// The dependencies of the owner are locked while the graph of the blocker is
+// checked and the dependency is saved, so that two dependencies saved at once
+// can never close a cycle together.
+func (r *dependencyRepository) Save(ownerID, taskID, blockerID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer func() {
+		if nil == err || errors.Is(err, failure.ErrDependencyCycle) {
+			return
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentTaskError(pqerr):
+				err = failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+	}()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if nil != err {
+		return false, err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock (hashtext ('dependencies'), hashtext ($1));`, ownerID)
+	if nil != err {
+		return false, err
+	}
+	nodes, err := fetchGraph(ctx, tx, ownerID, blockerID)
+	if nil != err {
+		return false, err
+	}
+	if isBlockedBy(nodes, blockerID, taskID) {
+		return false, failure.ErrDependencyCycle
+	}
+	err = tx.QueryRowContext(ctx, `SELECT "dependencies"."save" ($1, $2, $3);`, ownerID, taskID, blockerID).Scan(&ok)
+	if nil != err {
+		return false, err
+	}
+	if err = tx.Commit(); nil != err {
+		return false, err
+	}
+	return ok, nil
+}
+
+// Remove makes the task no longer blocked by the blocker. It is not ok if it
+// was not.
+func (r *dependencyRepository) Remove(ownerID, taskID, blockerID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "dependencies"."remove" ($1, $2, $3);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, taskID, blockerID).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// FetchGraph retrieves the task along with every task linked to it through
+// dependencies, in either direction, oldest first.
+func (r *dependencyRepository) FetchGraph(ownerID, taskID string) (nodes []*model.DependencyNode, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	nodes, err = fetchGraph(ctx, r.db, ownerID, taskID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// queryer runs queries either on the database or within a transaction.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// fetchGraph retrieves the graph of the task through q.
+func fetchGraph(ctx context.Context, q queryer, ownerID, taskID string) (nodes []*model.DependencyNode, err error) {
+	rows, err := q.QueryContext(ctx, `SELECT * FROM "dependencies"."fetch_graph" ($1, $2);`, ownerID, taskID)
+	if nil != err {
+		return nil, err
+	}
+	defer rows.Close()
+	nodes = make([]*model.DependencyNode, 0)
+	for rows.Next() {
+		var node = new(model.DependencyNode)
+		var blockers []string
+		err = rows.Scan(
+			&node.TaskUUID,
+			&node.ListUUID,
+			&node.Title,
+			&node.Status,
+			pq.Array(&blockers))
+		if nil != err {
+			return nil, err
+		}
+		node.BlockedBy = make([]uuid.UUID, 0, len(blockers))
+		for _, blocker := range blockers {
+			id, err := uuid.Parse(blocker)
+			if nil != err {
+				return nil, err
+			}
+			node.BlockedBy = append(node.BlockedBy, id)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, rows.Err()
+}
+
+// isBlockedBy tells whether the task is blocked by the blocker, directly or
+// through other tasks of the graph.
+func isBlockedBy(nodes []*model.DependencyNode, taskID, blockerID string) bool {
+	var blockers = make(map[string][]uuid.UUID, len(nodes))
+	for _, node := range nodes {
+		blockers[node.TaskUUID.String()] = node.BlockedBy
+	}
+	var visited = make(map[string]bool)
+	var pending = []string{taskID}
+	for 0 < len(pending) {
+		var current = pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, blocker := range blockers[current] {
+			if blocker.String() == blockerID {
+				return true
+			}
+			if !visited[blocker.String()] {
+				visited[blocker.String()] = true
+				pending = append(pending, blocker.String())
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+)
+
+const blockerID = "b1d3f5a7-9c2e-4f6a-8b0d-2e4f6a8c0e13"
+
+func TestDependencyRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewDependencyRepository(db)
+		lock    = regexp.QuoteMeta(`SELECT pg_advisory_xact_lock (hashtext ('dependencies'), hashtext ($1));`)
+		graph   = regexp.QuoteMeta(`SELECT * FROM "dependencies"."fetch_graph" ($1, $2);`)
+		query   = regexp.QuoteMeta(`SELECT "dependencies"."save" ($1, $2, $3);`)
+		columns = []string{"task_uuid", "list_uuid", "title", "status", "blocked_by"}
+		ok      bool
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(lock).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectQuery(graph).
+			WithArgs(userID, blockerID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(blockerID, listID, "Design", "unfinished", "{}"))
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, blockerID).
+			WillReturnRows(sqlmock.NewRows([]string{"save"}).AddRow(true))
+		mock.ExpectCommit()
+		ok, err = r.Save(userID, taskID, blockerID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("refuses cycles", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(lock).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectQuery(graph).
+			WithArgs(userID, blockerID).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(taskID, listID, "Design", "unfinished", "{}").
+				AddRow(blockerID, listID, "Build", "unfinished", "{"+taskID+"}"))
+		mock.ExpectRollback()
+		ok, err = r.Save(userID, taskID, blockerID)
+		assert.ErrorIs(t, err, failure.ErrDependencyCycle)
+		assert.False(t, ok)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(lock).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.
+			ExpectQuery(graph).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + blockerID + "\""})
+		mock.ExpectRollback()
+		ok, err = r.Save(userID, taskID, blockerID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.False(t, ok)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(lock).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(graph).WillReturnRows(sqlmock.NewRows(columns))
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
+		ok, err = r.Save(userID, taskID, blockerID)
+		assert.Error(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestDependencyRepository_Remove(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewDependencyRepository(db)
+		query = regexp.QuoteMeta(`SELECT "dependencies"."remove" ($1, $2, $3);`)
+		ok    bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, blockerID).
+			WillReturnRows(sqlmock.NewRows([]string{"remove"}).AddRow(true))
+		ok, err = r.Remove(userID, taskID, blockerID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		ok, err = r.Remove(userID, taskID, blockerID)
+		assert.Error(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestDependencyRepository_FetchGraph(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewDependencyRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "dependencies"."fetch_graph" ($1, $2);`)
+		columns = []string{"task_uuid", "list_uuid", "title", "status", "blocked_by"}
+		res     []*model.DependencyNode
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(blockerID, listID, "Design", "finished", "{}").
+				AddRow(taskID, listID, "Build", "in progress", "{"+blockerID+"}"))
+		res, err = r.FetchGraph(userID, taskID)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.DependencyNode{
+			{
+				TaskUUID:  uuid.MustParse(blockerID),
+				ListUUID:  uuid.MustParse(listID),
+				Title:     "Design",
+				Status:    types.TaskStatusComplete,
+				BlockedBy: []uuid.UUID{},
+			},
+			{
+				TaskUUID:  uuid.MustParse(taskID),
+				ListUUID:  uuid.MustParse(listID),
+				Title:     "Build",
+				Status:    types.TaskStatusIncomplete,
+				BlockedBy: []uuid.UUID{uuid.MustParse(blockerID)},
+			},
+		}, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.FetchGraph(userID, taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+type ExportRepository interface {
+	Save(ownerID string) (insertedID string, err error)
+	FetchLatest(ownerID string) (export *model.Export, err error)
+	FetchPending(limit int) (exports []*model.Export, err error)
+	FetchExpired(now time.Time, limit int) (exports []*model.Export, err error)
+	MarkReady(exportID, blobKey string, size int64, expiresAt time.Time) (ok bool, err error)
+	MarkFailed(exportID string) (ok bool, err error)
+	MarkExpired(exportID string) (ok bool, err error)
+}
+
+type exportRepository struct {
+	db *sql.DB
+}
+
+func NewExportRepository(db *sql.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+// Save records a pending export of the data of the user.
+func (r *exportRepository) Save(ownerID string) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "export"."make" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+// FetchLatest retrieves the export the user asked for last.
+func (r *exportRepository) FetchLatest(ownerID string) (export *model.Export, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "export"."fetch_latest" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID)
+	export = new(model.Export)
+	err = row.Scan(
+		&export.UUID,
+		&export.OwnerUUID,
+		&export.Status,
+		&export.Size,
+		&export.BlobKey,
+		&export.RequestedAt,
+		&export.ReadyAt,
+		&export.ExpiresAt)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, failure.ErrExportNotFound
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// FetchPending retrieves at most limit exports whose archive is yet to be
+// built, the oldest first.
+func (r *exportRepository) FetchPending(limit int) (exports []*model.Export, err error) {
+	return r.fetch(`SELECT * FROM "export"."fetch_pending" ($1);`, limit)
+}
+
+// FetchExpired retrieves at most limit ready exports whose archive expired
+// before now.
+func (r *exportRepository) FetchExpired(now time.Time, limit int) (exports []*model.Export, err error) {
+	return r.fetch(`SELECT * FROM "export"."fetch_expired" ($1, $2);`, now, limit)
+}
+
+func (r *exportRepository) fetch(query string, args ...any) (exports []*model.Export, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	exports = make([]*model.Export, 0)
+	for rows.Next() {
+		var export = new(model.Export)
+		err = rows.Scan(
+			&export.UUID,
+			&export.OwnerUUID,
+			&export.Status,
+			&export.Size,
+			&export.BlobKey,
+			&export.RequestedAt,
+			&export.ReadyAt,
+			&export.ExpiresAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	return exports, nil
+}
+
+// MarkReady records that the archive of the export is stored under blobKey
+// and can be downloaded until expiresAt.
+func (r *exportRepository) MarkReady(exportID, blobKey string, size int64, expiresAt time.Time) (ok bool, err error) {
+	return r.mark(`SELECT "export"."mark_ready" ($1, $2, $3, $4);`, exportID, blobKey, size, expiresAt)
+}
+
+func (r *exportRepository) MarkFailed(exportID string) (ok bool, err error) {
+	return r.mark(`SELECT "export"."mark_failed" ($1);`, exportID)
+}
+
+// MarkExpired records that the archive of the export has been removed.
+func (r *exportRepository) MarkExpired(exportID string) (ok bool, err error) {
+	return r.mark(`SELECT "export"."mark_expired" ($1);`, exportID)
+}
+
+func (r *exportRepository) mark(query string, args ...any) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var row = r.db.QueryRowContext(ctx, query, args...)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
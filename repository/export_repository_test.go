@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const exportID = "0b6c3d2e-8f4a-4e1b-9c7d-2a5f6e3b1c4d"
+
+var exportColumns = []string{
+	"export_uuid", "owner_uuid", "status", "size", "blob_key", "requested_at", "ready_at", "expires_at",
+}
+
+func TestExportRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewExportRepository(db)
+		query = regexp.QuoteMeta(`SELECT "export"."make" ($1);`)
+		res   string
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"make"}).AddRow(exportID))
+		res, err = r.Save(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, exportID, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.Save(userID)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Empty(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(userID)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestExportRepository_FetchLatest(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r           = NewExportRepository(db)
+		query       = regexp.QuoteMeta(`SELECT * FROM "export"."fetch_latest" ($1);`)
+		requestedAt = time.Now()
+		res         *model.Export
+		err         error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.
+				NewRows(exportColumns).
+				AddRow(exportID, userID, "pending", 0, nil, requestedAt, nil, nil))
+		res, err = r.FetchLatest(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.Export{
+			UUID:        uuid.MustParse(exportID),
+			OwnerUUID:   uuid.MustParse(userID),
+			Status:      types.ExportStatusPending,
+			RequestedAt: requestedAt,
+		}, res)
+	})
+
+	t.Run("export not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchLatest(userID)
+		assert.ErrorIs(t, err, failure.ErrExportNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.FetchLatest(userID)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Nil(t, res)
+	})
+}
+
+func TestExportRepository_FetchPending(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r           = NewExportRepository(db)
+		query       = regexp.QuoteMeta(`SELECT * FROM "export"."fetch_pending" ($1);`)
+		requestedAt = time.Now()
+		res         []*model.Export
+		err         error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(10).
+			WillReturnRows(sqlmock.
+				NewRows(exportColumns).
+				AddRow(exportID, userID, "pending", 0, nil, requestedAt, nil, nil))
+		res, err = r.FetchPending(10)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, uuid.MustParse(exportID), res[0].UUID)
+	})
+
+	t.Run("got a scanning error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"export_uuid"}).AddRow(exportID))
+		res, err = r.FetchPending(10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchPending(10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestExportRepository_FetchExpired(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r         = NewExportRepository(db)
+		query     = regexp.QuoteMeta(`SELECT * FROM "export"."fetch_expired" ($1, $2);`)
+		now       = time.Now()
+		readyAt   = now.Add(-8 * 24 * time.Hour)
+		expiresAt = now.Add(-24 * time.Hour)
+		key       = "exports/" + userID + "/" + exportID + ".zip"
+		res       []*model.Export
+		err       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(now, 10).
+			WillReturnRows(sqlmock.
+				NewRows(exportColumns).
+				AddRow(exportID, userID, "ready", 2048, key, readyAt, readyAt, expiresAt))
+		res, err = r.FetchExpired(now, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Export{{
+			UUID:        uuid.MustParse(exportID),
+			OwnerUUID:   uuid.MustParse(userID),
+			Status:      types.ExportStatusReady,
+			Size:        2048,
+			BlobKey:     &key,
+			RequestedAt: readyAt,
+			ReadyAt:     &readyAt,
+			ExpiresAt:   &expiresAt,
+		}}, res)
+	})
+}
+
+func TestExportRepository_MarkReady(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r         = NewExportRepository(db)
+		query     = regexp.QuoteMeta(`SELECT "export"."mark_ready" ($1, $2, $3, $4);`)
+		expiresAt = time.Now()
+		res       bool
+		err       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(exportID, "exports/a.zip", int64(2048), expiresAt).
+			WillReturnRows(sqlmock.NewRows([]string{"mark_ready"}).AddRow(true))
+		res, err = r.MarkReady(exportID, "exports/a.zip", 2048, expiresAt)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.MarkReady(exportID, "exports/a.zip", 2048, expiresAt)
+		assert.Error(t, err)
+		assert.False(t, res)
+	})
+}
+
+func TestExportRepository_MarkFailed(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewExportRepository(db)
+		query = regexp.QuoteMeta(`SELECT "export"."mark_failed" ($1);`)
+	)
+	mock.
+		ExpectQuery(query).
+		WithArgs(exportID).
+		WillReturnRows(sqlmock.NewRows([]string{"mark_failed"}).AddRow(true))
+	res, err := r.MarkFailed(exportID)
+	assert.NoError(t, err)
+	assert.True(t, res)
+}
+
+func TestExportRepository_MarkExpired(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewExportRepository(db)
+		query = regexp.QuoteMeta(`SELECT "export"."mark_expired" ($1);`)
+	)
+	mock.
+		ExpectQuery(query).
+		WithArgs(exportID).
+		WillReturnRows(sqlmock.NewRows([]string{"mark_expired"}).AddRow(false))
+	res, err := r.MarkExpired(exportID)
+	assert.NoError(t, err)
+	assert.False(t, res)
+}
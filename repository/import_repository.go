@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+// ImportRepository keeps what the imports of a user made, by the format it
+// was imported from and the key it had there, so that importing the same data
+// again skips it.
+type ImportRepository interface {
+	SaveRecord(ownerID, format, kind, key, entityID string) error
+	FetchRecords(ownerID, format string) (records []*model.ImportRecord, err error)
+}
+
+type importRepository struct {
+	db *sql.DB
+}
+
+func NewImportRepository(db *sql.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) SaveRecord(ownerID, format, kind, key, entityID string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "import"."save_record" ($1, $2, $3, $4, $5);`
+	_, err := r.db.ExecContext(ctx, query, ownerID, format, kind, key, entityID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// FetchRecords retrieves what was imported from the format, leaving out what
+// has been deleted since.
+func (r *importRepository) FetchRecords(ownerID, format string) (records []*model.ImportRecord, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "import"."fetch_records" ($1, $2);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, format)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	records = make([]*model.ImportRecord, 0)
+	for rows.Next() {
+		var record = new(model.ImportRecord)
+		err = rows.Scan(&record.Kind, &record.Key, &record.EntityUUID)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
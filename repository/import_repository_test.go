@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/failure"
+	"regexp"
+	"testing"
+)
+
+func TestImportRepository_SaveRecord(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewImportRepository(db)
+		query = regexp.QuoteMeta(`SELECT "import"."save_record" ($1, $2, $3, $4, $5);`)
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(userID, "trello", "task", "c1", taskID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = r.SaveRecord(userID, "trello", "task", "c1", taskID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		err = r.SaveRecord(userID, "trello", "task", "c1", taskID)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+	})
+}
+
+func TestImportRepository_FetchRecords(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r      = NewImportRepository(db)
+		query  = regexp.QuoteMeta(`SELECT * FROM "import"."fetch_records" ($1, $2);`)
+		record = &model.ImportRecord{Kind: "list", Key: "l1", EntityUUID: uuid.MustParse(listID)}
+		res    []*model.ImportRecord
+		err    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, "trello").
+			WillReturnRows(sqlmock.
+				NewRows([]string{"kind", "key", "entity_uuid"}).
+				AddRow(record.Kind, record.Key, record.EntityUUID))
+		res, err = r.FetchRecords(userID, "trello")
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.ImportRecord{record}, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.FetchRecords(userID, "trello")
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+type JobRunRepository interface {
+	Start(job, instance string) (insertedID string, err error)
+	Finish(runID, errMessage string) (ok bool, err error)
+	Fetch(page, rpp int64, job string) (runs []*model.JobRun, err error)
+}
+
+type jobRunRepository struct {
+	db *sql.DB
+}
+
+func NewJobRunRepository(db *sql.DB) JobRunRepository {
+	return &jobRunRepository{db: db}
+}
+
+func (r *jobRunRepository) Start(job, instance string) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "jobs"."start_run" ($1, $2);`
+	var row = r.db.QueryRowContext(ctx, query, job, instance)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+// Finish marks the run as finished. An empty errMessage is stored as NULL and
+// means that the run succeeded.
+func (r *jobRunRepository) Finish(runID, errMessage string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "jobs"."finish_run" ($1, $2);`
+	var nullableMessage = sql.NullString{String: errMessage, Valid: "" != errMessage}
+	var row = r.db.QueryRowContext(ctx, query, runID, nullableMessage)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// Fetch retrieves the runs, the most recent first. An empty job retrieves the
+// runs of all the jobs.
+func (r *jobRunRepository) Fetch(page, rpp int64, job string) (runs []*model.JobRun, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "jobs"."fetch_runs" ($1, $2, $3);`
+	rows, err := r.db.QueryContext(ctx, query, page, rpp, job)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	runs = make([]*model.JobRun, 0)
+	for rows.Next() {
+		var run = new(model.JobRun)
+		err = rows.Scan(
+			&run.UUID,
+			&run.Job,
+			&run.Instance,
+			&run.StartedAt,
+			&run.FinishedAt,
+			&run.Error)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const runID = "5e1f0c2a-7b6d-4c3e-9a8f-1d2c3b4a5e6f"
+
+func TestJobRunRepository_Start(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewJobRunRepository(db)
+		query = regexp.QuoteMeta(`SELECT "jobs"."start_run" ($1, $2);`)
+		res   string
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs("rollover", "host:1").
+			WillReturnRows(sqlmock.NewRows([]string{"start_run"}).AddRow(runID))
+		res, err = r.Start("rollover", "host:1")
+		assert.NoError(t, err)
+		assert.Equal(t, runID, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Start("rollover", "host:1")
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestJobRunRepository_Finish(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewJobRunRepository(db)
+		query = regexp.QuoteMeta(`SELECT "jobs"."finish_run" ($1, $2);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(runID, sql.NullString{}).
+			WillReturnRows(sqlmock.NewRows([]string{"finish_run"}).AddRow(true))
+		res, err = r.Finish(runID, "")
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("failed run", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(runID, sql.NullString{String: "boom", Valid: true}).
+			WillReturnRows(sqlmock.NewRows([]string{"finish_run"}).AddRow(true))
+		res, err = r.Finish(runID, "boom")
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Finish(runID, "")
+		assert.Error(t, err)
+		assert.False(t, res)
+	})
+}
+
+func TestJobRunRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r          = NewJobRunRepository(db)
+		query      = regexp.QuoteMeta(`SELECT * FROM "jobs"."fetch_runs" ($1, $2, $3);`)
+		columns    = []string{"run_uuid", "job", "instance", "started_at", "finished_at", "error"}
+		startedAt  = time.Now()
+		finishedAt = startedAt.Add(time.Second)
+		message    = "boom"
+		res        []*model.JobRun
+		err        error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(int64(1), int64(10), "rollover").
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(runID, "rollover", "host:1", startedAt, finishedAt, message).
+				AddRow(runID, "rollover", "host:1", startedAt, nil, nil))
+		res, err = r.Fetch(1, 10, "rollover")
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.JobRun{
+			{UUID: uuid.MustParse(runID), Job: "rollover", Instance: "host:1", StartedAt: startedAt, FinishedAt: &finishedAt, Error: &message},
+			{UUID: uuid.MustParse(runID), Job: "rollover", Instance: "host:1", StartedAt: startedAt},
+		}, res)
+	})
+
+	t.Run("got a scanning error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"run_uuid"}).AddRow(runID))
+		res, err = r.Fetch(1, 10, "")
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Fetch(1, 10, "")
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
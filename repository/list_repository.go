@@ -147,6 +147,8 @@ func (r *listRepository) GetTomorrowListID(ownerID string) (listID string, err e
 	return
 }
 
+// Fetch retrieves the lists of the user, in the order the database keeps
+// them; sortExpr is not applied, as by FetchGrouped.
 func (r *listRepository) Fetch(
 	ownerID string,
 	page, rpp int64,
@@ -162,9 +164,9 @@ func (r *listRepository) Fetch(
               FROM "lists"."fetch" (p_owner_uuid := $1,
                                     p_group_uuid := NULL,
                                     p_list_uuid := NULL,
-                                    p_needle := $3,
-                                    p_page := $4,
-                                    p_rpp := $5);`
+                                    p_needle := $2,
+                                    p_page := $3,
+                                    p_rpp := $4);`
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	result, err := r.db.QueryContext(ctx, query, ownerID, needle, page, rpp)
@@ -296,6 +296,36 @@ func TestListRepository_Fetch(t *testing.T) {
 	assert.Nil(t, res)
 }
 
+func TestListRepository_FetchBindsEveryPlaceholder(t *testing.T) {
+	var columns = []string{"uuid", "owner_uuid", "group_uuid", "name", "description", "created_at", "updated_at"}
+
+	t.Run("Fetch", func(t *testing.T) {
+		db, mock := newArityMock(4)
+		defer db.Close()
+		mock.
+			ExpectQuery(`"lists"."fetch"`).
+			WithArgs(userID, "", int64(1), int64(10)).
+			WillReturnRows(sqlmock.NewRows(columns))
+		res, err := NewListRepository(db).Fetch(userID, 1, 10, "", "")
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("FetchGrouped", func(t *testing.T) {
+		db, mock := newArityMock(5)
+		defer db.Close()
+		mock.
+			ExpectQuery(`"lists"."fetch"`).
+			WithArgs(userID, groupID, "", int64(1), int64(10)).
+			WillReturnRows(sqlmock.NewRows(columns))
+		res, err := NewListRepository(db).FetchGrouped(userID, groupID, 1, 10, "", "")
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestListRepository_FetchGrouped(t *testing.T) {
 	db, mock := newMock()
 	defer db.Close()
@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"time"
+)
+
+type MemberRepository interface {
+	FetchListAccess(userID, listID string) (access *model.ListAccess, err error)
+	FetchGroupAccess(userID, groupID string) (access *model.GroupAccess, err error)
+	Invite(inviterID string, kind types.ShareKind, targetID, email string, role types.MemberRole) (insertedID string, err error)
+	FetchInvitations(userID string, page, rpp int64) (invitations []*model.Invitation, err error)
+	Accept(userID, invitationID string) (ok bool, err error)
+	Decline(userID, invitationID string) (ok bool, err error)
+	FetchMembers(kind types.ShareKind, targetID string, page, rpp int64) (members []*model.Member, err error)
+	SetRole(kind types.ShareKind, targetID, memberID string, role types.MemberRole) (ok bool, err error)
+	Remove(kind types.ShareKind, targetID, memberID string) (ok bool, err error)
+	FetchSharedLists(userID string, page, rpp int64, needle string) (lists []*model.SharedList, err error)
+	FetchSharedGroups(userID string, page, rpp int64, needle string) (groups []*model.SharedGroup, err error)
+}
+
+type memberRepository struct {
+	db *sql.DB
+}
+
+func NewMemberRepository(db *sql.DB) MemberRepository {
+	return &memberRepository{db: db}
+}
+
+// FetchListAccess retrieves what the user can do with a list, that is, whether
+// it owns the list or the role it was given in the list or in its group; the
+// highest one if both. A list that is not shared with the user is not found.
+func (r *memberRepository) FetchListAccess(userID, listID string) (access *model.ListAccess, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "sharing"."fetch_list_access" ($1, $2);`
+	access = new(model.ListAccess)
+	err = r.db.QueryRowContext(ctx, query, userID, listID).
+		Scan(&access.ListUUID, &access.OwnerUUID, &access.GroupUUID, &access.Role)
+	if nil != err {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, failure.ErrListNotFound
+		case errors.As(err, &pqerr):
+			log.Println(failure.PQErrorToString(pqerr))
+		}
+		return nil, err
+	}
+	return access, nil
+}
+
+// FetchGroupAccess retrieves what the user can do with a group. A group that is
+// not shared with the user is not found.
+func (r *memberRepository) FetchGroupAccess(userID, groupID string) (access *model.GroupAccess, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "sharing"."fetch_group_access" ($1, $2);`
+	access = new(model.GroupAccess)
+	err = r.db.QueryRowContext(ctx, query, userID, groupID).
+		Scan(&access.GroupUUID, &access.OwnerUUID, &access.Role)
+	if nil != err {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, failure.ErrGroupNotFound
+		case errors.As(err, &pqerr):
+			log.Println(failure.PQErrorToString(pqerr))
+		}
+		return nil, err
+	}
+	return access, nil
+}
+
+// Invite invites the user with the given email to become a member of a list or
+// a group with the given role.
+func (r *memberRepository) Invite(
+	inviterID string,
+	kind types.ShareKind,
+	targetID, email string,
+	role types.MemberRole,
+) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "sharing"."invite" ($1, $2, $3, $4, $5);`
+	err = r.db.QueryRowContext(ctx, query, inviterID, kind, targetID, email, role).Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			case isNonexistentInviteeError(pqerr):
+				return "", failure.ErrUserNotFound.Clone().SetDetails("Could not find any user with this email.")
+			case isNonexistentListError(pqerr):
+				return "", failure.ErrListNotFound
+			case isNonexistentGroupError(pqerr):
+				return "", failure.ErrGroupNotFound
+			case isAlreadyMemberError(pqerr):
+				return "", failure.ErrAlreadyMember
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+// FetchInvitations retrieves the pending invitations of the user, the most
+// recent first.
+func (r *memberRepository) FetchInvitations(userID string, page, rpp int64) (invitations []*model.Invitation, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "sharing"."fetch_invitations" ($1, $2, $3);`
+	rows, err := r.db.QueryContext(ctx, query, userID, page, rpp)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	invitations = make([]*model.Invitation, 0)
+	for rows.Next() {
+		var invitation = new(model.Invitation)
+		err = rows.Scan(
+			&invitation.UUID,
+			&invitation.Kind,
+			&invitation.TargetUUID,
+			&invitation.TargetName,
+			&invitation.InviterUUID,
+			&invitation.InviterEmail,
+			&invitation.Role,
+			&invitation.CreatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+	return invitations, nil
+}
+
+// Accept makes the user a member of what it was invited to.
+func (r *memberRepository) Accept(userID, invitationID string) (ok bool, err error) {
+	return r.answer(`SELECT "sharing"."accept_invitation" ($1, $2);`, userID, invitationID)
+}
+
+// Decline discards an invitation of the user.
+func (r *memberRepository) Decline(userID, invitationID string) (ok bool, err error) {
+	return r.answer(`SELECT "sharing"."decline_invitation" ($1, $2);`, userID, invitationID)
+}
+
+func (r *memberRepository) answer(query, userID, invitationID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = r.db.QueryRowContext(ctx, query, userID, invitationID).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentInvitationError(pqerr):
+				return false, failure.ErrInvitationNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// FetchMembers retrieves the members of a list or a group, the oldest first.
+// The owner is not one of them.
+func (r *memberRepository) FetchMembers(kind types.ShareKind, targetID string, page, rpp int64) (members []*model.Member, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "sharing"."fetch_members" ($1, $2, $3, $4);`
+	rows, err := r.db.QueryContext(ctx, query, kind, targetID, page, rpp)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	members = make([]*model.Member, 0)
+	for rows.Next() {
+		var member = new(model.Member)
+		err = rows.Scan(
+			&member.UserUUID,
+			&member.FirstName,
+			&member.LastName,
+			&member.Email,
+			&member.Role,
+			&member.JoinedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (r *memberRepository) SetRole(kind types.ShareKind, targetID, memberID string, role types.MemberRole) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "sharing"."set_member_role" ($1, $2, $3, $4);`
+	err = r.db.QueryRowContext(ctx, query, kind, targetID, memberID, role).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentMemberError(pqerr):
+				return false, failure.ErrMemberNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *memberRepository) Remove(kind types.ShareKind, targetID, memberID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "sharing"."remove_member" ($1, $2, $3);`
+	err = r.db.QueryRowContext(ctx, query, kind, targetID, memberID).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentMemberError(pqerr):
+				return false, failure.ErrMemberNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// FetchSharedLists retrieves the lists of other users that are shared with the
+// user, directly or through their groups.
+func (r *memberRepository) FetchSharedLists(userID string, page, rpp int64, needle string) (lists []*model.SharedList, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "sharing"."fetch_shared_lists" ($1, $2, $3, $4);`
+	rows, err := r.db.QueryContext(ctx, query, userID, page, rpp, needle)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	lists = make([]*model.SharedList, 0)
+	for rows.Next() {
+		var (
+			list        = new(model.SharedList)
+			groupID     uuid.NullUUID
+			description sql.NullString
+		)
+		err = rows.Scan(
+			&list.UUID,
+			&list.OwnerUUID,
+			&groupID,
+			&list.Name,
+			&description,
+			&list.CreatedAt,
+			&list.UpdatedAt,
+			&list.Role)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		list.GroupUUID = groupID.UUID
+		list.Description = description.String
+		lists = append(lists, list)
+	}
+	return lists, nil
+}
+
+// FetchSharedGroups retrieves the groups of other users that are shared with
+// the user.
+func (r *memberRepository) FetchSharedGroups(userID string, page, rpp int64, needle string) (groups []*model.SharedGroup, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "sharing"."fetch_shared_groups" ($1, $2, $3, $4);`
+	rows, err := r.db.QueryContext(ctx, query, userID, page, rpp, needle)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	groups = make([]*model.SharedGroup, 0)
+	for rows.Next() {
+		var (
+			group       = new(model.SharedGroup)
+			description sql.NullString
+		)
+		err = rows.Scan(
+			&group.UUID,
+			&group.OwnerUUID,
+			&group.Name,
+			&description,
+			&group.CreatedAt,
+			&group.UpdatedAt,
+			&group.Role)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		group.Description = description.String
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
@@ -0,0 +1,454 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const (
+	memberID     = "4f6b8d0a-2c4e-4a6b-8d0f-1a3c5e7b9d24"
+	invitationID = "a1c3e5b7-9d2f-4b6a-8c0e-2f4a6c8e0b13"
+)
+
+func TestMemberRepository_FetchListAccess(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewMemberRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "sharing"."fetch_list_access" ($1, $2);`)
+		columns = []string{"list_uuid", "owner_uuid", "group_uuid", "role"}
+		res     *model.ListAccess
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, listID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(listID, userID, groupID, "editor"))
+		res, err = r.FetchListAccess(memberID, listID)
+		assert.NoError(t, err)
+		var group = uuid.MustParse(groupID)
+		assert.Equal(t, &model.ListAccess{
+			ListUUID:  uuid.MustParse(listID),
+			OwnerUUID: uuid.MustParse(userID),
+			GroupUUID: &group,
+			Role:      types.MemberRoleEditor,
+		}, res)
+	})
+
+	t.Run("list not shared with the user", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, listID).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchListAccess(memberID, listID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchListAccess(memberID, listID)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestMemberRepository_FetchGroupAccess(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewMemberRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "sharing"."fetch_group_access" ($1, $2);`)
+		columns = []string{"group_uuid", "owner_uuid", "role"}
+		res     *model.GroupAccess
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, groupID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(groupID, userID, "viewer"))
+		res, err = r.FetchGroupAccess(memberID, groupID)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.GroupAccess{
+			GroupUUID: uuid.MustParse(groupID),
+			OwnerUUID: uuid.MustParse(userID),
+			Role:      types.MemberRoleViewer,
+		}, res)
+	})
+
+	t.Run("group not shared with the user", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, groupID).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchGroupAccess(memberID, groupID)
+		assert.ErrorIs(t, err, failure.ErrGroupNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestMemberRepository_Invite(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewMemberRepository(db)
+		query = regexp.QuoteMeta(`SELECT "sharing"."invite" ($1, $2, $3, $4, $5);`)
+		email = "member@noda.com"
+		res   string
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, types.ShareKindList, listID, email, types.MemberRoleEditor).
+			WillReturnRows(sqlmock.NewRows([]string{"invite"}).AddRow(invitationID))
+		res, err = r.Invite(userID, types.ShareKindList, listID, email, types.MemberRoleEditor)
+		assert.NoError(t, err)
+		assert.Equal(t, invitationID, res)
+	})
+
+	t.Run("invitee not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with email \"" + email + "\""})
+		res, err = r.Invite(userID, types.ShareKindList, listID, email, types.MemberRoleEditor)
+		assert.ErrorContains(t, err, "Could not find any user with this email.")
+		assert.Empty(t, res)
+	})
+
+	t.Run("already a member", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "user \"" + email + "\" is already a member or invited"})
+		res, err = r.Invite(userID, types.ShareKindGroup, groupID, email, types.MemberRoleViewer)
+		assert.ErrorIs(t, err, failure.ErrAlreadyMember)
+		assert.Empty(t, res)
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent group with UUID \"" + groupID + "\""})
+		res, err = r.Invite(userID, types.ShareKindGroup, groupID, email, types.MemberRoleViewer)
+		assert.ErrorIs(t, err, failure.ErrGroupNotFound)
+		assert.Empty(t, res)
+	})
+}
+
+func TestMemberRepository_FetchInvitations(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewMemberRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "sharing"."fetch_invitations" ($1, $2, $3);`)
+		columns = []string{"invitation_uuid", "kind", "target_uuid", "target_name", "inviter_uuid", "inviter_email", "role", "created_at"}
+		now     = time.Now()
+		res     []*model.Invitation
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, int64(1), int64(10)).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(invitationID, "list", listID, "groceries", userID, "owner@noda.com", "editor", now))
+		res, err = r.FetchInvitations(memberID, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Invitation{{
+			UUID:         uuid.MustParse(invitationID),
+			Kind:         types.ShareKindList,
+			TargetUUID:   uuid.MustParse(listID),
+			TargetName:   "groceries",
+			InviterUUID:  uuid.MustParse(userID),
+			InviterEmail: "owner@noda.com",
+			Role:         types.MemberRoleEditor,
+			CreatedAt:    now,
+		}}, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchInvitations(memberID, 1, 10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestMemberRepository_Accept(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewMemberRepository(db)
+		query = regexp.QuoteMeta(`SELECT "sharing"."accept_invitation" ($1, $2);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, invitationID).
+			WillReturnRows(sqlmock.NewRows([]string{"accept_invitation"}).AddRow(true))
+		res, err = r.Accept(memberID, invitationID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("invitation not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent invitation with UUID \"" + invitationID + "\""})
+		res, err = r.Accept(memberID, invitationID)
+		assert.ErrorIs(t, err, failure.ErrInvitationNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestMemberRepository_Decline(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewMemberRepository(db)
+		query = regexp.QuoteMeta(`SELECT "sharing"."decline_invitation" ($1, $2);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, invitationID).
+			WillReturnRows(sqlmock.NewRows([]string{"decline_invitation"}).AddRow(true))
+		res, err = r.Decline(memberID, invitationID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("invitation not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent invitation with UUID \"" + invitationID + "\""})
+		res, err = r.Decline(memberID, invitationID)
+		assert.ErrorIs(t, err, failure.ErrInvitationNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestMemberRepository_FetchMembers(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewMemberRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "sharing"."fetch_members" ($1, $2, $3, $4);`)
+		columns = []string{"user_uuid", "first_name", "last_name", "email", "role", "joined_at"}
+		now     = time.Now()
+		res     []*model.Member
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(types.ShareKindGroup, groupID, int64(1), int64(10)).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(memberID, "Jane", "Doe", "jane@noda.com", "admin", now))
+		res, err = r.FetchMembers(types.ShareKindGroup, groupID, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Member{{
+			UserUUID:  uuid.MustParse(memberID),
+			FirstName: "Jane",
+			LastName:  "Doe",
+			Email:     "jane@noda.com",
+			Role:      types.MemberRoleAdmin,
+			JoinedAt:  now,
+		}}, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchMembers(types.ShareKindGroup, groupID, 1, 10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestMemberRepository_SetRole(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewMemberRepository(db)
+		query = regexp.QuoteMeta(`SELECT "sharing"."set_member_role" ($1, $2, $3, $4);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(types.ShareKindList, listID, memberID, types.MemberRoleAdmin).
+			WillReturnRows(sqlmock.NewRows([]string{"set_member_role"}).AddRow(true))
+		res, err = r.SetRole(types.ShareKindList, listID, memberID, types.MemberRoleAdmin)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("member not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent member with UUID \"" + memberID + "\""})
+		res, err = r.SetRole(types.ShareKindList, listID, memberID, types.MemberRoleAdmin)
+		assert.ErrorIs(t, err, failure.ErrMemberNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestMemberRepository_Remove(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewMemberRepository(db)
+		query = regexp.QuoteMeta(`SELECT "sharing"."remove_member" ($1, $2, $3);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(types.ShareKindGroup, groupID, memberID).
+			WillReturnRows(sqlmock.NewRows([]string{"remove_member"}).AddRow(true))
+		res, err = r.Remove(types.ShareKindGroup, groupID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("member not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent member with UUID \"" + memberID + "\""})
+		res, err = r.Remove(types.ShareKindGroup, groupID, memberID)
+		assert.ErrorIs(t, err, failure.ErrMemberNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestMemberRepository_FetchSharedLists(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewMemberRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "sharing"."fetch_shared_lists" ($1, $2, $3, $4);`)
+		columns = []string{"list_uuid", "owner_uuid", "group_uuid", "name", "description", "created_at", "updated_at", "role"}
+		now     = time.Now()
+		res     []*model.SharedList
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, int64(1), int64(10), "").
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(listID, userID, nil, "groceries", nil, now, now, "viewer"))
+		res, err = r.FetchSharedLists(memberID, 1, 10, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.SharedList{{
+			List: model.List{
+				UUID:      uuid.MustParse(listID),
+				OwnerUUID: uuid.MustParse(userID),
+				Name:      "groceries",
+				CreatedAt: now,
+				UpdatedAt: now,
+			},
+			Role: types.MemberRoleViewer,
+		}}, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + memberID + "\""})
+		res, err = r.FetchSharedLists(memberID, 1, 10, "")
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Nil(t, res)
+	})
+}
+
+func TestMemberRepository_FetchSharedGroups(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewMemberRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "sharing"."fetch_shared_groups" ($1, $2, $3, $4);`)
+		columns = []string{"group_uuid", "owner_uuid", "name", "description", "created_at", "updated_at", "role"}
+		now     = time.Now()
+		res     []*model.SharedGroup
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, int64(1), int64(10), "work").
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(groupID, userID, "work", "all about work", now, now, "admin"))
+		res, err = r.FetchSharedGroups(memberID, 1, 10, "work")
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.SharedGroup{{
+			Group: model.Group{
+				UUID:        uuid.MustParse(groupID),
+				OwnerUUID:   uuid.MustParse(userID),
+				Name:        "work",
+				Description: "all about work",
+				CreatedAt:   &now,
+				UpdatedAt:   &now,
+			},
+			Role: types.MemberRoleAdmin,
+		}}, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchSharedGroups(memberID, 1, 10, "work")
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
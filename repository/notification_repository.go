@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+type NotificationRepository interface {
+	Save(ownerID, taskID, title, message string, remindAt time.Time) (insertedID string, err error)
+	Fetch(ownerID string, page, rpp int64) (notifications []*model.Notification, err error)
+	Dismiss(ownerID, notificationID string) (ok bool, err error)
+	Snooze(ownerID, notificationID string, until time.Time) (ok bool, err error)
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Save adds a notification of the reminder of the task at remindAt to the
+// inbox of the owner. There is at most one notification per reminder: saving
+// it again returns the UUID of the one that exists.
+func (r *notificationRepository) Save(ownerID, taskID, title, message string, remindAt time.Time) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "notifications"."make" ($1, $2, $3, $4, $5);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, title, message, remindAt)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return "", failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+// Fetch retrieves the notifications of the owner that were not dismissed, the
+// most recent first.
+func (r *notificationRepository) Fetch(ownerID string, page, rpp int64) (notifications []*model.Notification, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "notifications"."fetch" ($1, $2, $3);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, page, rpp)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	notifications = make([]*model.Notification, 0)
+	for rows.Next() {
+		var notification = new(model.Notification)
+		err = rows.Scan(
+			&notification.UUID,
+			&notification.OwnerUUID,
+			&notification.TaskUUID,
+			&notification.Title,
+			&notification.Message,
+			&notification.RemindAt,
+			&notification.CreatedAt,
+			&notification.DismissedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) Dismiss(ownerID, notificationID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "notifications"."dismiss" ($1, $2);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, notificationID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentNotificationError(pqerr):
+				return false, failure.ErrNotificationNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// Snooze dismisses the notification and sets the reminder of its task to
+// until, so that it is delivered again then.
+func (r *notificationRepository) Snooze(ownerID, notificationID string, until time.Time) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "notifications"."snooze" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, notificationID, until)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentNotificationError(pqerr):
+				return false, failure.ErrNotificationNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const notificationID = "3c9a7e1b-2f4d-4b8a-9e6c-5d1f0a2b7c48"
+
+func TestNotificationRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewNotificationRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "notifications"."make" ($1, $2, $3, $4, $5);`)
+		remindAt = time.Now()
+		res      string
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, "title", "message", remindAt).
+			WillReturnRows(sqlmock.NewRows([]string{"make"}).AddRow(notificationID))
+		res, err = r.Save(userID, taskID, "title", "message", remindAt)
+		assert.NoError(t, err)
+		assert.Equal(t, notificationID, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, "title", "message", remindAt).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Save(userID, taskID, "title", "message", remindAt)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Empty(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(userID, taskID, "title", "message", remindAt)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestNotificationRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r           = NewNotificationRepository(db)
+		query       = regexp.QuoteMeta(`SELECT * FROM "notifications"."fetch" ($1, $2, $3);`)
+		columns     = []string{"notification_uuid", "owner_uuid", "task_uuid", "title", "message", "remind_at", "created_at", "dismissed_at"}
+		remindAt    = time.Now()
+		createdAt   = remindAt.Add(time.Second)
+		dismissedAt = createdAt.Add(time.Minute)
+		res         []*model.Notification
+		err         error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, int64(1), int64(10)).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(notificationID, userID, taskID, "title", "message", remindAt, createdAt, nil).
+				AddRow(notificationID, userID, taskID, "title", "", remindAt, createdAt, dismissedAt))
+		res, err = r.Fetch(userID, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Notification{
+			{UUID: uuid.MustParse(notificationID), OwnerUUID: uuid.MustParse(userID), TaskUUID: uuid.MustParse(taskID), Title: "title", Message: "message", RemindAt: remindAt, CreatedAt: createdAt},
+			{UUID: uuid.MustParse(notificationID), OwnerUUID: uuid.MustParse(userID), TaskUUID: uuid.MustParse(taskID), Title: "title", RemindAt: remindAt, CreatedAt: createdAt, DismissedAt: &dismissedAt},
+		}, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.Fetch(userID, 1, 10)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got a scanning error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"notification_uuid"}).AddRow(notificationID))
+		res, err = r.Fetch(userID, 1, 10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestNotificationRepository_Dismiss(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewNotificationRepository(db)
+		query = regexp.QuoteMeta(`SELECT "notifications"."dismiss" ($1, $2);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, notificationID).
+			WillReturnRows(sqlmock.NewRows([]string{"dismiss"}).AddRow(true))
+		res, err = r.Dismiss(userID, notificationID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("notification not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, notificationID).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent notification with UUID \"" + notificationID + "\""})
+		res, err = r.Dismiss(userID, notificationID)
+		assert.ErrorIs(t, err, failure.ErrNotificationNotFound)
+		assert.False(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Dismiss(userID, notificationID)
+		assert.Error(t, err)
+		assert.False(t, res)
+	})
+}
+
+func TestNotificationRepository_Snooze(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewNotificationRepository(db)
+		query = regexp.QuoteMeta(`SELECT "notifications"."snooze" ($1, $2, $3);`)
+		until = time.Now().Add(time.Hour)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, notificationID, until).
+			WillReturnRows(sqlmock.NewRows([]string{"snooze"}).AddRow(true))
+		res, err = r.Snooze(userID, notificationID, until)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("notification not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, notificationID, until).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent notification with UUID \"" + notificationID + "\""})
+		res, err = r.Snooze(userID, notificationID, until)
+		assert.ErrorIs(t, err, failure.ErrNotificationNotFound)
+		assert.False(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Snooze(userID, notificationID, until)
+		assert.Error(t, err)
+		assert.False(t, res)
+	})
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+type PasswordResetRepository interface {
+	Save(userID, hash string, expiresAt time.Time) (insertedID string, err error)
+	FetchByHash(hash string) (reset *model.PasswordReset, err error)
+	Consume(resetID string) (ok bool, err error)
+}
+
+type passwordResetRepository struct {
+	db *sql.DB
+}
+
+func NewPasswordResetRepository(db *sql.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+// Save stores a new password reset for the user. Any previous reset of the
+// user that was not used yet stops being valid.
+func (r *passwordResetRepository) Save(userID, hash string, expiresAt time.Time) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "password_resets"."make" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, userID, hash, expiresAt)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+func (r *passwordResetRepository) FetchByHash(hash string) (reset *model.PasswordReset, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "password_resets"."fetch_by_hash" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, hash)
+	reset = new(model.PasswordReset)
+	err = row.Scan(
+		&reset.UUID,
+		&reset.UserUUID,
+		&reset.Hash,
+		&reset.ExpiresAt,
+		&reset.UsedAt,
+		&reset.CreatedAt)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, failure.ErrInvalidResetToken
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	return reset, nil
+}
+
+// Consume marks a password reset as used. It is false if the reset was
+// already used, so that a reset token works only once under concurrent
+// requests.
+func (r *passwordResetRepository) Consume(resetID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "password_resets"."consume" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, resetID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const resetID = "8e1f0c5a-4d7b-4c2e-9a3f-6b5d2e1c0a97"
+
+func TestPasswordResetRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r         = NewPasswordResetRepository(db)
+		query     = regexp.QuoteMeta(`SELECT "password_resets"."make" ($1, $2, $3);`)
+		hash      = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+		expiresAt = time.Now().Add(time.Hour)
+		res       string
+		err       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, hash, expiresAt).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"make"}).
+				AddRow(resetID))
+		res, err = r.Save(userID, hash, expiresAt)
+		assert.NoError(t, err)
+		assert.Equal(t, resetID, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		res, err = r.Save(userID, hash, expiresAt)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Equal(t, "", res)
+	})
+}
+
+func TestPasswordResetRepository_FetchByHash(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewPasswordResetRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "password_resets"."fetch_by_hash" ($1);`)
+		reset = &model.PasswordReset{
+			UUID:      uuid.MustParse(resetID),
+			UserUUID:  uuid.MustParse(userID),
+			Hash:      "hash",
+			ExpiresAt: time.Now().Add(time.Hour),
+			CreatedAt: time.Now(),
+		}
+		columns = []string{"reset_uuid", "user_uuid", "hash", "expires_at", "used_at", "created_at"}
+		res     *model.PasswordReset
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(reset.Hash).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(reset.UUID, reset.UserUUID, reset.Hash, reset.ExpiresAt, reset.UsedAt, reset.CreatedAt))
+		res, err = r.FetchByHash(reset.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, reset, res)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchByHash(reset.Hash)
+		assert.ErrorIs(t, err, failure.ErrInvalidResetToken)
+		assert.Nil(t, res)
+	})
+}
+
+func TestPasswordResetRepository_Consume(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewPasswordResetRepository(db)
+		query = regexp.QuoteMeta(`SELECT "password_resets"."consume" ($1);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(resetID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"consume"}).
+				AddRow(true))
+		res, err = r.Consume(resetID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("already used", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(resetID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"consume"}).
+				AddRow(false))
+		res, err = r.Consume(resetID)
+		assert.NoError(t, err)
+		assert.False(t, res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Consume(resetID)
+		assert.Error(t, err)
+		assert.False(t, res)
+	})
+}
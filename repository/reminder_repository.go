@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+type ReminderRepository interface {
+	FetchDue(before time.Time, limit int64) (reminders []*model.DueReminder, err error)
+	RecordDelivery(taskID string, remindAt time.Time, channel, errMessage string) (ok bool, err error)
+	MarkAsFired(taskID string, remindAt time.Time) (ok bool, err error)
+	FetchDeliveries(ownerID, taskID string, page, rpp int64) (deliveries []*model.ReminderDelivery, err error)
+}
+
+type reminderRepository struct {
+	db *sql.DB
+}
+
+func NewReminderRepository(db *sql.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// FetchDue retrieves, the oldest first, the reminders of the tasks that are
+// due before the given time and have not been marked as fired, along with the
+// channels they were already delivered through.
+func (r *reminderRepository) FetchDue(before time.Time, limit int64) (reminders []*model.DueReminder, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "reminders"."fetch_due" ($1, $2);`
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	reminders = make([]*model.DueReminder, 0)
+	for rows.Next() {
+		var (
+			reminder                    = new(model.DueReminder)
+			headline, email, webhookURL sql.NullString
+		)
+		err = rows.Scan(
+			&reminder.TaskUUID,
+			&reminder.OwnerUUID,
+			&reminder.Title,
+			&headline,
+			&reminder.DueDate,
+			&reminder.RemindAt,
+			&email,
+			&webhookURL,
+			pq.Array(&reminder.DeliveredChannels),
+			&reminder.FailedAttempts)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		reminder.Headline, reminder.Email, reminder.WebhookURL = headline.String, email.String, webhookURL.String
+		reminders = append(reminders, reminder)
+	}
+	return reminders, nil
+}
+
+// RecordDelivery adds an attempt to deliver the reminder of the task at
+// remindAt through channel to the delivery history. An empty errMessage is
+// stored as NULL and means that the reminder was delivered.
+func (r *reminderRepository) RecordDelivery(taskID string, remindAt time.Time, channel, errMessage string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "reminders"."record_delivery" ($1, $2, $3, $4);`
+	var nullableMessage = sql.NullString{String: errMessage, Valid: "" != errMessage}
+	var row = r.db.QueryRowContext(ctx, query, taskID, remindAt, channel, nullableMessage)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// MarkAsFired stops the reminder of the task from being due. Nothing happens
+// if the reminder was set to another time in the meantime.
+func (r *reminderRepository) MarkAsFired(taskID string, remindAt time.Time) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "reminders"."mark_as_fired" ($1, $2);`
+	var row = r.db.QueryRowContext(ctx, query, taskID, remindAt)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// FetchDeliveries retrieves the delivery history of the reminders of a task,
+// the most recent first.
+func (r *reminderRepository) FetchDeliveries(ownerID, taskID string, page, rpp int64) (deliveries []*model.ReminderDelivery, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "reminders"."fetch_deliveries" ($1, $2, $3, $4);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, taskID, page, rpp)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	deliveries = make([]*model.ReminderDelivery, 0)
+	for rows.Next() {
+		var delivery = new(model.ReminderDelivery)
+		err = rows.Scan(
+			&delivery.UUID,
+			&delivery.TaskUUID,
+			&delivery.Channel,
+			&delivery.RemindAt,
+			&delivery.Delivered,
+			&delivery.Error,
+			&delivery.AttemptedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
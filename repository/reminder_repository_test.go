@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const deliveryID = "6a2d4f8c-1e3b-4c5a-8d7f-9b0e2c4a6d81"
+
+func TestReminderRepository_FetchDue(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewReminderRepository(db)
+		query    = regexp.QuoteMeta(`SELECT * FROM "reminders"."fetch_due" ($1, $2);`)
+		columns  = []string{"task_uuid", "owner_uuid", "title", "headline", "due_date", "remind_at", "email", "webhook_url", "delivered_channels", "failed_attempts"}
+		now      = time.Now()
+		dueDate  = now.Add(time.Hour)
+		remindAt = now.Add(-time.Minute)
+		res      []*model.DueReminder
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(now, int64(100)).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(taskID, userID, "title", "headline", dueDate, remindAt, "foo@bar.com", "https://example.com", "{email}", 2).
+				AddRow(taskID, userID, "title", nil, nil, remindAt, nil, nil, "{}", 0))
+		res, err = r.FetchDue(now, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.DueReminder{
+			{
+				TaskUUID:          uuid.MustParse(taskID),
+				OwnerUUID:         uuid.MustParse(userID),
+				Title:             "title",
+				Headline:          "headline",
+				DueDate:           &dueDate,
+				RemindAt:          remindAt,
+				Email:             "foo@bar.com",
+				WebhookURL:        "https://example.com",
+				DeliveredChannels: []string{"email"},
+				FailedAttempts:    2,
+			},
+			{
+				TaskUUID:          uuid.MustParse(taskID),
+				OwnerUUID:         uuid.MustParse(userID),
+				Title:             "title",
+				RemindAt:          remindAt,
+				DeliveredChannels: []string{},
+			},
+		}, res)
+	})
+
+	t.Run("got a scanning error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"task_uuid"}).AddRow(taskID))
+		res, err = r.FetchDue(now, 100)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchDue(now, 100)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestReminderRepository_RecordDelivery(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewReminderRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "reminders"."record_delivery" ($1, $2, $3, $4);`)
+		remindAt = time.Now()
+		res      bool
+		err      error
+	)
+
+	t.Run("delivered", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(taskID, remindAt, "email", sql.NullString{}).
+			WillReturnRows(sqlmock.NewRows([]string{"record_delivery"}).AddRow(true))
+		res, err = r.RecordDelivery(taskID, remindAt, "email", "")
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(taskID, remindAt, "webhook", sql.NullString{String: "boom", Valid: true}).
+			WillReturnRows(sqlmock.NewRows([]string{"record_delivery"}).AddRow(true))
+		res, err = r.RecordDelivery(taskID, remindAt, "webhook", "boom")
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.RecordDelivery(taskID, remindAt, "email", "")
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestReminderRepository_MarkAsFired(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewReminderRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "reminders"."mark_as_fired" ($1, $2);`)
+		remindAt = time.Now()
+		res      bool
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(taskID, remindAt).
+			WillReturnRows(sqlmock.NewRows([]string{"mark_as_fired"}).AddRow(true))
+		res, err = r.MarkAsFired(taskID, remindAt)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.MarkAsFired(taskID, remindAt)
+		assert.Error(t, err)
+		assert.False(t, res)
+	})
+}
+
+func TestReminderRepository_FetchDeliveries(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r           = NewReminderRepository(db)
+		query       = regexp.QuoteMeta(`SELECT * FROM "reminders"."fetch_deliveries" ($1, $2, $3, $4);`)
+		columns     = []string{"delivery_uuid", "task_uuid", "channel", "remind_at", "delivered", "error", "attempted_at"}
+		remindAt    = time.Now()
+		attemptedAt = remindAt.Add(time.Second)
+		message     = "boom"
+		res         []*model.ReminderDelivery
+		err         error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, int64(1), int64(10)).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(deliveryID, taskID, "webhook", remindAt, false, message, attemptedAt).
+				AddRow(deliveryID, taskID, "email", remindAt, true, nil, attemptedAt))
+		res, err = r.FetchDeliveries(userID, taskID, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.ReminderDelivery{
+			{UUID: uuid.MustParse(deliveryID), TaskUUID: uuid.MustParse(taskID), Channel: "webhook", RemindAt: remindAt, Error: &message, AttemptedAt: attemptedAt},
+			{UUID: uuid.MustParse(deliveryID), TaskUUID: uuid.MustParse(taskID), Channel: "email", RemindAt: remindAt, Delivered: true, AttemptedAt: attemptedAt},
+		}, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.FetchDeliveries(userID, taskID, 1, 10)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchDeliveries(userID, taskID, 1, 10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
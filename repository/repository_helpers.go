@@ -23,7 +23,47 @@ func isNonexistentListError(err *pq.Error) bool {
 
 func isNonexistentTaskError(err *pq.Error) bool {
 	return err.Code == "P0001" &&
-		strings.Contains(err.Message, "nonexistent list with UUID")
+		strings.Contains(err.Message, "nonexistent task with UUID")
+}
+
+func isNonexistentStepError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent step with UUID")
+}
+
+func isNonexistentTagError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent tag with UUID")
+}
+
+func isNonexistentAttachmentError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent attachment with UUID")
+}
+
+func isNonexistentNotificationError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent notification with UUID")
+}
+
+func isNonexistentInvitationError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent invitation with UUID")
+}
+
+func isNonexistentMemberError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent member with UUID")
+}
+
+func isNonexistentInviteeError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent user with email")
+}
+
+func isAlreadyMemberError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "is already a member or invited")
 }
 
 func isContextDeadlineError(err error) bool {
@@ -44,3 +84,18 @@ func isDuplicatedEmailError(err *pq.Error) bool {
 	return err.Code == "23505" &&
 		strings.Contains(err.Message, "duplicate key value violates unique constraint \"user_email_key\"")
 }
+
+func isNonexistentCommentError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent comment with UUID")
+}
+
+func isNonexistentRevisionError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent revision with UUID")
+}
+
+func isNonexistentSmartListError(err *pq.Error) bool {
+	return err.Code == "P0001" &&
+		strings.Contains(err.Message, "nonexistent smart list with UUID")
+}
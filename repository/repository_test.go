@@ -2,9 +2,12 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"log"
 	"os"
+	"regexp"
+	"strconv"
 )
 
 func beQuiet() func() {
@@ -22,6 +25,34 @@ func beQuiet() func() {
 	}
 }
 
+// newArityMock creates a mock whose queries must use the placeholders $1 to
+// $n and no other, n being how many arguments they bind, so that a query that
+// uses an argument it does not bind fails as it would against Postgres.
+func newArityMock(n int) (*sql.DB, sqlmock.Sqlmock) {
+	var placeholder = regexp.MustCompile(`\$(\d+)`)
+	var matcher = sqlmock.QueryMatcherFunc(func(_, actualSQL string) error {
+		var used = make(map[string]bool)
+		for _, match := range placeholder.FindAllStringSubmatch(actualSQL, -1) {
+			used[match[1]] = true
+		}
+		for i := 1; i <= n; i++ {
+			if !used[strconv.Itoa(i)] {
+				return fmt.Errorf("the query does not use $%d", i)
+			}
+			delete(used, strconv.Itoa(i))
+		}
+		for number := range used {
+			return fmt.Errorf("the query uses $%s, but only %d arguments are bound", number, n)
+		}
+		return nil
+	})
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	return db, mock
+}
+
 func newMock() (*sql.DB, sqlmock.Sqlmock) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
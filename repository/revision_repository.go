@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"time"
+)
+
+// RevisionRepository keeps the revisions of the tasks. A revision is the state
+// of a task right before one of its changes, taken by the database as a JSON
+// document with the same fields as a model.Task.
+type RevisionRepository interface {
+	Save(ownerID, listID, taskID, actorID string, action types.RevisionAction) (insertedID string, err error)
+	Discard(revisionID string) error
+	Fetch(ownerID, listID, taskID string, page, rpp int64) (revisions []*model.Revision, err error)
+	FetchLast(actorID string, since time.Time) (revision *model.Revision, err error)
+	Restore(ownerID, listID, taskID, revisionID, actorID string) (ok bool, err error)
+	Undo(ownerID, revisionID, actorID string) (ok bool, err error)
+}
+
+type revisionRepository struct {
+	db *sql.DB
+}
+
+func NewRevisionRepository(db *sql.DB) RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+// Save takes a revision of the task before the actor changes it. If listID is
+// an empty string, the task can be in any list of its owner.
+func (r *revisionRepository) Save(ownerID, listID, taskID, actorID string, action types.RevisionAction) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "revisions"."save" ($1, $2, $3, $4, $5);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, actorID, action).Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return "", failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return "", failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+// Discard removes a revision taken before a change that did not happen.
+func (r *revisionRepository) Discard(revisionID string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "revisions"."discard" ($1);`
+	_, err := r.db.ExecContext(ctx, query, revisionID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// Fetch retrieves the revisions of a task, latest first, each with the state
+// of the task right after its change, that is, the state of the next revision
+// or the current state of the task.
+func (r *revisionRepository) Fetch(ownerID, listID, taskID string, page, rpp int64) (revisions []*model.Revision, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "revisions"."fetch" ($1, $2, $3, $4, $5);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, listID, taskID, page, rpp)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	revisions = make([]*model.Revision, 0)
+	for rows.Next() {
+		var revision = new(model.Revision)
+		var before, after []byte
+		err = rows.Scan(
+			&revision.UUID,
+			&revision.TaskUUID,
+			&revision.OwnerUUID,
+			&revision.ListUUID,
+			&revision.ActorUUID,
+			&revision.Action,
+			&before,
+			&after,
+			&revision.IsUndone,
+			&revision.RevisedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		revision.Before, revision.After = before, after
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// FetchLast retrieves the latest revision taken since the given time before a
+// change made by the actor that was not undone yet. Undoing is not a change
+// that can be undone.
+func (r *revisionRepository) FetchLast(actorID string, since time.Time) (revision *model.Revision, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "revisions"."fetch_last" ($1, $2);`
+	revision = new(model.Revision)
+	var before, after []byte
+	err = r.db.QueryRowContext(ctx, query, actorID, since).
+		Scan(
+			&revision.UUID,
+			&revision.TaskUUID,
+			&revision.OwnerUUID,
+			&revision.ListUUID,
+			&revision.ActorUUID,
+			&revision.Action,
+			&before,
+			&after,
+			&revision.IsUndone,
+			&revision.RevisedAt)
+	if nil != err {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, failure.ErrNothingToUndo
+		case errors.As(err, &pqerr):
+			log.Println(failure.PQErrorToString(pqerr))
+		}
+		return nil, err
+	}
+	revision.Before, revision.After = before, after
+	return revision, nil
+}
+
+// Restore brings the task back to the state it had in the revision, after
+// taking a revision of its current state on behalf of the actor.
+func (r *revisionRepository) Restore(ownerID, listID, taskID, revisionID, actorID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "revisions"."restore" ($1, $2, $3, $4, $5);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, revisionID, actorID).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentListError(pqerr):
+				return false, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentRevisionError(pqerr):
+				return false, failure.ErrRevisionNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// Undo restores the revision like Restore does and marks it as undone, so that
+// undoing again reverses the change made before it.
+func (r *revisionRepository) Undo(ownerID, revisionID, actorID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "revisions"."undo" ($1, $2, $3);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, revisionID, actorID).Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentRevisionError(pqerr):
+				return false, failure.ErrRevisionNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const revisionID = "a7c9e1f3-5b2d-4e6a-8c0f-1d3b5f7a9c2e"
+
+var revisionTableColumns = []string{"revision_uuid", "task_uuid", "owner_uuid", "list_uuid", "actor_uuid", "action", "before", "after", "is_undone", "revised_at"}
+
+func TestRevisionRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewRevisionRepository(db)
+		query = regexp.QuoteMeta(`SELECT "revisions"."save" ($1, $2, $3, $4, $5);`)
+		res   string
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, memberID, types.RevisionActionUpdated).
+			WillReturnRows(sqlmock.NewRows([]string{"save"}).AddRow(revisionID))
+		res, err = r.Save(userID, listID, taskID, memberID, types.RevisionActionUpdated)
+		assert.NoError(t, err)
+		assert.Equal(t, revisionID, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Save(userID, "", taskID, userID, types.RevisionActionMoved)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Empty(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(userID, listID, taskID, memberID, types.RevisionActionUpdated)
+		assert.Error(t, err)
+		assert.Empty(t, res)
+	})
+}
+
+func TestRevisionRepository_Discard(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewRevisionRepository(db)
+		query = regexp.QuoteMeta(`SELECT "revisions"."discard" ($1);`)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(revisionID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		assert.NoError(t, r.Discard(revisionID))
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{})
+		assert.Error(t, r.Discard(revisionID))
+	})
+}
+
+func TestRevisionRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewRevisionRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "revisions"."fetch" ($1, $2, $3, $4, $5);`)
+		now   = time.Now()
+		res   []*model.Revision
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, int64(1), int64(10)).
+			WillReturnRows(sqlmock.
+				NewRows(revisionTableColumns).
+				AddRow(revisionID, taskID, userID, listID, memberID, "updated", []byte(`{"title":"old"}`), []byte(`{"title":"new"}`), false, now))
+		res, err = r.Fetch(userID, listID, taskID, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Revision{{
+			UUID:      uuid.MustParse(revisionID),
+			TaskUUID:  uuid.MustParse(taskID),
+			OwnerUUID: uuid.MustParse(userID),
+			ListUUID:  uuid.MustParse(listID),
+			ActorUUID: uuid.MustParse(memberID),
+			Action:    types.RevisionActionUpdated,
+			Before:    json.RawMessage(`{"title":"old"}`),
+			After:     json.RawMessage(`{"title":"new"}`),
+			RevisedAt: now,
+		}}, res)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		res, err = r.Fetch(userID, listID, taskID, 1, 10)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Fetch(userID, listID, taskID, 1, 10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestRevisionRepository_FetchLast(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewRevisionRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "revisions"."fetch_last" ($1, $2);`)
+		now   = time.Now()
+		since = now.Add(-time.Minute)
+		res   *model.Revision
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(memberID, since).
+			WillReturnRows(sqlmock.
+				NewRows(revisionTableColumns).
+				AddRow(revisionID, taskID, userID, listID, memberID, "trashed", []byte(`{"title":"old"}`), nil, false, now))
+		res, err = r.FetchLast(memberID, since)
+		assert.NoError(t, err)
+		assert.Equal(t, uuid.MustParse(revisionID), res.UUID)
+		assert.Equal(t, types.RevisionActionTrashed, res.Action)
+		assert.Equal(t, json.RawMessage(`{"title":"old"}`), res.Before)
+		assert.Nil(t, res.After)
+	})
+
+	t.Run("nothing to undo", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchLast(memberID, since)
+		assert.ErrorIs(t, err, failure.ErrNothingToUndo)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchLast(memberID, since)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestRevisionRepository_Restore(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewRevisionRepository(db)
+		query = regexp.QuoteMeta(`SELECT "revisions"."restore" ($1, $2, $3, $4, $5);`)
+		ok    bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, revisionID, memberID).
+			WillReturnRows(sqlmock.NewRows([]string{"restore"}).AddRow(true))
+		ok, err = r.Restore(userID, listID, taskID, revisionID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent revision with UUID \"" + revisionID + "\""})
+		ok, err = r.Restore(userID, listID, taskID, revisionID, memberID)
+		assert.ErrorIs(t, err, failure.ErrRevisionNotFound)
+		assert.False(t, ok)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		ok, err = r.Restore(userID, listID, taskID, revisionID, memberID)
+		assert.Error(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestRevisionRepository_Undo(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewRevisionRepository(db)
+		query = regexp.QuoteMeta(`SELECT "revisions"."undo" ($1, $2, $3);`)
+		ok    bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, revisionID, memberID).
+			WillReturnRows(sqlmock.NewRows([]string{"undo"}).AddRow(true))
+		ok, err = r.Undo(userID, revisionID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		ok, err = r.Undo(userID, revisionID, memberID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.False(t, ok)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		ok, err = r.Undo(userID, revisionID, memberID)
+		assert.Error(t, err)
+		assert.False(t, ok)
+	})
+}
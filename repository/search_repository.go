@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"html"
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"strings"
+	"time"
+)
+
+// SearchRepository looks for text in everything a user can see, through the
+// full-text indexes of the tasks, steps, lists, groups, tags and comments.
+type SearchRepository interface {
+	Search(userID, query string, entities []types.SearchEntity, limit int64) (hits []*model.SearchHit, err error)
+	CountHits(userID, query string) (facets map[types.SearchEntity]int64, err error)
+}
+
+// The database wraps the matching words of a snippet between these private
+// use characters, which never come out of it otherwise, so that the snippet
+// can be escaped before they are made <mark> tags.
+const (
+	snippetStart = "\uE000"
+	snippetStop  = "\uE001"
+)
+
+type searchRepository struct {
+	db *sql.DB
+}
+
+func NewSearchRepository(db *sql.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// Search retrieves up to limit of the best ranked things of the given kinds,
+// or of any kind if none is given, that match the query, a text search query
+// such as "paint:* & fence:*". The snippet of a hit is HTML, with the matching
+// words wrapped in <mark> tags.
+func (r *searchRepository) Search(userID, query string, entities []types.SearchEntity, limit int64) (hits []*model.SearchHit, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var kinds = make([]string, 0, len(entities))
+	for _, entity := range entities {
+		kinds = append(kinds, string(entity))
+	}
+	var statement = `SELECT * FROM "search"."search" ($1, $2, $3, $4);`
+	rows, err := r.db.QueryContext(ctx, statement, userID, query, pq.Array(kinds), limit)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	hits = make([]*model.SearchHit, 0)
+	for rows.Next() {
+		var hit = new(model.SearchHit)
+		err = rows.Scan(
+			&hit.Entity,
+			&hit.EntityUUID,
+			&hit.ParentUUID,
+			&hit.Title,
+			&hit.Snippet,
+			&hit.Rank)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		hit.Snippet = markSnippet(hit.Snippet)
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// CountHits counts all the things of every kind that match the query.
+func (r *searchRepository) CountHits(userID, query string) (facets map[types.SearchEntity]int64, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var statement = `SELECT * FROM "search"."count_hits" ($1, $2);`
+	rows, err := r.db.QueryContext(ctx, statement, userID, query)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	facets = make(map[types.SearchEntity]int64)
+	for rows.Next() {
+		var entity types.SearchEntity
+		var count int64
+		if err = rows.Scan(&entity, &count); nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		facets[entity] = count
+	}
+	return facets, nil
+}
+
+// markSnippet escapes a snippet as HTML, and only then wraps its matching
+// words in <mark> tags.
+func markSnippet(snippet string) string {
+	return strings.NewReplacer(snippetStart, "<mark>", snippetStop, "</mark>").Replace(html.EscapeString(snippet))
+}
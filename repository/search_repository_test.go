@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+)
+
+func TestSearchRepository_Search(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewSearchRepository(db)
+		query   = regexp.QuoteMeta(`SELECT * FROM "search"."search" ($1, $2, $3, $4);`)
+		columns = []string{"entity", "entity_uuid", "parent_uuid", "title", "snippet", "rank"}
+		list    = uuid.MustParse(listID)
+		res     []*model.SearchHit
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, "paint:* & fence:*", `{"task","list"}`, 1<<10).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("task", taskID, listID, "Paint the fence", "\uE000Paint\uE001 the \uE000fence\uE001", 0.9).
+				AddRow("list", listID, nil, "Garden", "<b>\uE000fence\uE001</b> & \uE000paint\uE001 jobs", 0.4))
+		res, err = r.Search(userID, "paint:* & fence:*", []types.SearchEntity{types.SearchEntityTask, types.SearchEntityList}, 1<<10)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.SearchHit{
+			{
+				Entity:     types.SearchEntityTask,
+				EntityUUID: uuid.MustParse(taskID),
+				ParentUUID: &list,
+				Title:      "Paint the fence",
+				Snippet:    "<mark>Paint</mark> the <mark>fence</mark>",
+				Rank:       0.9,
+			},
+			{
+				Entity:     types.SearchEntityList,
+				EntityUUID: list,
+				Title:      "Garden",
+				Snippet:    "&lt;b&gt;<mark>fence</mark>&lt;/b&gt; &amp; <mark>paint</mark> jobs",
+				Rank:       0.4,
+			},
+		}, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.Search(userID, "paint:*", nil, 1<<10)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Nil(t, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Search(userID, "paint:*", nil, 1<<10)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestSearchRepository_CountHits(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewSearchRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "search"."count_hits" ($1, $2);`)
+		res   map[types.SearchEntity]int64
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, "fence:*").
+			WillReturnRows(sqlmock.NewRows([]string{"entity", "hits"}).
+				AddRow("task", 1500).
+				AddRow("comment", 3))
+		res, err = r.CountHits(userID, "fence:*")
+		assert.NoError(t, err)
+		assert.Equal(t, map[types.SearchEntity]int64{
+			types.SearchEntityTask:    1500,
+			types.SearchEntityComment: 3,
+		}, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.CountHits(userID, "fence:*")
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"time"
+)
+
+// SmartListRepository keeps the smart lists of a user, that is, its saved
+// filters, and retrieves the tasks they are evaluated on.
+type SmartListRepository interface {
+	Save(ownerID string, creation *transfer.SmartListCreation) (insertedID string, err error)
+	FetchByID(ownerID, smartListID string) (smartList *model.SmartList, err error)
+	Fetch(ownerID string) (smartLists []*model.SmartList, err error)
+	FetchCandidates(ownerID string, candidates *types.CandidateFilter, page, rpp int64) (tasks []*model.FilterableTask, err error)
+	Update(ownerID, smartListID string, update *transfer.SmartListUpdate) (ok bool, err error)
+	Delete(ownerID, smartListID string) error
+}
+
+type smartListRepository struct {
+	db *sql.DB
+}
+
+func NewSmartListRepository(db *sql.DB) SmartListRepository {
+	return &smartListRepository{db: db}
+}
+
+func (r *smartListRepository) Save(ownerID string, creation *transfer.SmartListCreation) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "smart_lists"."make" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, creation.Name, creation.Expression)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+func (r *smartListRepository) FetchByID(ownerID, smartListID string) (smartList *model.SmartList, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "smart_lists"."fetch_by_uuid" ($1, $2);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, smartListID)
+	smartList = new(model.SmartList)
+	err = row.Scan(
+		&smartList.UUID,
+		&smartList.OwnerUUID,
+		&smartList.Name,
+		&smartList.Expression,
+		&smartList.CreatedAt,
+		&smartList.UpdatedAt)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, failure.ErrSmartListNotFound
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentSmartListError(pqerr):
+				return nil, failure.ErrSmartListNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	return smartList, nil
+}
+
+// Fetch retrieves all the smart lists of the user, by name.
+func (r *smartListRepository) Fetch(ownerID string) (smartLists []*model.SmartList, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "smart_lists"."fetch" ($1);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	smartLists = make([]*model.SmartList, 0)
+	for rows.Next() {
+		var smartList = new(model.SmartList)
+		err = rows.Scan(
+			&smartList.UUID,
+			&smartList.OwnerUUID,
+			&smartList.Name,
+			&smartList.Expression,
+			&smartList.CreatedAt,
+			&smartList.UpdatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		smartLists = append(smartLists, smartList)
+	}
+	return smartLists, nil
+}
+
+// FetchCandidates retrieves a page of the tasks a smart list of the user can
+// hold: the tasks of the lists it can see that are not in the trash, with the
+// name of their list and of their tags, narrowed down by candidates. They are
+// ordered by due date, soonest first, and then as in their lists.
+func (r *smartListRepository) FetchCandidates(ownerID string, candidates *types.CandidateFilter, page, rpp int64) (tasks []*model.FilterableTask, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "smart_lists"."fetch_candidates" ($1, $2, $3, $4, $5, $6, $7, $8);`
+	rows, err := r.db.QueryContext(ctx, query,
+		ownerID,
+		candidates.Status,
+		candidates.Pinned,
+		candidates.List,
+		candidates.DueFrom,
+		candidates.DueTo,
+		page,
+		rpp)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	tasks = make([]*model.FilterableTask, 0)
+	for rows.Next() {
+		var task = &model.FilterableTask{Task: new(model.Task)}
+		err = rows.Scan(
+			&task.Task.UUID,
+			&task.Task.OwnerUUID,
+			&task.Task.ListUUID,
+			&task.Task.ParentUUID,
+			&task.Task.PositionInList,
+			&task.Task.Title,
+			&task.Task.Headline,
+			&task.Task.Description,
+			&task.Task.Priority,
+			&task.Task.Status,
+			&task.Task.IsPinned,
+			&task.Task.DueDate,
+			&task.Task.RemindAt,
+			&task.Task.Recurrence,
+			&task.Task.CompletedAt,
+			&task.Task.CreatedAt,
+			&task.Task.UpdatedAt,
+			&task.ListName,
+			pq.Array(&task.TagNames))
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (r *smartListRepository) Update(ownerID, smartListID string, update *transfer.SmartListUpdate) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "smart_lists"."update" ($1, $2, $3, $4);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, smartListID, update.Name, update.Expression)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentSmartListError(pqerr):
+				return false, failure.ErrSmartListNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *smartListRepository) Delete(ownerID, smartListID string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "smart_lists"."delete" ($1, $2);`
+	_, err := r.db.ExecContext(ctx, query, ownerID, smartListID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return failure.ErrUserNoLongerExists
+			case isNonexistentSmartListError(pqerr):
+				return failure.ErrSmartListNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
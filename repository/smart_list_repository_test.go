@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const smartListID = "5a7c9e1b-3d5f-4a7c-9e1b-3d5f7a9c1e35"
+
+var smartListTableColumns = []string{"smart_list_uuid", "owner_uuid", "name", "expression", "created_at", "updated_at"}
+
+func TestSmartListRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewSmartListRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "smart_lists"."make" ($1, $2, $3);`)
+		creation = &transfer.SmartListCreation{Name: "Urgent", Expression: "priority>=high"}
+		res      string
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, creation.Name, creation.Expression).
+			WillReturnRows(sqlmock.NewRows([]string{"make"}).AddRow(smartListID))
+		res, err = r.Save(userID, creation)
+		assert.NoError(t, err)
+		assert.Equal(t, smartListID, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.Save(userID, creation)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Equal(t, "", res)
+	})
+}
+
+func TestSmartListRepository_FetchByID(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewSmartListRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "smart_lists"."fetch_by_uuid" ($1, $2);`)
+		now   = time.Now()
+		res   *model.SmartList
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, smartListID).
+			WillReturnRows(sqlmock.NewRows(smartListTableColumns).AddRow(smartListID, userID, "Urgent", "priority>=high", now, now))
+		res, err = r.FetchByID(userID, smartListID)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.SmartList{
+			UUID:       uuid.MustParse(smartListID),
+			OwnerUUID:  uuid.MustParse(userID),
+			Name:       "Urgent",
+			Expression: "priority>=high",
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}, res)
+	})
+
+	t.Run("smart list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchByID(userID, smartListID)
+		assert.ErrorIs(t, err, failure.ErrSmartListNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestSmartListRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewSmartListRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "smart_lists"."fetch" ($1);`)
+		now   = time.Now()
+		res   []*model.SmartList
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(smartListTableColumns).
+				AddRow(smartListID, userID, "Urgent", "priority>=high", now, now).
+				AddRow(uuid.NewString(), userID, "Overdue", "overdue", now, now))
+		res, err = r.Fetch(userID)
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Fetch(userID)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestSmartListRepository_FetchCandidates(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewSmartListRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "smart_lists"."fetch_candidates" ($1, $2, $3, $4, $5, $6, $7, $8);`)
+		task  = &model.Task{
+			UUID:      uuid.MustParse(taskID),
+			OwnerUUID: uuid.MustParse(userID),
+			ListUUID:  uuid.MustParse(listID),
+			Title:     "Buy milk",
+			Priority:  types.TaskPriorityUrgent,
+			Status:    types.TaskStatusIncomplete,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		status     = types.TaskStatusIncomplete
+		from       = time.Now()
+		candidates = &types.CandidateFilter{Status: &status, DueFrom: &from}
+		res        []*model.FilterableTask
+		err        error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, string(status), nil, nil, from, nil, 1, 256).
+			WillReturnRows(sqlmock.
+				NewRows(append(taskTableColumns, "list_name", "tag_names")).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt, "Groceries", "{errands,home}"))
+		res, err = r.FetchCandidates(userID, candidates, 1, 256)
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.FilterableTask{{Task: task, ListName: "Groceries", TagNames: []string{"errands", "home"}}}, res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchCandidates(userID, candidates, 1, 256)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestSmartListRepository_Update(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r      = NewSmartListRepository(db)
+		query  = regexp.QuoteMeta(`SELECT "smart_lists"."update" ($1, $2, $3, $4);`)
+		update = &transfer.SmartListUpdate{Expression: "priority=urgent"}
+		ok     bool
+		err    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, smartListID, update.Name, update.Expression).
+			WillReturnRows(sqlmock.NewRows([]string{"update"}).AddRow(true))
+		ok, err = r.Update(userID, smartListID, update)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("smart list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent smart list with UUID \"" + smartListID + "\""})
+		ok, err = r.Update(userID, smartListID, update)
+		assert.ErrorIs(t, err, failure.ErrSmartListNotFound)
+		assert.False(t, ok)
+	})
+}
+
+func TestSmartListRepository_Delete(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewSmartListRepository(db)
+		query = regexp.QuoteMeta(`SELECT "smart_lists"."delete" ($1, $2);`)
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(userID, smartListID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = r.Delete(userID, smartListID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{})
+		err = r.Delete(userID, smartListID)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"time"
+)
+
+type StepRepository interface {
+	Save(ownerID, taskID string, creation *transfer.StepCreation) (insertedID string, err error)
+	Fetch(ownerID, taskID string) (steps []*model.Step, err error)
+	Update(ownerID, taskID, stepID string, update *transfer.StepUpdate) (ok bool, err error)
+	Accomplish(ownerID, taskID, stepID string) (ok bool, err error)
+	Unaccomplish(ownerID, taskID, stepID string) (ok bool, err error)
+	Reorder(ownerID, taskID, stepID string, position uint64) (ok bool, err error)
+	Delete(ownerID, taskID, stepID string) error
+}
+
+type stepRepository struct {
+	db *sql.DB
+}
+
+func NewStepRepository(db *sql.DB) StepRepository {
+	return &stepRepository{db: db}
+}
+
+func (r *stepRepository) Save(ownerID, taskID string, creation *transfer.StepCreation) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "steps"."make" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, creation.Description)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return "", failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+func (r *stepRepository) Fetch(ownerID, taskID string) (steps []*model.Step, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "steps"."fetch" ($1, $2);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, taskID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	steps = make([]*model.Step, 0)
+	for rows.Next() {
+		var step = new(model.Step)
+		err = rows.Scan(
+			&step.UUID,
+			&step.TaskUUID,
+			&step.Order,
+			&step.Description,
+			&step.CompletedAt,
+			&step.CreatedAt,
+			&step.UpdatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func (r *stepRepository) Update(ownerID, taskID, stepID string, update *transfer.StepUpdate) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "steps"."update" ($1, $2, $3, $4);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, stepID, update.Description)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentStepError(pqerr):
+				return false, failure.ErrStepNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *stepRepository) Accomplish(ownerID, taskID, stepID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "steps"."accomplish" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, stepID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentStepError(pqerr):
+				return false, failure.ErrStepNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *stepRepository) Unaccomplish(ownerID, taskID, stepID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "steps"."unaccomplish" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, stepID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentStepError(pqerr):
+				return false, failure.ErrStepNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *stepRepository) Reorder(ownerID, taskID, stepID string, position uint64) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "steps"."reorder" ($1, $2, $3, $4);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, stepID, position)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentStepError(pqerr):
+				return false, failure.ErrStepNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *stepRepository) Delete(ownerID, taskID, stepID string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "steps"."delete" ($1, $2, $3);`
+	_, err := r.db.ExecContext(ctx, query, ownerID, taskID, stepID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return failure.ErrTaskNotFound
+			case isNonexistentStepError(pqerr):
+				return failure.ErrStepNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const stepID = "0b9e3c1c-54a2-4bd5-9a8f-6a1c5e2f1d7e"
+
+func TestStepRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewStepRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "steps"."make" ($1, $2, $3);`)
+		creation = &transfer.StepCreation{Description: "step description"}
+		res      string
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, creation.Description).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"make"}).
+				AddRow(stepID))
+		res, err = r.Save(userID, taskID, creation)
+		assert.Equal(t, stepID, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent user error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, creation.Description).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.Save(userID, taskID, creation)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(userID, taskID, creation)
+		assert.Error(t, err)
+		assert.Equal(t, "", res)
+	})
+}
+
+var stepTableColumns = []string{
+	"step_uuid",
+	"task_uuid",
+	"order",
+	"description",
+	"completed_at",
+	"created_at",
+	"updated_at"}
+
+func TestStepRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewStepRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "steps"."fetch" ($1, $2);`)
+		res   []*model.Step
+		err   error
+		now   = time.Now()
+		step  = &model.Step{
+			UUID:        uuid.MustParse(stepID),
+			TaskUUID:    uuid.MustParse(taskID),
+			Order:       1,
+			Description: "step description",
+			CompletedAt: &now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		steps = []*model.Step{step, step}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID).
+			WillReturnRows(sqlmock.
+				NewRows(stepTableColumns).
+				AddRow(step.UUID, step.TaskUUID, step.Order, step.Description, step.CompletedAt, step.CreatedAt, step.UpdatedAt).
+				AddRow(step.UUID, step.TaskUUID, step.Order, step.Description, step.CompletedAt, step.CreatedAt, step.UpdatedAt))
+		res, err = r.Fetch(userID, taskID)
+		assert.Equal(t, steps, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent task error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.Fetch(userID, taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Fetch(userID, taskID)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestStepRepository_Update(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r      = NewStepRepository(db)
+		query  = regexp.QuoteMeta(`SELECT "steps"."update" ($1, $2, $3, $4);`)
+		update = &transfer.StepUpdate{Description: "new description"}
+		res    bool
+		err    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, stepID, update.Description).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"update"}).
+				AddRow(true))
+		res, err = r.Update(userID, taskID, stepID, update)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent step error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, stepID, update.Description).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent step with UUID \"" + stepID + "\""})
+		res, err = r.Update(userID, taskID, stepID, update)
+		assert.ErrorIs(t, err, failure.ErrStepNotFound)
+		assert.False(t, res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Update(userID, taskID, stepID, update)
+		assert.False(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestStepRepository_Accomplish(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewStepRepository(db)
+		query = regexp.QuoteMeta(`SELECT "steps"."accomplish" ($1, $2, $3);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, stepID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"accomplish"}).
+				AddRow(true))
+		res, err = r.Accomplish(userID, taskID, stepID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Accomplish(userID, taskID, stepID)
+		assert.False(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestStepRepository_Unaccomplish(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewStepRepository(db)
+		query = regexp.QuoteMeta(`SELECT "steps"."unaccomplish" ($1, $2, $3);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, stepID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"unaccomplish"}).
+				AddRow(true))
+		res, err = r.Unaccomplish(userID, taskID, stepID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Unaccomplish(userID, taskID, stepID)
+		assert.False(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestStepRepository_Reorder(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewStepRepository(db)
+		query = regexp.QuoteMeta(`SELECT "steps"."reorder" ($1, $2, $3, $4);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, stepID, 3).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"reorder"}).
+				AddRow(true))
+		res, err = r.Reorder(userID, taskID, stepID, 3)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Reorder(userID, taskID, stepID, 3)
+		assert.False(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestStepRepository_Delete(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewStepRepository(db)
+		query = regexp.QuoteMeta(`SELECT "steps"."delete" ($1, $2, $3);`)
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(userID, taskID, stepID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = r.Delete(userID, taskID, stepID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent step error", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(userID, taskID, stepID).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent step with UUID \"" + stepID + "\""})
+		err = r.Delete(userID, taskID, stepID)
+		assert.ErrorIs(t, err, failure.ErrStepNotFound)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{})
+		err = r.Delete(userID, taskID, stepID)
+		assert.Error(t, err)
+	})
+}
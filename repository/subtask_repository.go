@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"time"
+)
+
+// SubtaskRepository keeps the tasks that are nested in other tasks of the
+// same list. A subtask is a task like any other, with a parent task.
+type SubtaskRepository interface {
+	Save(ownerID, listID, parentID string, creation *transfer.TaskCreation) (insertedID string, err error)
+	FetchTree(ownerID, listID, taskID string) (tasks []*model.Task, err error)
+	FetchDepth(ownerID, listID, taskID string) (depth int, err error)
+	PromoteStep(ownerID, listID, taskID, stepID string) (insertedID string, err error)
+}
+
+type subtaskRepository struct {
+	db *sql.DB
+}
+
+func NewSubtaskRepository(db *sql.DB) SubtaskRepository {
+	return &subtaskRepository{db: db}
+}
+
+// Save makes a new task in the list, nested in the parent task.
+func (r *subtaskRepository) Save(ownerID, listID, parentID string, creation *transfer.TaskCreation) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "subtasks"."make" ($1, $2, $3, $4, $5, $6, $7, $8);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, parentID,
+		creation.Title, creation.Headline, creation.Description, creation.Priority, creation.Status)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return "", failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return "", failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+// FetchTree retrieves the task and all of the subtasks below it, every parent
+// before its subtasks, and the subtasks of a parent in the order of the list.
+func (r *subtaskRepository) FetchTree(ownerID, listID, taskID string) (tasks []*model.Task, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, listID, taskID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return nil, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	tasks = make([]*model.Task, 0)
+	for rows.Next() {
+		var task = new(model.Task)
+		err = rows.Scan(
+			&task.UUID,
+			&task.OwnerUUID,
+			&task.ListUUID,
+			&task.ParentUUID,
+			&task.PositionInList,
+			&task.Title,
+			&task.Headline,
+			&task.Description,
+			&task.Priority,
+			&task.Status,
+			&task.IsPinned,
+			&task.DueDate,
+			&task.RemindAt,
+			&task.Recurrence,
+			&task.CompletedAt,
+			&task.CreatedAt,
+			&task.UpdatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// FetchDepth counts the tasks above the task, that is, 0 for a task that is
+// not a subtask.
+func (r *subtaskRepository) FetchDepth(ownerID, listID, taskID string) (depth int, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "subtasks"."depth" ($1, $2, $3);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID).Scan(&depth)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return 0, failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return 0, failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return 0, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return 0, err
+	}
+	return depth, nil
+}
+
+// PromoteStep turns a step of the task into a subtask of it, in one
+// transaction. The subtask is titled after the step and is finished if the
+// step was accomplished; the step is removed.
+func (r *subtaskRepository) PromoteStep(ownerID, listID, taskID, stepID string) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "subtasks"."promote_step" ($1, $2, $3, $4);`
+	err = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, stepID).Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return "", failure.ErrListNotFound
+			case isNonexistentTaskError(pqerr):
+				return "", failure.ErrTaskNotFound
+			case isNonexistentStepError(pqerr):
+				return "", failure.ErrStepNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
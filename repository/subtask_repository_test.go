@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const parentID = "e3a5c7e9-1b2d-4f6a-8c0e-3a5c7e9b1d24"
+
+func TestSubtaskRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewSubtaskRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "subtasks"."make" ($1, $2, $3, $4, $5, $6, $7, $8);`)
+		creation = &transfer.TaskCreation{Title: "Buy paint", Priority: types.TaskPriorityMedium, Status: types.TaskStatusIncomplete}
+		res      string
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, parentID, creation.Title, creation.Headline, creation.Description, creation.Priority, creation.Status).
+			WillReturnRows(sqlmock.NewRows([]string{"make"}).AddRow(taskID))
+		res, err = r.Save(userID, listID, parentID, creation)
+		assert.NoError(t, err)
+		assert.Equal(t, taskID, res)
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + parentID + "\""})
+		res, err = r.Save(userID, listID, parentID, creation)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(userID, listID, parentID, creation)
+		assert.Error(t, err)
+		assert.Equal(t, "", res)
+	})
+}
+
+func TestSubtaskRepository_FetchTree(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r      = NewSubtaskRepository(db)
+		query  = regexp.QuoteMeta(`SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`)
+		parent = uuid.MustParse(taskID)
+		task   = &model.Task{
+			UUID:       uuid.MustParse(parentID),
+			OwnerUUID:  uuid.MustParse(userID),
+			ListUUID:   uuid.MustParse(listID),
+			ParentUUID: &parent,
+			Title:      "Buy paint",
+			Priority:   types.TaskPriorityMedium,
+			Status:     types.TaskStatusIncomplete,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}
+		res []*model.Task
+		err error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID).
+			WillReturnRows(sqlmock.
+				NewRows(taskTableColumns).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+		res, err = r.FetchTree(userID, listID, taskID)
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+		assert.Equal(t, &parent, res[0].ParentUUID)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.FetchTree(userID, listID, taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestSubtaskRepository_FetchDepth(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewSubtaskRepository(db)
+		query = regexp.QuoteMeta(`SELECT "subtasks"."depth" ($1, $2, $3);`)
+		res   int
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID).
+			WillReturnRows(sqlmock.NewRows([]string{"depth"}).AddRow(2))
+		res, err = r.FetchDepth(userID, listID, taskID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, res)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent list with UUID \"" + listID + "\""})
+		res, err = r.FetchDepth(userID, listID, taskID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Equal(t, 0, res)
+	})
+}
+
+func TestSubtaskRepository_PromoteStep(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewSubtaskRepository(db)
+		query = regexp.QuoteMeta(`SELECT "subtasks"."promote_step" ($1, $2, $3, $4);`)
+		res   string
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, stepID).
+			WillReturnRows(sqlmock.NewRows([]string{"promote_step"}).AddRow(parentID))
+		res, err = r.PromoteStep(userID, listID, taskID, stepID)
+		assert.NoError(t, err)
+		assert.Equal(t, parentID, res)
+	})
+
+	t.Run("step not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent step with UUID \"" + stepID + "\""})
+		res, err = r.PromoteStep(userID, listID, taskID, stepID)
+		assert.ErrorIs(t, err, failure.ErrStepNotFound)
+		assert.Equal(t, "", res)
+	})
+}
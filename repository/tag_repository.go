@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"time"
+)
+
+type TagRepository interface {
+	Save(ownerID string, creation *transfer.TagCreation) (insertedID string, err error)
+	FetchByID(ownerID, tagID string) (tag *model.Tag, err error)
+	Fetch(ownerID string, page, rpp int64, needle, sortExpr string) (tags []*model.Tag, err error)
+	FetchFromTask(ownerID, taskID string) (tags []*model.Tag, err error)
+	Update(ownerID, tagID string, update *transfer.TagUpdate) (ok bool, err error)
+	Attach(ownerID, taskID, tagID string) (ok bool, err error)
+	Detach(ownerID, taskID, tagID string) (ok bool, err error)
+	Delete(ownerID, tagID string) error
+}
+
+type tagRepository struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Save(ownerID string, creation *transfer.TagCreation) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tags"."make" ($1, $2, $3, $4);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, creation.Name, creation.Description, creation.Color)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+func (r *tagRepository) FetchByID(ownerID, tagID string) (tag *model.Tag, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "tags"."fetch_by_uuid" ($1, $2);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, tagID)
+	tag = new(model.Tag)
+	err = row.Scan(
+		&tag.UUID,
+		&tag.OwnerUUID,
+		&tag.Name,
+		&tag.Description,
+		&tag.Color,
+		&tag.CreatedAt,
+		&tag.UpdatedAt)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, failure.ErrTagNotFound
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentTagError(pqerr):
+				return nil, failure.ErrTagNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (r *tagRepository) Fetch(ownerID string, page, rpp int64, needle, sortExpr string) (tags []*model.Tag, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "tags"."fetch" ($1, $2, $3, $4, $5);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, page, rpp, needle, sortExpr)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTags(rows)
+}
+
+func (r *tagRepository) FetchFromTask(ownerID, taskID string) (tags []*model.Tag, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "tags"."fetch_from_task" ($1, $2);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, taskID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return nil, failure.ErrTaskNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTags(rows)
+}
+
+// scanTags reads every tag in rows.
+func scanTags(rows *sql.Rows) (tags []*model.Tag, err error) {
+	tags = make([]*model.Tag, 0)
+	for rows.Next() {
+		var tag = new(model.Tag)
+		err = rows.Scan(
+			&tag.UUID,
+			&tag.OwnerUUID,
+			&tag.Name,
+			&tag.Description,
+			&tag.Color,
+			&tag.CreatedAt,
+			&tag.UpdatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) Update(ownerID, tagID string, update *transfer.TagUpdate) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tags"."update" ($1, $2, $3, $4, $5);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, tagID, update.Name, update.Description, update.Color)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentTagError(pqerr):
+				return false, failure.ErrTagNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *tagRepository) Attach(ownerID, taskID, tagID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tags"."attach" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, tagID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentTagError(pqerr):
+				return false, failure.ErrTagNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *tagRepository) Detach(ownerID, taskID, tagID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tags"."detach" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, tagID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			case isNonexistentTaskError(pqerr):
+				return false, failure.ErrTaskNotFound
+			case isNonexistentTagError(pqerr):
+				return false, failure.ErrTagNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+func (r *tagRepository) Delete(ownerID, tagID string) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tags"."delete" ($1, $2);`
+	_, err := r.db.ExecContext(ctx, query, ownerID, tagID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return failure.ErrUserNoLongerExists
+			case isNonexistentTagError(pqerr):
+				return failure.ErrTagNotFound
+			}
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
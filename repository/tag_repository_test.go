@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const tagID = "5f3c2a8e-3f0b-4f3e-8a43-0d2c9a4b1e6f"
+
+var tagTableColumns = []string{
+	"tag_uuid",
+	"owner_uuid",
+	"name",
+	"description",
+	"color",
+	"created_at",
+	"updated_at"}
+
+func TestTagRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r        = NewTagRepository(db)
+		query    = regexp.QuoteMeta(`SELECT "tags"."make" ($1, $2, $3, $4);`)
+		creation = &transfer.TagCreation{Name: "work", Description: "work stuff", Color: "#ff0000"}
+		res      string
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, creation.Name, creation.Description, creation.Color).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"make"}).
+				AddRow(tagID))
+		res, err = r.Save(userID, creation)
+		assert.Equal(t, tagID, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent user error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+		res, err = r.Save(userID, creation)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Equal(t, "", res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Save(userID, creation)
+		assert.Error(t, err)
+		assert.Equal(t, "", res)
+	})
+}
+
+func TestTagRepository_FetchByID(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r           = NewTagRepository(db)
+		query       = regexp.QuoteMeta(`SELECT * FROM "tags"."fetch_by_uuid" ($1, $2);`)
+		res         *model.Tag
+		err         error
+		description = "work stuff"
+		tag         = &model.Tag{
+			UUID:        uuid.MustParse(tagID),
+			OwnerUUID:   uuid.MustParse(userID),
+			Name:        "work",
+			Description: &description,
+			Color:       "#ff0000",
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, tagID).
+			WillReturnRows(sqlmock.
+				NewRows(tagTableColumns).
+				AddRow(tag.UUID, tag.OwnerUUID, tag.Name, tag.Description, tag.Color, tag.CreatedAt, tag.UpdatedAt))
+		res, err = r.FetchByID(userID, tagID)
+		assert.Equal(t, tag, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, tagID).
+			WillReturnRows(sqlmock.NewRows(tagTableColumns))
+		res, err = r.FetchByID(userID, tagID)
+		assert.ErrorIs(t, err, failure.ErrTagNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchByID(userID, tagID)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestTagRepository_Fetch(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTagRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "tags"."fetch" ($1, $2, $3, $4, $5);`)
+		res   []*model.Tag
+		err   error
+		tag   = &model.Tag{
+			UUID:      uuid.MustParse(tagID),
+			OwnerUUID: uuid.MustParse(userID),
+			Name:      "work",
+			Color:     "#ff0000",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, 1, 10, "", "").
+			WillReturnRows(sqlmock.
+				NewRows(tagTableColumns).
+				AddRow(tag.UUID, tag.OwnerUUID, tag.Name, tag.Description, tag.Color, tag.CreatedAt, tag.UpdatedAt).
+				AddRow(tag.UUID, tag.OwnerUUID, tag.Name, tag.Description, tag.Color, tag.CreatedAt, tag.UpdatedAt))
+		res, err = r.Fetch(userID, 1, 10, "", "")
+		assert.Equal(t, []*model.Tag{tag, tag}, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Fetch(userID, 1, 10, "", "")
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestTagRepository_FetchFromTask(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTagRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "tags"."fetch_from_task" ($1, $2);`)
+		res   []*model.Tag
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID).
+			WillReturnRows(sqlmock.NewRows(tagTableColumns))
+		res, err = r.FetchFromTask(userID, taskID)
+		assert.Equal(t, []*model.Tag{}, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent task error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID \"" + taskID + "\""})
+		res, err = r.FetchFromTask(userID, taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestTagRepository_Update(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r      = NewTagRepository(db)
+		query  = regexp.QuoteMeta(`SELECT "tags"."update" ($1, $2, $3, $4, $5);`)
+		update = &transfer.TagUpdate{Name: "home", Color: "#00ff00"}
+		res    bool
+		err    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, tagID, update.Name, update.Description, update.Color).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"update"}).
+				AddRow(true))
+		res, err = r.Update(userID, tagID, update)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent tag error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent tag with UUID \"" + tagID + "\""})
+		res, err = r.Update(userID, tagID, update)
+		assert.ErrorIs(t, err, failure.ErrTagNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestTagRepository_Attach(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTagRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tags"."attach" ($1, $2, $3);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, tagID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"attach"}).
+				AddRow(true))
+		res, err = r.Attach(userID, taskID, tagID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("got a nonexistent tag error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent tag with UUID \"" + tagID + "\""})
+		res, err = r.Attach(userID, taskID, tagID)
+		assert.ErrorIs(t, err, failure.ErrTagNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestTagRepository_Detach(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTagRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tags"."detach" ($1, $2, $3);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, taskID, tagID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"detach"}).
+				AddRow(true))
+		res, err = r.Detach(userID, taskID, tagID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Detach(userID, taskID, tagID)
+		assert.False(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestTagRepository_Delete(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTagRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tags"."delete" ($1, $2);`)
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(userID, tagID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		err = r.Delete(userID, tagID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{})
+		err = r.Delete(userID, tagID)
+		assert.Error(t, err)
+	})
+}
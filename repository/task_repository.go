@@ -18,15 +18,22 @@ type TaskRepository interface {
 	Save(ownerID, taskID string, creation *transfer.TaskCreation) (insertedID string, err error)
 	Duplicate(ownerID, taskID string) (replicaID string, err error)
 	FetchByID(ownerID, listID, taskID string) (task *model.Task, err error)
-	Fetch(ownerID, listID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
-	FetchFromToday(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
-	FetchFromTomorrow(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
+	Fetch(ownerID, listID string, page, rpp int64, needle, sortExpr string, tagIDs []string, matchAllTags bool, assigneeID string) (tasks []*model.Task, err error)
+	FetchFromToday(ownerID string, page, rpp int64, needle, sortExpr string, tagIDs []string, matchAllTags bool) (tasks []*model.Task, err error)
+	FetchFromTomorrow(ownerID string, page, rpp int64, needle, sortExpr string, tagIDs []string, matchAllTags bool) (tasks []*model.Task, err error)
 	FetchFromDeferred(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
+	FetchAll(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
+	FetchCompleted(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
+	FetchArchived(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
+	FetchTrashed(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error)
+	Locate(taskID string) (listID string, err error)
 	Update(ownerID, listID, taskID string, update *transfer.TaskUpdate) (ok bool, err error)
 	Reorder(ownerID, listID, taskID string, position uint64) (ok bool, err error)
 	SetReminder(ownerID, listID, taskID string, remindAt time.Time) (ok bool, err error)
 	SetPriority(ownerID, listID, taskID string, priority types.TaskPriority) (ok bool, err error)
 	SetDueDate(ownerID, listID, taskID string, dueDate time.Time) (ok bool, err error)
+	SetRecurrence(ownerID, listID, taskID string, rule *types.RecurrenceRule) (ok bool, err error)
+	SaveOccurrence(ownerID, listID, taskID string, dueDate time.Time, remindAt *time.Time, rule *types.RecurrenceRule) (insertedID string, err error)
 	Complete(ownerID, listID, taskID string) (ok bool, err error)
 	Resume(ownerID, listID, taskID string) (ok bool, err error)
 	Pin(ownerID, listID, taskID string) (ok bool, err error)
@@ -38,6 +45,8 @@ type TaskRepository interface {
 	Trash(ownerID, listID, taskID string) (ok bool, err error)
 	RestoreFromTrash(ownerID, listID, taskID string) (ok bool, err error)
 	Delete(ownerID, listID, taskID string) error
+	FetchRolloverCandidates() (candidates []*model.RolloverCandidate, err error)
+	RollOver(ownerID string, day time.Time) (ok bool, err error)
 }
 
 type taskRepository struct {
@@ -52,9 +61,14 @@ func (r *taskRepository) Save(ownerID, listID string, creation *transfer.TaskCre
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	var query = `SELECT "tasks"."make" ($1, $2, $3);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, listID,
-		fmt.Sprintf("ROW('%s', '%s', '%s', '%s', '%s', %s, %s)",
-			creation.Title, creation.Headline, creation.Description, creation.Priority, creation.Status, "NULL", "NULL"))
+	var creationRow = fmt.Sprintf("ROW('%s', '%s', '%s', '%s', '%s', %s, %s)",
+		creation.Title, creation.Headline, creation.Description, creation.Priority, creation.Status, "NULL", "NULL")
+	var row *sql.Row
+	if "" != listID {
+		row = r.db.QueryRowContext(ctx, query, ownerID, listID, creationRow)
+	} else {
+		row = r.db.QueryRowContext(ctx, query, ownerID, nil, creationRow)
+	}
 	err = row.Scan(&insertedID)
 	if nil != err {
 		var pqerr *pq.Error
@@ -110,6 +124,7 @@ func (r *taskRepository) FetchByID(ownerID, listID, taskID string) (task *model.
 		&task.UUID,
 		&task.OwnerUUID,
 		&task.ListUUID,
+		&task.ParentUUID,
 		&task.PositionInList,
 		&task.Title,
 		&task.Headline,
@@ -119,6 +134,7 @@ func (r *taskRepository) FetchByID(ownerID, listID, taskID string) (task *model.
 		&task.IsPinned,
 		&task.DueDate,
 		&task.RemindAt,
+		&task.Recurrence,
 		&task.CompletedAt,
 		&task.CreatedAt,
 		&task.UpdatedAt)
@@ -143,11 +159,20 @@ func (r *taskRepository) FetchByID(ownerID, listID, taskID string) (task *model.
 	return task, nil
 }
 
-func (r *taskRepository) Fetch(ownerID, listID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+// Fetch retrieves the tasks of a list. If assigneeID is not empty, only the
+// tasks assigned to that user are retrieved.
+func (r *taskRepository) Fetch(
+	ownerID, listID string,
+	page, rpp int64,
+	needle, sortExpr string,
+	tagIDs []string,
+	matchAllTags bool,
+	assigneeID string,
+) (tasks []*model.Task, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."fetch" ($1, $2, $3, $4, $5, $6);`
-	rows, err := r.db.QueryContext(ctx, query, ownerID, listID, page, rpp, needle, sortExpr)
+	var query = `SELECT "tasks"."fetch" ($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, listID, page, rpp, needle, sortExpr, pq.Array(tagIDs), matchAllTags, assigneeID)
 	if nil != err {
 		var pqerr *pq.Error
 		if errors.As(err, &pqerr) {
@@ -173,6 +198,7 @@ func (r *taskRepository) Fetch(ownerID, listID string, page, rpp int64, needle,
 			&task.UUID,
 			&task.OwnerUUID,
 			&task.ListUUID,
+			&task.ParentUUID,
 			&task.PositionInList,
 			&task.Title,
 			&task.Headline,
@@ -182,6 +208,7 @@ func (r *taskRepository) Fetch(ownerID, listID string, page, rpp int64, needle,
 			&task.IsPinned,
 			&task.DueDate,
 			&task.RemindAt,
+			&task.Recurrence,
 			&task.CompletedAt,
 			&task.CreatedAt,
 			&task.UpdatedAt)
@@ -193,11 +220,17 @@ func (r *taskRepository) Fetch(ownerID, listID string, page, rpp int64, needle,
 	return tasks, nil
 }
 
-func (r *taskRepository) FetchFromToday(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+func (r *taskRepository) FetchFromToday(
+	ownerID string,
+	page, rpp int64,
+	needle, sortExpr string,
+	tagIDs []string,
+	matchAllTags bool,
+) (tasks []*model.Task, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."fetch_from_today_list" ($1, $2, $3, $4, $5);`
-	rows, err := r.db.QueryContext(ctx, query, ownerID, page, rpp, needle, sortExpr)
+	var query = `SELECT "tasks"."fetch_from_today_list" ($1, $2, $3, $4, $5, $6, $7);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, page, rpp, needle, sortExpr, pq.Array(tagIDs), matchAllTags)
 	if nil != err {
 		var pqerr *pq.Error
 		if errors.As(err, &pqerr) {
@@ -223,6 +256,7 @@ func (r *taskRepository) FetchFromToday(ownerID string, page, rpp int64, needle,
 			&task.UUID,
 			&task.OwnerUUID,
 			&task.ListUUID,
+			&task.ParentUUID,
 			&task.PositionInList,
 			&task.Title,
 			&task.Headline,
@@ -232,6 +266,7 @@ func (r *taskRepository) FetchFromToday(ownerID string, page, rpp int64, needle,
 			&task.IsPinned,
 			&task.DueDate,
 			&task.RemindAt,
+			&task.Recurrence,
 			&task.CompletedAt,
 			&task.CreatedAt,
 			&task.UpdatedAt)
@@ -243,11 +278,17 @@ func (r *taskRepository) FetchFromToday(ownerID string, page, rpp int64, needle,
 	return tasks, nil
 }
 
-func (r *taskRepository) FetchFromTomorrow(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+func (r *taskRepository) FetchFromTomorrow(
+	ownerID string,
+	page, rpp int64,
+	needle, sortExpr string,
+	tagIDs []string,
+	matchAllTags bool,
+) (tasks []*model.Task, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."fetch_from_tomorrow_list" ($1, $2, $3, $4, $5);`
-	rows, err := r.db.QueryContext(ctx, query, ownerID, page, rpp, needle, sortExpr)
+	var query = `SELECT "tasks"."fetch_from_tomorrow_list" ($1, $2, $3, $4, $5, $6, $7);`
+	rows, err := r.db.QueryContext(ctx, query, ownerID, page, rpp, needle, sortExpr, pq.Array(tagIDs), matchAllTags)
 	if nil != err {
 		var pqerr *pq.Error
 		if errors.As(err, &pqerr) {
@@ -273,6 +314,7 @@ func (r *taskRepository) FetchFromTomorrow(ownerID string, page, rpp int64, need
 			&task.UUID,
 			&task.OwnerUUID,
 			&task.ListUUID,
+			&task.ParentUUID,
 			&task.PositionInList,
 			&task.Title,
 			&task.Headline,
@@ -282,6 +324,7 @@ func (r *taskRepository) FetchFromTomorrow(ownerID string, page, rpp int64, need
 			&task.IsPinned,
 			&task.DueDate,
 			&task.RemindAt,
+			&task.Recurrence,
 			&task.CompletedAt,
 			&task.CreatedAt,
 			&task.UpdatedAt)
@@ -323,6 +366,7 @@ func (r *taskRepository) FetchFromDeferred(ownerID string, page, rpp int64, need
 			&task.UUID,
 			&task.OwnerUUID,
 			&task.ListUUID,
+			&task.ParentUUID,
 			&task.PositionInList,
 			&task.Title,
 			&task.Headline,
@@ -332,6 +376,7 @@ func (r *taskRepository) FetchFromDeferred(ownerID string, page, rpp int64, need
 			&task.IsPinned,
 			&task.DueDate,
 			&task.RemindAt,
+			&task.Recurrence,
 			&task.CompletedAt,
 			&task.CreatedAt,
 			&task.UpdatedAt)
@@ -343,6 +388,107 @@ func (r *taskRepository) FetchFromDeferred(ownerID string, page, rpp int64, need
 	return tasks, nil
 }
 
+// FetchAll retrieves the tasks of every list of the owner.
+func (r *taskRepository) FetchAll(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var query = `SELECT "tasks"."fetch_all" ($1, $2, $3, $4, $5);`
+	return r.doFetchAcrossLists(query, ownerID, page, rpp, needle, sortExpr)
+}
+
+// FetchCompleted retrieves the completed tasks of every list of the owner.
+func (r *taskRepository) FetchCompleted(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var query = `SELECT "tasks"."fetch_completed" ($1, $2, $3, $4, $5);`
+	return r.doFetchAcrossLists(query, ownerID, page, rpp, needle, sortExpr)
+}
+
+// FetchArchived retrieves the tasks of the archived lists of the owner.
+func (r *taskRepository) FetchArchived(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var query = `SELECT "tasks"."fetch_archived" ($1, $2, $3, $4, $5);`
+	return r.doFetchAcrossLists(query, ownerID, page, rpp, needle, sortExpr)
+}
+
+// FetchTrashed retrieves the tasks of the owner that are in the trash.
+func (r *taskRepository) FetchTrashed(ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var query = `SELECT "tasks"."fetch_trashed" ($1, $2, $3, $4, $5);`
+	return r.doFetchAcrossLists(query, ownerID, page, rpp, needle, sortExpr)
+}
+
+func (r *taskRepository) doFetchAcrossLists(query, ownerID string, page, rpp int64, needle, sortExpr string) (tasks []*model.Task, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, query, ownerID, page, rpp, needle, sortExpr)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return nil, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTasks(rows)
+}
+
+// scanTasks reads every task in rows.
+func scanTasks(rows *sql.Rows) (tasks []*model.Task, err error) {
+	tasks = make([]*model.Task, 0)
+	for rows.Next() {
+		var task = new(model.Task)
+		err = rows.Scan(
+			&task.UUID,
+			&task.OwnerUUID,
+			&task.ListUUID,
+			&task.ParentUUID,
+			&task.PositionInList,
+			&task.Title,
+			&task.Headline,
+			&task.Description,
+			&task.Priority,
+			&task.Status,
+			&task.IsPinned,
+			&task.DueDate,
+			&task.RemindAt,
+			&task.Recurrence,
+			&task.CompletedAt,
+			&task.CreatedAt,
+			&task.UpdatedAt)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// Locate retrieves the list a task belongs to, whether it is in the trash or
+// not, regardless of who owns it.
+func (r *taskRepository) Locate(taskID string) (listID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tasks"."locate" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, taskID)
+	err = row.Scan(&listID)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", failure.ErrTaskNotFound
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return listID, nil
+}
+
 func (r *taskRepository) Update(ownerID, listID, taskID string, update *transfer.TaskUpdate) (ok bool, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -479,11 +625,13 @@ func (r *taskRepository) SetDueDate(ownerID, listID, taskID string, dueDate time
 	return ok, nil
 }
 
-func (r *taskRepository) Complete(ownerID, listID, taskID string) (ok bool, err error) {
+// SetRecurrence sets the recurrence rule of a task. A nil rule makes the task
+// not recur anymore.
+func (r *taskRepository) SetRecurrence(ownerID, listID, taskID string, rule *types.RecurrenceRule) (ok bool, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."set_as_completed" ($1, $2, $3);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID)
+	var query = `SELECT "tasks"."set_recurrence" ($1, $2, $3, $4);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, rule)
 	err = row.Scan(&ok)
 	if nil != err {
 		var pqerr *pq.Error
@@ -506,12 +654,19 @@ func (r *taskRepository) Complete(ownerID, listID, taskID string) (ok bool, err
 	return ok, nil
 }
 
-func (r *taskRepository) Resume(ownerID, listID, taskID string) (ok bool, err error) {
+// SaveOccurrence copies the task into a new uncompleted one, in the same
+// list, with the given due date, reminder and recurrence rule.
+func (r *taskRepository) SaveOccurrence(
+	ownerID, listID, taskID string,
+	dueDate time.Time,
+	remindAt *time.Time,
+	rule *types.RecurrenceRule,
+) (insertedID string, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."set_as_uncompleted" ($1, $2, $3);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID)
-	err = row.Scan(&ok)
+	var query = `SELECT "tasks"."make_next_occurrence" ($1, $2, $3, $4, $5, $6);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID, dueDate, remindAt, rule)
+	err = row.Scan(&insertedID)
 	if nil != err {
 		var pqerr *pq.Error
 		if errors.As(err, &pqerr) {
@@ -519,24 +674,24 @@ func (r *taskRepository) Resume(ownerID, listID, taskID string) (ok bool, err er
 			default:
 				log.Println(failure.PQErrorToString(pqerr))
 			case isNonexistentUserError(pqerr):
-				return false, failure.ErrUserNoLongerExists
+				return "", failure.ErrUserNoLongerExists
 			case isNonexistentListError(pqerr):
-				return false, failure.ErrListNotFound
+				return "", failure.ErrListNotFound
 			case isNonexistentTaskError(pqerr):
-				return false, failure.ErrTaskNotFound
+				return "", failure.ErrTaskNotFound
 			}
 		} else {
 			log.Println(err)
 		}
-		return false, err
+		return "", err
 	}
-	return ok, nil
+	return insertedID, nil
 }
 
-func (r *taskRepository) Pin(ownerID, listID, taskID string) (ok bool, err error) {
+func (r *taskRepository) Complete(ownerID, listID, taskID string) (ok bool, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."pin" ($1, $2, $3);`
+	var query = `SELECT "tasks"."set_as_completed" ($1, $2, $3);`
 	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID)
 	err = row.Scan(&ok)
 	if nil != err {
@@ -560,10 +715,10 @@ func (r *taskRepository) Pin(ownerID, listID, taskID string) (ok bool, err error
 	return ok, nil
 }
 
-func (r *taskRepository) Unpin(ownerID, listID, taskID string) (ok bool, err error) {
+func (r *taskRepository) Resume(ownerID, listID, taskID string) (ok bool, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."unpin" ($1, $2, $3);`
+	var query = `SELECT "tasks"."set_as_uncompleted" ($1, $2, $3);`
 	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID)
 	err = row.Scan(&ok)
 	if nil != err {
@@ -587,11 +742,11 @@ func (r *taskRepository) Unpin(ownerID, listID, taskID string) (ok bool, err err
 	return ok, nil
 }
 
-func (r *taskRepository) Move(ownerID, taskID, targetListID string) (ok bool, err error) {
+func (r *taskRepository) Pin(ownerID, listID, taskID string) (ok bool, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."move_one_from_list" ($1, $2, $3);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID, targetListID)
+	var query = `SELECT "tasks"."pin" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID)
 	err = row.Scan(&ok)
 	if nil != err {
 		var pqerr *pq.Error
@@ -614,11 +769,11 @@ func (r *taskRepository) Move(ownerID, taskID, targetListID string) (ok bool, er
 	return ok, nil
 }
 
-func (r *taskRepository) Today(ownerID, taskID string) (ok bool, err error) {
+func (r *taskRepository) Unpin(ownerID, listID, taskID string) (ok bool, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."move_one_to_today_list" ($1, $2);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID)
+	var query = `SELECT "tasks"."unpin" ($1, $2, $3);`
+	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID)
 	err = row.Scan(&ok)
 	if nil != err {
 		var pqerr *pq.Error
@@ -628,6 +783,8 @@ func (r *taskRepository) Today(ownerID, taskID string) (ok bool, err error) {
 				log.Println(failure.PQErrorToString(pqerr))
 			case isNonexistentUserError(pqerr):
 				return false, failure.ErrUserNoLongerExists
+			case isNonexistentListError(pqerr):
+				return false, failure.ErrListNotFound
 			case isNonexistentTaskError(pqerr):
 				return false, failure.ErrTaskNotFound
 			}
@@ -639,132 +796,322 @@ func (r *taskRepository) Today(ownerID, taskID string) (ok bool, err error) {
 	return ok, nil
 }
 
+// Move moves the task to another list of the owner. Subtasks and the tasks
+// that have subtasks are not moved, for a subtask is in the list of its parent.
+func (r *taskRepository) Move(ownerID, taskID, targetListID string) (ok bool, err error) {
+	return r.moveAlone(`SELECT "tasks"."move_one_from_list" ($1, $2, $3);`, ownerID, taskID, targetListID)
+}
+
+// moveAlone moves the task out of its list with the query, which is given the
+// owner, the task and args, in one transaction with making sure that the task
+// is neither a subtask nor has subtasks.
+func (r *taskRepository) moveAlone(query, ownerID, taskID string, args ...any) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer func() {
+		if nil != err && !errors.Is(err, failure.ErrNestedTaskMove) {
+			err = explainTaskError(err)
+		}
+	}()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if nil != err {
+		return false, err
+	}
+	defer tx.Rollback()
+	var listID string
+	err = tx.QueryRowContext(ctx, `SELECT "tasks"."locate" ($1);`, taskID).Scan(&listID)
+	if nil != err {
+		return false, err
+	}
+	tree, err := fetchTree(ctx, tx, ownerID, listID, taskID)
+	if nil != err {
+		return false, err
+	}
+	if 1 < len(tree) || (1 == len(tree) && nil != tree[0].ParentUUID) {
+		return false, failure.ErrNestedTaskMove
+	}
+	err = tx.QueryRowContext(ctx, query, append([]any{ownerID, taskID}, args...)...).Scan(&ok)
+	if nil != err || !ok {
+		return false, err
+	}
+	if err = tx.Commit(); nil != err {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *taskRepository) Today(ownerID, taskID string) (ok bool, err error) {
+	return r.moveAlone(`SELECT "tasks"."move_one_to_today_list" ($1, $2);`, ownerID, taskID)
+}
+
 func (r *taskRepository) Tomorrow(ownerID, taskID string) (ok bool, err error) {
+	return r.moveAlone(`SELECT "tasks"."move_one_to_tomorrow_list" ($1, $2);`, ownerID, taskID)
+}
+
+func (r *taskRepository) Defer(ownerID, taskID string) (ok bool, err error) {
+	return r.moveAlone(`SELECT "tasks"."move_one_to_deferred_list" ($1, $2);`, ownerID, taskID)
+}
+
+// Trash throws the task to the trash along with all the subtasks below it, in
+// one transaction. The subtasks go first, so that none is ever left without
+// its parent.
+func (r *taskRepository) Trash(ownerID, listID, taskID string) (ok bool, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."move_one_to_tomorrow_list" ($1, $2);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID)
-	err = row.Scan(&ok)
+	defer func() {
+		if nil != err {
+			err = explainTaskError(err)
+		}
+	}()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if nil != err {
-		var pqerr *pq.Error
-		if errors.As(err, &pqerr) {
-			switch {
-			default:
-				log.Println(failure.PQErrorToString(pqerr))
-			case isNonexistentUserError(pqerr):
-				return false, failure.ErrUserNoLongerExists
-			case isNonexistentTaskError(pqerr):
-				return false, failure.ErrTaskNotFound
-			}
-		} else {
-			log.Println(err)
+		return false, err
+	}
+	defer tx.Rollback()
+	tree, err := fetchTree(ctx, tx, ownerID, listID, taskID)
+	if nil != err {
+		return false, err
+	}
+	var query = `SELECT "tasks"."trash" ($1, $2, $3);`
+	for i := len(tree) - 1; 0 < i; i-- {
+		if _, err = tx.ExecContext(ctx, query, ownerID, listID, tree[i].UUID.String()); nil != err {
+			return false, err
 		}
+	}
+	err = tx.QueryRowContext(ctx, query, ownerID, listID, taskID).Scan(&ok)
+	if nil != err || !ok {
 		return false, err
 	}
-	return ok, nil
+	if err = tx.Commit(); nil != err {
+		return false, err
+	}
+	return true, nil
 }
 
-func (r *taskRepository) Defer(ownerID, taskID string) (ok bool, err error) {
+// RestoreFromTrash takes the task out of the trash along with all the
+// subtasks below it, in one transaction. The task goes first, so that none of
+// its subtasks is ever left without its parent.
+func (r *taskRepository) RestoreFromTrash(ownerID, listID, taskID string) (ok bool, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."move_one_to_deferred_list" ($1, $2);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, taskID)
-	err = row.Scan(&ok)
+	defer func() {
+		if nil != err {
+			err = explainTaskError(err)
+		}
+	}()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if nil != err {
-		var pqerr *pq.Error
-		if errors.As(err, &pqerr) {
-			switch {
-			default:
-				log.Println(failure.PQErrorToString(pqerr))
-			case isNonexistentUserError(pqerr):
-				return false, failure.ErrUserNoLongerExists
-			case isNonexistentTaskError(pqerr):
-				return false, failure.ErrTaskNotFound
-			}
-		} else {
-			log.Println(err)
+		return false, err
+	}
+	defer tx.Rollback()
+	tree, err := fetchTrashedTree(ctx, tx, ownerID, listID, taskID)
+	if nil != err {
+		return false, err
+	}
+	var query = `SELECT "tasks"."restore_from_trash" ($1, $2, $3);`
+	err = tx.QueryRowContext(ctx, query, ownerID, listID, taskID).Scan(&ok)
+	if nil != err || !ok {
+		return false, err
+	}
+	for i := 1; i < len(tree); i++ {
+		if _, err = tx.ExecContext(ctx, query, ownerID, listID, tree[i].UUID.String()); nil != err {
+			return false, err
 		}
+	}
+	if err = tx.Commit(); nil != err {
 		return false, err
 	}
-	return ok, nil
+	return true, nil
 }
 
-func (r *taskRepository) Trash(ownerID, listID, taskID string) (ok bool, err error) {
+// Delete removes the task along with all the subtasks below it, whether they
+// are in the trash or not, in one transaction. The subtasks go first.
+func (r *taskRepository) Delete(ownerID, listID, taskID string) (err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."trash" ($1, $2, $3);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID)
-	err = row.Scan(&ok)
+	defer func() {
+		if nil != err {
+			err = explainTaskError(err)
+		}
+	}()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if nil != err {
-		var pqerr *pq.Error
-		if errors.As(err, &pqerr) {
+		return err
+	}
+	defer tx.Rollback()
+	tree, err := fetchTrashedTree(ctx, tx, ownerID, listID, taskID)
+	if nil != err {
+		return err
+	}
+	if 0 == len(tree) {
+		tree, err = fetchTree(ctx, tx, ownerID, listID, taskID)
+		if nil != err {
+			return err
+		}
+	}
+	var query = `SELECT "tasks"."delete" ($1, $2, $3);`
+	for i := len(tree) - 1; 0 < i; i-- {
+		if _, err = tx.ExecContext(ctx, query, ownerID, listID, tree[i].UUID.String()); nil != err {
+			return err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, query, ownerID, listID, taskID); nil != err {
+		return err
+	}
+	return tx.Commit()
+}
+
+// trashPageSize is how many trashed tasks are read at once to find the
+// subtasks of a trashed task.
+const trashPageSize = 100
+
+// fetchTree retrieves the task and all the subtasks below it, every parent
+// before its subtasks.
+func fetchTree(ctx context.Context, q queryer, ownerID, listID, taskID string) (tasks []*model.Task, err error) {
+	rows, err := q.QueryContext(ctx, `SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`, ownerID, listID, taskID)
+	if nil != err {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTasks(rows)
+}
+
+// fetchTrashedTree retrieves the trashed task and all the trashed subtasks
+// below it, every parent before its subtasks. It is empty if the task is not
+// in the trash.
+func fetchTrashedTree(ctx context.Context, q queryer, ownerID, listID, taskID string) (tasks []*model.Task, err error) {
+	var (
+		root     *model.Task
+		children = make(map[string][]*model.Task)
+	)
+	for page := int64(1); ; page++ {
+		rows, err := q.QueryContext(ctx, `SELECT * FROM "tasks"."fetch_trashed" ($1, $2, $3, $4, $5);`,
+			ownerID, page, int64(trashPageSize), "", "")
+		if nil != err {
+			return nil, err
+		}
+		trashed, err := scanTasks(rows)
+		rows.Close()
+		if nil != err {
+			return nil, err
+		}
+		for _, task := range trashed {
 			switch {
-			default:
-				log.Println(failure.PQErrorToString(pqerr))
-			case isNonexistentUserError(pqerr):
-				return false, failure.ErrUserNoLongerExists
-			case isNonexistentListError(pqerr):
-				return false, failure.ErrListNotFound
-			case isNonexistentTaskError(pqerr):
-				return false, failure.ErrTaskNotFound
+			case listID != task.ListUUID.String():
+			case taskID == task.UUID.String():
+				root = task
+			case nil != task.ParentUUID:
+				children[task.ParentUUID.String()] = append(children[task.ParentUUID.String()], task)
 			}
-		} else {
-			log.Println(err)
 		}
-		return false, err
+		if trashPageSize > len(trashed) {
+			break
+		}
 	}
-	return ok, nil
+	if nil == root {
+		return nil, nil
+	}
+	tasks = []*model.Task{root}
+	for i := 0; i < len(tasks); i++ {
+		tasks = append(tasks, children[tasks[i].UUID.String()]...)
+	}
+	return tasks, nil
 }
 
-func (r *taskRepository) RestoreFromTrash(ownerID, listID, taskID string) (ok bool, err error) {
+// explainTaskError turns the errors of the routines on the tasks into the
+// failures they stand for, and logs the others.
+func explainTaskError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return failure.ErrTaskNotFound
+	}
+	var pqerr *pq.Error
+	if errors.As(err, &pqerr) {
+		switch {
+		default:
+			log.Println(failure.PQErrorToString(pqerr))
+		case isNonexistentUserError(pqerr):
+			return failure.ErrUserNoLongerExists
+		case isNonexistentListError(pqerr):
+			return failure.ErrListNotFound
+		case isNonexistentTaskError(pqerr):
+			return failure.ErrTaskNotFound
+		}
+	} else {
+		log.Println(err)
+	}
+	return err
+}
+
+func (r *taskRepository) FetchRolloverCandidates() (candidates []*model.RolloverCandidate, err error) {
 	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."restore_from_trash" ($1, $2, $3);`
-	var row = r.db.QueryRowContext(ctx, query, ownerID, listID, taskID)
-	err = row.Scan(&ok)
+	var query = `SELECT "user_uuid", "timezone", "last_rollover_on" FROM "tasks"."fetch_rollover_candidates" ();`
+	rows, err := r.db.QueryContext(ctx, query)
 	if nil != err {
 		var pqerr *pq.Error
 		if errors.As(err, &pqerr) {
-			switch {
-			default:
-				log.Println(failure.PQErrorToString(pqerr))
-			case isNonexistentUserError(pqerr):
-				return false, failure.ErrUserNoLongerExists
-			case isNonexistentListError(pqerr):
-				return false, failure.ErrListNotFound
-			case isNonexistentTaskError(pqerr):
-				return false, failure.ErrTaskNotFound
-			}
+			log.Println(failure.PQErrorToString(pqerr))
 		} else {
 			log.Println(err)
 		}
-		return false, err
+		return nil, err
 	}
-	return ok, nil
+	defer rows.Close()
+	candidates = make([]*model.RolloverCandidate, 0)
+	for rows.Next() {
+		var candidate = new(model.RolloverCandidate)
+		err = rows.Scan(&candidate.OwnerUUID, &candidate.Timezone, &candidate.LastRolloverOn)
+		if nil != err {
+			log.Println(err)
+			return nil, err
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
 }
 
-func (r *taskRepository) Delete(ownerID, listID, taskID string) error {
-	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+// RollOver moves, in one transaction, the tasks of the today list of the owner
+// to its deferred list and then the ones of its tomorrow list to its today
+// list. It happens at most once per day, the local date of the owner; ok is
+// false if the owner was already rolled over on that day.
+func (r *taskRepository) RollOver(ownerID string, day time.Time) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	var query = `SELECT "tasks"."delete" ($1, $2, $3);`
-	_, err := r.db.ExecContext(ctx, query, ownerID, listID, taskID)
-	if nil != err {
+	defer func() {
+		if nil == err {
+			return
+		}
 		var pqerr *pq.Error
 		if errors.As(err, &pqerr) {
 			switch {
 			default:
 				log.Println(failure.PQErrorToString(pqerr))
 			case isNonexistentUserError(pqerr):
-				return failure.ErrUserNoLongerExists
-			case isNonexistentListError(pqerr):
-				return failure.ErrListNotFound
-			case isNonexistentTaskError(pqerr):
-				return failure.ErrTaskNotFound
+				err = failure.ErrUserNoLongerExists
 			}
 		} else {
 			log.Println(err)
 		}
-		return err
+	}()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if nil != err {
+		return false, err
+	}
+	defer tx.Rollback()
+	err = tx.QueryRowContext(ctx, `SELECT "tasks"."claim_rollover" ($1, $2);`, ownerID, day).Scan(&ok)
+	if nil != err || !ok {
+		return false, err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT "tasks"."defer_tasks_in_today_list" ($1);`, ownerID)
+	if nil != err {
+		return false, err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT "tasks"."move_tasks_from_tomorrow_to_today_list" ($1);`, ownerID)
+	if nil != err {
+		return false, err
+	}
+	if err = tx.Commit(); nil != err {
+		return false, err
 	}
-	return nil
+	return true, nil
 }
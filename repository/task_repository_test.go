@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
@@ -9,6 +10,7 @@ import (
 	"noda/data/model"
 	"noda/data/transfer"
 	"noda/data/types"
+	"noda/failure"
 	"regexp"
 	"testing"
 	"time"
@@ -48,6 +50,20 @@ func TestTaskRepository_Save(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("success for the Today list", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, nil,
+				fmt.Sprintf("ROW('%s', '%s', '%s', '%s', '%s', %s, %s)",
+					creation.Title, creation.Headline, creation.Description, creation.Priority, creation.Status, "NULL", "NULL")).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"make_task"}).
+				AddRow(taskID))
+		res, err = r.Save(userID, "", creation)
+		assert.Equal(t, taskID, res)
+		assert.NoError(t, err)
+	})
+
 	t.Run("unexpected database error", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
@@ -96,6 +112,7 @@ var taskTableColumns = []string{
 	"task_uuid",
 	"owner_uuid",
 	"list_uuid",
+	"parent_uuid",
 	"position_in_list",
 	"title",
 	"headline",
@@ -105,6 +122,7 @@ var taskTableColumns = []string{
 	"is_pinned",
 	"due_date",
 	"remind_at",
+	"recurrence",
 	"completed_at",
 	"created_at",
 	"updated_at"}
@@ -143,7 +161,7 @@ func TestTaskRepository_FetchByID(t *testing.T) {
 			WithArgs(userID, listID, taskID).
 			WillReturnRows(sqlmock.
 				NewRows(taskTableColumns).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
 		res, err = r.FetchByID(userID, listID, taskID)
 		assert.Equal(t, task, res)
 		assert.NoError(t, err)
@@ -165,7 +183,7 @@ func TestTaskRepository_Fetch(t *testing.T) {
 	defer db.Close()
 	var (
 		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."fetch" ($1, $2, $3, $4, $5, $6);`)
+		query = regexp.QuoteMeta(`SELECT "tasks"."fetch" ($1, $2, $3, $4, $5, $6, $7, $8, $9);`)
 		res   []*model.Task
 		err   error
 		task  = &model.Task{
@@ -185,19 +203,20 @@ func TestTaskRepository_Fetch(t *testing.T) {
 			CreatedAt:      time.Now(),
 			UpdatedAt:      time.Now(),
 		}
-		tasks = []*model.Task{task, task, task}
+		tasks  = []*model.Task{task, task, task}
+		tagIDs = []string{uuid.New().String(), uuid.New().String()}
 	)
 
 	t.Run("success", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID, listID, 1, 10, "", "").
+			WithArgs(userID, listID, 1, 10, "", "", pq.Array(tagIDs), true, "").
 			WillReturnRows(sqlmock.
 				NewRows(taskTableColumns).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
-		res, err = r.Fetch(userID, listID, 1, 10, "", "")
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+		res, err = r.Fetch(userID, listID, 1, 10, "", "", tagIDs, true, "")
 		assert.Equal(t, tasks, res)
 		assert.NoError(t, err)
 	})
@@ -206,7 +225,7 @@ func TestTaskRepository_Fetch(t *testing.T) {
 		mock.
 			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
-		res, err = r.Fetch(userID, listID, 1, 10, "", "")
+		res, err = r.Fetch(userID, listID, 1, 10, "", "", tagIDs, true, "")
 		assert.Error(t, err)
 		assert.Nil(t, res)
 	})
@@ -218,7 +237,7 @@ func TestTaskRepository_FetchFromToday(t *testing.T) {
 	defer db.Close()
 	var (
 		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."fetch_from_today_list" ($1, $2, $3, $4, $5);`)
+		query = regexp.QuoteMeta(`SELECT "tasks"."fetch_from_today_list" ($1, $2, $3, $4, $5, $6, $7);`)
 		res   []*model.Task
 		err   error
 		task  = &model.Task{
@@ -244,13 +263,13 @@ func TestTaskRepository_FetchFromToday(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID, 1, 10, "", "").
+			WithArgs(userID, 1, 10, "", "", pq.Array([]string(nil)), false).
 			WillReturnRows(sqlmock.
 				NewRows(taskTableColumns).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
-		res, err = r.FetchFromToday(userID, 1, 10, "", "")
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+		res, err = r.FetchFromToday(userID, 1, 10, "", "", nil, false)
 		assert.Equal(t, tasks, res)
 		assert.NoError(t, err)
 	})
@@ -259,7 +278,7 @@ func TestTaskRepository_FetchFromToday(t *testing.T) {
 		mock.
 			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
-		res, err = r.FetchFromToday(userID, 1, 10, "", "")
+		res, err = r.FetchFromToday(userID, 1, 10, "", "", nil, false)
 		assert.Error(t, err)
 		assert.Nil(t, res)
 	})
@@ -271,7 +290,7 @@ func TestTaskRepository_FetchFromTomorrow(t *testing.T) {
 	defer db.Close()
 	var (
 		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."fetch_from_tomorrow_list" ($1, $2, $3, $4, $5);`)
+		query = regexp.QuoteMeta(`SELECT "tasks"."fetch_from_tomorrow_list" ($1, $2, $3, $4, $5, $6, $7);`)
 		res   []*model.Task
 		err   error
 		task  = &model.Task{
@@ -297,13 +316,13 @@ func TestTaskRepository_FetchFromTomorrow(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID, 1, 10, "", "").
+			WithArgs(userID, 1, 10, "", "", pq.Array([]string(nil)), false).
 			WillReturnRows(sqlmock.
 				NewRows(taskTableColumns).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
-		res, err = r.FetchFromTomorrow(userID, 1, 10, "", "")
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+		res, err = r.FetchFromTomorrow(userID, 1, 10, "", "", nil, false)
 		assert.Equal(t, tasks, res)
 		assert.NoError(t, err)
 	})
@@ -312,7 +331,7 @@ func TestTaskRepository_FetchFromTomorrow(t *testing.T) {
 		mock.
 			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
-		res, err = r.FetchFromTomorrow(userID, 1, 10, "", "")
+		res, err = r.FetchFromTomorrow(userID, 1, 10, "", "", nil, false)
 		assert.Error(t, err)
 		assert.Nil(t, res)
 	})
@@ -353,9 +372,9 @@ func TestTaskRepository_FetchFromDeferred(t *testing.T) {
 			WithArgs(userID, 1, 10, "", "").
 			WillReturnRows(sqlmock.
 				NewRows(taskTableColumns).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
-				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt).
+				AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
 		res, err = r.FetchFromDeferred(userID, 1, 10, "", "")
 		assert.Equal(t, tasks, res)
 		assert.NoError(t, err)
@@ -371,6 +390,89 @@ func TestTaskRepository_FetchFromDeferred(t *testing.T) {
 	})
 }
 
+func TestTaskRepository_FetchAcrossLists(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r    = NewTaskRepository(db)
+		task = &model.Task{
+			UUID:      uuid.MustParse(taskID),
+			OwnerUUID: uuid.MustParse(userID),
+			ListUUID:  uuid.MustParse(listID),
+			Title:     "task title",
+			Priority:  types.TaskPriorityHigh,
+			Status:    types.TaskStatusComplete,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+	)
+	var cases = []struct {
+		routine string
+		fetch   func(ownerID string, page, rpp int64, needle, sortExpr string) ([]*model.Task, error)
+	}{
+		{"fetch_all", r.FetchAll},
+		{"fetch_completed", r.FetchCompleted},
+		{"fetch_archived", r.FetchArchived},
+		{"fetch_trashed", r.FetchTrashed},
+	}
+
+	for _, c := range cases {
+		var query = regexp.QuoteMeta(`SELECT "tasks"."` + c.routine + `" ($1, $2, $3, $4, $5);`)
+
+		t.Run(c.routine, func(t *testing.T) {
+			mock.
+				ExpectQuery(query).
+				WithArgs(userID, 1, 10, "x", "").
+				WillReturnRows(sqlmock.
+					NewRows(taskTableColumns).
+					AddRow(task.UUID, task.OwnerUUID, task.ListUUID, task.ParentUUID, task.PositionInList, task.Title, task.Headline, task.Description, task.Priority, task.Status, task.IsPinned, task.DueDate, task.RemindAt, task.Recurrence, task.CompletedAt, task.CreatedAt, task.UpdatedAt))
+			res, err := c.fetch(userID, 1, 10, "x", "")
+			assert.NoError(t, err)
+			assert.Equal(t, []*model.Task{task}, res)
+		})
+
+		t.Run(c.routine+" of a user that no longer exists", func(t *testing.T) {
+			mock.
+				ExpectQuery(query).
+				WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID \"" + userID + "\""})
+			res, err := c.fetch(userID, 1, 10, "", "")
+			assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+			assert.Nil(t, res)
+		})
+	}
+}
+
+func TestTaskRepository_Locate(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTaskRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tasks"."locate" ($1);`)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(taskID).
+			WillReturnRows(sqlmock.NewRows([]string{"locate"}).AddRow(listID))
+		res, err := r.Locate(taskID)
+		assert.NoError(t, err)
+		assert.Equal(t, listID, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(taskID).
+			WillReturnError(sql.ErrNoRows)
+		res, err := r.Locate(taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Empty(t, res)
+	})
+}
+
 func TestTaskRepository_Update(t *testing.T) {
 	defer beQuiet()()
 	db, mock := newMock()
@@ -546,6 +648,89 @@ func TestTaskRepository_SetDueDate(t *testing.T) {
 	})
 }
 
+func TestTaskRepository_SetRecurrence(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTaskRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tasks"."set_recurrence" ($1, $2, $3, $4);`)
+		rule  = &types.RecurrenceRule{Frequency: types.FrequencyWeekly, Interval: 1}
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, "FREQ=WEEKLY").
+			WillReturnRows(sqlmock.
+				NewRows([]string{"set_recurrence"}).
+				AddRow(true))
+		res, err = r.SetRecurrence(userID, listID, taskID, rule)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("removes the rule", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, nil).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"set_recurrence"}).
+				AddRow(true))
+		res, err = r.SetRecurrence(userID, listID, taskID, nil)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent task with UUID"})
+		res, err = r.SetRecurrence(userID, listID, taskID, rule)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+	})
+}
+
+func TestTaskRepository_SaveOccurrence(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r          = NewTaskRepository(db)
+		query      = regexp.QuoteMeta(`SELECT "tasks"."make_next_occurrence" ($1, $2, $3, $4, $5, $6);`)
+		rule       = &types.RecurrenceRule{Frequency: types.FrequencyDaily, Interval: 1, Count: 2}
+		dueDate    = time.Now()
+		remindAt   = dueDate.Add(-time.Hour)
+		insertedID = uuid.New().String()
+		res        string
+		err        error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, listID, taskID, dueDate, remindAt, "FREQ=DAILY;COUNT=2").
+			WillReturnRows(sqlmock.
+				NewRows([]string{"make_next_occurrence"}).
+				AddRow(insertedID))
+		res, err = r.SaveOccurrence(userID, listID, taskID, dueDate, &remindAt, rule)
+		assert.Equal(t, insertedID, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.SaveOccurrence(userID, listID, taskID, dueDate, nil, rule)
+		assert.Equal(t, "", res)
+		assert.Error(t, err)
+	})
+}
+
 func TestTaskRepository_Complete(t *testing.T) {
 	defer beQuiet()()
 	db, mock := newMock()
@@ -678,37 +863,98 @@ func TestTaskRepository_Unpin(t *testing.T) {
 	})
 }
 
+// taskRows returns the rows of the tasks of the list, each given as its UUID
+// and the UUID of its parent, if any, in the given list.
+func taskRows(listID string, tasks ...[2]string) *sqlmock.Rows {
+	var rows = sqlmock.NewRows(taskTableColumns)
+	for _, task := range tasks {
+		var parent *string
+		if "" != task[1] {
+			parent = &task[1]
+		}
+		rows.AddRow(task[0], userID, listID, parent, 1, "Title", "", "", types.TaskPriorityMedium,
+			types.TaskStatusIncomplete, false, nil, nil, nil, nil, time.Now(), time.Now())
+	}
+	return rows
+}
+
 func TestTaskRepository_Move(t *testing.T) {
 	defer beQuiet()()
 	db, mock := newMock()
 	defer db.Close()
 	var (
 		r            = NewTaskRepository(db)
+		locate       = regexp.QuoteMeta(`SELECT "tasks"."locate" ($1);`)
+		fetchTree    = regexp.QuoteMeta(`SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`)
 		query        = regexp.QuoteMeta(`SELECT "tasks"."move_one_from_list" ($1, $2, $3);`)
 		res          bool
 		err          error
 		targetListID = uuid.New().String()
+		subtaskID    = uuid.New().String()
 	)
 
 	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(locate).WithArgs(taskID).WillReturnRows(sqlmock.NewRows([]string{"locate"}).AddRow(listID))
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).WillReturnRows(taskRows(listID, [2]string{taskID, ""}))
 		mock.
 			ExpectQuery(query).
 			WithArgs(userID, taskID, targetListID).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"move_task_from_list"}).
 				AddRow(true))
+		mock.ExpectCommit()
 		res, err = r.Move(userID, taskID, targetListID)
 		assert.True(t, res)
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a subtask is not moved", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(locate).WithArgs(subtaskID).WillReturnRows(sqlmock.NewRows([]string{"locate"}).AddRow(listID))
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, subtaskID).
+			WillReturnRows(taskRows(listID, [2]string{subtaskID, taskID}))
+		mock.ExpectRollback()
+		res, err = r.Move(userID, subtaskID, targetListID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrNestedTaskMove)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a task with subtasks is not moved", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(locate).WithArgs(taskID).WillReturnRows(sqlmock.NewRows([]string{"locate"}).AddRow(listID))
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).
+			WillReturnRows(taskRows(listID, [2]string{taskID, ""}, [2]string{subtaskID, taskID}))
+		mock.ExpectRollback()
+		res, err = r.Move(userID, taskID, targetListID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrNestedTaskMove)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(locate).WithArgs(taskID).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+		res, err = r.Move(userID, taskID, targetListID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
 	})
 
 	t.Run("unexpected database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(locate).WithArgs(taskID).WillReturnRows(sqlmock.NewRows([]string{"locate"}).AddRow(listID))
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).WillReturnRows(taskRows(listID, [2]string{taskID, ""}))
 		mock.
 			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
 		res, err = r.Move(userID, taskID, targetListID)
 		assert.False(t, res)
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -717,31 +963,53 @@ func TestTaskRepository_Today(t *testing.T) {
 	db, mock := newMock()
 	defer db.Close()
 	var (
-		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."move_one_to_today_list" ($1, $2);`)
-		res   bool
-		err   error
+		r         = NewTaskRepository(db)
+		locate    = regexp.QuoteMeta(`SELECT "tasks"."locate" ($1);`)
+		fetchTree = regexp.QuoteMeta(`SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`)
+		query     = regexp.QuoteMeta(`SELECT "tasks"."move_one_to_today_list" ($1, $2);`)
+		res       bool
+		err       error
 	)
+	var expectTree = func(tasks ...[2]string) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(locate).WithArgs(taskID).WillReturnRows(sqlmock.NewRows([]string{"locate"}).AddRow(listID))
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).WillReturnRows(taskRows(listID, tasks...))
+	}
 
 	t.Run("success", func(t *testing.T) {
+		expectTree([2]string{taskID, ""})
 		mock.
 			ExpectQuery(query).
 			WithArgs(userID, taskID).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"move_task_to_today_list"}).
 				AddRow(true))
+		mock.ExpectCommit()
 		res, err = r.Today(userID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a subtask is not moved", func(t *testing.T) {
+		expectTree([2]string{taskID, uuid.NewString()})
+		mock.ExpectRollback()
+		res, err = r.Today(userID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrNestedTaskMove)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("unexpected database error", func(t *testing.T) {
+		expectTree([2]string{taskID, ""})
 		mock.
 			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
 		res, err = r.Today(userID, taskID)
 		assert.False(t, res)
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -750,31 +1018,53 @@ func TestTaskRepository_Tomorrow(t *testing.T) {
 	db, mock := newMock()
 	defer db.Close()
 	var (
-		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."move_one_to_tomorrow_list" ($1, $2);`)
-		res   bool
-		err   error
+		r         = NewTaskRepository(db)
+		locate    = regexp.QuoteMeta(`SELECT "tasks"."locate" ($1);`)
+		fetchTree = regexp.QuoteMeta(`SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`)
+		query     = regexp.QuoteMeta(`SELECT "tasks"."move_one_to_tomorrow_list" ($1, $2);`)
+		res       bool
+		err       error
 	)
+	var expectTree = func(tasks ...[2]string) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(locate).WithArgs(taskID).WillReturnRows(sqlmock.NewRows([]string{"locate"}).AddRow(listID))
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).WillReturnRows(taskRows(listID, tasks...))
+	}
 
 	t.Run("success", func(t *testing.T) {
+		expectTree([2]string{taskID, ""})
 		mock.
 			ExpectQuery(query).
 			WithArgs(userID, taskID).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"move_task_to_tomorrow_list"}).
 				AddRow(true))
+		mock.ExpectCommit()
 		res, err = r.Tomorrow(userID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a subtask is not moved", func(t *testing.T) {
+		expectTree([2]string{taskID, uuid.NewString()})
+		mock.ExpectRollback()
+		res, err = r.Tomorrow(userID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrNestedTaskMove)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("unexpected database error", func(t *testing.T) {
+		expectTree([2]string{taskID, ""})
 		mock.
 			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
 		res, err = r.Tomorrow(userID, taskID)
 		assert.False(t, res)
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -783,31 +1073,53 @@ func TestTaskRepository_Defer(t *testing.T) {
 	db, mock := newMock()
 	defer db.Close()
 	var (
-		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."move_one_to_deferred_list" ($1, $2);`)
-		res   bool
-		err   error
+		r         = NewTaskRepository(db)
+		locate    = regexp.QuoteMeta(`SELECT "tasks"."locate" ($1);`)
+		fetchTree = regexp.QuoteMeta(`SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`)
+		query     = regexp.QuoteMeta(`SELECT "tasks"."move_one_to_deferred_list" ($1, $2);`)
+		res       bool
+		err       error
 	)
+	var expectTree = func(tasks ...[2]string) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(locate).WithArgs(taskID).WillReturnRows(sqlmock.NewRows([]string{"locate"}).AddRow(listID))
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).WillReturnRows(taskRows(listID, tasks...))
+	}
 
 	t.Run("success", func(t *testing.T) {
+		expectTree([2]string{taskID, ""})
 		mock.
 			ExpectQuery(query).
 			WithArgs(userID, taskID).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"move_task_to_deferred_list"}).
 				AddRow(true))
+		mock.ExpectCommit()
 		res, err = r.Defer(userID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a subtask is not moved", func(t *testing.T) {
+		expectTree([2]string{taskID, uuid.NewString()})
+		mock.ExpectRollback()
+		res, err = r.Defer(userID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrNestedTaskMove)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("unexpected database error", func(t *testing.T) {
+		expectTree([2]string{taskID, ""})
 		mock.
 			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
 		res, err = r.Defer(userID, taskID)
 		assert.False(t, res)
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -816,31 +1128,69 @@ func TestTaskRepository_Trash(t *testing.T) {
 	db, mock := newMock()
 	defer db.Close()
 	var (
-		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."trash" ($1, $2, $3);`)
-		res   bool
-		err   error
+		r                   = NewTaskRepository(db)
+		fetchTree           = regexp.QuoteMeta(`SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`)
+		query               = regexp.QuoteMeta(`SELECT "tasks"."trash" ($1, $2, $3);`)
+		subtaskID, nestedID = uuid.NewString(), uuid.NewString()
+		res                 bool
+		err                 error
 	)
 
 	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).WillReturnRows(taskRows(listID, [2]string{taskID, ""}))
 		mock.
 			ExpectQuery(query).
 			WithArgs(userID, listID, taskID).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"trash_task"}).
 				AddRow(true))
+		mock.ExpectCommit()
+		res, err = r.Trash(userID, listID, taskID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("the subtasks go first", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).
+			WillReturnRows(taskRows(listID, [2]string{taskID, ""}, [2]string{subtaskID, taskID}, [2]string{nestedID, subtaskID}))
+		mock.ExpectExec(query).WithArgs(userID, listID, nestedID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(query).WithArgs(userID, listID, subtaskID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(query).WithArgs(userID, listID, taskID).
+			WillReturnRows(sqlmock.NewRows([]string{"trash_task"}).AddRow(true))
+		mock.ExpectCommit()
 		res, err = r.Trash(userID, listID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing is trashed if the task is not", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).
+			WillReturnRows(taskRows(listID, [2]string{taskID, ""}, [2]string{subtaskID, taskID}))
+		mock.ExpectExec(query).WithArgs(userID, listID, subtaskID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(query).WithArgs(userID, listID, taskID).
+			WillReturnRows(sqlmock.NewRows([]string{"trash_task"}).AddRow(false))
+		mock.ExpectRollback()
+		res, err = r.Trash(userID, listID, taskID)
+		assert.False(t, res)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("unexpected database error", func(t *testing.T) {
+		mock.ExpectBegin()
 		mock.
-			ExpectQuery(query).
+			ExpectQuery(fetchTree).
 			WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
 		res, err = r.Trash(userID, listID, taskID)
 		assert.False(t, res)
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -849,31 +1199,61 @@ func TestTaskRepository_RestoreFromTrash(t *testing.T) {
 	db, mock := newMock()
 	defer db.Close()
 	var (
-		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."restore_from_trash" ($1, $2, $3);`)
-		res   bool
-		err   error
+		r                   = NewTaskRepository(db)
+		fetchTrashed        = regexp.QuoteMeta(`SELECT * FROM "tasks"."fetch_trashed" ($1, $2, $3, $4, $5);`)
+		query               = regexp.QuoteMeta(`SELECT "tasks"."restore_from_trash" ($1, $2, $3);`)
+		subtaskID, nestedID = uuid.NewString(), uuid.NewString()
+		res                 bool
+		err                 error
 	)
 
 	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTrashed).WithArgs(userID, int64(1), int64(trashPageSize), "", "").
+			WillReturnRows(taskRows(listID, [2]string{taskID, ""}))
 		mock.
 			ExpectQuery(query).
 			WithArgs(userID, listID, taskID).
 			WillReturnRows(sqlmock.
 				NewRows([]string{"restore_task_from_trash"}).
 				AddRow(true))
+		mock.ExpectCommit()
 		res, err = r.RestoreFromTrash(userID, listID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("the task goes first, then its subtasks", func(t *testing.T) {
+		var trashed = taskRows(listID,
+			[2]string{nestedID, subtaskID},
+			[2]string{uuid.NewString(), uuid.NewString()},
+			[2]string{subtaskID, taskID},
+			[2]string{taskID, ""})
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTrashed).WithArgs(userID, int64(1), int64(trashPageSize), "", "").WillReturnRows(trashed)
+		mock.ExpectQuery(query).WithArgs(userID, listID, taskID).
+			WillReturnRows(sqlmock.NewRows([]string{"restore_task_from_trash"}).AddRow(true))
+		mock.ExpectExec(query).WithArgs(userID, listID, subtaskID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(query).WithArgs(userID, listID, nestedID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		res, err = r.RestoreFromTrash(userID, listID, taskID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("unexpected database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTrashed).WillReturnRows(taskRows(listID))
 		mock.
 			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
 		res, err = r.RestoreFromTrash(userID, listID, taskID)
 		assert.False(t, res)
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 }
 
@@ -882,25 +1262,148 @@ func TestTaskRepository_Delete(t *testing.T) {
 	db, mock := newMock()
 	defer db.Close()
 	var (
-		r     = NewTaskRepository(db)
-		query = regexp.QuoteMeta(`SELECT "tasks"."delete" ($1, $2, $3);`)
-		err   error
+		r            = NewTaskRepository(db)
+		fetchTrashed = regexp.QuoteMeta(`SELECT * FROM "tasks"."fetch_trashed" ($1, $2, $3, $4, $5);`)
+		fetchTree    = regexp.QuoteMeta(`SELECT * FROM "subtasks"."fetch_tree" ($1, $2, $3);`)
+		query        = regexp.QuoteMeta(`SELECT "tasks"."delete" ($1, $2, $3);`)
+		subtaskID    = uuid.NewString()
+		err          error
 	)
 
 	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTrashed).WithArgs(userID, int64(1), int64(trashPageSize), "", "").WillReturnRows(taskRows(listID))
+		mock.ExpectQuery(fetchTree).WithArgs(userID, listID, taskID).
+			WillReturnRows(taskRows(listID, [2]string{taskID, ""}, [2]string{subtaskID, taskID}))
+		mock.ExpectExec(query).WithArgs(userID, listID, subtaskID).WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.
 			ExpectExec(query).
 			WithArgs(userID, listID, taskID).
 			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
 		err = r.Delete(userID, listID, taskID)
 		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a trashed task is removed along with its trashed subtasks", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTrashed).WithArgs(userID, int64(1), int64(trashPageSize), "", "").
+			WillReturnRows(taskRows(listID, [2]string{subtaskID, taskID}, [2]string{taskID, ""}))
+		mock.ExpectExec(query).WithArgs(userID, listID, subtaskID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(query).WithArgs(userID, listID, taskID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		err = r.Delete(userID, listID, taskID)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
 	})
 
 	t.Run("unexpected database error", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(fetchTrashed).WillReturnRows(taskRows(listID))
+		mock.ExpectQuery(fetchTree).WillReturnRows(taskRows(listID, [2]string{taskID, ""}))
 		mock.
-			ExpectQuery(query).
+			ExpectExec(query).
 			WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
 		err = r.Delete(userID, listID, taskID)
 		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTaskRepository_FetchRolloverCandidates(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r       = NewTaskRepository(db)
+		query   = regexp.QuoteMeta(`SELECT "user_uuid", "timezone", "last_rollover_on" FROM "tasks"."fetch_rollover_candidates" ();`)
+		columns = []string{"user_uuid", "timezone", "last_rollover_on"}
+		day     = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		res     []*model.RolloverCandidate
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(userID, "America/Managua", day).
+				AddRow(userID, "UTC", day))
+		res, err = r.FetchRolloverCandidates()
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.RolloverCandidate{
+			{OwnerUUID: uuid.MustParse(userID), Timezone: "America/Managua", LastRolloverOn: day},
+			{OwnerUUID: uuid.MustParse(userID), Timezone: "UTC", LastRolloverOn: day},
+		}, res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.FetchRolloverCandidates()
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestTaskRepository_RollOver(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r            = NewTaskRepository(db)
+		claim        = regexp.QuoteMeta(`SELECT "tasks"."claim_rollover" ($1, $2);`)
+		deferToday   = regexp.QuoteMeta(`SELECT "tasks"."defer_tasks_in_today_list" ($1);`)
+		moveTomorrow = regexp.QuoteMeta(`SELECT "tasks"."move_tasks_from_tomorrow_to_today_list" ($1);`)
+		day          = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		claimed      = func(ok bool) *sqlmock.Rows { return sqlmock.NewRows([]string{"claim_rollover"}).AddRow(ok) }
+		res          bool
+		err          error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(claim).WithArgs(userID, day).WillReturnRows(claimed(true))
+		mock.ExpectExec(deferToday).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(moveTomorrow).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		res, err = r.RollOver(userID, day)
+		assert.NoError(t, err)
+		assert.True(t, res)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already rolled over on that day", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(claim).WithArgs(userID, day).WillReturnRows(claimed(false))
+		mock.ExpectRollback()
+		res, err = r.RollOver(userID, day)
+		assert.NoError(t, err)
+		assert.False(t, res)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("a failure rolls back the whole rollover", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(claim).WithArgs(userID, day).WillReturnRows(claimed(true))
+		mock.ExpectExec(deferToday).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(moveTomorrow).WithArgs(userID).WillReturnError(&pq.Error{})
+		mock.ExpectRollback()
+		res, err = r.RollOver(userID, day)
+		assert.Error(t, err)
+		assert.False(t, res)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(claim).WithArgs(userID, day).WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		mock.ExpectRollback()
+		res, err = r.RollOver(userID, day)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.False(t, res)
 	})
 }
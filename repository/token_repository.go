@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/lib/pq"
+	"log"
+	"noda/data/model"
+	"noda/failure"
+	"time"
+)
+
+type TokenRepository interface {
+	Save(userID, sessionID, hash string, expiresAt time.Time) (insertedID string, err error)
+	FetchByHash(hash string) (token *model.RefreshToken, err error)
+	Revoke(tokenID string) (ok bool, err error)
+	RevokeSession(userID, sessionID string) (ok bool, err error)
+	RevokeAll(userID string) (ok bool, err error)
+	DenySession(sessionID string, until time.Time) error
+	FetchSessionDenial(sessionID string) (until *time.Time, err error)
+	PurgeSessionDenials() (purged int64, err error)
+}
+
+type tokenRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRepository(db *sql.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) Save(userID, sessionID, hash string, expiresAt time.Time) (insertedID string, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tokens"."make" ($1, $2, $3, $4);`
+	var row = r.db.QueryRowContext(ctx, query, userID, sessionID, hash, expiresAt)
+	err = row.Scan(&insertedID)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return "", failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return "", err
+	}
+	return insertedID, nil
+}
+
+func (r *tokenRepository) FetchByHash(hash string) (token *model.RefreshToken, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT * FROM "tokens"."fetch_by_hash" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, hash)
+	token = new(model.RefreshToken)
+	err = row.Scan(
+		&token.UUID,
+		&token.UserUUID,
+		&token.SessionUUID,
+		&token.Hash,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.CreatedAt)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, failure.ErrInvalidRefreshToken
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// Revoke revokes a refresh token. It is false if the token was already revoked,
+// which makes it safe to rotate a token only once under concurrent requests.
+func (r *tokenRepository) Revoke(tokenID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tokens"."revoke" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, tokenID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// RevokeSession revokes every refresh token of a session of the user.
+func (r *tokenRepository) RevokeSession(userID, sessionID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tokens"."revoke_session" ($1, $2);`
+	var row = r.db.QueryRowContext(ctx, query, userID, sessionID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// RevokeAll revokes every refresh token of the user, of all its sessions.
+func (r *tokenRepository) RevokeAll(userID string) (ok bool, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tokens"."revoke_all" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, userID)
+	err = row.Scan(&ok)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			switch {
+			default:
+				log.Println(failure.PQErrorToString(pqerr))
+			case isNonexistentUserError(pqerr):
+				return false, failure.ErrUserNoLongerExists
+			}
+		} else {
+			log.Println(err)
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
+// DenySession denies the access tokens of a session until the given moment, or
+// later if it was already denied for longer.
+func (r *tokenRepository) DenySession(sessionID string, until time.Time) error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tokens"."deny_session" ($1, $2);`
+	_, err := r.db.ExecContext(ctx, query, sessionID, until)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// FetchSessionDenial retrieves the moment until which the access tokens of a
+// session are denied. It is nil if they are not, or no longer, denied.
+func (r *tokenRepository) FetchSessionDenial(sessionID string) (until *time.Time, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tokens"."fetch_session_denial" ($1);`
+	var row = r.db.QueryRowContext(ctx, query, sessionID)
+	var deniedUntil sql.NullTime
+	err = row.Scan(&deniedUntil)
+	if nil != err {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return nil, err
+	}
+	if !deniedUntil.Valid {
+		return nil, nil
+	}
+	return &deniedUntil.Time, nil
+}
+
+// PurgeSessionDenials forgets the sessions whose access tokens have all
+// expired by now.
+func (r *tokenRepository) PurgeSessionDenials() (purged int64, err error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var query = `SELECT "tokens"."purge_session_denials" ();`
+	var row = r.db.QueryRowContext(ctx, query)
+	err = row.Scan(&purged)
+	if nil != err {
+		var pqerr *pq.Error
+		if errors.As(err, &pqerr) {
+			log.Println(failure.PQErrorToString(pqerr))
+		} else {
+			log.Println(err)
+		}
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/failure"
+	"regexp"
+	"testing"
+	"time"
+)
+
+const (
+	sessionID = "3b8c7c1e-2f4d-4b8a-9c61-5d0e7a9f2b13"
+	tokenID   = "c2a4e6f8-1b3d-4e5f-8a7b-9c0d1e2f3a4b"
+)
+
+func TestTokenRepository_Save(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r         = NewTokenRepository(db)
+		query     = regexp.QuoteMeta(`SELECT "tokens"."make" ($1, $2, $3, $4);`)
+		hash      = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+		expiresAt = time.Now().Add(time.Hour)
+		res       string
+		err       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, sessionID, hash, expiresAt).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"make"}).
+				AddRow(tokenID))
+		res, err = r.Save(userID, sessionID, hash, expiresAt)
+		assert.NoError(t, err)
+		assert.Equal(t, tokenID, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		res, err = r.Save(userID, sessionID, hash, expiresAt)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Equal(t, "", res)
+	})
+}
+
+func TestTokenRepository_FetchByHash(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTokenRepository(db)
+		query = regexp.QuoteMeta(`SELECT * FROM "tokens"."fetch_by_hash" ($1);`)
+		token = &model.RefreshToken{
+			UUID:        uuid.MustParse(tokenID),
+			UserUUID:    uuid.MustParse(userID),
+			SessionUUID: uuid.MustParse(sessionID),
+			Hash:        "hash",
+			ExpiresAt:   time.Now().Add(time.Hour),
+			CreatedAt:   time.Now(),
+		}
+		columns = []string{"token_uuid", "user_uuid", "session_uuid", "hash", "expires_at", "revoked_at", "created_at"}
+		res     *model.RefreshToken
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(token.Hash).
+			WillReturnRows(sqlmock.
+				NewRows(columns).
+				AddRow(token.UUID, token.UserUUID, token.SessionUUID, token.Hash, token.ExpiresAt, token.RevokedAt, token.CreatedAt))
+		res, err = r.FetchByHash(token.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, token, res)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(sql.ErrNoRows)
+		res, err = r.FetchByHash(token.Hash)
+		assert.ErrorIs(t, err, failure.ErrInvalidRefreshToken)
+		assert.Nil(t, res)
+	})
+}
+
+func TestTokenRepository_Revoke(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTokenRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tokens"."revoke" ($1);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(tokenID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"revoke"}).
+				AddRow(true))
+		res, err = r.Revoke(tokenID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err = r.Revoke(tokenID)
+		assert.Error(t, err)
+		assert.False(t, res)
+	})
+}
+
+func TestTokenRepository_RevokeSession(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTokenRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tokens"."revoke_session" ($1, $2);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, sessionID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"revoke_session"}).
+				AddRow(true))
+		res, err = r.RevokeSession(userID, sessionID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		res, err = r.RevokeSession(userID, sessionID)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.False(t, res)
+	})
+}
+
+func TestTokenRepository_RevokeAll(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTokenRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tokens"."revoke_all" ($1);`)
+		res   bool
+		err   error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.
+				NewRows([]string{"revoke_all"}).
+				AddRow(true))
+		res, err = r.RevokeAll(userID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		res, err = r.RevokeAll(userID)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.False(t, res)
+	})
+}
+
+func TestTokenRepository_DenySession(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTokenRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tokens"."deny_session" ($1, $2);`)
+		until = time.Now().Add(time.Hour)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WithArgs(sessionID, until).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		assert.NoError(t, r.DenySession(sessionID, until))
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectExec(query).
+			WillReturnError(&pq.Error{})
+		assert.Error(t, r.DenySession(sessionID, until))
+	})
+}
+
+func TestTokenRepository_FetchSessionDenial(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTokenRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tokens"."fetch_session_denial" ($1);`)
+		until = time.Now().Add(time.Hour)
+	)
+
+	t.Run("denied", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(sessionID).
+			WillReturnRows(sqlmock.NewRows([]string{"fetch_session_denial"}).AddRow(until))
+		res, err := r.FetchSessionDenial(sessionID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, res) {
+			assert.True(t, until.Equal(*res))
+		}
+	})
+
+	t.Run("not denied", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(sessionID).
+			WillReturnRows(sqlmock.NewRows([]string{"fetch_session_denial"}).AddRow(nil))
+		res, err := r.FetchSessionDenial(sessionID)
+		assert.NoError(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnError(&pq.Error{})
+		res, err := r.FetchSessionDenial(sessionID)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestTokenRepository_PurgeSessionDenials(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewTokenRepository(db)
+		query = regexp.QuoteMeta(`SELECT "tokens"."purge_session_denials" ();`)
+	)
+	mock.
+		ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"purge_session_denials"}).AddRow(3))
+	purged, err := r.PurgeSessionDenials()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
@@ -9,6 +9,7 @@ import (
 	"noda/data/model"
 	"noda/data/transfer"
 	"noda/failure"
+	"time"
 )
 
 type UserRepository interface {
@@ -23,13 +24,18 @@ type UserRepository interface {
 	FetchOneSetting(userID string, settingKey string) (setting *transfer.UserSetting, err error)
 	Search(page, rpp int64, needle, sortExpr string) (users []*transfer.User, err error)
 	Update(id string, update *transfer.UserUpdate) (ok bool, err error)
+	UpdatePassword(id, hashedPassword string) (ok bool, err error)
 	UpdateUserSetting(userID, settingKey, newValue string) (ok bool, err error)
-	Block(id string) (ok bool, err error)
+	FetchStatus(id string) (status *model.UserStatus, err error)
+	Block(id, reason string, until *time.Time) (ok bool, err error)
 	Unblock(id string) (ok bool, err error)
 	PromoteToAdmin(id string) (ok bool, err error)
 	DegradeToUser(id string) (ok bool, err error)
 	RemoveHardly(id string) error
-	RemoveSoftly(id string) error
+	RemoveSoftly(id string, purgeAt time.Time) error
+	Restore(id string) (ok bool, err error)
+	FetchDeleted(page, rpp int64, needle, sortExpr string) (users []*transfer.DeletedUser, err error)
+	PurgeDeleted() (purged int64, err error)
 }
 
 type userRepository struct {
@@ -80,6 +86,25 @@ func (r userRepository) Update(userID string, up *transfer.UserUpdate) (bool, er
 	return wasUpdated, nil
 }
 
+func (r userRepository) UpdatePassword(userID, hashedPassword string) (bool, error) {
+	row := r.db.QueryRow(`SELECT "users"."update_password" ($1, $2);`, userID, hashedPassword)
+	var wasUpdated bool
+	if err := row.Scan(&wasUpdated); err != nil {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.As(err, &pqerr):
+			if isNonexistentUserError(pqerr) {
+				return false, failure.ErrUserNotFound
+			}
+			log.Println(failure.PQErrorToString(pqerr))
+		}
+		return false, err
+	}
+	return wasUpdated, nil
+}
+
 func (r userRepository) PromoteToAdmin(userID string) (bool, error) {
 	row := r.db.QueryRow(`SELECT "users"."promote_to_admin" ($1);`, userID)
 	var wasPromoted bool
@@ -118,8 +143,11 @@ func (r userRepository) DegradeToUser(userID string) (bool, error) {
 	return wasDegraded, nil
 }
 
-func (r userRepository) Block(userID string) (bool, error) {
-	row := r.db.QueryRow(`SELECT "users"."block" ($1);`, userID)
+// Block blocks the user until the given time, or until it is unblocked if
+// until is nil. An empty reason is stored as NULL.
+func (r userRepository) Block(userID, reason string, until *time.Time) (bool, error) {
+	var nullableReason = sql.NullString{String: reason, Valid: "" != reason}
+	row := r.db.QueryRow(`SELECT "users"."block" ($1, $2, $3);`, userID, nullableReason, until)
 	var wasBlocked bool
 	if err := row.Scan(&wasBlocked); err != nil {
 		var pqerr *pq.Error
@@ -137,6 +165,41 @@ func (r userRepository) Block(userID string) (bool, error) {
 	return wasBlocked, nil
 }
 
+func (r userRepository) FetchStatus(userID string) (*model.UserStatus, error) {
+	row := r.db.QueryRow(`
+	SELECT "role_id",
+	       "is_blocked",
+	       "block_reason",
+	       "blocked_until",
+	       "deleted_at",
+	       "purge_at"
+	  FROM "users"."fetch_status" ($1);`, userID)
+	var status model.UserStatus
+	err := row.Scan(
+		&status.Role,
+		&status.IsBlocked,
+		&status.BlockReason,
+		&status.BlockedUntil,
+		&status.DeletedAt,
+		&status.PurgeAt)
+	if err != nil {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, failure.ErrUserNotFound
+		case errors.As(err, &pqerr):
+			if isNonexistentUserError(pqerr) {
+				return nil, failure.ErrUserNotFound
+			}
+			log.Println(failure.PQErrorToString(pqerr))
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
 func (r userRepository) Unblock(userID string) (bool, error) {
 	row := r.db.QueryRow(`SELECT "users"."unblock" ($1);`, userID)
 	var wasUnblocked bool
@@ -494,24 +557,96 @@ func (r userRepository) RemoveHardly(userID string) error {
 	return nil
 }
 
-func (r userRepository) RemoveSoftly(userID string) error {
-	var (
-		query = `SELECT "users"."delete_hardly" ($1);` // TODO: use soft delete
-		row   = r.db.QueryRow(query, userID)
-	)
-	var err = row.Err()
+// RemoveSoftly deactivates the user until it is purged at purgeAt. In the
+// meantime the user cannot sign in and it can still be restored.
+func (r userRepository) RemoveSoftly(userID string, purgeAt time.Time) error {
+	err := r.db.
+		QueryRow(`SELECT "users"."delete_softly" ($1, $2);`, userID, purgeAt).
+		Err()
 	if err != nil {
 		var pqerr *pq.Error
 		switch {
 		default:
 			log.Println(err)
-			return err
 		case errors.As(err, &pqerr):
+			if isNonexistentUserError(pqerr) {
+				return failure.ErrUserNotFound
+			}
 			log.Println(failure.PQErrorToString(pqerr))
-			return err
-		case errors.Is(err, sql.ErrNoRows):
-			return failure.ErrUserNotFound
 		}
+		return err
 	}
 	return nil
 }
+
+func (r userRepository) Restore(userID string) (bool, error) {
+	row := r.db.QueryRow(`SELECT "users"."restore" ($1);`, userID)
+	var wasRestored bool
+	if err := row.Scan(&wasRestored); err != nil {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.As(err, &pqerr):
+			if isNonexistentUserError(pqerr) {
+				return false, failure.ErrUserNotFound
+			}
+			log.Println(failure.PQErrorToString(pqerr))
+		}
+		return false, err
+	}
+	return wasRestored, nil
+}
+
+func (r userRepository) FetchDeleted(page, rpp int64, needle, sortExpr string) ([]*transfer.DeletedUser, error) {
+	query := `
+	SELECT "user_uuid" AS "uuid",
+	       "role_id" AS "role",
+	       "first_name",
+	       "middle_name",
+	       "last_name",
+	       "surname",
+	       "picture_url",
+	       "email",
+	       "created_at",
+	       "updated_at",
+	       "deleted_at",
+	       "purge_at"
+	  FROM "users"."fetch_deleted" ($1, $2, $3, $4);`
+	rows, err := r.db.Query(query, page, rpp, needle, sortExpr)
+	if err != nil {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.As(err, &pqerr):
+			log.Println(failure.PQErrorToString(pqerr))
+		}
+		return nil, err
+	}
+	defer rows.Close()
+	var users = make([]*transfer.DeletedUser, 0)
+	if err = sqlscan.ScanAll(&users, rows); err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return users, nil
+}
+
+// PurgeDeleted permanently removes the deleted users whose grace period is
+// over, and returns how many were removed.
+func (r userRepository) PurgeDeleted() (int64, error) {
+	row := r.db.QueryRow(`SELECT "users"."purge_deleted" ();`)
+	var purged int64
+	if err := row.Scan(&purged); err != nil {
+		var pqerr *pq.Error
+		switch {
+		default:
+			log.Println(err)
+		case errors.As(err, &pqerr):
+			log.Println(failure.PQErrorToString(pqerr))
+		}
+		return 0, err
+	}
+	return purged, nil
+}
@@ -1,13 +1,14 @@
 package repository
 
 import (
-	"database/sql"
 	"github.com/google/uuid"
 	"noda/data/model"
 	"noda/data/transfer"
+	"noda/data/types"
 	"noda/failure"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/lib/pq"
@@ -781,6 +782,49 @@ func TestUserRepository_UpdateUserSetting(t *testing.T) {
 	})
 }
 
+func TestUserRepository_UpdatePassword(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r      = NewUserRepository(db)
+		res    bool
+		err    error
+		hashed = "$2a$10$2Vn0bq9Ojg1N5bOQ8N2Wbe3ZPq9x6PZ0Ugp3f7Qq1u8j3Q6s1v7i."
+		query  = regexp.QuoteMeta(`SELECT "users"."update_password" ($1, $2);`)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, hashed).
+			WillReturnRows(sqlmock.NewRows([]string{"update_password"}).AddRow(true))
+		res, err = r.UpdatePassword(userID, hashed)
+		assert.NoError(t, err)
+		assert.Equal(t, res, true)
+	})
+
+	t.Run("got not found user error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, hashed).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		res, err = r.UpdatePassword(userID, hashed)
+		assert.ErrorIs(t, err, failure.ErrUserNotFound)
+		assert.Equal(t, res, false)
+	})
+
+	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, hashed).
+			WillReturnError(&pq.Error{})
+		res, err = r.UpdatePassword(userID, hashed)
+		assert.Error(t, err)
+		assert.Equal(t, res, false)
+	})
+}
+
 func TestUserRepository_Block(t *testing.T) {
 	defer beQuiet()()
 	db, mock := newMock()
@@ -789,15 +833,16 @@ func TestUserRepository_Block(t *testing.T) {
 		r     = NewUserRepository(db)
 		res   bool
 		err   error
-		query = regexp.QuoteMeta(`SELECT "users"."block" ($1);`)
+		until = time.Now().Add(24 * time.Hour)
+		query = regexp.QuoteMeta(`SELECT "users"."block" ($1, $2, $3);`)
 	)
 
 	t.Run("success", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID).
+			WithArgs(userID, "spam", until).
 			WillReturnRows(sqlmock.NewRows([]string{"block_user"}).AddRow(true))
-		res, err = r.Block(userID)
+		res, err = r.Block(userID, "spam", &until)
 		assert.NoError(t, err)
 		assert.Equal(t, res, true)
 	})
@@ -805,9 +850,9 @@ func TestUserRepository_Block(t *testing.T) {
 	t.Run("could not block but didn't get any error", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID).
+			WithArgs(userID, nil, nil).
 			WillReturnRows(sqlmock.NewRows([]string{"block_user"}).AddRow(false))
-		res, err = r.Block(userID)
+		res, err = r.Block(userID, "", nil)
 		assert.NoError(t, err)
 		assert.Equal(t, res, false)
 	})
@@ -815,9 +860,9 @@ func TestUserRepository_Block(t *testing.T) {
 	t.Run("got not found user error", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID).
+			WithArgs(userID, nil, nil).
 			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
-		res, err = r.Block(userID)
+		res, err = r.Block(userID, "", nil)
 		assert.ErrorIs(t, err, failure.ErrUserNotFound)
 		assert.Equal(t, res, false)
 	})
@@ -825,14 +870,78 @@ func TestUserRepository_Block(t *testing.T) {
 	t.Run("unexpected database error", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID).
+			WithArgs(userID, nil, nil).
 			WillReturnError(&pq.Error{})
-		res, err = r.Block(userID)
+		res, err = r.Block(userID, "", nil)
 		assert.Error(t, err)
 		assert.Equal(t, res, false)
 	})
 }
 
+func TestUserRepository_FetchStatus(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r      = NewUserRepository(db)
+		res    *model.UserStatus
+		err    error
+		reason = "spam"
+		until  = time.Now().Add(24 * time.Hour)
+		query  = regexp.QuoteMeta(`
+	SELECT "role_id",
+	       "is_blocked",
+	       "block_reason",
+	       "blocked_until",
+	       "deleted_at",
+	       "purge_at"
+	  FROM "users"."fetch_status" ($1);`)
+		columns = []string{"role_id", "is_blocked", "block_reason", "blocked_until", "deleted_at", "purge_at"}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(types.RoleUser, true, reason, until, nil, nil))
+		res, err = r.FetchStatus(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.UserStatus{Role: types.RoleUser, IsBlocked: true, BlockReason: &reason, BlockedUntil: &until}, res)
+	})
+
+	t.Run("not blocked", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(types.RoleAdmin, false, nil, nil, nil, nil))
+		res, err = r.FetchStatus(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.UserStatus{Role: types.RoleAdmin}, res)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		var deletedAt, purgeAt = time.Now(), time.Now().Add(24 * time.Hour)
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(types.RoleUser, false, nil, nil, deletedAt, purgeAt))
+		res, err = r.FetchStatus(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.UserStatus{Role: types.RoleUser, DeletedAt: &deletedAt, PurgeAt: &purgeAt}, res)
+		assert.True(t, res.Deleted())
+	})
+
+	t.Run("got not found user error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		res, err = r.FetchStatus(userID)
+		assert.ErrorIs(t, err, failure.ErrUserNotFound)
+		assert.Nil(t, res)
+	})
+}
+
 func TestUserRepository_Unblock(t *testing.T) {
 	defer beQuiet()()
 	db, mock := newMock()
@@ -1038,37 +1147,176 @@ func TestUserRepository_RemoveSoftly(t *testing.T) {
 	db, mock := newMock()
 	defer db.Close()
 	var (
-		r     = NewUserRepository(db)
-		query = regexp.QuoteMeta(`SELECT "users"."delete_hardly" ($1);`)
-		err   error
+		r       = NewUserRepository(db)
+		query   = regexp.QuoteMeta(`SELECT "users"."delete_softly" ($1, $2);`)
+		purgeAt = time.Now().Add(24 * time.Hour)
+		err     error
 	)
 
 	t.Run("success", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID).
+			WithArgs(userID, purgeAt).
 			WillReturnRows(sqlmock.
-				NewRows([]string{"delete_user_hardly"}).
+				NewRows([]string{"delete_softly"}).
 				AddRow(true))
-		err = r.RemoveSoftly(userID)
+		err = r.RemoveSoftly(userID, purgeAt)
 		assert.NoError(t, err)
 	})
 
 	t.Run("could not remove user", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
-			WithArgs(userID).
-			WillReturnError(sql.ErrNoRows)
-		err = r.RemoveSoftly(userID)
+			WithArgs(userID, purgeAt).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		err = r.RemoveSoftly(userID, purgeAt)
 		assert.ErrorIs(t, err, failure.ErrUserNotFound)
 	})
 
 	t.Run("unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID, purgeAt).
+			WillReturnError(&pq.Error{})
+		err = r.RemoveSoftly(userID, purgeAt)
+		assert.Error(t, err)
+	})
+}
+
+func TestUserRepository_Restore(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewUserRepository(db)
+		res   bool
+		err   error
+		query = regexp.QuoteMeta(`SELECT "users"."restore" ($1);`)
+	)
+
+	t.Run("success", func(t *testing.T) {
 		mock.
 			ExpectQuery(query).
 			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"restore"}).AddRow(true))
+		res, err = r.Restore(userID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("the user was not deleted", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"restore"}).AddRow(false))
+		res, err = r.Restore(userID)
+		assert.NoError(t, err)
+		assert.False(t, res)
+	})
+
+	t.Run("got not found user error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(userID).
+			WillReturnError(&pq.Error{Code: "P0001", Message: "nonexistent user with UUID"})
+		res, err = r.Restore(userID)
+		assert.ErrorIs(t, err, failure.ErrUserNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestUserRepository_FetchDeleted(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r         = NewUserRepository(db)
+		res       []*transfer.DeletedUser
+		err       error
+		page, rpp int64 = 1, 2
+		needle          = "foo"
+		sortExpr        = "-first_name"
+		deletedAt       = time.Now()
+		purgeAt         = deletedAt.Add(24 * time.Hour)
+		columns         = []string{"uuid", "role", "first_name", "middle_name", "last_name", "surname", "picture_url", "email", "created_at", "updated_at", "deleted_at", "purge_at"}
+		query           = regexp.QuoteMeta(`
+	SELECT "user_uuid" AS "uuid",
+	       "role_id" AS "role",
+	       "first_name",
+	       "middle_name",
+	       "last_name",
+	       "surname",
+	       "picture_url",
+	       "email",
+	       "created_at",
+	       "updated_at",
+	       "deleted_at",
+	       "purge_at"
+	  FROM "users"."fetch_deleted" ($1, $2, $3, $4);`)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var rows = sqlmock.
+			NewRows(columns).
+			AddRow(userID, types.RoleUser, "foo", "", "bar", "", nil, "foo@bar.com", deletedAt, deletedAt, deletedAt, purgeAt).
+			AddRow(userID, types.RoleUser, "foo", "", "bar", "", nil, "foo@bar.com", deletedAt, deletedAt, deletedAt, purgeAt)
+		mock.
+			ExpectQuery(query).
+			WithArgs(page, rpp, needle, sortExpr).
+			WillReturnRows(rows)
+		res, err = r.FetchDeleted(page, rpp, needle, sortExpr)
+		assert.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, &transfer.DeletedUser{
+			User: transfer.User{
+				UUID:      uuid.MustParse(userID),
+				Role:      types.RoleUser,
+				FirstName: "foo",
+				LastName:  "bar",
+				Email:     "foo@bar.com",
+				CreatedAt: deletedAt,
+				UpdatedAt: deletedAt,
+			},
+			DeletedAt: deletedAt,
+			PurgeAt:   purgeAt,
+		}, res[1])
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WithArgs(page, rpp, needle, sortExpr).
+			WillReturnError(new(pq.Error))
+		res, err = r.FetchDeleted(page, rpp, needle, sortExpr)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestUserRepository_PurgeDeleted(t *testing.T) {
+	defer beQuiet()()
+	db, mock := newMock()
+	defer db.Close()
+	var (
+		r     = NewUserRepository(db)
+		query = regexp.QuoteMeta(`SELECT "users"."purge_deleted" ();`)
+	)
+
+	t.Run("success", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"purge_deleted"}).AddRow(3))
+		purged, err := r.PurgeDeleted()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+	})
+
+	t.Run("got an unexpected database error", func(t *testing.T) {
+		mock.
+			ExpectQuery(query).
 			WillReturnError(&pq.Error{})
-		err = r.RemoveSoftly(userID)
+		purged, err := r.PurgeDeleted()
 		assert.Error(t, err)
+		assert.Zero(t, purged)
 	})
 }
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// AdvisoryLock is a Leader elected through a PostgreSQL session-level advisory
+// lock. The instance that takes the lock keeps it, on a dedicated connection,
+// until the connection is lost or the lock is released; the other instances
+// try to take it on every call to Lead.
+type AdvisoryLock struct {
+	db   *sql.DB
+	key  int64
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewAdvisoryLock(db *sql.DB, key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: db, key: key}
+}
+
+func (l *AdvisoryLock) Lead() (bool, error) {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if nil != l.conn {
+		if err := l.conn.PingContext(ctx); nil == err {
+			return true, nil
+		}
+		/* The session is gone and so is the lock.  */
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+	conn, err := l.db.Conn(ctx)
+	if nil != err {
+		return false, err
+	}
+	var locked bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock ($1);`, l.key).Scan(&locked)
+	if nil != err || !locked {
+		_ = conn.Close()
+		return false, err
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// Release gives up the lock, if it was taken, so that another instance can
+// lead.
+func (l *AdvisoryLock) Release() error {
+	var ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if nil == l.conn {
+		return nil
+	}
+	var _, err = l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock ($1);`, l.key)
+	err = errors.Join(err, l.conn.Close())
+	l.conn = nil
+	return err
+}
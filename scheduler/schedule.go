@@ -0,0 +1,184 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrBadSchedule is returned when a schedule cannot be parsed.
+var ErrBadSchedule = errors.New("bad schedule")
+
+// Schedule is a cron schedule: the minutes, hours, days of the month, months
+// and days of the week at which a job runs.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type bounds struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	doms    = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dows = bounds{0, 7, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// Parse parses a schedule in the standard five-field cron format
+// ("minute hour day-of-month month day-of-week"), with lists, ranges, steps
+// and the names of months and days, or one of the descriptors @yearly,
+// @monthly, @weekly, @daily, @midnight and @hourly. As in cron, when both the
+// day of the month and the day of the week are restricted, a day matches if
+// either of them does.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+	var fields = strings.Fields(spec)
+	if 5 != len(fields) {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d in %q", ErrBadSchedule, len(fields), spec)
+	}
+	var (
+		s   = new(Schedule)
+		err error
+	)
+	if s.minute, err = parseField(fields[0], minutes); nil != err {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); nil != err {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], doms); nil != err {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); nil != err {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dows); nil != err {
+		return nil, err
+	}
+	if 0 != s.dow&(1<<7) {
+		/* Both 0 and 7 are Sunday.  */
+		s.dow |= 1
+	}
+	s.domStar = "*" == fields[2]
+	s.dowStar = "*" == fields[4]
+	return s, nil
+}
+
+func parseField(field string, b bounds) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		var (
+			rangeExpr      = part
+			step      uint = 1
+		)
+		if i := strings.IndexByte(part, '/'); -1 != i {
+			rangeExpr = part[:i]
+			n, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if nil != err || 0 == n {
+				return 0, fmt.Errorf("%w: bad step in %q", ErrBadSchedule, part)
+			}
+			step = uint(n)
+		}
+		var low, high uint
+		switch {
+		case "*" == rangeExpr:
+			low, high = b.min, b.max
+		case strings.Contains(rangeExpr, "-"):
+			var ends = strings.SplitN(rangeExpr, "-", 2)
+			if low, err = parseValue(ends[0], b); nil != err {
+				return 0, err
+			}
+			if high, err = parseValue(ends[1], b); nil != err {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("%w: bad range %q", ErrBadSchedule, rangeExpr)
+			}
+		default:
+			if low, err = parseValue(rangeExpr, b); nil != err {
+				return 0, err
+			}
+			high = low
+			if step > 1 {
+				/* "a/n" stands for "a-max/n".  */
+				high = b.max
+			}
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, b bounds) (uint, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 8)
+	if nil != err || uint(v) < b.min || uint(v) > b.max {
+		return 0, fmt.Errorf("%w: value %q out of range [%d, %d]", ErrBadSchedule, s, b.min, b.max)
+	}
+	return uint(v), nil
+}
+
+// Next returns the first time after t that matches the schedule, in the
+// location of t. It returns the zero time if the schedule never matches, as
+// in "0 0 30 2 *".
+func (s *Schedule) Next(t time.Time) time.Time {
+	var (
+		loc   = t.Location()
+		limit = t.Year() + 5
+	)
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	for t.Year() <= limit {
+		switch {
+		case 0 == s.month&(1<<uint(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case 0 == s.hour&(1<<uint(t.Hour())):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case 0 == s.minute&(1<<uint(t.Minute())):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) matchesDay(t time.Time) bool {
+	var (
+		dom = 0 != s.dom&(1<<uint(t.Day()))
+		dow = 0 != s.dow&(1<<uint(t.Weekday()))
+	)
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
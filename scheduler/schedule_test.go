@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("invalid schedules", func(t *testing.T) {
+		for _, spec := range []string{
+			"",
+			"@often",
+			"* * * *",
+			"* * * * * *",
+			"60 * * * *",
+			"* 24 * * *",
+			"* * 0 * *",
+			"* * * 13 *",
+			"* * * * 8",
+			"*/0 * * * *",
+			"5-1 * * * *",
+			"a * * * *",
+			"* * * foo *",
+		} {
+			_, err := Parse(spec)
+			assert.ErrorIs(t, err, ErrBadSchedule, spec)
+		}
+	})
+}
+
+func TestSchedule_Next(t *testing.T) {
+	var cases = []struct {
+		spec     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"* * * * *", at(2024, time.January, 1, 10, 30), at(2024, time.January, 1, 10, 31)},
+		{"*/15 * * * *", at(2024, time.January, 1, 10, 30), at(2024, time.January, 1, 10, 45)},
+		{"*/15 * * * *", at(2024, time.January, 1, 23, 50), at(2024, time.January, 2, 0, 0)},
+		{"@hourly", at(2024, time.January, 1, 10, 0), at(2024, time.January, 1, 11, 0)},
+		{"@daily", at(2024, time.December, 31, 10, 0), at(2025, time.January, 1, 0, 0)},
+		{"30 2 * * mon-fri", at(2024, time.January, 5, 3, 0), at(2024, time.January, 8, 2, 30)},
+		{"0 9 * * 7", at(2024, time.January, 1, 0, 0), at(2024, time.January, 7, 9, 0)},
+		{"0 0 29 feb *", at(2024, time.March, 1, 0, 0), at(2028, time.February, 29, 0, 0)},
+		{"5/20 8-10 * * *", at(2024, time.January, 1, 9, 50), at(2024, time.January, 1, 10, 5)},
+		{"0 0 1,15 * *", at(2024, time.January, 2, 0, 0), at(2024, time.January, 15, 0, 0)},
+		/* Either the day of the month or the day of the week.  */
+		{"0 0 13 * fri", at(2024, time.January, 1, 0, 0), at(2024, time.January, 5, 0, 0)},
+		{"0 0 30 2 *", at(2024, time.January, 1, 0, 0), time.Time{}},
+	}
+	for _, c := range cases {
+		schedule, err := Parse(c.spec)
+		if assert.NoError(t, err, c.spec) {
+			assert.Equal(t, c.expected, schedule.Next(c.from), c.spec)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Job is a unit of background work. The error it returns is recorded as the
+// failure of the run.
+type Job func() error
+
+// Leader decides whether this instance of the server is the one that runs the
+// jobs, so that a job does not run once per instance.
+type Leader interface {
+	Lead() (bool, error)
+}
+
+// Recorder keeps the history of the runs of the jobs. An empty errMessage
+// means that the run succeeded.
+type Recorder interface {
+	Start(job, instance string) (runID string, err error)
+	Finish(runID, errMessage string) (ok bool, err error)
+}
+
+type entry struct {
+	name     string
+	schedule *Schedule
+	job      Job
+	next     time.Time
+	running  atomic.Bool
+}
+
+// Scheduler runs jobs on cron schedules, evaluated in UTC. A job is not
+// started again while its previous run is still going.
+type Scheduler struct {
+	leader   Leader
+	recorder Recorder
+	instance string
+	now      func() time.Time
+	mu       sync.Mutex
+	entries  []*entry
+	running  sync.WaitGroup
+}
+
+func New(leader Leader, recorder Recorder) *Scheduler {
+	hostname, err := os.Hostname()
+	if nil != err {
+		hostname = "unknown"
+	}
+	return &Scheduler{
+		leader:   leader,
+		recorder: recorder,
+		instance: fmt.Sprintf("%s:%d", hostname, os.Getpid()),
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Register adds a job that runs on the given schedule. See Parse for the
+// format of spec.
+func (s *Scheduler) Register(name, spec string, job Job) error {
+	schedule, err := Parse(spec)
+	if nil != err {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if e.name == name {
+			return fmt.Errorf("job %q is already registered", name)
+		}
+	}
+	s.entries = append(s.entries, &entry{
+		name:     name,
+		schedule: schedule,
+		job:      job,
+		next:     schedule.Next(s.now()),
+	})
+	return nil
+}
+
+// Run runs the jobs until ctx is done, and then waits for the running ones to
+// finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		var timer = time.NewTimer(s.untilNext())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.running.Wait()
+			return
+		case <-timer.C:
+			s.tick(s.now())
+		}
+	}
+}
+
+// untilNext is how long to wait for the next job to be due.
+func (s *Scheduler) untilNext() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, e := range s.entries {
+		if !e.next.IsZero() && (next.IsZero() || e.next.Before(next)) {
+			next = e.next
+		}
+	}
+	if next.IsZero() {
+		return time.Minute
+	}
+	return max(next.Sub(s.now()), 0)
+}
+
+// tick starts the jobs that are due at now, if this instance leads. The due
+// jobs are scheduled again either way.
+func (s *Scheduler) tick(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*entry
+	for _, e := range s.entries {
+		if !e.next.IsZero() && !e.next.After(now) {
+			due = append(due, e)
+			e.next = e.schedule.Next(now)
+		}
+	}
+	if 0 == len(due) {
+		return
+	}
+	lead, err := s.leader.Lead()
+	if nil != err {
+		log.Printf("could not tell whether this instance leads the scheduler: %v", err)
+		return
+	}
+	if !lead {
+		return
+	}
+	for _, e := range due {
+		if !e.running.CompareAndSwap(false, true) {
+			log.Printf("job %q is still running, skipping this run", e.name)
+			continue
+		}
+		s.running.Add(1)
+		go s.run(e)
+	}
+}
+
+func (s *Scheduler) run(e *entry) {
+	defer s.running.Done()
+	defer e.running.Store(false)
+	runID, err := s.recorder.Start(e.name, s.instance)
+	if nil != err {
+		log.Printf("could not record the start of job %q: %v", e.name, err)
+	}
+	var message = s.call(e.job)
+	if "" != message {
+		log.Printf("job %q failed: %s", e.name, message)
+	}
+	if "" == runID {
+		return
+	}
+	if _, err = s.recorder.Finish(runID, message); nil != err {
+		log.Printf("could not record the end of job %q: %v", e.name, err)
+	}
+}
+
+// call calls the job and returns why it failed, if it did.
+func (s *Scheduler) call(job Job) (message string) {
+	defer func() {
+		if r := recover(); nil != r {
+			message = fmt.Sprintf("panic: %v", r)
+		}
+	}()
+	if err := job(); nil != err {
+		return err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func beQuiet() func() {
+	log.SetOutput(io.Discard)
+	return func() { log.SetOutput(os.Stderr) }
+}
+
+type leader struct {
+	lead bool
+	err  error
+}
+
+func (l *leader) Lead() (bool, error) { return l.lead, l.err }
+
+type run struct {
+	job, instance, message string
+	finished               bool
+}
+
+type recorder struct {
+	mu   sync.Mutex
+	runs []*run
+}
+
+func (r *recorder) Start(job, instance string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, &run{job: job, instance: instance})
+	return string(rune('0' + len(r.runs) - 1)), nil
+}
+
+func (r *recorder) Finish(runID, errMessage string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var run = r.runs[runID[0]-'0']
+	run.message, run.finished = errMessage, true
+	return true, nil
+}
+
+func newTestScheduler(l Leader, r Recorder, now time.Time) *Scheduler {
+	var s = New(l, r)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestScheduler_Register(t *testing.T) {
+	var s = New(&leader{}, &recorder{})
+	assert.NoError(t, s.Register("job", "@hourly", func() error { return nil }))
+	assert.Error(t, s.Register("job", "@daily", func() error { return nil }), "Names are unique.")
+	assert.ErrorIs(t, s.Register("other", "@sometimes", func() error { return nil }), ErrBadSchedule)
+}
+
+func TestScheduler_tick(t *testing.T) {
+	defer beQuiet()()
+	var start = at(2024, time.January, 1, 10, 7)
+
+	t.Run("runs the due jobs and records them", func(t *testing.T) {
+		var (
+			r     = &recorder{}
+			s     = newTestScheduler(&leader{lead: true}, r, start)
+			calls = make(map[string]int)
+			mu    sync.Mutex
+		)
+		var count = func(name string, err error) Job {
+			return func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				calls[name]++
+				return err
+			}
+		}
+		assert.NoError(t, s.Register("quarterly", "*/15 * * * *", count("quarterly", nil)))
+		assert.NoError(t, s.Register("hourly", "@hourly", count("hourly", errors.New("boom"))))
+		s.tick(at(2024, time.January, 1, 10, 14))
+		s.running.Wait()
+		assert.Empty(t, calls, "No job is due yet.")
+		s.tick(at(2024, time.January, 1, 11, 0))
+		s.running.Wait()
+		assert.Equal(t, map[string]int{"quarterly": 1, "hourly": 1}, calls)
+		assert.Len(t, r.runs, 2)
+		for _, run := range r.runs {
+			assert.True(t, run.finished)
+			assert.Equal(t, s.instance, run.instance)
+			if "hourly" == run.job {
+				assert.Equal(t, "boom", run.message)
+			} else {
+				assert.Empty(t, run.message)
+			}
+		}
+		assert.Equal(t, at(2024, time.January, 1, 11, 15), s.entries[0].next)
+		assert.Equal(t, at(2024, time.January, 1, 12, 0), s.entries[1].next)
+	})
+
+	t.Run("followers do not run jobs", func(t *testing.T) {
+		var (
+			r      = &recorder{}
+			s      = newTestScheduler(&leader{lead: false}, r, start)
+			called bool
+		)
+		assert.NoError(t, s.Register("job", "@hourly", func() error { called = true; return nil }))
+		s.tick(at(2024, time.January, 1, 11, 0))
+		s.running.Wait()
+		assert.False(t, called)
+		assert.Empty(t, r.runs)
+		assert.Equal(t, at(2024, time.January, 1, 12, 0), s.entries[0].next, "The job is scheduled again anyway.")
+	})
+
+	t.Run("a job does not overlap with itself", func(t *testing.T) {
+		var (
+			r       = &recorder{}
+			s       = newTestScheduler(&leader{lead: true}, r, start)
+			release = make(chan struct{})
+		)
+		assert.NoError(t, s.Register("slow", "* * * * *", func() error { <-release; return nil }))
+		s.tick(at(2024, time.January, 1, 10, 8))
+		s.tick(at(2024, time.January, 1, 10, 9))
+		close(release)
+		s.running.Wait()
+		assert.Len(t, r.runs, 1)
+	})
+
+	t.Run("a panicking job is recorded as failed", func(t *testing.T) {
+		var (
+			r = &recorder{}
+			s = newTestScheduler(&leader{lead: true}, r, start)
+		)
+		assert.NoError(t, s.Register("panicky", "* * * * *", func() error { panic("oops") }))
+		s.tick(at(2024, time.January, 1, 10, 8))
+		s.running.Wait()
+		if assert.Len(t, r.runs, 1) {
+			assert.Equal(t, "panic: oops", r.runs[0].message)
+		}
+	})
+}
+
+func TestAdvisoryLock(t *testing.T) {
+	const key int64 = 42
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+	var (
+		query = regexp.QuoteMeta(`SELECT pg_try_advisory_lock ($1);`)
+		lock  = NewAdvisoryLock(db, key)
+	)
+
+	mock.ExpectQuery(query).WithArgs(key).WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	lead, err := lock.Lead()
+	assert.NoError(t, err)
+	assert.False(t, lead, "Another instance holds the lock.")
+
+	mock.ExpectQuery(query).WithArgs(key).WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	lead, err = lock.Lead()
+	assert.NoError(t, err)
+	assert.True(t, lead)
+
+	lead, err = lock.Lead()
+	assert.NoError(t, err)
+	assert.True(t, lead, "The lock is kept without asking again.")
+
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock ($1);`)).WithArgs(key).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, lock.Release())
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectQuery(query).WithArgs(key).WillReturnError(sql.ErrConnDone)
+	lead, err = lock.Lead()
+	assert.Error(t, err)
+	assert.False(t, lead)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/notify"
+	"noda/repository"
+	"time"
+)
+
+// AssignmentService manages who is expected to work on the tasks.
+type AssignmentService interface {
+	Assign(userID, listID, taskID, assigneeID uuid.UUID) (ok bool, err error)
+	Unassign(userID, listID, taskID, assigneeID uuid.UUID) (ok bool, err error)
+	Fetch(userID, listID, taskID uuid.UUID) (assignees []*model.Assignee, err error)
+	FetchAssigned(userID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
+}
+
+type assignmentService struct {
+	r       repository.AssignmentRepository
+	tasks   repository.TaskRepository
+	members repository.MemberRepository
+	inbox   notify.Inbox
+}
+
+func NewAssignmentService(
+	r repository.AssignmentRepository,
+	tasks repository.TaskRepository,
+	members repository.MemberRepository,
+	inbox notify.Inbox,
+) AssignmentService {
+	return &assignmentService{r: r, tasks: tasks, members: members, inbox: inbox}
+}
+
+// Assign assigns a task to a user who can see its list. Only editors can assign
+// tasks, and the assignee is notified in its inbox unless it assigned itself.
+func (s *assignmentService) Assign(userID, listID, taskID, assigneeID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Assign", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Assign", "listID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Assign", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == assigneeID:
+		err = failure.NewNilParameterError("Assign", "assigneeID")
+		log.Println(err)
+		return false, err
+	}
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	_, err = authorizeList(s.members, assigneeID, listID, types.MemberRoleViewer)
+	if errors.Is(err, failure.ErrListNotFound) {
+		return false, failure.ErrMemberNotFound.Clone().SetDetails("The user is neither the owner nor a member of this list.")
+	}
+	if nil != err {
+		return false, err
+	}
+	ok, err = s.r.Assign(access.OwnerUUID.String(), listID.String(), taskID.String(), assigneeID.String(), userID.String())
+	if nil != err || !ok {
+		return ok, err
+	}
+	if userID != assigneeID {
+		s.notify(access.OwnerUUID, listID, taskID, assigneeID, "Assigned to you: ")
+	}
+	return true, nil
+}
+
+// Unassign takes a task away from a user. Only editors can unassign others,
+// but every assignee can unassign itself.
+func (s *assignmentService) Unassign(userID, listID, taskID, assigneeID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Unassign", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Unassign", "listID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Unassign", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == assigneeID:
+		err = failure.NewNilParameterError("Unassign", "assigneeID")
+		log.Println(err)
+		return false, err
+	}
+	var needed = types.MemberRoleEditor
+	if userID == assigneeID {
+		needed = types.MemberRoleViewer
+	}
+	access, err := authorizeList(s.members, userID, listID, needed)
+	if nil != err {
+		return false, err
+	}
+	ok, err = s.r.Unassign(access.OwnerUUID.String(), listID.String(), taskID.String(), assigneeID.String())
+	if nil != err || !ok {
+		return ok, err
+	}
+	if userID != assigneeID {
+		s.notify(access.OwnerUUID, listID, taskID, assigneeID, "No longer assigned to you: ")
+	}
+	return true, nil
+}
+
+// notify lets the assignee know about a change of its assignments. A failure
+// is logged but does not undo the change.
+func (s *assignmentService) notify(ownerID, listID, taskID, assigneeID uuid.UUID, prefix string) {
+	task, err := s.tasks.FetchByID(ownerID.String(), listID.String(), taskID.String())
+	if nil != err {
+		log.Printf("could not notify assignee %s of task %s: %v", assigneeID, taskID, err)
+		return
+	}
+	_, err = s.inbox.Save(assigneeID.String(), taskID.String(), prefix+task.Title, task.Headline, time.Now())
+	if nil != err {
+		log.Printf("could not notify assignee %s of task %s: %v", assigneeID, taskID, err)
+	}
+}
+
+func (s *assignmentService) Fetch(userID, listID, taskID uuid.UUID) (assignees []*model.Assignee, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Fetch", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Fetch", "listID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Fetch", "taskID")
+		log.Println(err)
+		return nil, err
+	}
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	return s.r.Fetch(access.OwnerUUID.String(), listID.String(), taskID.String())
+}
+
+// FetchAssigned retrieves the tasks assigned to the user across all the lists
+// it can see.
+func (s *assignmentService) FetchAssigned(
+	userID uuid.UUID,
+	pagination *types.Pagination,
+	needle, sortExpr string,
+) (result *types.Result[model.Task], err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchAssigned", "userID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("FetchAssigned", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	doTrim(&needle, &sortExpr)
+	tasks, err := s.r.FetchAssigned(userID.String(), pagination.Page, pagination.RPP, needle, sortExpr)
+	if nil != err {
+		return nil, err
+	}
+	return &types.Result[model.Task]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(tasks)),
+		Payload:   tasks,
+	}, nil
+}
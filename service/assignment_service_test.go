@@ -0,0 +1,219 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+)
+
+func TestAssignmentService_Assign(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, memberID, listID, taskID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		ownerAccess                       = &model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleOwner}
+		memberAccess                      = &model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}
+		res                               bool
+		err                               error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			tasks   = mocks.NewTaskRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+			inbox   = mocks.NewNotificationRepositoryMock()
+		)
+		members.On("FetchListAccess", ownerID.String(), listID.String()).Return(ownerAccess, nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).Return(memberAccess, nil)
+		r.On("Assign", ownerID.String(), listID.String(), taskID.String(), memberID.String(), ownerID.String()).Return(true, nil)
+		tasks.On("FetchByID", ownerID.String(), listID.String(), taskID.String()).Return(&model.Task{Title: "Write the report"}, nil)
+		inbox.On("Save", memberID.String(), taskID.String(), "Assigned to you: Write the report", "", mock.Anything).Return(uuid.NewString(), nil)
+		res, err = NewAssignmentService(r, tasks, members, inbox).Assign(ownerID, listID, taskID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+		inbox.AssertExpectations(t)
+	})
+
+	t.Run("assigning itself is not notified", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			tasks   = mocks.NewTaskRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+			inbox   = mocks.NewNotificationRepositoryMock()
+		)
+		members.On("FetchListAccess", ownerID.String(), listID.String()).Return(ownerAccess, nil)
+		r.On("Assign", ownerID.String(), listID.String(), taskID.String(), ownerID.String(), ownerID.String()).Return(true, nil)
+		res, err = NewAssignmentService(r, tasks, members, inbox).Assign(ownerID, listID, taskID, ownerID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+		inbox.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("a failed notification does not fail the assignment", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			tasks   = mocks.NewTaskRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+			inbox   = mocks.NewNotificationRepositoryMock()
+		)
+		members.On("FetchListAccess", ownerID.String(), listID.String()).Return(ownerAccess, nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).Return(memberAccess, nil)
+		r.On("Assign", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		tasks.On("FetchByID", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("unexpected error"))
+		res, err = NewAssignmentService(r, tasks, members, inbox).Assign(ownerID, listID, taskID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+		inbox.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("the assignee must see the list", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", ownerID.String(), listID.String()).Return(ownerAccess, nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).Return(nil, failure.ErrListNotFound)
+		res, err = NewAssignmentService(r, nil, members, nil).Assign(ownerID, listID, taskID, memberID)
+		assert.ErrorContains(t, err, "neither the owner nor a member")
+		assert.False(t, res)
+		r.AssertNotCalled(t, "Assign")
+	})
+
+	t.Run("viewers cannot assign", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", memberID.String(), listID.String()).Return(memberAccess, nil)
+		res, err = NewAssignmentService(r, nil, members, nil).Assign(memberID, listID, taskID, memberID)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		assert.False(t, res)
+		r.AssertNotCalled(t, "Assign")
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var r = mocks.NewAssignmentRepositoryMock()
+		_, err = NewAssignmentService(r, nil, nil, nil).Assign(uuid.Nil, listID, taskID, memberID)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Assign", "userID").Error())
+		_, err = NewAssignmentService(r, nil, nil, nil).Assign(ownerID, uuid.Nil, taskID, memberID)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Assign", "listID").Error())
+		_, err = NewAssignmentService(r, nil, nil, nil).Assign(ownerID, listID, uuid.Nil, memberID)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Assign", "taskID").Error())
+		_, err = NewAssignmentService(r, nil, nil, nil).Assign(ownerID, listID, taskID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Assign", "assigneeID").Error())
+		r.AssertNotCalled(t, "Assign")
+	})
+}
+
+func TestAssignmentService_Unassign(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, memberID, listID, taskID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		memberAccess                      = &model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}
+		res                               bool
+		err                               error
+	)
+
+	t.Run("a viewer unassigns itself", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+			inbox   = mocks.NewNotificationRepositoryMock()
+		)
+		members.On("FetchListAccess", memberID.String(), listID.String()).Return(memberAccess, nil)
+		r.On("Unassign", ownerID.String(), listID.String(), taskID.String(), memberID.String()).Return(true, nil)
+		res, err = NewAssignmentService(r, nil, members, inbox).Unassign(memberID, listID, taskID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+		inbox.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("an editor unassigns others and they are notified", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			tasks   = mocks.NewTaskRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+			inbox   = mocks.NewNotificationRepositoryMock()
+		)
+		members.On("FetchListAccess", ownerID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleOwner}, nil)
+		r.On("Unassign", ownerID.String(), listID.String(), taskID.String(), memberID.String()).Return(true, nil)
+		tasks.On("FetchByID", ownerID.String(), listID.String(), taskID.String()).Return(&model.Task{Title: "Write the report"}, nil)
+		inbox.On("Save", memberID.String(), taskID.String(), "No longer assigned to you: Write the report", "", mock.Anything).Return(uuid.NewString(), nil)
+		res, err = NewAssignmentService(r, tasks, members, inbox).Unassign(ownerID, listID, taskID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+		inbox.AssertExpectations(t)
+	})
+
+	t.Run("a viewer cannot unassign others", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", memberID.String(), listID.String()).Return(memberAccess, nil)
+		res, err = NewAssignmentService(r, nil, members, nil).Unassign(memberID, listID, taskID, ownerID)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		assert.False(t, res)
+		r.AssertNotCalled(t, "Unassign")
+	})
+}
+
+func TestAssignmentService_Fetch(t *testing.T) {
+	defer beQuiet()()
+	var ownerID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r         = mocks.NewAssignmentRepositoryMock()
+			assignees = []*model.Assignee{{}, {}}
+		)
+		r.On("Fetch", ownerID.String(), listID.String(), taskID.String()).Return(assignees, nil)
+		res, err := NewAssignmentService(r, nil, soleOwner{}, nil).Fetch(ownerID, listID, taskID)
+		assert.NoError(t, err)
+		assert.Equal(t, assignees, res)
+	})
+
+	t.Run("list not shared with the user", func(t *testing.T) {
+		var (
+			r       = mocks.NewAssignmentRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", mock.Anything, mock.Anything).Return(nil, failure.ErrListNotFound)
+		res, err := NewAssignmentService(r, nil, members, nil).Fetch(ownerID, listID, taskID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+		r.AssertNotCalled(t, "Fetch")
+	})
+}
+
+func TestAssignmentService_FetchAssigned(t *testing.T) {
+	defer beQuiet()()
+	var userID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r     = mocks.NewAssignmentRepositoryMock()
+			tasks = []*model.Task{{}, {}}
+		)
+		r.On("FetchAssigned", userID.String(), int64(1), int64(10), "report", "-due_date").Return(tasks, nil)
+		res, err := NewAssignmentService(r, nil, nil, nil).FetchAssigned(userID, &types.Pagination{}, blankset+"report"+blankset, "-due_date")
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.Task]{Page: 1, RPP: 10, Retrieved: 2, Payload: tasks}, res)
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var r = mocks.NewAssignmentRepositoryMock()
+		_, err := NewAssignmentService(r, nil, nil, nil).FetchAssigned(uuid.Nil, &types.Pagination{}, "", "")
+		assert.ErrorContains(t, err, failure.NewNilParameterError("FetchAssigned", "userID").Error())
+		_, err = NewAssignmentService(r, nil, nil, nil).FetchAssigned(userID, nil, "", "")
+		assert.ErrorContains(t, err, failure.NewNilParameterError("FetchAssigned", "pagination").Error())
+		r.AssertNotCalled(t, "FetchAssigned")
+	})
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"log"
+	"net/http"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/repository"
+	"noda/storage"
+	"path"
+	"strings"
+	"time"
+)
+
+// MaxAttachmentSize is the largest file, in bytes, that can be attached to a
+// task.
+const MaxAttachmentSize = 10 << 20
+
+// blobTimeout bounds how long an upload to the blob store may take.
+const blobTimeout = 5 * time.Minute
+
+type AttachmentService interface {
+	Save(ownerID, taskID uuid.UUID, fileName string, content io.Reader) (insertedID uuid.UUID, err error)
+	FetchByID(ownerID, taskID, attachmentID uuid.UUID) (attachment *model.Attachment, err error)
+	Fetch(ownerID, taskID uuid.UUID) (attachments []*model.Attachment, err error)
+	Open(ownerID, taskID, attachmentID uuid.UUID) (attachment *model.Attachment, content io.ReadCloser, err error)
+	Delete(ownerID, taskID, attachmentID uuid.UUID) error
+}
+
+type attachmentService struct {
+	r     repository.AttachmentRepository
+	store storage.BlobStore
+}
+
+func NewAttachmentService(repository repository.AttachmentRepository, store storage.BlobStore) AttachmentService {
+	return &attachmentService{r: repository, store: store}
+}
+
+// errFileTooLarge is returned by a limitedReader once the limit is exceeded.
+var errFileTooLarge = errors.New("file too large")
+
+// limitedReader counts what is read from r and fails as soon as it goes over
+// remaining bytes.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+	read      int64
+}
+
+func (l *limitedReader) Read(p []byte) (n int, err error) {
+	n, err = l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.remaining {
+		return n, errFileTooLarge
+	}
+	return n, err
+}
+
+func (s *attachmentService) Save(ownerID, taskID uuid.UUID, fileName string, content io.Reader) (insertedID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Save", "ownerID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Save", "taskID")
+		log.Println(err)
+		return uuid.Nil, err
+	case nil == content:
+		err = failure.NewNilParameterError("Save", "content")
+		log.Println(err)
+		return uuid.Nil, err
+	}
+	/* Never trust a path coming from the client.  */
+	fileName = path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	doTrim(&fileName)
+	switch {
+	case "" == fileName || "." == fileName || "/" == fileName:
+		return uuid.Nil, failure.ErrMissingFile
+	case 1<<8-1 < len(fileName):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("file_name", "attachment", 1<<8-1)
+	}
+	/* The content type announced by the client is ignored in favour of the
+	   one sniffed from the first bytes of the file.  */
+	var head = make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if nil != err && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return uuid.Nil, err
+	}
+	head = head[:n]
+	var (
+		contentType = http.DetectContentType(head)
+		key         = fmt.Sprintf("%s/%s/%s", ownerID, taskID, uuid.New())
+		limited     = &limitedReader{r: io.MultiReader(bytes.NewReader(head), content), remaining: MaxAttachmentSize}
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), blobTimeout)
+	defer cancel()
+	err = s.store.Put(ctx, key, limited, -1, contentType)
+	if nil != err {
+		if errors.Is(err, errFileTooLarge) {
+			return uuid.Nil, failure.ErrFileTooLarge.Clone().FormatDetails(MaxAttachmentSize)
+		}
+		return uuid.Nil, err
+	}
+	var creation = &transfer.AttachmentCreation{
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        limited.read,
+		BlobKey:     key,
+	}
+	inserted, err := s.r.Save(ownerID.String(), taskID.String(), creation)
+	if nil != err {
+		if e := s.store.Delete(ctx, key); nil != e {
+			log.Printf("could not remove orphan blob %q: %v", key, e)
+		}
+		return uuid.Nil, err
+	}
+	return uuid.Parse(inserted)
+}
+
+func (s *attachmentService) FetchByID(ownerID, taskID, attachmentID uuid.UUID) (attachment *model.Attachment, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("FetchByID", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("FetchByID", "taskID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == attachmentID:
+		err = failure.NewNilParameterError("FetchByID", "attachmentID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.r.FetchByID(ownerID.String(), taskID.String(), attachmentID.String())
+}
+
+func (s *attachmentService) Fetch(ownerID, taskID uuid.UUID) (attachments []*model.Attachment, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Fetch", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Fetch", "taskID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.r.Fetch(ownerID.String(), taskID.String())
+}
+
+func (s *attachmentService) Open(
+	ownerID, taskID, attachmentID uuid.UUID,
+) (attachment *model.Attachment, content io.ReadCloser, err error) {
+	attachment, err = s.FetchByID(ownerID, taskID, attachmentID)
+	if nil != err {
+		return nil, nil, err
+	}
+	content, err = s.store.Get(context.Background(), attachment.BlobKey)
+	if nil != err {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			log.Printf("attachment %q has no blob under %q", attachment.UUID, attachment.BlobKey)
+			return nil, nil, failure.ErrAttachmentNotFound
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+func (s *attachmentService) Delete(ownerID, taskID, attachmentID uuid.UUID) error {
+	attachment, err := s.FetchByID(ownerID, taskID, attachmentID)
+	if nil != err {
+		return err
+	}
+	err = s.r.Delete(ownerID.String(), taskID.String(), attachmentID.String())
+	if nil != err {
+		return err
+	}
+	var ctx, cancel = context.WithTimeout(context.Background(), blobTimeout)
+	defer cancel()
+	if e := s.store.Delete(ctx, attachment.BlobKey); nil != e {
+		log.Printf("could not remove blob %q: %v", attachment.BlobKey, e)
+	}
+	return nil
+}
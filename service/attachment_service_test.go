@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/mocks"
+	"noda/storage"
+	"strings"
+	"testing"
+)
+
+func newBlobStore(t *testing.T) storage.BlobStore {
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+	return store
+}
+
+func TestAttachmentService_Save(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Save"
+	var (
+		ownerID, taskID, inserted = uuid.New(), uuid.New(), uuid.New()
+		res                       uuid.UUID
+		err                       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			store    = newBlobStore(t)
+			r        = mocks.NewAttachmentRepositoryMock()
+			creation *transfer.AttachmentCreation
+		)
+		r.On(routine, ownerID.String(), taskID.String(), mock.Anything).
+			Run(func(args mock.Arguments) { creation = args.Get(2).(*transfer.AttachmentCreation) }).
+			Return(inserted.String(), nil)
+		res, err = NewAttachmentService(r, store).Save(ownerID, taskID, "notes.txt", strings.NewReader("some notes"))
+		require.NoError(t, err)
+		assert.Equal(t, inserted, res)
+		assert.Equal(t, "notes.txt", creation.FileName)
+		assert.Equal(t, "text/plain; charset=utf-8", creation.ContentType)
+		assert.Equal(t, int64(10), creation.Size)
+		assert.True(t, strings.HasPrefix(creation.BlobKey, ownerID.String()+"/"+taskID.String()+"/"))
+		content, err := store.Get(context.Background(), creation.BlobKey)
+		require.NoError(t, err)
+		defer content.Close()
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, "some notes", string(data))
+	})
+
+	t.Run("sniffs the content type", func(t *testing.T) {
+		var (
+			r        = mocks.NewAttachmentRepositoryMock()
+			png      = append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), make([]byte, 1024)...)
+			creation *transfer.AttachmentCreation
+		)
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { creation = args.Get(2).(*transfer.AttachmentCreation) }).
+			Return(inserted.String(), nil)
+		_, err = NewAttachmentService(r, newBlobStore(t)).Save(ownerID, taskID, "image.txt", bytes.NewReader(png))
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", creation.ContentType)
+		assert.Equal(t, int64(len(png)), creation.Size)
+	})
+
+	t.Run("strips directories from the file name", func(t *testing.T) {
+		var (
+			r        = mocks.NewAttachmentRepositoryMock()
+			creation *transfer.AttachmentCreation
+		)
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { creation = args.Get(2).(*transfer.AttachmentCreation) }).
+			Return(inserted.String(), nil)
+		_, err = NewAttachmentService(r, newBlobStore(t)).Save(ownerID, taskID, "..\\..\\etc/passwd", strings.NewReader("x"))
+		require.NoError(t, err)
+		assert.Equal(t, "passwd", creation.FileName)
+	})
+
+	t.Run("parameters are not nil or uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewAttachmentRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewAttachmentService(r, newBlobStore(t)).Save(uuid.Nil, taskID, "a", strings.NewReader("x"))
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "ownerID").Error())
+		res, err = NewAttachmentService(r, newBlobStore(t)).Save(ownerID, uuid.Nil, "a", strings.NewReader("x"))
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "taskID").Error())
+		res, err = NewAttachmentService(r, newBlobStore(t)).Save(ownerID, taskID, "a", nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "content").Error())
+	})
+
+	t.Run("empty file name", func(t *testing.T) {
+		var r = mocks.NewAttachmentRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewAttachmentService(r, newBlobStore(t)).Save(ownerID, taskID, blankset, strings.NewReader("x"))
+		assert.ErrorIs(t, err, failure.ErrMissingFile)
+		assert.Equal(t, uuid.Nil, res)
+	})
+
+	t.Run("file is too large", func(t *testing.T) {
+		var r = mocks.NewAttachmentRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		var content = io.LimitReader(zeroes{}, MaxAttachmentSize+1)
+		res, err = NewAttachmentService(r, newBlobStore(t)).Save(ownerID, taskID, "big.bin", content)
+		assert.ErrorContains(t, err, failure.ErrFileTooLarge.Clone().FormatDetails(MaxAttachmentSize).Error())
+		assert.Equal(t, uuid.Nil, res)
+	})
+
+	t.Run("removes the blob if the repository fails", func(t *testing.T) {
+		var (
+			store      = newBlobStore(t)
+			r          = mocks.NewAttachmentRepositoryMock()
+			creation   *transfer.AttachmentCreation
+			unexpected = failure.ErrTaskNotFound
+		)
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { creation = args.Get(2).(*transfer.AttachmentCreation) }).
+			Return("", unexpected)
+		res, err = NewAttachmentService(r, store).Save(ownerID, taskID, "a.txt", strings.NewReader("x"))
+		assert.ErrorIs(t, err, unexpected)
+		assert.Equal(t, uuid.Nil, res)
+		_, err = store.Get(context.Background(), creation.BlobKey)
+		assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+	})
+}
+
+// zeroes is an endless source of zero bytes.
+type zeroes struct{}
+
+func (zeroes) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestAttachmentService_Open(t *testing.T) {
+	defer beQuiet()()
+	const routine = "FetchByID"
+	var ownerID, taskID, attachmentID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			store      = newBlobStore(t)
+			attachment = &model.Attachment{UUID: attachmentID, BlobKey: "a/b/c"}
+			r          = mocks.NewAttachmentRepositoryMock()
+		)
+		require.NoError(t, store.Put(context.Background(), "a/b/c", strings.NewReader("content"), 7, ""))
+		r.On(routine, ownerID.String(), taskID.String(), attachmentID.String()).Return(attachment, nil)
+		res, content, err := NewAttachmentService(r, store).Open(ownerID, taskID, attachmentID)
+		require.NoError(t, err)
+		defer content.Close()
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, attachment, res)
+		assert.Equal(t, "content", string(data))
+	})
+
+	t.Run("the blob is missing", func(t *testing.T) {
+		var r = mocks.NewAttachmentRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).
+			Return(&model.Attachment{BlobKey: "a/b/missing"}, nil)
+		res, content, err := NewAttachmentService(r, newBlobStore(t)).Open(ownerID, taskID, attachmentID)
+		assert.ErrorIs(t, err, failure.ErrAttachmentNotFound)
+		assert.Nil(t, res)
+		assert.Nil(t, content)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewAttachmentRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(nil, failure.ErrAttachmentNotFound)
+		res, content, err := NewAttachmentService(r, newBlobStore(t)).Open(ownerID, taskID, attachmentID)
+		assert.ErrorIs(t, err, failure.ErrAttachmentNotFound)
+		assert.Nil(t, res)
+		assert.Nil(t, content)
+	})
+}
+
+func TestAttachmentService_Delete(t *testing.T) {
+	defer beQuiet()()
+	var ownerID, taskID, attachmentID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			store = newBlobStore(t)
+			r     = mocks.NewAttachmentRepositoryMock()
+		)
+		require.NoError(t, store.Put(context.Background(), "a/b/c", strings.NewReader("x"), 1, ""))
+		r.On("FetchByID", ownerID.String(), taskID.String(), attachmentID.String()).
+			Return(&model.Attachment{BlobKey: "a/b/c"}, nil)
+		r.On("Delete", ownerID.String(), taskID.String(), attachmentID.String()).Return(nil)
+		assert.NoError(t, NewAttachmentService(r, store).Delete(ownerID, taskID, attachmentID))
+		_, err := store.Get(context.Background(), "a/b/c")
+		assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+	})
+
+	t.Run("keeps the blob if the repository fails", func(t *testing.T) {
+		var (
+			store      = newBlobStore(t)
+			r          = mocks.NewAttachmentRepositoryMock()
+			unexpected = errors.New("unexpected error")
+		)
+		require.NoError(t, store.Put(context.Background(), "a/b/c", strings.NewReader("x"), 1, ""))
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything).Return(&model.Attachment{BlobKey: "a/b/c"}, nil)
+		r.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
+		assert.ErrorIs(t, NewAttachmentService(r, store).Delete(ownerID, taskID, attachmentID), unexpected)
+		content, err := store.Get(context.Background(), "a/b/c")
+		assert.NoError(t, err)
+		content.Close()
+	})
+}
@@ -0,0 +1,600 @@
+package service
+
+import (
+	"log"
+	"noda/audit"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditService lets the administrators look into the audit log, and keeps it
+// within its retention period.
+type AuditService interface {
+	Fetch(pagination *types.Pagination, filter *types.AuditFilter) (result *types.Result[model.AuditEntry], err error)
+	Purge(now time.Time) (purged int64, err error)
+}
+
+type auditService struct {
+	r         repository.AuditRepository
+	trail     *audit.Trail
+	retention time.Duration
+}
+
+// NewAuditService returns an AuditService that keeps the entries for the given
+// retention period. Zero keeps them forever. Every purge is itself recorded.
+func NewAuditService(r repository.AuditRepository, retention time.Duration) AuditService {
+	return &auditService{r, audit.NewTrail(r), retention}
+}
+
+func (s *auditService) Fetch(pagination *types.Pagination, filter *types.AuditFilter) (result *types.Result[model.AuditEntry], err error) {
+	switch {
+	case nil == pagination:
+		err = failure.NewNilParameterError("Fetch", "pagination")
+		log.Println(err)
+		return nil, err
+	case nil == filter:
+		err = failure.NewNilParameterError("Fetch", "filter")
+		log.Println(err)
+		return nil, err
+	}
+	doTrim(&filter.Entity)
+	doDefaultPagination(pagination)
+	entries, err := s.r.Fetch(filter, pagination.Page, pagination.RPP)
+	if nil != err {
+		return nil, err
+	}
+	result = &types.Result[model.AuditEntry]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(entries)),
+		Payload:   entries,
+	}
+	return result, nil
+}
+
+// Purge removes the entries that are past the retention period at now.
+func (s *auditService) Purge(now time.Time) (purged int64, err error) {
+	if 0 == s.retention {
+		return 0, nil
+	}
+	var before = now.Add(-s.retention)
+	purged, err = s.r.Purge(before)
+	if nil == err && 0 < purged {
+		s.trail.Record(audit.Origin{}, "audit", uuid.Nil, "purged", nil, map[string]any{"before": before, "purged": purged})
+	}
+	return purged, err
+}
+
+// Within returns the service recording the changes made through it as coming
+// from the origin, if its changes are audited, and the service itself if not.
+func Within[S any](s S, origin audit.Origin) S {
+	if audited, ok := any(s).(interface{ Within(audit.Origin) S }); ok {
+		return audited.Within(origin)
+	}
+	return s
+}
+
+// auditedUserService records in the audit trail every change made to the users
+// through the UserService it wraps, with the states of the user before and
+// after it.
+type auditedUserService struct {
+	UserService
+	trail  *audit.Trail
+	origin audit.Origin
+}
+
+// NewAuditedUserService wraps a UserService so that its changes to the users
+// are audited. They come from the server itself unless the service is used
+// Within the origin of a request.
+func NewAuditedUserService(next UserService, trail *audit.Trail) UserService {
+	return &auditedUserService{UserService: next, trail: trail}
+}
+
+func (u *auditedUserService) Within(origin audit.Origin) UserService {
+	var bound = *u
+	bound.origin = origin
+	return &bound
+}
+
+// state retrieves the user as it is, or nil if it could not.
+func (u *auditedUserService) state(id uuid.UUID) *transfer.User {
+	user, err := u.UserService.FetchByID(id)
+	if nil != err {
+		return nil
+	}
+	return user
+}
+
+// change records the change of the user, if it happens.
+func (u *auditedUserService) change(id uuid.UUID, action string, change func() (bool, error)) (ok bool, err error) {
+	var before = u.state(id)
+	ok, err = change()
+	if nil == err && ok {
+		u.trail.Record(u.origin, "user", id, action, before, u.state(id))
+	}
+	return ok, err
+}
+
+func (u *auditedUserService) Save(creation *transfer.UserCreation) (insertedID uuid.UUID, err error) {
+	insertedID, err = u.UserService.Save(creation)
+	if nil == err {
+		u.trail.Record(u.origin.As(insertedID), "user", insertedID, "created", nil, u.state(insertedID))
+	}
+	return insertedID, err
+}
+
+func (u *auditedUserService) Update(id uuid.UUID, update *transfer.UserUpdate) (ok bool, err error) {
+	return u.change(id, "updated", func() (bool, error) {
+		return u.UserService.Update(id, update)
+	})
+}
+
+func (u *auditedUserService) ChangePassword(id uuid.UUID, change *transfer.PasswordChange) (ok bool, err error) {
+	return u.change(id, "password_changed", func() (bool, error) {
+		return u.UserService.ChangePassword(id, change)
+	})
+}
+
+func (u *auditedUserService) SetPassword(id uuid.UUID, password string) (ok bool, err error) {
+	return u.change(id, "password_set", func() (bool, error) {
+		return u.UserService.SetPassword(id, password)
+	})
+}
+
+func (u *auditedUserService) UpdateUserSetting(userID uuid.UUID, settingKey string, update *transfer.UserSettingUpdate) (ok bool, err error) {
+	var state = func() *transfer.UserSetting {
+		setting, err := u.UserService.FetchOneSetting(userID, settingKey)
+		if nil != err {
+			return nil
+		}
+		return setting
+	}
+	var before = state()
+	ok, err = u.UserService.UpdateUserSetting(userID, settingKey, update)
+	if nil == err && ok {
+		u.trail.Record(u.origin, "user", userID, "setting_updated", before, state())
+	}
+	return ok, err
+}
+
+// Block records the block itself, since whether a user is blocked is not part
+// of its state.
+func (u *auditedUserService) Block(id uuid.UUID, block *transfer.UserBlock) (ok bool, err error) {
+	ok, err = u.UserService.Block(id, block)
+	if nil == err && ok {
+		u.trail.Record(u.origin, "user", id, "blocked", nil, block)
+	}
+	return ok, err
+}
+
+func (u *auditedUserService) Unblock(id uuid.UUID) (ok bool, err error) {
+	return u.change(id, "unblocked", func() (bool, error) {
+		return u.UserService.Unblock(id)
+	})
+}
+
+func (u *auditedUserService) PromoteToAdmin(id uuid.UUID) (ok bool, err error) {
+	return u.change(id, "promoted_to_admin", func() (bool, error) {
+		return u.UserService.PromoteToAdmin(id)
+	})
+}
+
+func (u *auditedUserService) DegradeToUser(id uuid.UUID) (ok bool, err error) {
+	return u.change(id, "degraded_to_user", func() (bool, error) {
+		return u.UserService.DegradeToUser(id)
+	})
+}
+
+func (u *auditedUserService) RemoveHardly(id uuid.UUID) error {
+	_, err := u.change(id, "removed", func() (bool, error) {
+		return true, u.UserService.RemoveHardly(id)
+	})
+	return err
+}
+
+func (u *auditedUserService) RemoveSoftly(id uuid.UUID) error {
+	_, err := u.change(id, "deleted", func() (bool, error) {
+		return true, u.UserService.RemoveSoftly(id)
+	})
+	return err
+}
+
+func (u *auditedUserService) Restore(id uuid.UUID) (ok bool, err error) {
+	return u.change(id, "restored", func() (bool, error) {
+		return u.UserService.Restore(id)
+	})
+}
+
+func (u *auditedUserService) PurgeDeleted() (purged int64, err error) {
+	purged, err = u.UserService.PurgeDeleted()
+	if 0 < purged {
+		u.trail.Record(u.origin, "user", uuid.Nil, "purged", nil, map[string]any{"purged": purged})
+	}
+	return purged, err
+}
+
+// auditedTaskService records in the audit trail every change made to the tasks
+// through the TaskService it wraps, with the states of the task before and
+// after it. The actor is always the user acting on the task.
+type auditedTaskService struct {
+	TaskService
+	trail  *audit.Trail
+	origin audit.Origin
+}
+
+// NewAuditedTaskService wraps a TaskService so that its changes to the tasks
+// are audited.
+func NewAuditedTaskService(next TaskService, trail *audit.Trail) TaskService {
+	return &auditedTaskService{TaskService: next, trail: trail}
+}
+
+func (t *auditedTaskService) Within(origin audit.Origin) TaskService {
+	var bound = *t
+	bound.origin = origin
+	return &bound
+}
+
+// state retrieves the task as the user sees it, wherever it is, or nil if it
+// could not.
+func (t *auditedTaskService) state(userID, taskID uuid.UUID) *model.Task {
+	return stateOfTask(t.TaskService, userID, taskID)
+}
+
+func stateOfTask(tasks TaskService, userID, taskID uuid.UUID) *model.Task {
+	listID, err := tasks.Locate(userID, taskID)
+	if nil != err {
+		return nil
+	}
+	task, err := tasks.FetchByID(userID, listID, taskID)
+	if nil != err {
+		return nil
+	}
+	return task
+}
+
+// change records the change of the task, if it happens.
+func (t *auditedTaskService) change(userID, taskID uuid.UUID, action types.RevisionAction, change func() (bool, error)) (ok bool, err error) {
+	if uuid.Nil == userID || uuid.Nil == taskID {
+		return change()
+	}
+	var before = t.state(userID, taskID)
+	ok, err = change()
+	if nil == err && ok {
+		t.trail.Record(t.origin.As(userID), "task", taskID, string(action), before, t.state(userID, taskID))
+	}
+	return ok, err
+}
+
+func (t *auditedTaskService) Save(ownerID, listID uuid.UUID, creation *transfer.TaskCreation) (insertedID uuid.UUID, err error) {
+	insertedID, err = t.TaskService.Save(ownerID, listID, creation)
+	if nil == err {
+		t.trail.Record(t.origin.As(ownerID), "task", insertedID, "created", nil, t.state(ownerID, insertedID))
+	}
+	return insertedID, err
+}
+
+func (t *auditedTaskService) Duplicate(ownerID, taskID uuid.UUID) (replicaID uuid.UUID, err error) {
+	replicaID, err = t.TaskService.Duplicate(ownerID, taskID)
+	if nil == err {
+		t.trail.Record(t.origin.As(ownerID), "task", replicaID, "created", nil, t.state(ownerID, replicaID))
+	}
+	return replicaID, err
+}
+
+func (t *auditedTaskService) Update(ownerID, listID, taskID uuid.UUID, update *transfer.TaskUpdate) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionUpdated, func() (bool, error) {
+		return t.TaskService.Update(ownerID, listID, taskID, update)
+	})
+}
+
+func (t *auditedTaskService) Reorder(ownerID, listID, taskID uuid.UUID, position uint64) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionReordered, func() (bool, error) {
+		return t.TaskService.Reorder(ownerID, listID, taskID, position)
+	})
+}
+
+func (t *auditedTaskService) SetReminder(ownerID, listID, taskID uuid.UUID, remindAt time.Time) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionReminderSet, func() (bool, error) {
+		return t.TaskService.SetReminder(ownerID, listID, taskID, remindAt)
+	})
+}
+
+func (t *auditedTaskService) SetPriority(ownerID, listID, taskID uuid.UUID, priority types.TaskPriority) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionPriorityChanged, func() (bool, error) {
+		return t.TaskService.SetPriority(ownerID, listID, taskID, priority)
+	})
+}
+
+func (t *auditedTaskService) SetDueDate(ownerID, listID, taskID uuid.UUID, dueDate time.Time) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionDueDateSet, func() (bool, error) {
+		return t.TaskService.SetDueDate(ownerID, listID, taskID, dueDate)
+	})
+}
+
+func (t *auditedTaskService) SetRecurrence(ownerID, listID, taskID uuid.UUID, rule string) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionRecurrenceSet, func() (bool, error) {
+		return t.TaskService.SetRecurrence(ownerID, listID, taskID, rule)
+	})
+}
+
+func (t *auditedTaskService) RemoveRecurrence(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionRecurrenceRemoved, func() (bool, error) {
+		return t.TaskService.RemoveRecurrence(ownerID, listID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Complete(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionCompleted, func() (bool, error) {
+		return t.TaskService.Complete(ownerID, listID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Resume(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionResumed, func() (bool, error) {
+		return t.TaskService.Resume(ownerID, listID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Pin(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionPinned, func() (bool, error) {
+		return t.TaskService.Pin(ownerID, listID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Unpin(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionUnpinned, func() (bool, error) {
+		return t.TaskService.Unpin(ownerID, listID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Move(ownerID, taskID, targetListID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionMoved, func() (bool, error) {
+		return t.TaskService.Move(ownerID, taskID, targetListID)
+	})
+}
+
+func (t *auditedTaskService) Today(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionMoved, func() (bool, error) {
+		return t.TaskService.Today(ownerID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Tomorrow(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionMoved, func() (bool, error) {
+		return t.TaskService.Tomorrow(ownerID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Defer(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionMoved, func() (bool, error) {
+		return t.TaskService.Defer(ownerID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Trash(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionTrashed, func() (bool, error) {
+		return t.TaskService.Trash(ownerID, listID, taskID)
+	})
+}
+
+func (t *auditedTaskService) RestoreFromTrash(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.change(ownerID, taskID, types.RevisionActionRestoredFromTrash, func() (bool, error) {
+		return t.TaskService.RestoreFromTrash(ownerID, listID, taskID)
+	})
+}
+
+func (t *auditedTaskService) Delete(ownerID, listID, taskID uuid.UUID) error {
+	_, err := t.change(ownerID, taskID, "removed", func() (bool, error) {
+		return true, t.TaskService.Delete(ownerID, listID, taskID)
+	})
+	return err
+}
+
+// auditedRevisionService records in the audit trail the restorations and the
+// undoing of the changes made to the tasks through the RevisionService it
+// wraps, which write the tasks without going through the TaskService. The
+// actor is always the user acting on the task.
+type auditedRevisionService struct {
+	RevisionService
+	tasks  TaskService
+	trail  *audit.Trail
+	origin audit.Origin
+}
+
+// NewAuditedRevisionService wraps a RevisionService so that its changes to the
+// tasks are audited. The tasks are read through the given TaskService.
+func NewAuditedRevisionService(next RevisionService, tasks TaskService, trail *audit.Trail) RevisionService {
+	return &auditedRevisionService{RevisionService: next, tasks: tasks, trail: trail}
+}
+
+func (s *auditedRevisionService) Within(origin audit.Origin) RevisionService {
+	var bound = *s
+	bound.origin = origin
+	return &bound
+}
+
+func (s *auditedRevisionService) Restore(userID, listID, taskID, revisionID uuid.UUID) (ok bool, err error) {
+	var before = stateOfTask(s.tasks, userID, taskID)
+	ok, err = s.RevisionService.Restore(userID, listID, taskID, revisionID)
+	if nil == err && ok {
+		s.trail.Record(s.origin.As(userID), "task", taskID, string(types.RevisionActionRestored), before, stateOfTask(s.tasks, userID, taskID))
+	}
+	return ok, err
+}
+
+// Undo records the task as the undone change left it before, for which task
+// is undone is only known once it is.
+func (s *auditedRevisionService) Undo(userID uuid.UUID) (revision *model.Revision, err error) {
+	revision, err = s.RevisionService.Undo(userID)
+	if nil == err {
+		var before any
+		if 0 < len(revision.After) {
+			before = revision.After
+		}
+		s.trail.Record(s.origin.As(userID), "task", revision.TaskUUID, string(types.RevisionActionUndone), before, stateOfTask(s.tasks, userID, revision.TaskUUID))
+	}
+	return revision, err
+}
+
+// auditedListService records in the audit trail every change made to the lists
+// through the ListService it wraps, with the states of the list before and
+// after it.
+type auditedListService struct {
+	ListService
+	trail   *audit.Trail
+	members repository.MemberRepository
+	origin  audit.Origin
+}
+
+// NewAuditedListService wraps a ListService so that its changes to the lists
+// are audited.
+func NewAuditedListService(next ListService, trail *audit.Trail, members repository.MemberRepository) ListService {
+	return &auditedListService{ListService: next, trail: trail, members: members}
+}
+
+func (s *auditedListService) Within(origin audit.Origin) ListService {
+	var bound = *s
+	bound.origin = origin
+	return &bound
+}
+
+// state retrieves the list as the user sees it, in whichever group it is, or
+// nil if it could not.
+func (s *auditedListService) state(userID, listID uuid.UUID) *model.List {
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil
+	}
+	var groupID = uuid.Nil
+	if nil != access.GroupUUID {
+		groupID = *access.GroupUUID
+	}
+	list, err := s.ListService.FetchByID(userID, groupID, listID)
+	if nil != err {
+		return nil
+	}
+	return list
+}
+
+// change records the change of the list, if it happens.
+func (s *auditedListService) change(userID, listID uuid.UUID, action string, change func() (bool, error)) (ok bool, err error) {
+	if uuid.Nil == userID || uuid.Nil == listID {
+		return change()
+	}
+	var before = s.state(userID, listID)
+	ok, err = change()
+	if nil == err && ok {
+		s.trail.Record(s.origin.As(userID), "list", listID, action, before, s.state(userID, listID))
+	}
+	return ok, err
+}
+
+func (s *auditedListService) Save(ownerID, groupID uuid.UUID, creation *transfer.ListCreation) (insertedID uuid.UUID, err error) {
+	insertedID, err = s.ListService.Save(ownerID, groupID, creation)
+	if nil == err {
+		s.trail.Record(s.origin.As(ownerID), "list", insertedID, "created", nil, s.state(ownerID, insertedID))
+	}
+	return insertedID, err
+}
+
+func (s *auditedListService) Update(ownerID, groupID, listID uuid.UUID, update *transfer.ListUpdate) (ok bool, err error) {
+	return s.change(ownerID, listID, "updated", func() (bool, error) {
+		return s.ListService.Update(ownerID, groupID, listID, update)
+	})
+}
+
+func (s *auditedListService) Duplicate(ownerID, listID uuid.UUID) (replicaID uuid.UUID, err error) {
+	replicaID, err = s.ListService.Duplicate(ownerID, listID)
+	if nil == err {
+		s.trail.Record(s.origin.As(ownerID), "list", replicaID, "created", nil, s.state(ownerID, replicaID))
+	}
+	return replicaID, err
+}
+
+func (s *auditedListService) Move(ownerID, listID, targetGroupID uuid.UUID) (ok bool, err error) {
+	return s.change(ownerID, listID, "moved", func() (bool, error) {
+		return s.ListService.Move(ownerID, listID, targetGroupID)
+	})
+}
+
+func (s *auditedListService) Scatter(ownerID, listID uuid.UUID) (ok bool, err error) {
+	return s.change(ownerID, listID, "moved", func() (bool, error) {
+		return s.ListService.Scatter(ownerID, listID)
+	})
+}
+
+func (s *auditedListService) Remove(ownerID, groupID, listID uuid.UUID) error {
+	_, err := s.change(ownerID, listID, "removed", func() (bool, error) {
+		return true, s.ListService.Remove(ownerID, groupID, listID)
+	})
+	return err
+}
+
+// auditedGroupService records in the audit trail every change made to the
+// groups through the GroupService it wraps, with the states of the group
+// before and after it.
+type auditedGroupService struct {
+	GroupService
+	trail  *audit.Trail
+	origin audit.Origin
+}
+
+// NewAuditedGroupService wraps a GroupService so that its changes to the
+// groups are audited.
+func NewAuditedGroupService(next GroupService, trail *audit.Trail) GroupService {
+	return &auditedGroupService{GroupService: next, trail: trail}
+}
+
+func (s *auditedGroupService) Within(origin audit.Origin) GroupService {
+	var bound = *s
+	bound.origin = origin
+	return &bound
+}
+
+// state retrieves the group as the user sees it, or nil if it could not.
+func (s *auditedGroupService) state(userID, groupID uuid.UUID) *model.Group {
+	group, err := s.GroupService.FetchByID(userID, groupID)
+	if nil != err {
+		return nil
+	}
+	return group
+}
+
+// change records the change of the group, if it happens.
+func (s *auditedGroupService) change(userID, groupID uuid.UUID, action string, change func() (bool, error)) (ok bool, err error) {
+	if uuid.Nil == userID || uuid.Nil == groupID {
+		return change()
+	}
+	var before = s.state(userID, groupID)
+	ok, err = change()
+	if nil == err && ok {
+		s.trail.Record(s.origin.As(userID), "group", groupID, action, before, s.state(userID, groupID))
+	}
+	return ok, err
+}
+
+func (s *auditedGroupService) Save(ownerID uuid.UUID, creation *transfer.GroupCreation) (insertedID uuid.UUID, err error) {
+	insertedID, err = s.GroupService.Save(ownerID, creation)
+	if nil == err {
+		s.trail.Record(s.origin.As(ownerID), "group", insertedID, "created", nil, s.state(ownerID, insertedID))
+	}
+	return insertedID, err
+}
+
+func (s *auditedGroupService) Update(ownerID, groupID uuid.UUID, update *transfer.GroupUpdate) (ok bool, err error) {
+	return s.change(ownerID, groupID, "updated", func() (bool, error) {
+		return s.GroupService.Update(ownerID, groupID, update)
+	})
+}
+
+func (s *auditedGroupService) Remove(ownerID, groupID uuid.UUID) (ok bool, err error) {
+	return s.change(ownerID, groupID, "removed", func() (bool, error) {
+		return s.GroupService.Remove(ownerID, groupID)
+	})
+}
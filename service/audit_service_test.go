@@ -0,0 +1,336 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"noda/audit"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+	"time"
+)
+
+func TestAuditService_Fetch(t *testing.T) {
+	defer beQuiet()()
+	var (
+		s   AuditService
+		res *types.Result[model.AuditEntry]
+		err error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pag     = &types.Pagination{}
+			filter  = &types.AuditFilter{ActorUUID: uuid.New(), Entity: blankset + "user" + blankset}
+			entries = []*model.AuditEntry{{}, {}}
+			m       = mocks.NewAuditRepositoryMock()
+		)
+		m.On("Fetch", filter, int64(1), int64(10)).Return(entries, nil)
+		s = NewAuditService(m, time.Hour)
+		res, err = s.Fetch(pag, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, "user", filter.Entity)
+		assert.Equal(t, &types.Result[model.AuditEntry]{
+			Page:      1,
+			RPP:       10,
+			Retrieved: 2,
+			Payload:   entries,
+		}, res)
+	})
+
+	t.Run("nil pagination", func(t *testing.T) {
+		s = NewAuditService(mocks.NewAuditRepositoryMock(), time.Hour)
+		res, err = s.Fetch(nil, &types.AuditFilter{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "pagination").Error())
+		assert.Nil(t, res)
+	})
+
+	t.Run("nil filter", func(t *testing.T) {
+		s = NewAuditService(mocks.NewAuditRepositoryMock(), time.Hour)
+		res, err = s.Fetch(&types.Pagination{}, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "filter").Error())
+		assert.Nil(t, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var (
+			unexpected = errors.New("unexpected error")
+			filter     = &types.AuditFilter{}
+			m          = mocks.NewAuditRepositoryMock()
+		)
+		m.On("Fetch", filter, int64(1), int64(10)).Return(nil, unexpected)
+		s = NewAuditService(m, time.Hour)
+		res, err = s.Fetch(&types.Pagination{}, filter)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Nil(t, res)
+	})
+}
+
+func TestAuditService_Purge(t *testing.T) {
+	defer beQuiet()()
+	var now = time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		var m = mocks.NewAuditRepositoryMock()
+		m.On("Purge", now.Add(-24*time.Hour)).Return(int64(4), nil)
+		m.On("Save", mock.MatchedBy(func(entry *model.AuditEntry) bool {
+			return "audit" == entry.Entity && "purged" == entry.Action && nil == entry.ActorUUID
+		})).Return(uuid.NewString(), nil)
+		purged, err := NewAuditService(m, 24*time.Hour).Purge(now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), purged)
+		m.AssertNumberOfCalls(t, "Save", 1)
+	})
+
+	t.Run("entries are kept forever", func(t *testing.T) {
+		var m = mocks.NewAuditRepositoryMock()
+		purged, err := NewAuditService(m, 0).Purge(now)
+		assert.NoError(t, err)
+		assert.Zero(t, purged)
+		m.AssertNotCalled(t, "Purge", now)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var (
+			unexpected = errors.New("unexpected error")
+			m          = mocks.NewAuditRepositoryMock()
+		)
+		m.On("Purge", now.Add(-time.Hour)).Return(int64(0), unexpected)
+		purged, err := NewAuditService(m, time.Hour).Purge(now)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Zero(t, purged)
+	})
+}
+
+// recordingLog keeps the entries it is given.
+type recordingLog struct {
+	entries []*model.AuditEntry
+}
+
+func (l *recordingLog) Save(entry *model.AuditEntry) (string, error) {
+	l.entries = append(l.entries, entry)
+	return uuid.NewString(), nil
+}
+
+func TestWithin(t *testing.T) {
+	var next = mocks.NewUserServiceMock()
+	assert.Same(t, next, Within[UserService](next, audit.Origin{}))
+	var audited = NewAuditedUserService(next, nil)
+	assert.NotSame(t, audited, Within(audited, audit.Origin{IP: "10.0.0.7"}))
+}
+
+func TestAuditedUserService(t *testing.T) {
+	defer beQuiet()()
+	var (
+		adminID, userID = uuid.New(), uuid.New()
+		origin          = audit.Origin{ActorUUID: &adminID, IP: "10.0.0.7", UserAgent: "curl/8.0"}
+		block           = &transfer.UserBlock{Reason: "spam"}
+	)
+
+	t.Run("records the change with its origin", func(t *testing.T) {
+		var (
+			next = mocks.NewUserServiceMock()
+			l    = new(recordingLog)
+		)
+		next.On("FetchByID", userID).Return(&transfer.User{UUID: userID, Role: types.RoleUser}, nil).Once()
+		next.On("PromoteToAdmin", userID).Return(true, nil)
+		next.On("FetchByID", userID).Return(&transfer.User{UUID: userID, Role: types.RoleAdmin}, nil).Once()
+		ok, err := Within(NewAuditedUserService(next, audit.NewTrail(l)), origin).PromoteToAdmin(userID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		require.Len(t, l.entries, 1)
+		var entry = l.entries[0]
+		assert.Equal(t, &adminID, entry.ActorUUID)
+		assert.Equal(t, "user", entry.Entity)
+		assert.Equal(t, &userID, entry.EntityUUID)
+		assert.Equal(t, "promoted_to_admin", entry.Action)
+		assert.JSONEq(t, fmt.Sprintf(`{"role_id":%d}`, types.RoleUser), string(entry.Before))
+		assert.JSONEq(t, fmt.Sprintf(`{"role_id":%d}`, types.RoleAdmin), string(entry.After))
+		assert.Equal(t, "10.0.0.7", entry.IP)
+		assert.Equal(t, "curl/8.0", entry.UserAgent)
+	})
+
+	t.Run("records the block", func(t *testing.T) {
+		var (
+			next = mocks.NewUserServiceMock()
+			l    = new(recordingLog)
+		)
+		next.On("Block", userID, block).Return(true, nil)
+		_, err := Within(NewAuditedUserService(next, audit.NewTrail(l)), origin).Block(userID, block)
+		assert.NoError(t, err)
+		require.Len(t, l.entries, 1)
+		assert.Equal(t, "blocked", l.entries[0].Action)
+		assert.JSONEq(t, `{"reason":"spam","until":null}`, string(l.entries[0].After))
+	})
+
+	t.Run("the changes of the server have no actor", func(t *testing.T) {
+		var (
+			next = mocks.NewUserServiceMock()
+			l    = new(recordingLog)
+		)
+		next.On("PurgeDeleted").Return(int64(3), nil)
+		purged, err := NewAuditedUserService(next, audit.NewTrail(l)).PurgeDeleted()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+		require.Len(t, l.entries, 1)
+		assert.Nil(t, l.entries[0].ActorUUID)
+		assert.Equal(t, "purged", l.entries[0].Action)
+		assert.JSONEq(t, `{"purged":3}`, string(l.entries[0].After))
+	})
+
+	t.Run("failed changes are not recorded", func(t *testing.T) {
+		var (
+			next = mocks.NewUserServiceMock()
+			l    = new(recordingLog)
+		)
+		next.On("FetchByID", userID).Return(nil, failure.ErrUserNotFound)
+		next.On("Block", userID, block).Return(false, failure.ErrUserNotFound)
+		_, err := Within(NewAuditedUserService(next, audit.NewTrail(l)), origin).Block(userID, block)
+		assert.ErrorIs(t, err, failure.ErrUserNotFound)
+		assert.Empty(t, l.entries)
+	})
+}
+
+func TestAuditedTaskService(t *testing.T) {
+	defer beQuiet()()
+	var userID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("the actor is the user acting on the task", func(t *testing.T) {
+		var (
+			next   = mocks.NewTaskServiceMock()
+			l      = new(recordingLog)
+			update = &transfer.TaskUpdate{Title: "New"}
+		)
+		next.On("Locate", userID, taskID).Return(listID, nil)
+		next.On("FetchByID", userID, listID, taskID).Return(&model.Task{UUID: taskID, Title: "Old"}, nil).Once()
+		next.On("Update", userID, listID, taskID, update).Return(true, nil)
+		next.On("FetchByID", userID, listID, taskID).Return(&model.Task{UUID: taskID, Title: "New"}, nil).Once()
+		ok, err := Within(NewAuditedTaskService(next, audit.NewTrail(l)), audit.Origin{IP: "10.0.0.7"}).
+			Update(userID, listID, taskID, update)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		require.Len(t, l.entries, 1)
+		var entry = l.entries[0]
+		assert.Equal(t, &userID, entry.ActorUUID)
+		assert.Equal(t, "task", entry.Entity)
+		assert.Equal(t, &taskID, entry.EntityUUID)
+		assert.Equal(t, string(types.RevisionActionUpdated), entry.Action)
+		assert.JSONEq(t, `{"title":"Old"}`, string(entry.Before))
+		assert.JSONEq(t, `{"title":"New"}`, string(entry.After))
+		assert.Equal(t, "10.0.0.7", entry.IP)
+	})
+
+	t.Run("records the removed task", func(t *testing.T) {
+		var (
+			next = mocks.NewTaskServiceMock()
+			l    = new(recordingLog)
+		)
+		next.On("Locate", userID, taskID).Return(listID, nil).Once()
+		next.On("FetchByID", userID, listID, taskID).Return(&model.Task{UUID: taskID, Title: "Old"}, nil).Once()
+		next.On("Delete", userID, listID, taskID).Return(nil)
+		next.On("Locate", userID, taskID).Return(uuid.Nil, failure.ErrTaskNotFound).Once()
+		err := NewAuditedTaskService(next, audit.NewTrail(l)).Delete(userID, listID, taskID)
+		assert.NoError(t, err)
+		require.Len(t, l.entries, 1)
+		assert.Equal(t, "removed", l.entries[0].Action)
+		assert.NotNil(t, l.entries[0].Before)
+		assert.Nil(t, l.entries[0].After)
+	})
+
+	t.Run("changes that did not happen are not recorded", func(t *testing.T) {
+		var (
+			next = mocks.NewTaskServiceMock()
+			l    = new(recordingLog)
+		)
+		next.On("Locate", userID, taskID).Return(listID, nil)
+		next.On("FetchByID", userID, listID, taskID).Return(&model.Task{UUID: taskID}, nil)
+		next.On("Pin", userID, listID, taskID).Return(false, nil)
+		ok, err := NewAuditedTaskService(next, audit.NewTrail(l)).Pin(userID, listID, taskID)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, l.entries)
+	})
+}
+
+func TestAuditedRevisionService(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID, taskID, revisionID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		origin                             = audit.Origin{IP: "10.0.0.7", UserAgent: "curl/8.0"}
+	)
+
+	t.Run("records the restoration with its origin", func(t *testing.T) {
+		var (
+			next  = mocks.NewRevisionServiceMock()
+			tasks = mocks.NewTaskServiceMock()
+			l     = new(recordingLog)
+		)
+		tasks.On("Locate", userID, taskID).Return(listID, nil)
+		tasks.On("FetchByID", userID, listID, taskID).Return(&model.Task{UUID: taskID, Title: "New"}, nil).Once()
+		next.On("Restore", userID, listID, taskID, revisionID).Return(true, nil)
+		tasks.On("FetchByID", userID, listID, taskID).Return(&model.Task{UUID: taskID, Title: "Old"}, nil).Once()
+		ok, err := Within(NewAuditedRevisionService(next, tasks, audit.NewTrail(l)), origin).
+			Restore(userID, listID, taskID, revisionID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		require.Len(t, l.entries, 1)
+		var entry = l.entries[0]
+		assert.Equal(t, &userID, entry.ActorUUID)
+		assert.Equal(t, &taskID, entry.EntityUUID)
+		assert.Equal(t, string(types.RevisionActionRestored), entry.Action)
+		assert.JSONEq(t, `{"title":"New"}`, string(entry.Before))
+		assert.JSONEq(t, `{"title":"Old"}`, string(entry.After))
+		assert.Equal(t, "10.0.0.7", entry.IP)
+		assert.Equal(t, "curl/8.0", entry.UserAgent)
+	})
+
+	t.Run("records the undoing with its origin", func(t *testing.T) {
+		var (
+			next     = mocks.NewRevisionServiceMock()
+			tasks    = mocks.NewTaskServiceMock()
+			l        = new(recordingLog)
+			revision = &model.Revision{UUID: revisionID, TaskUUID: taskID, ListUUID: listID}
+		)
+		revision.After, _ = json.Marshal(&model.Task{UUID: taskID, Title: "New"})
+		next.On("Undo", userID).Return(revision, nil)
+		tasks.On("Locate", userID, taskID).Return(listID, nil)
+		tasks.On("FetchByID", userID, listID, taskID).Return(&model.Task{UUID: taskID, Title: "Old"}, nil)
+		res, err := Within(NewAuditedRevisionService(next, tasks, audit.NewTrail(l)), origin).Undo(userID)
+		assert.NoError(t, err)
+		assert.Same(t, revision, res)
+		require.Len(t, l.entries, 1)
+		var entry = l.entries[0]
+		assert.Equal(t, &userID, entry.ActorUUID)
+		assert.Equal(t, &taskID, entry.EntityUUID)
+		assert.Equal(t, string(types.RevisionActionUndone), entry.Action)
+		assert.JSONEq(t, `{"title":"New"}`, string(entry.Before))
+		assert.JSONEq(t, `{"title":"Old"}`, string(entry.After))
+		assert.Equal(t, "10.0.0.7", entry.IP)
+	})
+
+	t.Run("changes that did not happen are not recorded", func(t *testing.T) {
+		var (
+			next  = mocks.NewRevisionServiceMock()
+			tasks = mocks.NewTaskServiceMock()
+			l     = new(recordingLog)
+		)
+		tasks.On("Locate", userID, taskID).Return(listID, nil)
+		tasks.On("FetchByID", userID, listID, taskID).Return(&model.Task{UUID: taskID}, nil)
+		next.On("Restore", userID, listID, taskID, revisionID).Return(false, failure.ErrTaskNotFound)
+		next.On("Undo", userID).Return(nil, failure.ErrNothingToUndo)
+		var s = NewAuditedRevisionService(next, tasks, audit.NewTrail(l))
+		_, err := s.Restore(userID, listID, taskID, revisionID)
+		assert.Error(t, err)
+		_, err = s.Undo(userID)
+		assert.Error(t, err)
+		assert.Empty(t, l.entries)
+	})
+}
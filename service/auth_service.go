@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"noda/data/model"
 	"noda/data/transfer"
 	"noda/data/types"
 	"noda/failure"
 	"noda/global"
+	"noda/repository"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -18,15 +20,32 @@ import (
 type AuthenticationService interface {
 	SignUp(creation *transfer.UserCreation) (insertedID uuid.UUID, err error)
 	SignIn(credentials *transfer.UserCredentials) (payload *types.TokenPayload, err error)
+	Authenticate(credentials *transfer.UserCredentials) (userID uuid.UUID, role types.Role, err error)
+	Refresh(refreshToken string) (payload *types.TokenPayload, err error)
+	Logout(userID, sessionID uuid.UUID) error
+	RestoreAccount(credentials *transfer.UserCredentials) error
 }
 
+const (
+	// AccessTokenLifetime is how long a JSON Web Token is valid.
+	AccessTokenLifetime = 1 * time.Hour
+
+	// RefreshTokenLifetime is how long a refresh token can be exchanged for a
+	// new pair of tokens.
+	RefreshTokenLifetime = 30 * 24 * time.Hour
+)
+
 type authenticationService struct {
 	userService UserService
+	tokens      repository.TokenRepository
+	denylist    Denylist
 }
 
-func NewAuthenticationService(userService UserService) AuthenticationService {
+func NewAuthenticationService(userService UserService, tokens repository.TokenRepository, denylist Denylist) AuthenticationService {
 	return &authenticationService{
 		userService: userService,
+		tokens:      tokens,
+		denylist:    denylist,
 	}
 }
 
@@ -43,6 +62,61 @@ func (s *authenticationService) SignIn(credentials *transfer.UserCredentials) (p
 	if nil == credentials {
 		return nil, failure.NewNilParameterError("SignIn", "credentials")
 	}
+	user, err := s.authenticate(credentials)
+	if nil != err {
+		return nil, err
+	}
+	role, err := s.userService.AssertActive(user.UUID)
+	if nil != err {
+		return nil, err
+	}
+	return s.issue(user.UUID, role, uuid.New())
+}
+
+// Authenticate checks the credentials of an active user without signing in,
+// for clients that send them along with every request, such as CalDAV ones.
+func (s *authenticationService) Authenticate(credentials *transfer.UserCredentials) (userID uuid.UUID, role types.Role, err error) {
+	if nil == credentials {
+		return uuid.Nil, 0, failure.NewNilParameterError("Authenticate", "credentials")
+	}
+	user, err := s.authenticate(credentials)
+	if nil != err {
+		return uuid.Nil, 0, err
+	}
+	role, err = s.userService.AssertActive(user.UUID)
+	if nil != err {
+		return uuid.Nil, 0, err
+	}
+	return user.UUID, role, nil
+}
+
+// RestoreAccount restores the deleted account of the user with the given
+// credentials before its grace period is over. The sessions the user had
+// before the deletion are not restored; the user must sign in again.
+func (s *authenticationService) RestoreAccount(credentials *transfer.UserCredentials) error {
+	if nil == credentials {
+		return failure.NewNilParameterError("RestoreAccount", "credentials")
+	}
+	user, err := s.authenticate(credentials)
+	if nil != err {
+		return err
+	}
+	ok, err := s.userService.Restore(user.UUID)
+	if nil != err {
+		return err
+	}
+	if !ok {
+		return failure.ErrUserNotDeleted
+	}
+	if _, err := s.tokens.RevokeAll(user.UUID.String()); nil != err {
+		log.Println(err)
+	}
+	return nil
+}
+
+// authenticate returns the user with the given credentials, regardless of the
+// status of its account.
+func (s *authenticationService) authenticate(credentials *transfer.UserCredentials) (*model.User, error) {
 	doTrim(&credentials.Email, &credentials.Password)
 	switch {
 	case 72 < len(credentials.Password):
@@ -67,13 +141,86 @@ func (s *authenticationService) SignIn(credentials *transfer.UserCredentials) (p
 			return nil, failure.ErrIncorrectPassword
 		}
 	}
+	return user, nil
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens of the same
+// session. The refresh token is revoked in the process, so it can be used only
+// once: presenting it again is taken as a sign that it was stolen and revokes
+// the whole session.
+func (s *authenticationService) Refresh(refreshToken string) (payload *types.TokenPayload, err error) {
+	doTrim(&refreshToken)
+	if "" == refreshToken {
+		return nil, failure.ErrInvalidRefreshToken
+	}
+	token, err := s.tokens.FetchByHash(hashOpaqueToken(refreshToken))
+	if nil != err {
+		return nil, err
+	}
+	if nil != token.RevokedAt {
+		s.revokeReusedSession(token)
+		return nil, failure.ErrInvalidRefreshToken
+	}
+	if !time.Now().Before(token.ExpiresAt) {
+		return nil, failure.ErrInvalidRefreshToken
+	}
+	ok, err := s.tokens.Revoke(token.UUID.String())
+	if nil != err {
+		return nil, err
+	}
+	if !ok {
+		/* Another request rotated this token first.  */
+		s.revokeReusedSession(token)
+		return nil, failure.ErrInvalidRefreshToken
+	}
+	role, err := s.userService.AssertActive(token.UserUUID)
+	if nil != err {
+		return nil, err
+	}
+	return s.issue(token.UserUUID, role, token.SessionUUID)
+}
+
+func (s *authenticationService) revokeReusedSession(token *model.RefreshToken) {
+	log.Printf("refresh token %s of session %s was reused, revoking the session", token.UUID, token.SessionUUID)
+	err := s.Logout(token.UserUUID, token.SessionUUID)
+	if nil != err {
+		log.Println(err)
+	}
+}
+
+// Logout revokes the refresh tokens of the session and denies its access
+// tokens until they expire.
+func (s *authenticationService) Logout(userID, sessionID uuid.UUID) error {
+	switch {
+	case uuid.Nil == userID:
+		var err = failure.NewNilParameterError("Logout", "userID")
+		log.Println(err)
+		return err
+	case uuid.Nil == sessionID:
+		var err = failure.NewNilParameterError("Logout", "sessionID")
+		log.Println(err)
+		return err
+	}
+	_, err := s.tokens.RevokeSession(userID.String(), sessionID.String())
+	if nil != err {
+		return err
+	}
+	return s.denylist.Add(sessionID.String(), time.Now().Add(AccessTokenLifetime))
+}
+
+// issue signs a new access token and stores a new refresh token for the
+// session of the user.
+func (s *authenticationService) issue(userID uuid.UUID, role types.Role, sessionID uuid.UUID) (payload *types.TokenPayload, err error) {
+	var now = time.Now()
 	var claims = jwt.MapClaims{
 		"iss":       "noda",
 		"sub":       "authentication",
-		"iat":       jwt.NewNumericDate(time.Now()),
-		"exp":       jwt.NewNumericDate(time.Now().Add(1 * time.Hour)),
-		"user_uuid": user.UUID,
-		"user_role": user.Role,
+		"jti":       uuid.New().String(),
+		"sid":       sessionID.String(),
+		"iat":       jwt.NewNumericDate(now),
+		"exp":       jwt.NewNumericDate(now.Add(AccessTokenLifetime)),
+		"user_uuid": userID,
+		"user_role": role,
 	}
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	ss, err := t.SignedString(global.Secret())
@@ -81,6 +228,16 @@ func (s *authenticationService) SignIn(credentials *transfer.UserCredentials) (p
 		log.Println(err)
 		return nil, err
 	}
+	refreshToken, err := generateOpaqueToken()
+	if nil != err {
+		log.Println(err)
+		return nil, err
+	}
+	var refreshExpiresAt = now.Add(RefreshTokenLifetime)
+	_, err = s.tokens.Save(userID.String(), sessionID.String(), hashOpaqueToken(refreshToken), refreshExpiresAt)
+	if nil != err {
+		return nil, err
+	}
 	var jti, _ = claims["jti"].(string)
 	var sub, _ = claims["sub"].(string)
 	var iss, _ = claims["iss"].(string)
@@ -89,6 +246,9 @@ func (s *authenticationService) SignIn(credentials *transfer.UserCredentials) (p
 		Token:   ss,
 		Subject: sub,
 		Issuer:  iss,
+
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
 	}
 	var ok bool
 	var iat, exp *jwt.NumericDate
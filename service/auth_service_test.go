@@ -31,7 +31,7 @@ func TestAuthenticationService_SignUp(t *testing.T) {
 		var creation = &transfer.UserCreation{}
 		var s = mocks.NewUserServiceMock()
 		s.On(routine, creation).Return(inserted, nil)
-		res, err = NewAuthenticationService(s).SignUp(creation)
+		res, err = NewAuthenticationService(s, mocks.NewTokenRepositoryMock(), NewDenylist(nil)).SignUp(creation)
 		assert.Equal(t, inserted, res)
 		assert.NoError(t, err)
 	})
@@ -39,7 +39,7 @@ func TestAuthenticationService_SignUp(t *testing.T) {
 	t.Run("parameter \"creation\" cannot be nil", func(t *testing.T) {
 		var s = mocks.NewUserServiceMock()
 		s.AssertNotCalled(t, routine)
-		res, err = NewAuthenticationService(s).SignUp(nil)
+		res, err = NewAuthenticationService(s, mocks.NewTokenRepositoryMock(), NewDenylist(nil)).SignUp(nil)
 		assert.ErrorContains(t, err, failure.NewNilParameterError("SignUp", "creation").Error())
 		assert.Equal(t, uuid.Nil, res)
 	})
@@ -49,7 +49,7 @@ func TestAuthenticationService_SignUp(t *testing.T) {
 		var creation = &transfer.UserCreation{}
 		var s = mocks.NewUserServiceMock()
 		s.On(routine, mock.Anything).Return(uuid.Nil, unexpected)
-		res, err = NewAuthenticationService(s).SignUp(creation)
+		res, err = NewAuthenticationService(s, mocks.NewTokenRepositoryMock(), NewDenylist(nil)).SignUp(creation)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Equal(t, uuid.Nil, res)
 	})
@@ -70,13 +70,16 @@ func TestAuthenticationService_SignIn(t *testing.T) {
 			Email:    email,
 			Password: string(hash),
 		}
+		tokens = mocks.NewTokenRepositoryMock()
 	)
+	tokens.On("Save", user.UUID.String(), mock.Anything, mock.Anything, mock.Anything).Return(uuid.NewString(), nil)
 
 	t.Run("success", func(t *testing.T) {
 		var credentials = &transfer.UserCredentials{Email: user.Email, Password: password}
 		var us = mocks.NewUserServiceMock()
 		us.On(routine, credentials.Email).Return(user, nil)
-		res, err = NewAuthenticationService(us).SignIn(credentials)
+		us.On("AssertActive", user.UUID).Return(user.Role, nil)
+		res, err = NewAuthenticationService(us, tokens, NewDenylist(tokens)).SignIn(credentials)
 		assert.NoError(t, err)
 		if assert.NotNil(t, res) {
 			var token, _ = jwt.Parse(res.Token, func(tk *jwt.Token) (any, error) {
@@ -114,13 +117,19 @@ func TestAuthenticationService_SignIn(t *testing.T) {
 			}
 			assert.Equal(t, user.UUID.String(), claims["user_uuid"])
 			assert.Equal(t, user.Role, types.Role(claims["user_role"].(float64)))
+			assert.Equal(t, claims["jti"], res.ID)
+			_, err = uuid.Parse(claims["sid"].(string))
+			assert.NoError(t, err, "Claim \"sid\" must be a UUID.")
+			assert.NotEmpty(t, res.RefreshToken)
+			assert.WithinDuration(t, time.Now().Add(RefreshTokenLifetime), res.RefreshExpiresAt, time.Minute)
+			tokens.AssertCalled(t, "Save", user.UUID.String(), claims["sid"], hashOpaqueToken(res.RefreshToken), res.RefreshExpiresAt)
 		}
 	})
 
 	t.Run("parameter \"credentials\" cannot be nil", func(t *testing.T) {
 		var s = mocks.NewUserServiceMock()
 		s.AssertNotCalled(t, routine)
-		res, err = NewAuthenticationService(s).SignIn(nil)
+		res, err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).SignIn(nil)
 		assert.ErrorContains(t, err, failure.NewNilParameterError("SignIn", "credentials").Error())
 		assert.Nil(t, res)
 	})
@@ -132,16 +141,39 @@ func TestAuthenticationService_SignIn(t *testing.T) {
 		}
 		var s = mocks.NewUserServiceMock()
 		s.On(routine, email).Return(user, nil)
-		res, err = NewAuthenticationService(s).SignIn(credentials)
+		s.On("AssertActive", user.UUID).Return(user.Role, nil)
+		res, err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).SignIn(credentials)
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
 	})
 
+	t.Run("blocked user cannot sign in", func(t *testing.T) {
+		var credentials = &transfer.UserCredentials{Email: email, Password: password}
+		var s = mocks.NewUserServiceMock()
+		var tokens = mocks.NewTokenRepositoryMock()
+		s.On(routine, email).Return(user, nil)
+		s.On("AssertActive", user.UUID).Return(types.Role(0), failure.ErrUserBlocked)
+		res, err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).SignIn(credentials)
+		assert.ErrorIs(t, err, failure.ErrUserBlocked)
+		assert.Nil(t, res)
+		tokens.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("wrong password does not tell whether the user is blocked", func(t *testing.T) {
+		var credentials = &transfer.UserCredentials{Email: email, Password: password + "x"}
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, email).Return(user, nil)
+		res, err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).SignIn(credentials)
+		assert.ErrorIs(t, err, failure.ErrIncorrectPassword)
+		assert.Nil(t, res)
+		s.AssertNotCalled(t, "AssertActive", mock.Anything)
+	})
+
 	t.Run("email must match regexp", func(t *testing.T) {
 		var credentials = &transfer.UserCredentials{Email: "wrong"}
 		var s = mocks.NewUserServiceMock()
 		s.AssertNotCalled(t, routine)
-		res, err = NewAuthenticationService(s).SignIn(credentials)
+		res, err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).SignIn(credentials)
 		assert.Nil(t, res)
 		assert.ErrorContains(t, err, "Email address does not match regular expression")
 	})
@@ -155,7 +187,7 @@ func TestAuthenticationService_SignIn(t *testing.T) {
 			credentials.Email = max
 			var s = mocks.NewUserServiceMock()
 			s.AssertNotCalled(t, routine)
-			res, err = NewAuthenticationService(s).SignIn(credentials)
+			res, err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).SignIn(credentials)
 			assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Email", "credentials", 240).Error())
 			assert.Nil(t, res)
 			credentials.Email = ""
@@ -165,7 +197,7 @@ func TestAuthenticationService_SignIn(t *testing.T) {
 			credentials.Password = max + "0*"
 			var r = mocks.NewUserServiceMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewAuthenticationService(r).SignIn(credentials)
+			res, err = NewAuthenticationService(r, tokens, NewDenylist(tokens)).SignIn(credentials)
 			assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Password", "credentials", 72).Error())
 			assert.Nil(t, res)
 		})
@@ -176,8 +208,279 @@ func TestAuthenticationService_SignIn(t *testing.T) {
 		var credentials = &transfer.UserCredentials{Email: email, Password: password}
 		var s = mocks.NewUserServiceMock()
 		s.On(routine, mock.Anything).Return(nil, unexpected)
-		res, err = NewAuthenticationService(s).SignIn(credentials)
+		res, err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).SignIn(credentials)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Nil(t, res)
+	})
+}
+
+func TestAuthenticationService_Authenticate(t *testing.T) {
+	const (
+		routine  = "FetchRawUserByEmail"
+		password = "x@e8[a+*GAUsKBZ!d}>3&"
+		email    = "izs16833@zslsz.com"
+	)
+	var hash, _ = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	var user = &model.User{
+		UUID:     uuid.New(),
+		Email:    email,
+		Password: string(hash),
+		Role:     types.RoleUser,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var credentials = &transfer.UserCredentials{Email: email, Password: password}
+		var s = mocks.NewUserServiceMock()
+		var tokens = mocks.NewTokenRepositoryMock()
+		s.On(routine, email).Return(user, nil)
+		s.On("AssertActive", user.UUID).Return(types.RoleUser, nil)
+		userID, role, err := NewAuthenticationService(s, tokens, NewDenylist(tokens)).Authenticate(credentials)
+		assert.NoError(t, err)
+		assert.Equal(t, user.UUID, userID)
+		assert.Equal(t, types.RoleUser, role)
+		tokens.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		var credentials = &transfer.UserCredentials{Email: email, Password: password + "x"}
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, email).Return(user, nil)
+		userID, _, err := NewAuthenticationService(s, nil, NewDenylist(nil)).Authenticate(credentials)
+		assert.ErrorIs(t, err, failure.ErrIncorrectPassword)
+		assert.Equal(t, uuid.Nil, userID)
+	})
+
+	t.Run("blocked user", func(t *testing.T) {
+		var credentials = &transfer.UserCredentials{Email: email, Password: password}
+		var s = mocks.NewUserServiceMock()
+		s.On(routine, email).Return(user, nil)
+		s.On("AssertActive", user.UUID).Return(types.Role(0), failure.ErrUserBlocked)
+		userID, _, err := NewAuthenticationService(s, nil, NewDenylist(nil)).Authenticate(credentials)
+		assert.ErrorIs(t, err, failure.ErrUserBlocked)
+		assert.Equal(t, uuid.Nil, userID)
+	})
+}
+
+func TestAuthenticationService_Refresh(t *testing.T) {
+	defer beQuiet()()
+	const refreshToken = "Nq3nQGa0yVt0cJxk1Vf2mXo5c7cS8oA6PzS3zqkFh1E"
+	var (
+		res   *types.TokenPayload
+		err   error
+		hash  = hashOpaqueToken(refreshToken)
+		user  = &transfer.User{UUID: uuid.New(), Role: types.RoleAdmin}
+		fresh = func() *model.RefreshToken {
+			return &model.RefreshToken{
+				UUID:        uuid.New(),
+				UserUUID:    user.UUID,
+				SessionUUID: uuid.New(),
+				Hash:        hash,
+				ExpiresAt:   time.Now().Add(time.Hour),
+			}
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			token  = fresh()
+			tokens = mocks.NewTokenRepositoryMock()
+			us     = mocks.NewUserServiceMock()
+		)
+		tokens.On("FetchByHash", hash).Return(token, nil)
+		tokens.On("Revoke", token.UUID.String()).Return(true, nil)
+		tokens.On("Save", user.UUID.String(), token.SessionUUID.String(), mock.Anything, mock.Anything).Return(uuid.NewString(), nil)
+		us.On("AssertActive", user.UUID).Return(user.Role, nil)
+		res, err = NewAuthenticationService(us, tokens, NewDenylist(tokens)).Refresh(refreshToken)
+		assert.NoError(t, err)
+		if assert.NotNil(t, res) {
+			assert.NotEqual(t, refreshToken, res.RefreshToken)
+			var claims = jwt.MapClaims{}
+			_, err = jwt.ParseWithClaims(res.Token, claims, func(*jwt.Token) (any, error) { return global.Secret(), nil })
+			assert.NoError(t, err)
+			assert.Equal(t, token.SessionUUID.String(), claims["sid"])
+			assert.Equal(t, user.Role, types.Role(claims["user_role"].(float64)))
+		}
+		tokens.AssertExpectations(t)
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		var tokens = mocks.NewTokenRepositoryMock()
+		res, err = NewAuthenticationService(mocks.NewUserServiceMock(), tokens, NewDenylist(tokens)).Refresh(blankset)
+		assert.ErrorIs(t, err, failure.ErrInvalidRefreshToken)
+		assert.Nil(t, res)
+		tokens.AssertNotCalled(t, "FetchByHash", mock.Anything)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		var (
+			token  = fresh()
+			tokens = mocks.NewTokenRepositoryMock()
+		)
+		token.ExpiresAt = time.Now().Add(-time.Second)
+		tokens.On("FetchByHash", hash).Return(token, nil)
+		res, err = NewAuthenticationService(mocks.NewUserServiceMock(), tokens, NewDenylist(tokens)).Refresh(refreshToken)
+		assert.ErrorIs(t, err, failure.ErrInvalidRefreshToken)
+		assert.Nil(t, res)
+		tokens.AssertNotCalled(t, "Revoke", mock.Anything)
+	})
+
+	t.Run("reused token revokes the session", func(t *testing.T) {
+		var (
+			token     = fresh()
+			revokedAt = time.Now().Add(-time.Minute)
+			tokens    = mocks.NewTokenRepositoryMock()
+			denylist  = NewDenylist(tokens)
+		)
+		token.RevokedAt = &revokedAt
+		tokens.On("FetchByHash", hash).Return(token, nil)
+		tokens.On("RevokeSession", user.UUID.String(), token.SessionUUID.String()).Return(true, nil)
+		tokens.On("DenySession", token.SessionUUID.String(), mock.Anything).Return(nil)
+		res, err = NewAuthenticationService(mocks.NewUserServiceMock(), tokens, denylist).Refresh(refreshToken)
+		assert.ErrorIs(t, err, failure.ErrInvalidRefreshToken)
+		assert.Nil(t, res)
+		tokens.AssertCalled(t, "DenySession", token.SessionUUID.String(), mock.Anything)
+		tokens.AssertNotCalled(t, "Revoke", mock.Anything)
+	})
+
+	t.Run("lost the rotation to a concurrent request", func(t *testing.T) {
+		var (
+			token    = fresh()
+			tokens   = mocks.NewTokenRepositoryMock()
+			us       = mocks.NewUserServiceMock()
+			denylist = NewDenylist(tokens)
+		)
+		tokens.On("FetchByHash", hash).Return(token, nil)
+		tokens.On("Revoke", token.UUID.String()).Return(false, nil)
+		tokens.On("RevokeSession", user.UUID.String(), token.SessionUUID.String()).Return(true, nil)
+		tokens.On("DenySession", token.SessionUUID.String(), mock.Anything).Return(nil)
+		res, err = NewAuthenticationService(us, tokens, denylist).Refresh(refreshToken)
+		assert.ErrorIs(t, err, failure.ErrInvalidRefreshToken)
+		assert.Nil(t, res)
+		tokens.AssertCalled(t, "DenySession", token.SessionUUID.String(), mock.Anything)
+		us.AssertNotCalled(t, "AssertActive", mock.Anything)
+	})
+
+	t.Run("blocked user cannot refresh", func(t *testing.T) {
+		var (
+			token  = fresh()
+			tokens = mocks.NewTokenRepositoryMock()
+			us     = mocks.NewUserServiceMock()
+		)
+		tokens.On("FetchByHash", hash).Return(token, nil)
+		tokens.On("Revoke", token.UUID.String()).Return(true, nil)
+		us.On("AssertActive", user.UUID).Return(types.Role(0), failure.ErrUserBlocked)
+		res, err = NewAuthenticationService(us, tokens, NewDenylist(tokens)).Refresh(refreshToken)
+		assert.ErrorIs(t, err, failure.ErrUserBlocked)
+		assert.Nil(t, res)
+		tokens.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("got token repository error", func(t *testing.T) {
+		var (
+			unexpected = errors.New("unexpected error")
+			tokens     = mocks.NewTokenRepositoryMock()
+		)
+		tokens.On("FetchByHash", hash).Return(nil, unexpected)
+		res, err = NewAuthenticationService(mocks.NewUserServiceMock(), tokens, NewDenylist(tokens)).Refresh(refreshToken)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
 	})
 }
+
+func TestAuthenticationService_Logout(t *testing.T) {
+	defer beQuiet()()
+	const routine = "RevokeSession"
+	var (
+		userID    = uuid.New()
+		sessionID = uuid.New()
+		err       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			tokens   = mocks.NewTokenRepositoryMock()
+			denylist = NewDenylist(tokens)
+		)
+		tokens.On(routine, userID.String(), sessionID.String()).Return(true, nil)
+		tokens.On("DenySession", sessionID.String(), mock.Anything).Return(nil)
+		err = NewAuthenticationService(mocks.NewUserServiceMock(), tokens, denylist).Logout(userID, sessionID)
+		assert.NoError(t, err)
+		denied, err := denylist.Contains(sessionID.String())
+		assert.NoError(t, err)
+		assert.True(t, denied)
+		tokens.AssertNotCalled(t, "FetchSessionDenial", mock.Anything)
+	})
+
+	t.Run("parameters cannot be uuid.Nil", func(t *testing.T) {
+		var tokens = mocks.NewTokenRepositoryMock()
+		var s = NewAuthenticationService(mocks.NewUserServiceMock(), tokens, NewDenylist(tokens))
+		err = s.Logout(uuid.Nil, sessionID)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Logout", "userID").Error())
+		err = s.Logout(userID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Logout", "sessionID").Error())
+		tokens.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("got token repository error", func(t *testing.T) {
+		var (
+			unexpected = errors.New("unexpected error")
+			tokens     = mocks.NewTokenRepositoryMock()
+		)
+		tokens.On(routine, userID.String(), sessionID.String()).Return(false, unexpected)
+		err = NewAuthenticationService(mocks.NewUserServiceMock(), tokens, NewDenylist(tokens)).Logout(userID, sessionID)
+		assert.ErrorIs(t, err, unexpected)
+		tokens.AssertNotCalled(t, "DenySession", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthenticationService_RestoreAccount(t *testing.T) {
+	defer beQuiet()()
+	const (
+		routine  = "Restore"
+		password = "x@e8[a+*GAUsKBZ!d}>3&"
+		email    = "izs16833@zslsz.com"
+	)
+	var hash, _ = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	var (
+		err         error
+		user        = &model.User{UUID: uuid.New(), Email: email, Password: string(hash)}
+		credentials = &transfer.UserCredentials{Email: email, Password: password}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var s = mocks.NewUserServiceMock()
+		var tokens = mocks.NewTokenRepositoryMock()
+		s.On("FetchRawUserByEmail", email).Return(user, nil)
+		s.On(routine, user.UUID).Return(true, nil)
+		tokens.On("RevokeAll", user.UUID.String()).Return(true, nil)
+		err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).RestoreAccount(credentials)
+		assert.NoError(t, err)
+		tokens.AssertCalled(t, "RevokeAll", user.UUID.String())
+	})
+
+	t.Run("parameter \"credentials\" cannot be nil", func(t *testing.T) {
+		var s = mocks.NewUserServiceMock()
+		err = NewAuthenticationService(s, mocks.NewTokenRepositoryMock(), NewDenylist(nil)).RestoreAccount(nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("RestoreAccount", "credentials").Error())
+		s.AssertNotCalled(t, routine, mock.Anything)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		var s = mocks.NewUserServiceMock()
+		s.On("FetchRawUserByEmail", email).Return(user, nil)
+		err = NewAuthenticationService(s, mocks.NewTokenRepositoryMock(), NewDenylist(nil)).
+			RestoreAccount(&transfer.UserCredentials{Email: email, Password: password + "x"})
+		assert.ErrorIs(t, err, failure.ErrIncorrectPassword)
+		s.AssertNotCalled(t, routine, mock.Anything)
+	})
+
+	t.Run("the account was not deleted", func(t *testing.T) {
+		var s = mocks.NewUserServiceMock()
+		var tokens = mocks.NewTokenRepositoryMock()
+		s.On("FetchRawUserByEmail", email).Return(user, nil)
+		s.On(routine, user.UUID).Return(false, nil)
+		err = NewAuthenticationService(s, tokens, NewDenylist(tokens)).RestoreAccount(credentials)
+		assert.ErrorIs(t, err, failure.ErrUserNotDeleted)
+		tokens.AssertNotCalled(t, "RevokeAll", mock.Anything)
+	})
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"noda/audit"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/ical"
+	"noda/repository"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CalDAVService serves the lists of a user as CalDAV calendars and their tasks
+// as VTODO calendar objects, so that calendars and task apps can sync them
+// both ways. Changes made by the clients go through the TaskService, which
+// validates them as it does any other change.
+//
+// The headline of a task is not part of its calendar object, so clients
+// cannot clobber it, and neither are the properties of a VTODO tasks have no
+// field for. A client cannot remove the due date or the reminder of a task.
+type CalDAVService interface {
+	FetchCalendars(userID uuid.UUID) (calendars []*model.Calendar, err error)
+	FetchCalendar(userID, listID uuid.UUID) (calendar *model.Calendar, err error)
+	FetchObjects(userID, listID uuid.UUID) (objects []*model.CalendarObject, err error)
+	FetchObject(userID, listID uuid.UUID, name string) (object *model.CalendarObject, err error)
+	PutObject(userID, listID uuid.UUID, name, ifMatch, ifNoneMatch string, data []byte) (created bool, err error)
+	DeleteObject(userID, listID uuid.UUID, name, ifMatch string) error
+}
+
+// calDAVPageSize is how many lists or tasks are read at once to serve a whole
+// collection.
+const calDAVPageSize = 100
+
+type calDAVService struct {
+	r     repository.CalDAVRepository
+	lists ListService
+	tasks TaskService
+}
+
+func NewCalDAVService(r repository.CalDAVRepository, lists ListService, tasks TaskService) CalDAVService {
+	return &calDAVService{r: r, lists: lists, tasks: tasks}
+}
+
+// Within returns the service making its changes through the list and task
+// services as coming from the origin, so that they are audited as such.
+func (s *calDAVService) Within(origin audit.Origin) CalDAVService {
+	var bound = *s
+	bound.lists = Within(s.lists, origin)
+	bound.tasks = Within(s.tasks, origin)
+	return &bound
+}
+
+// FetchCalendars retrieves the lists of the user, each as a calendar.
+func (s *calDAVService) FetchCalendars(userID uuid.UUID) (calendars []*model.Calendar, err error) {
+	if uuid.Nil == userID {
+		err = failure.NewNilParameterError("FetchCalendars", "userID")
+		log.Println(err)
+		return nil, err
+	}
+	lists, err := s.fetchLists(userID)
+	if nil != err {
+		return nil, err
+	}
+	calendars = make([]*model.Calendar, 0, len(lists))
+	for _, list := range lists {
+		calendar, err := s.makeCalendar(userID, list)
+		if nil != err {
+			return nil, err
+		}
+		calendars = append(calendars, calendar)
+	}
+	return calendars, nil
+}
+
+func (s *calDAVService) FetchCalendar(userID, listID uuid.UUID) (calendar *model.Calendar, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchCalendar", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("FetchCalendar", "listID")
+		log.Println(err)
+		return nil, err
+	}
+	lists, err := s.fetchLists(userID)
+	if nil != err {
+		return nil, err
+	}
+	for _, list := range lists {
+		if listID == list.UUID {
+			return s.makeCalendar(userID, list)
+		}
+	}
+	return nil, failure.ErrListNotFound
+}
+
+// FetchObjects retrieves every task of the list as a calendar object.
+func (s *calDAVService) FetchObjects(userID, listID uuid.UUID) (objects []*model.CalendarObject, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchObjects", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("FetchObjects", "listID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.fetchObjects(userID, listID)
+}
+
+func (s *calDAVService) FetchObject(userID, listID uuid.UUID, name string) (object *model.CalendarObject, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchObject", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("FetchObject", "listID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.lookUpObject(userID, listID, strings.TrimSpace(name))
+}
+
+// PutObject makes a task out of the VTODO in data, or updates the task of the
+// object with the given name if there is one. A new object cannot take the UID
+// of another object of the calendar. The request can be made conditional with
+// the ETag of the object: ifMatch is checked strongly, and ifNoneMatch may
+// only be "*", so as not to overwrite an existing object.
+func (s *calDAVService) PutObject(userID, listID uuid.UUID, name, ifMatch, ifNoneMatch string, data []byte) (created bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("PutObject", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("PutObject", "listID")
+		log.Println(err)
+		return false, err
+	}
+	doTrim(&name, &ifMatch, &ifNoneMatch)
+	todo, uid, err := readObject(data)
+	if nil != err {
+		return false, err
+	}
+	existing, err := s.lookUpObject(userID, listID, name)
+	if nil != err && !errors.Is(err, failure.ErrCalendarObjectNotFound) {
+		return false, err
+	}
+	if nil == existing {
+		_, err = s.r.FetchHrefByUID(userID.String(), listID.String(), uid)
+		switch {
+		case nil == err:
+			return false, failure.ErrCalendarUIDConflict
+		case !errors.Is(err, failure.ErrCalendarObjectNotFound):
+			return false, err
+		}
+	} else if uid != existing.UID {
+		return false, failure.ErrCalendarUIDConflict
+	}
+	switch {
+	case nil != existing && "*" == ifNoneMatch:
+		return false, failure.ErrPreconditionFailed
+	case "" != ifMatch && (nil == existing || !satisfiesIfMatch(ifMatch, existing.ETag)):
+		return false, failure.ErrPreconditionFailed
+	case nil == existing:
+		err = s.makeTask(userID, listID, name, uid, todo)
+		return nil == err, err
+	}
+	return false, s.updateTask(userID, listID, existing.Task, todo)
+}
+
+// DeleteObject moves the task of the object to the trash, where it can still
+// be restored from.
+func (s *calDAVService) DeleteObject(userID, listID uuid.UUID, name, ifMatch string) error {
+	switch {
+	case uuid.Nil == userID:
+		var err = failure.NewNilParameterError("DeleteObject", "userID")
+		log.Println(err)
+		return err
+	case uuid.Nil == listID:
+		var err = failure.NewNilParameterError("DeleteObject", "listID")
+		log.Println(err)
+		return err
+	}
+	doTrim(&name, &ifMatch)
+	object, err := s.lookUpObject(userID, listID, name)
+	if nil != err {
+		return err
+	}
+	if "" != ifMatch && !satisfiesIfMatch(ifMatch, object.ETag) {
+		return failure.ErrPreconditionFailed
+	}
+	ok, err := s.tasks.Trash(userID, listID, object.Task.UUID)
+	if nil != err {
+		return err
+	}
+	if !ok {
+		return failure.ErrCalendarObjectNotFound
+	}
+	return nil
+}
+
+// makeTask makes the task of a new object and remembers the name and the UID
+// the client gave to it. The due date, the reminder and the completion are
+// then set as they would be on an existing task. The object is made entirely
+// or not at all: if any step fails, the task is deleted again, so that the
+// client can retry without leaving a half-made task behind.
+func (s *calDAVService) makeTask(userID, listID uuid.UUID, name, uid string, todo *model.Task) error {
+	var creation = &transfer.TaskCreation{
+		Title:       todo.Title,
+		Description: todo.Description,
+		Priority:    todo.Priority,
+	}
+	taskID, err := s.tasks.Save(userID, listID, creation)
+	if nil != err {
+		return err
+	}
+	err = s.r.SaveHref(userID.String(), listID.String(), taskID.String(), name, uid)
+	if nil == err {
+		err = s.updateTask(userID, listID, &model.Task{
+			UUID:        taskID,
+			Title:       creation.Title,
+			Description: creation.Description,
+			Priority:    creation.Priority,
+			Status:      types.TaskStatusIncomplete,
+		}, todo)
+	}
+	if nil != err {
+		if undoErr := s.tasks.Delete(userID, listID, taskID); nil != undoErr {
+			log.Printf("could not undo the creation of task %q: %v", taskID, undoErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// updateTask changes what differs between the task and the fields read out of
+// its VTODO.
+func (s *calDAVService) updateTask(userID, listID uuid.UUID, task, todo *model.Task) error {
+	var (
+		taskID = task.UUID
+		err    error
+	)
+	if ("" != todo.Title && todo.Title != task.Title) || todo.Description != task.Description {
+		var title = todo.Title
+		if "" == title {
+			title = task.Title
+		}
+		_, err = s.tasks.Update(userID, listID, taskID, &transfer.TaskUpdate{
+			Title:       title,
+			Headline:    task.Headline,
+			Description: todo.Description,
+		})
+		if nil != err {
+			return err
+		}
+	}
+	if todo.Priority != task.Priority {
+		_, err = s.tasks.SetPriority(userID, listID, taskID, todo.Priority)
+		if nil != err {
+			return err
+		}
+	}
+	if nil != todo.DueDate && (nil == task.DueDate || !todo.DueDate.Equal(*task.DueDate)) {
+		_, err = s.tasks.SetDueDate(userID, listID, taskID, *todo.DueDate)
+		if nil != err {
+			return err
+		}
+	}
+	if nil != todo.RemindAt && (nil == task.RemindAt || !todo.RemindAt.Equal(*task.RemindAt)) {
+		_, err = s.tasks.SetReminder(userID, listID, taskID, *todo.RemindAt)
+		if nil != err {
+			return err
+		}
+	}
+	var (
+		wasComplete = types.TaskStatusComplete == task.Status
+		isComplete  = types.TaskStatusComplete == todo.Status
+	)
+	switch {
+	case isComplete && !wasComplete:
+		_, err = s.tasks.Complete(userID, listID, taskID)
+	case !isComplete && wasComplete:
+		_, err = s.tasks.Resume(userID, listID, taskID)
+	}
+	return err
+}
+
+// makeCalendar makes the calendar of a list, tagged with how many tasks it has
+// and when it or any of them last changed.
+func (s *calDAVService) makeCalendar(userID uuid.UUID, list *model.List) (*model.Calendar, error) {
+	count, updatedAt, err := s.r.FetchLastChange(userID.String(), list.UUID.String())
+	if nil != err {
+		return nil, err
+	}
+	var latest = list.UpdatedAt
+	if nil != updatedAt && updatedAt.After(latest) {
+		latest = *updatedAt
+	}
+	return &model.Calendar{
+		List: list,
+		CTag: fmt.Sprintf(`"%d-%d"`, count, latest.UnixMicro()),
+	}, nil
+}
+
+func (s *calDAVService) fetchLists(userID uuid.UUID) (lists []*model.List, err error) {
+	lists = make([]*model.List, 0)
+	for page := int64(1); ; page++ {
+		result, err := s.lists.Fetch(userID, &types.Pagination{Page: page, RPP: calDAVPageSize}, "", "")
+		if nil != err {
+			return nil, err
+		}
+		lists = append(lists, result.Payload...)
+		if result.Retrieved < calDAVPageSize {
+			return lists, nil
+		}
+	}
+}
+
+// fetchObjects retrieves the tasks of the list along with the names and the
+// UIDs clients gave to them, and renders each as a calendar object.
+func (s *calDAVService) fetchObjects(userID, listID uuid.UUID) (objects []*model.CalendarObject, err error) {
+	hrefs, err := s.r.FetchHrefs(userID.String(), listID.String())
+	if nil != err {
+		return nil, err
+	}
+	var hrefByTask = make(map[uuid.UUID]*model.CalendarHref, len(hrefs))
+	for _, href := range hrefs {
+		hrefByTask[href.TaskUUID] = href
+	}
+	objects = make([]*model.CalendarObject, 0)
+	for page := int64(1); ; page++ {
+		result, err := s.tasks.Fetch(userID, listID, &types.Pagination{Page: page, RPP: calDAVPageSize}, "", "", nil, uuid.Nil)
+		if nil != err {
+			return nil, err
+		}
+		for _, task := range result.Payload {
+			object, err := renderObject(task, hrefByTask[task.UUID])
+			if nil != err {
+				return nil, err
+			}
+			objects = append(objects, object)
+		}
+		if result.Retrieved < calDAVPageSize {
+			return objects, nil
+		}
+	}
+}
+
+// renderObject writes a task as a calendar object, under the name and the UID
+// its client gave to it if it has one, else under its UUID. Its ETag is the
+// last time it changed.
+func renderObject(task *model.Task, href *model.CalendarHref) (*model.CalendarObject, error) {
+	var name, uid = task.UUID.String() + ".ics", task.UUID.String()
+	if nil != href {
+		name, uid = href.Name, href.UID
+	}
+	var withoutHeadline = *task
+	withoutHeadline.Headline = ""
+	var todo = ical.NewTodo(&withoutHeadline)
+	todo.Set("UID", ical.EscapeText(uid))
+	var calendar = ical.NewResource()
+	calendar.Append(todo)
+	var buf bytes.Buffer
+	err := calendar.Encode(&buf)
+	if nil != err {
+		log.Println(err)
+		return nil, err
+	}
+	return &model.CalendarObject{
+		Task: task,
+		Name: name,
+		UID:  uid,
+		ETag: fmt.Sprintf(`"%d"`, task.UpdatedAt.UnixMicro()),
+		Data: buf.Bytes(),
+	}, nil
+}
+
+// readObject reads the VTODO of a calendar object and its UID.
+func readObject(data []byte) (todo *model.Task, uid string, err error) {
+	calendar, err := ical.Decode(bytes.NewReader(data))
+	if nil != err {
+		return nil, "", failure.ErrBadCalendarObject.Clone().FormatDetails(err.Error())
+	}
+	var component *ical.Component
+	if "VCALENDAR" == calendar.Name {
+		component = calendar.Find("VTODO")
+	}
+	if nil == component {
+		return nil, "", failure.ErrBadCalendarObject.Clone().FormatDetails("The calendar has no VTODO.")
+	}
+	if p := component.Get("UID"); nil != p {
+		uid = strings.TrimSpace(p.Text())
+	}
+	if "" == uid {
+		return nil, "", failure.ErrBadCalendarObject.Clone().FormatDetails("The VTODO has no UID.")
+	}
+	todo, err = ical.ReadTodo(component)
+	if nil != err {
+		return nil, "", failure.ErrBadCalendarObject.Clone().FormatDetails(err.Error())
+	}
+	return todo, uid, nil
+}
+
+// lookUpObject finds the object with the given name: the task a client made
+// under that name, or else the task whose UUID the name is, unless a client
+// named it otherwise.
+func (s *calDAVService) lookUpObject(userID, listID uuid.UUID, name string) (*model.CalendarObject, error) {
+	var taskID uuid.UUID
+	href, err := s.r.FetchHrefByName(userID.String(), listID.String(), name)
+	switch {
+	case nil == err:
+		taskID = href.TaskUUID
+	case !errors.Is(err, failure.ErrCalendarObjectNotFound):
+		return nil, err
+	default:
+		var ok bool
+		taskID, ok = parseObjectName(name)
+		if !ok {
+			return nil, failure.ErrCalendarObjectNotFound
+		}
+		_, err = s.r.FetchHrefOfTask(userID.String(), listID.String(), taskID.String())
+		switch {
+		case nil == err:
+			return nil, failure.ErrCalendarObjectNotFound
+		case !errors.Is(err, failure.ErrCalendarObjectNotFound):
+			return nil, err
+		}
+		href = nil
+	}
+	task, err := s.tasks.FetchByID(userID, listID, taskID)
+	if nil != err {
+		if errors.Is(err, failure.ErrTaskNotFound) {
+			return nil, failure.ErrCalendarObjectNotFound
+		}
+		return nil, err
+	}
+	return renderObject(task, href)
+}
+
+// parseObjectName reads the UUID of a task out of the name of an object that
+// no client named, "<uuid>.ics".
+func parseObjectName(name string) (uuid.UUID, bool) {
+	var base, found = strings.CutSuffix(name, ".ics")
+	if !found {
+		return uuid.Nil, false
+	}
+	taskID, err := uuid.Parse(base)
+	if nil != err || base != taskID.String() {
+		return uuid.Nil, false
+	}
+	return taskID, true
+}
+
+// satisfiesIfMatch tells whether an If-Match header matches the ETag,
+// strongly as RFC 9110 requires.
+func satisfiesIfMatch(ifMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if "*" == candidate || etag == candidate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/audit"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"noda/repository"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalDAVService_FetchCalendars(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID  = uuid.New()
+		updated = time.Date(2024, 12, 2, 10, 15, 0, 0, time.UTC)
+		list    = &model.List{UUID: uuid.New(), OwnerUUID: userID, Name: "Chores", UpdatedAt: updated.Add(-time.Hour)}
+		empty   = &model.List{UUID: uuid.New(), OwnerUUID: userID, Name: "Errands", UpdatedAt: updated}
+	)
+	var lists = mocks.NewListServiceMock()
+	lists.On("Fetch", userID, mock.Anything, "", "").
+		Return(&types.Result[model.List]{Page: 1, RPP: calDAVPageSize, Retrieved: 2, Payload: []*model.List{list, empty}}, nil)
+	var r = mocks.NewCalDAVRepositoryMock()
+	r.On("FetchLastChange", userID.String(), list.UUID.String()).Return(int64(1), &updated, nil)
+	r.On("FetchLastChange", userID.String(), empty.UUID.String()).Return(int64(0), nil, nil)
+	res, err := NewCalDAVService(r, lists, nil).FetchCalendars(userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.Calendar{
+		{List: list, CTag: `"1-1733134500000000"`},
+		{List: empty, CTag: `"0-1733134500000000"`},
+	}, res)
+}
+
+// TestCalDAVService_FetchCalendarsFromTheDatabase discovers the calendars
+// through the list service and repository the server uses, so that a query
+// binding other placeholders than its arguments fails here as in Postgres.
+func TestCalDAVService_FetchCalendarsFromTheDatabase(t *testing.T) {
+	defer beQuiet()()
+	var placeholders = regexp.MustCompile(`\$\d+`)
+	var binds = func(n int) sqlmock.QueryMatcher {
+		return sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+			if !strings.Contains(actualSQL, expectedSQL) {
+				return fmt.Errorf("the query does not call %s", expectedSQL)
+			}
+			var want = make([]string, 0, n)
+			for i := 1; i <= n; i++ {
+				want = append(want, fmt.Sprintf("$%d", i))
+			}
+			if got := placeholders.FindAllString(actualSQL, -1); !assert.ObjectsAreEqual(want, got) {
+				return fmt.Errorf("the query binds %v, not %v", got, want)
+			}
+			return nil
+		})
+	}
+	var (
+		userID  = uuid.New()
+		listID  = uuid.New()
+		updated = time.Date(2024, 12, 2, 10, 15, 0, 0, time.UTC)
+	)
+	db, dbMock, err := sqlmock.New(sqlmock.QueryMatcherOption(binds(4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	dbMock.
+		ExpectQuery(`"lists"."fetch"`).
+		WithArgs(userID.String(), "", int64(1), int64(calDAVPageSize)).
+		WillReturnRows(sqlmock.
+			NewRows([]string{"uuid", "owner_uuid", "group_uuid", "name", "description", "created_at", "updated_at"}).
+			AddRow(listID, userID, nil, "Chores", "", updated, updated))
+	var r = mocks.NewCalDAVRepositoryMock()
+	r.On("FetchLastChange", userID.String(), listID.String()).Return(int64(1), &updated, nil)
+	var lists = NewListService(repository.NewListRepository(db), nil)
+	res, err := NewCalDAVService(r, lists, nil).FetchCalendars(userID)
+	assert.NoError(t, err)
+	if assert.Len(t, res, 1) {
+		assert.Equal(t, listID, res[0].List.UUID)
+		assert.Equal(t, "Chores", res[0].List.Name)
+		assert.Equal(t, `"1-1733134500000000"`, res[0].CTag)
+	}
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
+
+func TestCalDAVService_FetchObject(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID = uuid.New(), uuid.New()
+		updated        = time.Date(2024, 12, 2, 10, 15, 0, 0, time.UTC)
+		made           = &model.Task{UUID: uuid.New(), Title: "Buy paint", Headline: "White", UpdatedAt: updated}
+		synced         = &model.Task{UUID: uuid.New(), Title: "Paint the fence", UpdatedAt: updated}
+		href           = &model.CalendarHref{TaskUUID: synced.UUID, Name: "0C5D1F8E.ics", UID: "0C5D1F8E"}
+		s              CalDAVService
+	)
+	var tasks = mocks.NewTaskServiceMock()
+	tasks.On("FetchByID", userID, listID, made.UUID).Return(made, nil)
+	tasks.On("FetchByID", userID, listID, synced.UUID).Return(synced, nil)
+	var r = mocks.NewCalDAVRepositoryMock()
+	r.On("FetchHrefByName", userID.String(), listID.String(), "0C5D1F8E.ics").Return(href, nil)
+	r.On("FetchHrefByName", userID.String(), listID.String(), mock.Anything).Return(nil, failure.ErrCalendarObjectNotFound)
+	r.On("FetchHrefOfTask", userID.String(), listID.String(), synced.UUID.String()).Return(href, nil)
+	r.On("FetchHrefOfTask", userID.String(), listID.String(), mock.Anything).Return(nil, failure.ErrCalendarObjectNotFound)
+	s = NewCalDAVService(r, nil, tasks)
+
+	t.Run("under its UUID", func(t *testing.T) {
+		res, err := s.FetchObject(userID, listID, made.UUID.String()+".ics")
+		assert.NoError(t, err)
+		assert.Equal(t, made, res.Task)
+		assert.Equal(t, made.UUID.String(), res.UID)
+		assert.Equal(t, `"1733134500000000"`, res.ETag)
+		assert.Contains(t, string(res.Data), "UID:"+made.UUID.String()+"\r\n")
+		assert.NotContains(t, string(res.Data), "White")
+		assert.NotContains(t, string(res.Data), "METHOD")
+	})
+
+	t.Run("under the name its client gave", func(t *testing.T) {
+		res, err := s.FetchObject(userID, listID, "0C5D1F8E.ics")
+		assert.NoError(t, err)
+		assert.Equal(t, synced, res.Task)
+		assert.Contains(t, string(res.Data), "UID:0C5D1F8E\r\n")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var missing = uuid.New()
+		tasks.On("FetchByID", userID, listID, missing).Return(nil, failure.ErrTaskNotFound)
+		for _, name := range []string{synced.UUID.String() + ".ics", missing.String() + ".ics", "missing.ics", made.UUID.String()} {
+			res, err := s.FetchObject(userID, listID, name)
+			assert.ErrorIs(t, err, failure.ErrCalendarObjectNotFound, "name: %q", name)
+			assert.Nil(t, res)
+		}
+	})
+}
+
+func TestCalDAVService_PutObject(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID = uuid.New(), uuid.New()
+		updated        = time.Date(2024, 12, 2, 10, 15, 0, 0, time.UTC)
+		due            = time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+		existing       = &model.Task{
+			UUID:      uuid.New(),
+			Title:     "Paint the fence",
+			Priority:  types.TaskPriorityMedium,
+			Status:    types.TaskStatusIncomplete,
+			DueDate:   &due,
+			UpdatedAt: updated,
+		}
+		etag = `"1733134500000000"`
+		name = existing.UUID.String() + ".ics"
+	)
+	var todo = func(properties ...string) []byte {
+		return []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\n" +
+			strings.Join(properties, "\r\n") + "\r\nEND:VTODO\r\nEND:VCALENDAR\r\n")
+	}
+	var setUp = func() (*mocks.CalDAVRepository, *mocks.TaskServiceMock) {
+		var tasks = mocks.NewTaskServiceMock()
+		tasks.On("FetchByID", userID, listID, existing.UUID).Return(existing, nil).Maybe()
+		var r = mocks.NewCalDAVRepositoryMock()
+		r.On("FetchHrefByName", userID.String(), listID.String(), mock.Anything).Return(nil, failure.ErrCalendarObjectNotFound)
+		r.On("FetchHrefOfTask", userID.String(), listID.String(), mock.Anything).Return(nil, failure.ErrCalendarObjectNotFound).Maybe()
+		return r, tasks
+	}
+
+	t.Run("makes a task", func(t *testing.T) {
+		var r, tasks = setUp()
+		var taskID = uuid.New()
+		tasks.On("Save", userID, listID, &transfer.TaskCreation{Title: "Buy paint", Priority: types.TaskPriorityUrgent}).
+			Return(taskID, nil)
+		tasks.On("SetDueDate", userID, listID, taskID, due).Return(true, nil)
+		tasks.On("Complete", userID, listID, taskID).Return(true, nil)
+		r.On("FetchHrefByUID", userID.String(), listID.String(), "0C5D1F8E").Return(nil, failure.ErrCalendarObjectNotFound)
+		r.On("SaveHref", userID.String(), listID.String(), taskID.String(), "0C5D1F8E.ics", "0C5D1F8E").Return(nil)
+		created, err := NewCalDAVService(r, nil, tasks).PutObject(userID, listID, "0C5D1F8E.ics", "", "*",
+			todo("UID:0C5D1F8E", "SUMMARY:Buy paint", "PRIORITY:1", "DUE:20241231T180000Z", "STATUS:COMPLETED"))
+		assert.NoError(t, err)
+		assert.True(t, created)
+		tasks.AssertExpectations(t)
+		r.AssertExpectations(t)
+		tasks.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("deletes the task it could not finish", func(t *testing.T) {
+		var r, tasks = setUp()
+		var (
+			taskID     = uuid.New()
+			unexpected = errors.New("unexpected error")
+		)
+		tasks.On("Save", userID, listID, mock.Anything).Return(taskID, nil)
+		tasks.On("SetDueDate", userID, listID, taskID, due).Return(false, unexpected)
+		tasks.On("Delete", userID, listID, taskID).Return(nil)
+		r.On("FetchHrefByUID", userID.String(), listID.String(), "0C5D1F8E").Return(nil, failure.ErrCalendarObjectNotFound)
+		r.On("SaveHref", userID.String(), listID.String(), taskID.String(), "0C5D1F8E.ics", "0C5D1F8E").Return(nil)
+		created, err := NewCalDAVService(r, nil, tasks).PutObject(userID, listID, "0C5D1F8E.ics", "", "",
+			todo("UID:0C5D1F8E", "SUMMARY:Buy paint", "DUE:20241231T180000Z"))
+		assert.ErrorIs(t, err, unexpected)
+		assert.False(t, created)
+		tasks.AssertExpectations(t)
+	})
+
+	t.Run("updates what changed", func(t *testing.T) {
+		var r, tasks = setUp()
+		tasks.On("SetPriority", userID, listID, existing.UUID, types.TaskPriorityHigh).Return(true, nil)
+		created, err := NewCalDAVService(r, nil, tasks).PutObject(userID, listID, name, etag, "",
+			todo("UID:"+existing.UUID.String(), "SUMMARY:Paint the fence", "PRIORITY:3", "DUE:20241231T180000Z"))
+		assert.NoError(t, err)
+		assert.False(t, created)
+		tasks.AssertExpectations(t)
+		tasks.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		tasks.AssertNotCalled(t, "SetDueDate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses a UID in use", func(t *testing.T) {
+		var r, tasks = setUp()
+		r.On("FetchHrefByUID", userID.String(), listID.String(), "0C5D1F8E").
+			Return(&model.CalendarHref{TaskUUID: uuid.New(), Name: "other.ics", UID: "0C5D1F8E"}, nil)
+		created, err := NewCalDAVService(r, nil, tasks).PutObject(userID, listID, "0C5D1F8E.ics", "", "",
+			todo("UID:0C5D1F8E", "SUMMARY:Buy paint"))
+		assert.ErrorIs(t, err, failure.ErrCalendarUIDConflict)
+		assert.False(t, created)
+		_, err = NewCalDAVService(r, nil, tasks).PutObject(userID, listID, name, "", "",
+			todo("UID:0C5D1F8E", "SUMMARY:Paint the fence"))
+		assert.ErrorIs(t, err, failure.ErrCalendarUIDConflict)
+		tasks.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("refuses stale changes", func(t *testing.T) {
+		for _, headers := range [][2]string{{`"1"`, ""}, {"", "*"}, {etag, "*"}} {
+			var r, tasks = setUp()
+			created, err := NewCalDAVService(r, nil, tasks).PutObject(userID, listID, name, headers[0], headers[1],
+				todo("UID:"+existing.UUID.String(), "SUMMARY:Paint the fence"))
+			assert.ErrorIs(t, err, failure.ErrPreconditionFailed, "headers: %q", headers)
+			assert.False(t, created)
+		}
+		var r, tasks = setUp()
+		r.On("FetchHrefByUID", userID.String(), listID.String(), "missing").Return(nil, failure.ErrCalendarObjectNotFound)
+		_, err := NewCalDAVService(r, nil, tasks).PutObject(userID, listID, "missing.ics", etag, "",
+			todo("UID:missing", "SUMMARY:Paint the fence"))
+		assert.ErrorIs(t, err, failure.ErrPreconditionFailed)
+	})
+
+	t.Run("refuses invalid objects", func(t *testing.T) {
+		for details, data := range map[string][]byte{
+			"missing END:VCALENDAR":      []byte("BEGIN:VCALENDAR\r\n"),
+			"The calendar has no VTODO.": []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"),
+			"The VTODO has no UID.":      todo("SUMMARY:Paint the fence"),
+			`invalid PRIORITY "high"`:    todo("UID:a", "PRIORITY:high"),
+		} {
+			var r, tasks = setUp()
+			_, err := NewCalDAVService(r, nil, tasks).PutObject(userID, listID, name, "", "", data)
+			assert.ErrorContains(t, err, failure.ErrBadCalendarObject.Clone().FormatDetails(details).Error())
+		}
+	})
+}
+
+func TestCalDAVService_DeleteObject(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID = uuid.New(), uuid.New()
+		task           = &model.Task{UUID: uuid.New(), Title: "Paint the fence", UpdatedAt: time.Now()}
+		name           = task.UUID.String() + ".ics"
+	)
+	var setUp = func() (*mocks.CalDAVRepository, *mocks.TaskServiceMock) {
+		var tasks = mocks.NewTaskServiceMock()
+		tasks.On("FetchByID", userID, listID, task.UUID).Return(task, nil)
+		var r = mocks.NewCalDAVRepositoryMock()
+		r.On("FetchHrefByName", userID.String(), listID.String(), mock.Anything).Return(nil, failure.ErrCalendarObjectNotFound)
+		r.On("FetchHrefOfTask", userID.String(), listID.String(), task.UUID.String()).Return(nil, failure.ErrCalendarObjectNotFound)
+		return r, tasks
+	}
+
+	t.Run("moves the task to the trash", func(t *testing.T) {
+		var r, tasks = setUp()
+		tasks.On("Trash", userID, listID, task.UUID).Return(true, nil)
+		err := NewCalDAVService(r, nil, tasks).DeleteObject(userID, listID, name, "")
+		assert.NoError(t, err)
+		tasks.AssertExpectations(t)
+	})
+
+	t.Run("audited as coming from the client", func(t *testing.T) {
+		var r, tasks = setUp()
+		var l = new(recordingLog)
+		tasks.On("Locate", userID, task.UUID).Return(listID, nil)
+		tasks.On("Trash", userID, listID, task.UUID).Return(true, nil)
+		var s = NewCalDAVService(r, nil, NewAuditedTaskService(tasks, audit.NewTrail(l)))
+		err := Within(s, audit.Origin{IP: "10.0.0.7", UserAgent: "DAVx5/4.4"}).DeleteObject(userID, listID, name, "")
+		assert.NoError(t, err)
+		if assert.Len(t, l.entries, 1) {
+			assert.Equal(t, &userID, l.entries[0].ActorUUID)
+			assert.Equal(t, string(types.RevisionActionTrashed), l.entries[0].Action)
+			assert.Equal(t, "10.0.0.7", l.entries[0].IP)
+			assert.Equal(t, "DAVx5/4.4", l.entries[0].UserAgent)
+		}
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		var r, tasks = setUp()
+		err := NewCalDAVService(r, nil, tasks).DeleteObject(userID, listID, name, `"1"`)
+		assert.ErrorIs(t, err, failure.ErrPreconditionFailed)
+		tasks.AssertNotCalled(t, "Trash", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var r, tasks = setUp()
+		err := NewCalDAVService(r, nil, tasks).DeleteObject(userID, listID, "missing.ics", "")
+		assert.ErrorIs(t, err, failure.ErrCalendarObjectNotFound)
+	})
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"noda/data/types"
+	"noda/failure"
+	"noda/ical"
+	"noda/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarService publishes the tasks of a user as iCalendar feeds that
+// calendars can subscribe to. Calendars cannot sign in, so the feeds are
+// reached with a token of their own, which the user can replace or revoke.
+type CalendarService interface {
+	IssueFeedToken(userID uuid.UUID) (token string, err error)
+	RevokeFeedToken(userID uuid.UUID) error
+	RenderFeed(token string, listID uuid.UUID) (feed []byte, err error)
+}
+
+type calendarService struct {
+	r       repository.CalendarRepository
+	members repository.MemberRepository
+	users   repository.UserRepository
+}
+
+// NewCalendarService creates a CalendarService whose feeds are not found while
+// their owner is blocked or deleted.
+func NewCalendarService(r repository.CalendarRepository, members repository.MemberRepository, users repository.UserRepository) CalendarService {
+	return &calendarService{r: r, members: members, users: users}
+}
+
+// IssueFeedToken makes a new feed token for the user. The previous one, if
+// any, stops working.
+func (s *calendarService) IssueFeedToken(userID uuid.UUID) (token string, err error) {
+	if uuid.Nil == userID {
+		err = failure.NewNilParameterError("IssueFeedToken", "userID")
+		log.Println(err)
+		return "", err
+	}
+	token, err = generateOpaqueToken()
+	if nil != err {
+		log.Println(err)
+		return "", err
+	}
+	err = s.r.SaveFeedToken(userID.String(), hashOpaqueToken(token))
+	if nil != err {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *calendarService) RevokeFeedToken(userID uuid.UUID) error {
+	if uuid.Nil == userID {
+		var err = failure.NewNilParameterError("RevokeFeedToken", "userID")
+		log.Println(err)
+		return err
+	}
+	return s.r.DeleteFeedToken(userID.String())
+}
+
+// RenderFeed writes the feed of the owner of the token as a VCALENDAR with a
+// VTODO for each task that has a due date or a reminder. The feed has the
+// tasks of the given list, or of every list the owner can see if listID is
+// uuid.Nil. The feed is not found while the owner is blocked or deleted.
+func (s *calendarService) RenderFeed(token string, listID uuid.UUID) (feed []byte, err error) {
+	doTrim(&token)
+	if "" == token {
+		return nil, failure.ErrInvalidFeedToken
+	}
+	owner, err := s.r.FetchFeedOwner(hashOpaqueToken(token))
+	if nil != err {
+		return nil, err
+	}
+	ownerID, err := uuid.Parse(owner)
+	if nil != err {
+		log.Println(err)
+		return nil, err
+	}
+	status, err := s.users.FetchStatus(owner)
+	switch {
+	case errors.Is(err, failure.ErrUserNotFound):
+		return nil, failure.ErrFeedNotFound
+	case nil != err:
+		return nil, err
+	case status.Deleted(), status.BlockedAt(time.Now()):
+		return nil, failure.ErrFeedNotFound
+	}
+	var name, list = "Noda", ""
+	if uuid.Nil != listID {
+		_, err = authorizeList(s.members, ownerID, listID, types.MemberRoleViewer)
+		if nil != err {
+			return nil, err
+		}
+		list = listID.String()
+		name, err = s.r.FetchListName(owner, list)
+		if nil != err {
+			return nil, err
+		}
+	}
+	tasks, err := s.r.FetchTasks(owner, list)
+	if nil != err {
+		return nil, err
+	}
+	var calendar = ical.NewCalendar(name)
+	for _, task := range tasks {
+		calendar.Append(ical.NewTodo(task))
+	}
+	var buf bytes.Buffer
+	err = calendar.Encode(&buf)
+	if nil != err {
+		log.Println(err)
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
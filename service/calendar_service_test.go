@@ -0,0 +1,159 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendarService_IssueFeedToken(t *testing.T) {
+	defer beQuiet()()
+	var userID = uuid.New()
+
+	t.Run("stores the hash of a new token", func(t *testing.T) {
+		var r = mocks.NewCalendarRepositoryMock()
+		r.On("SaveFeedToken", userID.String(), mock.Anything).Return(nil)
+		token, err := NewCalendarService(r, nil, nil).IssueFeedToken(userID)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		r.AssertCalled(t, "SaveFeedToken", userID.String(), hashOpaqueToken(token))
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		var r = mocks.NewCalendarRepositoryMock()
+		r.On("SaveFeedToken", userID.String(), mock.Anything).Return(failure.ErrUserNoLongerExists)
+		token, err := NewCalendarService(r, nil, nil).IssueFeedToken(userID)
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Equal(t, "", token)
+	})
+}
+
+func TestCalendarService_RenderFeed(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, listID = uuid.New(), uuid.New()
+		token           = "q9Vb0WnA1yZ3lH8x"
+		hash            = hashOpaqueToken(token)
+		due             = time.Date(2024, 12, 31, 18, 0, 0, 0, time.UTC)
+		tasks           = []*model.Task{{
+			UUID:      uuid.New(),
+			ListUUID:  listID,
+			Title:     "Paint the fence",
+			Priority:  types.TaskPriorityHigh,
+			Status:    types.TaskStatusIncomplete,
+			DueDate:   &due,
+			CreatedAt: due.Add(-48 * time.Hour),
+			UpdatedAt: due.Add(-24 * time.Hour),
+		}}
+	)
+	var active = func() *mocks.UserRepository {
+		var users = mocks.NewUserRepositoryMock()
+		users.On("FetchStatus", ownerID.String()).Return(&model.UserStatus{}, nil)
+		return users
+	}
+
+	t.Run("every list", func(t *testing.T) {
+		var r = mocks.NewCalendarRepositoryMock()
+		r.On("FetchFeedOwner", hash).Return(ownerID.String(), nil)
+		r.On("FetchTasks", ownerID.String(), "").Return(tasks, nil)
+		feed, err := NewCalendarService(r, nil, active()).RenderFeed(" "+token+" ", uuid.Nil)
+		assert.NoError(t, err)
+		var text = string(feed)
+		assert.True(t, strings.HasPrefix(text, "BEGIN:VCALENDAR\r\n"))
+		assert.Contains(t, text, "\r\nX-WR-CALNAME:Noda\r\n")
+		assert.Contains(t, text, "\r\nBEGIN:VTODO\r\nUID:"+tasks[0].UUID.String()+"\r\n")
+		assert.Contains(t, text, "\r\nDUE:20241231T180000Z\r\nPRIORITY:3\r\nSTATUS:NEEDS-ACTION\r\n")
+		assert.True(t, strings.HasSuffix(text, "END:VTODO\r\nEND:VCALENDAR\r\n"))
+		r.AssertNotCalled(t, "FetchListName")
+	})
+
+	t.Run("one list", func(t *testing.T) {
+		var r = mocks.NewCalendarRepositoryMock()
+		r.On("FetchFeedOwner", hash).Return(ownerID.String(), nil)
+		r.On("FetchListName", ownerID.String(), listID.String()).Return("Garden", nil)
+		r.On("FetchTasks", ownerID.String(), listID.String()).Return(tasks, nil)
+		feed, err := NewCalendarService(r, soleOwner{}, active()).RenderFeed(token, listID)
+		assert.NoError(t, err)
+		assert.Contains(t, string(feed), "\r\nX-WR-CALNAME:Garden\r\n")
+	})
+
+	t.Run("list not shared with the owner", func(t *testing.T) {
+		var (
+			r       = mocks.NewCalendarRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		r.On("FetchFeedOwner", hash).Return(ownerID.String(), nil)
+		members.On("FetchListAccess", ownerID.String(), listID.String()).Return(nil, failure.ErrListNotFound)
+		feed, err := NewCalendarService(r, members, active()).RenderFeed(token, listID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, feed)
+		r.AssertNotCalled(t, "FetchTasks")
+	})
+
+	for name, status := range map[string]*model.UserStatus{
+		"blocked owner": {IsBlocked: true},
+		"deleted owner": {DeletedAt: &due},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var (
+				r     = mocks.NewCalendarRepositoryMock()
+				users = mocks.NewUserRepositoryMock()
+			)
+			r.On("FetchFeedOwner", hash).Return(ownerID.String(), nil)
+			users.On("FetchStatus", ownerID.String()).Return(status, nil)
+			feed, err := NewCalendarService(r, soleOwner{}, users).RenderFeed(token, uuid.Nil)
+			assert.ErrorIs(t, err, failure.ErrFeedNotFound)
+			assert.Nil(t, feed)
+			r.AssertNotCalled(t, "FetchTasks")
+		})
+	}
+
+	t.Run("owner no longer exists", func(t *testing.T) {
+		var (
+			r     = mocks.NewCalendarRepositoryMock()
+			users = mocks.NewUserRepositoryMock()
+		)
+		r.On("FetchFeedOwner", hash).Return(ownerID.String(), nil)
+		users.On("FetchStatus", ownerID.String()).Return(nil, failure.ErrUserNotFound)
+		feed, err := NewCalendarService(r, nil, users).RenderFeed(token, uuid.Nil)
+		assert.ErrorIs(t, err, failure.ErrFeedNotFound)
+		assert.Nil(t, feed)
+	})
+
+	t.Run("block that ended", func(t *testing.T) {
+		var (
+			r     = mocks.NewCalendarRepositoryMock()
+			users = mocks.NewUserRepositoryMock()
+			ended = time.Now().Add(-time.Hour)
+		)
+		r.On("FetchFeedOwner", hash).Return(ownerID.String(), nil)
+		users.On("FetchStatus", ownerID.String()).Return(&model.UserStatus{IsBlocked: true, BlockedUntil: &ended}, nil)
+		r.On("FetchTasks", ownerID.String(), "").Return(tasks, nil)
+		feed, err := NewCalendarService(r, nil, users).RenderFeed(token, uuid.Nil)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, feed)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		var r = mocks.NewCalendarRepositoryMock()
+		r.On("FetchFeedOwner", hash).Return("", failure.ErrInvalidFeedToken)
+		feed, err := NewCalendarService(r, nil, nil).RenderFeed(token, uuid.Nil)
+		assert.ErrorIs(t, err, failure.ErrInvalidFeedToken)
+		assert.Nil(t, feed)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		var r = mocks.NewCalendarRepositoryMock()
+		feed, err := NewCalendarService(r, nil, nil).RenderFeed("  ", uuid.Nil)
+		assert.ErrorIs(t, err, failure.ErrInvalidFeedToken)
+		assert.Nil(t, feed)
+		r.AssertNotCalled(t, "FetchFeedOwner")
+	})
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/notify"
+	"noda/repository"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// mentionPattern matches a mention of a user by its email, as in
+// "@jane@noda.com". The email must not be glued to a preceding word.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.+-])@([\w.+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+type CommentService interface {
+	Save(userID, listID, taskID uuid.UUID, creation *transfer.CommentCreation) (insertedID uuid.UUID, err error)
+	Fetch(userID, listID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Comment], err error)
+	Update(userID, listID, taskID, commentID uuid.UUID, update *transfer.CommentUpdate) (ok bool, err error)
+	Delete(userID, listID, taskID, commentID uuid.UUID) error
+	FetchActivity(userID, listID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Activity], err error)
+}
+
+type commentService struct {
+	r       repository.CommentRepository
+	tasks   repository.TaskRepository
+	users   repository.UserRepository
+	members repository.MemberRepository
+	inbox   notify.Inbox
+}
+
+func NewCommentService(
+	r repository.CommentRepository,
+	tasks repository.TaskRepository,
+	users repository.UserRepository,
+	members repository.MemberRepository,
+	inbox notify.Inbox,
+) CommentService {
+	return &commentService{r: r, tasks: tasks, users: users, members: members, inbox: inbox}
+}
+
+// Save comments on a task, or replies to one of its comments. Every member of
+// the list can comment, and the users mentioned in the comment are notified.
+func (s *commentService) Save(userID, listID, taskID uuid.UUID, creation *transfer.CommentCreation) (insertedID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Save", "userID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Save", "listID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Save", "taskID")
+		log.Println(err)
+		return uuid.Nil, err
+	case nil == creation:
+		err = failure.NewNilParameterError("Save", "creation")
+		log.Println(err)
+		return uuid.Nil, err
+	}
+	err = checkCommentContent(&creation.Content)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	var parentID string
+	if nil != creation.ParentUUID {
+		parentID = creation.ParentUUID.String()
+	}
+	var mentions = s.resolveMentions(userID, listID, creation.Content)
+	inserted, err := s.r.Save(access.OwnerUUID.String(), listID.String(), taskID.String(), userID.String(),
+		parentID, creation.Content, mentions)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	s.notify(access.OwnerUUID, listID, taskID, mentions, creation.Content)
+	return uuid.Parse(inserted)
+}
+
+func (s *commentService) Fetch(
+	userID, listID, taskID uuid.UUID,
+	pagination *types.Pagination,
+) (result *types.Result[model.Comment], err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Fetch", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Fetch", "listID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Fetch", "taskID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("Fetch", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	comments, err := s.r.Fetch(access.OwnerUUID.String(), listID.String(), taskID.String(), pagination.Page, pagination.RPP)
+	if nil != err {
+		return nil, err
+	}
+	return &types.Result[model.Comment]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(comments)),
+		Payload:   comments,
+	}, nil
+}
+
+// Update edits a comment. Only its author can edit it, and only the users who
+// were not mentioned before are notified.
+func (s *commentService) Update(
+	userID, listID, taskID, commentID uuid.UUID,
+	update *transfer.CommentUpdate,
+) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Update", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Update", "listID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Update", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == commentID:
+		err = failure.NewNilParameterError("Update", "commentID")
+		log.Println(err)
+		return false, err
+	case nil == update:
+		err = failure.NewNilParameterError("Update", "update")
+		log.Println(err)
+		return false, err
+	}
+	err = checkCommentContent(&update.Content)
+	if nil != err {
+		return false, err
+	}
+	access, comment, err := s.authorize(userID, listID, taskID, commentID)
+	if nil != err {
+		return false, err
+	}
+	var mentions = s.resolveMentions(userID, listID, update.Content)
+	ok, err = s.r.Update(access.OwnerUUID.String(), listID.String(), taskID.String(), commentID.String(),
+		update.Content, mentions)
+	if nil != err || !ok {
+		return ok, err
+	}
+	var newcomers = make([]string, 0)
+	for _, mention := range mentions {
+		if !slices.Contains(comment.Mentions, mention) {
+			newcomers = append(newcomers, mention)
+		}
+	}
+	s.notify(access.OwnerUUID, listID, taskID, newcomers, update.Content)
+	return true, nil
+}
+
+// Delete removes a comment along with its replies. Only its author can
+// remove it.
+func (s *commentService) Delete(userID, listID, taskID, commentID uuid.UUID) error {
+	switch {
+	case uuid.Nil == userID:
+		err := failure.NewNilParameterError("Delete", "userID")
+		log.Println(err)
+		return err
+	case uuid.Nil == listID:
+		err := failure.NewNilParameterError("Delete", "listID")
+		log.Println(err)
+		return err
+	case uuid.Nil == taskID:
+		err := failure.NewNilParameterError("Delete", "taskID")
+		log.Println(err)
+		return err
+	case uuid.Nil == commentID:
+		err := failure.NewNilParameterError("Delete", "commentID")
+		log.Println(err)
+		return err
+	}
+	access, _, err := s.authorize(userID, listID, taskID, commentID)
+	if nil != err {
+		return err
+	}
+	_, err = s.r.Remove(access.OwnerUUID.String(), listID.String(), taskID.String(), commentID.String())
+	return err
+}
+
+// FetchActivity retrieves the comments of a task merged with the changes made
+// to it, latest first.
+func (s *commentService) FetchActivity(
+	userID, listID, taskID uuid.UUID,
+	pagination *types.Pagination,
+) (result *types.Result[model.Activity], err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchActivity", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("FetchActivity", "listID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("FetchActivity", "taskID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("FetchActivity", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	activity, err := s.r.FetchActivity(access.OwnerUUID.String(), listID.String(), taskID.String(), pagination.Page, pagination.RPP)
+	if nil != err {
+		return nil, err
+	}
+	return &types.Result[model.Activity]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(activity)),
+		Payload:   activity,
+	}, nil
+}
+
+// authorize makes sure that the user can see the list and is the author of
+// the comment.
+func (s *commentService) authorize(userID, listID, taskID, commentID uuid.UUID) (*model.ListAccess, *model.Comment, error) {
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, nil, err
+	}
+	comment, err := s.r.FetchByID(access.OwnerUUID.String(), listID.String(), taskID.String(), commentID.String())
+	if nil != err {
+		return nil, nil, err
+	}
+	if userID != comment.AuthorUUID {
+		return nil, nil, failure.ErrNotAuthor
+	}
+	return access, comment, nil
+}
+
+// resolveMentions finds the users mentioned in the content who can see the
+// list, other than its author. Mentions of anyone else are left as plain text.
+func (s *commentService) resolveMentions(authorID, listID uuid.UUID, content string) (mentions []string) {
+	mentions = make([]string, 0)
+	for _, email := range extractMentions(content) {
+		user, err := s.users.FetchByEmail(email)
+		if nil != err || authorID == user.UUID {
+			continue
+		}
+		_, err = authorizeList(s.members, user.UUID, listID, types.MemberRoleViewer)
+		if nil != err {
+			continue
+		}
+		if !slices.Contains(mentions, user.UUID.String()) {
+			mentions = append(mentions, user.UUID.String())
+		}
+	}
+	return mentions
+}
+
+// notify lets the mentioned users know about the comment. A failure is logged
+// but does not undo the comment.
+func (s *commentService) notify(ownerID, listID, taskID uuid.UUID, mentions []string, content string) {
+	if 0 == len(mentions) {
+		return
+	}
+	task, err := s.tasks.FetchByID(ownerID.String(), listID.String(), taskID.String())
+	if nil != err {
+		log.Printf("could not notify mentions in task %s: %v", taskID, err)
+		return
+	}
+	for _, mention := range mentions {
+		_, err = s.inbox.Save(mention, taskID.String(), "Mentioned in a comment on: "+task.Title, content, time.Now())
+		if nil != err {
+			log.Printf("could not notify mention %s in task %s: %v", mention, taskID, err)
+		}
+	}
+}
+
+// extractMentions retrieves the distinct emails mentioned in the content, in
+// lower case.
+func extractMentions(content string) (emails []string) {
+	emails = make([]string, 0)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		var email = strings.ToLower(match[1])
+		if !slices.Contains(emails, email) {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+func checkCommentContent(content *string) error {
+	doTrim(content)
+	switch {
+	case "" == *content:
+		return errors.New("content cannot be an empty string") // must've been handled by validator
+	case 2048 < len(*content):
+		return failure.ErrTooLong.Clone().FormatDetails("content", "comment", 2048)
+	}
+	return nil
+}
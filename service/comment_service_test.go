@@ -0,0 +1,238 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"strings"
+	"testing"
+)
+
+func TestExtractMentions(t *testing.T) {
+	assert.Equal(t, []string{"jane@noda.com", "john.doe+work@noda.co.uk"},
+		extractMentions("@Jane@noda.com, can you and @john.doe+work@noda.co.uk check this? Thanks @jane@noda.com."))
+	assert.Equal(t, []string{}, extractMentions("Write to jane@noda.com or me@ home"))
+}
+
+func TestCommentService_Save(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		jane                   = &model.User{UUID: uuid.New(), Email: "jane@noda.com"}
+		res                    uuid.UUID
+		err                    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r        = mocks.NewCommentRepositoryMock()
+			tasks    = mocks.NewTaskRepositoryMock()
+			users    = mocks.NewUserRepositoryMock()
+			inbox    = mocks.NewNotificationRepositoryMock()
+			creation = &transfer.CommentCreation{Content: blankset + "Can you check this, @jane@noda.com and @ghost@noda.com?" + blankset}
+			content  = "Can you check this, @jane@noda.com and @ghost@noda.com?"
+			inserted = uuid.New()
+		)
+		users.On("FetchByEmail", "jane@noda.com").Return(jane, nil)
+		users.On("FetchByEmail", "ghost@noda.com").Return(nil, failure.ErrUserNotFound)
+		r.On("Save", userID.String(), listID.String(), taskID.String(), userID.String(), "", content, []string{jane.UUID.String()}).
+			Return(inserted.String(), nil)
+		tasks.On("FetchByID", userID.String(), listID.String(), taskID.String()).Return(&model.Task{Title: "Write the report"}, nil)
+		inbox.On("Save", jane.UUID.String(), taskID.String(), "Mentioned in a comment on: Write the report", content, mock.Anything).
+			Return(uuid.NewString(), nil)
+		res, err = NewCommentService(r, tasks, users, soleOwner{}, inbox).Save(userID, listID, taskID, creation)
+		assert.NoError(t, err)
+		assert.Equal(t, inserted, res)
+		inbox.AssertExpectations(t)
+	})
+
+	t.Run("replies without mentions", func(t *testing.T) {
+		var (
+			r        = mocks.NewCommentRepositoryMock()
+			inbox    = mocks.NewNotificationRepositoryMock()
+			parentID = uuid.New()
+			inserted = uuid.New()
+		)
+		r.On("Save", userID.String(), listID.String(), taskID.String(), userID.String(), parentID.String(), "Agreed.", []string{}).
+			Return(inserted.String(), nil)
+		res, err = NewCommentService(r, nil, nil, soleOwner{}, inbox).
+			Save(userID, listID, taskID, &transfer.CommentCreation{Content: "Agreed.", ParentUUID: &parentID})
+		assert.NoError(t, err)
+		assert.Equal(t, inserted, res)
+		inbox.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("mentions of users who cannot see the list are ignored", func(t *testing.T) {
+		var (
+			r       = mocks.NewCommentRepositoryMock()
+			users   = mocks.NewUserRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+			inbox   = mocks.NewNotificationRepositoryMock()
+		)
+		members.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: userID, Role: types.MemberRoleOwner}, nil)
+		members.On("FetchListAccess", jane.UUID.String(), listID.String()).Return(nil, failure.ErrListNotFound)
+		users.On("FetchByEmail", "jane@noda.com").Return(jane, nil)
+		r.On("Save", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, []string{}).
+			Return(uuid.NewString(), nil)
+		_, err = NewCommentService(r, nil, users, members, inbox).
+			Save(userID, listID, taskID, &transfer.CommentCreation{Content: "Thoughts, @jane@noda.com?"})
+		assert.NoError(t, err)
+		inbox.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("content too long", func(t *testing.T) {
+		var r = mocks.NewCommentRepositoryMock()
+		res, err = NewCommentService(r, nil, nil, soleOwner{}, nil).
+			Save(userID, listID, taskID, &transfer.CommentCreation{Content: strings.Repeat("x", 2049)})
+		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("content", "comment", 2048).Error())
+		assert.Equal(t, uuid.Nil, res)
+		r.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var r = mocks.NewCommentRepositoryMock()
+		_, err = NewCommentService(r, nil, nil, nil, nil).Save(uuid.Nil, listID, taskID, &transfer.CommentCreation{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "userID").Error())
+		_, err = NewCommentService(r, nil, nil, nil, nil).Save(userID, uuid.Nil, taskID, &transfer.CommentCreation{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "listID").Error())
+		_, err = NewCommentService(r, nil, nil, nil, nil).Save(userID, listID, uuid.Nil, &transfer.CommentCreation{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "taskID").Error())
+		_, err = NewCommentService(r, nil, nil, nil, nil).Save(userID, listID, taskID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "creation").Error())
+		r.AssertNotCalled(t, "Save")
+	})
+}
+
+func TestCommentService_Fetch(t *testing.T) {
+	defer beQuiet()()
+	var userID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r        = mocks.NewCommentRepositoryMock()
+			comments = []*model.Comment{{}, {}}
+		)
+		r.On("Fetch", userID.String(), listID.String(), taskID.String(), int64(1), int64(10)).Return(comments, nil)
+		res, err := NewCommentService(r, nil, nil, soleOwner{}, nil).Fetch(userID, listID, taskID, &types.Pagination{})
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.Comment]{Page: 1, RPP: 10, Retrieved: 2, Payload: comments}, res)
+	})
+
+	t.Run("list not shared with the user", func(t *testing.T) {
+		var (
+			r       = mocks.NewCommentRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", mock.Anything, mock.Anything).Return(nil, failure.ErrListNotFound)
+		res, err := NewCommentService(r, nil, nil, members, nil).Fetch(userID, listID, taskID, &types.Pagination{})
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+		r.AssertNotCalled(t, "Fetch")
+	})
+}
+
+func TestCommentService_Update(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID, taskID, commentID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		jane                              = &model.User{UUID: uuid.New(), Email: "jane@noda.com"}
+		john                              = &model.User{UUID: uuid.New(), Email: "john@noda.com"}
+	)
+
+	t.Run("only the new mentions are notified", func(t *testing.T) {
+		var (
+			r       = mocks.NewCommentRepositoryMock()
+			tasks   = mocks.NewTaskRepositoryMock()
+			users   = mocks.NewUserRepositoryMock()
+			inbox   = mocks.NewNotificationRepositoryMock()
+			content = "@jane@noda.com and @john@noda.com, thoughts?"
+			comment = &model.Comment{UUID: commentID, AuthorUUID: userID, Mentions: []string{jane.UUID.String()}}
+		)
+		users.On("FetchByEmail", "jane@noda.com").Return(jane, nil)
+		users.On("FetchByEmail", "john@noda.com").Return(john, nil)
+		r.On("FetchByID", userID.String(), listID.String(), taskID.String(), commentID.String()).Return(comment, nil)
+		r.On("Update", userID.String(), listID.String(), taskID.String(), commentID.String(), content,
+			[]string{jane.UUID.String(), john.UUID.String()}).Return(true, nil)
+		tasks.On("FetchByID", userID.String(), listID.String(), taskID.String()).Return(&model.Task{Title: "Write the report"}, nil)
+		inbox.On("Save", john.UUID.String(), taskID.String(), "Mentioned in a comment on: Write the report", content, mock.Anything).
+			Return(uuid.NewString(), nil)
+		res, err := NewCommentService(r, tasks, users, soleOwner{}, inbox).
+			Update(userID, listID, taskID, commentID, &transfer.CommentUpdate{Content: content})
+		assert.NoError(t, err)
+		assert.True(t, res)
+		inbox.AssertNumberOfCalls(t, "Save", 1)
+	})
+
+	t.Run("only the author can edit", func(t *testing.T) {
+		var r = mocks.NewCommentRepositoryMock()
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(&model.Comment{UUID: commentID, AuthorUUID: uuid.New()}, nil)
+		res, err := NewCommentService(r, nil, nil, soleOwner{}, nil).
+			Update(userID, listID, taskID, commentID, &transfer.CommentUpdate{Content: "Edited."})
+		assert.ErrorIs(t, err, failure.ErrNotAuthor)
+		assert.False(t, res)
+		r.AssertNotCalled(t, "Update")
+	})
+
+	t.Run("comment not found", func(t *testing.T) {
+		var r = mocks.NewCommentRepositoryMock()
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, failure.ErrCommentNotFound)
+		res, err := NewCommentService(r, nil, nil, soleOwner{}, nil).
+			Update(userID, listID, taskID, commentID, &transfer.CommentUpdate{Content: "Edited."})
+		assert.ErrorIs(t, err, failure.ErrCommentNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestCommentService_Delete(t *testing.T) {
+	defer beQuiet()()
+	var userID, listID, taskID, commentID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewCommentRepositoryMock()
+		r.On("FetchByID", userID.String(), listID.String(), taskID.String(), commentID.String()).
+			Return(&model.Comment{UUID: commentID, AuthorUUID: userID}, nil)
+		r.On("Remove", userID.String(), listID.String(), taskID.String(), commentID.String()).Return(true, nil)
+		err := NewCommentService(r, nil, nil, soleOwner{}, nil).Delete(userID, listID, taskID, commentID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("only the author can remove", func(t *testing.T) {
+		var r = mocks.NewCommentRepositoryMock()
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(&model.Comment{UUID: commentID, AuthorUUID: uuid.New()}, nil)
+		err := NewCommentService(r, nil, nil, soleOwner{}, nil).Delete(userID, listID, taskID, commentID)
+		assert.ErrorIs(t, err, failure.ErrNotAuthor)
+		r.AssertNotCalled(t, "Remove")
+	})
+}
+
+func TestCommentService_FetchActivity(t *testing.T) {
+	defer beQuiet()()
+	var userID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r        = mocks.NewCommentRepositoryMock()
+			activity = []*model.Activity{{Kind: types.ActivityKindCompleted}, {Kind: types.ActivityKindComment}}
+		)
+		r.On("FetchActivity", userID.String(), listID.String(), taskID.String(), int64(2), int64(5)).Return(activity, nil)
+		res, err := NewCommentService(r, nil, nil, soleOwner{}, nil).
+			FetchActivity(userID, listID, taskID, &types.Pagination{Page: 2, RPP: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.Activity]{Page: 2, RPP: 5, Retrieved: 2, Payload: activity}, res)
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var r = mocks.NewCommentRepositoryMock()
+		_, err := NewCommentService(r, nil, nil, nil, nil).FetchActivity(userID, listID, taskID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("FetchActivity", "pagination").Error())
+		r.AssertNotCalled(t, "FetchActivity")
+	})
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"log"
+	"noda/repository"
+	"sync"
+	"time"
+)
+
+// Denylist keeps the identifiers of revoked sessions until the moment their
+// access tokens would have expired by themselves, after which there is no need
+// to remember them anymore.
+type Denylist interface {
+	Add(id string, until time.Time) error
+	Contains(id string) (bool, error)
+}
+
+// denylistSweepInterval is how often the expired entries are evicted from the
+// cache. Contains ignores them in between.
+const denylistSweepInterval = time.Minute
+
+// denylistAllowanceTTL bounds how long a session logged out through another
+// instance of the server can still be used on this one.
+const denylistAllowanceTTL = 5 * time.Second
+
+type denylist struct {
+	tokens  repository.TokenRepository
+	mu      sync.Mutex
+	entries map[string]time.Time
+	allowed map[string]time.Time
+	swept   time.Time
+	now     func() time.Time
+}
+
+// NewDenylist creates a Denylist stored along the refresh tokens, so that a
+// session logged out on an instance of the server is denied on every other,
+// and after a restart. The sessions known to be denied are cached, since a
+// denial is never lifted before it expires, and so are the ones known not to
+// be for denylistAllowanceTTL, so that every request does not query the
+// database.
+func NewDenylist(tokens repository.TokenRepository) Denylist {
+	return &denylist{
+		tokens:  tokens,
+		entries: make(map[string]time.Time),
+		allowed: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (d *denylist) Add(id string, until time.Time) error {
+	if !d.now().Before(until) {
+		return nil
+	}
+	err := d.tokens.DenySession(id, until)
+	if nil != err {
+		return err
+	}
+	d.remember(id, until)
+	return nil
+}
+
+func (d *denylist) Contains(id string) (bool, error) {
+	denied, known := d.recalls(id)
+	if known {
+		return denied, nil
+	}
+	until, err := d.tokens.FetchSessionDenial(id)
+	if nil != err {
+		log.Println(err)
+		return false, err
+	}
+	if nil == until || !d.now().Before(*until) {
+		d.allow(id)
+		return false, nil
+	}
+	d.remember(id, *until)
+	return true, nil
+}
+
+// remember caches a denial, evicting the expired entries once in a while.
+func (d *denylist) remember(id string, until time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sweep()
+	if current, ok := d.entries[id]; !ok || current.Before(until) {
+		d.entries[id] = until
+	}
+}
+
+// allow caches that a session is not denied for denylistAllowanceTTL.
+func (d *denylist) allow(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sweep()
+	d.allowed[id] = d.now().Add(denylistAllowanceTTL)
+}
+
+// sweep evicts the expired entries if it has not done so for
+// denylistSweepInterval. d.mu must be held.
+func (d *denylist) sweep() {
+	var now = d.now()
+	if now.Sub(d.swept) < denylistSweepInterval {
+		return
+	}
+	for _, entries := range []map[string]time.Time{d.entries, d.allowed} {
+		for key, expires := range entries {
+			if !now.Before(expires) {
+				delete(entries, key)
+			}
+		}
+	}
+	d.swept = now
+}
+
+// recalls tells whether the session is denied, if it is cached that it is or
+// that it is not. A cached denial prevails, since it is never lifted.
+func (d *denylist) recalls(id string) (denied, known bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var now = d.now()
+	if until, ok := d.entries[id]; ok {
+		if now.Before(until) {
+			return true, true
+		}
+		delete(d.entries, id)
+	}
+	if expires, ok := d.allowed[id]; ok {
+		if now.Before(expires) {
+			return false, true
+		}
+		delete(d.allowed, id)
+	}
+	return false, false
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/mocks"
+	"testing"
+	"time"
+)
+
+func TestDenylist(t *testing.T) {
+	defer beQuiet()()
+	var (
+		now    = time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+		tokens = mocks.NewTokenRepositoryMock()
+		d      = NewDenylist(tokens).(*denylist)
+	)
+	d.now = func() time.Time { return now }
+
+	t.Run("contains an entry until it expires", func(t *testing.T) {
+		tokens.On("DenySession", "a", now.Add(time.Hour)).Return(nil).Once()
+		tokens.On("FetchSessionDenial", "b").Return(nil, nil).Once()
+		assert.NoError(t, d.Add("a", now.Add(time.Hour)))
+		denied, err := d.Contains("a")
+		assert.NoError(t, err)
+		assert.True(t, denied)
+		denied, _ = d.Contains("b")
+		assert.False(t, denied)
+		now = now.Add(time.Hour)
+		var expired = now
+		tokens.On("FetchSessionDenial", "a").Return(&expired, nil).Once()
+		denied, _ = d.Contains("a")
+		assert.False(t, denied)
+		assert.NotContains(t, d.entries, "a")
+	})
+
+	t.Run("ignores entries that already expired", func(t *testing.T) {
+		assert.NoError(t, d.Add("c", now.Add(-time.Second)))
+		tokens.AssertNotCalled(t, "DenySession", "c", mock.Anything)
+		assert.NotContains(t, d.entries, "c")
+	})
+
+	t.Run("finds the entries added by another instance", func(t *testing.T) {
+		var until = now.Add(time.Hour)
+		tokens.On("FetchSessionDenial", "d").Return(&until, nil).Once()
+		denied, err := d.Contains("d")
+		assert.NoError(t, err)
+		assert.True(t, denied)
+		denied, _ = d.Contains("d")
+		assert.True(t, denied)
+		tokens.AssertNumberOfCalls(t, "FetchSessionDenial", 3)
+	})
+
+	t.Run("sweeps expired entries on add", func(t *testing.T) {
+		tokens.On("DenySession", "e", mock.Anything).Return(nil).Once()
+		tokens.On("DenySession", "f", mock.Anything).Return(nil).Once()
+		assert.NoError(t, d.Add("e", now.Add(time.Minute)))
+		now = now.Add(2 * time.Hour)
+		assert.NoError(t, d.Add("f", now.Add(time.Minute)))
+		assert.NotContains(t, d.entries, "d")
+		assert.NotContains(t, d.entries, "e")
+		assert.Contains(t, d.entries, "f")
+	})
+
+	t.Run("caches for a while that a session is not denied", func(t *testing.T) {
+		tokens.On("FetchSessionDenial", "h").Return(nil, nil).Twice()
+		for i := 0; i < 3; i++ {
+			denied, err := d.Contains("h")
+			assert.NoError(t, err)
+			assert.False(t, denied)
+		}
+		tokens.AssertNumberOfCalls(t, "FetchSessionDenial", 4)
+		now = now.Add(denylistAllowanceTTL)
+		denied, _ := d.Contains("h")
+		assert.False(t, denied)
+		tokens.AssertNumberOfCalls(t, "FetchSessionDenial", 5)
+	})
+
+	t.Run("denies at once a session logged out on this instance", func(t *testing.T) {
+		tokens.On("FetchSessionDenial", "i").Return(nil, nil).Once()
+		tokens.On("DenySession", "i", mock.Anything).Return(nil).Once()
+		denied, _ := d.Contains("i")
+		assert.False(t, denied)
+		assert.NoError(t, d.Add("i", now.Add(time.Minute)))
+		denied, _ = d.Contains("i")
+		assert.True(t, denied)
+	})
+
+	t.Run("got token repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		tokens.On("DenySession", "g", mock.Anything).Return(unexpected).Once()
+		tokens.On("FetchSessionDenial", "g").Return(nil, unexpected).Once()
+		assert.ErrorIs(t, d.Add("g", now.Add(time.Minute)), unexpected)
+		assert.NotContains(t, d.entries, "g")
+		_, err := d.Contains("g")
+		assert.ErrorIs(t, err, unexpected)
+	})
+}
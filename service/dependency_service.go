@@ -0,0 +1,209 @@
+package service
+
+import (
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+
+	"github.com/google/uuid"
+)
+
+// DependencyService links the tasks of a user that must be done in order. A
+// task is blocked by another one until that other one is finished.
+type DependencyService interface {
+	Save(ownerID, taskID, blockerID uuid.UUID) (ok bool, err error)
+	Remove(ownerID, taskID, blockerID uuid.UUID) (ok bool, err error)
+	FetchGraph(ownerID, taskID uuid.UUID) (graph *model.DependencyGraph, err error)
+}
+
+type dependencyService struct {
+	r repository.DependencyRepository
+}
+
+func NewDependencyService(r repository.DependencyRepository) DependencyService {
+	return &dependencyService{r}
+}
+
+// Save makes the task blocked by the blocker, unless the blocker is already
+// blocked by the task, directly or through other tasks, which the repository
+// checks atomically with the saving.
+func (s *dependencyService) Save(ownerID, taskID, blockerID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Save", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Save", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == blockerID:
+		err = failure.NewNilParameterError("Save", "blockerID")
+		log.Println(err)
+		return false, err
+	case taskID == blockerID:
+		return false, failure.ErrDependencyCycle
+	}
+	return s.r.Save(ownerID.String(), taskID.String(), blockerID.String())
+}
+
+func (s *dependencyService) Remove(ownerID, taskID, blockerID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Remove", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Remove", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == blockerID:
+		err = failure.NewNilParameterError("Remove", "blockerID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Remove(ownerID.String(), taskID.String(), blockerID.String())
+}
+
+// FetchGraph retrieves the tasks linked to the task through dependencies, in
+// an order they can be done in, along with the unfinished ones that are not
+// blocked anymore and can be worked on now. The tasks that cannot be ordered
+// because of a cycle, which Save never makes, are reported apart.
+func (s *dependencyService) FetchGraph(ownerID, taskID uuid.UUID) (graph *model.DependencyGraph, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("FetchGraph", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("FetchGraph", "taskID")
+		log.Println(err)
+		return nil, err
+	}
+	nodes, err := s.r.FetchGraph(ownerID.String(), taskID.String())
+	if nil != err {
+		return nil, err
+	}
+	graph = &model.DependencyGraph{
+		TaskUUID: taskID,
+		Nodes:    nodes,
+		Ready:    make([]uuid.UUID, 0),
+	}
+	graph.Order, graph.Cyclic = sortTopologically(nodes)
+	if 0 < len(graph.Cyclic) {
+		log.Printf("dependency graph of task %q has a cycle through %v", taskID, graph.Cyclic)
+	}
+	var status = make(map[uuid.UUID]types.TaskStatus, len(nodes))
+	for _, node := range nodes {
+		status[node.TaskUUID] = node.Status
+	}
+	for _, node := range nodes {
+		if types.TaskStatusComplete != node.Status && 0 == countOpenBlockers(node, status) {
+			graph.Ready = append(graph.Ready, node.TaskUUID)
+		}
+	}
+	return graph, nil
+}
+
+// sortTopologically orders the tasks of the graph so that every task comes
+// after its blockers. Tasks that do not depend on each other keep the order of
+// the graph. The tasks that cannot be ordered, because they are in a cycle or
+// blocked by one, are left out of the order and returned as cyclic, in the
+// order of the graph.
+func sortTopologically(nodes []*model.DependencyNode) (order, cyclic []uuid.UUID) {
+	var (
+		inGraph   = make(map[uuid.UUID]bool, len(nodes))
+		remaining = make(map[uuid.UUID]int, len(nodes))
+		blocks    = make(map[uuid.UUID][]uuid.UUID, len(nodes))
+	)
+	for _, node := range nodes {
+		inGraph[node.TaskUUID] = true
+	}
+	for _, node := range nodes {
+		for _, blocker := range node.BlockedBy {
+			if inGraph[blocker] {
+				remaining[node.TaskUUID]++
+				blocks[blocker] = append(blocks[blocker], node.TaskUUID)
+			}
+		}
+	}
+	order = make([]uuid.UUID, 0, len(nodes))
+	var done = make(map[uuid.UUID]bool, len(nodes))
+	for len(order) < len(nodes) {
+		var progressed = false
+		for _, node := range nodes {
+			if done[node.TaskUUID] || 0 < remaining[node.TaskUUID] {
+				continue
+			}
+			done[node.TaskUUID], progressed = true, true
+			order = append(order, node.TaskUUID)
+			for _, blocked := range blocks[node.TaskUUID] {
+				remaining[blocked]--
+			}
+			break
+		}
+		if !progressed {
+			break
+		}
+	}
+	cyclic = make([]uuid.UUID, 0)
+	for _, node := range nodes {
+		if !done[node.TaskUUID] {
+			cyclic = append(cyclic, node.TaskUUID)
+		}
+	}
+	return order, cyclic
+}
+
+// countOpenBlockers counts the blockers of the node that are not finished.
+func countOpenBlockers(node *model.DependencyNode, status map[uuid.UUID]types.TaskStatus) (open int) {
+	for _, blocker := range node.BlockedBy {
+		if types.TaskStatusComplete != status[blocker] {
+			open++
+		}
+	}
+	return open
+}
+
+// blockableTaskService refuses to complete the tasks that are blocked through
+// the TaskService it wraps.
+type blockableTaskService struct {
+	TaskService
+	dependencies repository.DependencyRepository
+	members      repository.MemberRepository
+}
+
+// NewBlockableTaskService wraps a TaskService so that it refuses to complete a
+// task while any of its blockers is not finished.
+func NewBlockableTaskService(next TaskService, dependencies repository.DependencyRepository, members repository.MemberRepository) TaskService {
+	return &blockableTaskService{TaskService: next, dependencies: dependencies, members: members}
+}
+
+func (t *blockableTaskService) Complete(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	if uuid.Nil == ownerID || uuid.Nil == listID || uuid.Nil == taskID {
+		return t.TaskService.Complete(ownerID, listID, taskID)
+	}
+	access, err := authorizeList(t.members, ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	nodes, err := t.dependencies.FetchGraph(access.OwnerUUID.String(), taskID.String())
+	if nil != err {
+		return false, err
+	}
+	var status = make(map[uuid.UUID]types.TaskStatus, len(nodes))
+	for _, node := range nodes {
+		status[node.TaskUUID] = node.Status
+	}
+	for _, node := range nodes {
+		if node.TaskUUID != taskID {
+			continue
+		}
+		if open := countOpenBlockers(node, status); 0 < open {
+			return false, failure.ErrTaskBlocked.Clone().FormatDetails(open)
+		}
+	}
+	return t.TaskService.Complete(ownerID, listID, taskID)
+}
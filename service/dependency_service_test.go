@@ -0,0 +1,146 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+)
+
+// chain makes a graph where design blocks build, which blocks release, and docs
+// blocks nothing.
+func chain() (design, build, release, docs *model.DependencyNode) {
+	design = &model.DependencyNode{TaskUUID: uuid.New(), Title: "Design", Status: types.TaskStatusComplete, BlockedBy: []uuid.UUID{}}
+	build = &model.DependencyNode{TaskUUID: uuid.New(), Title: "Build", Status: types.TaskStatusIncomplete, BlockedBy: []uuid.UUID{design.TaskUUID}}
+	release = &model.DependencyNode{TaskUUID: uuid.New(), Title: "Release", Status: types.TaskStatusIncomplete, BlockedBy: []uuid.UUID{build.TaskUUID}}
+	docs = &model.DependencyNode{TaskUUID: uuid.New(), Title: "Docs", Status: types.TaskStatusIncomplete, BlockedBy: []uuid.UUID{}}
+	return design, build, release, docs
+}
+
+func TestDependencyService_Save(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID                   = uuid.New()
+		design, build, release, _ = chain()
+		ok                        bool
+		err                       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewDependencyRepositoryMock()
+		var docsID = uuid.New()
+		r.On("Save", ownerID.String(), docsID.String(), release.TaskUUID.String()).Return(true, nil)
+		ok, err = NewDependencyService(r).Save(ownerID, docsID, release.TaskUUID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("refuses cycles", func(t *testing.T) {
+		var r = mocks.NewDependencyRepositoryMock()
+		r.On("Save", ownerID.String(), design.TaskUUID.String(), release.TaskUUID.String()).Return(false, failure.ErrDependencyCycle)
+		ok, err = NewDependencyService(r).Save(ownerID, design.TaskUUID, release.TaskUUID)
+		assert.ErrorIs(t, err, failure.ErrDependencyCycle)
+		assert.False(t, ok)
+	})
+
+	t.Run("refuses a task blocking itself", func(t *testing.T) {
+		var r = mocks.NewDependencyRepositoryMock()
+		ok, err = NewDependencyService(r).Save(ownerID, build.TaskUUID, build.TaskUUID)
+		assert.ErrorIs(t, err, failure.ErrDependencyCycle)
+		assert.False(t, ok)
+		r.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("blocker not found", func(t *testing.T) {
+		var (
+			r     = mocks.NewDependencyRepositoryMock()
+			ghost = uuid.New()
+		)
+		r.On("Save", ownerID.String(), build.TaskUUID.String(), ghost.String()).Return(false, failure.ErrTaskNotFound)
+		ok, err = NewDependencyService(r).Save(ownerID, build.TaskUUID, ghost)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.False(t, ok)
+	})
+
+	t.Run("nil blocker", func(t *testing.T) {
+		ok, err = NewDependencyService(nil).Save(ownerID, build.TaskUUID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "blockerID").Error())
+		assert.False(t, ok)
+	})
+}
+
+func TestDependencyService_FetchGraph(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID                      = uuid.New()
+		design, build, release, docs = chain()
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewDependencyRepositoryMock()
+		r.On("FetchGraph", ownerID.String(), build.TaskUUID.String()).
+			Return([]*model.DependencyNode{release, docs, build, design}, nil)
+		res, err := NewDependencyService(r).FetchGraph(ownerID, build.TaskUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, build.TaskUUID, res.TaskUUID)
+		assert.Equal(t, []uuid.UUID{docs.TaskUUID, design.TaskUUID, build.TaskUUID, release.TaskUUID}, res.Order)
+		assert.Empty(t, res.Cyclic)
+		assert.Equal(t, []uuid.UUID{docs.TaskUUID, build.TaskUUID}, res.Ready)
+	})
+
+	t.Run("reports a cycle", func(t *testing.T) {
+		var r = mocks.NewDependencyRepositoryMock()
+		var looped = *design
+		looped.BlockedBy = []uuid.UUID{release.TaskUUID}
+		r.On("FetchGraph", ownerID.String(), build.TaskUUID.String()).
+			Return([]*model.DependencyNode{docs, &looped, build, release}, nil)
+		res, err := NewDependencyService(r).FetchGraph(ownerID, build.TaskUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{docs.TaskUUID}, res.Order)
+		assert.Equal(t, []uuid.UUID{design.TaskUUID, build.TaskUUID, release.TaskUUID}, res.Cyclic)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewDependencyRepositoryMock()
+		r.On("FetchGraph", ownerID.String(), build.TaskUUID.String()).Return(nil, failure.ErrTaskNotFound)
+		res, err := NewDependencyService(r).FetchGraph(ownerID, build.TaskUUID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestBlockableTaskService_Complete(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID            = uuid.New(), uuid.New()
+		design, build, release, _ = chain()
+		graph                     = []*model.DependencyNode{design, build, release}
+	)
+
+	t.Run("blockers are finished", func(t *testing.T) {
+		var (
+			next         = mocks.NewTaskServiceMock()
+			dependencies = mocks.NewDependencyRepositoryMock()
+		)
+		dependencies.On("FetchGraph", userID.String(), build.TaskUUID.String()).Return(graph, nil)
+		next.On("Complete", userID, listID, build.TaskUUID).Return(true, nil)
+		ok, err := NewBlockableTaskService(next, dependencies, soleOwner{}).Complete(userID, listID, build.TaskUUID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("blocked", func(t *testing.T) {
+		var (
+			next         = mocks.NewTaskServiceMock()
+			dependencies = mocks.NewDependencyRepositoryMock()
+		)
+		dependencies.On("FetchGraph", userID.String(), release.TaskUUID.String()).Return(graph, nil)
+		ok, err := NewBlockableTaskService(next, dependencies, soleOwner{}).Complete(userID, listID, release.TaskUUID)
+		assert.ErrorContains(t, err, failure.ErrTaskBlocked.Clone().FormatDetails(1).Error())
+		assert.False(t, ok)
+		next.AssertNotCalled(t, "Complete")
+	})
+}
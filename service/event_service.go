@@ -0,0 +1,462 @@
+package service
+
+import (
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/events"
+	"noda/failure"
+	"noda/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventService hands the changes made to the tasks, the lists and the groups
+// to the users who can see them, as they happen.
+type EventService interface {
+	Subscribe(userID uuid.UUID, lastEventID *uint64) (subscription *events.Subscription, missed []*model.Event, err error)
+	Unsubscribe(subscription *events.Subscription)
+	Recheck(userID, sessionID uuid.UUID) error
+}
+
+type eventService struct {
+	broker   *events.Broker
+	denylist Denylist
+	users    UserService
+}
+
+// NewEventService creates an EventService whose streams are rechecked against
+// the sessions in denylist and the statuses of the users.
+func NewEventService(broker *events.Broker, denylist Denylist, users UserService) EventService {
+	return &eventService{broker: broker, denylist: denylist, users: users}
+}
+
+// Subscribe starts handing the events the user can see. With lastEventID, the
+// events that came after it are in missed, or a reset event when some of them
+// are no longer known, so that everything must be retrieved again.
+func (s *eventService) Subscribe(userID uuid.UUID, lastEventID *uint64) (subscription *events.Subscription, missed []*model.Event, err error) {
+	if uuid.Nil == userID {
+		err = failure.NewNilParameterError("Subscribe", "userID")
+		log.Println(err)
+		return nil, nil, err
+	}
+	subscription, missed = s.broker.Subscribe(userID, lastEventID)
+	return subscription, missed, nil
+}
+
+func (s *eventService) Unsubscribe(subscription *events.Subscription) {
+	s.broker.Unsubscribe(subscription)
+}
+
+// Recheck makes sure that the session a stream was opened with still holds,
+// since a stream outlives the check of its request: the session was not
+// logged out, and its user still exists and is not blocked. It fails with a
+// failure.Error when the session no longer holds, and with any other error
+// when it cannot tell.
+func (s *eventService) Recheck(userID, sessionID uuid.UUID) error {
+	denied, err := s.denylist.Contains(sessionID.String())
+	if nil != err {
+		return err
+	}
+	if denied {
+		return failure.ErrRevokedToken
+	}
+	_, err = s.users.AssertActive(userID)
+	return err
+}
+
+// membersPageSize is how many members are retrieved at once to find who can
+// see an event.
+const membersPageSize = 100
+
+// NewEventAudience returns the audience of the events of a broker: the owner
+// and the members of the list of the event, and those of its group.
+func NewEventAudience(members repository.MemberRepository) events.Audience {
+	return func(event *model.Event) ([]uuid.UUID, error) {
+		return eventAudience(members, event)
+	}
+}
+
+func eventAudience(members repository.MemberRepository, event *model.Event) (audience []uuid.UUID, err error) {
+	var actorID, groupID = event.ActorUUID.String(), event.GroupUUID
+	audience = make([]uuid.UUID, 0)
+	if nil != event.ListUUID {
+		access, err := members.FetchListAccess(actorID, event.ListUUID.String())
+		if nil != err {
+			return nil, err
+		}
+		shared, err := fetchEveryMember(members, types.ShareKindList, event.ListUUID.String())
+		if nil != err {
+			return nil, err
+		}
+		audience = append(append(audience, access.OwnerUUID), shared...)
+		if nil == groupID {
+			groupID = access.GroupUUID
+		}
+	}
+	if nil != groupID {
+		access, err := members.FetchGroupAccess(actorID, groupID.String())
+		if nil != err {
+			return nil, err
+		}
+		shared, err := fetchEveryMember(members, types.ShareKindGroup, groupID.String())
+		if nil != err {
+			return nil, err
+		}
+		audience = append(append(audience, access.OwnerUUID), shared...)
+	}
+	return audience, nil
+}
+
+func fetchEveryMember(members repository.MemberRepository, kind types.ShareKind, targetID string) (userIDs []uuid.UUID, err error) {
+	userIDs = make([]uuid.UUID, 0)
+	for page := int64(1); ; page++ {
+		fetched, err := members.FetchMembers(kind, targetID, page, membersPageSize)
+		if nil != err {
+			return nil, err
+		}
+		for _, member := range fetched {
+			userIDs = append(userIDs, member.UserUUID)
+		}
+		if len(fetched) < membersPageSize {
+			return userIDs, nil
+		}
+	}
+}
+
+// newEvent makes an event of what the actor did; the nil UUIDs among the ones
+// of the group, the list and the task are left out.
+func newEvent(kind types.EventKind, actorID, groupID, listID, taskID uuid.UUID) *model.Event {
+	var event = &model.Event{Kind: kind, ActorUUID: actorID, OccurredAt: time.Now().UTC()}
+	if uuid.Nil != groupID {
+		event.GroupUUID = &groupID
+	}
+	if uuid.Nil != listID {
+		event.ListUUID = &listID
+	}
+	if uuid.Nil != taskID {
+		event.TaskUUID = &taskID
+	}
+	return event
+}
+
+// publishOnRemoval publishes the event of something about to be removed with
+// its audience, found while it can still be, once remove succeeds.
+func publishOnRemoval(publisher events.Publisher, members repository.MemberRepository, event *model.Event, remove func() (bool, error)) (ok bool, err error) {
+	audience, err := eventAudience(members, event)
+	if nil != err {
+		log.Printf("could not find who can see event %q: %v", event.Kind, err)
+	}
+	ok, err = remove()
+	if nil == err && ok {
+		event.Audience = audience
+		publisher.Publish(event)
+	}
+	return ok, err
+}
+
+// observableTaskService publishes an event for every change made to a task
+// through the TaskService it wraps.
+type observableTaskService struct {
+	TaskService
+	publisher events.Publisher
+	members   repository.MemberRepository
+}
+
+// NewObservableTaskService wraps a TaskService so that its changes to the
+// tasks are published. A task moved out of a list is told to those who could
+// see it there, as well as to those of the list it goes to.
+func NewObservableTaskService(next TaskService, publisher events.Publisher, members repository.MemberRepository) TaskService {
+	return &observableTaskService{TaskService: next, publisher: publisher, members: members}
+}
+
+// publish publishes the event of a change once it is made.
+func (t *observableTaskService) publish(kind types.EventKind, actorID, listID, taskID uuid.UUID, change func() (bool, error)) (ok bool, err error) {
+	ok, err = change()
+	if nil == err && ok {
+		t.publisher.Publish(newEvent(kind, actorID, uuid.Nil, listID, taskID))
+	}
+	return ok, err
+}
+
+// publishMove publishes the move of a task out of its list, to those who could
+// see it there, found before move, and to those of targetListID when it is not
+// uuid.Nil.
+func (t *observableTaskService) publishMove(actorID, taskID, targetListID uuid.UUID, move func() (bool, error)) (ok bool, err error) {
+	var left *model.Event
+	sourceListID, err := t.TaskService.Locate(actorID, taskID)
+	if nil != err {
+		log.Printf("could not find the list task %q leaves: %v", taskID, err)
+	} else if sourceListID != targetListID {
+		left = newEvent(types.EventKindTaskMoved, actorID, uuid.Nil, sourceListID, taskID)
+		left.Audience, err = eventAudience(t.members, left)
+		if nil != err {
+			log.Printf("could not find who can see event %q: %v", left.Kind, err)
+		}
+	}
+	ok, err = move()
+	if nil != err || !ok {
+		return ok, err
+	}
+	if nil != left {
+		t.publisher.Publish(left)
+	}
+	if uuid.Nil != targetListID {
+		t.publisher.Publish(newEvent(types.EventKindTaskMoved, actorID, uuid.Nil, targetListID, taskID))
+	}
+	return ok, err
+}
+
+func (t *observableTaskService) Save(ownerID, listID uuid.UUID, creation *transfer.TaskCreation) (insertedID uuid.UUID, err error) {
+	insertedID, err = t.TaskService.Save(ownerID, listID, creation)
+	if nil == err {
+		t.publisher.Publish(newEvent(types.EventKindTaskCreated, ownerID, uuid.Nil, listID, insertedID))
+	}
+	return insertedID, err
+}
+
+func (t *observableTaskService) Duplicate(ownerID, taskID uuid.UUID) (replicaID uuid.UUID, err error) {
+	replicaID, err = t.TaskService.Duplicate(ownerID, taskID)
+	if nil == err {
+		t.publisher.Publish(newEvent(types.EventKindTaskCreated, ownerID, uuid.Nil, uuid.Nil, replicaID))
+	}
+	return replicaID, err
+}
+
+func (t *observableTaskService) Update(ownerID, listID, taskID uuid.UUID, update *transfer.TaskUpdate) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.Update(ownerID, listID, taskID, update)
+	})
+}
+
+func (t *observableTaskService) Reorder(ownerID, listID, taskID uuid.UUID, position uint64) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.Reorder(ownerID, listID, taskID, position)
+	})
+}
+
+func (t *observableTaskService) SetReminder(ownerID, listID, taskID uuid.UUID, remindAt time.Time) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.SetReminder(ownerID, listID, taskID, remindAt)
+	})
+}
+
+func (t *observableTaskService) SetPriority(ownerID, listID, taskID uuid.UUID, priority types.TaskPriority) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.SetPriority(ownerID, listID, taskID, priority)
+	})
+}
+
+func (t *observableTaskService) SetDueDate(ownerID, listID, taskID uuid.UUID, dueDate time.Time) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.SetDueDate(ownerID, listID, taskID, dueDate)
+	})
+}
+
+func (t *observableTaskService) SetRecurrence(ownerID, listID, taskID uuid.UUID, rule string) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.SetRecurrence(ownerID, listID, taskID, rule)
+	})
+}
+
+func (t *observableTaskService) RemoveRecurrence(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.RemoveRecurrence(ownerID, listID, taskID)
+	})
+}
+
+func (t *observableTaskService) Complete(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publish(types.EventKindTaskCompleted, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.Complete(ownerID, listID, taskID)
+	})
+}
+
+func (t *observableTaskService) Resume(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publish(types.EventKindTaskResumed, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.Resume(ownerID, listID, taskID)
+	})
+}
+
+func (t *observableTaskService) Pin(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.Pin(ownerID, listID, taskID)
+	})
+}
+
+func (t *observableTaskService) Unpin(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publish(types.EventKindTaskUpdated, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.Unpin(ownerID, listID, taskID)
+	})
+}
+
+func (t *observableTaskService) Move(ownerID, taskID, targetListID uuid.UUID) (ok bool, err error) {
+	return t.publishMove(ownerID, taskID, targetListID, func() (bool, error) {
+		return t.TaskService.Move(ownerID, taskID, targetListID)
+	})
+}
+
+func (t *observableTaskService) Today(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publishMove(ownerID, taskID, uuid.Nil, func() (bool, error) {
+		return t.TaskService.Today(ownerID, taskID)
+	})
+}
+
+func (t *observableTaskService) Tomorrow(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publishMove(ownerID, taskID, uuid.Nil, func() (bool, error) {
+		return t.TaskService.Tomorrow(ownerID, taskID)
+	})
+}
+
+func (t *observableTaskService) Defer(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publishMove(ownerID, taskID, uuid.Nil, func() (bool, error) {
+		return t.TaskService.Defer(ownerID, taskID)
+	})
+}
+
+func (t *observableTaskService) Trash(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publish(types.EventKindTaskTrashed, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.Trash(ownerID, listID, taskID)
+	})
+}
+
+func (t *observableTaskService) RestoreFromTrash(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.publish(types.EventKindTaskRestored, ownerID, listID, taskID, func() (bool, error) {
+		return t.TaskService.RestoreFromTrash(ownerID, listID, taskID)
+	})
+}
+
+func (t *observableTaskService) Delete(ownerID, listID, taskID uuid.UUID) error {
+	var err = t.TaskService.Delete(ownerID, listID, taskID)
+	if nil == err {
+		t.publisher.Publish(newEvent(types.EventKindTaskRemoved, ownerID, uuid.Nil, listID, taskID))
+	}
+	return err
+}
+
+// observableRevisionService publishes an event for every task restored to a
+// revision through the RevisionService it wraps.
+type observableRevisionService struct {
+	RevisionService
+	publisher events.Publisher
+}
+
+func NewObservableRevisionService(next RevisionService, publisher events.Publisher) RevisionService {
+	return &observableRevisionService{RevisionService: next, publisher: publisher}
+}
+
+func (s *observableRevisionService) Restore(userID, listID, taskID, revisionID uuid.UUID) (ok bool, err error) {
+	ok, err = s.RevisionService.Restore(userID, listID, taskID, revisionID)
+	if nil == err && ok {
+		s.publisher.Publish(newEvent(types.EventKindTaskUpdated, userID, uuid.Nil, listID, taskID))
+	}
+	return ok, err
+}
+
+func (s *observableRevisionService) Undo(userID uuid.UUID) (revision *model.Revision, err error) {
+	revision, err = s.RevisionService.Undo(userID)
+	if nil == err {
+		s.publisher.Publish(newEvent(types.EventKindTaskUpdated, userID, uuid.Nil, revision.ListUUID, revision.TaskUUID))
+	}
+	return revision, err
+}
+
+// observableListService publishes an event for every change made to a list
+// through the ListService it wraps.
+type observableListService struct {
+	ListService
+	publisher events.Publisher
+	members   repository.MemberRepository
+}
+
+// NewObservableListService wraps a ListService so that its changes to the
+// lists are published.
+func NewObservableListService(next ListService, publisher events.Publisher, members repository.MemberRepository) ListService {
+	return &observableListService{ListService: next, publisher: publisher, members: members}
+}
+
+func (s *observableListService) Save(ownerID, groupID uuid.UUID, creation *transfer.ListCreation) (insertedID uuid.UUID, err error) {
+	insertedID, err = s.ListService.Save(ownerID, groupID, creation)
+	if nil == err {
+		s.publisher.Publish(newEvent(types.EventKindListCreated, ownerID, groupID, insertedID, uuid.Nil))
+	}
+	return insertedID, err
+}
+
+func (s *observableListService) Update(ownerID, groupID, listID uuid.UUID, update *transfer.ListUpdate) (ok bool, err error) {
+	ok, err = s.ListService.Update(ownerID, groupID, listID, update)
+	if nil == err && ok {
+		s.publisher.Publish(newEvent(types.EventKindListUpdated, ownerID, groupID, listID, uuid.Nil))
+	}
+	return ok, err
+}
+
+func (s *observableListService) Duplicate(ownerID, listID uuid.UUID) (replicaID uuid.UUID, err error) {
+	replicaID, err = s.ListService.Duplicate(ownerID, listID)
+	if nil == err {
+		s.publisher.Publish(newEvent(types.EventKindListCreated, ownerID, uuid.Nil, replicaID, uuid.Nil))
+	}
+	return replicaID, err
+}
+
+func (s *observableListService) Move(ownerID, listID, targetGroupID uuid.UUID) (ok bool, err error) {
+	ok, err = s.ListService.Move(ownerID, listID, targetGroupID)
+	if nil == err && ok {
+		s.publisher.Publish(newEvent(types.EventKindListMoved, ownerID, targetGroupID, listID, uuid.Nil))
+	}
+	return ok, err
+}
+
+func (s *observableListService) Scatter(ownerID, listID uuid.UUID) (ok bool, err error) {
+	ok, err = s.ListService.Scatter(ownerID, listID)
+	if nil == err && ok {
+		s.publisher.Publish(newEvent(types.EventKindListMoved, ownerID, uuid.Nil, listID, uuid.Nil))
+	}
+	return ok, err
+}
+
+func (s *observableListService) Remove(ownerID, groupID, listID uuid.UUID) error {
+	var event = newEvent(types.EventKindListRemoved, ownerID, groupID, listID, uuid.Nil)
+	_, err := publishOnRemoval(s.publisher, s.members, event, func() (bool, error) {
+		return true, s.ListService.Remove(ownerID, groupID, listID)
+	})
+	return err
+}
+
+// observableGroupService publishes an event for every change made to a group
+// through the GroupService it wraps.
+type observableGroupService struct {
+	GroupService
+	publisher events.Publisher
+	members   repository.MemberRepository
+}
+
+// NewObservableGroupService wraps a GroupService so that its changes to the
+// groups are published.
+func NewObservableGroupService(next GroupService, publisher events.Publisher, members repository.MemberRepository) GroupService {
+	return &observableGroupService{GroupService: next, publisher: publisher, members: members}
+}
+
+func (s *observableGroupService) Save(ownerID uuid.UUID, creation *transfer.GroupCreation) (insertedID uuid.UUID, err error) {
+	insertedID, err = s.GroupService.Save(ownerID, creation)
+	if nil == err {
+		s.publisher.Publish(newEvent(types.EventKindGroupCreated, ownerID, insertedID, uuid.Nil, uuid.Nil))
+	}
+	return insertedID, err
+}
+
+func (s *observableGroupService) Update(ownerID, groupID uuid.UUID, update *transfer.GroupUpdate) (ok bool, err error) {
+	ok, err = s.GroupService.Update(ownerID, groupID, update)
+	if nil == err && ok {
+		s.publisher.Publish(newEvent(types.EventKindGroupUpdated, ownerID, groupID, uuid.Nil, uuid.Nil))
+	}
+	return ok, err
+}
+
+func (s *observableGroupService) Remove(ownerID, groupID uuid.UUID) (ok bool, err error) {
+	var event = newEvent(types.EventKindGroupRemoved, ownerID, groupID, uuid.Nil, uuid.Nil)
+	return publishOnRemoval(s.publisher, s.members, event, func() (bool, error) {
+		return s.GroupService.Remove(ownerID, groupID)
+	})
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"errors"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/events"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher keeps the events it is given.
+type recordingPublisher struct {
+	published []*model.Event
+}
+
+func (p *recordingPublisher) Publish(event *model.Event) {
+	p.published = append(p.published, event)
+}
+
+func TestEventService_Subscribe(t *testing.T) {
+	defer beQuiet()()
+	var s = NewEventService(events.NewBroker(func(*model.Event) ([]uuid.UUID, error) { return nil, nil }, 10, 10), nil, nil)
+
+	t.Run("success", func(t *testing.T) {
+		var lastEventID uint64 = 0
+		subscription, missed, err := s.Subscribe(uuid.New(), &lastEventID)
+		require.NoError(t, err)
+		defer s.Unsubscribe(subscription)
+		require.Len(t, missed, 1)
+		assert.Equal(t, types.EventKindReset, missed[0].Kind)
+	})
+
+	t.Run("nil user", func(t *testing.T) {
+		subscription, _, err := s.Subscribe(uuid.Nil, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Subscribe", "userID").Error())
+		assert.Nil(t, subscription)
+	})
+}
+
+func TestEventService_Recheck(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, sessionID = uuid.New(), uuid.New()
+		until             = time.Now().Add(time.Hour)
+		unexpected        = errors.New("unexpected error")
+	)
+	var setUp = func() (*mocks.TokenRepository, *mocks.UserService, EventService) {
+		var tokens, users = mocks.NewTokenRepositoryMock(), mocks.NewUserServiceMock()
+		return tokens, users, NewEventService(nil, NewDenylist(tokens), users)
+	}
+
+	t.Run("session holds", func(t *testing.T) {
+		var tokens, users, s = setUp()
+		tokens.On("FetchSessionDenial", sessionID.String()).Return(nil, nil)
+		users.On("AssertActive", userID).Return(types.RoleUser, nil)
+		assert.NoError(t, s.Recheck(userID, sessionID))
+	})
+
+	t.Run("session was logged out", func(t *testing.T) {
+		var tokens, users, s = setUp()
+		tokens.On("FetchSessionDenial", sessionID.String()).Return(&until, nil)
+		assert.ErrorIs(t, s.Recheck(userID, sessionID), failure.ErrRevokedToken)
+		users.AssertNotCalled(t, "AssertActive", userID)
+	})
+
+	t.Run("user was blocked", func(t *testing.T) {
+		var tokens, users, s = setUp()
+		var blocked = failure.ErrUserBlocked.Clone()
+		tokens.On("FetchSessionDenial", sessionID.String()).Return(nil, nil)
+		users.On("AssertActive", userID).Return(types.Role(0), blocked)
+		assert.ErrorIs(t, s.Recheck(userID, sessionID), blocked)
+	})
+
+	t.Run("got token repository error", func(t *testing.T) {
+		var tokens, _, s = setUp()
+		tokens.On("FetchSessionDenial", sessionID.String()).Return(nil, unexpected)
+		assert.ErrorIs(t, s.Recheck(userID, sessionID), unexpected)
+	})
+}
+
+func TestEventAudience(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, actorID, listMemberID, groupMemberID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		groupID, listID                               = uuid.New(), uuid.New()
+	)
+
+	t.Run("list of a group", func(t *testing.T) {
+		var members = mocks.NewMemberRepositoryMock()
+		members.On("FetchListAccess", actorID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, GroupUUID: &groupID, Role: types.MemberRoleEditor}, nil)
+		members.On("FetchMembers", types.ShareKindList, listID.String(), int64(1), int64(membersPageSize)).
+			Return([]*model.Member{{UserUUID: listMemberID}}, nil)
+		members.On("FetchGroupAccess", actorID.String(), groupID.String()).
+			Return(&model.GroupAccess{GroupUUID: groupID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		members.On("FetchMembers", types.ShareKindGroup, groupID.String(), int64(1), int64(membersPageSize)).
+			Return([]*model.Member{{UserUUID: groupMemberID}, {UserUUID: actorID}}, nil)
+		var event = newEvent(types.EventKindTaskCreated, actorID, uuid.Nil, listID, uuid.New())
+		audience, err := NewEventAudience(members)(event)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{ownerID, listMemberID, ownerID, groupMemberID, actorID}, audience)
+	})
+
+	t.Run("group", func(t *testing.T) {
+		var members = mocks.NewMemberRepositoryMock()
+		members.On("FetchGroupAccess", ownerID.String(), groupID.String()).
+			Return(&model.GroupAccess{GroupUUID: groupID, OwnerUUID: ownerID, Role: types.MemberRoleOwner}, nil)
+		members.On("FetchMembers", types.ShareKindGroup, groupID.String(), int64(1), int64(membersPageSize)).
+			Return([]*model.Member{{UserUUID: groupMemberID}}, nil)
+		var event = newEvent(types.EventKindGroupUpdated, ownerID, groupID, uuid.Nil, uuid.Nil)
+		audience, err := NewEventAudience(members)(event)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{ownerID, groupMemberID}, audience)
+	})
+
+	t.Run("without list nor group", func(t *testing.T) {
+		var event = newEvent(types.EventKindTaskMoved, actorID, uuid.Nil, uuid.Nil, uuid.New())
+		audience, err := NewEventAudience(mocks.NewMemberRepositoryMock())(event)
+		assert.NoError(t, err)
+		assert.Empty(t, audience)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		var members = mocks.NewMemberRepositoryMock()
+		members.On("FetchListAccess", actorID.String(), listID.String()).Return(nil, failure.ErrListNotFound)
+		var event = newEvent(types.EventKindTaskUpdated, actorID, uuid.Nil, listID, uuid.New())
+		_, err := NewEventAudience(members)(event)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+	})
+}
+
+func TestObservableTaskService(t *testing.T) {
+	defer beQuiet()()
+	var userID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("publishes a change", func(t *testing.T) {
+		var next = mocks.NewTaskServiceMock()
+		var publisher = new(recordingPublisher)
+		next.On("Complete", userID, listID, taskID).Return(true, nil)
+		ok, err := NewObservableTaskService(next, publisher, soleOwner{}).Complete(userID, listID, taskID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		require.Len(t, publisher.published, 1)
+		var event = publisher.published[0]
+		assert.Equal(t, types.EventKindTaskCompleted, event.Kind)
+		assert.Equal(t, userID, event.ActorUUID)
+		assert.Equal(t, &listID, event.ListUUID)
+		assert.Equal(t, &taskID, event.TaskUUID)
+		assert.Nil(t, event.GroupUUID)
+	})
+
+	t.Run("publishes a creation", func(t *testing.T) {
+		var next = mocks.NewTaskServiceMock()
+		var publisher = new(recordingPublisher)
+		var creation = &transfer.TaskCreation{Title: "Write the report"}
+		next.On("Save", userID, listID, creation).Return(taskID, nil)
+		_, err := NewObservableTaskService(next, publisher, soleOwner{}).Save(userID, listID, creation)
+		assert.NoError(t, err)
+		require.Len(t, publisher.published, 1)
+		assert.Equal(t, types.EventKindTaskCreated, publisher.published[0].Kind)
+		assert.Equal(t, &taskID, publisher.published[0].TaskUUID)
+	})
+
+	t.Run("publishes a move to the list left and to the target list", func(t *testing.T) {
+		var (
+			next         = mocks.NewTaskServiceMock()
+			members      = mocks.NewMemberRepositoryMock()
+			publisher    = new(recordingPublisher)
+			sourceListID = uuid.New()
+			memberID     = uuid.New()
+		)
+		next.On("Locate", userID, taskID).Return(sourceListID, nil)
+		members.On("FetchListAccess", userID.String(), sourceListID.String()).
+			Return(&model.ListAccess{ListUUID: sourceListID, OwnerUUID: userID, Role: types.MemberRoleOwner}, nil)
+		members.On("FetchMembers", types.ShareKindList, sourceListID.String(), int64(1), int64(membersPageSize)).
+			Return([]*model.Member{{UserUUID: memberID}}, nil)
+		next.On("Move", userID, taskID, listID).Return(true, nil)
+		_, err := NewObservableTaskService(next, publisher, members).Move(userID, taskID, listID)
+		assert.NoError(t, err)
+		require.Len(t, publisher.published, 2)
+		assert.Equal(t, types.EventKindTaskMoved, publisher.published[0].Kind)
+		assert.Equal(t, &sourceListID, publisher.published[0].ListUUID)
+		assert.ElementsMatch(t, []uuid.UUID{userID, memberID}, publisher.published[0].Audience)
+		assert.Equal(t, types.EventKindTaskMoved, publisher.published[1].Kind)
+		assert.Equal(t, &listID, publisher.published[1].ListUUID)
+		assert.Nil(t, publisher.published[1].Audience)
+	})
+
+	t.Run("publishes a move to today to the list left", func(t *testing.T) {
+		var (
+			next      = mocks.NewTaskServiceMock()
+			members   = mocks.NewMemberRepositoryMock()
+			publisher = new(recordingPublisher)
+			memberID  = uuid.New()
+		)
+		next.On("Locate", userID, taskID).Return(listID, nil)
+		members.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: userID, Role: types.MemberRoleOwner}, nil)
+		members.On("FetchMembers", types.ShareKindList, listID.String(), int64(1), int64(membersPageSize)).
+			Return([]*model.Member{{UserUUID: memberID}}, nil)
+		next.On("Today", userID, taskID).Return(true, nil)
+		_, err := NewObservableTaskService(next, publisher, members).Today(userID, taskID)
+		assert.NoError(t, err)
+		require.Len(t, publisher.published, 1)
+		assert.Equal(t, types.EventKindTaskMoved, publisher.published[0].Kind)
+		assert.Equal(t, &listID, publisher.published[0].ListUUID)
+		assert.ElementsMatch(t, []uuid.UUID{userID, memberID}, publisher.published[0].Audience)
+	})
+
+	t.Run("move refused", func(t *testing.T) {
+		var next = mocks.NewTaskServiceMock()
+		var publisher = new(recordingPublisher)
+		next.On("Locate", userID, taskID).Return(uuid.Nil, failure.ErrTaskNotFound)
+		next.On("Move", userID, taskID, listID).Return(false, failure.ErrTaskNotFound)
+		_, err := NewObservableTaskService(next, publisher, soleOwner{}).Move(userID, taskID, listID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Empty(t, publisher.published)
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		var next = mocks.NewTaskServiceMock()
+		var publisher = new(recordingPublisher)
+		next.On("Pin", userID, listID, taskID).Return(false, nil)
+		ok, err := NewObservableTaskService(next, publisher, soleOwner{}).Pin(userID, listID, taskID)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, publisher.published)
+	})
+
+	t.Run("change failed", func(t *testing.T) {
+		var next = mocks.NewTaskServiceMock()
+		var publisher = new(recordingPublisher)
+		next.On("Delete", userID, listID, taskID).Return(failure.ErrInsufficientRole)
+		err := NewObservableTaskService(next, publisher, soleOwner{}).Delete(userID, listID, taskID)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		assert.Empty(t, publisher.published)
+	})
+}
+
+func TestObservableListService_Remove(t *testing.T) {
+	defer beQuiet()()
+	var userID, memberID, groupID, listID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			next      = mocks.NewListServiceMock()
+			members   = mocks.NewMemberRepositoryMock()
+			publisher = new(recordingPublisher)
+		)
+		members.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: userID, GroupUUID: &groupID, Role: types.MemberRoleOwner}, nil)
+		members.On("FetchMembers", types.ShareKindList, listID.String(), int64(1), int64(membersPageSize)).
+			Return([]*model.Member{{UserUUID: memberID}}, nil)
+		members.On("FetchGroupAccess", userID.String(), groupID.String()).
+			Return(&model.GroupAccess{GroupUUID: groupID, OwnerUUID: userID, Role: types.MemberRoleOwner}, nil)
+		members.On("FetchMembers", types.ShareKindGroup, groupID.String(), int64(1), int64(membersPageSize)).
+			Return([]*model.Member{}, nil)
+		next.On("Remove", userID, groupID, listID).Return(nil)
+		err := NewObservableListService(next, publisher, members).Remove(userID, groupID, listID)
+		assert.NoError(t, err)
+		require.Len(t, publisher.published, 1)
+		assert.Equal(t, types.EventKindListRemoved, publisher.published[0].Kind)
+		assert.ElementsMatch(t, []uuid.UUID{userID, memberID, userID}, publisher.published[0].Audience)
+	})
+
+	t.Run("removal failed", func(t *testing.T) {
+		var (
+			next      = mocks.NewListServiceMock()
+			members   = mocks.NewMemberRepositoryMock()
+			publisher = new(recordingPublisher)
+		)
+		members.On("FetchListAccess", userID.String(), listID.String()).Return(nil, failure.ErrListNotFound)
+		next.On("Remove", userID, groupID, listID).Return(failure.ErrListNotFound)
+		err := NewObservableListService(next, publisher, members).Remove(userID, groupID, listID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Empty(t, publisher.published)
+	})
+}
+
+func TestObservableGroupService_Update(t *testing.T) {
+	defer beQuiet()()
+	var userID, groupID = uuid.New(), uuid.New()
+	var update = &transfer.GroupUpdate{Name: "Work"}
+
+	t.Run("success", func(t *testing.T) {
+		var next = mocks.NewGroupServiceMock()
+		var publisher = new(recordingPublisher)
+		next.On("Update", userID, groupID, update).Return(true, nil)
+		ok, err := NewObservableGroupService(next, publisher, soleOwner{}).Update(userID, groupID, update)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		require.Len(t, publisher.published, 1)
+		assert.Equal(t, types.EventKindGroupUpdated, publisher.published[0].Kind)
+		assert.Equal(t, &groupID, publisher.published[0].GroupUUID)
+	})
+
+	t.Run("got an error", func(t *testing.T) {
+		var next = mocks.NewGroupServiceMock()
+		var publisher = new(recordingPublisher)
+		next.On("Update", userID, groupID, update).Return(false, errors.New("unexpected error"))
+		_, err := NewObservableGroupService(next, publisher, soleOwner{}).Update(userID, groupID, update)
+		assert.Error(t, err)
+		assert.Empty(t, publisher.published)
+	})
+}
+
+func TestObservableRevisionService_Undo(t *testing.T) {
+	defer beQuiet()()
+	var userID = uuid.New()
+	var revision = &model.Revision{UUID: uuid.New(), TaskUUID: uuid.New(), ListUUID: uuid.New()}
+	var next = mocks.NewRevisionServiceMock()
+	var publisher = new(recordingPublisher)
+	next.On("Undo", userID).Return(revision, nil)
+	_, err := NewObservableRevisionService(next, publisher).Undo(userID)
+	assert.NoError(t, err)
+	require.Len(t, publisher.published, 1)
+	assert.Equal(t, types.EventKindTaskUpdated, publisher.published[0].Kind)
+	assert.Equal(t, &revision.TaskUUID, publisher.published[0].TaskUUID)
+	assert.Equal(t, &revision.ListUUID, publisher.published[0].ListUUID)
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// exportArchive is everything of a user that an export holds. It is written
+// as a zip of a JSON file per kind of thing, the files of the attachments
+// under "attachments/{attachment_uuid}/", and a Markdown rendering of it all
+// meant to be read by a person.
+type exportArchive struct {
+	exportedAt  time.Time
+	profile     *transfer.User
+	settings    []*transfer.UserSetting
+	groups      []*model.Group
+	lists       []*model.List
+	tasks       []*model.Task
+	trashed     []*model.Task
+	steps       []*model.Step
+	tags        []*model.Tag
+	taskTags    []*exportTaskTag
+	attachments []*model.Attachment
+}
+
+/* Tells that a tag is attached to a task.  */
+type exportTaskTag struct {
+	TaskUUID uuid.UUID `json:"task_uuid"`
+	TagUUID  uuid.UUID `json:"tag_uuid"`
+}
+
+// write writes the archive as a zip into w. The files of the attachments are
+// opened with open, which returns a nil content for those that have none.
+func (a *exportArchive) write(w io.Writer, open func(attachment *model.Attachment) (io.ReadCloser, error)) error {
+	var archive = zip.NewWriter(w)
+	var files = []struct {
+		name    string
+		content any
+	}{
+		{"profile.json", a.profile},
+		{"settings.json", a.settings},
+		{"groups.json", a.groups},
+		{"lists.json", a.lists},
+		{"tasks.json", a.tasks},
+		{"trash.json", a.trashed},
+		{"steps.json", a.steps},
+		{"tags.json", a.tags},
+		{"task_tags.json", a.taskTags},
+		{"attachments.json", a.attachments},
+	}
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: a.exportedAt})
+		if nil != err {
+			return err
+		}
+		var encoder = json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(file.content); nil != err {
+			return err
+		}
+	}
+	f, err := archive.CreateHeader(&zip.FileHeader{Name: "export.md", Method: zip.Deflate, Modified: a.exportedAt})
+	if nil != err {
+		return err
+	}
+	if err = a.render(f); nil != err {
+		return err
+	}
+	for _, attachment := range a.attachments {
+		if err = writeAttachment(archive, attachment, open); nil != err {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func writeAttachment(
+	archive *zip.Writer,
+	attachment *model.Attachment,
+	open func(attachment *model.Attachment) (io.ReadCloser, error),
+) error {
+	content, err := open(attachment)
+	if nil != err {
+		return err
+	}
+	if nil == content {
+		return nil
+	}
+	defer content.Close()
+	/* Most attachments are compressed already.  */
+	f, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     attachmentPath(attachment),
+		Method:   zip.Store,
+		Modified: attachment.CreatedAt,
+	})
+	if nil != err {
+		return err
+	}
+	_, err = io.Copy(f, content)
+	return err
+}
+
+// attachmentPath is where the file of an attachment is in an archive.
+func attachmentPath(attachment *model.Attachment) string {
+	return fmt.Sprintf("attachments/%s/%s", attachment.UUID, attachment.FileName)
+}
+
+// markdownEscaper escapes the characters that Markdown would read as markup.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "#", `\#`, "<", `\<`, ">", `\>`,
+)
+
+// render writes the archive as a Markdown document: the profile and the
+// settings of the user, then each group with its lists, then the lists
+// without a group, each with its tasks as a checklist, then the tasks of no
+// list above, such as those of Today and Tomorrow, and the trash.
+func (a *exportArchive) render(w io.Writer) error {
+	var b strings.Builder
+	var name = strings.Join(strings.Fields(a.profile.FirstName+" "+a.profile.LastName), " ")
+	fmt.Fprintf(&b, "# Noda data of %s\n\n", markdownEscaper.Replace(name))
+	fmt.Fprintf(&b, "Exported on %s for %s.\n", a.exportedAt.UTC().Format(time.RFC1123), markdownEscaper.Replace(a.profile.Email))
+	if 0 < len(a.settings) {
+		b.WriteString("\n## Settings\n\n")
+		for _, setting := range a.settings {
+			value, err := json.Marshal(setting.Value)
+			if nil != err {
+				return err
+			}
+			fmt.Fprintf(&b, "- **%s**: `%s`\n", markdownEscaper.Replace(setting.Key), value)
+		}
+	}
+	var renderer = newExportRenderer(a)
+	for _, group := range a.groups {
+		fmt.Fprintf(&b, "\n## %s\n", markdownEscaper.Replace(group.Name))
+		renderDescription(&b, group.Description, "")
+		for _, list := range renderer.listsOf[group.UUID] {
+			renderer.renderList(&b, list)
+		}
+	}
+	if lists := renderer.listsOf[uuid.Nil]; 0 < len(lists) {
+		b.WriteString("\n## Lists without a group\n")
+		for _, list := range lists {
+			renderer.renderList(&b, list)
+		}
+	}
+	var listed = make(map[uuid.UUID]bool, len(a.lists))
+	for _, list := range a.lists {
+		listed[list.UUID] = true
+	}
+	var others = make([]*model.Task, 0)
+	for _, task := range a.tasks {
+		if !listed[task.ListUUID] {
+			others = append(others, task)
+		}
+	}
+	for _, section := range []struct {
+		title string
+		tasks []*model.Task
+	}{{"Other tasks", others}, {"Trash", a.trashed}} {
+		if 0 == len(section.tasks) {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", section.title)
+		for _, task := range section.tasks {
+			renderer.renderTask(&b, task)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// exportRenderer indexes the things of an archive by what they belong to.
+type exportRenderer struct {
+	listsOf       map[uuid.UUID][]*model.List
+	tasksOf       map[uuid.UUID][]*model.Task
+	stepsOf       map[uuid.UUID][]*model.Step
+	tagsOf        map[uuid.UUID][]*model.Tag
+	attachmentsOf map[uuid.UUID][]*model.Attachment
+}
+
+func newExportRenderer(a *exportArchive) *exportRenderer {
+	var r = &exportRenderer{
+		listsOf:       make(map[uuid.UUID][]*model.List),
+		tasksOf:       make(map[uuid.UUID][]*model.Task),
+		stepsOf:       make(map[uuid.UUID][]*model.Step),
+		tagsOf:        make(map[uuid.UUID][]*model.Tag),
+		attachmentsOf: make(map[uuid.UUID][]*model.Attachment),
+	}
+	var groups = make(map[uuid.UUID]bool, len(a.groups))
+	for _, group := range a.groups {
+		groups[group.UUID] = true
+	}
+	for _, list := range a.lists {
+		var groupID = list.GroupUUID
+		if !groups[groupID] {
+			groupID = uuid.Nil
+		}
+		r.listsOf[groupID] = append(r.listsOf[groupID], list)
+	}
+	for _, task := range a.tasks {
+		r.tasksOf[task.ListUUID] = append(r.tasksOf[task.ListUUID], task)
+	}
+	for _, step := range a.steps {
+		r.stepsOf[step.TaskUUID] = append(r.stepsOf[step.TaskUUID], step)
+	}
+	var tags = make(map[uuid.UUID]*model.Tag, len(a.tags))
+	for _, tag := range a.tags {
+		tags[tag.UUID] = tag
+	}
+	for _, taskTag := range a.taskTags {
+		if tag, ok := tags[taskTag.TagUUID]; ok {
+			r.tagsOf[taskTag.TaskUUID] = append(r.tagsOf[taskTag.TaskUUID], tag)
+		}
+	}
+	for _, attachment := range a.attachments {
+		r.attachmentsOf[attachment.TaskUUID] = append(r.attachmentsOf[attachment.TaskUUID], attachment)
+	}
+	return r
+}
+
+func (r *exportRenderer) renderList(b *strings.Builder, list *model.List) {
+	fmt.Fprintf(b, "\n### %s\n", markdownEscaper.Replace(list.Name))
+	renderDescription(b, list.Description, "")
+	var tasks = r.tasksOf[list.UUID]
+	if 0 == len(tasks) {
+		b.WriteString("\nNo tasks.\n")
+		return
+	}
+	b.WriteString("\n")
+	for _, task := range tasks {
+		r.renderTask(b, task)
+	}
+}
+
+// renderTask writes a task as an item of a checklist, with its details, its
+// description, its steps and its attachments nested below it.
+func (r *exportRenderer) renderTask(b *strings.Builder, task *model.Task) {
+	fmt.Fprintf(b, "- [%s] **%s**", checkbox(types.TaskStatusComplete == task.Status), markdownEscaper.Replace(task.Title))
+	var details = make([]string, 0, 3)
+	if "" != task.Priority {
+		details = append(details, string(task.Priority)+" priority")
+	}
+	if nil != task.DueDate {
+		details = append(details, "due "+task.DueDate.UTC().Format(time.RFC1123))
+	}
+	if nil != task.RemindAt {
+		details = append(details, "reminder "+task.RemindAt.UTC().Format(time.RFC1123))
+	}
+	if 0 < len(details) {
+		fmt.Fprintf(b, " (%s)", strings.Join(details, ", "))
+	}
+	for _, tag := range r.tagsOf[task.UUID] {
+		fmt.Fprintf(b, " `#%s`", strings.ReplaceAll(tag.Name, "`", "'"))
+	}
+	b.WriteString("\n")
+	renderDescription(b, task.Description, "  ")
+	for _, step := range r.stepsOf[task.UUID] {
+		fmt.Fprintf(b, "  - [%s] %s\n", checkbox(nil != step.CompletedAt), markdownEscaper.Replace(step.Description))
+	}
+	for _, attachment := range r.attachmentsOf[task.UUID] {
+		fmt.Fprintf(b, "  - Attachment: [%s](<%s>)\n",
+			markdownEscaper.Replace(attachment.FileName), strings.ReplaceAll(attachmentPath(attachment), ">", "%3E"))
+	}
+}
+
+// renderDescription writes a description as a paragraph of its own, or
+// indented by indent below an item of a list. Descriptions are written as
+// they are, since they can be Markdown already.
+func renderDescription(b *strings.Builder, description, indent string) {
+	if "" == strings.TrimSpace(description) {
+		return
+	}
+	if "" == indent {
+		b.WriteString("\n")
+	}
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		fmt.Fprintf(b, "%s%s\n", indent, strings.TrimRight(line, "\r"))
+	}
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "x"
+	}
+	return " "
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportArchive_render(t *testing.T) {
+	var (
+		exportedAt = time.Date(2024, 12, 31, 9, 0, 0, 0, time.UTC)
+		due        = time.Date(2025, 1, 2, 17, 0, 0, 0, time.UTC)
+		group      = &model.Group{UUID: uuid.New(), Name: "Work", Description: "Day job"}
+		grouped    = &model.List{UUID: uuid.New(), GroupUUID: group.UUID, Name: "Reports"}
+		scattered  = &model.List{UUID: uuid.New(), Name: "Errands"}
+		task       = &model.Task{
+			UUID:        uuid.New(),
+			ListUUID:    grouped.UUID,
+			Title:       "Write *the* report",
+			Description: "Quarterly.\nWith charts.",
+			Priority:    types.TaskPriorityUrgent,
+			Status:      types.TaskStatusComplete,
+			DueDate:     &due,
+		}
+		archive = &exportArchive{
+			exportedAt: exportedAt,
+			profile:    &transfer.User{FirstName: "Jane", LastName: "Doe", Email: "jane@noda.com"},
+			groups:     []*model.Group{group},
+			lists:      []*model.List{grouped, scattered},
+			tasks:      []*model.Task{task},
+			steps:      []*model.Step{{TaskUUID: task.UUID, Description: "Draft"}},
+		}
+		b strings.Builder
+	)
+	require.NoError(t, archive.render(&b))
+	assert.Equal(t, "# Noda data of Jane Doe\n\n"+
+		"Exported on Tue, 31 Dec 2024 09:00:00 UTC for jane@noda.com.\n\n"+
+		"## Work\n\n"+
+		"Day job\n\n"+
+		"### Reports\n\n"+
+		"- [x] **Write \\*the\\* report** (urgent priority, due Thu, 02 Jan 2025 17:00:00 UTC)\n"+
+		"  Quarterly.\n"+
+		"  With charts.\n"+
+		"  - [ ] Draft\n\n"+
+		"## Lists without a group\n\n"+
+		"### Errands\n\n"+
+		"No tasks.\n", b.String())
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mail"
+	"noda/repository"
+	"noda/storage"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportService builds archives of all the data of a user, so that they can
+// take it with them. Building an archive can take a while, so asking for an
+// export only records it, and the archives are built in the background by
+// Build, which mails their owner once they are ready. An archive can be
+// downloaded until its retention is over, and then Purge removes it.
+type ExportService interface {
+	Request(ownerID uuid.UUID) (export *model.Export, err error)
+	FetchLatest(ownerID uuid.UUID) (export *model.Export, err error)
+	Open(ownerID uuid.UUID) (export *model.Export, content io.ReadCloser, err error)
+	Build(now time.Time) (built int, err error)
+	Purge(now time.Time) (purged int, err error)
+}
+
+const (
+	// exportBatchSize is how many archives a run of Build builds at most.
+	exportBatchSize = 5
+	// purgeBatchSize is how many archives a run of Purge removes at most.
+	purgeBatchSize = 100
+	// exportPageSize is how many things of a kind are read at once to build
+	// an archive.
+	exportPageSize = 100
+)
+
+type exportService struct {
+	r           repository.ExportRepository
+	store       storage.BlobStore
+	mailer      mail.Mailer
+	retention   time.Duration
+	users       UserService
+	groups      GroupService
+	lists       ListService
+	tasks       TaskService
+	steps       StepService
+	tags        TagService
+	attachments AttachmentService
+}
+
+// NewExportService creates an ExportService that keeps the archives in store
+// for the given retention.
+func NewExportService(
+	r repository.ExportRepository,
+	store storage.BlobStore,
+	mailer mail.Mailer,
+	retention time.Duration,
+	users UserService,
+	groups GroupService,
+	lists ListService,
+	tasks TaskService,
+	steps StepService,
+	tags TagService,
+	attachments AttachmentService,
+) ExportService {
+	return &exportService{
+		r:           r,
+		store:       store,
+		mailer:      mailer,
+		retention:   retention,
+		users:       users,
+		groups:      groups,
+		lists:       lists,
+		tasks:       tasks,
+		steps:       steps,
+		tags:        tags,
+		attachments: attachments,
+	}
+}
+
+// Request records an export of the data of the user, unless one is already
+// pending, in which case that one is returned.
+func (s *exportService) Request(ownerID uuid.UUID) (export *model.Export, err error) {
+	if uuid.Nil == ownerID {
+		err = failure.NewNilParameterError("Request", "ownerID")
+		log.Println(err)
+		return nil, err
+	}
+	export, err = s.r.FetchLatest(ownerID.String())
+	switch {
+	case nil == err && types.ExportStatusPending == export.Status:
+		return export, nil
+	case nil != err && !errors.Is(err, failure.ErrExportNotFound):
+		return nil, err
+	}
+	if _, err = s.r.Save(ownerID.String()); nil != err {
+		return nil, err
+	}
+	return s.r.FetchLatest(ownerID.String())
+}
+
+func (s *exportService) FetchLatest(ownerID uuid.UUID) (export *model.Export, err error) {
+	if uuid.Nil == ownerID {
+		err = failure.NewNilParameterError("FetchLatest", "ownerID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.r.FetchLatest(ownerID.String())
+}
+
+// Open opens the archive of the last export of the user, which must be ready.
+func (s *exportService) Open(ownerID uuid.UUID) (export *model.Export, content io.ReadCloser, err error) {
+	export, err = s.FetchLatest(ownerID)
+	if nil != err {
+		return nil, nil, err
+	}
+	var status = export.Status
+	if types.ExportStatusReady == status && nil != export.ExpiresAt && !time.Now().Before(*export.ExpiresAt) {
+		status = types.ExportStatusExpired
+	}
+	if types.ExportStatusReady != status || nil == export.BlobKey {
+		return nil, nil, failure.ErrExportNotReady.Clone().FormatDetails(status)
+	}
+	content, err = s.store.Get(context.Background(), *export.BlobKey)
+	if nil != err {
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			log.Printf("export %q has no blob under %q", export.UUID, *export.BlobKey)
+			return nil, nil, failure.ErrExportNotReady.Clone().FormatDetails(types.ExportStatusExpired)
+		}
+		return nil, nil, err
+	}
+	return export, content, nil
+}
+
+// Build builds the archives of the pending exports, and mails their owners
+// that they can be downloaded until the retention is over. An export whose
+// archive cannot be built is marked as failed.
+func (s *exportService) Build(now time.Time) (built int, err error) {
+	exports, err := s.r.FetchPending(exportBatchSize)
+	if nil != err {
+		return 0, err
+	}
+	var failed = 0
+	for _, export := range exports {
+		key, size, err := s.build(export, now)
+		if nil != err {
+			log.Printf("could not build the archive of export %q: %v", export.UUID, err)
+			if _, err = s.r.MarkFailed(export.UUID.String()); nil != err {
+				log.Printf("could not mark export %q as failed: %v", export.UUID, err)
+			}
+			failed++
+			continue
+		}
+		var expiresAt = now.Add(s.retention)
+		if _, err = s.r.MarkReady(export.UUID.String(), key, size, expiresAt); nil != err {
+			log.Printf("could not mark export %q as ready: %v", export.UUID, err)
+			s.remove(key)
+			failed++
+			continue
+		}
+		built++
+		s.notify(export.OwnerUUID, expiresAt)
+	}
+	if 0 < failed {
+		return built, fmt.Errorf("could not build %d export(s)", failed)
+	}
+	return built, nil
+}
+
+// build writes the archive of the export into a temporary file, so that its
+// size is known, and stores it.
+func (s *exportService) build(export *model.Export, now time.Time) (key string, size int64, err error) {
+	archive, err := s.gather(export.OwnerUUID, now)
+	if nil != err {
+		return "", 0, err
+	}
+	file, err := os.CreateTemp("", "noda-export-*.zip")
+	if nil != err {
+		return "", 0, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	if err = archive.write(file, s.open); nil != err {
+		return "", 0, err
+	}
+	if size, err = file.Seek(0, io.SeekCurrent); nil != err {
+		return "", 0, err
+	}
+	if _, err = file.Seek(0, io.SeekStart); nil != err {
+		return "", 0, err
+	}
+	key = fmt.Sprintf("exports/%s/%s.zip", export.OwnerUUID, export.UUID)
+	var ctx, cancel = context.WithTimeout(context.Background(), blobTimeout)
+	defer cancel()
+	if err = s.store.Put(ctx, key, file, size, "application/zip"); nil != err {
+		return "", 0, err
+	}
+	return key, size, nil
+}
+
+// gather retrieves everything of the user that goes into an archive.
+func (s *exportService) gather(ownerID uuid.UUID, now time.Time) (archive *exportArchive, err error) {
+	archive = &exportArchive{exportedAt: now}
+	if archive.profile, err = s.users.FetchByID(ownerID); nil != err {
+		return nil, err
+	}
+	archive.settings, err = fetchEveryPage(func(p *types.Pagination) (*types.Result[transfer.UserSetting], error) {
+		return s.users.FetchSettings(ownerID, p, "", "")
+	})
+	if nil != err {
+		return nil, err
+	}
+	archive.groups, err = fetchEveryPage(func(p *types.Pagination) (*types.Result[model.Group], error) {
+		return s.groups.Fetch(ownerID, p, "", "")
+	})
+	if nil != err {
+		return nil, err
+	}
+	archive.lists, err = fetchEveryPage(func(p *types.Pagination) (*types.Result[model.List], error) {
+		return s.lists.Fetch(ownerID, p, "", "")
+	})
+	if nil != err {
+		return nil, err
+	}
+	archive.tags, err = fetchEveryPage(func(p *types.Pagination) (*types.Result[model.Tag], error) {
+		return s.tags.Fetch(ownerID, p, "", "")
+	})
+	if nil != err {
+		return nil, err
+	}
+	if archive.tasks, archive.trashed, err = s.gatherTasks(ownerID, archive.lists); nil != err {
+		return nil, err
+	}
+	archive.steps = make([]*model.Step, 0)
+	archive.taskTags = make([]*exportTaskTag, 0)
+	archive.attachments = make([]*model.Attachment, 0)
+	for _, tasks := range [][]*model.Task{archive.tasks, archive.trashed} {
+		for _, task := range tasks {
+			steps, err := s.steps.Fetch(ownerID, task.UUID)
+			if nil != err {
+				return nil, err
+			}
+			tags, err := s.tags.FetchFromTask(ownerID, task.UUID)
+			if nil != err {
+				return nil, err
+			}
+			attachments, err := s.attachments.Fetch(ownerID, task.UUID)
+			if nil != err {
+				return nil, err
+			}
+			archive.steps = append(archive.steps, steps...)
+			for _, tag := range tags {
+				archive.taskTags = append(archive.taskTags, &exportTaskTag{TaskUUID: task.UUID, TagUUID: tag.UUID})
+			}
+			archive.attachments = append(archive.attachments, attachments...)
+		}
+	}
+	return archive, nil
+}
+
+// gatherTasks retrieves every task of the user, each once: those of the
+// lists, of Today and Tomorrow, the deferred, completed and archived ones, and
+// apart from them those in the trash.
+func (s *exportService) gatherTasks(ownerID uuid.UUID, lists []*model.List) (tasks, trashed []*model.Task, err error) {
+	trashed, err = fetchEveryPage(func(p *types.Pagination) (*types.Result[model.Task], error) {
+		return s.tasks.FetchTrashed(ownerID, p, "", "")
+	})
+	if nil != err {
+		return nil, nil, err
+	}
+	var seen = make(map[uuid.UUID]bool, len(trashed))
+	for _, task := range trashed {
+		seen[task.UUID] = true
+	}
+	var sources = make([]func(p *types.Pagination) (*types.Result[model.Task], error), 0, len(lists)+6)
+	for _, list := range lists {
+		sources = append(sources, func(p *types.Pagination) (*types.Result[model.Task], error) {
+			return s.tasks.Fetch(ownerID, list.UUID, p, "", "", nil, uuid.Nil)
+		})
+	}
+	sources = append(sources,
+		func(p *types.Pagination) (*types.Result[model.Task], error) {
+			return s.tasks.FetchFromToday(ownerID, p, "", "", nil)
+		},
+		func(p *types.Pagination) (*types.Result[model.Task], error) {
+			return s.tasks.FetchFromTomorrow(ownerID, p, "", "", nil)
+		},
+		func(p *types.Pagination) (*types.Result[model.Task], error) {
+			return s.tasks.FetchFromDeferred(ownerID, p, "", "")
+		},
+		func(p *types.Pagination) (*types.Result[model.Task], error) {
+			return s.tasks.FetchCompleted(ownerID, p, "", "")
+		},
+		func(p *types.Pagination) (*types.Result[model.Task], error) {
+			return s.tasks.FetchArchived(ownerID, p, "", "")
+		},
+		func(p *types.Pagination) (*types.Result[model.Task], error) {
+			return s.tasks.FetchAll(ownerID, p, "", "")
+		},
+	)
+	tasks = make([]*model.Task, 0)
+	for _, fetch := range sources {
+		found, err := fetchEveryPage(fetch)
+		if nil != err {
+			return nil, nil, err
+		}
+		for _, task := range found {
+			if !seen[task.UUID] {
+				seen[task.UUID] = true
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks, trashed, nil
+}
+
+// open opens the file of an attachment, or tells that it has none with a nil
+// content.
+func (s *exportService) open(attachment *model.Attachment) (content io.ReadCloser, err error) {
+	_, content, err = s.attachments.Open(attachment.OwnerUUID, attachment.TaskUUID, attachment.UUID)
+	if errors.Is(err, failure.ErrAttachmentNotFound) {
+		return nil, nil
+	}
+	return content, err
+}
+
+// fetchEveryPage retrieves every page of a paginated collection.
+func fetchEveryPage[T any](fetch func(pagination *types.Pagination) (*types.Result[T], error)) (all []*T, err error) {
+	all = make([]*T, 0)
+	for page := int64(1); ; page++ {
+		result, err := fetch(&types.Pagination{Page: page, RPP: exportPageSize})
+		if nil != err {
+			return nil, err
+		}
+		all = append(all, result.Payload...)
+		if result.Retrieved < exportPageSize {
+			return all, nil
+		}
+	}
+}
+
+// notify mails the user that the archive of its data is ready. The archive
+// can still be downloaded if the email is not delivered.
+func (s *exportService) notify(ownerID uuid.UUID, expiresAt time.Time) {
+	user, err := s.users.FetchByID(ownerID)
+	if nil != err {
+		log.Printf("could not mail user %s that the export is ready: %v", ownerID, err)
+		return
+	}
+	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = s.mailer.Send(ctx, &mail.Message{
+		To:      []string{user.Email},
+		Subject: "Your Noda data is ready to download",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"The archive of your Noda data that you asked for is ready. You can download it "+
+			"from /me/export/archive until %s, when it will be removed.\n\n"+
+			"If you did not ask for it, change your password, as someone else may have.\n",
+			user.FirstName, expiresAt.UTC().Format(time.RFC1123)),
+	})
+	if nil != err {
+		log.Printf("could not mail user %s that the export is ready: %v", ownerID, err)
+	}
+}
+
+// Purge removes the archives whose retention is over.
+func (s *exportService) Purge(now time.Time) (purged int, err error) {
+	exports, err := s.r.FetchExpired(now, purgeBatchSize)
+	if nil != err {
+		return 0, err
+	}
+	var failed = 0
+	for _, export := range exports {
+		if nil != export.BlobKey && !s.remove(*export.BlobKey) {
+			failed++
+			continue
+		}
+		if _, err = s.r.MarkExpired(export.UUID.String()); nil != err {
+			log.Printf("could not mark export %q as expired: %v", export.UUID, err)
+			failed++
+			continue
+		}
+		purged++
+	}
+	if 0 < failed {
+		return purged, fmt.Errorf("could not purge %d export(s)", failed)
+	}
+	return purged, nil
+}
+
+func (s *exportService) remove(key string) (ok bool) {
+	var ctx, cancel = context.WithTimeout(context.Background(), blobTimeout)
+	defer cancel()
+	if err := s.store.Delete(ctx, key); nil != err {
+		log.Printf("could not remove blob %q: %v", key, err)
+		return false
+	}
+	return true
+}
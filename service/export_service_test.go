@@ -0,0 +1,344 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mail"
+	"noda/mocks"
+	"noda/storage"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportService_Request(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID    = uuid.New()
+		pending    = &model.Export{UUID: uuid.New(), OwnerUUID: ownerID, Status: types.ExportStatusPending}
+		newService = func(r *mocks.ExportRepository) ExportService {
+			return NewExportService(r, nil, nil, time.Hour, nil, nil, nil, nil, nil, nil, nil)
+		}
+	)
+
+	t.Run("records a new export", func(t *testing.T) {
+		var r = mocks.NewExportRepositoryMock()
+		r.On("FetchLatest", ownerID.String()).Return(nil, failure.ErrExportNotFound).Once()
+		r.On("Save", ownerID.String()).Return(pending.UUID.String(), nil)
+		r.On("FetchLatest", ownerID.String()).Return(pending, nil).Once()
+		res, err := newService(r).Request(ownerID)
+		assert.NoError(t, err)
+		assert.Equal(t, pending, res)
+		r.AssertExpectations(t)
+	})
+
+	t.Run("records a new export after a ready one", func(t *testing.T) {
+		var r = mocks.NewExportRepositoryMock()
+		r.On("FetchLatest", ownerID.String()).
+			Return(&model.Export{UUID: uuid.New(), Status: types.ExportStatusReady}, nil).Once()
+		r.On("Save", ownerID.String()).Return(pending.UUID.String(), nil)
+		r.On("FetchLatest", ownerID.String()).Return(pending, nil).Once()
+		res, err := newService(r).Request(ownerID)
+		assert.NoError(t, err)
+		assert.Equal(t, pending, res)
+	})
+
+	t.Run("returns the pending export", func(t *testing.T) {
+		var r = mocks.NewExportRepositoryMock()
+		r.On("FetchLatest", ownerID.String()).Return(pending, nil)
+		res, err := newService(r).Request(ownerID)
+		assert.NoError(t, err)
+		assert.Equal(t, pending, res)
+		r.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("got an unexpected error", func(t *testing.T) {
+		var r = mocks.NewExportRepositoryMock()
+		r.On("FetchLatest", ownerID.String()).Return(nil, errors.New("unexpected error"))
+		res, err := newService(r).Request(ownerID)
+		assert.Error(t, err)
+		assert.Nil(t, res)
+		r.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("got a nil owner", func(t *testing.T) {
+		res, err := newService(mocks.NewExportRepositoryMock()).Request(uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Request", "ownerID").Error())
+		assert.Nil(t, res)
+	})
+}
+
+func TestExportService_Open(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID   = uuid.New()
+		key       = "exports/archive.zip"
+		missing   = "exports/missing.zip"
+		later     = time.Now().Add(time.Hour)
+		earlier   = time.Now().Add(-time.Hour)
+		store     = newBlobStore(t)
+		newExport = func(status types.ExportStatus, key *string, expiresAt *time.Time) *model.Export {
+			return &model.Export{UUID: uuid.New(), OwnerUUID: ownerID, Status: status, BlobKey: key, ExpiresAt: expiresAt}
+		}
+		open = func(export *model.Export) (*model.Export, io.ReadCloser, error) {
+			var r = mocks.NewExportRepositoryMock()
+			r.On("FetchLatest", ownerID.String()).Return(export, nil)
+			return NewExportService(r, store, nil, time.Hour, nil, nil, nil, nil, nil, nil, nil).Open(ownerID)
+		}
+	)
+	require.NoError(t, store.Put(context.Background(), key, strings.NewReader("PK"), 2, "application/zip"))
+
+	t.Run("success", func(t *testing.T) {
+		var export = newExport(types.ExportStatusReady, &key, &later)
+		res, content, err := open(export)
+		require.NoError(t, err)
+		defer content.Close()
+		assert.Equal(t, export, res)
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, "PK", string(data))
+	})
+
+	t.Run("export is pending", func(t *testing.T) {
+		_, content, err := open(newExport(types.ExportStatusPending, nil, nil))
+		assert.ErrorContains(t, err, failure.ErrExportNotReady.Clone().FormatDetails("pending").Error())
+		assert.Nil(t, content)
+	})
+
+	t.Run("export is past its retention", func(t *testing.T) {
+		_, content, err := open(newExport(types.ExportStatusReady, &key, &earlier))
+		assert.ErrorContains(t, err, failure.ErrExportNotReady.Clone().FormatDetails("expired").Error())
+		assert.Nil(t, content)
+	})
+
+	t.Run("archive was removed", func(t *testing.T) {
+		_, content, err := open(newExport(types.ExportStatusReady, &missing, &later))
+		assert.ErrorContains(t, err, failure.ErrExportNotReady.Clone().FormatDetails("expired").Error())
+		assert.Nil(t, content)
+	})
+}
+
+func TestExportService_Build(t *testing.T) {
+	defer beQuiet()()
+	var (
+		now     = time.Date(2024, 12, 31, 9, 0, 0, 0, time.UTC)
+		ownerID = uuid.New()
+		export  = &model.Export{UUID: uuid.New(), OwnerUUID: ownerID, Status: types.ExportStatusPending}
+		key     = "exports/" + ownerID.String() + "/" + export.UUID.String() + ".zip"
+		profile = &transfer.User{UUID: ownerID, FirstName: "Jane", LastName: "Doe", Email: "jane@noda.com"}
+		group   = &model.Group{UUID: uuid.New(), OwnerUUID: ownerID, Name: "Home"}
+		list    = &model.List{UUID: uuid.New(), OwnerUUID: ownerID, GroupUUID: group.UUID, Name: "Chores"}
+		task    = &model.Task{UUID: uuid.New(), ListUUID: list.UUID, Title: "Paint the fence", Priority: types.TaskPriorityHigh}
+		step    = &model.Step{UUID: uuid.New(), TaskUUID: task.UUID, Description: "Buy paint", CompletedAt: &now}
+		tag     = &model.Tag{UUID: uuid.New(), OwnerUUID: ownerID, Name: "Outside"}
+		file    = &model.Attachment{UUID: uuid.New(), OwnerUUID: ownerID, TaskUUID: task.UUID, FileName: "fence.txt"}
+		page    = &types.Pagination{Page: 1, RPP: exportPageSize}
+	)
+	type services struct {
+		r           *mocks.ExportRepository
+		store       storage.BlobStore
+		mailer      *mail.MemoryMailer
+		users       *mocks.UserService
+		groups      *mocks.GroupServiceMock
+		lists       *mocks.ListService
+		tasks       *mocks.TaskServiceMock
+		steps       *mocks.StepServiceMock
+		tags        *mocks.TagServiceMock
+		attachments *mocks.AttachmentServiceMock
+	}
+	// setUp serves the tasks of the list, and the tasks given by the name of
+	// the method of the TaskService that retrieves them.
+	var setUp = func(t *testing.T, elsewhere map[string][]*model.Task) (*services, ExportService) {
+		var m = &services{
+			r:           mocks.NewExportRepositoryMock(),
+			store:       newBlobStore(t),
+			mailer:      mail.NewMemoryMailer(),
+			users:       mocks.NewUserServiceMock(),
+			groups:      mocks.NewGroupServiceMock(),
+			lists:       mocks.NewListServiceMock(),
+			tasks:       mocks.NewTaskServiceMock(),
+			steps:       mocks.NewStepServiceMock(),
+			tags:        mocks.NewTagServiceMock(),
+			attachments: mocks.NewAttachmentServiceMock(),
+		}
+		m.r.On("FetchPending", exportBatchSize).Return([]*model.Export{export}, nil)
+		m.users.On("FetchByID", ownerID).Return(profile, nil)
+		m.users.On("FetchSettings", ownerID, page, "", "").
+			Return(&types.Result[transfer.UserSetting]{Retrieved: 1, Payload: []*transfer.UserSetting{{Key: "timezone", Value: "UTC"}}}, nil)
+		m.groups.On("Fetch", ownerID, page, "", "").
+			Return(&types.Result[model.Group]{Retrieved: 1, Payload: []*model.Group{group}}, nil)
+		m.lists.On("Fetch", ownerID, page, "", "").
+			Return(&types.Result[model.List]{Retrieved: 1, Payload: []*model.List{list}}, nil)
+		m.tags.On("Fetch", ownerID, page, "", "").
+			Return(&types.Result[model.Tag]{Retrieved: 1, Payload: []*model.Tag{tag}}, nil)
+		m.tasks.On("Fetch", ownerID, list.UUID, page, "", "", (*types.TagFilter)(nil), uuid.Nil).
+			Return(&types.Result[model.Task]{Retrieved: 1, Payload: []*model.Task{task}}, nil)
+		for _, method := range []string{"FetchFromToday", "FetchFromTomorrow", "FetchFromDeferred", "FetchCompleted", "FetchArchived", "FetchAll", "FetchTrashed"} {
+			var tasks = elsewhere[method]
+			if nil == tasks {
+				tasks = []*model.Task{}
+			}
+			var arguments = []any{ownerID, page, "", ""}
+			if "FetchFromToday" == method || "FetchFromTomorrow" == method {
+				arguments = append(arguments, (*types.TagFilter)(nil))
+			}
+			m.tasks.On(method, arguments...).Return(&types.Result[model.Task]{Retrieved: int64(len(tasks)), Payload: tasks}, nil)
+		}
+		m.steps.On("Fetch", ownerID, task.UUID).Return([]*model.Step{step}, nil)
+		m.tags.On("FetchFromTask", ownerID, task.UUID).Return([]*model.Tag{tag}, nil)
+		m.attachments.On("Fetch", ownerID, task.UUID).Return([]*model.Attachment{file}, nil)
+		var s = NewExportService(m.r, m.store, m.mailer, 7*24*time.Hour,
+			m.users, m.groups, m.lists, m.tasks, m.steps, m.tags, m.attachments)
+		return m, s
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var m, s = setUp(t, nil)
+		var size int64
+		m.attachments.On("Open", ownerID, task.UUID, file.UUID).
+			Return(file, io.NopCloser(strings.NewReader("fresh paint")), nil)
+		m.r.On("MarkReady", export.UUID.String(), key, mock.Anything, now.Add(7*24*time.Hour)).
+			Run(func(args mock.Arguments) { size = args.Get(2).(int64) }).
+			Return(true, nil)
+		built, err := s.Build(now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, built)
+
+		content, err := m.store.Get(context.Background(), key)
+		require.NoError(t, err)
+		defer content.Close()
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, int64(len(data)), size)
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		var files = make(map[string]string)
+		for _, f := range archive.File {
+			r, err := f.Open()
+			require.NoError(t, err)
+			data, _ := io.ReadAll(r)
+			r.Close()
+			files[f.Name] = string(data)
+		}
+		for _, name := range []string{
+			"profile.json", "settings.json", "groups.json", "lists.json", "tasks.json", "trash.json",
+			"steps.json", "tags.json", "task_tags.json", "attachments.json", "export.md",
+		} {
+			assert.Contains(t, files, name)
+		}
+		assert.Contains(t, files["profile.json"], `"email": "jane@noda.com"`)
+		assert.Contains(t, files["task_tags.json"], tag.UUID.String())
+		assert.Contains(t, files["export.md"], "- [ ] **Paint the fence** (high priority) `#Outside`\n  - [x] Buy paint\n")
+		assert.Equal(t, "fresh paint", files["attachments/"+file.UUID.String()+"/fence.txt"])
+
+		var messages = m.mailer.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{"jane@noda.com"}, messages[0].To)
+		assert.Contains(t, messages[0].Body, "Tue, 07 Jan 2025 09:00:00 UTC")
+	})
+
+	t.Run("gathers every task once", func(t *testing.T) {
+		var (
+			today    = &model.Task{UUID: uuid.New(), ListUUID: uuid.New(), Title: "Call the painter"}
+			deferred = &model.Task{UUID: uuid.New(), ListUUID: list.UUID, Title: "Paint the shed"}
+			done     = &model.Task{UUID: uuid.New(), ListUUID: list.UUID, Title: "Sand the fence", Status: types.TaskStatusComplete}
+			trashed  = &model.Task{UUID: uuid.New(), ListUUID: list.UUID, Title: "Paint it black"}
+		)
+		var m, s = setUp(t, map[string][]*model.Task{
+			"FetchFromToday":    {today},
+			"FetchFromDeferred": {deferred},
+			"FetchCompleted":    {done},
+			"FetchAll":          {task, today, deferred, trashed},
+			"FetchTrashed":      {trashed},
+		})
+		m.steps.On("Fetch", ownerID, mock.Anything).Return([]*model.Step{}, nil)
+		m.tags.On("FetchFromTask", ownerID, mock.Anything).Return([]*model.Tag{}, nil)
+		m.attachments.On("Fetch", ownerID, mock.Anything).Return([]*model.Attachment{}, nil)
+		m.attachments.On("Open", ownerID, task.UUID, file.UUID).Return(nil, nil, failure.ErrAttachmentNotFound)
+		m.r.On("MarkReady", export.UUID.String(), key, mock.Anything, mock.Anything).Return(true, nil)
+		_, err := s.Build(now)
+		require.NoError(t, err)
+
+		content, err := m.store.Get(context.Background(), key)
+		require.NoError(t, err)
+		defer content.Close()
+		data, _ := io.ReadAll(content)
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		var read = func(name string) string {
+			f, err := archive.Open(name)
+			require.NoError(t, err)
+			defer f.Close()
+			data, _ := io.ReadAll(f)
+			return string(data)
+		}
+		var tasks, trash []*model.Task
+		require.NoError(t, json.Unmarshal([]byte(read("tasks.json")), &tasks))
+		require.NoError(t, json.Unmarshal([]byte(read("trash.json")), &trash))
+		var titles = make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			titles = append(titles, task.Title)
+		}
+		assert.Equal(t, []string{"Paint the fence", "Call the painter", "Paint the shed", "Sand the fence"}, titles)
+		if assert.Len(t, trash, 1) {
+			assert.Equal(t, trashed.UUID, trash[0].UUID)
+		}
+		var document = read("export.md")
+		assert.Contains(t, document, "- [x] **Sand the fence**")
+		assert.Contains(t, document, "## Other tasks\n\n- [ ] **Call the painter**\n")
+		assert.Contains(t, document, "## Trash\n\n- [ ] **Paint it black**\n")
+		m.steps.AssertCalled(t, "Fetch", ownerID, trashed.UUID)
+	})
+
+	t.Run("skips an attachment without a file", func(t *testing.T) {
+		var m, s = setUp(t, nil)
+		m.attachments.On("Open", ownerID, task.UUID, file.UUID).Return(nil, nil, failure.ErrAttachmentNotFound)
+		m.r.On("MarkReady", export.UUID.String(), key, mock.Anything, mock.Anything).Return(true, nil)
+		built, err := s.Build(now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, built)
+	})
+
+	t.Run("marks the export as failed", func(t *testing.T) {
+		var m, s = setUp(t, nil)
+		m.attachments.On("Open", ownerID, task.UUID, file.UUID).Return(nil, nil, errors.New("unexpected error"))
+		m.r.On("MarkFailed", export.UUID.String()).Return(true, nil)
+		built, err := s.Build(now)
+		assert.Error(t, err)
+		assert.Zero(t, built)
+		m.r.AssertCalled(t, "MarkFailed", export.UUID.String())
+		m.r.AssertNotCalled(t, "MarkReady", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, m.mailer.Messages())
+		_, err = m.store.Get(context.Background(), key)
+		assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+	})
+}
+
+func TestExportService_Purge(t *testing.T) {
+	defer beQuiet()()
+	var (
+		now     = time.Now()
+		store   = newBlobStore(t)
+		key     = "exports/archive.zip"
+		expired = &model.Export{UUID: uuid.New(), Status: types.ExportStatusReady, BlobKey: &key}
+		r       = mocks.NewExportRepositoryMock()
+	)
+	require.NoError(t, store.Put(context.Background(), key, strings.NewReader("PK"), 2, "application/zip"))
+	r.On("FetchExpired", now, purgeBatchSize).Return([]*model.Export{expired}, nil)
+	r.On("MarkExpired", expired.UUID.String()).Return(true, nil)
+	purged, err := NewExportService(r, store, nil, time.Hour, nil, nil, nil, nil, nil, nil, nil).Purge(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	_, err = store.Get(context.Background(), key)
+	assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+}
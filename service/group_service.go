@@ -18,11 +18,12 @@ type GroupService interface {
 }
 
 type groupService struct {
-	r repository.GroupRepository
+	r       repository.GroupRepository
+	members repository.MemberRepository
 }
 
-func NewGroupService(repository repository.GroupRepository) GroupService {
-	return &groupService{repository}
+func NewGroupService(repository repository.GroupRepository, members repository.MemberRepository) GroupService {
+	return &groupService{repository, members}
 }
 
 func (s *groupService) Save(ownerID uuid.UUID, newGroup *transfer.GroupCreation) (insertedID uuid.UUID, err error) {
@@ -41,6 +42,11 @@ func (s *groupService) Save(ownerID uuid.UUID, newGroup *transfer.GroupCreation)
 }
 
 func (s *groupService) FetchByID(ownerID, groupID uuid.UUID) (group *model.Group, err error) {
+	access, err := authorizeGroup(s.members, ownerID, groupID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	ownerID = access.OwnerUUID
 	return s.r.FetchByID(ownerID.String(), groupID.String())
 }
 
@@ -71,6 +77,11 @@ func (s *groupService) Update(ownerID, groupID uuid.UUID, up *transfer.GroupUpda
 	case 1<<9 < len(up.Description):
 		return false, failure.ErrTooLong.Clone().FormatDetails("description", "group", 1<<9)
 	}
+	access, err := authorizeGroup(s.members, ownerID, groupID, types.MemberRoleAdmin)
+	if nil != err {
+		return false, err
+	}
+	ownerID = access.OwnerUUID
 	return s.r.Update(ownerID.String(), groupID.String(), up)
 }
 
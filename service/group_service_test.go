@@ -26,7 +26,7 @@ func TestGroupService_SaveGroup(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("Save", ownerID.String(), next).
 			Return(ownerID.String(), nil)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Save(ownerID, next)
 		assert.Equal(t, ownerID, res)
 		assert.NoError(t, err)
@@ -37,7 +37,7 @@ func TestGroupService_SaveGroup(t *testing.T) {
 		next.Name = strings.Repeat("x", 1+32)
 		var m = mocks.NewGroupRepositoryMock()
 		m.AssertNotCalled(t, "Save")
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Save(ownerID, next)
 		assert.Equal(t, uuid.Nil, res)
 		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("name", "group", 32).Error())
@@ -49,7 +49,7 @@ func TestGroupService_SaveGroup(t *testing.T) {
 		next.Description = strings.Repeat("x", 1+512)
 		var m = mocks.NewGroupRepositoryMock()
 		m.AssertNotCalled(t, "Save")
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Save(ownerID, next)
 		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("description", "group", 512).Error())
 		assert.Equal(t, uuid.Nil, res)
@@ -60,7 +60,7 @@ func TestGroupService_SaveGroup(t *testing.T) {
 		unexpected := errors.New("unexpected error")
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("Save", ownerID.String(), next).Return("", unexpected)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Save(ownerID, next)
 		assert.Equal(t, uuid.Nil, res)
 		assert.ErrorIs(t, err, unexpected)
@@ -80,7 +80,7 @@ func TestGroupService_FindGroupByID(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("FetchByID", ownerID.String(), groupID.String()).
 			Return(current, nil)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.FetchByID(ownerID, groupID)
 		assert.Equal(t, current, res)
 		assert.NoError(t, err)
@@ -91,7 +91,7 @@ func TestGroupService_FindGroupByID(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("FetchByID", ownerID.String(), groupID.String()).
 			Return(nil, unexpected)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.FetchByID(ownerID, groupID)
 		assert.Nil(t, res)
 		assert.ErrorIs(t, err, unexpected)
@@ -118,7 +118,7 @@ func TestGroupService_FindGroups(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("Fetch", ownerID.String(), pag.Page, pag.RPP, "", "").
 			Return(groups, nil)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Fetch(ownerID, pag, "", "")
 		assert.Equal(t, current, res)
 		assert.NoError(t, err)
@@ -129,7 +129,7 @@ func TestGroupService_FindGroups(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("Fetch", ownerID.String(), pag.Page, pag.RPP, "", "").
 			Return(nil, unexpected)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Fetch(ownerID, pag, "", "")
 		assert.Nil(t, res)
 		assert.ErrorIs(t, err, unexpected)
@@ -149,7 +149,7 @@ func TestGroupService_UpdateGroup(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("Update", ownerID.String(), groupID.String(), up).
 			Return(true, nil)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, up)
 		assert.True(t, res)
 		assert.NoError(t, err)
@@ -160,7 +160,7 @@ func TestGroupService_UpdateGroup(t *testing.T) {
 		up.Name = strings.Repeat("x", 1+32)
 		var m = mocks.NewGroupRepositoryMock()
 		m.AssertNotCalled(t, "Update")
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, up)
 		assert.False(t, res)
 		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("name", "group", 32).Error())
@@ -172,7 +172,7 @@ func TestGroupService_UpdateGroup(t *testing.T) {
 		up.Description = strings.Repeat("x", 1+512)
 		var m = mocks.NewGroupRepositoryMock()
 		m.AssertNotCalled(t, "Update")
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, up)
 		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("description", "group", 512).Error())
 		assert.False(t, res)
@@ -184,11 +184,25 @@ func TestGroupService_UpdateGroup(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("Update", ownerID.String(), groupID.String(), up).
 			Return(false, unexpected)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, up)
 		assert.False(t, res)
 		assert.ErrorIs(t, err, unexpected)
 	})
+
+	t.Run("a viewer cannot update", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			m        = mocks.NewGroupRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchGroupAccess", memberID.String(), groupID.String()).
+			Return(&model.GroupAccess{GroupUUID: groupID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		res, err = NewGroupService(m, members).Update(memberID, groupID, up)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		m.AssertNotCalled(t, "Update")
+	})
 }
 
 func TestGroupService_DeleteGroup(t *testing.T) {
@@ -203,7 +217,7 @@ func TestGroupService_DeleteGroup(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("Remove", ownerID.String(), groupID.String()).
 			Return(true, nil)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Remove(ownerID, groupID)
 		assert.True(t, res)
 		assert.NoError(t, err)
@@ -214,7 +228,7 @@ func TestGroupService_DeleteGroup(t *testing.T) {
 		var m = mocks.NewGroupRepositoryMock()
 		m.On("Remove", ownerID.String(), groupID.String()).
 			Return(false, unexpected)
-		s = NewGroupService(m)
+		s = NewGroupService(m, soleOwner{})
 		res, err = s.Remove(ownerID, groupID)
 		assert.False(t, res)
 		assert.ErrorIs(t, err, unexpected)
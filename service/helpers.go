@@ -1,7 +1,15 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"noda/data/model"
 	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
 	"regexp"
 	"strings"
 )
@@ -32,3 +40,61 @@ func doDefaultPagination(pagination *types.Pagination) {
 		pagination.RPP = 10
 	}
 }
+
+// unfoldTagFilter converts a tag filter into the values expected by the
+// repositories. A nil filter or a filter without tags yields no tag IDs.
+func unfoldTagFilter(filter *types.TagFilter) (tagIDs []string, matchAll bool) {
+	if nil == filter || 0 == len(filter.Tags) {
+		return nil, false
+	}
+	tagIDs = make([]string, 0, len(filter.Tags))
+	for _, id := range filter.Tags {
+		tagIDs = append(tagIDs, id.String())
+	}
+	return tagIDs, filter.MatchAll
+}
+
+// generateOpaqueToken returns a token made of 32 random bytes, such as the
+// ones used to refresh a session or to reset a password.
+func generateOpaqueToken() (string, error) {
+	var buf = make([]byte, 32)
+	_, err := rand.Read(buf)
+	if nil != err {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashOpaqueToken hashes a token generated by generateOpaqueToken the way it
+// is stored. These tokens are random enough for a fast hash to be safe.
+func hashOpaqueToken(token string) string {
+	var sum = sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// authorizeList retrieves the access of the user to the list and makes sure
+// that it has at least the needed role. A list that is not shared with the user
+// is not found.
+func authorizeList(members repository.MemberRepository, userID, listID uuid.UUID, needed types.MemberRole) (access *model.ListAccess, err error) {
+	access, err = members.FetchListAccess(userID.String(), listID.String())
+	if nil != err {
+		return nil, err
+	}
+	if !access.Role.Grants(needed) {
+		return nil, failure.ErrInsufficientRole
+	}
+	return access, nil
+}
+
+// authorizeGroup retrieves the access of the user to the group and makes sure
+// that it has at least the needed role.
+func authorizeGroup(members repository.MemberRepository, userID, groupID uuid.UUID, needed types.MemberRole) (access *model.GroupAccess, err error) {
+	access, err = members.FetchGroupAccess(userID.String(), groupID.String())
+	if nil != err {
+		return nil, err
+	}
+	if !access.Role.Grants(needed) {
+		return nil, failure.ErrInsufficientRole
+	}
+	return access, nil
+}
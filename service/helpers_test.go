@@ -1,6 +1,7 @@
 package service
 
 import (
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"noda/data/types"
 	"testing"
@@ -46,3 +47,24 @@ func TestHelpers_doDefaultPagination(t *testing.T) {
 		doDefaultPagination(nil)
 	})
 }
+
+func TestHelpers_unfoldTagFilter(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var a, b = uuid.New(), uuid.New()
+		tagIDs, matchAll := unfoldTagFilter(&types.TagFilter{Tags: []uuid.UUID{a, b}, MatchAll: true})
+		assert.Equal(t, []string{a.String(), b.String()}, tagIDs)
+		assert.True(t, matchAll)
+	})
+
+	t.Run("no tags for nil parameter", func(t *testing.T) {
+		tagIDs, matchAll := unfoldTagFilter(nil)
+		assert.Nil(t, tagIDs)
+		assert.False(t, matchAll)
+	})
+
+	t.Run("no tags for an empty filter", func(t *testing.T) {
+		tagIDs, matchAll := unfoldTagFilter(&types.TagFilter{MatchAll: true})
+		assert.Nil(t, tagIDs)
+		assert.False(t, matchAll)
+	})
+}
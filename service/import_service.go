@@ -0,0 +1,403 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"noda/audit"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/importer"
+	"noda/repository"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ImportService makes the groups, lists, tasks, steps and tags of a file
+// exported by another app through the services that make them otherwise, so
+// that they are validated the same way.
+//
+// What an import makes is recorded under the key it has in the file, and an
+// import of the same data skips it, even if it has changed since. Tags are
+// told apart by their name, so the tags the user already has are used rather
+// than made again. In a dry run, nothing is made, and the report tells what
+// would be.
+type ImportService interface {
+	Import(ownerID uuid.UUID, format importer.Format, data io.Reader, dryRun bool) (report *model.ImportReport, err error)
+}
+
+// The kinds of things an import makes.
+const (
+	importKindGroup = "group"
+	importKindList  = "list"
+	importKindTask  = "task"
+	importKindTag   = "tag"
+)
+
+// importPageSize is how many tags are read at once to find those the user
+// already has.
+const importPageSize = 100
+
+type importService struct {
+	r      repository.ImportRepository
+	groups GroupService
+	lists  ListService
+	tasks  TaskService
+	steps  StepService
+	tags   TagService
+}
+
+func NewImportService(
+	r repository.ImportRepository,
+	groups GroupService,
+	lists ListService,
+	tasks TaskService,
+	steps StepService,
+	tags TagService,
+) ImportService {
+	return &importService{r: r, groups: groups, lists: lists, tasks: tasks, steps: steps, tags: tags}
+}
+
+// Within returns the service making its changes through the other services
+// as coming from the origin, so that they are audited as such.
+func (s *importService) Within(origin audit.Origin) ImportService {
+	var bound = *s
+	bound.groups = Within(s.groups, origin)
+	bound.lists = Within(s.lists, origin)
+	bound.tasks = Within(s.tasks, origin)
+	bound.steps = Within(s.steps, origin)
+	bound.tags = Within(s.tags, origin)
+	return &bound
+}
+
+// Import reads data in the given format and makes what it holds, skipping
+// what earlier imports from the format made. If some rows of the file cannot
+// be read, nothing is made and the error is failure.ErrBadImport, with a
+// detail per row. If some things are refused when they are made, such as a
+// task with a title too long, the others are still made, and the report of
+// what was made is returned along with failure.ErrIncompleteImport, both with
+// a detail per thing refused. Something that is made but cannot be recorded is
+// removed again and refused, so that the next import makes it once more
+// rather than twice.
+func (s *importService) Import(ownerID uuid.UUID, format importer.Format, data io.Reader, dryRun bool) (report *model.ImportReport, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Import", "ownerID")
+		log.Println(err)
+		return nil, err
+	case nil == data:
+		err = failure.NewNilParameterError("Import", "data")
+		log.Println(err)
+		return nil, err
+	}
+	batch, err := importer.Parse(format, data)
+	if nil != err {
+		return nil, failure.ErrBadImport.Clone().SetDetails(err.Error())
+	}
+	records, err := s.r.FetchRecords(ownerID.String(), string(format))
+	if nil != err {
+		return nil, err
+	}
+	existingTags, err := s.fetchTags(ownerID)
+	if nil != err {
+		return nil, err
+	}
+	var run = &importRun{
+		s:        s,
+		ownerID:  ownerID,
+		format:   string(format),
+		dryRun:   dryRun,
+		imported: make(map[string]uuid.UUID, len(records)),
+		groups:   make(map[*importer.Group]uuid.UUID, len(batch.Groups)),
+		lists:    make(map[*importer.List]uuid.UUID, len(batch.Lists)),
+		tags:     make(map[*importer.Tag]uuid.UUID, len(batch.Tags)),
+		report:   &model.ImportReport{DryRun: dryRun, Items: make([]*model.ImportItem, 0)},
+	}
+	for _, record := range records {
+		run.imported[record.Kind+"\x00"+record.Key] = record.EntityUUID
+	}
+	for _, group := range batch.Groups {
+		if err = run.makeGroup(group); nil != err {
+			return nil, err
+		}
+	}
+	for _, list := range batch.Lists {
+		if err = run.makeList(list); nil != err {
+			return nil, err
+		}
+	}
+	for _, tag := range batch.Tags {
+		if err = run.makeTag(tag, existingTags); nil != err {
+			return nil, err
+		}
+	}
+	for _, task := range batch.Tasks {
+		if err = run.makeTask(task); nil != err {
+			return nil, err
+		}
+	}
+	if run.problems.Has() {
+		return run.report, failure.ErrIncompleteImport.Clone().SetDetails(run.problems.Error())
+	}
+	return run.report, nil
+}
+
+// fetchTags retrieves the tags of the user by their name, in lowercase.
+func (s *importService) fetchTags(ownerID uuid.UUID) (tags map[string]uuid.UUID, err error) {
+	tags = make(map[string]uuid.UUID)
+	for page := int64(1); ; page++ {
+		result, err := s.tags.Fetch(ownerID, &types.Pagination{Page: page, RPP: importPageSize}, "", "")
+		if nil != err {
+			return nil, err
+		}
+		for _, tag := range result.Payload {
+			tags[strings.ToLower(tag.Name)] = tag.UUID
+		}
+		if result.Retrieved < importPageSize {
+			return tags, nil
+		}
+	}
+}
+
+// importRun is the state of an import. The groups, lists and tags that could
+// not be made are missing from their maps, and those that would be made in a
+// dry run are there with a nil UUID.
+type importRun struct {
+	s        *importService
+	ownerID  uuid.UUID
+	format   string
+	dryRun   bool
+	imported map[string]uuid.UUID
+	groups   map[*importer.Group]uuid.UUID
+	lists    map[*importer.List]uuid.UUID
+	tags     map[*importer.Tag]uuid.UUID
+	report   *model.ImportReport
+	problems failure.AggregateDetails
+}
+
+func (run *importRun) makeGroup(group *importer.Group) error {
+	if id, ok := run.imported[importKindGroup+"\x00"+group.Key]; ok {
+		run.groups[group] = id
+		run.report.Skipped.Groups++
+		run.add(importKindGroup, group.Name, true, id)
+		return nil
+	}
+	run.report.Created.Groups++
+	if run.dryRun {
+		run.groups[group] = uuid.Nil
+		run.add(importKindGroup, group.Name, false, uuid.Nil)
+		return nil
+	}
+	var ref = fmt.Sprintf("Group %q", group.Name)
+	id, err := run.s.groups.Save(run.ownerID, &transfer.GroupCreation{Name: group.Name, Description: group.Description})
+	if nil != err {
+		run.report.Created.Groups--
+		return run.refuse(ref, err)
+	}
+	err = run.s.r.SaveRecord(run.ownerID.String(), run.format, importKindGroup, group.Key, id.String())
+	if nil != err {
+		run.report.Created.Groups--
+		run.unmake(ref, err, func() error {
+			_, err := run.s.groups.Remove(run.ownerID, id)
+			return err
+		})
+		return nil
+	}
+	run.groups[group] = id
+	run.add(importKindGroup, group.Name, false, id)
+	return nil
+}
+
+// makeList makes a list, unless its group could not be made.
+func (run *importRun) makeList(list *importer.List) error {
+	var groupID = uuid.Nil
+	if nil != list.Group {
+		var ok bool
+		if groupID, ok = run.groups[list.Group]; !ok {
+			return nil
+		}
+	}
+	if id, ok := run.imported[importKindList+"\x00"+list.Key]; ok {
+		run.lists[list] = id
+		run.report.Skipped.Lists++
+		run.add(importKindList, list.Name, true, id)
+		return nil
+	}
+	run.report.Created.Lists++
+	if run.dryRun {
+		run.lists[list] = uuid.Nil
+		run.add(importKindList, list.Name, false, uuid.Nil)
+		return nil
+	}
+	var ref = fmt.Sprintf("List %q", list.Name)
+	id, err := run.s.lists.Save(run.ownerID, groupID, &transfer.ListCreation{Name: list.Name, Description: list.Description})
+	if nil != err {
+		run.report.Created.Lists--
+		return run.refuse(ref, err)
+	}
+	err = run.s.r.SaveRecord(run.ownerID.String(), run.format, importKindList, list.Key, id.String())
+	if nil != err {
+		run.report.Created.Lists--
+		run.unmake(ref, err, func() error {
+			return run.s.lists.Remove(run.ownerID, groupID, id)
+		})
+		return nil
+	}
+	run.lists[list] = id
+	run.add(importKindList, list.Name, false, id)
+	return nil
+}
+
+// makeTag makes a tag, unless the user already has one with its name.
+func (run *importRun) makeTag(tag *importer.Tag, existing map[string]uuid.UUID) error {
+	if id, ok := existing[strings.ToLower(tag.Name)]; ok {
+		run.tags[tag] = id
+		run.report.Skipped.Tags++
+		run.add(importKindTag, tag.Name, true, id)
+		return nil
+	}
+	run.report.Created.Tags++
+	if run.dryRun {
+		run.tags[tag] = uuid.Nil
+		run.add(importKindTag, tag.Name, false, uuid.Nil)
+		return nil
+	}
+	id, err := run.s.tags.Save(run.ownerID, &transfer.TagCreation{Name: tag.Name, Color: tag.Color})
+	if nil != err {
+		run.report.Created.Tags--
+		return run.refuse(fmt.Sprintf("Tag %q", tag.Name), err)
+	}
+	run.tags[tag] = id
+	run.add(importKindTag, tag.Name, false, id)
+	return nil
+}
+
+// makeTask makes a task, unless its list could not be made, and then sets
+// what cannot be set when a task is made: its due date, its reminder, its
+// steps, its tags and its status.
+func (run *importRun) makeTask(task *importer.Task) error {
+	var listID, ok = run.lists[task.List]
+	if !ok {
+		return nil
+	}
+	if id, ok := run.imported[importKindTask+"\x00"+task.Key]; ok {
+		run.report.Skipped.Tasks++
+		run.add(importKindTask, task.Title, true, id)
+		return nil
+	}
+	if run.dryRun {
+		run.report.Created.Tasks++
+		run.report.Created.Steps += len(task.Steps)
+		run.add(importKindTask, task.Title, false, uuid.Nil)
+		return nil
+	}
+	id, err := run.s.tasks.Save(run.ownerID, listID, &transfer.TaskCreation{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+	})
+	if nil != err {
+		return run.refuse(task.Ref, err)
+	}
+	err = run.s.r.SaveRecord(run.ownerID.String(), run.format, importKindTask, task.Key, id.String())
+	if nil != err {
+		run.unmake(task.Ref, err, func() error {
+			return run.s.tasks.Delete(run.ownerID, listID, id)
+		})
+		return nil
+	}
+	run.report.Created.Tasks++
+	run.add(importKindTask, task.Title, false, id)
+	if nil != task.DueDate {
+		if _, err = run.s.tasks.SetDueDate(run.ownerID, listID, id, *task.DueDate); nil != err {
+			if err = run.refuse(task.Ref, err); nil != err {
+				return err
+			}
+		}
+	}
+	if nil != task.RemindAt {
+		if _, err = run.s.tasks.SetReminder(run.ownerID, listID, id, *task.RemindAt); nil != err {
+			if err = run.refuse(task.Ref, err); nil != err {
+				return err
+			}
+		}
+	}
+	for _, step := range task.Steps {
+		if err = run.makeStep(task, id, step); nil != err {
+			return err
+		}
+	}
+	for _, tag := range task.Tags {
+		var tagID, ok = run.tags[tag]
+		if !ok {
+			continue
+		}
+		if _, err = run.s.tags.Attach(run.ownerID, id, tagID); nil != err {
+			if err = run.refuse(task.Ref, err); nil != err {
+				return err
+			}
+		}
+	}
+	if task.Completed {
+		if _, err = run.s.tasks.Complete(run.ownerID, listID, id); nil != err {
+			return run.refuse(task.Ref, err)
+		}
+	}
+	return nil
+}
+
+func (run *importRun) makeStep(task *importer.Task, taskID uuid.UUID, step *importer.Step) error {
+	id, err := run.s.steps.Save(run.ownerID, taskID, &transfer.StepCreation{Description: step.Description})
+	if nil != err {
+		return run.refuse(task.Ref, err)
+	}
+	run.report.Created.Steps++
+	if step.Done {
+		if _, err = run.s.steps.Accomplish(run.ownerID, taskID, id); nil != err {
+			return run.refuse(task.Ref, err)
+		}
+	}
+	return nil
+}
+
+func (run *importRun) add(kind, name string, skipped bool, id uuid.UUID) {
+	var item = &model.ImportItem{Kind: kind, Name: name, Skipped: skipped}
+	if uuid.Nil != id {
+		item.UUID = &id
+	}
+	run.report.Items = append(run.report.Items, item)
+}
+
+// refuse reports a failure.Error as a problem of the thing ref tells of, and
+// returns any other error, which stops the import.
+func (run *importRun) refuse(ref string, err error) error {
+	var e *failure.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	var detail = e.Details()
+	if "" == detail {
+		detail = e.Message()
+	}
+	run.complain(ref + ": " + detail)
+	return nil
+}
+
+// unmake removes, with remove, the thing ref tells of, which was made but
+// whose record could not be saved, and reports it as a problem.
+func (run *importRun) unmake(ref string, err error, remove func() error) {
+	log.Printf("could not record %s: %v", ref, err)
+	if err = remove(); nil != err {
+		log.Printf("could not remove %s, which was not recorded: %v", ref, err)
+	}
+	run.complain(ref + ": It could not be recorded, so it was not imported.")
+}
+
+func (run *importRun) complain(problem string) {
+	run.problems.Append(problem)
+	run.report.Problems = append(run.report.Problems, problem)
+}
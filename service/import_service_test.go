@@ -0,0 +1,181 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/audit"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/importer"
+	"noda/mocks"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportService_Import(t *testing.T) {
+	defer beQuiet()()
+	const file = "id,group,list,title,priority,completed,due_date,tags,steps\n" +
+		"F-1,Home,Chores,Paint the fence,high,true,2024-12-31,Outside,[x] Buy paint\n"
+	var (
+		userID                  = uuid.New()
+		groupID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		tagID, stepID           = uuid.New(), uuid.New()
+		due                     = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+		format                  = importer.FormatCSV
+	)
+	type services struct {
+		r      *mocks.ImportRepository
+		groups *mocks.GroupServiceMock
+		lists  *mocks.ListService
+		tasks  *mocks.TaskServiceMock
+		steps  *mocks.StepServiceMock
+		tags   *mocks.TagServiceMock
+	}
+	var setUp = func(records []*model.ImportRecord, tags ...*model.Tag) (*services, ImportService) {
+		var m = &services{
+			r:      mocks.NewImportRepositoryMock(),
+			groups: mocks.NewGroupServiceMock(),
+			lists:  mocks.NewListServiceMock(),
+			tasks:  mocks.NewTaskServiceMock(),
+			steps:  mocks.NewStepServiceMock(),
+			tags:   mocks.NewTagServiceMock(),
+		}
+		m.r.On("FetchRecords", userID.String(), "csv").Return(records, nil)
+		m.tags.On("Fetch", userID, &types.Pagination{Page: 1, RPP: importPageSize}, "", "").
+			Return(&types.Result[model.Tag]{Retrieved: int64(len(tags)), Payload: tags}, nil)
+		return m, NewImportService(m.r, m.groups, m.lists, m.tasks, m.steps, m.tags)
+	}
+
+	t.Run("makes everything", func(t *testing.T) {
+		var m, s = setUp([]*model.ImportRecord{})
+		m.groups.On("Save", userID, &transfer.GroupCreation{Name: "Home"}).Return(groupID, nil)
+		m.lists.On("Save", userID, groupID, &transfer.ListCreation{Name: "Chores"}).Return(listID, nil)
+		m.tags.On("Save", userID, &transfer.TagCreation{Name: "Outside", Color: "#808080"}).Return(tagID, nil)
+		m.tasks.On("Save", userID, listID, &transfer.TaskCreation{Title: "Paint the fence", Priority: types.TaskPriorityHigh}).
+			Return(taskID, nil)
+		m.tasks.On("SetDueDate", userID, listID, taskID, due).Return(true, nil)
+		m.steps.On("Save", userID, taskID, &transfer.StepCreation{Description: "Buy paint"}).Return(stepID, nil)
+		m.steps.On("Accomplish", userID, taskID, stepID).Return(true, nil)
+		m.tags.On("Attach", userID, taskID, tagID).Return(true, nil)
+		m.tasks.On("Complete", userID, listID, taskID).Return(true, nil)
+		for kind, ids := range map[string][2]string{
+			"group": {"Home", groupID.String()},
+			"list":  {"Home/Chores", listID.String()},
+			"task":  {"F-1", taskID.String()},
+		} {
+			m.r.On("SaveRecord", userID.String(), "csv", kind, ids[0], ids[1]).Return(nil)
+		}
+		report, err := s.Import(userID, format, strings.NewReader(file), false)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ImportCounts{Groups: 1, Lists: 1, Tasks: 1, Steps: 1, Tags: 1}, report.Created)
+		assert.Equal(t, model.ImportCounts{}, report.Skipped)
+		assert.Equal(t, []*model.ImportItem{
+			{Kind: "group", Name: "Home", UUID: &groupID},
+			{Kind: "list", Name: "Chores", UUID: &listID},
+			{Kind: "tag", Name: "Outside", UUID: &tagID},
+			{Kind: "task", Name: "Paint the fence", UUID: &taskID},
+		}, report.Items)
+		for _, o := range []*mock.Mock{&m.r.Mock, &m.groups.Mock, &m.lists.Mock, &m.tasks.Mock, &m.steps.Mock, &m.tags.Mock} {
+			o.AssertExpectations(t)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		var m, s = setUp([]*model.ImportRecord{})
+		report, err := s.Import(userID, format, strings.NewReader(file), true)
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, model.ImportCounts{Groups: 1, Lists: 1, Tasks: 1, Steps: 1, Tags: 1}, report.Created)
+		assert.Len(t, report.Items, 4)
+		assert.Nil(t, report.Items[3].UUID)
+		m.groups.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		m.tasks.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+		m.r.AssertNotCalled(t, "SaveRecord", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("skips what was imported", func(t *testing.T) {
+		var m, s = setUp([]*model.ImportRecord{
+			{Kind: "group", Key: "Home", EntityUUID: groupID},
+			{Kind: "list", Key: "Home/Chores", EntityUUID: listID},
+			{Kind: "task", Key: "F-1", EntityUUID: taskID},
+		}, &model.Tag{UUID: tagID, Name: "outside"})
+		report, err := s.Import(userID, format, strings.NewReader(file), false)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ImportCounts{}, report.Created)
+		assert.Equal(t, model.ImportCounts{Groups: 1, Lists: 1, Tasks: 1, Tags: 1}, report.Skipped)
+		m.groups.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		m.tags.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		m.tasks.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("bad rows", func(t *testing.T) {
+		var _, s = setUp([]*model.ImportRecord{})
+		report, err := s.Import(userID, format, strings.NewReader("title,priority\nPaint the fence,soon\n"), false)
+		assert.ErrorContains(t, err, failure.ErrBadImport.Clone().
+			SetDetails(`["Row 2: The priority \"soon\" is not one of urgent, high, medium, normal or low."]`).Error())
+		assert.Nil(t, report)
+	})
+
+	t.Run("refused tasks", func(t *testing.T) {
+		var m, s = setUp([]*model.ImportRecord{{Kind: "list", Key: "Imported", EntityUUID: listID}})
+		var tooLong = failure.ErrTooLong.Clone().FormatDetails("title", "task", 128)
+		m.tasks.On("Save", userID, listID, &transfer.TaskCreation{Title: strings.Repeat("a", 129), Priority: types.TaskPriorityNormal}).
+			Return(uuid.Nil, tooLong)
+		m.tasks.On("Save", userID, listID, &transfer.TaskCreation{Title: "Buy paint", Priority: types.TaskPriorityNormal}).
+			Return(taskID, nil)
+		m.r.On("SaveRecord", userID.String(), "csv", "task", "Imported/Buy paint", taskID.String()).Return(nil)
+		report, err := s.Import(userID, format, strings.NewReader("title\n"+strings.Repeat("a", 129)+"\nBuy paint\n"), false)
+		assert.ErrorContains(t, err, failure.ErrIncompleteImport.Clone().
+			SetDetails(`["Row 2: `+strings.ReplaceAll(tooLong.Details(), `"`, `\"`)+`"]`).Error())
+		if assert.NotNil(t, report) {
+			assert.Equal(t, model.ImportCounts{Tasks: 1}, report.Created)
+			assert.Equal(t, []string{"Row 2: " + tooLong.Details()}, report.Problems)
+			assert.Equal(t, &model.ImportItem{Kind: "task", Name: "Buy paint", UUID: &taskID}, report.Items[len(report.Items)-1])
+		}
+		m.tasks.AssertExpectations(t)
+		m.r.AssertExpectations(t)
+	})
+
+	t.Run("removes what it could not record", func(t *testing.T) {
+		var m, s = setUp([]*model.ImportRecord{{Kind: "list", Key: "Imported", EntityUUID: listID}})
+		var unexpected = errors.New("unexpected error")
+		m.groups.On("Save", userID, &transfer.GroupCreation{Name: "Home"}).Return(groupID, nil)
+		m.groups.On("Remove", userID, groupID).Return(true, nil)
+		m.tasks.On("Save", userID, listID, &transfer.TaskCreation{Title: "Buy paint", Priority: types.TaskPriorityNormal}).
+			Return(taskID, nil)
+		m.tasks.On("Delete", userID, listID, taskID).Return(nil)
+		m.r.On("SaveRecord", userID.String(), "csv", "group", "Home", groupID.String()).Return(unexpected)
+		m.r.On("SaveRecord", userID.String(), "csv", "task", "Imported/Buy paint", taskID.String()).Return(unexpected)
+		report, err := s.Import(userID, format, strings.NewReader("group,list,title\nHome,Chores,Paint the fence\n,,Buy paint\n"), false)
+		assert.ErrorContains(t, err, failure.ErrIncompleteImport.Clone().SetDetails(
+			`["Group \"Home\": It could not be recorded, so it was not imported.",`+
+				`"Row 3: It could not be recorded, so it was not imported."]`).Error())
+		if assert.NotNil(t, report) {
+			assert.Equal(t, model.ImportCounts{}, report.Created)
+			assert.Equal(t, model.ImportCounts{Lists: 1}, report.Skipped)
+			assert.Len(t, report.Problems, 2)
+		}
+		m.groups.AssertExpectations(t)
+		m.tasks.AssertExpectations(t)
+		m.lists.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+		m.tasks.AssertNotCalled(t, "SetDueDate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestImportService_Within(t *testing.T) {
+	var (
+		origin = audit.Origin{IP: "10.0.0.7", UserAgent: "curl/8.0"}
+		lists  = NewAuditedListService(mocks.NewListServiceMock(), nil, nil)
+		tasks  = NewAuditedTaskService(mocks.NewTaskServiceMock(), nil)
+		s      = NewImportService(nil, mocks.NewGroupServiceMock(), lists, tasks, nil, nil)
+	)
+	var bound = Within(s, origin).(*importService)
+	assert.Equal(t, origin, bound.lists.(*auditedListService).origin)
+	assert.Equal(t, origin, bound.tasks.(*auditedTaskService).origin)
+	assert.Equal(t, audit.Origin{}, s.(*importService).tasks.(*auditedTaskService).origin)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+)
+
+type JobRunService interface {
+	Fetch(pagination *types.Pagination, job string) (result *types.Result[model.JobRun], err error)
+}
+
+type jobRunService struct {
+	r repository.JobRunRepository
+}
+
+func NewJobRunService(r repository.JobRunRepository) JobRunService {
+	return &jobRunService{r}
+}
+
+func (s *jobRunService) Fetch(pagination *types.Pagination, job string) (result *types.Result[model.JobRun], err error) {
+	if nil == pagination {
+		err = failure.NewNilParameterError("Fetch", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doTrim(&job)
+	doDefaultPagination(pagination)
+	runs, err := s.r.Fetch(pagination.Page, pagination.RPP, job)
+	if nil != err {
+		return nil, err
+	}
+	result = &types.Result[model.JobRun]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(runs)),
+		Payload:   runs,
+	}
+	return result, nil
+}
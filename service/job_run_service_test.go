@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+)
+
+func TestJobRunService_Fetch(t *testing.T) {
+	defer beQuiet()()
+	var (
+		s   JobRunService
+		res *types.Result[model.JobRun]
+		err error
+		job = blankset + "rollover" + blankset
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pag  = &types.Pagination{}
+			runs = []*model.JobRun{{}, {}}
+			m    = mocks.NewJobRunRepositoryMock()
+		)
+		m.On("Fetch", int64(1), int64(10), "rollover").Return(runs, nil)
+		s = NewJobRunService(m)
+		res, err = s.Fetch(pag, job)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.JobRun]{
+			Page:      1,
+			RPP:       10,
+			Retrieved: 2,
+			Payload:   runs,
+		}, res)
+	})
+
+	t.Run("nil pagination", func(t *testing.T) {
+		s = NewJobRunService(mocks.NewJobRunRepositoryMock())
+		res, err = s.Fetch(nil, job)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "pagination").Error())
+		assert.Nil(t, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var (
+			unexpected = errors.New("unexpected error")
+			m          = mocks.NewJobRunRepositoryMock()
+		)
+		m.On("Fetch", int64(1), int64(10), "rollover").Return(nil, unexpected)
+		s = NewJobRunService(m)
+		res, err = s.Fetch(&types.Pagination{}, job)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Nil(t, res)
+	})
+}
@@ -27,11 +27,12 @@ type ListService interface {
 }
 
 type listService struct {
-	r repository.ListRepository
+	r       repository.ListRepository
+	members repository.MemberRepository
 }
 
-func NewListService(r repository.ListRepository) ListService {
-	return &listService{r}
+func NewListService(r repository.ListRepository, members repository.MemberRepository) ListService {
+	return &listService{r, members}
 }
 
 func (s *listService) Save(ownerID, groupID uuid.UUID, creation *transfer.ListCreation) (insertedID uuid.UUID, err error) {
@@ -76,6 +77,17 @@ func (s *listService) FetchByID(ownerID, groupID, listID uuid.UUID) (list *model
 		log.Println(err)
 		return nil, err
 	}
+	access, err := authorizeList(s.members, ownerID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	if access.OwnerUUID != ownerID {
+		/* A member does not know about the groups of the owner.  */
+		ownerID, groupID = access.OwnerUUID, uuid.Nil
+		if nil != access.GroupUUID {
+			groupID = *access.GroupUUID
+		}
+	}
 	return s.r.FetchByID(ownerID.String(), groupID.String(), listID.String())
 }
 
@@ -152,6 +164,11 @@ func (s *listService) FetchGrouped(
 		log.Println(err)
 		return nil, err
 	}
+	access, err := authorizeGroup(s.members, ownerID, groupID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	ownerID = access.OwnerUUID
 	doTrim(&needle, &sortExpr)
 	doDefaultPagination(pagination)
 	res, err := s.r.FetchGrouped(ownerID.String(), groupID.String(), pagination.Page, pagination.RPP, needle, sortExpr)
@@ -293,5 +310,15 @@ func (s *listService) Update(ownerID, groupID, listID uuid.UUID, up *transfer.Li
 	case 1<<9 < len(up.Description):
 		return false, failure.ErrTooLong.Clone().FormatDetails("description", "list", 1<<9)
 	}
+	access, err := authorizeList(s.members, ownerID, listID, types.MemberRoleAdmin)
+	if nil != err {
+		return false, err
+	}
+	if access.OwnerUUID != ownerID {
+		ownerID, groupIDStr = access.OwnerUUID, ""
+		if nil != access.GroupUUID {
+			groupIDStr = access.GroupUUID.String()
+		}
+	}
 	return s.r.Update(ownerID.String(), groupIDStr, listID.String(), up)
 }
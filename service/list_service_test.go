@@ -32,7 +32,7 @@ func TestListService_Save(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Save", ownerID.String(), groupID.String(), next).
 			Return(insertedID.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, next)
 		assert.Equal(t, insertedID, res)
 		assert.NoError(t, err)
@@ -43,7 +43,7 @@ func TestListService_Save(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Save", ownerID.String(), "", next).
 			Return(insertedID.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, uuid.Nil, next)
 		assert.Equal(t, insertedID, res)
 		assert.NoError(t, err)
@@ -53,7 +53,7 @@ func TestListService_Save(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Save", mock.Anything, mock.Anything, mock.Anything).
 			Return("x", nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, next)
 		assert.ErrorContains(t, err, "invalid UUID length: 1")
 		assert.Equal(t, uuid.Nil, res)
@@ -64,7 +64,7 @@ func TestListService_Save(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Save", mock.Anything, mock.Anything, mock.Anything).
 			Return(parsed.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, next)
 		assert.Equal(t, parsed, res)
 		assert.NoError(t, err)
@@ -75,7 +75,7 @@ func TestListService_Save(t *testing.T) {
 		next.Name = "  		  \n"
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Save")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, next)
 		next.Name = previousName
 		assert.ErrorContains(t, err, "name cannot be an empty string")
@@ -85,7 +85,7 @@ func TestListService_Save(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Save")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(uuid.Nil, groupID, next)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Save", "ownerID").Error())
@@ -95,7 +95,7 @@ func TestListService_Save(t *testing.T) {
 	t.Run("parameter next cannot be nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Save")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, nil)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Save", "creation").Error())
@@ -107,7 +107,7 @@ func TestListService_Save(t *testing.T) {
 		next.Name = strings.Repeat("x", 1+32)
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Save")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, next)
 		next.Name = previousName
 		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("name", "list", 32).Error())
@@ -119,7 +119,7 @@ func TestListService_Save(t *testing.T) {
 		next.Description = strings.Repeat("x", 1+512)
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Save")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, next)
 		next.Description = description
 		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("description", "list", 512).Error())
@@ -131,10 +131,10 @@ func TestListService_Save(t *testing.T) {
 		var insertedID = uuid.New()
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Save")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		m.On("Save", mock.Anything, mock.Anything, mock.Anything).
 			Return(insertedID.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, next)
 		assert.Equal(t, "list name", next.Name)
 		assert.Equal(t, "description", next.Description)
@@ -148,7 +148,7 @@ func TestListService_Save(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Save", mock.Anything, mock.Anything, mock.Anything).
 			Return("", unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Save(ownerID, groupID, next)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Equal(t, uuid.Nil, res)
@@ -175,7 +175,7 @@ func TestListService_FetchByID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("FetchByID", ownerID.String(), groupID.String(), listID.String()).
 			Return(actual, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchByID(ownerID, groupID, listID)
 		assert.NoError(t, err)
 		assert.Equal(t, actual, res)
@@ -185,7 +185,7 @@ func TestListService_FetchByID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("FetchByID", ownerID.String(), uuid.Nil.String(), listID.String()).
 			Return(actual, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchByID(ownerID, uuid.Nil, listID)
 		assert.NoError(t, err)
 		assert.Equal(t, actual, res)
@@ -194,7 +194,7 @@ func TestListService_FetchByID(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "FetchByID")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchByID(uuid.Nil, groupID, listID)
 		assert.Nil(t, res)
 		assert.ErrorContains(t, err,
@@ -204,7 +204,7 @@ func TestListService_FetchByID(t *testing.T) {
 	t.Run("parameter listID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "FetchByID")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchByID(ownerID, groupID, uuid.Nil)
 		assert.Nil(t, res)
 		assert.ErrorContains(t, err,
@@ -216,11 +216,25 @@ func TestListService_FetchByID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("FetchByID", mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchByID(ownerID, groupID, listID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
 	})
+
+	t.Run("a member fetches the list from its owner", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			m        = mocks.NewListRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, GroupUUID: &groupID, Role: types.MemberRoleViewer}, nil)
+		m.On("FetchByID", ownerID.String(), groupID.String(), listID.String()).Return(actual, nil)
+		res, err = NewListService(m, members).FetchByID(memberID, uuid.Nil, listID)
+		assert.NoError(t, err)
+		assert.Equal(t, actual, res)
+	})
 }
 
 func TestListService_GetTodayListID(t *testing.T) {
@@ -236,7 +250,7 @@ func TestListService_GetTodayListID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("GetTodayListID", mock.Anything).
 			Return(listID.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTodayListID(ownerID)
 		assert.Equal(t, listID, res)
 		assert.NoError(t, err)
@@ -246,7 +260,7 @@ func TestListService_GetTodayListID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("GetTodayListID", mock.Anything).
 			Return("x", nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTodayListID(ownerID)
 		assert.ErrorContains(t, err, "invalid UUID length: 1")
 		assert.Equal(t, uuid.Nil, res)
@@ -257,7 +271,7 @@ func TestListService_GetTodayListID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("GetTodayListID", mock.Anything).
 			Return(id.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTodayListID(ownerID)
 		assert.Equal(t, id, res)
 		assert.NoError(t, err)
@@ -266,7 +280,7 @@ func TestListService_GetTodayListID(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "GetTodayListID")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTodayListID(uuid.Nil)
 		assert.Equal(t, uuid.Nil, res)
 		assert.ErrorContains(t, err,
@@ -278,7 +292,7 @@ func TestListService_GetTodayListID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("GetTodayListID", mock.Anything).
 			Return("", unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTodayListID(ownerID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Equal(t, uuid.Nil, res)
@@ -298,7 +312,7 @@ func TestListService_GetTomorrowListID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("GetTomorrowListID", mock.Anything).
 			Return(listID.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTomorrowListID(ownerID)
 		assert.Equal(t, listID, res)
 		assert.NoError(t, err)
@@ -308,7 +322,7 @@ func TestListService_GetTomorrowListID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("GetTomorrowListID", mock.Anything).
 			Return("x", nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTomorrowListID(ownerID)
 		assert.ErrorContains(t, err, "invalid UUID length: 1")
 		assert.Equal(t, uuid.Nil, res)
@@ -319,7 +333,7 @@ func TestListService_GetTomorrowListID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("GetTomorrowListID", mock.Anything).
 			Return(id.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTomorrowListID(ownerID)
 		assert.Equal(t, id, res)
 		assert.NoError(t, err)
@@ -328,7 +342,7 @@ func TestListService_GetTomorrowListID(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "GetTomorrowListID")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTomorrowListID(uuid.Nil)
 		assert.Equal(t, uuid.Nil, res)
 		assert.ErrorContains(t, err,
@@ -340,7 +354,7 @@ func TestListService_GetTomorrowListID(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("GetTomorrowListID", mock.Anything).
 			Return("", unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.GetTomorrowListID(ownerID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Equal(t, uuid.Nil, res)
@@ -369,7 +383,7 @@ func TestListService_Fetch(t *testing.T) {
 		m.On("Fetch",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Fetch(ownerID, pagination, "", "")
 		assert.Equal(t, current, res)
 		assert.NoError(t, err)
@@ -378,7 +392,7 @@ func TestListService_Fetch(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Fetch")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Fetch(uuid.Nil, pagination, "", "")
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Fetch", "ownerID").Error())
@@ -388,7 +402,7 @@ func TestListService_Fetch(t *testing.T) {
 	t.Run("parameter pagination cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Fetch")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Fetch(ownerID, nil, "", "")
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Fetch", "pagination").Error())
@@ -401,7 +415,7 @@ func TestListService_Fetch(t *testing.T) {
 		m.On("Fetch",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Fetch(ownerID, pagination, "", "")
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
@@ -430,7 +444,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 		m.On("FetchGrouped",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, pagination, "", "")
 		assert.Equal(t, current, res)
 		assert.NoError(t, err)
@@ -439,7 +453,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "FetchGrouped")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(uuid.Nil, groupID, pagination, "", "")
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("FetchGrouped", "ownerID").Error())
@@ -449,7 +463,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 	t.Run("parameter groupID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "FetchGrouped")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, uuid.Nil, pagination, "", "")
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("FetchGrouped", "groupID").Error())
@@ -459,7 +473,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 	t.Run("parameter pagination cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "FetchGrouped")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, nil, "", "")
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("FetchGrouped", "pagination").Error())
@@ -476,7 +490,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 			ownerID.String(), groupID.String(), pagination.Page, pagination.RPP,
 			strings.Trim(needle, " \n\t"), "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, pagination, needle, "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -495,7 +509,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 		m.On("FetchGrouped",
 			ownerID.String(), groupID.String(), expectedPageNumber, pag.RPP, "", "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, pag, "", "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -507,7 +521,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 		m.On("FetchGrouped",
 			ownerID.String(), groupID.String(), expectedPageNumber, pag.RPP, "", "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, pag, "", "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -526,7 +540,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 		m.On("FetchGrouped",
 			ownerID.String(), groupID.String(), pag.Page, expectedRPPNumber, "", "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, pag, "", "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -538,7 +552,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 		m.On("FetchGrouped",
 			ownerID.String(), groupID.String(), pag.Page, expectedRPPNumber, "", "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, pag, "", "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -554,7 +568,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 			ownerID.String(), groupID.String(), pagination.Page, pagination.RPP, "",
 			strings.Trim(sortExpr, " \n\t")).
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, pagination, "", sortExpr)
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -566,7 +580,7 @@ func TestListService_FetchGrouped(t *testing.T) {
 		m.On("FetchGrouped",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchGrouped(ownerID, groupID, pagination, "", "")
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
@@ -595,7 +609,7 @@ func TestListService_FetchScattered(t *testing.T) {
 		m.On("FetchScattered",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, pagination, "", "")
 		assert.Equal(t, current, res)
 		assert.NoError(t, err)
@@ -604,7 +618,7 @@ func TestListService_FetchScattered(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "FetchScattered")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(uuid.Nil, pagination, "", "")
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("FetchScattered", "ownerID").Error())
@@ -614,7 +628,7 @@ func TestListService_FetchScattered(t *testing.T) {
 	t.Run("parameter pagination cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "FetchScattered")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, nil, "", "")
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("FetchScattered", "pagination").Error())
@@ -631,7 +645,7 @@ func TestListService_FetchScattered(t *testing.T) {
 			ownerID.String(), pagination.Page, pagination.RPP,
 			strings.Trim(needle, " \n\t"), "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, pagination, needle, "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -650,7 +664,7 @@ func TestListService_FetchScattered(t *testing.T) {
 		m.On("FetchScattered",
 			ownerID.String(), expectedPageNumber, pag.RPP, "", "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, pag, "", "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -662,7 +676,7 @@ func TestListService_FetchScattered(t *testing.T) {
 		m.On("FetchScattered",
 			ownerID.String(), expectedPageNumber, pag.RPP, "", "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, pag, "", "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -681,7 +695,7 @@ func TestListService_FetchScattered(t *testing.T) {
 		m.On("FetchScattered",
 			ownerID.String(), pag.Page, expectedRPPNumber, "", "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, pag, "", "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -693,7 +707,7 @@ func TestListService_FetchScattered(t *testing.T) {
 		m.On("FetchScattered",
 			ownerID.String(), pag.Page, expectedRPPNumber, "", "").
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, pag, "", "")
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -709,7 +723,7 @@ func TestListService_FetchScattered(t *testing.T) {
 			ownerID.String(), pagination.Page, pagination.RPP, "",
 			strings.Trim(sortExpr, " \n\t")).
 			Return(lists, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, pagination, "", sortExpr)
 		assert.NotNil(t, res)
 		assert.NoError(t, err)
@@ -721,7 +735,7 @@ func TestListService_FetchScattered(t *testing.T) {
 		m.On("FetchScattered",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.FetchScattered(ownerID, pagination, "", "")
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
@@ -740,7 +754,7 @@ func TestListService_Remove(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Remove", mock.Anything, mock.Anything, mock.Anything).
 			Return(true, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		err = s.Remove(ownerID, groupID, listID)
 		assert.NoError(t, err)
 	})
@@ -749,7 +763,7 @@ func TestListService_Remove(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Remove", ownerID.String(), "", listID.String()).
 			Return(true, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		err = s.Remove(ownerID, uuid.Nil, listID)
 		assert.NoError(t, err)
 	})
@@ -757,7 +771,7 @@ func TestListService_Remove(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Remove")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		err = s.Remove(uuid.Nil, groupID, listID)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Remove", "ownerID").Error())
@@ -766,7 +780,7 @@ func TestListService_Remove(t *testing.T) {
 	t.Run("parameter listID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Remove")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		err = s.Remove(ownerID, groupID, uuid.Nil)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Remove", "listID").Error())
@@ -777,7 +791,7 @@ func TestListService_Remove(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Remove", mock.Anything, mock.Anything, mock.Anything).
 			Return(false, unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		err = s.Remove(ownerID, groupID, listID)
 		assert.ErrorIs(t, err, unexpected)
 	})
@@ -797,7 +811,7 @@ func TestListService_Duplicate(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Duplicate", mock.Anything, mock.Anything, mock.Anything).
 			Return(replicaID.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Duplicate(ownerID, listID)
 		assert.Equal(t, replicaID, res)
 		assert.NoError(t, err)
@@ -807,7 +821,7 @@ func TestListService_Duplicate(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Duplicate", mock.Anything, mock.Anything, mock.Anything).
 			Return("x", nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Duplicate(ownerID, listID)
 		assert.ErrorContains(t, err, "invalid UUID length: 1")
 		assert.Equal(t, uuid.Nil, res)
@@ -818,7 +832,7 @@ func TestListService_Duplicate(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Duplicate", mock.Anything, mock.Anything, mock.Anything).
 			Return(id.String(), nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Duplicate(ownerID, listID)
 		assert.Equal(t, id, res)
 		assert.NoError(t, err)
@@ -827,7 +841,7 @@ func TestListService_Duplicate(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Duplicate")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Duplicate(uuid.Nil, listID)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Duplicate", "ownerID").Error())
@@ -837,7 +851,7 @@ func TestListService_Duplicate(t *testing.T) {
 	t.Run("parameter listID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Duplicate")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Duplicate(ownerID, uuid.Nil)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Duplicate", "listID").Error())
@@ -849,7 +863,7 @@ func TestListService_Duplicate(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Duplicate", mock.Anything, mock.Anything, mock.Anything).
 			Return("", unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Duplicate(ownerID, listID)
 		assert.Equal(t, uuid.Nil, res)
 		assert.ErrorIs(t, err, unexpected)
@@ -869,7 +883,7 @@ func TestListService_Scatter(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Scatter", mock.Anything, mock.Anything, mock.Anything).
 			Return(true, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Scatter(ownerID, listID)
 		assert.True(t, res)
 		assert.NoError(t, err)
@@ -878,7 +892,7 @@ func TestListService_Scatter(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Scatter")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Scatter(uuid.Nil, listID)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Scatter", "ownerID").Error())
@@ -888,7 +902,7 @@ func TestListService_Scatter(t *testing.T) {
 	t.Run("parameter listID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Scatter")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Scatter(ownerID, uuid.Nil)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Scatter", "listID").Error())
@@ -900,7 +914,7 @@ func TestListService_Scatter(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Scatter", mock.Anything, mock.Anything, mock.Anything).
 			Return(false, unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Scatter(ownerID, listID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
@@ -920,7 +934,7 @@ func TestListService_Move(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Move", mock.Anything, mock.Anything, mock.Anything).
 			Return(true, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Move(ownerID, listID, groupID)
 		assert.True(t, res)
 		assert.NoError(t, err)
@@ -929,7 +943,7 @@ func TestListService_Move(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Move")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Move(uuid.Nil, listID, groupID)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Move", "ownerID").Error())
@@ -939,7 +953,7 @@ func TestListService_Move(t *testing.T) {
 	t.Run("parameter listID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Move")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Move(ownerID, uuid.Nil, groupID)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Move", "listID").Error())
@@ -949,7 +963,7 @@ func TestListService_Move(t *testing.T) {
 	t.Run("parameter targetGroupID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Move")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Move(ownerID, listID, uuid.Nil)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Move", "targetGroupID").Error())
@@ -961,7 +975,7 @@ func TestListService_Move(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.On("Move", mock.Anything, mock.Anything, mock.Anything).
 			Return(false, unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Move(ownerID, listID, groupID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
@@ -986,7 +1000,7 @@ func TestListService_Update(t *testing.T) {
 		m.On("Update",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(true, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, listID, up)
 		assert.True(t, res)
 		assert.NoError(t, err)
@@ -997,7 +1011,7 @@ func TestListService_Update(t *testing.T) {
 		m.On("Update",
 			ownerID.String(), "", listID.String(), up).
 			Return(true, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(ownerID, uuid.Nil, listID, up)
 		assert.True(t, res)
 		assert.NoError(t, err)
@@ -1006,7 +1020,7 @@ func TestListService_Update(t *testing.T) {
 	t.Run("parameter ownerID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Update")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(uuid.Nil, groupID, listID, up)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Update", "ownerID").Error())
@@ -1016,7 +1030,7 @@ func TestListService_Update(t *testing.T) {
 	t.Run("parameter listID cannot be uuid.Nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Update")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, uuid.Nil, up)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Update", "listID").Error())
@@ -1026,7 +1040,7 @@ func TestListService_Update(t *testing.T) {
 	t.Run("parameter up cannot be nil", func(t *testing.T) {
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Update")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, listID, nil)
 		assert.ErrorContains(t, err,
 			failure.NewNilParameterError("Update", "up").Error())
@@ -1038,7 +1052,7 @@ func TestListService_Update(t *testing.T) {
 		up.Name = strings.Repeat("x", 1+32)
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Update")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, listID, up)
 		up.Name = previousName
 		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("name", "list", 32).Error())
@@ -1050,7 +1064,7 @@ func TestListService_Update(t *testing.T) {
 		up.Description = strings.Repeat("x", 1+512)
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Update")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, listID, up)
 		up.Description = previousDescription
 		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("description", "list", 512).Error())
@@ -1061,11 +1075,11 @@ func TestListService_Update(t *testing.T) {
 		var previousName, previousDesc = up.Name, up.Description
 		var m = mocks.NewListRepositoryMock()
 		m.AssertNotCalled(t, "Update")
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		m.On("Update",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(false, nil)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, listID, up)
 		assert.Equal(t, "list name", up.Name)
 		assert.Equal(t, "description", up.Description)
@@ -1080,9 +1094,23 @@ func TestListService_Update(t *testing.T) {
 		m.On("Update",
 			mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(false, unexpected)
-		s = NewListService(m)
+		s = NewListService(m, soleOwner{})
 		res, err = s.Update(ownerID, groupID, listID, up)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
+
+	t.Run("only admins can update", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			m        = mocks.NewListRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		res, err = NewListService(m, members).Update(memberID, uuid.Nil, listID, &transfer.ListUpdate{Name: "name"})
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		m.AssertNotCalled(t, "Update")
+	})
 }
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationService interface {
+	Fetch(ownerID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Notification], err error)
+	Dismiss(ownerID, notificationID uuid.UUID) (ok bool, err error)
+	Snooze(ownerID, notificationID uuid.UUID, snooze *transfer.NotificationSnooze) (ok bool, err error)
+}
+
+type notificationService struct {
+	r repository.NotificationRepository
+}
+
+func NewNotificationService(r repository.NotificationRepository) NotificationService {
+	return &notificationService{r}
+}
+
+func (s *notificationService) Fetch(ownerID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Notification], err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Fetch", "ownerID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("Fetch", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	notifications, err := s.r.Fetch(ownerID.String(), pagination.Page, pagination.RPP)
+	if nil != err {
+		return nil, err
+	}
+	result = &types.Result[model.Notification]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(notifications)),
+		Payload:   notifications,
+	}
+	return result, nil
+}
+
+func (s *notificationService) Dismiss(ownerID, notificationID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Dismiss", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == notificationID:
+		err = failure.NewNilParameterError("Dismiss", "notificationID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Dismiss(ownerID.String(), notificationID.String())
+}
+
+// Snooze dismisses a notification and reminds its owner of the task again at
+// the given time, which must be in the future.
+func (s *notificationService) Snooze(ownerID, notificationID uuid.UUID, snooze *transfer.NotificationSnooze) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Snooze", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == notificationID:
+		err = failure.NewNilParameterError("Snooze", "notificationID")
+		log.Println(err)
+		return false, err
+	case nil == snooze:
+		err = failure.NewNilParameterError("Snooze", "snooze")
+		log.Println(err)
+		return false, err
+	case !snooze.Until.After(time.Now()):
+		return false, failure.ErrBadRequest.Clone().SetDetails("A notification can only be snoozed until a time in the future.")
+	}
+	return s.r.Snooze(ownerID.String(), notificationID.String(), snooze.Until)
+}
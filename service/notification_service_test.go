@@ -0,0 +1,136 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+	"time"
+)
+
+func TestNotificationService_Fetch(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID = uuid.New()
+		res     *types.Result[model.Notification]
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pag           = &types.Pagination{}
+			notifications = []*model.Notification{{}, {}}
+			m             = mocks.NewNotificationRepositoryMock()
+		)
+		m.On("Fetch", ownerID.String(), int64(1), int64(10)).Return(notifications, nil)
+		res, err = NewNotificationService(m).Fetch(ownerID, pag)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.Notification]{Page: 1, RPP: 10, Retrieved: 2, Payload: notifications}, res)
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var m = mocks.NewNotificationRepositoryMock()
+		res, err = NewNotificationService(m).Fetch(uuid.Nil, &types.Pagination{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "ownerID").Error())
+		res, err = NewNotificationService(m).Fetch(ownerID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "pagination").Error())
+		assert.Nil(t, res)
+		m.AssertNotCalled(t, "Fetch")
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var m = mocks.NewNotificationRepositoryMock()
+		m.On("Fetch", mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
+		res, err = NewNotificationService(m).Fetch(ownerID, &types.Pagination{})
+		assert.ErrorIs(t, err, unexpected)
+		assert.Nil(t, res)
+	})
+}
+
+func TestNotificationService_Dismiss(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, notificationID = uuid.New(), uuid.New()
+		res                     bool
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var m = mocks.NewNotificationRepositoryMock()
+		m.On("Dismiss", ownerID.String(), notificationID.String()).Return(true, nil)
+		res, err = NewNotificationService(m).Dismiss(ownerID, notificationID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var m = mocks.NewNotificationRepositoryMock()
+		res, err = NewNotificationService(m).Dismiss(uuid.Nil, notificationID)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Dismiss", "ownerID").Error())
+		res, err = NewNotificationService(m).Dismiss(ownerID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Dismiss", "notificationID").Error())
+		assert.False(t, res)
+		m.AssertNotCalled(t, "Dismiss")
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var m = mocks.NewNotificationRepositoryMock()
+		m.On("Dismiss", mock.Anything, mock.Anything).Return(false, failure.ErrNotificationNotFound)
+		res, err = NewNotificationService(m).Dismiss(ownerID, notificationID)
+		assert.ErrorIs(t, err, failure.ErrNotificationNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestNotificationService_Snooze(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, notificationID = uuid.New(), uuid.New()
+		until                   = time.Now().Add(time.Hour)
+		res                     bool
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var m = mocks.NewNotificationRepositoryMock()
+		m.On("Snooze", ownerID.String(), notificationID.String(), until).Return(true, nil)
+		res, err = NewNotificationService(m).Snooze(ownerID, notificationID, &transfer.NotificationSnooze{Until: until})
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("cannot snooze until the past", func(t *testing.T) {
+		var m = mocks.NewNotificationRepositoryMock()
+		res, err = NewNotificationService(m).Snooze(ownerID, notificationID, &transfer.NotificationSnooze{Until: time.Now().Add(-time.Minute)})
+		assert.ErrorContains(t, err, "time in the future")
+		assert.False(t, res)
+		m.AssertNotCalled(t, "Snooze")
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var m = mocks.NewNotificationRepositoryMock()
+		res, err = NewNotificationService(m).Snooze(uuid.Nil, notificationID, &transfer.NotificationSnooze{Until: until})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Snooze", "ownerID").Error())
+		res, err = NewNotificationService(m).Snooze(ownerID, uuid.Nil, &transfer.NotificationSnooze{Until: until})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Snooze", "notificationID").Error())
+		res, err = NewNotificationService(m).Snooze(ownerID, notificationID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Snooze", "snooze").Error())
+		assert.False(t, res)
+		m.AssertNotCalled(t, "Snooze")
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var m = mocks.NewNotificationRepositoryMock()
+		m.On("Snooze", mock.Anything, mock.Anything, mock.Anything).Return(false, failure.ErrNotificationNotFound)
+		res, err = NewNotificationService(m).Snooze(ownerID, notificationID, &transfer.NotificationSnooze{Until: until})
+		assert.ErrorIs(t, err, failure.ErrNotificationNotFound)
+		assert.False(t, res)
+	})
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"noda/failure"
+	"noda/mail"
+	"noda/repository"
+	"sync"
+	"time"
+)
+
+type PasswordResetService interface {
+	Request(email string) error
+	Reset(token, password string) error
+}
+
+// PasswordResetTokenLifetime is how long a password reset token can be used.
+const PasswordResetTokenLifetime = 1 * time.Hour
+
+// maxPendingResets is how many password resets can be on their way at once;
+// the ones requested beyond are dropped.
+const maxPendingResets = 16
+
+type passwordResetService struct {
+	userService UserService
+	resets      repository.PasswordResetRepository
+	tokens      repository.TokenRepository
+	mailer      mail.Mailer
+	resetURL    string
+	pending     chan struct{}
+	inFlight    sync.WaitGroup
+}
+
+// NewPasswordResetService creates a PasswordResetService that mails the reset
+// tokens. If resetURL is not empty, the email links to it with the token in
+// the "token" query parameter; otherwise the email contains the bare token.
+func NewPasswordResetService(
+	userService UserService,
+	resets repository.PasswordResetRepository,
+	tokens repository.TokenRepository,
+	mailer mail.Mailer,
+	resetURL string,
+) PasswordResetService {
+	return &passwordResetService{
+		userService: userService,
+		resets:      resets,
+		tokens:      tokens,
+		mailer:      mailer,
+		resetURL:    resetURL,
+		pending:     make(chan struct{}, maxPendingResets),
+	}
+}
+
+// Request mails a password reset token to the user with the given email, in
+// the background. It only fails when the email is malformed: neither what it
+// returns nor how long it takes tells which addresses are registered, or
+// whether the email could be sent.
+func (s *passwordResetService) Request(email string) error {
+	doTrim(&email)
+	switch {
+	case 240 < len(email):
+		return failure.ErrTooLong.Clone().FormatDetails("Email", "password reset", 240)
+	case !emailRegexp.MatchString(email):
+		return failure.ErrBadRequest.
+			Clone().
+			SetDetails(fmt.Sprintf("Email address does not match regular expression: %q.", emailRegexp.String()))
+	}
+	select {
+	case s.pending <- struct{}{}:
+	default:
+		log.Println("could not mail password reset: too many are on their way")
+		return nil
+	}
+	s.inFlight.Add(1)
+	go func() {
+		defer s.inFlight.Done()
+		defer func() { <-s.pending }()
+		if err := s.send(email); nil != err {
+			log.Println(err)
+		}
+	}()
+	return nil
+}
+
+// send makes a password reset token for the user with the given email, if
+// there is one, and mails it.
+func (s *passwordResetService) send(email string) error {
+	user, err := s.userService.FetchByEmail(email)
+	if nil != err {
+		if errors.Is(err, failure.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	token, err := generateOpaqueToken()
+	if nil != err {
+		return err
+	}
+	_, err = s.resets.Save(user.UUID.String(), hashOpaqueToken(token), time.Now().Add(PasswordResetTokenLifetime))
+	if nil != err {
+		return err
+	}
+	link, err := s.link(token)
+	if nil != err {
+		return err
+	}
+	var ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = s.mailer.Send(ctx, &mail.Message{
+		To:      []string{user.Email},
+		Subject: "Reset your Noda password",
+		Body: fmt.Sprintf("Hello %s,\n\n"+
+			"Someone asked to reset the password of your Noda account. If it was you, "+
+			"use the following within the next %d minutes to choose a new password:\n\n"+
+			"%s\n\n"+
+			"If it was not you, you can ignore this email and your password will stay the same.\n",
+			user.FirstName, int(PasswordResetTokenLifetime.Minutes()), link),
+	})
+	if nil != err {
+		return fmt.Errorf("could not mail password reset to user %s: %w", user.UUID, err)
+	}
+	return nil
+}
+
+func (s *passwordResetService) link(token string) (string, error) {
+	if "" == s.resetURL {
+		return token, nil
+	}
+	link, err := url.Parse(s.resetURL)
+	if nil != err {
+		return "", err
+	}
+	var query = link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+	return link.String(), nil
+}
+
+// Reset sets a new password for the owner of the reset token. The token can
+// be used only once, and every session of the user is signed out.
+func (s *passwordResetService) Reset(token, password string) error {
+	doTrim(&token, &password)
+	switch {
+	case "" == token:
+		return failure.ErrInvalidResetToken
+	case 72 < len(password):
+		return failure.ErrTooLong.Clone().FormatDetails("NewPassword", "password reset", 72)
+	}
+	reset, err := s.resets.FetchByHash(hashOpaqueToken(token))
+	if nil != err {
+		return err
+	}
+	if nil != reset.UsedAt || !time.Now().Before(reset.ExpiresAt) {
+		return failure.ErrInvalidResetToken
+	}
+	user, err := s.userService.FetchByID(reset.UserUUID)
+	if nil != err {
+		return err
+	}
+	/* Checked before consuming the token so that a weak password does not
+	   waste it.  */
+	if err := assertPasswordIsValid(&password, &user.Email); err != nil {
+		return err
+	}
+	ok, err := s.resets.Consume(reset.UUID.String())
+	if nil != err {
+		return err
+	}
+	if !ok {
+		return failure.ErrInvalidResetToken
+	}
+	_, err = s.userService.SetPassword(user.UUID, password)
+	if nil != err {
+		return err
+	}
+	_, err = s.tokens.RevokeAll(user.UUID.String())
+	if nil != err {
+		log.Printf("could not sign out user %s after resetting the password: %v", user.UUID, err)
+	}
+	return nil
+}
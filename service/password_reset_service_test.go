@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/url"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/mail"
+	"noda/mocks"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPasswordResetService_Request(t *testing.T) {
+	defer beQuiet()()
+	const (
+		routine = "Save"
+		email   = "izs16833@zslsz.com"
+	)
+	var (
+		err  error
+		user = &transfer.User{UUID: uuid.New(), FirstName: "Ada", Email: email}
+	)
+	// request requests a password reset and waits for it to be on its way.
+	var request = func(s PasswordResetService, email string) error {
+		var err = s.Request(email)
+		s.(*passwordResetService).inFlight.Wait()
+		return err
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			us     = mocks.NewUserServiceMock()
+			resets = mocks.NewPasswordResetRepositoryMock()
+			mailer = mail.NewMemoryMailer()
+			hash   string
+		)
+		us.On("FetchByEmail", email).Return(user, nil)
+		resets.On(routine, user.UUID.String(), mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { hash = args.String(1) }).
+			Return(uuid.NewString(), nil)
+		err = request(NewPasswordResetService(us, resets, nil, mailer, "https://noda.app/reset?lang=en"), blankset+email)
+		assert.NoError(t, err)
+		var messages = mailer.Messages()
+		require.Len(t, messages, 1)
+		assert.Equal(t, []string{email}, messages[0].To)
+		assert.Contains(t, messages[0].Body, "Hello Ada,")
+		var start = strings.Index(messages[0].Body, "https://")
+		require.NotEqual(t, -1, start)
+		link, err := url.Parse(strings.Fields(messages[0].Body[start:])[0])
+		require.NoError(t, err)
+		assert.Equal(t, "en", link.Query().Get("lang"))
+		assert.Equal(t, hash, hashOpaqueToken(link.Query().Get("token")))
+	})
+
+	t.Run("bare token without reset URL", func(t *testing.T) {
+		var (
+			us     = mocks.NewUserServiceMock()
+			resets = mocks.NewPasswordResetRepositoryMock()
+			mailer = mail.NewMemoryMailer()
+			hash   string
+		)
+		us.On("FetchByEmail", email).Return(user, nil)
+		resets.On(routine, user.UUID.String(), mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { hash = args.String(1) }).
+			Return(uuid.NewString(), nil)
+		err = request(NewPasswordResetService(us, resets, nil, mailer, ""), email)
+		assert.NoError(t, err)
+		require.Len(t, mailer.Messages(), 1)
+		var found bool
+		for _, field := range strings.Fields(mailer.Messages()[0].Body) {
+			found = found || hash == hashOpaqueToken(field)
+		}
+		assert.True(t, found, "The email must contain the token.")
+	})
+
+	t.Run("unknown email is not reported", func(t *testing.T) {
+		var (
+			us     = mocks.NewUserServiceMock()
+			resets = mocks.NewPasswordResetRepositoryMock()
+			mailer = mail.NewMemoryMailer()
+		)
+		us.On("FetchByEmail", email).Return(nil, failure.ErrUserNotFound)
+		err = request(NewPasswordResetService(us, resets, nil, mailer, ""), email)
+		assert.NoError(t, err)
+		assert.Empty(t, mailer.Messages())
+		resets.AssertNotCalled(t, routine, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("email must match regexp", func(t *testing.T) {
+		var us = mocks.NewUserServiceMock()
+		err = request(NewPasswordResetService(us, nil, nil, mail.NewMemoryMailer(), ""), "wrong")
+		assert.ErrorContains(t, err, "Email address does not match regular expression")
+		us.AssertNotCalled(t, "FetchByEmail", mock.Anything)
+	})
+
+	t.Run("answers before looking the email up", func(t *testing.T) {
+		var (
+			us      = mocks.NewUserServiceMock()
+			release = make(chan time.Time)
+			s       = NewPasswordResetService(us, nil, nil, mail.NewMemoryMailer(), "")
+		)
+		us.On("FetchByEmail", email).WaitUntil(release).Return(nil, failure.ErrUserNotFound)
+		err = s.Request(email)
+		assert.NoError(t, err)
+		close(release)
+		s.(*passwordResetService).inFlight.Wait()
+		us.AssertCalled(t, "FetchByEmail", email)
+	})
+
+	t.Run("drops the requests beyond the pending ones", func(t *testing.T) {
+		var (
+			us      = mocks.NewUserServiceMock()
+			release = make(chan time.Time)
+			s       = NewPasswordResetService(us, nil, nil, mail.NewMemoryMailer(), "")
+		)
+		us.On("FetchByEmail", email).WaitUntil(release).Return(nil, failure.ErrUserNotFound)
+		for i := 0; i < maxPendingResets+1; i++ {
+			assert.NoError(t, s.Request(email))
+		}
+		close(release)
+		s.(*passwordResetService).inFlight.Wait()
+		us.AssertNumberOfCalls(t, "FetchByEmail", maxPendingResets)
+	})
+
+	t.Run("got repository error", func(t *testing.T) {
+		var (
+			unexpected = errors.New("unexpected error")
+			us         = mocks.NewUserServiceMock()
+			resets     = mocks.NewPasswordResetRepositoryMock()
+			mailer     = mail.NewMemoryMailer()
+		)
+		us.On("FetchByEmail", email).Return(user, nil)
+		resets.On(routine, mock.Anything, mock.Anything, mock.Anything).Return("", unexpected)
+		err = request(NewPasswordResetService(us, resets, nil, mailer, ""), email)
+		assert.NoError(t, err)
+		assert.Empty(t, mailer.Messages())
+	})
+
+	t.Run("mail not sent is not reported", func(t *testing.T) {
+		var (
+			us     = mocks.NewUserServiceMock()
+			resets = mocks.NewPasswordResetRepositoryMock()
+		)
+		us.On("FetchByEmail", email).Return(user, nil)
+		resets.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(uuid.NewString(), nil)
+		err = request(NewPasswordResetService(us, resets, nil, unreachableMailer{}, ""), email)
+		assert.NoError(t, err)
+	})
+}
+
+// unreachableMailer fails to send every email.
+type unreachableMailer struct{}
+
+func (unreachableMailer) Send(context.Context, *mail.Message) error {
+	return errors.New("connection refused")
+}
+
+func TestPasswordResetService_Reset(t *testing.T) {
+	defer beQuiet()()
+	const (
+		token    = "Nq3nQGa0yVt0cJxk1Vf2mXo5c7cS8oA6PzS3zqkFh1E"
+		password = "Gq7!vR2#pLk9@wZ"
+	)
+	var (
+		err   error
+		hash  = hashOpaqueToken(token)
+		user  = &transfer.User{UUID: uuid.New(), Email: "izs16833@zslsz.com"}
+		fresh = func() *model.PasswordReset {
+			return &model.PasswordReset{
+				UUID:      uuid.New(),
+				UserUUID:  user.UUID,
+				Hash:      hash,
+				ExpiresAt: time.Now().Add(time.Hour),
+			}
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			reset  = fresh()
+			us     = mocks.NewUserServiceMock()
+			resets = mocks.NewPasswordResetRepositoryMock()
+			tokens = mocks.NewTokenRepositoryMock()
+		)
+		resets.On("FetchByHash", hash).Return(reset, nil)
+		resets.On("Consume", reset.UUID.String()).Return(true, nil)
+		us.On("FetchByID", user.UUID).Return(user, nil)
+		us.On("SetPassword", user.UUID, password).Return(true, nil)
+		tokens.On("RevokeAll", user.UUID.String()).Return(true, nil)
+		err = NewPasswordResetService(us, resets, tokens, nil, "").Reset(token, password)
+		assert.NoError(t, err)
+		us.AssertExpectations(t)
+		tokens.AssertExpectations(t)
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		var resets = mocks.NewPasswordResetRepositoryMock()
+		err = NewPasswordResetService(nil, resets, nil, nil, "").Reset(blankset, password)
+		assert.ErrorIs(t, err, failure.ErrInvalidResetToken)
+		resets.AssertNotCalled(t, "FetchByHash", mock.Anything)
+	})
+
+	t.Run("used or expired token", func(t *testing.T) {
+		var (
+			used    = fresh()
+			expired = fresh()
+			usedAt  = time.Now().Add(-time.Minute)
+		)
+		used.UsedAt = &usedAt
+		expired.ExpiresAt = time.Now().Add(-time.Second)
+		for _, reset := range []*model.PasswordReset{used, expired} {
+			var resets = mocks.NewPasswordResetRepositoryMock()
+			resets.On("FetchByHash", hash).Return(reset, nil)
+			err = NewPasswordResetService(mocks.NewUserServiceMock(), resets, nil, nil, "").Reset(token, password)
+			assert.ErrorIs(t, err, failure.ErrInvalidResetToken)
+			resets.AssertNotCalled(t, "Consume", mock.Anything)
+		}
+	})
+
+	t.Run("weak password keeps the token", func(t *testing.T) {
+		var (
+			reset  = fresh()
+			us     = mocks.NewUserServiceMock()
+			resets = mocks.NewPasswordResetRepositoryMock()
+		)
+		resets.On("FetchByHash", hash).Return(reset, nil)
+		us.On("FetchByID", user.UUID).Return(user, nil)
+		err = NewPasswordResetService(us, resets, nil, nil, "").Reset(token, "weak")
+		var aggregate *failure.AggregateDetails
+		assert.ErrorAs(t, err, &aggregate)
+		resets.AssertNotCalled(t, "Consume", mock.Anything)
+	})
+
+	t.Run("lost the token to a concurrent request", func(t *testing.T) {
+		var (
+			reset  = fresh()
+			us     = mocks.NewUserServiceMock()
+			resets = mocks.NewPasswordResetRepositoryMock()
+		)
+		resets.On("FetchByHash", hash).Return(reset, nil)
+		resets.On("Consume", reset.UUID.String()).Return(false, nil)
+		us.On("FetchByID", user.UUID).Return(user, nil)
+		err = NewPasswordResetService(us, resets, nil, nil, "").Reset(token, password)
+		assert.ErrorIs(t, err, failure.ErrInvalidResetToken)
+		us.AssertNotCalled(t, "SetPassword", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/notify"
+	"noda/repository"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// dispatchBatchSize is how many due reminders are delivered per dispatch.
+	dispatchBatchSize = 100
+
+	// maxReminderAttempts is how many failed deliveries a reminder may have
+	// before it is given up.
+	maxReminderAttempts = 10
+
+	// undelivered is what the delivery history tells of a failed delivery:
+	// the error itself, such as what a webhook answered or why it could not
+	// be reached, is only logged, for it says more about the network of the
+	// server than its users should know.
+	undelivered = "the reminder could not be delivered"
+)
+
+// ReminderService delivers the reminders of the tasks when they are due.
+type ReminderService interface {
+	Dispatch(now time.Time) (delivered int, err error)
+	FetchDeliveries(ownerID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.ReminderDelivery], err error)
+}
+
+type reminderService struct {
+	r         repository.ReminderRepository
+	notifiers []notify.Notifier
+}
+
+func NewReminderService(r repository.ReminderRepository, notifiers ...notify.Notifier) ReminderService {
+	return &reminderService{r: r, notifiers: notifiers}
+}
+
+// Dispatch delivers the reminders that are due at now through every notifier
+// that has not delivered them yet. Every attempt is recorded, and a reminder
+// stops being due once all its channels delivered it, so a channel that fails
+// is tried again on the next dispatch while the others are not repeated. A
+// reminder that failed maxReminderAttempts times is given up.
+func (s *reminderService) Dispatch(now time.Time) (delivered int, err error) {
+	reminders, err := s.r.FetchDue(now, dispatchBatchSize)
+	if nil != err {
+		return 0, err
+	}
+	var failed = 0
+	for _, due := range reminders {
+		var done = s.deliver(due)
+		if !done && maxReminderAttempts > due.FailedAttempts+1 {
+			failed++
+			continue
+		}
+		if !done {
+			log.Printf("giving up the reminder of task %q after %d failed attempts", due.TaskUUID, maxReminderAttempts)
+		}
+		if _, err = s.r.MarkAsFired(due.TaskUUID.String(), due.RemindAt); nil != err {
+			log.Printf("could not mark the reminder of task %q as fired: %v", due.TaskUUID, err)
+			failed++
+			continue
+		}
+		if done {
+			delivered++
+		}
+	}
+	if 0 < failed {
+		return delivered, fmt.Errorf("could not deliver %d reminder(s)", failed)
+	}
+	return delivered, nil
+}
+
+// deliver delivers due through the channels that have not delivered it yet
+// and tells whether all of them did.
+func (s *reminderService) deliver(due *model.DueReminder) (done bool) {
+	var reminder = &notify.Reminder{
+		Key:        fmt.Sprintf("%s@%d", due.TaskUUID, due.RemindAt.Unix()),
+		TaskUUID:   due.TaskUUID,
+		OwnerUUID:  due.OwnerUUID,
+		Title:      due.Title,
+		Headline:   due.Headline,
+		DueDate:    due.DueDate,
+		RemindAt:   due.RemindAt,
+		Email:      due.Email,
+		WebhookURL: due.WebhookURL,
+	}
+	done = true
+	for _, notifier := range s.notifiers {
+		var channel = notifier.Channel()
+		if slices.Contains(due.DeliveredChannels, channel) {
+			continue
+		}
+		var ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+		err := notifier.Notify(ctx, reminder)
+		cancel()
+		if errors.Is(err, notify.ErrNoRecipient) {
+			continue
+		}
+		var message = ""
+		if nil != err {
+			log.Printf("could not deliver the reminder of task %q by %s: %v", due.TaskUUID, channel, err)
+			message = undelivered
+			done = false
+		}
+		if _, err = s.r.RecordDelivery(due.TaskUUID.String(), due.RemindAt, channel, message); nil != err {
+			/* It would be delivered again, which is better than not at all.  */
+			log.Printf("could not record the delivery of the reminder of task %q by %s: %v", due.TaskUUID, channel, err)
+			done = false
+		}
+	}
+	return done
+}
+
+func (s *reminderService) FetchDeliveries(
+	ownerID, taskID uuid.UUID,
+	pagination *types.Pagination,
+) (result *types.Result[model.ReminderDelivery], err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("FetchDeliveries", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("FetchDeliveries", "taskID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("FetchDeliveries", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	deliveries, err := s.r.FetchDeliveries(ownerID.String(), taskID.String(), pagination.Page, pagination.RPP)
+	if nil != err {
+		return nil, err
+	}
+	result = &types.Result[model.ReminderDelivery]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(deliveries)),
+		Payload:   deliveries,
+	}
+	return result, nil
+}
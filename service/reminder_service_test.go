@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"noda/notify"
+	"testing"
+	"time"
+)
+
+type notifierFunc struct {
+	channel string
+	notify  func(reminder *notify.Reminder) error
+	called  int
+}
+
+func (n *notifierFunc) Channel() string {
+	return n.channel
+}
+
+func (n *notifierFunc) Notify(_ context.Context, reminder *notify.Reminder) error {
+	n.called++
+	return n.notify(reminder)
+}
+
+func TestReminderService_Dispatch(t *testing.T) {
+	defer beQuiet()()
+	var (
+		now      = time.Date(2024, time.January, 1, 9, 0, 30, 0, time.UTC)
+		remindAt = time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+		taskID   = uuid.New()
+		newDue   = func(delivered ...string) *model.DueReminder {
+			return &model.DueReminder{
+				TaskUUID:          taskID,
+				OwnerUUID:         uuid.New(),
+				Title:             "Pay the rent",
+				RemindAt:          remindAt,
+				Email:             "foo@bar.com",
+				DeliveredChannels: delivered,
+			}
+		}
+		succeed = func(*notify.Reminder) error { return nil }
+		fail    = func(*notify.Reminder) error { return errors.New("boom") }
+	)
+
+	t.Run("every channel delivers", func(t *testing.T) {
+		var (
+			email = &notifierFunc{channel: "email", notify: func(reminder *notify.Reminder) error {
+				assert.Equal(t, "foo@bar.com", reminder.Email)
+				assert.Equal(t, taskID.String()+"@1704099600", reminder.Key)
+				return nil
+			}}
+			webhook = &notifierFunc{channel: "webhook", notify: func(*notify.Reminder) error { return notify.ErrNoRecipient }}
+			m       = mocks.NewReminderRepositoryMock()
+		)
+		m.On("FetchDue", now, int64(dispatchBatchSize)).Return([]*model.DueReminder{newDue()}, nil)
+		m.On("RecordDelivery", taskID.String(), remindAt, "email", "").Return(true, nil)
+		m.On("MarkAsFired", taskID.String(), remindAt).Return(true, nil)
+		delivered, err := NewReminderService(m, email, webhook).Dispatch(now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, 1, webhook.called)
+		m.AssertNotCalled(t, "RecordDelivery", taskID.String(), remindAt, "webhook", mock.Anything)
+	})
+
+	t.Run("channels that already delivered are skipped", func(t *testing.T) {
+		var (
+			email = &notifierFunc{channel: "email", notify: succeed}
+			inbox = &notifierFunc{channel: "inbox", notify: succeed}
+			m     = mocks.NewReminderRepositoryMock()
+		)
+		m.On("FetchDue", now, int64(dispatchBatchSize)).Return([]*model.DueReminder{newDue("email")}, nil)
+		m.On("RecordDelivery", taskID.String(), remindAt, "inbox", "").Return(true, nil)
+		m.On("MarkAsFired", taskID.String(), remindAt).Return(true, nil)
+		delivered, err := NewReminderService(m, email, inbox).Dispatch(now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		assert.Equal(t, 0, email.called)
+		assert.Equal(t, 1, inbox.called)
+	})
+
+	t.Run("a failed channel keeps the reminder due", func(t *testing.T) {
+		var (
+			email   = &notifierFunc{channel: "email", notify: succeed}
+			webhook = &notifierFunc{channel: "webhook", notify: fail}
+			m       = mocks.NewReminderRepositoryMock()
+		)
+		m.On("FetchDue", now, int64(dispatchBatchSize)).Return([]*model.DueReminder{newDue()}, nil)
+		m.On("RecordDelivery", taskID.String(), remindAt, "email", "").Return(true, nil)
+		m.On("RecordDelivery", taskID.String(), remindAt, "webhook", undelivered).Return(true, nil)
+		delivered, err := NewReminderService(m, email, webhook).Dispatch(now)
+		assert.ErrorContains(t, err, "1 reminder(s)")
+		assert.Equal(t, 0, delivered)
+		m.AssertNotCalled(t, "MarkAsFired", mock.Anything, mock.Anything)
+	})
+
+	t.Run("a reminder that failed too many times is given up", func(t *testing.T) {
+		var (
+			webhook = &notifierFunc{channel: "webhook", notify: fail}
+			due     = newDue()
+			m       = mocks.NewReminderRepositoryMock()
+		)
+		due.FailedAttempts = maxReminderAttempts - 1
+		m.On("FetchDue", now, int64(dispatchBatchSize)).Return([]*model.DueReminder{due}, nil)
+		m.On("RecordDelivery", taskID.String(), remindAt, "webhook", undelivered).Return(true, nil)
+		m.On("MarkAsFired", taskID.String(), remindAt).Return(true, nil)
+		delivered, err := NewReminderService(m, webhook).Dispatch(now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		m.AssertCalled(t, "MarkAsFired", taskID.String(), remindAt)
+	})
+
+	t.Run("an unrecorded delivery is attempted again", func(t *testing.T) {
+		var (
+			email = &notifierFunc{channel: "email", notify: succeed}
+			m     = mocks.NewReminderRepositoryMock()
+		)
+		m.On("FetchDue", now, int64(dispatchBatchSize)).Return([]*model.DueReminder{newDue()}, nil)
+		m.On("RecordDelivery", taskID.String(), remindAt, "email", "").Return(false, errors.New("unexpected error"))
+		delivered, err := NewReminderService(m, email).Dispatch(now)
+		assert.Error(t, err)
+		assert.Equal(t, 0, delivered)
+		m.AssertNotCalled(t, "MarkAsFired", mock.Anything, mock.Anything)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var m = mocks.NewReminderRepositoryMock()
+		m.On("FetchDue", now, int64(dispatchBatchSize)).Return(nil, unexpected)
+		delivered, err := NewReminderService(m).Dispatch(now)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Equal(t, 0, delivered)
+	})
+}
+
+func TestReminderService_FetchDeliveries(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, taskID = uuid.New(), uuid.New()
+		res             *types.Result[model.ReminderDelivery]
+		err             error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			pag        = &types.Pagination{}
+			deliveries = []*model.ReminderDelivery{{}, {}}
+			m          = mocks.NewReminderRepositoryMock()
+		)
+		m.On("FetchDeliveries", ownerID.String(), taskID.String(), int64(1), int64(10)).Return(deliveries, nil)
+		res, err = NewReminderService(m).FetchDeliveries(ownerID, taskID, pag)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.ReminderDelivery]{Page: 1, RPP: 10, Retrieved: 2, Payload: deliveries}, res)
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var m = mocks.NewReminderRepositoryMock()
+		var s = NewReminderService(m)
+		res, err = s.FetchDeliveries(uuid.Nil, taskID, &types.Pagination{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("FetchDeliveries", "ownerID").Error())
+		res, err = s.FetchDeliveries(ownerID, uuid.Nil, &types.Pagination{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("FetchDeliveries", "taskID").Error())
+		res, err = s.FetchDeliveries(ownerID, taskID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("FetchDeliveries", "pagination").Error())
+		assert.Nil(t, res)
+		m.AssertNotCalled(t, "FetchDeliveries")
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var m = mocks.NewReminderRepositoryMock()
+		m.On("FetchDeliveries", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, failure.ErrTaskNotFound)
+		res, err = NewReminderService(m).FetchDeliveries(ownerID, taskID, &types.Pagination{})
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UndoWindow is how long after a change to a task the change can be undone.
+const UndoWindow = 10 * time.Minute
+
+// RevisionService retrieves the revisions of the tasks and brings the tasks
+// back to them. The revisions are taken by the TaskService returned by
+// NewRevisedTaskService.
+type RevisionService interface {
+	Fetch(userID, listID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Revision], err error)
+	Restore(userID, listID, taskID, revisionID uuid.UUID) (ok bool, err error)
+	Undo(userID uuid.UUID) (revision *model.Revision, err error)
+}
+
+type revisionService struct {
+	r       repository.RevisionRepository
+	members repository.MemberRepository
+}
+
+func NewRevisionService(r repository.RevisionRepository, members repository.MemberRepository) RevisionService {
+	return &revisionService{r: r, members: members}
+}
+
+// Fetch retrieves the revisions of a task, latest first, with the fields each
+// of their changes made to the task.
+func (s *revisionService) Fetch(userID, listID, taskID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Revision], err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Fetch", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Fetch", "listID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Fetch", "taskID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("Fetch", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	revisions, err := s.r.Fetch(access.OwnerUUID.String(), listID.String(), taskID.String(), pagination.Page, pagination.RPP)
+	if nil != err {
+		return nil, err
+	}
+	for _, revision := range revisions {
+		revision.Changes = diffTaskStates(revision.Before, revision.After)
+	}
+	result = &types.Result[model.Revision]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(revisions)),
+		Payload:   revisions,
+	}
+	return result, nil
+}
+
+// Restore brings a task back to the state it had right before the change of
+// the revision. Restoring is itself a change that gets a revision.
+func (s *revisionService) Restore(userID, listID, taskID, revisionID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Restore", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Restore", "listID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Restore", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == revisionID:
+		err = failure.NewNilParameterError("Restore", "revisionID")
+		log.Println(err)
+		return false, err
+	}
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	return s.r.Restore(access.OwnerUUID.String(), listID.String(), taskID.String(), revisionID.String(), userID.String())
+}
+
+// Undo reverses the latest change the user made to a task within the undo
+// window, and returns its revision. Undoing again reverses the change made
+// before it, and so on.
+func (s *revisionService) Undo(userID uuid.UUID) (revision *model.Revision, err error) {
+	if uuid.Nil == userID {
+		err = failure.NewNilParameterError("Undo", "userID")
+		log.Println(err)
+		return nil, err
+	}
+	revision, err = s.r.FetchLast(userID.String(), time.Now().Add(-UndoWindow))
+	if nil != err {
+		return nil, err
+	}
+	access, err := authorizeList(s.members, userID, revision.ListUUID, types.MemberRoleEditor)
+	if nil != err {
+		return nil, err
+	}
+	ok, err := s.r.Undo(access.OwnerUUID.String(), revision.UUID.String(), userID.String())
+	if nil != err {
+		return nil, err
+	}
+	if !ok {
+		return nil, failure.ErrNothingToUndo
+	}
+	revision.IsUndone = true
+	revision.Changes = diffTaskStates(revision.Before, revision.After)
+	return revision, nil
+}
+
+// diffTaskStates lists the fields that differ between two states of a task, in
+// alphabetical order. The time of the last update is left out as it changes
+// every time. A missing state, such as the one after a removal, has no fields.
+func diffTaskStates(before, after json.RawMessage) (changes []*model.RevisionChange) {
+	var previous, current map[string]json.RawMessage
+	if 0 < len(before) {
+		if err := json.Unmarshal(before, &previous); nil != err {
+			log.Printf("could not read a state of a task: %v", err)
+		}
+	}
+	if 0 < len(after) {
+		if err := json.Unmarshal(after, &current); nil != err {
+			log.Printf("could not read a state of a task: %v", err)
+		}
+	}
+	var fields = make([]string, 0, len(previous))
+	for field := range previous {
+		fields = append(fields, field)
+	}
+	for field := range current {
+		if _, ok := previous[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+	changes = make([]*model.RevisionChange, 0)
+	for _, field := range fields {
+		if "updated_at" == field {
+			continue
+		}
+		var p, c = previous[field], current[field]
+		if nil != p && nil != c && bytes.Equal(compact(p), compact(c)) {
+			continue
+		}
+		changes = append(changes, &model.RevisionChange{Field: field, Previous: p, Current: c})
+	}
+	return changes
+}
+
+func compact(value json.RawMessage) []byte {
+	var buffer bytes.Buffer
+	if nil != json.Compact(&buffer, value) {
+		return value
+	}
+	return buffer.Bytes()
+}
+
+// revisedTaskService takes a revision of a task before every change made to it
+// through the TaskService it wraps, so that the change can be reversed later.
+type revisedTaskService struct {
+	TaskService
+	revisions repository.RevisionRepository
+	members   repository.MemberRepository
+}
+
+// NewRevisedTaskService wraps a TaskService so that its changes to the tasks
+// are revised. Removing a task for good is not revised.
+func NewRevisedTaskService(next TaskService, revisions repository.RevisionRepository, members repository.MemberRepository) TaskService {
+	return &revisedTaskService{TaskService: next, revisions: revisions, members: members}
+}
+
+// reviseInList revises a task of a list on which the user acts, then makes the
+// change. The revision is discarded if the change does not happen.
+func (t *revisedTaskService) reviseInList(userID, listID, taskID uuid.UUID, action types.RevisionAction, change func() (bool, error)) (ok bool, err error) {
+	if uuid.Nil == userID || uuid.Nil == listID || uuid.Nil == taskID {
+		return change()
+	}
+	access, err := authorizeList(t.members, userID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	return t.doRevise(access.OwnerUUID, listID.String(), taskID, userID, action, change)
+}
+
+// revise revises a task wherever it is, on whichever list of whichever owner
+// the user acts, then makes the change. The revision is discarded if the
+// change does not happen.
+func (t *revisedTaskService) revise(userID, taskID uuid.UUID, action types.RevisionAction, change func() (bool, error)) (ok bool, err error) {
+	if uuid.Nil == userID || uuid.Nil == taskID {
+		return change()
+	}
+	listID, err := t.TaskService.Locate(userID, taskID)
+	if nil != err {
+		return false, err
+	}
+	return t.reviseInList(userID, listID, taskID, action, change)
+}
+
+func (t *revisedTaskService) doRevise(
+	ownerID uuid.UUID,
+	listID string,
+	taskID, actorID uuid.UUID,
+	action types.RevisionAction,
+	change func() (bool, error),
+) (ok bool, err error) {
+	revisionID, err := t.revisions.Save(ownerID.String(), listID, taskID.String(), actorID.String(), action)
+	if nil != err {
+		return false, err
+	}
+	ok, err = change()
+	if nil != err || !ok {
+		if err := t.revisions.Discard(revisionID); nil != err {
+			log.Printf("could not discard revision %q: %v", revisionID, err)
+		}
+	}
+	return ok, err
+}
+
+func (t *revisedTaskService) Update(ownerID, listID, taskID uuid.UUID, update *transfer.TaskUpdate) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionUpdated, func() (bool, error) {
+		return t.TaskService.Update(ownerID, listID, taskID, update)
+	})
+}
+
+func (t *revisedTaskService) Reorder(ownerID, listID, taskID uuid.UUID, position uint64) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionReordered, func() (bool, error) {
+		return t.TaskService.Reorder(ownerID, listID, taskID, position)
+	})
+}
+
+func (t *revisedTaskService) SetReminder(ownerID, listID, taskID uuid.UUID, remindAt time.Time) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionReminderSet, func() (bool, error) {
+		return t.TaskService.SetReminder(ownerID, listID, taskID, remindAt)
+	})
+}
+
+func (t *revisedTaskService) SetPriority(ownerID, listID, taskID uuid.UUID, priority types.TaskPriority) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionPriorityChanged, func() (bool, error) {
+		return t.TaskService.SetPriority(ownerID, listID, taskID, priority)
+	})
+}
+
+func (t *revisedTaskService) SetDueDate(ownerID, listID, taskID uuid.UUID, dueDate time.Time) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionDueDateSet, func() (bool, error) {
+		return t.TaskService.SetDueDate(ownerID, listID, taskID, dueDate)
+	})
+}
+
+func (t *revisedTaskService) SetRecurrence(ownerID, listID, taskID uuid.UUID, rule string) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionRecurrenceSet, func() (bool, error) {
+		return t.TaskService.SetRecurrence(ownerID, listID, taskID, rule)
+	})
+}
+
+func (t *revisedTaskService) RemoveRecurrence(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionRecurrenceRemoved, func() (bool, error) {
+		return t.TaskService.RemoveRecurrence(ownerID, listID, taskID)
+	})
+}
+
+func (t *revisedTaskService) Complete(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionCompleted, func() (bool, error) {
+		return t.TaskService.Complete(ownerID, listID, taskID)
+	})
+}
+
+func (t *revisedTaskService) Resume(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionResumed, func() (bool, error) {
+		return t.TaskService.Resume(ownerID, listID, taskID)
+	})
+}
+
+func (t *revisedTaskService) Pin(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionPinned, func() (bool, error) {
+		return t.TaskService.Pin(ownerID, listID, taskID)
+	})
+}
+
+func (t *revisedTaskService) Unpin(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionUnpinned, func() (bool, error) {
+		return t.TaskService.Unpin(ownerID, listID, taskID)
+	})
+}
+
+func (t *revisedTaskService) Move(ownerID, taskID, targetListID uuid.UUID) (ok bool, err error) {
+	return t.revise(ownerID, taskID, types.RevisionActionMoved, func() (bool, error) {
+		return t.TaskService.Move(ownerID, taskID, targetListID)
+	})
+}
+
+func (t *revisedTaskService) Today(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.revise(ownerID, taskID, types.RevisionActionMoved, func() (bool, error) {
+		return t.TaskService.Today(ownerID, taskID)
+	})
+}
+
+func (t *revisedTaskService) Tomorrow(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.revise(ownerID, taskID, types.RevisionActionMoved, func() (bool, error) {
+		return t.TaskService.Tomorrow(ownerID, taskID)
+	})
+}
+
+func (t *revisedTaskService) Defer(ownerID, taskID uuid.UUID) (ok bool, err error) {
+	return t.revise(ownerID, taskID, types.RevisionActionMoved, func() (bool, error) {
+		return t.TaskService.Defer(ownerID, taskID)
+	})
+}
+
+func (t *revisedTaskService) Trash(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionTrashed, func() (bool, error) {
+		return t.TaskService.Trash(ownerID, listID, taskID)
+	})
+}
+
+func (t *revisedTaskService) RestoreFromTrash(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	return t.reviseInList(ownerID, listID, taskID, types.RevisionActionRestoredFromTrash, func() (bool, error) {
+		return t.TaskService.RestoreFromTrash(ownerID, listID, taskID)
+	})
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+)
+
+func TestDiffTaskStates(t *testing.T) {
+	var (
+		before = json.RawMessage(`{"title":"Report","priority":"low","description":"Long","updated_at":"2024-01-01T00:00:00Z"}`)
+		after  = json.RawMessage(`{"title": "Report", "priority":"high","description":"","updated_at":"2024-01-02T00:00:00Z"}`)
+	)
+	assert.Equal(t, []*model.RevisionChange{
+		{Field: "description", Previous: json.RawMessage(`"Long"`), Current: json.RawMessage(`""`)},
+		{Field: "priority", Previous: json.RawMessage(`"low"`), Current: json.RawMessage(`"high"`)},
+	}, diffTaskStates(before, after))
+	assert.Equal(t, []*model.RevisionChange{
+		{Field: "title", Previous: json.RawMessage(`"Report"`)},
+	}, diffTaskStates(json.RawMessage(`{"title":"Report"}`), nil))
+	assert.Empty(t, diffTaskStates(before, before))
+}
+
+func TestRevisionService_Fetch(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		res                    *types.Result[model.Revision]
+		err                    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r         = mocks.NewRevisionRepositoryMock()
+			revisions = []*model.Revision{{
+				UUID:   uuid.New(),
+				Action: types.RevisionActionUpdated,
+				Before: json.RawMessage(`{"title":"old"}`),
+				After:  json.RawMessage(`{"title":"new"}`),
+			}}
+		)
+		r.On("Fetch", userID.String(), listID.String(), taskID.String(), int64(1), int64(10)).Return(revisions, nil)
+		res, err = NewRevisionService(r, soleOwner{}).Fetch(userID, listID, taskID, &types.Pagination{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), res.Retrieved)
+		assert.Equal(t, []*model.RevisionChange{
+			{Field: "title", Previous: json.RawMessage(`"old"`), Current: json.RawMessage(`"new"`)},
+		}, res.Payload[0].Changes)
+	})
+
+	t.Run("nil pagination", func(t *testing.T) {
+		res, err = NewRevisionService(nil, soleOwner{}).Fetch(userID, listID, taskID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "pagination").Error())
+		assert.Nil(t, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewRevisionRepositoryMock()
+		r.On("Fetch", userID.String(), listID.String(), taskID.String(), int64(1), int64(10)).Return(nil, failure.ErrTaskNotFound)
+		res, err = NewRevisionService(r, soleOwner{}).Fetch(userID, listID, taskID, &types.Pagination{})
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestRevisionService_Restore(t *testing.T) {
+	defer beQuiet()()
+	var userID, listID, taskID, revisionID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewRevisionRepositoryMock()
+		r.On("Restore", userID.String(), listID.String(), taskID.String(), revisionID.String(), userID.String()).Return(true, nil)
+		ok, err := NewRevisionService(r, soleOwner{}).Restore(userID, listID, taskID, revisionID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("viewers cannot restore", func(t *testing.T) {
+		var (
+			r       = mocks.NewRevisionRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+			ownerID = uuid.New()
+		)
+		members.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		ok, err := NewRevisionService(r, members).Restore(userID, listID, taskID, revisionID)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		assert.False(t, ok)
+		r.AssertNotCalled(t, "Restore")
+	})
+
+	t.Run("nil revision", func(t *testing.T) {
+		ok, err := NewRevisionService(nil, soleOwner{}).Restore(userID, listID, taskID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Restore", "revisionID").Error())
+		assert.False(t, ok)
+	})
+}
+
+func TestRevisionService_Undo(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, ownerID, listID = uuid.New(), uuid.New(), uuid.New()
+		revision                = func() *model.Revision {
+			return &model.Revision{
+				UUID:     uuid.New(),
+				ListUUID: listID,
+				Action:   types.RevisionActionTrashed,
+				Before:   json.RawMessage(`{"title":"Report"}`),
+			}
+		}
+		editor = func() *mocks.MemberRepository {
+			var members = mocks.NewMemberRepositoryMock()
+			members.On("FetchListAccess", userID.String(), listID.String()).
+				Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+			return members
+		}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r    = mocks.NewRevisionRepositoryMock()
+			last = revision()
+		)
+		r.On("FetchLast", userID.String(), mock.Anything).Return(last, nil)
+		r.On("Undo", ownerID.String(), last.UUID.String(), userID.String()).Return(true, nil)
+		res, err := NewRevisionService(r, editor()).Undo(userID)
+		assert.NoError(t, err)
+		assert.True(t, res.IsUndone)
+		assert.Equal(t, []*model.RevisionChange{{Field: "title", Previous: json.RawMessage(`"Report"`)}}, res.Changes)
+	})
+
+	t.Run("nothing to undo", func(t *testing.T) {
+		var r = mocks.NewRevisionRepositoryMock()
+		r.On("FetchLast", userID.String(), mock.Anything).Return(nil, failure.ErrNothingToUndo)
+		res, err := NewRevisionService(r, editor()).Undo(userID)
+		assert.ErrorIs(t, err, failure.ErrNothingToUndo)
+		assert.Nil(t, res)
+	})
+
+	t.Run("no longer allowed to change the list", func(t *testing.T) {
+		var (
+			r       = mocks.NewRevisionRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		r.On("FetchLast", userID.String(), mock.Anything).Return(revision(), nil)
+		members.On("FetchListAccess", userID.String(), listID.String()).Return(nil, failure.ErrListNotFound)
+		res, err := NewRevisionService(r, members).Undo(userID)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		assert.Nil(t, res)
+		r.AssertNotCalled(t, "Undo")
+	})
+
+	t.Run("nil user", func(t *testing.T) {
+		res, err := NewRevisionService(nil, nil).Undo(uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Undo", "userID").Error())
+		assert.Nil(t, res)
+	})
+}
+
+func TestRevisedTaskService(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		revisionID             = uuid.NewString()
+	)
+
+	t.Run("revises before changing", func(t *testing.T) {
+		var (
+			next      = mocks.NewTaskServiceMock()
+			revisions = mocks.NewRevisionRepositoryMock()
+			update    = &transfer.TaskUpdate{Description: "Clobbered"}
+		)
+		revisions.On("Save", userID.String(), listID.String(), taskID.String(), userID.String(), types.RevisionActionUpdated).
+			Return(revisionID, nil)
+		next.On("Update", userID, listID, taskID, update).Return(true, nil)
+		ok, err := NewRevisedTaskService(next, revisions, soleOwner{}).Update(userID, listID, taskID, update)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		revisions.AssertNotCalled(t, "Discard", revisionID)
+	})
+
+	t.Run("revises the tasks moved out of their list", func(t *testing.T) {
+		var (
+			next      = mocks.NewTaskServiceMock()
+			revisions = mocks.NewRevisionRepositoryMock()
+			targetID  = uuid.New()
+		)
+		next.On("Locate", userID, taskID).Return(listID, nil)
+		revisions.On("Save", userID.String(), listID.String(), taskID.String(), userID.String(), types.RevisionActionMoved).
+			Return(revisionID, nil)
+		next.On("Move", userID, taskID, targetID).Return(true, nil)
+		ok, err := NewRevisedTaskService(next, revisions, soleOwner{}).Move(userID, taskID, targetID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("discards the revision of a change that did not happen", func(t *testing.T) {
+		var (
+			next       = mocks.NewTaskServiceMock()
+			revisions  = mocks.NewRevisionRepositoryMock()
+			unexpected = errors.New("unexpected error")
+		)
+		revisions.On("Save", userID.String(), listID.String(), taskID.String(), userID.String(), types.RevisionActionTrashed).
+			Return(revisionID, nil)
+		revisions.On("Discard", revisionID).Return(nil)
+		next.On("Trash", userID, listID, taskID).Return(false, unexpected)
+		ok, err := NewRevisedTaskService(next, revisions, soleOwner{}).Trash(userID, listID, taskID)
+		assert.ErrorIs(t, err, unexpected)
+		assert.False(t, ok)
+		revisions.AssertCalled(t, "Discard", revisionID)
+	})
+
+	t.Run("does not change a task that could not be revised", func(t *testing.T) {
+		var (
+			next      = mocks.NewTaskServiceMock()
+			revisions = mocks.NewRevisionRepositoryMock()
+		)
+		revisions.On("Save", userID.String(), listID.String(), taskID.String(), userID.String(), types.RevisionActionPriorityChanged).
+			Return("", failure.ErrTaskNotFound)
+		ok, err := NewRevisedTaskService(next, revisions, soleOwner{}).SetPriority(userID, listID, taskID, types.TaskPriorityHigh)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.False(t, ok)
+		next.AssertNotCalled(t, "SetPriority")
+	})
+
+	t.Run("leaves the nil parameters to the wrapped service", func(t *testing.T) {
+		var (
+			next      = mocks.NewTaskServiceMock()
+			revisions = mocks.NewRevisionRepositoryMock()
+			nilError  = failure.NewNilParameterError("Complete", "listID")
+		)
+		next.On("Complete", userID, uuid.Nil, taskID).Return(false, nilError)
+		ok, err := NewRevisedTaskService(next, revisions, soleOwner{}).Complete(userID, uuid.Nil, taskID)
+		assert.ErrorIs(t, err, nilError)
+		assert.False(t, ok)
+		revisions.AssertNotCalled(t, "Save")
+	})
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"noda/audit"
+	"noda/repository"
+	"time"
+)
+
+// RolloverService defers the tasks of the today list and moves the tasks of
+// the tomorrow list into the today list once the day is over for their owner,
+// that is, at the local midnight of the "timezone" setting of each user. Every
+// rollover is recorded in the audit trail.
+type RolloverService interface {
+	RollOver(now time.Time) (rolled int, err error)
+}
+
+type rolloverService struct {
+	r     repository.TaskRepository
+	trail *audit.Trail
+}
+
+func NewRolloverService(r repository.TaskRepository, trail *audit.Trail) RolloverService {
+	return &rolloverService{r, trail}
+}
+
+func (s *rolloverService) RollOver(now time.Time) (rolled int, err error) {
+	candidates, err := s.r.FetchRolloverCandidates()
+	if nil != err {
+		return 0, err
+	}
+	var failed = 0
+	for _, candidate := range candidates {
+		var day = localDay(now, candidate.Timezone)
+		if !candidate.LastRolloverOn.Before(day) {
+			continue
+		}
+		ok, err := s.r.RollOver(candidate.OwnerUUID.String(), day)
+		if nil != err {
+			log.Printf("could not roll over the lists of user %q: %v", candidate.OwnerUUID, err)
+			failed++
+			continue
+		}
+		if ok {
+			s.trail.Record(audit.Origin{}, "user", candidate.OwnerUUID, "rolled_over", nil, map[string]any{"day": day.Format(time.DateOnly)})
+			rolled++
+		}
+	}
+	if 0 < failed {
+		return rolled, fmt.Errorf("could not roll over the lists of %d user(s)", failed)
+	}
+	return rolled, nil
+}
+
+// localDay is the date of now in the given time zone, as a midnight in UTC.
+// An unknown time zone is taken as UTC.
+func localDay(now time.Time, timezone string) time.Time {
+	var loc, err = time.LoadLocation(timezone)
+	if nil != err || "" == timezone {
+		loc = time.UTC
+	}
+	var year, month, day = now.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/audit"
+	"noda/data/model"
+	"noda/mocks"
+	"testing"
+	"time"
+)
+
+func TestRolloverService_RollOver(t *testing.T) {
+	defer beQuiet()()
+	var (
+		s          RolloverService
+		rolled     int
+		err        error
+		managua    = uuid.New() /* UTC-6  */
+		tokyo      = uuid.New() /* UTC+9  */
+		unknown    = uuid.New()
+		now        = time.Date(2024, time.January, 2, 3, 0, 0, 0, time.UTC)
+		first      = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+		second     = time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)
+		candidates = []*model.RolloverCandidate{
+			{OwnerUUID: managua, Timezone: "America/Managua", LastRolloverOn: first},
+			{OwnerUUID: tokyo, Timezone: "Asia/Tokyo", LastRolloverOn: first},
+			{OwnerUUID: unknown, Timezone: "Nowhere/Nothing", LastRolloverOn: first},
+		}
+	)
+
+	t.Run("only the users whose day is over roll over", func(t *testing.T) {
+		var m = mocks.NewTaskRepositoryMock()
+		var audits = mocks.NewAuditRepositoryMock()
+		m.On("FetchRolloverCandidates").Return(candidates, nil)
+		m.On("RollOver", tokyo.String(), second).Return(true, nil)
+		m.On("RollOver", unknown.String(), second).Return(true, nil)
+		audits.On("Save", mock.MatchedBy(func(entry *model.AuditEntry) bool {
+			return "user" == entry.Entity && "rolled_over" == entry.Action && nil == entry.ActorUUID
+		})).Return(uuid.NewString(), nil)
+		s = NewRolloverService(m, audit.NewTrail(audits))
+		rolled, err = s.RollOver(now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, rolled)
+		m.AssertNotCalled(t, "RollOver", managua.String(), first)
+		audits.AssertNumberOfCalls(t, "Save", 2)
+	})
+
+	t.Run("days already rolled over elsewhere are not counted", func(t *testing.T) {
+		var m = mocks.NewTaskRepositoryMock()
+		m.On("FetchRolloverCandidates").Return(candidates[1:2], nil)
+		m.On("RollOver", tokyo.String(), second).Return(false, nil)
+		s = NewRolloverService(m, nil)
+		rolled, err = s.RollOver(now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, rolled)
+	})
+
+	t.Run("a failure does not stop the other users", func(t *testing.T) {
+		var m = mocks.NewTaskRepositoryMock()
+		m.On("FetchRolloverCandidates").Return(candidates, nil)
+		m.On("RollOver", tokyo.String(), second).Return(false, errors.New("unexpected error"))
+		m.On("RollOver", unknown.String(), second).Return(true, nil)
+		s = NewRolloverService(m, nil)
+		rolled, err = s.RollOver(now)
+		assert.ErrorContains(t, err, "1 user(s)")
+		assert.Equal(t, 1, rolled)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var m = mocks.NewTaskRepositoryMock()
+		m.On("FetchRolloverCandidates").Return(nil, unexpected)
+		s = NewRolloverService(m, nil)
+		rolled, err = s.RollOver(now)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Equal(t, 0, rolled)
+	})
+}
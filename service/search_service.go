@@ -0,0 +1,223 @@
+package service
+
+import (
+	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// searchLimit is how many of the best ranked hits a search looks at, over
+// the kinds of things it keeps.
+const searchLimit = 1 << 10
+
+// titleBoost is added to the rank of a hit whose title has all the words
+// looked for, and in proportion to a hit whose title has some of them, so
+// that the things named after what is looked for come before the ones that
+// only mention it.
+const titleBoost = 1.0
+
+// searchEntityOrder breaks the ties between hits ranked the same.
+var searchEntityOrder = map[types.SearchEntity]int{
+	types.SearchEntityTask:    0,
+	types.SearchEntityStep:    1,
+	types.SearchEntityList:    2,
+	types.SearchEntityGroup:   3,
+	types.SearchEntityTag:     4,
+	types.SearchEntityComment: 5,
+}
+
+// SearchService looks for text in the tasks, steps, lists, groups, tags and
+// comments a user can see.
+type SearchService interface {
+	Search(userID uuid.UUID, needle string, entities []types.SearchEntity, pagination *types.Pagination) (result *model.SearchResult, err error)
+}
+
+type searchService struct {
+	r repository.SearchRepository
+}
+
+func NewSearchService(r repository.SearchRepository) SearchService {
+	return &searchService{r}
+}
+
+// Search retrieves a page of the things that match the needle, the best ranked
+// first, keeping only the given kinds of things if any. The facets count all
+// the hits of every kind, whichever are kept.
+func (s *searchService) Search(userID uuid.UUID, needle string, entities []types.SearchEntity, pagination *types.Pagination) (result *model.SearchResult, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Search", "userID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("Search", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	result = &model.SearchResult{
+		Page:    pagination.Page,
+		RPP:     pagination.RPP,
+		Facets:  make(map[types.SearchEntity]int64),
+		Payload: make([]*model.SearchHit, 0),
+	}
+	var query, words = toTextSearchQuery(needle)
+	if "" == query {
+		return result, nil
+	}
+	kept, err := s.r.Search(userID.String(), query, entities, searchLimit)
+	if nil != err {
+		return nil, err
+	}
+	result.Facets, err = s.r.CountHits(userID.String(), query)
+	if nil != err {
+		return nil, err
+	}
+	rankHits(kept, words)
+	var skip = (pagination.Page - 1) * pagination.RPP
+	if skip < int64(len(kept)) {
+		kept = kept[skip:]
+		if pagination.RPP < int64(len(kept)) {
+			kept = kept[:pagination.RPP]
+		}
+		result.Payload = kept
+	}
+	result.Retrieved = int64(len(result.Payload))
+	return result, nil
+}
+
+// rankHits boosts the rank of the hits whose title has the words, and sorts
+// the hits by rank, then by kind and then by title.
+func rankHits(hits []*model.SearchHit, words []string) {
+	for _, hit := range hits {
+		if 0 < len(words) {
+			hit.Rank += titleBoost * float64(countTitleWords(hit.Title, words)) / float64(len(words))
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		var a, b = hits[i], hits[j]
+		switch {
+		case a.Rank != b.Rank:
+			return a.Rank > b.Rank
+		case a.Entity != b.Entity:
+			return searchEntityOrder[a.Entity] < searchEntityOrder[b.Entity]
+		default:
+			return strings.ToLower(a.Title) < strings.ToLower(b.Title)
+		}
+	})
+}
+
+// countTitleWords counts the words that begin a word of the title.
+func countTitleWords(title string, words []string) (count int) {
+	var titleWords = strings.FieldsFunc(strings.ToLower(title), isNotWordRune)
+	for _, word := range words {
+		if slices.ContainsFunc(titleWords, func(titleWord string) bool { return strings.HasPrefix(titleWord, word) }) {
+			count++
+		}
+	}
+	return count
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// toTextSearchQuery turns what a user typed into a text search query. Every
+// word must be found, as the beginning of a word, unless "or" stands between
+// them. A quoted text must be found as is, and a word or quoted text starting
+// with "-" must not be found. Anything but letters and digits separates words.
+// It returns an empty string if nothing is left to look for, along with the
+// words looked for, lowercased, leaving out the ones that must not be found.
+func toTextSearchQuery(needle string) (query string, words []string) {
+	var (
+		groups [][]string
+		terms  []string
+		wanted bool
+		flush  = func() {
+			if wanted {
+				groups = append(groups, terms)
+			}
+			terms, wanted = nil, false
+		}
+		runes = []rune(needle)
+	)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+		var negated = '-' == runes[i]
+		if negated {
+			i++
+		}
+		var text string
+		var quoted = i < len(runes) && '"' == runes[i]
+		if quoted {
+			var end = i + 1
+			for end < len(runes) && '"' != runes[end] {
+				end++
+			}
+			text = string(runes[i+1 : end])
+			i = end + 1
+		} else {
+			var end = i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && '"' != runes[end] {
+				end++
+			}
+			text = string(runes[i:end])
+			i = end
+		}
+		if !quoted && !negated && strings.EqualFold("or", text) {
+			flush()
+			continue
+		}
+		var term = toTextSearchTerm(text, !quoted)
+		if "" == term {
+			continue
+		}
+		if negated {
+			term = "!" + term
+		} else {
+			wanted = true
+			for _, word := range strings.FieldsFunc(strings.ToLower(text), isNotWordRune) {
+				if !slices.Contains(words, word) {
+					words = append(words, word)
+				}
+			}
+		}
+		terms = append(terms, term)
+	}
+	flush()
+	var ors = make([]string, 0, len(groups))
+	for _, group := range groups {
+		ors = append(ors, strings.Join(group, " & "))
+	}
+	return strings.Join(ors, " | "), words
+}
+
+// toTextSearchTerm makes the words of the text a phrase, the last of which is
+// matched as a prefix if asked to.
+func toTextSearchTerm(text string, prefix bool) string {
+	var words = strings.FieldsFunc(strings.ToLower(text), isNotWordRune)
+	switch len(words) {
+	case 0:
+		return ""
+	case 1:
+		if prefix {
+			return words[0] + ":*"
+		}
+		return words[0]
+	}
+	if prefix {
+		words[len(words)-1] += ":*"
+	}
+	return "(" + strings.Join(words, " <-> ") + ")"
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+)
+
+func TestSearchService_Search(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID = uuid.New()
+		hit    = func(entity types.SearchEntity, title string, rank float64) *model.SearchHit {
+			return &model.SearchHit{Entity: entity, EntityUUID: uuid.New(), Title: title, Rank: rank}
+		}
+		facets = map[types.SearchEntity]int64{
+			types.SearchEntityTask:    1200,
+			types.SearchEntityTag:     2,
+			types.SearchEntityComment: 1,
+		}
+	)
+
+	t.Run("ranks the things named after the words first", func(t *testing.T) {
+		var (
+			r = mocks.NewSearchRepositoryMock()
+			// Only mentions the fence, although a lot.
+			comment = hit(types.SearchEntityComment, "Saturday", 0.8)
+			// Named after both words.
+			task = hit(types.SearchEntityTask, "Paint the fence", 0.2)
+			// Named after one of the words.
+			step = hit(types.SearchEntityStep, "Buy paint", 0.5)
+			// Named after both words, ranked the same as task but of
+			// another kind, or of the same kind with another title.
+			list  = hit(types.SearchEntityList, "Fence painting", 0.2)
+			other = hit(types.SearchEntityTask, "Fences to paint", 0.2)
+		)
+		r.On("Search", userID.String(), "paint:* & fence:*", []types.SearchEntity(nil), int64(searchLimit)).
+			Return([]*model.SearchHit{comment, list, other, step, task}, nil)
+		r.On("CountHits", userID.String(), "paint:* & fence:*").Return(facets, nil)
+		res, err := NewSearchService(r).Search(userID, "Paint fence", nil, &types.Pagination{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.SearchHit{other, task, list, step, comment}, res.Payload)
+		assert.InDelta(t, 1.2, task.Rank, 1e-9)
+		assert.InDelta(t, 1.0, step.Rank, 1e-9)
+		assert.InDelta(t, 0.8, comment.Rank, 1e-9)
+		assert.Equal(t, int64(5), res.Retrieved)
+	})
+
+	t.Run("keeps the given kinds and counts them all", func(t *testing.T) {
+		var (
+			r        = mocks.NewSearchRepositoryMock()
+			entities = []types.SearchEntity{types.SearchEntityTag, types.SearchEntityComment}
+			tag      = hit(types.SearchEntityTag, "fence", 0.6)
+			otherTag = hit(types.SearchEntityTag, "Fencing", 0.6)
+			comment  = hit(types.SearchEntityComment, "About the fence", 0.3)
+		)
+		r.On("Search", userID.String(), "fence:*", entities, int64(searchLimit)).
+			Return([]*model.SearchHit{comment, otherTag, tag}, nil)
+		r.On("CountHits", userID.String(), "fence:*").Return(facets, nil)
+		res, err := NewSearchService(r).Search(userID, "fence", entities, &types.Pagination{Page: 1, RPP: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.SearchHit{tag, comment}, res.Payload)
+		assert.Equal(t, facets, res.Facets)
+	})
+
+	t.Run("page past the hits", func(t *testing.T) {
+		var r = mocks.NewSearchRepositoryMock()
+		r.On("Search", userID.String(), "fence:*", []types.SearchEntity(nil), int64(searchLimit)).
+			Return([]*model.SearchHit{hit(types.SearchEntityTask, "Fence", 0.1)}, nil)
+		r.On("CountHits", userID.String(), "fence:*").Return(facets, nil)
+		res, err := NewSearchService(r).Search(userID, "fence", nil, &types.Pagination{Page: 3, RPP: 5})
+		assert.NoError(t, err)
+		assert.Empty(t, res.Payload)
+		assert.Equal(t, int64(0), res.Retrieved)
+		assert.Equal(t, facets, res.Facets)
+	})
+
+	t.Run("nothing to look for", func(t *testing.T) {
+		var r = mocks.NewSearchRepositoryMock()
+		res, err := NewSearchService(r).Search(userID, ` -fence "" ?! `, nil, &types.Pagination{})
+		assert.NoError(t, err)
+		assert.Empty(t, res.Payload)
+		assert.Empty(t, res.Facets)
+		r.AssertNotCalled(t, "Search")
+		r.AssertNotCalled(t, "CountHits")
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		var r = mocks.NewSearchRepositoryMock()
+		r.On("Search", userID.String(), "fence:*", []types.SearchEntity(nil), int64(searchLimit)).Return(nil, failure.ErrUserNoLongerExists)
+		res, err := NewSearchService(r).Search(userID, "fence", nil, &types.Pagination{})
+		assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+		assert.Nil(t, res)
+	})
+
+	t.Run("nil pagination", func(t *testing.T) {
+		res, err := NewSearchService(nil).Search(userID, "fence", nil, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Search", "pagination").Error())
+		assert.Nil(t, res)
+	})
+}
+
+func TestToTextSearchQuery(t *testing.T) {
+	for needle, expected := range map[string]string{
+		"paint":                      "paint:*",
+		"  Paint   the FENCE ":       "paint:* & the:* & fence:*",
+		"milk or bread":              "milk:* | bread:*",
+		"milk OR bread eggs":         "milk:* | bread:* & eggs:*",
+		`"white fence" paint`:        "(white <-> fence) & paint:*",
+		"fence -blue":                "fence:* & !blue:*",
+		`fence -"blue paint"`:        "fence:* & !(blue <-> paint)",
+		"e-mail":                     "(e <-> mail:*)",
+		"it's & fine | ok:* !":       "(it <-> s:*) & fine:* & ok:*",
+		"or milk or":                 "milk:*",
+		"-blue or red":               "red:*",
+		`"unterminated quote`:        "(unterminated <-> quote)",
+		"café 2024":                  "café:* & 2024:*",
+		"":                           "",
+		"-":                          "",
+		`""`:                         "",
+		"or":                         "",
+		"orchard":                    "orchard:*",
+		"-blue -red":                 "",
+		"a:b":                        "(a <-> b:*)",
+		"fence\tpaint\nwhite":        "fence:* & paint:* & white:*",
+		"'; DROP TABLE tasks; --":    "drop:* & table:* & tasks:*",
+		"milk or -bread or -cheese":  "milk:*",
+		"milk -bread or eggs -bacon": "milk:* & !bread:* | eggs:* & !bacon:*",
+	} {
+		var query, _ = toTextSearchQuery(needle)
+		assert.Equal(t, expected, query, "needle: %q", needle)
+	}
+	var _, words = toTextSearchQuery(`Paint "the FENCE" -blue or paint e-mail`)
+	assert.Equal(t, []string{"paint", "the", "fence", "e", "mail"}, words)
+}
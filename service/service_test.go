@@ -1,7 +1,11 @@
 package service
 
 import (
+	"github.com/google/uuid"
 	"log"
+	"noda/data/model"
+	"noda/data/types"
+	"noda/repository"
 	"os"
 )
 
@@ -21,3 +25,25 @@ func beQuiet() func() {
 		log.SetOutput(os.Stderr)
 	}
 }
+
+// soleOwner lets every user act on every list and group as their owner, for
+// the tests that are not about sharing.
+type soleOwner struct {
+	repository.MemberRepository
+}
+
+func (soleOwner) FetchListAccess(userID, listID string) (*model.ListAccess, error) {
+	return &model.ListAccess{
+		ListUUID:  uuid.MustParse(listID),
+		OwnerUUID: uuid.MustParse(userID),
+		Role:      types.MemberRoleOwner,
+	}, nil
+}
+
+func (soleOwner) FetchGroupAccess(userID, groupID string) (*model.GroupAccess, error) {
+	return &model.GroupAccess{
+		GroupUUID: uuid.MustParse(groupID),
+		OwnerUUID: uuid.MustParse(userID),
+		Role:      types.MemberRoleOwner,
+	}, nil
+}
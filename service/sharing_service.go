@@ -0,0 +1,295 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+)
+
+type SharingService interface {
+	Invite(inviterID uuid.UUID, kind types.ShareKind, targetID uuid.UUID, invitation *transfer.Invitation) (insertedID uuid.UUID, err error)
+	FetchMembers(userID uuid.UUID, kind types.ShareKind, targetID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Member], err error)
+	SetMemberRole(userID uuid.UUID, kind types.ShareKind, targetID, memberID uuid.UUID, update *transfer.MemberRoleUpdate) (ok bool, err error)
+	RemoveMember(userID uuid.UUID, kind types.ShareKind, targetID, memberID uuid.UUID) (ok bool, err error)
+	FetchInvitations(userID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Invitation], err error)
+	AcceptInvitation(userID, invitationID uuid.UUID) (ok bool, err error)
+	DeclineInvitation(userID, invitationID uuid.UUID) (ok bool, err error)
+	FetchSharedLists(userID uuid.UUID, pagination *types.Pagination, needle string) (result *types.Result[model.SharedList], err error)
+	FetchSharedGroups(userID uuid.UUID, pagination *types.Pagination, needle string) (result *types.Result[model.SharedGroup], err error)
+}
+
+type sharingService struct {
+	r repository.MemberRepository
+}
+
+func NewSharingService(r repository.MemberRepository) SharingService {
+	return &sharingService{r: r}
+}
+
+// authorize makes sure that the user has at least the needed role in the list
+// or group.
+func (s *sharingService) authorize(userID uuid.UUID, kind types.ShareKind, targetID uuid.UUID, needed types.MemberRole) error {
+	var err error
+	switch kind {
+	default:
+		err = failure.ErrBadRequest.Clone().SetDetails("Only lists and groups can be shared.")
+	case types.ShareKindList:
+		_, err = authorizeList(s.r, userID, targetID, needed)
+	case types.ShareKindGroup:
+		_, err = authorizeGroup(s.r, userID, targetID, needed)
+	}
+	return err
+}
+
+// Invite invites a user to become a member of a list or a group. Only its
+// owner and admins can invite.
+func (s *sharingService) Invite(
+	inviterID uuid.UUID,
+	kind types.ShareKind,
+	targetID uuid.UUID,
+	invitation *transfer.Invitation,
+) (insertedID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == inviterID:
+		err = failure.NewNilParameterError("Invite", "inviterID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == targetID:
+		err = failure.NewNilParameterError("Invite", "targetID")
+		log.Println(err)
+		return uuid.Nil, err
+	case nil == invitation:
+		err = failure.NewNilParameterError("Invite", "invitation")
+		log.Println(err)
+		return uuid.Nil, err
+	}
+	doTrim(&invitation.Email)
+	if types.MemberRoleOwner == invitation.Role || !invitation.Role.Grants(types.MemberRoleViewer) {
+		return uuid.Nil, failure.ErrBadRequest.Clone().SetDetails("A member can only be a viewer, an editor or an admin.")
+	}
+	err = s.authorize(inviterID, kind, targetID, types.MemberRoleAdmin)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	id, err := s.r.Invite(inviterID.String(), kind, targetID.String(), invitation.Email, invitation.Role)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(id)
+}
+
+func (s *sharingService) FetchMembers(
+	userID uuid.UUID,
+	kind types.ShareKind,
+	targetID uuid.UUID,
+	pagination *types.Pagination,
+) (result *types.Result[model.Member], err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchMembers", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == targetID:
+		err = failure.NewNilParameterError("FetchMembers", "targetID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("FetchMembers", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	err = s.authorize(userID, kind, targetID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	members, err := s.r.FetchMembers(kind, targetID.String(), pagination.Page, pagination.RPP)
+	if nil != err {
+		return nil, err
+	}
+	return &types.Result[model.Member]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(members)),
+		Payload:   members,
+	}, nil
+}
+
+// SetMemberRole changes the role of a member. Only the owner and admins can
+// change roles, and never their own.
+func (s *sharingService) SetMemberRole(
+	userID uuid.UUID,
+	kind types.ShareKind,
+	targetID, memberID uuid.UUID,
+	update *transfer.MemberRoleUpdate,
+) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("SetMemberRole", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == targetID:
+		err = failure.NewNilParameterError("SetMemberRole", "targetID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == memberID:
+		err = failure.NewNilParameterError("SetMemberRole", "memberID")
+		log.Println(err)
+		return false, err
+	case nil == update:
+		err = failure.NewNilParameterError("SetMemberRole", "update")
+		log.Println(err)
+		return false, err
+	case types.MemberRoleOwner == update.Role || !update.Role.Grants(types.MemberRoleViewer):
+		return false, failure.ErrBadRequest.Clone().SetDetails("A member can only be a viewer, an editor or an admin.")
+	case userID == memberID:
+		return false, failure.ErrInsufficientRole.Clone().SetDetails("You cannot change your own role.")
+	}
+	err = s.authorize(userID, kind, targetID, types.MemberRoleAdmin)
+	if nil != err {
+		return false, err
+	}
+	return s.r.SetRole(kind, targetID.String(), memberID.String(), update.Role)
+}
+
+// RemoveMember removes a member from a list or a group. Only the owner and
+// admins can remove others, but every member can leave.
+func (s *sharingService) RemoveMember(userID uuid.UUID, kind types.ShareKind, targetID, memberID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("RemoveMember", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == targetID:
+		err = failure.NewNilParameterError("RemoveMember", "targetID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == memberID:
+		err = failure.NewNilParameterError("RemoveMember", "memberID")
+		log.Println(err)
+		return false, err
+	}
+	var needed = types.MemberRoleAdmin
+	if userID == memberID {
+		needed = types.MemberRoleViewer
+	}
+	err = s.authorize(userID, kind, targetID, needed)
+	if nil != err {
+		return false, err
+	}
+	return s.r.Remove(kind, targetID.String(), memberID.String())
+}
+
+func (s *sharingService) FetchInvitations(userID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Invitation], err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchInvitations", "userID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("FetchInvitations", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	invitations, err := s.r.FetchInvitations(userID.String(), pagination.Page, pagination.RPP)
+	if nil != err {
+		return nil, err
+	}
+	return &types.Result[model.Invitation]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(invitations)),
+		Payload:   invitations,
+	}, nil
+}
+
+func (s *sharingService) AcceptInvitation(userID, invitationID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("AcceptInvitation", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == invitationID:
+		err = failure.NewNilParameterError("AcceptInvitation", "invitationID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Accept(userID.String(), invitationID.String())
+}
+
+func (s *sharingService) DeclineInvitation(userID, invitationID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("DeclineInvitation", "userID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == invitationID:
+		err = failure.NewNilParameterError("DeclineInvitation", "invitationID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Decline(userID.String(), invitationID.String())
+}
+
+func (s *sharingService) FetchSharedLists(
+	userID uuid.UUID,
+	pagination *types.Pagination,
+	needle string,
+) (result *types.Result[model.SharedList], err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchSharedLists", "userID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("FetchSharedLists", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doTrim(&needle)
+	doDefaultPagination(pagination)
+	lists, err := s.r.FetchSharedLists(userID.String(), pagination.Page, pagination.RPP, needle)
+	if nil != err {
+		return nil, err
+	}
+	return &types.Result[model.SharedList]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(lists)),
+		Payload:   lists,
+	}, nil
+}
+
+func (s *sharingService) FetchSharedGroups(
+	userID uuid.UUID,
+	pagination *types.Pagination,
+	needle string,
+) (result *types.Result[model.SharedGroup], err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchSharedGroups", "userID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("FetchSharedGroups", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doTrim(&needle)
+	doDefaultPagination(pagination)
+	groups, err := s.r.FetchSharedGroups(userID.String(), pagination.Page, pagination.RPP, needle)
+	if nil != err {
+		return nil, err
+	}
+	return &types.Result[model.SharedGroup]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(groups)),
+		Payload:   groups,
+	}, nil
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+)
+
+func TestSharingService_Invite(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID, invitationID = uuid.New(), uuid.New(), uuid.New()
+		invitation                   = &transfer.Invitation{Email: " member@noda.com ", Role: types.MemberRoleEditor}
+		res                          uuid.UUID
+		err                          error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: userID, Role: types.MemberRoleOwner}, nil)
+		m.On("Invite", userID.String(), types.ShareKindList, listID.String(), "member@noda.com", types.MemberRoleEditor).
+			Return(invitationID.String(), nil)
+		res, err = NewSharingService(m).Invite(userID, types.ShareKindList, listID, invitation)
+		assert.NoError(t, err)
+		assert.Equal(t, invitationID, res)
+	})
+
+	t.Run("only admins can invite", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: uuid.New(), Role: types.MemberRoleEditor}, nil)
+		res, err = NewSharingService(m).Invite(userID, types.ShareKindList, listID, invitation)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		assert.Equal(t, uuid.Nil, res)
+		m.AssertNotCalled(t, "Invite")
+	})
+
+	t.Run("cannot invite as owner", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		res, err = NewSharingService(m).Invite(userID, types.ShareKindGroup, listID, &transfer.Invitation{Email: "a@b.c", Role: types.MemberRoleOwner})
+		assert.ErrorContains(t, err, "viewer, an editor or an admin")
+		assert.Equal(t, uuid.Nil, res)
+		m.AssertNotCalled(t, "FetchGroupAccess")
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		_, err = NewSharingService(m).Invite(uuid.Nil, types.ShareKindList, listID, invitation)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Invite", "inviterID").Error())
+		_, err = NewSharingService(m).Invite(userID, types.ShareKindList, uuid.Nil, invitation)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Invite", "targetID").Error())
+		_, err = NewSharingService(m).Invite(userID, types.ShareKindList, listID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Invite", "invitation").Error())
+		m.AssertNotCalled(t, "Invite")
+	})
+}
+
+func TestSharingService_FetchMembers(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, groupID = uuid.New(), uuid.New()
+		res             *types.Result[model.Member]
+		err             error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			m       = mocks.NewMemberRepositoryMock()
+			members = []*model.Member{{}, {}}
+		)
+		m.On("FetchGroupAccess", userID.String(), groupID.String()).
+			Return(&model.GroupAccess{GroupUUID: groupID, OwnerUUID: uuid.New(), Role: types.MemberRoleViewer}, nil)
+		m.On("FetchMembers", types.ShareKindGroup, groupID.String(), int64(1), int64(10)).Return(members, nil)
+		res, err = NewSharingService(m).FetchMembers(userID, types.ShareKindGroup, groupID, &types.Pagination{})
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.Member]{Page: 1, RPP: 10, Retrieved: 2, Payload: members}, res)
+	})
+
+	t.Run("group not shared with the user", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchGroupAccess", mock.Anything, mock.Anything).Return(nil, failure.ErrGroupNotFound)
+		res, err = NewSharingService(m).FetchMembers(userID, types.ShareKindGroup, groupID, &types.Pagination{})
+		assert.ErrorIs(t, err, failure.ErrGroupNotFound)
+		assert.Nil(t, res)
+		m.AssertNotCalled(t, "FetchMembers")
+	})
+}
+
+func TestSharingService_SetMemberRole(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID, memberID = uuid.New(), uuid.New(), uuid.New()
+		update                   = &transfer.MemberRoleUpdate{Role: types.MemberRoleAdmin}
+		res                      bool
+		err                      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: uuid.New(), Role: types.MemberRoleAdmin}, nil)
+		m.On("SetRole", types.ShareKindList, listID.String(), memberID.String(), types.MemberRoleAdmin).Return(true, nil)
+		res, err = NewSharingService(m).SetMemberRole(userID, types.ShareKindList, listID, memberID, update)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("cannot change its own role", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		res, err = NewSharingService(m).SetMemberRole(userID, types.ShareKindList, listID, userID, update)
+		assert.ErrorContains(t, err, "your own role")
+		assert.False(t, res)
+		m.AssertNotCalled(t, "SetRole")
+	})
+
+	t.Run("only admins can change roles", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: uuid.New(), Role: types.MemberRoleEditor}, nil)
+		res, err = NewSharingService(m).SetMemberRole(userID, types.ShareKindList, listID, memberID, update)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		assert.False(t, res)
+		m.AssertNotCalled(t, "SetRole")
+	})
+}
+
+func TestSharingService_RemoveMember(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID, listID, memberID = uuid.New(), uuid.New(), uuid.New()
+		res                      bool
+		err                      error
+	)
+
+	t.Run("an admin removes a member", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: uuid.New(), Role: types.MemberRoleAdmin}, nil)
+		m.On("Remove", types.ShareKindList, listID.String(), memberID.String()).Return(true, nil)
+		res, err = NewSharingService(m).RemoveMember(userID, types.ShareKindList, listID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("a viewer leaves", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: userID, Role: types.MemberRoleViewer}, nil)
+		m.On("Remove", types.ShareKindList, listID.String(), memberID.String()).Return(true, nil)
+		res, err = NewSharingService(m).RemoveMember(memberID, types.ShareKindList, listID, memberID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("a viewer cannot remove others", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchListAccess", userID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: uuid.New(), Role: types.MemberRoleViewer}, nil)
+		res, err = NewSharingService(m).RemoveMember(userID, types.ShareKindList, listID, memberID)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		assert.False(t, res)
+		m.AssertNotCalled(t, "Remove")
+	})
+
+	t.Run("cannot share anything else", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		res, err = NewSharingService(m).RemoveMember(userID, types.ShareKind("task"), listID, memberID)
+		assert.ErrorContains(t, err, "Only lists and groups")
+		assert.False(t, res)
+	})
+}
+
+func TestSharingService_FetchInvitations(t *testing.T) {
+	defer beQuiet()()
+	var (
+		userID = uuid.New()
+		res    *types.Result[model.Invitation]
+		err    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			m           = mocks.NewMemberRepositoryMock()
+			invitations = []*model.Invitation{{}}
+		)
+		m.On("FetchInvitations", userID.String(), int64(2), int64(5)).Return(invitations, nil)
+		res, err = NewSharingService(m).FetchInvitations(userID, &types.Pagination{Page: 2, RPP: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.Invitation]{Page: 2, RPP: 5, Retrieved: 1, Payload: invitations}, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("FetchInvitations", mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
+		res, err = NewSharingService(m).FetchInvitations(userID, &types.Pagination{})
+		assert.ErrorIs(t, err, unexpected)
+		assert.Nil(t, res)
+	})
+}
+
+func TestSharingService_AcceptInvitation(t *testing.T) {
+	defer beQuiet()()
+	var userID, invitationID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("Accept", userID.String(), invitationID.String()).Return(true, nil)
+		res, err := NewSharingService(m).AcceptInvitation(userID, invitationID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("nil parameters", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		_, err := NewSharingService(m).AcceptInvitation(userID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("AcceptInvitation", "invitationID").Error())
+		m.AssertNotCalled(t, "Accept")
+	})
+}
+
+func TestSharingService_DeclineInvitation(t *testing.T) {
+	defer beQuiet()()
+	var userID, invitationID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("Decline", userID.String(), invitationID.String()).Return(true, nil)
+		res, err := NewSharingService(m).DeclineInvitation(userID, invitationID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("invitation not found", func(t *testing.T) {
+		var m = mocks.NewMemberRepositoryMock()
+		m.On("Decline", mock.Anything, mock.Anything).Return(false, failure.ErrInvitationNotFound)
+		res, err := NewSharingService(m).DeclineInvitation(userID, invitationID)
+		assert.ErrorIs(t, err, failure.ErrInvitationNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestSharingService_FetchSharedLists(t *testing.T) {
+	defer beQuiet()()
+	var userID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			m     = mocks.NewMemberRepositoryMock()
+			lists = []*model.SharedList{{}, {}}
+		)
+		m.On("FetchSharedLists", userID.String(), int64(1), int64(10), "groceries").Return(lists, nil)
+		res, err := NewSharingService(m).FetchSharedLists(userID, &types.Pagination{}, blankset+"groceries"+blankset)
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.SharedList]{Page: 1, RPP: 10, Retrieved: 2, Payload: lists}, res)
+	})
+}
+
+func TestSharingService_FetchSharedGroups(t *testing.T) {
+	defer beQuiet()()
+	var userID = uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			m      = mocks.NewMemberRepositoryMock()
+			groups = []*model.SharedGroup{{}}
+		)
+		m.On("FetchSharedGroups", userID.String(), int64(1), int64(10), "").Return(groups, nil)
+		res, err := NewSharingService(m).FetchSharedGroups(userID, &types.Pagination{}, "")
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.SharedGroup]{Page: 1, RPP: 10, Retrieved: 1, Payload: groups}, res)
+	})
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/filter"
+	"noda/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SmartListService keeps the saved filters of a user and finds the tasks they
+// hold. See the filter package for the expression language.
+type SmartListService interface {
+	Save(ownerID uuid.UUID, creation *transfer.SmartListCreation) (insertedID uuid.UUID, err error)
+	FetchByID(ownerID, smartListID uuid.UUID) (smartList *model.SmartList, err error)
+	Fetch(ownerID uuid.UUID) (smartLists []*model.SmartList, err error)
+	FetchTasks(ownerID, smartListID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Task], err error)
+	Update(ownerID, smartListID uuid.UUID, update *transfer.SmartListUpdate) (ok bool, err error)
+	Delete(ownerID, smartListID uuid.UUID) error
+}
+
+// The tasks are matched against a smart list by batches of
+// candidateBatchSize, and at most maxCandidates of them are looked through.
+const (
+	candidateBatchSize = 1 << 8
+	maxCandidates      = 1 << 14
+)
+
+type smartListService struct {
+	r     repository.SmartListRepository
+	users UserService
+	batch int64
+	now   func() time.Time
+}
+
+// NewSmartListService creates a SmartListService that takes the days of the
+// filters, such as "today", in the "timezone" setting of the user.
+func NewSmartListService(r repository.SmartListRepository, users UserService) SmartListService {
+	return &smartListService{r: r, users: users, batch: candidateBatchSize, now: time.Now}
+}
+
+func (s *smartListService) Save(ownerID uuid.UUID, creation *transfer.SmartListCreation) (insertedID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Save", "ownerID")
+		log.Println(err)
+		return uuid.Nil, err
+	case nil == creation:
+		err = failure.NewNilParameterError("Save", "creation")
+		log.Println(err)
+		return uuid.Nil, err
+	}
+	doTrim(&creation.Name, &creation.Expression)
+	switch {
+	case "" == creation.Name:
+		return uuid.Nil, errors.New("name cannot be an empty string") // must've been handled by validator
+	case 1<<5 < len(creation.Name):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("name", "smart list", 1<<5)
+	case 1<<9 < len(creation.Expression):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("expression", "smart list", 1<<9)
+	}
+	if _, err = filter.Parse(creation.Expression); nil != err {
+		return uuid.Nil, failure.ErrBadFilter.Clone().FormatDetails(err.Error())
+	}
+	inserted, err := s.r.Save(ownerID.String(), creation)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(inserted)
+}
+
+func (s *smartListService) FetchByID(ownerID, smartListID uuid.UUID) (smartList *model.SmartList, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("FetchByID", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == smartListID:
+		err = failure.NewNilParameterError("FetchByID", "smartListID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.r.FetchByID(ownerID.String(), smartListID.String())
+}
+
+func (s *smartListService) Fetch(ownerID uuid.UUID) (smartLists []*model.SmartList, err error) {
+	if uuid.Nil == ownerID {
+		err = failure.NewNilParameterError("Fetch", "ownerID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.r.Fetch(ownerID.String())
+}
+
+// FetchTasks evaluates the smart list and retrieves a page of the tasks that
+// match it, with relative dates taken from now and days from the time zone of
+// the user. The candidates, narrowed down by the filter beforehand, are
+// fetched by batches until the page is full, or maxCandidates of them were
+// looked through, in which case the result is truncated.
+func (s *smartListService) FetchTasks(ownerID, smartListID uuid.UUID, pagination *types.Pagination) (result *types.Result[model.Task], err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("FetchTasks", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == smartListID:
+		err = failure.NewNilParameterError("FetchTasks", "smartListID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("FetchTasks", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	smartList, err := s.r.FetchByID(ownerID.String(), smartListID.String())
+	if nil != err {
+		return nil, err
+	}
+	f, err := filter.Parse(smartList.Expression)
+	if nil != err {
+		return nil, failure.ErrBadFilter.Clone().FormatDetails(err.Error())
+	}
+	loc, err := s.location(ownerID)
+	if nil != err {
+		return nil, err
+	}
+	var (
+		now        = s.now().In(loc)
+		candidates = f.Candidates(now)
+		skip       = (pagination.Page - 1) * pagination.RPP
+		tasks      = make([]*model.Task, 0, pagination.RPP)
+		truncated  = false
+	)
+	for page := int64(1); int64(len(tasks)) < pagination.RPP; page++ {
+		if maxCandidates <= (page-1)*s.batch {
+			truncated = true
+			break
+		}
+		fetched, err := s.r.FetchCandidates(ownerID.String(), candidates, page, s.batch)
+		if nil != err {
+			return nil, err
+		}
+		for _, candidate := range fetched {
+			if int64(len(tasks)) == pagination.RPP {
+				break
+			}
+			if !f.Match(candidate, now) {
+				continue
+			}
+			if 0 < skip {
+				skip--
+				continue
+			}
+			tasks = append(tasks, candidate.Task)
+		}
+		if int64(len(fetched)) < s.batch {
+			break
+		}
+	}
+	return &types.Result[model.Task]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(tasks)),
+		Payload:   tasks,
+		Truncated: truncated,
+	}, nil
+}
+
+// location is the time zone of the "timezone" setting of the user, or UTC if
+// it is not set or unknown, as for the daily rollover.
+func (s *smartListService) location(ownerID uuid.UUID) (*time.Location, error) {
+	setting, err := s.users.FetchOneSetting(ownerID, "timezone")
+	if errors.Is(err, failure.ErrSettingNotFound) {
+		return time.UTC, nil
+	}
+	if nil != err {
+		return nil, err
+	}
+	var timezone, _ = setting.Value.(string)
+	loc, err := time.LoadLocation(timezone)
+	if nil != err || "" == timezone {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+func (s *smartListService) Update(ownerID, smartListID uuid.UUID, update *transfer.SmartListUpdate) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Update", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == smartListID:
+		err = failure.NewNilParameterError("Update", "smartListID")
+		log.Println(err)
+		return false, err
+	case nil == update:
+		err = failure.NewNilParameterError("Update", "update")
+		log.Println(err)
+		return false, err
+	}
+	doTrim(&update.Name, &update.Expression)
+	switch {
+	case "" == update.Name && "" == update.Expression:
+		return false, nil
+	case 1<<5 < len(update.Name):
+		return false, failure.ErrTooLong.Clone().FormatDetails("name", "smart list", 1<<5)
+	case 1<<9 < len(update.Expression):
+		return false, failure.ErrTooLong.Clone().FormatDetails("expression", "smart list", 1<<9)
+	}
+	if "" != update.Expression {
+		if _, err = filter.Parse(update.Expression); nil != err {
+			return false, failure.ErrBadFilter.Clone().FormatDetails(err.Error())
+		}
+	}
+	return s.r.Update(ownerID.String(), smartListID.String(), update)
+}
+
+func (s *smartListService) Delete(ownerID, smartListID uuid.UUID) error {
+	var err error
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Delete", "ownerID")
+		log.Println(err)
+		return err
+	case uuid.Nil == smartListID:
+		err = failure.NewNilParameterError("Delete", "smartListID")
+		log.Println(err)
+		return err
+	}
+	return s.r.Delete(ownerID.String(), smartListID.String())
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSmartListService_Save(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID  = uuid.New()
+		inserted = uuid.New()
+		res      uuid.UUID
+		err      error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r        = mocks.NewSmartListRepositoryMock()
+			creation = &transfer.SmartListCreation{Name: " Urgent ", Expression: " priority>=high and not completed "}
+		)
+		r.On("Save", ownerID.String(), creation).Return(inserted.String(), nil)
+		res, err = NewSmartListService(r, nil).Save(ownerID, creation)
+		assert.NoError(t, err)
+		assert.Equal(t, inserted, res)
+		assert.Equal(t, "priority>=high and not completed", creation.Expression)
+	})
+
+	t.Run("refuses invalid expressions", func(t *testing.T) {
+		var r = mocks.NewSmartListRepositoryMock()
+		res, err = NewSmartListService(r, nil).Save(ownerID, &transfer.SmartListCreation{Name: "Urgent", Expression: "priority>=highest"})
+		assert.ErrorContains(t, err, `at position 11: unknown priority "highest"`)
+		assert.Equal(t, uuid.Nil, res)
+		r.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("name too long", func(t *testing.T) {
+		res, err = NewSmartListService(nil, nil).Save(ownerID, &transfer.SmartListCreation{Name: strings.Repeat("a", 33), Expression: "pinned"})
+		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("name", "smart list", 1<<5).Error())
+		assert.Equal(t, uuid.Nil, res)
+	})
+
+	t.Run("nil creation", func(t *testing.T) {
+		res, err = NewSmartListService(nil, nil).Save(ownerID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "creation").Error())
+		assert.Equal(t, uuid.Nil, res)
+	})
+}
+
+func TestSmartListService_FetchTasks(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, smartListID = uuid.New(), uuid.New()
+		smartList            = &model.SmartList{UUID: smartListID, OwnerUUID: ownerID, Name: "Urgent", Expression: "priority>=high and not completed"}
+		candidate            = func(priority types.TaskPriority, status types.TaskStatus) *model.FilterableTask {
+			return &model.FilterableTask{Task: &model.Task{UUID: uuid.New(), Priority: priority, Status: status}}
+		}
+		first      = candidate(types.TaskPriorityUrgent, types.TaskStatusIncomplete)
+		low        = candidate(types.TaskPriorityLow, types.TaskStatusIncomplete)
+		second     = candidate(types.TaskPriorityHigh, types.TaskStatusIncomplete)
+		done       = candidate(types.TaskPriorityHigh, types.TaskStatusComplete)
+		third      = candidate(types.TaskPriorityHigh, types.TaskStatusDeferred)
+		utc        = &transfer.UserSetting{Key: "timezone", Value: "UTC"}
+		unnarrowed = &types.CandidateFilter{}
+	)
+	var newService = func(r *mocks.SmartListRepository, users *mocks.UserService) *smartListService {
+		var s = NewSmartListService(r, users).(*smartListService)
+		s.batch = 2
+		return s
+	}
+
+	t.Run("paginates the matching tasks", func(t *testing.T) {
+		var (
+			r     = mocks.NewSmartListRepositoryMock()
+			users = mocks.NewUserServiceMock()
+		)
+		r.On("FetchByID", ownerID.String(), smartListID.String()).Return(smartList, nil)
+		users.On("FetchOneSetting", ownerID, "timezone").Return(utc, nil)
+		r.On("FetchCandidates", ownerID.String(), unnarrowed, int64(1), int64(2)).Return([]*model.FilterableTask{first, low}, nil)
+		r.On("FetchCandidates", ownerID.String(), unnarrowed, int64(2), int64(2)).Return([]*model.FilterableTask{second, done}, nil)
+		r.On("FetchCandidates", ownerID.String(), unnarrowed, int64(3), int64(2)).Return([]*model.FilterableTask{third}, nil)
+		res, err := newService(r, users).FetchTasks(ownerID, smartListID, &types.Pagination{Page: 2, RPP: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.Task]{Page: 2, RPP: 2, Retrieved: 1, Payload: []*model.Task{third.Task}}, res)
+	})
+
+	t.Run("stops fetching once the page is full", func(t *testing.T) {
+		var (
+			r     = mocks.NewSmartListRepositoryMock()
+			users = mocks.NewUserServiceMock()
+		)
+		r.On("FetchByID", ownerID.String(), smartListID.String()).Return(smartList, nil)
+		users.On("FetchOneSetting", ownerID, "timezone").Return(nil, failure.ErrSettingNotFound)
+		r.On("FetchCandidates", ownerID.String(), unnarrowed, int64(1), int64(2)).Return([]*model.FilterableTask{first, low}, nil)
+		r.On("FetchCandidates", ownerID.String(), unnarrowed, int64(2), int64(2)).Return([]*model.FilterableTask{second, done}, nil)
+		res, err := newService(r, users).FetchTasks(ownerID, smartListID, &types.Pagination{Page: 1, RPP: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Task{first.Task, second.Task}, res.Payload)
+		r.AssertNumberOfCalls(t, "FetchCandidates", 2)
+	})
+
+	t.Run("takes the days in the time zone of the user", func(t *testing.T) {
+		var (
+			r         = mocks.NewSmartListRepositoryMock()
+			users     = mocks.NewUserServiceMock()
+			dueAt     = time.Date(2024, time.March, 15, 5, 30, 0, 0, time.UTC)
+			late      = &model.FilterableTask{Task: &model.Task{UUID: uuid.New(), DueDate: &dueAt}}
+			dayBefore = &model.SmartList{UUID: smartListID, OwnerUUID: ownerID, Name: "Ides", Expression: "due=2024-03-14"}
+			setting   = &transfer.UserSetting{Key: "timezone", Value: "America/Managua"}
+		)
+		r.On("FetchByID", ownerID.String(), smartListID.String()).Return(dayBefore, nil)
+		users.On("FetchOneSetting", ownerID, "timezone").Return(setting, nil)
+		var ides = mock.MatchedBy(func(candidates *types.CandidateFilter) bool {
+			return "2024-03-14T00:00:00-06:00" == candidates.DueFrom.Format(time.RFC3339) &&
+				"2024-03-15T00:00:00-06:00" == candidates.DueTo.Format(time.RFC3339)
+		})
+		r.On("FetchCandidates", ownerID.String(), ides, int64(1), int64(2)).Return([]*model.FilterableTask{late}, nil)
+		var s = newService(r, users)
+		s.now = func() time.Time { return time.Date(2024, time.March, 15, 2, 0, 0, 0, time.UTC) }
+		res, err := s.FetchTasks(ownerID, smartListID, &types.Pagination{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Task{late.Task}, res.Payload)
+	})
+
+	t.Run("narrows the candidates down", func(t *testing.T) {
+		var (
+			r       = mocks.NewSmartListRepositoryMock()
+			users   = mocks.NewUserServiceMock()
+			pinned  = &model.SmartList{UUID: smartListID, OwnerUUID: ownerID, Name: "Pinned", Expression: "pinned and status=open"}
+			open    = types.TaskStatusIncomplete
+			yes     = true
+			wanted  = &types.CandidateFilter{Status: &open, Pinned: &yes}
+			matched = &model.FilterableTask{Task: &model.Task{UUID: uuid.New(), Status: open, IsPinned: true}}
+		)
+		r.On("FetchByID", ownerID.String(), smartListID.String()).Return(pinned, nil)
+		users.On("FetchOneSetting", ownerID, "timezone").Return(utc, nil)
+		r.On("FetchCandidates", ownerID.String(), wanted, int64(1), int64(2)).Return([]*model.FilterableTask{matched}, nil)
+		res, err := newService(r, users).FetchTasks(ownerID, smartListID, &types.Pagination{})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Task{matched.Task}, res.Payload)
+	})
+
+	t.Run("tells when the candidates were not all looked through", func(t *testing.T) {
+		var (
+			r     = mocks.NewSmartListRepositoryMock()
+			users = mocks.NewUserServiceMock()
+			batch = make([]*model.FilterableTask, maxCandidates/2)
+		)
+		for i := range batch {
+			batch[i] = low
+		}
+		r.On("FetchByID", ownerID.String(), smartListID.String()).Return(smartList, nil)
+		users.On("FetchOneSetting", ownerID, "timezone").Return(utc, nil)
+		r.On("FetchCandidates", ownerID.String(), unnarrowed, int64(1), int64(maxCandidates/2)).Return(batch, nil)
+		r.On("FetchCandidates", ownerID.String(), unnarrowed, int64(2), int64(maxCandidates/2)).Return(batch, nil)
+		var s = newService(r, users)
+		s.batch = maxCandidates / 2
+		res, err := s.FetchTasks(ownerID, smartListID, &types.Pagination{})
+		assert.NoError(t, err)
+		assert.Empty(t, res.Payload)
+		assert.True(t, res.Truncated)
+		r.AssertNumberOfCalls(t, "FetchCandidates", 2)
+	})
+
+	t.Run("smart list not found", func(t *testing.T) {
+		var r = mocks.NewSmartListRepositoryMock()
+		r.On("FetchByID", ownerID.String(), smartListID.String()).Return(nil, failure.ErrSmartListNotFound)
+		res, err := NewSmartListService(r, nil).FetchTasks(ownerID, smartListID, &types.Pagination{})
+		assert.ErrorIs(t, err, failure.ErrSmartListNotFound)
+		assert.Nil(t, res)
+		r.AssertNotCalled(t, "FetchCandidates")
+	})
+}
+
+func TestSmartListService_Update(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, smartListID = uuid.New(), uuid.New()
+		ok                   bool
+		err                  error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r      = mocks.NewSmartListRepositoryMock()
+			update = &transfer.SmartListUpdate{Expression: "overdue or due<1d"}
+		)
+		r.On("Update", ownerID.String(), smartListID.String(), update).Return(true, nil)
+		ok, err = NewSmartListService(r, nil).Update(ownerID, smartListID, update)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		var r = mocks.NewSmartListRepositoryMock()
+		ok, err = NewSmartListService(r, nil).Update(ownerID, smartListID, &transfer.SmartListUpdate{Name: "  "})
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		r.AssertNotCalled(t, "Update")
+	})
+
+	t.Run("refuses invalid expressions", func(t *testing.T) {
+		var r = mocks.NewSmartListRepositoryMock()
+		ok, err = NewSmartListService(r, nil).Update(ownerID, smartListID, &transfer.SmartListUpdate{Expression: "due<soon"})
+		assert.ErrorContains(t, err, `found "soon"`)
+		assert.False(t, ok)
+		r.AssertNotCalled(t, "Update")
+	})
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/repository"
+)
+
+type StepService interface {
+	Save(ownerID, taskID uuid.UUID, creation *transfer.StepCreation) (insertedID uuid.UUID, err error)
+	Fetch(ownerID, taskID uuid.UUID) (steps []*model.Step, err error)
+	Update(ownerID, taskID, stepID uuid.UUID, update *transfer.StepUpdate) (ok bool, err error)
+	Accomplish(ownerID, taskID, stepID uuid.UUID) (ok bool, err error)
+	Unaccomplish(ownerID, taskID, stepID uuid.UUID) (ok bool, err error)
+	Reorder(ownerID, taskID, stepID uuid.UUID, position uint64) (ok bool, err error)
+	Delete(ownerID, taskID, stepID uuid.UUID) error
+}
+
+type stepService struct {
+	r repository.StepRepository
+}
+
+func NewStepService(repository repository.StepRepository) StepService {
+	return &stepService{r: repository}
+}
+
+func (s *stepService) Save(ownerID, taskID uuid.UUID, creation *transfer.StepCreation) (insertedID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Save", "ownerID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Save", "taskID")
+		log.Println(err)
+		return uuid.Nil, err
+	case nil == creation:
+		err = failure.NewNilParameterError("Save", "creation")
+		log.Println(err)
+		return uuid.Nil, err
+	}
+	doTrim(&creation.Description)
+	switch {
+	case "" == creation.Description:
+		return uuid.Nil, errors.New("description cannot be an empty string") // must've been handled by validator
+	case 512 < len(creation.Description):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("description", "step", 512)
+	}
+	inserted, err := s.r.Save(ownerID.String(), taskID.String(), creation)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(inserted)
+}
+
+func (s *stepService) Fetch(ownerID, taskID uuid.UUID) (steps []*model.Step, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Fetch", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Fetch", "taskID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.r.Fetch(ownerID.String(), taskID.String())
+}
+
+func (s *stepService) Update(ownerID, taskID, stepID uuid.UUID, update *transfer.StepUpdate) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Update", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Update", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == stepID:
+		err = failure.NewNilParameterError("Update", "stepID")
+		log.Println(err)
+		return false, err
+	case nil == update:
+		err = failure.NewNilParameterError("Update", "update")
+		log.Println(err)
+		return false, err
+	}
+	doTrim(&update.Description)
+	switch {
+	case "" == update.Description:
+		return false, nil
+	case 512 < len(update.Description):
+		return false, failure.ErrTooLong.Clone().FormatDetails("description", "step", 512)
+	}
+	return s.r.Update(ownerID.String(), taskID.String(), stepID.String(), update)
+}
+
+func (s *stepService) Accomplish(ownerID, taskID, stepID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Accomplish", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Accomplish", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == stepID:
+		err = failure.NewNilParameterError("Accomplish", "stepID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Accomplish(ownerID.String(), taskID.String(), stepID.String())
+}
+
+func (s *stepService) Unaccomplish(ownerID, taskID, stepID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Unaccomplish", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Unaccomplish", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == stepID:
+		err = failure.NewNilParameterError("Unaccomplish", "stepID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Unaccomplish(ownerID.String(), taskID.String(), stepID.String())
+}
+
+func (s *stepService) Reorder(ownerID, taskID, stepID uuid.UUID, position uint64) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Reorder", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Reorder", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == stepID:
+		err = failure.NewNilParameterError("Reorder", "stepID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Reorder(ownerID.String(), taskID.String(), stepID.String(), position)
+}
+
+func (s *stepService) Delete(ownerID, taskID, stepID uuid.UUID) error {
+	var err error
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Delete", "ownerID")
+		log.Println(err)
+		return err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Delete", "taskID")
+		log.Println(err)
+		return err
+	case uuid.Nil == stepID:
+		err = failure.NewNilParameterError("Delete", "stepID")
+		log.Println(err)
+		return err
+	}
+	return s.r.Delete(ownerID.String(), taskID.String(), stepID.String())
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/failure"
+	"noda/mocks"
+	"strings"
+	"testing"
+)
+
+func TestStepService_Save(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Save"
+	var (
+		ownerID, taskID, inserted = uuid.New(), uuid.New(), uuid.New()
+		res                       uuid.UUID
+		err                       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var c = &transfer.StepCreation{Description: "description"}
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, ownerID.String(), taskID.String(), c).Return(inserted.String(), nil)
+		res, err = NewStepService(r).Save(ownerID, taskID, c)
+		assert.Equal(t, inserted, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("parameters are not nil or uuid.Nil", func(t *testing.T) {
+		var c = &transfer.StepCreation{Description: "description"}
+
+		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
+			var r = mocks.NewStepRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewStepService(r).Save(uuid.Nil, taskID, c)
+			assert.Equal(t, uuid.Nil, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "ownerID").Error())
+		})
+
+		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
+			var r = mocks.NewStepRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewStepService(r).Save(ownerID, uuid.Nil, c)
+			assert.Equal(t, uuid.Nil, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "taskID").Error())
+		})
+
+		t.Run("\"creation\" != nil", func(t *testing.T) {
+			var r = mocks.NewStepRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewStepService(r).Save(ownerID, taskID, nil)
+			assert.Equal(t, uuid.Nil, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "creation").Error())
+		})
+	})
+
+	t.Run("must trim all string fields in \"creation\"", func(t *testing.T) {
+		var c = &transfer.StepCreation{Description: blankset + "Description" + blankset}
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(inserted.String(), nil)
+		res, err = NewStepService(r).Save(ownerID, taskID, c)
+		assert.Equal(t, inserted, res)
+		assert.Equal(t, "Description", c.Description)
+		assert.NoError(t, err)
+	})
+
+	t.Run("512 < len(creation.Description)", func(t *testing.T) {
+		var c = &transfer.StepCreation{Description: strings.Repeat("x", 513)}
+		var r = mocks.NewStepRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewStepService(r).Save(ownerID, taskID, c)
+		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("description", "step", 512).Error())
+		assert.Equal(t, uuid.Nil, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var c = &transfer.StepCreation{Description: "description"}
+		var unexpected = errors.New("unexpected error")
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return("", unexpected)
+		res, err = NewStepService(r).Save(ownerID, taskID, c)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Equal(t, uuid.Nil, res)
+	})
+}
+
+func TestStepService_Fetch(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Fetch"
+	var (
+		ownerID, taskID = uuid.New(), uuid.New()
+		res             []*model.Step
+		err             error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var steps = make([]*model.Step, 3)
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, ownerID.String(), taskID.String()).Return(steps, nil)
+		res, err = NewStepService(r).Fetch(ownerID, taskID)
+		assert.Equal(t, steps, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("parameters are not nil or uuid.Nil", func(t *testing.T) {
+		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
+			var r = mocks.NewStepRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewStepService(r).Fetch(uuid.Nil, taskID)
+			assert.Nil(t, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "ownerID").Error())
+		})
+
+		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
+			var r = mocks.NewStepRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewStepService(r).Fetch(ownerID, uuid.Nil)
+			assert.Nil(t, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "taskID").Error())
+		})
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything).Return(nil, unexpected)
+		res, err = NewStepService(r).Fetch(ownerID, taskID)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Nil(t, res)
+	})
+}
+
+func TestStepService_Update(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Update"
+	var (
+		ownerID, taskID, stepID = uuid.New(), uuid.New(), uuid.New()
+		res                     bool
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var up = &transfer.StepUpdate{Description: blankset + "description" + blankset}
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, ownerID.String(), taskID.String(), stepID.String(), up).Return(true, nil)
+		res, err = NewStepService(r).Update(ownerID, taskID, stepID, up)
+		assert.True(t, res)
+		assert.Equal(t, "description", up.Description)
+		assert.NoError(t, err)
+	})
+
+	t.Run("parameters are not nil or uuid.Nil", func(t *testing.T) {
+		var up = &transfer.StepUpdate{Description: "description"}
+
+		t.Run("\"stepID\" != uuid.Nil", func(t *testing.T) {
+			var r = mocks.NewStepRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewStepService(r).Update(ownerID, taskID, uuid.Nil, up)
+			assert.False(t, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Update", "stepID").Error())
+		})
+
+		t.Run("\"update\" != nil", func(t *testing.T) {
+			var r = mocks.NewStepRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewStepService(r).Update(ownerID, taskID, stepID, nil)
+			assert.False(t, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Update", "update").Error())
+		})
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewStepService(r).Update(ownerID, taskID, stepID, &transfer.StepUpdate{Description: blankset})
+		assert.False(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("512 < len(update.Description)", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewStepService(r).Update(ownerID, taskID, stepID, &transfer.StepUpdate{Description: strings.Repeat("x", 513)})
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("description", "step", 512).Error())
+	})
+}
+
+func TestStepService_Accomplish(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Accomplish"
+	var (
+		ownerID, taskID, stepID = uuid.New(), uuid.New(), uuid.New()
+		res                     bool
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, ownerID.String(), taskID.String(), stepID.String()).Return(true, nil)
+		res, err = NewStepService(r).Accomplish(ownerID, taskID, stepID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("\"stepID\" != uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewStepService(r).Accomplish(ownerID, taskID, uuid.Nil)
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Accomplish", "stepID").Error())
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, failure.ErrStepNotFound)
+		res, err = NewStepService(r).Accomplish(ownerID, taskID, stepID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrStepNotFound)
+	})
+}
+
+func TestStepService_Unaccomplish(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Unaccomplish"
+	var (
+		ownerID, taskID, stepID = uuid.New(), uuid.New(), uuid.New()
+		res                     bool
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, ownerID.String(), taskID.String(), stepID.String()).Return(true, nil)
+		res, err = NewStepService(r).Unaccomplish(ownerID, taskID, stepID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewStepService(r).Unaccomplish(ownerID, uuid.Nil, stepID)
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Unaccomplish", "taskID").Error())
+	})
+}
+
+func TestStepService_Reorder(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Reorder"
+	var (
+		ownerID, taskID, stepID = uuid.New(), uuid.New(), uuid.New()
+		res                     bool
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, ownerID.String(), taskID.String(), stepID.String(), uint64(2)).Return(true, nil)
+		res, err = NewStepService(r).Reorder(ownerID, taskID, stepID, 2)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewStepService(r).Reorder(uuid.Nil, taskID, stepID, 2)
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Reorder", "ownerID").Error())
+	})
+}
+
+func TestStepService_Delete(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Delete"
+	var (
+		ownerID, taskID, stepID = uuid.New(), uuid.New(), uuid.New()
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, ownerID.String(), taskID.String(), stepID.String()).Return(nil)
+		err = NewStepService(r).Delete(ownerID, taskID, stepID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("\"stepID\" != uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		err = NewStepService(r).Delete(ownerID, taskID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Delete", "stepID").Error())
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewStepRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(failure.ErrStepNotFound)
+		err = NewStepService(r).Delete(ownerID, taskID, stepID)
+		assert.ErrorIs(t, err, failure.ErrStepNotFound)
+	})
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+
+	"github.com/google/uuid"
+)
+
+// SubtaskService nests tasks in other tasks of the same list, up to a limited
+// depth. Trashing, restoring or deleting a task does the same to its subtasks.
+type SubtaskService interface {
+	Save(userID, listID, parentID uuid.UUID, creation *transfer.TaskCreation) (insertedID uuid.UUID, err error)
+	FetchTree(userID, listID, taskID uuid.UUID) (tree *model.TaskTree, err error)
+	PromoteStep(userID, listID, taskID, stepID uuid.UUID) (insertedID uuid.UUID, err error)
+}
+
+type subtaskService struct {
+	r        repository.SubtaskRepository
+	members  repository.MemberRepository
+	maxDepth int
+}
+
+// NewSubtaskService makes a SubtaskService that refuses to nest subtasks more
+// than maxDepth levels below a task that is not a subtask.
+func NewSubtaskService(r repository.SubtaskRepository, members repository.MemberRepository, maxDepth int) SubtaskService {
+	return &subtaskService{r: r, members: members, maxDepth: maxDepth}
+}
+
+func (s *subtaskService) Save(userID, listID, parentID uuid.UUID, creation *transfer.TaskCreation) (insertedID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Save", "userID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Save", "listID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == parentID:
+		err = failure.NewNilParameterError("Save", "parentID")
+		log.Println(err)
+		return uuid.Nil, err
+	case nil == creation:
+		err = failure.NewNilParameterError("Save", "creation")
+		log.Println(err)
+		return uuid.Nil, err
+	case 128 < len(creation.Title):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("Title", "creation", 128)
+	case 64 < len(creation.Headline):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("Headline", "creation", 64)
+	case 512 < len(creation.Description):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("Description", "creation", 512)
+	}
+	doTrim(&creation.Title, &creation.Headline, &creation.Description)
+	if "" == creation.Title {
+		creation.Title = "Untitled"
+	}
+	if "" == creation.Priority {
+		creation.Priority = types.TaskPriorityMedium
+	}
+	if "" == creation.Status {
+		creation.Status = types.TaskStatusIncomplete
+	}
+	ownerID, err := s.nestUnder(userID, listID, parentID)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	inserted, err := s.r.Save(ownerID.String(), listID.String(), parentID.String(), creation)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(inserted)
+}
+
+// FetchTree retrieves the task with all the subtasks below it. The progress of
+// a task is 1 when it is finished, and otherwise the mean of the progress of
+// its subtasks, if it has any.
+func (s *subtaskService) FetchTree(userID, listID, taskID uuid.UUID) (tree *model.TaskTree, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("FetchTree", "userID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("FetchTree", "listID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("FetchTree", "taskID")
+		log.Println(err)
+		return nil, err
+	}
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	tasks, err := s.r.FetchTree(access.OwnerUUID.String(), listID.String(), taskID.String())
+	if nil != err {
+		return nil, err
+	}
+	tree = buildTaskTree(tasks, taskID)
+	if nil == tree {
+		return nil, failure.ErrTaskNotFound
+	}
+	rollUpProgress(tree)
+	return tree, nil
+}
+
+// PromoteStep turns a step of the task into a subtask of it.
+func (s *subtaskService) PromoteStep(userID, listID, taskID, stepID uuid.UUID) (insertedID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("PromoteStep", "userID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("PromoteStep", "listID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("PromoteStep", "taskID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == stepID:
+		err = failure.NewNilParameterError("PromoteStep", "stepID")
+		log.Println(err)
+		return uuid.Nil, err
+	}
+	ownerID, err := s.nestUnder(userID, listID, taskID)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	inserted, err := s.r.PromoteStep(ownerID.String(), listID.String(), taskID.String(), stepID.String())
+	if nil != err {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(inserted)
+}
+
+// nestUnder authorizes the user to add subtasks to the list and makes sure
+// that one more level fits below the parent. It returns the owner of the list.
+func (s *subtaskService) nestUnder(userID, listID, parentID uuid.UUID) (ownerID uuid.UUID, err error) {
+	access, err := authorizeList(s.members, userID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	depth, err := s.r.FetchDepth(access.OwnerUUID.String(), listID.String(), parentID.String())
+	if nil != err {
+		return uuid.Nil, err
+	}
+	if s.maxDepth <= depth {
+		return uuid.Nil, failure.ErrSubtaskTooDeep.Clone().FormatDetails(s.maxDepth)
+	}
+	return access.OwnerUUID, nil
+}
+
+// buildTaskTree nests the tasks under their parents, starting from the root.
+// It returns nil if the root is not among the tasks.
+func buildTaskTree(tasks []*model.Task, rootID uuid.UUID) *model.TaskTree {
+	var nodes = make(map[uuid.UUID]*model.TaskTree, len(tasks))
+	for _, task := range tasks {
+		nodes[task.UUID] = &model.TaskTree{Task: task, Subtasks: make([]*model.TaskTree, 0)}
+	}
+	for _, task := range tasks {
+		if task.UUID == rootID || nil == task.ParentUUID {
+			continue
+		}
+		if parent, found := nodes[*task.ParentUUID]; found {
+			parent.Subtasks = append(parent.Subtasks, nodes[task.UUID])
+		}
+	}
+	return nodes[rootID]
+}
+
+// rollUpProgress computes the progress of the tree and of all the subtrees
+// below it, and returns the progress of the tree.
+func rollUpProgress(tree *model.TaskTree) float64 {
+	var sum float64
+	for _, subtree := range tree.Subtasks {
+		sum += rollUpProgress(subtree)
+	}
+	switch {
+	case types.TaskStatusComplete == tree.Task.Status:
+		tree.Progress = 1
+	case 0 < len(tree.Subtasks):
+		tree.Progress = sum / float64(len(tree.Subtasks))
+	default:
+		tree.Progress = 0
+	}
+	return tree.Progress
+}
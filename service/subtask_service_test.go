@@ -0,0 +1,162 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"testing"
+)
+
+func TestSubtaskService_Save(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, listID, parentID = uuid.New(), uuid.New(), uuid.New()
+		inserted                  = uuid.New()
+		res                       uuid.UUID
+		err                       error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var (
+			r        = mocks.NewSubtaskRepositoryMock()
+			creation = &transfer.TaskCreation{Title: "  Buy paint "}
+		)
+		r.On("FetchDepth", ownerID.String(), listID.String(), parentID.String()).Return(1, nil)
+		r.On("Save", ownerID.String(), listID.String(), parentID.String(), creation).Return(inserted.String(), nil)
+		res, err = NewSubtaskService(r, soleOwner{}, 2).Save(ownerID, listID, parentID, creation)
+		assert.NoError(t, err)
+		assert.Equal(t, inserted, res)
+		assert.Equal(t, "Buy paint", creation.Title)
+		assert.Equal(t, types.TaskPriorityMedium, creation.Priority)
+		assert.Equal(t, types.TaskStatusIncomplete, creation.Status)
+	})
+
+	t.Run("refuses to nest too deep", func(t *testing.T) {
+		var r = mocks.NewSubtaskRepositoryMock()
+		r.On("FetchDepth", ownerID.String(), listID.String(), parentID.String()).Return(2, nil)
+		res, err = NewSubtaskService(r, soleOwner{}, 2).Save(ownerID, listID, parentID, &transfer.TaskCreation{Title: "Buy paint"})
+		assert.ErrorContains(t, err, failure.ErrSubtaskTooDeep.Clone().FormatDetails(2).Error())
+		assert.Equal(t, uuid.Nil, res)
+		r.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("viewers cannot add subtasks", func(t *testing.T) {
+		var (
+			r       = mocks.NewSubtaskRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", mock.Anything, mock.Anything).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		res, err = NewSubtaskService(r, members, 2).Save(uuid.New(), listID, parentID, &transfer.TaskCreation{Title: "Buy paint"})
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		assert.Equal(t, uuid.Nil, res)
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		var r = mocks.NewSubtaskRepositoryMock()
+		r.On("FetchDepth", ownerID.String(), listID.String(), parentID.String()).Return(0, failure.ErrTaskNotFound)
+		res, err = NewSubtaskService(r, soleOwner{}, 2).Save(ownerID, listID, parentID, &transfer.TaskCreation{Title: "Buy paint"})
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Equal(t, uuid.Nil, res)
+	})
+
+	t.Run("nil parent", func(t *testing.T) {
+		res, err = NewSubtaskService(nil, nil, 2).Save(ownerID, listID, uuid.Nil, &transfer.TaskCreation{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "parentID").Error())
+		assert.Equal(t, uuid.Nil, res)
+	})
+}
+
+func TestSubtaskService_FetchTree(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, listID = uuid.New(), uuid.New()
+		task            = func(parent *model.Task, status types.TaskStatus) *model.Task {
+			var t = &model.Task{UUID: uuid.New(), ListUUID: listID, Status: status}
+			if nil != parent {
+				t.ParentUUID = &parent.UUID
+			}
+			return t
+		}
+		root     = task(nil, types.TaskStatusIncomplete)
+		paint    = task(root, types.TaskStatusIncomplete)
+		primer   = task(paint, types.TaskStatusComplete)
+		coat     = task(paint, types.TaskStatusIncomplete)
+		clean    = task(root, types.TaskStatusComplete)
+		leftover = task(clean, types.TaskStatusIncomplete)
+	)
+
+	t.Run("rolls up the progress", func(t *testing.T) {
+		var r = mocks.NewSubtaskRepositoryMock()
+		r.On("FetchTree", ownerID.String(), listID.String(), root.UUID.String()).
+			Return([]*model.Task{root, paint, primer, coat, clean, leftover}, nil)
+		tree, err := NewSubtaskService(r, soleOwner{}, 5).FetchTree(ownerID, listID, root.UUID)
+		assert.NoError(t, err)
+		assert.Equal(t, root, tree.Task)
+		assert.Len(t, tree.Subtasks, 2)
+		assert.Equal(t, paint, tree.Subtasks[0].Task)
+		assert.Equal(t, 0.5, tree.Subtasks[0].Progress)
+		assert.Equal(t, float64(1), tree.Subtasks[1].Progress)
+		assert.Equal(t, float64(0), tree.Subtasks[1].Subtasks[0].Progress)
+		assert.Equal(t, 0.75, tree.Progress)
+	})
+
+	t.Run("a task without subtasks", func(t *testing.T) {
+		var r = mocks.NewSubtaskRepositoryMock()
+		r.On("FetchTree", ownerID.String(), listID.String(), coat.UUID.String()).Return([]*model.Task{coat}, nil)
+		tree, err := NewSubtaskService(r, soleOwner{}, 5).FetchTree(ownerID, listID, coat.UUID)
+		assert.NoError(t, err)
+		assert.Empty(t, tree.Subtasks)
+		assert.Equal(t, float64(0), tree.Progress)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		var r = mocks.NewSubtaskRepositoryMock()
+		r.On("FetchTree", ownerID.String(), listID.String(), root.UUID.String()).Return([]*model.Task{}, nil)
+		tree, err := NewSubtaskService(r, soleOwner{}, 5).FetchTree(ownerID, listID, root.UUID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, tree)
+	})
+}
+
+func TestSubtaskService_PromoteStep(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID, listID, taskID, stepID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		inserted                        = uuid.New()
+		res                             uuid.UUID
+		err                             error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewSubtaskRepositoryMock()
+		r.On("FetchDepth", ownerID.String(), listID.String(), taskID.String()).Return(0, nil)
+		r.On("PromoteStep", ownerID.String(), listID.String(), taskID.String(), stepID.String()).Return(inserted.String(), nil)
+		res, err = NewSubtaskService(r, soleOwner{}, 1).PromoteStep(ownerID, listID, taskID, stepID)
+		assert.NoError(t, err)
+		assert.Equal(t, inserted, res)
+	})
+
+	t.Run("refuses to nest too deep", func(t *testing.T) {
+		var r = mocks.NewSubtaskRepositoryMock()
+		r.On("FetchDepth", ownerID.String(), listID.String(), taskID.String()).Return(1, nil)
+		res, err = NewSubtaskService(r, soleOwner{}, 1).PromoteStep(ownerID, listID, taskID, stepID)
+		assert.ErrorContains(t, err, failure.ErrSubtaskTooDeep.Clone().FormatDetails(1).Error())
+		assert.Equal(t, uuid.Nil, res)
+		r.AssertNotCalled(t, "PromoteStep")
+	})
+
+	t.Run("step not found", func(t *testing.T) {
+		var r = mocks.NewSubtaskRepositoryMock()
+		r.On("FetchDepth", ownerID.String(), listID.String(), taskID.String()).Return(0, nil)
+		r.On("PromoteStep", ownerID.String(), listID.String(), taskID.String(), stepID.String()).Return("", failure.ErrStepNotFound)
+		res, err = NewSubtaskService(r, soleOwner{}, 1).PromoteStep(ownerID, listID, taskID, stepID)
+		assert.ErrorIs(t, err, failure.ErrStepNotFound)
+		assert.Equal(t, uuid.Nil, res)
+	})
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"log"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/repository"
+)
+
+type TagService interface {
+	Save(ownerID uuid.UUID, creation *transfer.TagCreation) (insertedID uuid.UUID, err error)
+	FetchByID(ownerID, tagID uuid.UUID) (tag *model.Tag, err error)
+	Fetch(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Tag], err error)
+	FetchFromTask(ownerID, taskID uuid.UUID) (tags []*model.Tag, err error)
+	Update(ownerID, tagID uuid.UUID, update *transfer.TagUpdate) (ok bool, err error)
+	Attach(ownerID, taskID, tagID uuid.UUID) (ok bool, err error)
+	Detach(ownerID, taskID, tagID uuid.UUID) (ok bool, err error)
+	Delete(ownerID, tagID uuid.UUID) error
+}
+
+type tagService struct {
+	r repository.TagRepository
+}
+
+func NewTagService(repository repository.TagRepository) TagService {
+	return &tagService{r: repository}
+}
+
+func (s *tagService) Save(ownerID uuid.UUID, creation *transfer.TagCreation) (insertedID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Save", "ownerID")
+		log.Println(err)
+		return uuid.Nil, err
+	case nil == creation:
+		err = failure.NewNilParameterError("Save", "creation")
+		log.Println(err)
+		return uuid.Nil, err
+	}
+	doTrim(&creation.Name, &creation.Description, &creation.Color)
+	switch {
+	case "" == creation.Name:
+		return uuid.Nil, errors.New("name cannot be an empty string") // must've been handled by validator
+	case 1<<5 < len(creation.Name):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("name", "tag", 1<<5)
+	case 1<<9 < len(creation.Description):
+		return uuid.Nil, failure.ErrTooLong.Clone().FormatDetails("description", "tag", 1<<9)
+	}
+	inserted, err := s.r.Save(ownerID.String(), creation)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(inserted)
+}
+
+func (s *tagService) FetchByID(ownerID, tagID uuid.UUID) (tag *model.Tag, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("FetchByID", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == tagID:
+		err = failure.NewNilParameterError("FetchByID", "tagID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.r.FetchByID(ownerID.String(), tagID.String())
+}
+
+func (s *tagService) Fetch(
+	ownerID uuid.UUID,
+	pagination *types.Pagination,
+	needle, sortExpr string,
+) (result *types.Result[model.Tag], err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Fetch", "ownerID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError("Fetch", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doTrim(&needle, &sortExpr)
+	doDefaultPagination(pagination)
+	res, err := s.r.Fetch(ownerID.String(), pagination.Page, pagination.RPP, needle, sortExpr)
+	if nil != err {
+		return nil, err
+	}
+	result = &types.Result[model.Tag]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(res)),
+		Payload:   res,
+	}
+	return result, nil
+}
+
+func (s *tagService) FetchFromTask(ownerID, taskID uuid.UUID) (tags []*model.Tag, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("FetchFromTask", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("FetchFromTask", "taskID")
+		log.Println(err)
+		return nil, err
+	}
+	return s.r.FetchFromTask(ownerID.String(), taskID.String())
+}
+
+func (s *tagService) Update(ownerID, tagID uuid.UUID, update *transfer.TagUpdate) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Update", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == tagID:
+		err = failure.NewNilParameterError("Update", "tagID")
+		log.Println(err)
+		return false, err
+	case nil == update:
+		err = failure.NewNilParameterError("Update", "update")
+		log.Println(err)
+		return false, err
+	}
+	doTrim(&update.Name, &update.Description, &update.Color)
+	switch {
+	case "" == update.Name && "" == update.Description && "" == update.Color:
+		return false, nil
+	case 1<<5 < len(update.Name):
+		return false, failure.ErrTooLong.Clone().FormatDetails("name", "tag", 1<<5)
+	case 1<<9 < len(update.Description):
+		return false, failure.ErrTooLong.Clone().FormatDetails("description", "tag", 1<<9)
+	}
+	return s.r.Update(ownerID.String(), tagID.String(), update)
+}
+
+func (s *tagService) Attach(ownerID, taskID, tagID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Attach", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Attach", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == tagID:
+		err = failure.NewNilParameterError("Attach", "tagID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Attach(ownerID.String(), taskID.String(), tagID.String())
+}
+
+func (s *tagService) Detach(ownerID, taskID, tagID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Detach", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Detach", "taskID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == tagID:
+		err = failure.NewNilParameterError("Detach", "tagID")
+		log.Println(err)
+		return false, err
+	}
+	return s.r.Detach(ownerID.String(), taskID.String(), tagID.String())
+}
+
+func (s *tagService) Delete(ownerID, tagID uuid.UUID) error {
+	var err error
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Delete", "ownerID")
+		log.Println(err)
+		return err
+	case uuid.Nil == tagID:
+		err = failure.NewNilParameterError("Delete", "tagID")
+		log.Println(err)
+		return err
+	}
+	return s.r.Delete(ownerID.String(), tagID.String())
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"noda/data/model"
+	"noda/data/transfer"
+	"noda/data/types"
+	"noda/failure"
+	"noda/mocks"
+	"strings"
+	"testing"
+)
+
+func TestTagService_Save(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Save"
+	var (
+		ownerID, inserted = uuid.New(), uuid.New()
+		res               uuid.UUID
+		err               error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var c = &transfer.TagCreation{Name: "work", Description: "work stuff", Color: "#ff0000"}
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, ownerID.String(), c).Return(inserted.String(), nil)
+		res, err = NewTagService(r).Save(ownerID, c)
+		assert.Equal(t, inserted, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("parameters are not nil or uuid.Nil", func(t *testing.T) {
+		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
+			var r = mocks.NewTagRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewTagService(r).Save(uuid.Nil, &transfer.TagCreation{Name: "work"})
+			assert.Equal(t, uuid.Nil, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "ownerID").Error())
+		})
+
+		t.Run("\"creation\" != nil", func(t *testing.T) {
+			var r = mocks.NewTagRepositoryMock()
+			r.AssertNotCalled(t, routine)
+			res, err = NewTagService(r).Save(ownerID, nil)
+			assert.Equal(t, uuid.Nil, res)
+			assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "creation").Error())
+		})
+	})
+
+	t.Run("must trim all string fields in \"creation\"", func(t *testing.T) {
+		var c = &transfer.TagCreation{
+			Name:        blankset + "work" + blankset,
+			Description: blankset + "work stuff" + blankset,
+			Color:       blankset + "#ff0000" + blankset,
+		}
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything).Return(inserted.String(), nil)
+		res, err = NewTagService(r).Save(ownerID, c)
+		assert.Equal(t, inserted, res)
+		assert.Equal(t, "work", c.Name)
+		assert.Equal(t, "work stuff", c.Description)
+		assert.Equal(t, "#ff0000", c.Color)
+		assert.NoError(t, err)
+	})
+
+	t.Run("32 < len(creation.Name)", func(t *testing.T) {
+		var c = &transfer.TagCreation{Name: strings.Repeat("x", 33)}
+		var r = mocks.NewTagRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTagService(r).Save(ownerID, c)
+		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("name", "tag", 1<<5).Error())
+		assert.Equal(t, uuid.Nil, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything).Return("", unexpected)
+		res, err = NewTagService(r).Save(ownerID, &transfer.TagCreation{Name: "work"})
+		assert.ErrorIs(t, err, unexpected)
+		assert.Equal(t, uuid.Nil, res)
+	})
+}
+
+func TestTagService_Fetch(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Fetch"
+	var (
+		ownerID = uuid.New()
+		tags    = []*model.Tag{{UUID: uuid.New()}, {UUID: uuid.New()}}
+		res     *types.Result[model.Tag]
+		err     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var pagination = &types.Pagination{Page: 2, RPP: 5}
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, ownerID.String(), int64(2), int64(5), "needle", "+name").Return(tags, nil)
+		res, err = NewTagService(r).Fetch(ownerID, pagination, blankset+"needle"+blankset, "+name")
+		assert.NoError(t, err)
+		assert.Equal(t, &types.Result[model.Tag]{Page: 2, RPP: 5, Retrieved: 2, Payload: tags}, res)
+	})
+
+	t.Run("\"pagination\" != nil", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTagService(r).Fetch(ownerID, nil, "", "")
+		assert.Nil(t, res)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "pagination").Error())
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
+		res, err = NewTagService(r).Fetch(ownerID, &types.Pagination{Page: 1, RPP: 10}, "", "")
+		assert.ErrorIs(t, err, unexpected)
+		assert.Nil(t, res)
+	})
+}
+
+func TestTagService_Update(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Update"
+	var (
+		ownerID, tagID = uuid.New(), uuid.New()
+		res            bool
+		err            error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var up = &transfer.TagUpdate{Name: "home"}
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, ownerID.String(), tagID.String(), up).Return(true, nil)
+		res, err = NewTagService(r).Update(ownerID, tagID, up)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("nothing to update", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTagService(r).Update(ownerID, tagID, &transfer.TagUpdate{Name: blankset})
+		assert.False(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("\"tagID\" != uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTagService(r).Update(ownerID, uuid.Nil, &transfer.TagUpdate{})
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Update", "tagID").Error())
+	})
+
+	t.Run("512 < len(update.Description)", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTagService(r).Update(ownerID, tagID, &transfer.TagUpdate{Description: strings.Repeat("x", 513)})
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("description", "tag", 1<<9).Error())
+	})
+}
+
+func TestTagService_Attach(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Attach"
+	var (
+		ownerID, taskID, tagID = uuid.New(), uuid.New(), uuid.New()
+		res                    bool
+		err                    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, ownerID.String(), taskID.String(), tagID.String()).Return(true, nil)
+		res, err = NewTagService(r).Attach(ownerID, taskID, tagID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTagService(r).Attach(ownerID, uuid.Nil, tagID)
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Attach", "taskID").Error())
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, failure.ErrTagNotFound)
+		res, err = NewTagService(r).Attach(ownerID, taskID, tagID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrTagNotFound)
+	})
+}
+
+func TestTagService_Delete(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Delete"
+	var ownerID, tagID = uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.On(routine, ownerID.String(), tagID.String()).Return(nil)
+		assert.NoError(t, NewTagService(r).Delete(ownerID, tagID))
+	})
+
+	t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewTagRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		var err = NewTagService(r).Delete(uuid.Nil, tagID)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Delete", "ownerID").Error())
+	})
+}
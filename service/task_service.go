@@ -1,6 +1,7 @@
 package service
 
 import (
+	"errors"
 	"github.com/google/uuid"
 	"log"
 	"noda/data/model"
@@ -15,15 +16,23 @@ type TaskService interface {
 	Save(ownerID, listID uuid.UUID, creation *transfer.TaskCreation) (insertedID uuid.UUID, err error)
 	Duplicate(ownerID, taskID uuid.UUID) (replicaID uuid.UUID, err error)
 	FetchByID(ownerID, listID, taskID uuid.UUID) (task *model.Task, err error)
-	Fetch(ownerID, listID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
-	FetchFromToday(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
-	FetchFromTomorrow(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
+	Fetch(ownerID, listID uuid.UUID, pagination *types.Pagination, needle, sortExpr string, filter *types.TagFilter, assigneeID uuid.UUID) (result *types.Result[model.Task], err error)
+	FetchFromToday(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string, filter *types.TagFilter) (result *types.Result[model.Task], err error)
+	FetchFromTomorrow(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string, filter *types.TagFilter) (result *types.Result[model.Task], err error)
 	FetchFromDeferred(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
+	FetchAll(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
+	FetchCompleted(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
+	FetchArchived(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
+	FetchTrashed(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error)
+	Locate(userID, taskID uuid.UUID) (listID uuid.UUID, err error)
 	Update(ownerID, listID, taskID uuid.UUID, update *transfer.TaskUpdate) (ok bool, err error)
 	Reorder(ownerID, listID, taskID uuid.UUID, position uint64) (ok bool, err error)
 	SetReminder(ownerID, listID, taskID uuid.UUID, remindAt time.Time) (ok bool, err error)
 	SetPriority(ownerID, listID, taskID uuid.UUID, priority types.TaskPriority) (ok bool, err error)
 	SetDueDate(ownerID, listID, taskID uuid.UUID, dueDate time.Time) (ok bool, err error)
+	SetRecurrence(ownerID, listID, taskID uuid.UUID, rule string) (ok bool, err error)
+	RemoveRecurrence(ownerID, listID, taskID uuid.UUID) (ok bool, err error)
+	Occurrences(ownerID, listID, taskID uuid.UUID, n int) (occurrences []time.Time, err error)
 	Complete(ownerID, listID, taskID uuid.UUID) (ok bool, err error)
 	Resume(ownerID, listID, taskID uuid.UUID) (ok bool, err error)
 	Pin(ownerID, listID, taskID uuid.UUID) (ok bool, err error)
@@ -37,24 +46,37 @@ type TaskService interface {
 	Delete(ownerID, listID, taskID uuid.UUID) error
 }
 
+// MaxOccurrences is the maximum number of occurrences of a recurring task that
+// can be expanded at once.
+const MaxOccurrences = 100
+
 type taskService struct {
-	r repository.TaskRepository
+	r       repository.TaskRepository
+	members repository.MemberRepository
 }
 
-func NewTaskService(repository repository.TaskRepository) TaskService {
-	return &taskService{r: repository}
+func NewTaskService(repository repository.TaskRepository, members repository.MemberRepository) TaskService {
+	return &taskService{r: repository, members: members}
+}
+
+// actAs authorizes the user to act on the tasks of the list with at least the
+// needed role and returns the owner of the list, on whose behalf the tasks are
+// then read or written.
+func (t *taskService) actAs(userID, listID uuid.UUID, needed types.MemberRole) (ownerID uuid.UUID, err error) {
+	access, err := authorizeList(t.members, userID, listID, needed)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	return access.OwnerUUID, nil
 }
 
 func (t *taskService) Save(ownerID, listID uuid.UUID, creation *transfer.TaskCreation) (insertedID uuid.UUID, err error) {
+	var listIDStr = ""
 	switch {
 	case uuid.Nil == ownerID:
 		err = failure.NewNilParameterError("Save", "ownerID")
 		log.Println(err)
 		return uuid.Nil, err
-	case uuid.Nil == listID:
-		err = failure.NewNilParameterError("Save", "listID")
-		log.Println(err)
-		return uuid.Nil, err
 	case nil == creation:
 		err = failure.NewNilParameterError("Save", "creation")
 		log.Println(err)
@@ -76,7 +98,14 @@ func (t *taskService) Save(ownerID, listID uuid.UUID, creation *transfer.TaskCre
 	if "" == creation.Status {
 		creation.Status = types.TaskStatusIncomplete
 	}
-	inserted, err := t.r.Save(ownerID.String(), listID.String(), creation)
+	if uuid.Nil != listID {
+		ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+		if nil != err {
+			return uuid.Nil, err
+		}
+		listIDStr = listID.String()
+	}
+	inserted, err := t.r.Save(ownerID.String(), listIDStr, creation)
 	if nil != err {
 		return uuid.Nil, err
 	}
@@ -94,7 +123,11 @@ func (t *taskService) Duplicate(ownerID, taskID uuid.UUID) (replicaID uuid.UUID,
 		log.Println(err)
 		return uuid.Nil, err
 	}
-	replica, err := t.r.Duplicate(ownerID.String(), taskID.String())
+	access, err := t.actOnTask(ownerID, taskID, types.MemberRoleEditor)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	replica, err := t.r.Duplicate(access.OwnerUUID.String(), taskID.String())
 	if nil != err {
 		return uuid.Nil, err
 	}
@@ -116,10 +149,22 @@ func (t *taskService) FetchByID(ownerID, listID, taskID uuid.UUID) (task *model.
 		log.Println(err)
 		return nil, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
 	return t.r.FetchByID(ownerID.String(), listID.String(), taskID.String())
 }
 
-func (t *taskService) Fetch(ownerID, listID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+// Fetch retrieves the tasks of a list. If assigneeID is not uuid.Nil, only the
+// tasks assigned to that user are retrieved.
+func (t *taskService) Fetch(
+	ownerID, listID uuid.UUID,
+	pagination *types.Pagination,
+	needle, sortExpr string,
+	filter *types.TagFilter,
+	assigneeID uuid.UUID,
+) (result *types.Result[model.Task], err error) {
 	switch {
 	case uuid.Nil == ownerID:
 		err = failure.NewNilParameterError("Fetch", "ownerID")
@@ -134,9 +179,20 @@ func (t *taskService) Fetch(ownerID, listID uuid.UUID, pagination *types.Paginat
 		log.Println(err)
 		return nil, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
 	doDefaultPagination(pagination)
 	doTrim(&needle, &sortExpr)
-	tasks, err := t.r.Fetch(ownerID.String(), listID.String(), pagination.Page, pagination.RPP, needle, sortExpr)
+	var (
+		tagIDs, matchAllTags = unfoldTagFilter(filter)
+		assigneeIDStr        = ""
+	)
+	if uuid.Nil != assigneeID {
+		assigneeIDStr = assigneeID.String()
+	}
+	tasks, err := t.r.Fetch(ownerID.String(), listID.String(), pagination.Page, pagination.RPP, needle, sortExpr, tagIDs, matchAllTags, assigneeIDStr)
 	if nil != err {
 		return nil, err
 	}
@@ -149,7 +205,7 @@ func (t *taskService) Fetch(ownerID, listID uuid.UUID, pagination *types.Paginat
 	return result, nil
 }
 
-func (t *taskService) FetchFromToday(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+func (t *taskService) FetchFromToday(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string, filter *types.TagFilter) (result *types.Result[model.Task], err error) {
 	switch {
 	case uuid.Nil == ownerID:
 		err = failure.NewNilParameterError("FetchFromToday", "ownerID")
@@ -162,7 +218,8 @@ func (t *taskService) FetchFromToday(ownerID uuid.UUID, pagination *types.Pagina
 	}
 	doDefaultPagination(pagination)
 	doTrim(&needle, &sortExpr)
-	tasks, err := t.r.FetchFromToday(ownerID.String(), pagination.Page, pagination.RPP, needle, sortExpr)
+	var tagIDs, matchAllTags = unfoldTagFilter(filter)
+	tasks, err := t.r.FetchFromToday(ownerID.String(), pagination.Page, pagination.RPP, needle, sortExpr, tagIDs, matchAllTags)
 	if nil != err {
 		return nil, err
 	}
@@ -175,7 +232,7 @@ func (t *taskService) FetchFromToday(ownerID uuid.UUID, pagination *types.Pagina
 	return result, nil
 }
 
-func (t *taskService) FetchFromTomorrow(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+func (t *taskService) FetchFromTomorrow(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string, filter *types.TagFilter) (result *types.Result[model.Task], err error) {
 	switch {
 	case uuid.Nil == ownerID:
 		err = failure.NewNilParameterError("FetchFromTomorrow", "ownerID")
@@ -188,7 +245,8 @@ func (t *taskService) FetchFromTomorrow(ownerID uuid.UUID, pagination *types.Pag
 	}
 	doDefaultPagination(pagination)
 	doTrim(&needle, &sortExpr)
-	tasks, err := t.r.FetchFromTomorrow(ownerID.String(), pagination.Page, pagination.RPP, needle, sortExpr)
+	var tagIDs, matchAllTags = unfoldTagFilter(filter)
+	tasks, err := t.r.FetchFromTomorrow(ownerID.String(), pagination.Page, pagination.RPP, needle, sortExpr, tagIDs, matchAllTags)
 	if nil != err {
 		return nil, err
 	}
@@ -227,6 +285,115 @@ func (t *taskService) FetchFromDeferred(ownerID uuid.UUID, pagination *types.Pag
 	return result, nil
 }
 
+// FetchAll retrieves the tasks of every list of the owner.
+func (t *taskService) FetchAll(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+	return t.doFetchAcrossLists("FetchAll", t.r.FetchAll, ownerID, pagination, needle, sortExpr)
+}
+
+// FetchCompleted retrieves the completed tasks of every list of the owner.
+func (t *taskService) FetchCompleted(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+	return t.doFetchAcrossLists("FetchCompleted", t.r.FetchCompleted, ownerID, pagination, needle, sortExpr)
+}
+
+// FetchArchived retrieves the tasks of the archived lists of the owner.
+func (t *taskService) FetchArchived(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+	return t.doFetchAcrossLists("FetchArchived", t.r.FetchArchived, ownerID, pagination, needle, sortExpr)
+}
+
+// FetchTrashed retrieves the tasks of the owner that are in the trash.
+func (t *taskService) FetchTrashed(ownerID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[model.Task], err error) {
+	return t.doFetchAcrossLists("FetchTrashed", t.r.FetchTrashed, ownerID, pagination, needle, sortExpr)
+}
+
+func (t *taskService) doFetchAcrossLists(
+	operation string,
+	fetch func(ownerID string, page, rpp int64, needle, sortExpr string) ([]*model.Task, error),
+	ownerID uuid.UUID,
+	pagination *types.Pagination,
+	needle, sortExpr string,
+) (result *types.Result[model.Task], err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError(operation, "ownerID")
+		log.Println(err)
+		return nil, err
+	case nil == pagination:
+		err = failure.NewNilParameterError(operation, "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doDefaultPagination(pagination)
+	doTrim(&needle, &sortExpr)
+	tasks, err := fetch(ownerID.String(), pagination.Page, pagination.RPP, needle, sortExpr)
+	if nil != err {
+		return nil, err
+	}
+	result = &types.Result[model.Task]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(tasks)),
+		Payload:   tasks,
+	}
+	return result, nil
+}
+
+// Locate retrieves the list of a task the user can see, so that a task can be
+// addressed by its ID alone.
+func (t *taskService) Locate(userID, taskID uuid.UUID) (listID uuid.UUID, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("Locate", "userID")
+		log.Println(err)
+		return uuid.Nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Locate", "taskID")
+		log.Println(err)
+		return uuid.Nil, err
+	}
+	access, err := t.actOnTask(userID, taskID, types.MemberRoleViewer)
+	if nil != err {
+		return uuid.Nil, err
+	}
+	return access.ListUUID, nil
+}
+
+// actOnTask authorizes the user to act on the task, wherever it is, with at
+// least the needed role on its list.
+func (t *taskService) actOnTask(userID, taskID uuid.UUID, needed types.MemberRole) (access *model.ListAccess, err error) {
+	located, err := t.r.Locate(taskID.String())
+	if nil != err {
+		return nil, err
+	}
+	listID, err := uuid.Parse(located)
+	if nil != err {
+		return nil, err
+	}
+	access, err = authorizeList(t.members, userID, listID, needed)
+	if errors.Is(err, failure.ErrListNotFound) {
+		/* Whether the task exists is none of the business of strangers.  */
+		return nil, failure.ErrTaskNotFound
+	}
+	if nil != err {
+		return nil, err
+	}
+	return access, nil
+}
+
+// actOnOwnTask makes sure the user owns the list of the task, for the task
+// is moved to the Today, Tomorrow or Deferred list of the user: the editors of
+// a shared list may not take its tasks to their own lists, nor to those of
+// its owner.
+func (t *taskService) actOnOwnTask(userID, taskID uuid.UUID) error {
+	access, err := t.actOnTask(userID, taskID, types.MemberRoleEditor)
+	if nil != err {
+		return err
+	}
+	if userID != access.OwnerUUID {
+		return failure.ErrCrossOwnerMove
+	}
+	return nil
+}
+
 func (t *taskService) Update(ownerID, listID, taskID uuid.UUID, update *transfer.TaskUpdate) (ok bool, err error) {
 	switch {
 	case uuid.Nil == ownerID:
@@ -248,6 +415,10 @@ func (t *taskService) Update(ownerID, listID, taskID uuid.UUID, update *transfer
 	case 512 < len(update.Description):
 		return false, failure.ErrTooLong.Clone().FormatDetails("Description", "update", 512)
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	doTrim(&update.Title, &update.Headline, &update.Description)
 	return t.r.Update(ownerID.String(), listID.String(), taskID.String(), update)
 }
@@ -267,6 +438,10 @@ func (t *taskService) Reorder(ownerID, listID, taskID uuid.UUID, position uint64
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.Reorder(ownerID.String(), listID.String(), taskID.String(), position)
 }
 
@@ -285,6 +460,10 @@ func (t *taskService) SetReminder(ownerID, listID, taskID uuid.UUID, remindAt ti
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.SetReminder(ownerID.String(), listID.String(), taskID.String(), remindAt)
 }
 
@@ -303,6 +482,10 @@ func (t *taskService) SetPriority(ownerID, listID, taskID uuid.UUID, priority ty
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.SetPriority(ownerID.String(), listID.String(), taskID.String(), priority)
 }
 
@@ -321,9 +504,106 @@ func (t *taskService) SetDueDate(ownerID, listID, taskID uuid.UUID, dueDate time
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.SetDueDate(ownerID.String(), listID.String(), taskID.String(), dueDate)
 }
 
+func (t *taskService) SetRecurrence(ownerID, listID, taskID uuid.UUID, rule string) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("SetRecurrence", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("SetRecurrence", "listID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("SetRecurrence", "taskID")
+		log.Println(err)
+		return false, err
+	case 1<<8 < len(rule):
+		return false, failure.ErrTooLong.Clone().FormatDetails("rule", "recurrence", 1<<8)
+	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	parsed, err := types.ParseRecurrenceRule(rule)
+	if nil != err {
+		return false, failure.ErrBadRecurrence.Clone().FormatDetails(err.Error())
+	}
+	task, err := t.r.FetchByID(ownerID.String(), listID.String(), taskID.String())
+	if nil != err {
+		return false, err
+	}
+	if nil == task.DueDate {
+		return false, failure.ErrBadRecurrence.Clone().FormatDetails("the task must have a due date to recur")
+	}
+	return t.r.SetRecurrence(ownerID.String(), listID.String(), taskID.String(), parsed)
+}
+
+func (t *taskService) RemoveRecurrence(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("RemoveRecurrence", "ownerID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("RemoveRecurrence", "listID")
+		log.Println(err)
+		return false, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("RemoveRecurrence", "taskID")
+		log.Println(err)
+		return false, err
+	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	return t.r.SetRecurrence(ownerID.String(), listID.String(), taskID.String(), nil)
+}
+
+// Occurrences expands up to n occurrences of the task, starting with its due
+// date. A task that does not recur has only one occurrence, or none if it has
+// no due date.
+func (t *taskService) Occurrences(ownerID, listID, taskID uuid.UUID, n int) (occurrences []time.Time, err error) {
+	switch {
+	case uuid.Nil == ownerID:
+		err = failure.NewNilParameterError("Occurrences", "ownerID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == listID:
+		err = failure.NewNilParameterError("Occurrences", "listID")
+		log.Println(err)
+		return nil, err
+	case uuid.Nil == taskID:
+		err = failure.NewNilParameterError("Occurrences", "taskID")
+		log.Println(err)
+		return nil, err
+	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleViewer)
+	if nil != err {
+		return nil, err
+	}
+	n = min(max(n, 0), MaxOccurrences)
+	task, err := t.r.FetchByID(ownerID.String(), listID.String(), taskID.String())
+	if nil != err {
+		return nil, err
+	}
+	switch {
+	case nil == task.DueDate || 0 == n:
+		return []time.Time{}, nil
+	case nil == task.Recurrence:
+		return []time.Time{*task.DueDate}, nil
+	}
+	return task.Recurrence.Occurrences(*task.DueDate, n), nil
+}
+
 func (t *taskService) Complete(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
 	switch {
 	case uuid.Nil == ownerID:
@@ -339,7 +619,49 @@ func (t *taskService) Complete(ownerID, listID, taskID uuid.UUID) (ok bool, err
 		log.Println(err)
 		return false, err
 	}
-	return t.r.Complete(ownerID.String(), listID.String(), taskID.String())
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	ok, err = t.r.Complete(ownerID.String(), listID.String(), taskID.String())
+	if nil != err || !ok {
+		return ok, err
+	}
+	task, err := t.r.FetchByID(ownerID.String(), listID.String(), taskID.String())
+	if nil == err && nil != task.Recurrence && nil != task.DueDate {
+		err = t.doMakeNextOccurrence(task)
+	}
+	if nil != err {
+		/* A recurring task must not end without its next occurrence, so the
+		   completion is undone and can be retried.  */
+		if _, undoErr := t.r.Resume(ownerID.String(), listID.String(), taskID.String()); nil != undoErr {
+			log.Printf("could not undo the completion of task %q: %v", taskID, undoErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// doMakeNextOccurrence makes the task that follows the recurring task, if the
+// series has not ended. Its due date is the next occurrence of the rule and its
+// reminder keeps the same distance to the due date.
+func (t *taskService) doMakeNextOccurrence(task *model.Task) error {
+	next, found := task.Recurrence.Next(*task.DueDate, *task.DueDate)
+	if !found {
+		return nil
+	}
+	var rule = *task.Recurrence
+	if 0 < rule.Count {
+		/* COUNT counts the occurrence that was just completed.  */
+		rule.Count--
+	}
+	var remindAt *time.Time
+	if nil != task.RemindAt {
+		var shifted = task.RemindAt.Add(next.Sub(*task.DueDate))
+		remindAt = &shifted
+	}
+	_, err := t.r.SaveOccurrence(task.OwnerUUID.String(), task.ListUUID.String(), task.UUID.String(), next, remindAt, &rule)
+	return err
 }
 
 func (t *taskService) Resume(ownerID, listID, taskID uuid.UUID) (ok bool, err error) {
@@ -357,6 +679,10 @@ func (t *taskService) Resume(ownerID, listID, taskID uuid.UUID) (ok bool, err er
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.Resume(ownerID.String(), listID.String(), taskID.String())
 }
 
@@ -375,6 +701,10 @@ func (t *taskService) Pin(ownerID, listID, taskID uuid.UUID) (ok bool, err error
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.Pin(ownerID.String(), listID.String(), taskID.String())
 }
 
@@ -393,6 +723,10 @@ func (t *taskService) Unpin(ownerID, listID, taskID uuid.UUID) (ok bool, err err
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.Unpin(ownerID.String(), listID.String(), taskID.String())
 }
 
@@ -411,7 +745,18 @@ func (t *taskService) Move(ownerID, taskID, targetListID uuid.UUID) (ok bool, er
 		log.Println(err)
 		return false, err
 	}
-	return t.r.Move(ownerID.String(), taskID.String(), targetListID.String())
+	source, err := t.actOnTask(ownerID, taskID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	target, err := authorizeList(t.members, ownerID, targetListID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
+	if source.OwnerUUID != target.OwnerUUID {
+		return false, failure.ErrCrossOwnerMove
+	}
+	return t.r.Move(source.OwnerUUID.String(), taskID.String(), targetListID.String())
 }
 
 func (t *taskService) Today(ownerID, taskID uuid.UUID) (ok bool, err error) {
@@ -425,6 +770,10 @@ func (t *taskService) Today(ownerID, taskID uuid.UUID) (ok bool, err error) {
 		log.Println(err)
 		return false, err
 	}
+	err = t.actOnOwnTask(ownerID, taskID)
+	if nil != err {
+		return false, err
+	}
 	return t.r.Today(ownerID.String(), taskID.String())
 }
 
@@ -439,6 +788,10 @@ func (t *taskService) Tomorrow(ownerID, taskID uuid.UUID) (ok bool, err error) {
 		log.Println(err)
 		return false, err
 	}
+	err = t.actOnOwnTask(ownerID, taskID)
+	if nil != err {
+		return false, err
+	}
 	return t.r.Tomorrow(ownerID.String(), taskID.String())
 }
 
@@ -453,6 +806,10 @@ func (t *taskService) Defer(ownerID, taskID uuid.UUID) (ok bool, err error) {
 		log.Println(err)
 		return false, err
 	}
+	err = t.actOnOwnTask(ownerID, taskID)
+	if nil != err {
+		return false, err
+	}
 	return t.r.Defer(ownerID.String(), taskID.String())
 }
 
@@ -471,6 +828,10 @@ func (t *taskService) Trash(ownerID, listID, taskID uuid.UUID) (ok bool, err err
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.Trash(ownerID.String(), listID.String(), taskID.String())
 }
 
@@ -489,6 +850,10 @@ func (t *taskService) RestoreFromTrash(ownerID, listID, taskID uuid.UUID) (ok bo
 		log.Println(err)
 		return false, err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return false, err
+	}
 	return t.r.RestoreFromTrash(ownerID.String(), listID.String(), taskID.String())
 }
 
@@ -508,5 +873,9 @@ func (t *taskService) Delete(ownerID, listID, taskID uuid.UUID) error {
 		log.Println(err)
 		return err
 	}
+	ownerID, err = t.actAs(ownerID, listID, types.MemberRoleEditor)
+	if nil != err {
+		return err
+	}
 	return t.r.Delete(ownerID.String(), listID.String(), taskID.String())
 }
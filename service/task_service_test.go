@@ -36,7 +36,7 @@ func TestTaskService_Save(t *testing.T) {
 		}
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), c).Return(inserted.String(), nil)
-		res, err = NewTaskService(r).Save(ownerID, listID, c)
+		res, err = NewTaskService(r, soleOwner{}).Save(ownerID, listID, c)
 		assert.Equal(t, inserted, res)
 		assert.NoError(t, err)
 	})
@@ -47,28 +47,29 @@ func TestTaskService_Save(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Save(uuid.Nil, listID, c)
+			res, err = NewTaskService(r, soleOwner{}).Save(uuid.Nil, listID, c)
 			assert.Equal(t, uuid.Nil, res)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "ownerID").Error())
 		})
 
-		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
-			var r = mocks.NewTaskRepositoryMock()
-			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Save(ownerID, uuid.Nil, c)
-			assert.Equal(t, uuid.Nil, res)
-			assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "listID").Error())
-		})
-
 		t.Run("\"creation\" != nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Save(ownerID, listID, nil)
+			res, err = NewTaskService(r, soleOwner{}).Save(ownerID, listID, nil)
 			assert.Equal(t, uuid.Nil, res)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Save", "creation").Error())
 		})
 	})
 
+	t.Run("\"listID\" == uuid.Nil saves the task into the Today list", func(t *testing.T) {
+		var c = &transfer.TaskCreation{Title: "title"}
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, ownerID.String(), "", c).Return(inserted.String(), nil)
+		res, err = NewTaskService(r, soleOwner{}).Save(ownerID, uuid.Nil, c)
+		assert.Equal(t, inserted, res)
+		assert.NoError(t, err)
+	})
+
 	t.Run("must trim all string fields in \"creation\"", func(t *testing.T) {
 		var c = &transfer.TaskCreation{
 			Title:       blankset + "Title" + blankset,
@@ -77,7 +78,7 @@ func TestTaskService_Save(t *testing.T) {
 		}
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(inserted.String(), nil)
-		res, err = NewTaskService(r).Save(ownerID, listID, c)
+		res, err = NewTaskService(r, soleOwner{}).Save(ownerID, listID, c)
 		assert.Equal(t, inserted, res)
 		assert.Equal(t, "Title", c.Title)
 		assert.Equal(t, "Headline", c.Headline)
@@ -92,7 +93,7 @@ func TestTaskService_Save(t *testing.T) {
 		}
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(inserted.String(), nil)
-		res, err = NewTaskService(r).Save(ownerID, listID, c)
+		res, err = NewTaskService(r, soleOwner{}).Save(ownerID, listID, c)
 		assert.Equal(t, inserted, res)
 		assert.Equal(t, "Untitled", c.Title)
 		assert.Equal(t, types.TaskPriorityMedium, c.Priority)
@@ -107,7 +108,7 @@ func TestTaskService_Save(t *testing.T) {
 			c.Title = strings.Repeat("x", 129)
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Save(ownerID, listID, c)
+			res, err = NewTaskService(r, soleOwner{}).Save(ownerID, listID, c)
 			assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Title", "creation", 128).Error())
 			assert.Equal(t, uuid.Nil, res)
 			c.Title = ""
@@ -117,7 +118,7 @@ func TestTaskService_Save(t *testing.T) {
 			c.Headline = strings.Repeat("x", 65)
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Save(ownerID, listID, c)
+			res, err = NewTaskService(r, soleOwner{}).Save(ownerID, listID, c)
 			assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Headline", "creation", 64).Error())
 			assert.Equal(t, uuid.Nil, res)
 			c.Headline = ""
@@ -127,7 +128,7 @@ func TestTaskService_Save(t *testing.T) {
 			c.Description = strings.Repeat("x", 513)
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Save(ownerID, listID, c)
+			res, err = NewTaskService(r, soleOwner{}).Save(ownerID, listID, c)
 			assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Description", "creation", 512).Error())
 			assert.Equal(t, uuid.Nil, res)
 			c.Description = ""
@@ -139,7 +140,7 @@ func TestTaskService_Save(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return("", unexpected)
-		res, err = NewTaskService(r).Save(ownerID, listID, c)
+		res, err = NewTaskService(r, soleOwner{}).Save(ownerID, listID, c)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Equal(t, uuid.Nil, res)
 	})
@@ -149,15 +150,16 @@ func TestTaskService_Duplicate(t *testing.T) {
 	defer beQuiet()()
 	const routine = "Duplicate"
 	var (
-		res                        uuid.UUID
-		err                        error
-		ownerID, taskID, replicaID = uuid.New(), uuid.New(), uuid.New()
+		res                                uuid.UUID
+		err                                error
+		ownerID, listID, taskID, replicaID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
 	)
 
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, ownerID.String(), taskID.String()).Return(replicaID.String(), nil)
-		res, err = NewTaskService(r).Duplicate(ownerID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Duplicate(ownerID, taskID)
 		assert.Equal(t, replicaID, res)
 		assert.NoError(t, err)
 	})
@@ -166,7 +168,7 @@ func TestTaskService_Duplicate(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Duplicate(uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Duplicate(uuid.Nil, taskID)
 			assert.Equal(t, uuid.Nil, res)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Duplicate", "ownerID").Error())
 		})
@@ -174,7 +176,7 @@ func TestTaskService_Duplicate(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Duplicate(ownerID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Duplicate(ownerID, uuid.Nil)
 			assert.Equal(t, uuid.Nil, res)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Duplicate", "taskID").Error())
 		})
@@ -183,11 +185,40 @@ func TestTaskService_Duplicate(t *testing.T) {
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, mock.Anything, mock.Anything).Return("", unexpected)
-		res, err = NewTaskService(r).Duplicate(ownerID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Duplicate(ownerID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Equal(t, uuid.Nil, res)
 	})
+
+	t.Run("a member acts on behalf of the owner", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		r.On(routine, ownerID.String(), taskID.String()).Return(replicaID.String(), nil)
+		res, err = NewTaskService(r, members).Duplicate(memberID, taskID)
+		assert.Equal(t, replicaID, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("task of a stranger", func(t *testing.T) {
+		var (
+			r       = mocks.NewTaskRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", mock.Anything, mock.Anything).Return(nil, failure.ErrListNotFound)
+		res, err = NewTaskService(r, members).Duplicate(uuid.New(), taskID)
+		assert.Equal(t, uuid.Nil, res)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		r.AssertNotCalled(t, routine)
+	})
 }
 
 func TestTaskService_FetchByID(t *testing.T) {
@@ -206,7 +237,7 @@ func TestTaskService_FetchByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, task.OwnerUUID.String(), task.ListUUID.String(), task.UUID.String()).Return(task, nil)
-		res, err = NewTaskService(r).FetchByID(task.OwnerUUID, task.ListUUID, task.UUID)
+		res, err = NewTaskService(r, soleOwner{}).FetchByID(task.OwnerUUID, task.ListUUID, task.UUID)
 		assert.Equal(t, task, res)
 		assert.NoError(t, err)
 	})
@@ -215,7 +246,7 @@ func TestTaskService_FetchByID(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchByID(uuid.Nil, task.ListUUID, task.UUID)
+			res, err = NewTaskService(r, soleOwner{}).FetchByID(uuid.Nil, task.ListUUID, task.UUID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchByID", "ownerID").Error())
 			assert.Nil(t, res)
 		})
@@ -223,7 +254,7 @@ func TestTaskService_FetchByID(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchByID(task.OwnerUUID, uuid.Nil, task.UUID)
+			res, err = NewTaskService(r, soleOwner{}).FetchByID(task.OwnerUUID, uuid.Nil, task.UUID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchByID", "listID").Error())
 			assert.Nil(t, res)
 		})
@@ -231,7 +262,7 @@ func TestTaskService_FetchByID(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchByID(task.OwnerUUID, task.ListUUID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).FetchByID(task.OwnerUUID, task.ListUUID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchByID", "taskID").Error())
 			assert.Nil(t, res)
 		})
@@ -241,7 +272,7 @@ func TestTaskService_FetchByID(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
-		res, err = NewTaskService(r).FetchByID(task.OwnerUUID, task.ListUUID, task.UUID)
+		res, err = NewTaskService(r, soleOwner{}).FetchByID(task.OwnerUUID, task.ListUUID, task.UUID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
 	})
@@ -274,8 +305,8 @@ func TestTaskService_Fetch(t *testing.T) {
 			Payload:   tasks,
 		}
 		var r = mocks.NewTaskRepositoryMock()
-		r.On(routine, ownerID.String(), listID.String(), page, rpp, needle, sortExpr).Return(tasks, nil)
-		res, err = NewTaskService(r).Fetch(ownerID, listID, pagination, needle, sortExpr)
+		r.On(routine, ownerID.String(), listID.String(), page, rpp, needle, sortExpr, []string(nil), false, "").Return(tasks, nil)
+		res, err = NewTaskService(r, soleOwner{}).Fetch(ownerID, listID, pagination, needle, sortExpr, nil, uuid.Nil)
 		assert.Equal(t, result, res)
 		assert.NoError(t, err)
 	})
@@ -284,7 +315,7 @@ func TestTaskService_Fetch(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Fetch(uuid.Nil, listID, pagination, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).Fetch(uuid.Nil, listID, pagination, needle, sortExpr, nil, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "ownerID").Error())
 			assert.Nil(t, res)
 		})
@@ -292,7 +323,7 @@ func TestTaskService_Fetch(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Fetch(ownerID, uuid.Nil, pagination, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).Fetch(ownerID, uuid.Nil, pagination, needle, sortExpr, nil, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "listID").Error())
 			assert.Nil(t, res)
 		})
@@ -300,7 +331,7 @@ func TestTaskService_Fetch(t *testing.T) {
 		t.Run("\"pagination\" != nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Fetch(ownerID, listID, nil, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).Fetch(ownerID, listID, nil, needle, sortExpr, nil, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Fetch", "pagination").Error())
 			assert.Nil(t, res)
 		})
@@ -310,15 +341,15 @@ func TestTaskService_Fetch(t *testing.T) {
 		t.Run("\"needle\" is trimmed", func(t *testing.T) {
 			var n = blankset + needle + blankset
 			var r = mocks.NewTaskRepositoryMock()
-			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, needle, mock.Anything).Return(tasks, nil)
-			_, _ = NewTaskService(r).Fetch(ownerID, listID, pagination, n, sortExpr)
+			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, needle, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tasks, nil)
+			_, _ = NewTaskService(r, soleOwner{}).Fetch(ownerID, listID, pagination, n, sortExpr, nil, uuid.Nil)
 		})
 
 		t.Run("\"sortExpr\" is trimmed", func(t *testing.T) {
 			var s = blankset + sortExpr + blankset
 			var r = mocks.NewTaskRepositoryMock()
-			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sortExpr).Return(tasks, nil)
-			_, _ = NewTaskService(r).Fetch(ownerID, listID, pagination, needle, s)
+			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sortExpr, mock.Anything, mock.Anything, mock.Anything).Return(tasks, nil)
+			_, _ = NewTaskService(r, soleOwner{}).Fetch(ownerID, listID, pagination, needle, s, nil, uuid.Nil)
 		})
 	})
 
@@ -327,17 +358,36 @@ func TestTaskService_Fetch(t *testing.T) {
 		pagination.Page = -1
 		pagination.RPP = 0
 		var r = mocks.NewTaskRepositoryMock()
-		r.On(routine, mock.Anything, mock.Anything, expectedPage, expectedRPP, mock.Anything, mock.Anything).Return(tasks, nil)
-		_, _ = NewTaskService(r).Fetch(ownerID, listID, pagination, needle, sortExpr)
+		r.On(routine, mock.Anything, mock.Anything, expectedPage, expectedRPP, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tasks, nil)
+		_, _ = NewTaskService(r, soleOwner{}).Fetch(ownerID, listID, pagination, needle, sortExpr, nil, uuid.Nil)
+	})
+
+	t.Run("filters by tags", func(t *testing.T) {
+		var tagA, tagB = uuid.New(), uuid.New()
+		var filter = &types.TagFilter{Tags: []uuid.UUID{tagA, tagB}, MatchAll: true}
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, []string{tagA.String(), tagB.String()}, true, "").Return(tasks, nil)
+		res, err = NewTaskService(r, soleOwner{}).Fetch(ownerID, listID, pagination, needle, sortExpr, filter, uuid.Nil)
+		assert.Equal(t, tasks, res.Payload)
+		assert.NoError(t, err)
+	})
+
+	t.Run("filters by assignee", func(t *testing.T) {
+		var assigneeID = uuid.New()
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, assigneeID.String()).Return(tasks, nil)
+		res, err = NewTaskService(r, soleOwner{}).Fetch(ownerID, listID, pagination, needle, sortExpr, nil, assigneeID)
+		assert.Equal(t, tasks, res.Payload)
+		assert.NoError(t, err)
 	})
 
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.
-			On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, unexpected)
-		res, err = NewTaskService(r).Fetch(ownerID, listID, pagination, needle, sortExpr)
+		res, err = NewTaskService(r, soleOwner{}).Fetch(ownerID, listID, pagination, needle, sortExpr, nil, uuid.Nil)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
 	})
@@ -370,8 +420,8 @@ func TestTaskService_FetchFromToday(t *testing.T) {
 			Payload:   tasks,
 		}
 		var r = mocks.NewTaskRepositoryMock()
-		r.On(routine, ownerID.String(), page, rpp, needle, sortExpr).Return(tasks, nil)
-		res, err = NewTaskService(r).FetchFromToday(ownerID, pagination, needle, sortExpr)
+		r.On(routine, ownerID.String(), page, rpp, needle, sortExpr, []string(nil), false).Return(tasks, nil)
+		res, err = NewTaskService(r, soleOwner{}).FetchFromToday(ownerID, pagination, needle, sortExpr, nil)
 		assert.Equal(t, result, res)
 		assert.NoError(t, err)
 	})
@@ -380,7 +430,7 @@ func TestTaskService_FetchFromToday(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchFromToday(uuid.Nil, pagination, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).FetchFromToday(uuid.Nil, pagination, needle, sortExpr, nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchFromToday", "ownerID").Error())
 			assert.Nil(t, res)
 		})
@@ -388,7 +438,7 @@ func TestTaskService_FetchFromToday(t *testing.T) {
 		t.Run("\"pagination\" != nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchFromToday(ownerID, nil, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).FetchFromToday(ownerID, nil, needle, sortExpr, nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchFromToday", "pagination").Error())
 			assert.Nil(t, res)
 		})
@@ -398,15 +448,15 @@ func TestTaskService_FetchFromToday(t *testing.T) {
 		t.Run("\"needle\" is trimmed", func(t *testing.T) {
 			var n = blankset + needle + blankset
 			var r = mocks.NewTaskRepositoryMock()
-			r.On(routine, mock.Anything, mock.Anything, mock.Anything, needle, mock.Anything).Return(tasks, nil)
-			_, _ = NewTaskService(r).FetchFromToday(ownerID, pagination, n, sortExpr)
+			r.On(routine, mock.Anything, mock.Anything, mock.Anything, needle, mock.Anything, mock.Anything, mock.Anything).Return(tasks, nil)
+			_, _ = NewTaskService(r, soleOwner{}).FetchFromToday(ownerID, pagination, n, sortExpr, nil)
 		})
 
 		t.Run("\"sortExpr\" is trimmed", func(t *testing.T) {
 			var s = blankset + sortExpr + blankset
 			var r = mocks.NewTaskRepositoryMock()
-			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sortExpr).Return(tasks, nil)
-			_, _ = NewTaskService(r).FetchFromToday(ownerID, pagination, needle, s)
+			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sortExpr, mock.Anything, mock.Anything).Return(tasks, nil)
+			_, _ = NewTaskService(r, soleOwner{}).FetchFromToday(ownerID, pagination, needle, s, nil)
 		})
 	})
 
@@ -415,17 +465,27 @@ func TestTaskService_FetchFromToday(t *testing.T) {
 		pagination.Page = -1
 		pagination.RPP = 0
 		var r = mocks.NewTaskRepositoryMock()
-		r.On(routine, mock.Anything, expectedPage, expectedRPP, mock.Anything, mock.Anything).Return(tasks, nil)
-		_, _ = NewTaskService(r).FetchFromToday(ownerID, pagination, needle, sortExpr)
+		r.On(routine, mock.Anything, expectedPage, expectedRPP, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tasks, nil)
+		_, _ = NewTaskService(r, soleOwner{}).FetchFromToday(ownerID, pagination, needle, sortExpr, nil)
+	})
+
+	t.Run("filters by tags", func(t *testing.T) {
+		var tagA, tagB = uuid.New(), uuid.New()
+		var filter = &types.TagFilter{Tags: []uuid.UUID{tagA, tagB}, MatchAll: true}
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, []string{tagA.String(), tagB.String()}, true).Return(tasks, nil)
+		res, err = NewTaskService(r, soleOwner{}).FetchFromToday(ownerID, pagination, needle, sortExpr, filter)
+		assert.Equal(t, tasks, res.Payload)
+		assert.NoError(t, err)
 	})
 
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.
-			On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, unexpected)
-		res, err = NewTaskService(r).FetchFromToday(ownerID, pagination, needle, sortExpr)
+		res, err = NewTaskService(r, soleOwner{}).FetchFromToday(ownerID, pagination, needle, sortExpr, nil)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
 	})
@@ -458,8 +518,8 @@ func TestTaskService_FetchFromTomorrow(t *testing.T) {
 			Payload:   tasks,
 		}
 		var r = mocks.NewTaskRepositoryMock()
-		r.On(routine, ownerID.String(), page, rpp, needle, sortExpr).Return(tasks, nil)
-		res, err = NewTaskService(r).FetchFromTomorrow(ownerID, pagination, needle, sortExpr)
+		r.On(routine, ownerID.String(), page, rpp, needle, sortExpr, []string(nil), false).Return(tasks, nil)
+		res, err = NewTaskService(r, soleOwner{}).FetchFromTomorrow(ownerID, pagination, needle, sortExpr, nil)
 		assert.Equal(t, result, res)
 		assert.NoError(t, err)
 	})
@@ -468,7 +528,7 @@ func TestTaskService_FetchFromTomorrow(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchFromTomorrow(uuid.Nil, pagination, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).FetchFromTomorrow(uuid.Nil, pagination, needle, sortExpr, nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchFromTomorrow", "ownerID").Error())
 			assert.Nil(t, res)
 		})
@@ -476,7 +536,7 @@ func TestTaskService_FetchFromTomorrow(t *testing.T) {
 		t.Run("\"pagination\" != nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchFromTomorrow(ownerID, nil, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).FetchFromTomorrow(ownerID, nil, needle, sortExpr, nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchFromTomorrow", "pagination").Error())
 			assert.Nil(t, res)
 		})
@@ -486,15 +546,15 @@ func TestTaskService_FetchFromTomorrow(t *testing.T) {
 		t.Run("\"needle\" is trimmed", func(t *testing.T) {
 			var n = blankset + needle + blankset
 			var r = mocks.NewTaskRepositoryMock()
-			r.On(routine, mock.Anything, mock.Anything, mock.Anything, needle, mock.Anything).Return(tasks, nil)
-			_, _ = NewTaskService(r).FetchFromTomorrow(ownerID, pagination, n, sortExpr)
+			r.On(routine, mock.Anything, mock.Anything, mock.Anything, needle, mock.Anything, mock.Anything, mock.Anything).Return(tasks, nil)
+			_, _ = NewTaskService(r, soleOwner{}).FetchFromTomorrow(ownerID, pagination, n, sortExpr, nil)
 		})
 
 		t.Run("\"sortExpr\" is trimmed", func(t *testing.T) {
 			var s = blankset + sortExpr + blankset
 			var r = mocks.NewTaskRepositoryMock()
-			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sortExpr).Return(tasks, nil)
-			_, _ = NewTaskService(r).FetchFromTomorrow(ownerID, pagination, needle, s)
+			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sortExpr, mock.Anything, mock.Anything).Return(tasks, nil)
+			_, _ = NewTaskService(r, soleOwner{}).FetchFromTomorrow(ownerID, pagination, needle, s, nil)
 		})
 	})
 
@@ -503,17 +563,27 @@ func TestTaskService_FetchFromTomorrow(t *testing.T) {
 		pagination.Page = -1
 		pagination.RPP = 0
 		var r = mocks.NewTaskRepositoryMock()
-		r.On(routine, mock.Anything, expectedPage, expectedRPP, mock.Anything, mock.Anything).Return(tasks, nil)
-		_, _ = NewTaskService(r).FetchFromTomorrow(ownerID, pagination, needle, sortExpr)
+		r.On(routine, mock.Anything, expectedPage, expectedRPP, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(tasks, nil)
+		_, _ = NewTaskService(r, soleOwner{}).FetchFromTomorrow(ownerID, pagination, needle, sortExpr, nil)
+	})
+
+	t.Run("filters by tags", func(t *testing.T) {
+		var tagA, tagB = uuid.New(), uuid.New()
+		var filter = &types.TagFilter{Tags: []uuid.UUID{tagA, tagB}, MatchAll: true}
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, []string{tagA.String(), tagB.String()}, true).Return(tasks, nil)
+		res, err = NewTaskService(r, soleOwner{}).FetchFromTomorrow(ownerID, pagination, needle, sortExpr, filter)
+		assert.Equal(t, tasks, res.Payload)
+		assert.NoError(t, err)
 	})
 
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.
-			On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, unexpected)
-		res, err = NewTaskService(r).FetchFromTomorrow(ownerID, pagination, needle, sortExpr)
+		res, err = NewTaskService(r, soleOwner{}).FetchFromTomorrow(ownerID, pagination, needle, sortExpr, nil)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
 	})
@@ -547,7 +617,7 @@ func TestTaskService_FetchFromDeferred(t *testing.T) {
 		}
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), page, rpp, needle, sortExpr).Return(tasks, nil)
-		res, err = NewTaskService(r).FetchFromDeferred(ownerID, pagination, needle, sortExpr)
+		res, err = NewTaskService(r, soleOwner{}).FetchFromDeferred(ownerID, pagination, needle, sortExpr)
 		assert.Equal(t, result, res)
 		assert.NoError(t, err)
 	})
@@ -556,7 +626,7 @@ func TestTaskService_FetchFromDeferred(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchFromDeferred(uuid.Nil, pagination, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).FetchFromDeferred(uuid.Nil, pagination, needle, sortExpr)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchFromDeferred", "ownerID").Error())
 			assert.Nil(t, res)
 		})
@@ -564,7 +634,7 @@ func TestTaskService_FetchFromDeferred(t *testing.T) {
 		t.Run("\"pagination\" != nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).FetchFromDeferred(ownerID, nil, needle, sortExpr)
+			res, err = NewTaskService(r, soleOwner{}).FetchFromDeferred(ownerID, nil, needle, sortExpr)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("FetchFromDeferred", "pagination").Error())
 			assert.Nil(t, res)
 		})
@@ -575,14 +645,14 @@ func TestTaskService_FetchFromDeferred(t *testing.T) {
 			var n = blankset + needle + blankset
 			var r = mocks.NewTaskRepositoryMock()
 			r.On(routine, mock.Anything, mock.Anything, mock.Anything, needle, mock.Anything).Return(tasks, nil)
-			_, _ = NewTaskService(r).FetchFromDeferred(ownerID, pagination, n, sortExpr)
+			_, _ = NewTaskService(r, soleOwner{}).FetchFromDeferred(ownerID, pagination, n, sortExpr)
 		})
 
 		t.Run("\"sortExpr\" is trimmed", func(t *testing.T) {
 			var s = blankset + sortExpr + blankset
 			var r = mocks.NewTaskRepositoryMock()
 			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sortExpr).Return(tasks, nil)
-			_, _ = NewTaskService(r).FetchFromDeferred(ownerID, pagination, needle, s)
+			_, _ = NewTaskService(r, soleOwner{}).FetchFromDeferred(ownerID, pagination, needle, s)
 		})
 	})
 
@@ -592,7 +662,7 @@ func TestTaskService_FetchFromDeferred(t *testing.T) {
 		pagination.RPP = 0
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, expectedPage, expectedRPP, mock.Anything, mock.Anything).Return(tasks, nil)
-		_, _ = NewTaskService(r).FetchFromDeferred(ownerID, pagination, needle, sortExpr)
+		_, _ = NewTaskService(r, soleOwner{}).FetchFromDeferred(ownerID, pagination, needle, sortExpr)
 	})
 
 	t.Run("got a repository error", func(t *testing.T) {
@@ -601,12 +671,108 @@ func TestTaskService_FetchFromDeferred(t *testing.T) {
 		r.
 			On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(nil, unexpected)
-		res, err = NewTaskService(r).FetchFromDeferred(ownerID, pagination, needle, sortExpr)
+		res, err = NewTaskService(r, soleOwner{}).FetchFromDeferred(ownerID, pagination, needle, sortExpr)
 		assert.ErrorIs(t, err, unexpected)
 		assert.Nil(t, res)
 	})
 }
 
+func TestTaskService_FetchAcrossLists(t *testing.T) {
+	defer beQuiet()()
+	var (
+		ownerID        = uuid.New()
+		page     int64 = 1
+		rpp      int64 = 10
+		needle         = "x"
+		sortExpr       = "-title"
+		tasks          = []*model.Task{{UUID: uuid.New(), OwnerUUID: ownerID}}
+	)
+	var cases = []struct {
+		routine string
+		fetch   func(s TaskService) func(uuid.UUID, *types.Pagination, string, string) (*types.Result[model.Task], error)
+	}{
+		{"FetchAll", func(s TaskService) func(uuid.UUID, *types.Pagination, string, string) (*types.Result[model.Task], error) {
+			return s.FetchAll
+		}},
+		{"FetchCompleted", func(s TaskService) func(uuid.UUID, *types.Pagination, string, string) (*types.Result[model.Task], error) {
+			return s.FetchCompleted
+		}},
+		{"FetchArchived", func(s TaskService) func(uuid.UUID, *types.Pagination, string, string) (*types.Result[model.Task], error) {
+			return s.FetchArchived
+		}},
+		{"FetchTrashed", func(s TaskService) func(uuid.UUID, *types.Pagination, string, string) (*types.Result[model.Task], error) {
+			return s.FetchTrashed
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.routine, func(t *testing.T) {
+			t.Run("success", func(t *testing.T) {
+				var r = mocks.NewTaskRepositoryMock()
+				r.On(c.routine, ownerID.String(), page, rpp, needle, sortExpr).Return(tasks, nil)
+				var pagination = &types.Pagination{Page: 0, RPP: 0}
+				res, err := c.fetch(NewTaskService(r, soleOwner{}))(ownerID, pagination, blankset+needle, sortExpr+blankset)
+				assert.NoError(t, err)
+				assert.Equal(t, &types.Result[model.Task]{Page: page, RPP: rpp, Retrieved: 1, Payload: tasks}, res)
+			})
+
+			t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
+				var r = mocks.NewTaskRepositoryMock()
+				res, err := c.fetch(NewTaskService(r, soleOwner{}))(uuid.Nil, &types.Pagination{}, needle, sortExpr)
+				assert.ErrorContains(t, err, failure.NewNilParameterError(c.routine, "ownerID").Error())
+				assert.Nil(t, res)
+				r.AssertNotCalled(t, c.routine)
+			})
+
+			t.Run("got a repository error", func(t *testing.T) {
+				var r = mocks.NewTaskRepositoryMock()
+				r.On(c.routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, failure.ErrUserNoLongerExists)
+				res, err := c.fetch(NewTaskService(r, soleOwner{}))(ownerID, &types.Pagination{}, needle, sortExpr)
+				assert.ErrorIs(t, err, failure.ErrUserNoLongerExists)
+				assert.Nil(t, res)
+			})
+		})
+	}
+}
+
+func TestTaskService_Locate(t *testing.T) {
+	defer beQuiet()()
+	var userID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		res, err := NewTaskService(r, soleOwner{}).Locate(userID, taskID)
+		assert.NoError(t, err)
+		assert.Equal(t, listID, res)
+	})
+
+	t.Run("list of a stranger", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		var members = mocks.NewMemberRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", userID.String(), listID.String()).Return(nil, failure.ErrListNotFound)
+		res, err := NewTaskService(r, members).Locate(userID, taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Equal(t, uuid.Nil, res)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return("", failure.ErrTaskNotFound)
+		_, err := NewTaskService(r, soleOwner{}).Locate(userID, taskID)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+	})
+
+	t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		_, err := NewTaskService(r, soleOwner{}).Locate(userID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Locate", "taskID").Error())
+		r.AssertNotCalled(t, "Locate")
+	})
+}
+
 func TestTaskService_Update(t *testing.T) {
 	defer beQuiet()()
 	const routine = "Update"
@@ -624,7 +790,7 @@ func TestTaskService_Update(t *testing.T) {
 		}
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String(), u).Return(true, nil)
-		res, err = NewTaskService(r).Update(ownerID, listID, taskID, u)
+		res, err = NewTaskService(r, soleOwner{}).Update(ownerID, listID, taskID, u)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -635,7 +801,7 @@ func TestTaskService_Update(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Update(uuid.Nil, listID, taskID, u)
+			res, err = NewTaskService(r, soleOwner{}).Update(uuid.Nil, listID, taskID, u)
 			assert.False(t, res)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Update", "ownerID").Error())
 		})
@@ -643,7 +809,7 @@ func TestTaskService_Update(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Update(ownerID, uuid.Nil, taskID, u)
+			res, err = NewTaskService(r, soleOwner{}).Update(ownerID, uuid.Nil, taskID, u)
 			assert.False(t, res)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Update", "listID").Error())
 		})
@@ -651,7 +817,7 @@ func TestTaskService_Update(t *testing.T) {
 		t.Run("\"update\" != nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Update(ownerID, listID, taskID, nil)
+			res, err = NewTaskService(r, soleOwner{}).Update(ownerID, listID, taskID, nil)
 			assert.False(t, res)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Update", "update").Error())
 		})
@@ -665,7 +831,7 @@ func TestTaskService_Update(t *testing.T) {
 		}
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
-		res, err = NewTaskService(r).Update(ownerID, listID, taskID, u)
+		res, err = NewTaskService(r, soleOwner{}).Update(ownerID, listID, taskID, u)
 		assert.Equal(t, "Title", u.Title)
 		assert.Equal(t, "Headline", u.Headline)
 		assert.Equal(t, "Description", u.Description)
@@ -680,7 +846,7 @@ func TestTaskService_Update(t *testing.T) {
 			u.Title = strings.Repeat("x", 129)
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Update(ownerID, listID, taskID, u)
+			res, err = NewTaskService(r, soleOwner{}).Update(ownerID, listID, taskID, u)
 			assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Title", "update", 128).Error())
 			assert.False(t, res)
 			u.Title = ""
@@ -690,7 +856,7 @@ func TestTaskService_Update(t *testing.T) {
 			u.Headline = strings.Repeat("x", 65)
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Update(ownerID, listID, taskID, u)
+			res, err = NewTaskService(r, soleOwner{}).Update(ownerID, listID, taskID, u)
 			assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Headline", "update", 64).Error())
 			assert.False(t, res)
 			u.Headline = ""
@@ -700,7 +866,7 @@ func TestTaskService_Update(t *testing.T) {
 			u.Description = strings.Repeat("x", 513)
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Update(ownerID, listID, taskID, u)
+			res, err = NewTaskService(r, soleOwner{}).Update(ownerID, listID, taskID, u)
 			assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Description", "update", 512).Error())
 			assert.False(t, res)
 			u.Description = ""
@@ -712,10 +878,51 @@ func TestTaskService_Update(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Update(ownerID, listID, taskID, u)
+		res, err = NewTaskService(r, soleOwner{}).Update(ownerID, listID, taskID, u)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
+
+	t.Run("a member acts on behalf of the owner", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			u        = &transfer.TaskUpdate{Title: "Title"}
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		r.On(routine, ownerID.String(), listID.String(), taskID.String(), u).Return(true, nil)
+		res, err = NewTaskService(r, members).Update(memberID, listID, taskID, u)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a viewer cannot update", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		res, err = NewTaskService(r, members).Update(memberID, listID, taskID, &transfer.TaskUpdate{})
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		r.AssertNotCalled(t, routine)
+	})
+
+	t.Run("list not shared with the user", func(t *testing.T) {
+		var (
+			r       = mocks.NewTaskRepositoryMock()
+			members = mocks.NewMemberRepositoryMock()
+		)
+		members.On("FetchListAccess", mock.Anything, mock.Anything).Return(nil, failure.ErrListNotFound)
+		res, err = NewTaskService(r, members).Update(uuid.New(), listID, taskID, &transfer.TaskUpdate{})
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrListNotFound)
+		r.AssertNotCalled(t, routine)
+	})
 }
 
 func TestTaskService_Reorder(t *testing.T) {
@@ -730,7 +937,7 @@ func TestTaskService_Reorder(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String(), uint64(10)).Return(true, nil)
-		res, err = NewTaskService(r).Reorder(ownerID, listID, taskID, 10)
+		res, err = NewTaskService(r, soleOwner{}).Reorder(ownerID, listID, taskID, 10)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -739,7 +946,7 @@ func TestTaskService_Reorder(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Reorder(uuid.Nil, listID, taskID, 1)
+			res, err = NewTaskService(r, soleOwner{}).Reorder(uuid.Nil, listID, taskID, 1)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Reorder", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -747,7 +954,7 @@ func TestTaskService_Reorder(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Reorder(ownerID, uuid.Nil, taskID, 1)
+			res, err = NewTaskService(r, soleOwner{}).Reorder(ownerID, uuid.Nil, taskID, 1)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Reorder", "listID").Error())
 			assert.False(t, res)
 		})
@@ -755,7 +962,7 @@ func TestTaskService_Reorder(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Reorder(ownerID, listID, uuid.Nil, 1)
+			res, err = NewTaskService(r, soleOwner{}).Reorder(ownerID, listID, uuid.Nil, 1)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Reorder", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -765,7 +972,7 @@ func TestTaskService_Reorder(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Reorder(ownerID, listID, taskID, 1)
+		res, err = NewTaskService(r, soleOwner{}).Reorder(ownerID, listID, taskID, 1)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
@@ -784,7 +991,7 @@ func TestTaskService_SetReminder(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String(), tm).Return(true, nil)
-		res, err = NewTaskService(r).SetReminder(ownerID, listID, taskID, tm)
+		res, err = NewTaskService(r, soleOwner{}).SetReminder(ownerID, listID, taskID, tm)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -793,7 +1000,7 @@ func TestTaskService_SetReminder(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).SetReminder(uuid.Nil, listID, taskID, tm)
+			res, err = NewTaskService(r, soleOwner{}).SetReminder(uuid.Nil, listID, taskID, tm)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("SetReminder", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -801,7 +1008,7 @@ func TestTaskService_SetReminder(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).SetReminder(ownerID, uuid.Nil, taskID, tm)
+			res, err = NewTaskService(r, soleOwner{}).SetReminder(ownerID, uuid.Nil, taskID, tm)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("SetReminder", "listID").Error())
 			assert.False(t, res)
 		})
@@ -809,7 +1016,7 @@ func TestTaskService_SetReminder(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).SetReminder(ownerID, listID, uuid.Nil, tm)
+			res, err = NewTaskService(r, soleOwner{}).SetReminder(ownerID, listID, uuid.Nil, tm)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("SetReminder", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -819,7 +1026,7 @@ func TestTaskService_SetReminder(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).SetReminder(ownerID, listID, taskID, tm)
+		res, err = NewTaskService(r, soleOwner{}).SetReminder(ownerID, listID, taskID, tm)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
@@ -839,7 +1046,7 @@ func TestTaskService_SetPriority(t *testing.T) {
 		t.Run("success", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.On(routine, ownerID.String(), listID.String(), taskID.String(), p).Return(true, nil)
-			res, err = NewTaskService(r).SetPriority(ownerID, listID, taskID, p)
+			res, err = NewTaskService(r, soleOwner{}).SetPriority(ownerID, listID, taskID, p)
 			assert.True(t, res)
 			assert.NoError(t, err)
 		})
@@ -848,7 +1055,7 @@ func TestTaskService_SetPriority(t *testing.T) {
 			t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 				var r = mocks.NewTaskRepositoryMock()
 				r.AssertNotCalled(t, routine)
-				res, err = NewTaskService(r).SetPriority(uuid.Nil, listID, taskID, p)
+				res, err = NewTaskService(r, soleOwner{}).SetPriority(uuid.Nil, listID, taskID, p)
 				assert.ErrorContains(t, err, failure.NewNilParameterError("SetPriority", "ownerID").Error())
 				assert.False(t, res)
 			})
@@ -856,7 +1063,7 @@ func TestTaskService_SetPriority(t *testing.T) {
 			t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 				var r = mocks.NewTaskRepositoryMock()
 				r.AssertNotCalled(t, routine)
-				res, err = NewTaskService(r).SetPriority(ownerID, uuid.Nil, taskID, p)
+				res, err = NewTaskService(r, soleOwner{}).SetPriority(ownerID, uuid.Nil, taskID, p)
 				assert.ErrorContains(t, err, failure.NewNilParameterError("SetPriority", "listID").Error())
 				assert.False(t, res)
 			})
@@ -864,7 +1071,7 @@ func TestTaskService_SetPriority(t *testing.T) {
 			t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 				var r = mocks.NewTaskRepositoryMock()
 				r.AssertNotCalled(t, routine)
-				res, err = NewTaskService(r).SetPriority(ownerID, listID, uuid.Nil, p)
+				res, err = NewTaskService(r, soleOwner{}).SetPriority(ownerID, listID, uuid.Nil, p)
 				assert.ErrorContains(t, err, failure.NewNilParameterError("SetPriority", "taskID").Error())
 				assert.False(t, res)
 			})
@@ -874,7 +1081,7 @@ func TestTaskService_SetPriority(t *testing.T) {
 			var unexpected = errors.New("unexpected error")
 			var r = mocks.NewTaskRepositoryMock()
 			r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-			res, err = NewTaskService(r).SetPriority(ownerID, listID, taskID, p)
+			res, err = NewTaskService(r, soleOwner{}).SetPriority(ownerID, listID, taskID, p)
 			assert.ErrorIs(t, err, unexpected)
 			assert.False(t, res)
 		})
@@ -896,7 +1103,7 @@ func TestTaskService_SetDueDate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String(), tm).Return(true, nil)
-		res, err = NewTaskService(r).SetDueDate(ownerID, listID, taskID, tm)
+		res, err = NewTaskService(r, soleOwner{}).SetDueDate(ownerID, listID, taskID, tm)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -905,7 +1112,7 @@ func TestTaskService_SetDueDate(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).SetDueDate(uuid.Nil, listID, taskID, tm)
+			res, err = NewTaskService(r, soleOwner{}).SetDueDate(uuid.Nil, listID, taskID, tm)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("SetDueDate", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -913,7 +1120,7 @@ func TestTaskService_SetDueDate(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).SetDueDate(ownerID, uuid.Nil, taskID, tm)
+			res, err = NewTaskService(r, soleOwner{}).SetDueDate(ownerID, uuid.Nil, taskID, tm)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("SetDueDate", "listID").Error())
 			assert.False(t, res)
 		})
@@ -921,7 +1128,7 @@ func TestTaskService_SetDueDate(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).SetDueDate(ownerID, listID, uuid.Nil, tm)
+			res, err = NewTaskService(r, soleOwner{}).SetDueDate(ownerID, listID, uuid.Nil, tm)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("SetDueDate", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -931,12 +1138,161 @@ func TestTaskService_SetDueDate(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).SetDueDate(ownerID, listID, taskID, tm)
+		res, err = NewTaskService(r, soleOwner{}).SetDueDate(ownerID, listID, taskID, tm)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
 }
 
+func TestTaskService_SetRecurrence(t *testing.T) {
+	defer beQuiet()()
+	const routine = "SetRecurrence"
+	var (
+		ownerID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		dueDate                 = time.Now()
+		res                     bool
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On("FetchByID", ownerID.String(), listID.String(), taskID.String()).Return(&model.Task{DueDate: &dueDate}, nil)
+		r.On(routine, ownerID.String(), listID.String(), taskID.String(),
+			&types.RecurrenceRule{Frequency: types.FrequencyWeekly, Interval: 1, ByDay: []types.RecurrenceWeekday{{Day: time.Monday}}}).
+			Return(true, nil)
+		res, err = NewTaskService(r, soleOwner{}).SetRecurrence(ownerID, listID, taskID, "FREQ=WEEKLY;BYDAY=MO")
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTaskService(r, soleOwner{}).SetRecurrence(ownerID, listID, taskID, "FREQ=YEARLY")
+		assert.ErrorContains(t, err, "FREQ must be one of DAILY, WEEKLY or MONTHLY")
+		assert.False(t, res)
+	})
+
+	t.Run("rule is too long", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTaskService(r, soleOwner{}).SetRecurrence(ownerID, listID, taskID, strings.Repeat("x", 1+1<<8))
+		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("rule", "recurrence", 1<<8).Error())
+		assert.False(t, res)
+	})
+
+	t.Run("the task has no due date", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything).Return(&model.Task{}, nil)
+		res, err = NewTaskService(r, soleOwner{}).SetRecurrence(ownerID, listID, taskID, "FREQ=DAILY")
+		assert.ErrorContains(t, err, "the task must have a due date to recur")
+		assert.False(t, res)
+		r.AssertNotCalled(t, routine)
+	})
+
+	t.Run("parameters are not uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTaskService(r, soleOwner{}).SetRecurrence(uuid.Nil, listID, taskID, "FREQ=DAILY")
+		assert.ErrorContains(t, err, failure.NewNilParameterError("SetRecurrence", "ownerID").Error())
+		res, err = NewTaskService(r, soleOwner{}).SetRecurrence(ownerID, uuid.Nil, taskID, "FREQ=DAILY")
+		assert.ErrorContains(t, err, failure.NewNilParameterError("SetRecurrence", "listID").Error())
+		res, err = NewTaskService(r, soleOwner{}).SetRecurrence(ownerID, listID, uuid.Nil, "FREQ=DAILY")
+		assert.ErrorContains(t, err, failure.NewNilParameterError("SetRecurrence", "taskID").Error())
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything).Return(nil, failure.ErrTaskNotFound)
+		res, err = NewTaskService(r, soleOwner{}).SetRecurrence(ownerID, listID, taskID, "FREQ=DAILY")
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestTaskService_RemoveRecurrence(t *testing.T) {
+	defer beQuiet()()
+	const routine = "SetRecurrence"
+	var (
+		ownerID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		res                     bool
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, ownerID.String(), listID.String(), taskID.String(), (*types.RecurrenceRule)(nil)).Return(true, nil)
+		res, err = NewTaskService(r, soleOwner{}).RemoveRecurrence(ownerID, listID, taskID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("parameters are not uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewTaskService(r, soleOwner{}).RemoveRecurrence(uuid.Nil, listID, taskID)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("RemoveRecurrence", "ownerID").Error())
+		res, err = NewTaskService(r, soleOwner{}).RemoveRecurrence(ownerID, uuid.Nil, taskID)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("RemoveRecurrence", "listID").Error())
+		res, err = NewTaskService(r, soleOwner{}).RemoveRecurrence(ownerID, listID, uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("RemoveRecurrence", "taskID").Error())
+	})
+}
+
+func TestTaskService_Occurrences(t *testing.T) {
+	defer beQuiet()()
+	const routine = "FetchByID"
+	var (
+		ownerID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		dueDate                 = time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+		rule, _                 = types.ParseRecurrenceRule("FREQ=DAILY;INTERVAL=3")
+		res                     []time.Time
+		err                     error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, ownerID.String(), listID.String(), taskID.String()).
+			Return(&model.Task{DueDate: &dueDate, Recurrence: rule}, nil)
+		res, err = NewTaskService(r, soleOwner{}).Occurrences(ownerID, listID, taskID, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{dueDate, dueDate.AddDate(0, 0, 3)}, res)
+	})
+
+	t.Run("no more than MaxOccurrences", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).
+			Return(&model.Task{DueDate: &dueDate, Recurrence: rule}, nil)
+		res, err = NewTaskService(r, soleOwner{}).Occurrences(ownerID, listID, taskID, MaxOccurrences+1)
+		assert.NoError(t, err)
+		assert.Len(t, res, MaxOccurrences)
+	})
+
+	t.Run("the task does not recur", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(&model.Task{DueDate: &dueDate}, nil)
+		res, err = NewTaskService(r, soleOwner{}).Occurrences(ownerID, listID, taskID, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{dueDate}, res)
+	})
+
+	t.Run("the task has no due date", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(&model.Task{Recurrence: rule}, nil)
+		res, err = NewTaskService(r, soleOwner{}).Occurrences(ownerID, listID, taskID, 10)
+		assert.NoError(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(nil, failure.ErrTaskNotFound)
+		res, err = NewTaskService(r, soleOwner{}).Occurrences(ownerID, listID, taskID, 10)
+		assert.ErrorIs(t, err, failure.ErrTaskNotFound)
+		assert.Nil(t, res)
+	})
+}
+
 func TestTaskService_Complete(t *testing.T) {
 	defer beQuiet()()
 	const routine = "Complete"
@@ -949,16 +1305,105 @@ func TestTaskService_Complete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Complete(ownerID, listID, taskID)
+		r.On("FetchByID", ownerID.String(), listID.String(), taskID.String()).Return(&model.Task{UUID: taskID}, nil)
+		res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, listID, taskID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+		r.AssertNotCalled(t, "SaveOccurrence")
+	})
+
+	t.Run("makes the next occurrence of a recurring task", func(t *testing.T) {
+		var (
+			r        = mocks.NewTaskRepositoryMock()
+			rule, _  = types.ParseRecurrenceRule("FREQ=WEEKLY;BYDAY=MO,FR;COUNT=3")
+			dueDate  = time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+			remindAt = dueDate.Add(-30 * time.Minute)
+			nextDue  = time.Date(2024, time.January, 5, 9, 0, 0, 0, time.UTC)
+			nextRem  = nextDue.Add(-30 * time.Minute)
+			task     = &model.Task{
+				UUID:       taskID,
+				OwnerUUID:  ownerID,
+				ListUUID:   listID,
+				DueDate:    &dueDate,
+				RemindAt:   &remindAt,
+				Recurrence: rule,
+			}
+		)
+		r.On(routine, ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
+		r.On("FetchByID", ownerID.String(), listID.String(), taskID.String()).Return(task, nil)
+		r.On("SaveOccurrence", ownerID.String(), listID.String(), taskID.String(), nextDue, &nextRem,
+			&types.RecurrenceRule{Frequency: rule.Frequency, Interval: 1, ByDay: rule.ByDay, Count: 2}).
+			Return(uuid.NewString(), nil)
+		res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, listID, taskID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, task.Recurrence.Count)
+	})
+
+	t.Run("the series ended", func(t *testing.T) {
+		var (
+			r       = mocks.NewTaskRepositoryMock()
+			rule, _ = types.ParseRecurrenceRule("FREQ=DAILY;COUNT=1")
+			dueDate = time.Now()
+		)
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything).
+			Return(&model.Task{UUID: taskID, DueDate: &dueDate, Recurrence: rule}, nil)
+		res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, listID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
+		r.AssertNotCalled(t, "SaveOccurrence")
+	})
+
+	t.Run("the task was already completed", func(t *testing.T) {
+		var r = mocks.NewTaskRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, listID, taskID)
+		assert.False(t, res)
+		assert.NoError(t, err)
+		r.AssertNotCalled(t, "FetchByID")
+		r.AssertNotCalled(t, "SaveOccurrence")
+	})
+
+	t.Run("could not make the next occurrence", func(t *testing.T) {
+		var (
+			r          = mocks.NewTaskRepositoryMock()
+			rule, _    = types.ParseRecurrenceRule("FREQ=DAILY")
+			dueDate    = time.Now()
+			unexpected = errors.New("unexpected error")
+		)
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything).
+			Return(&model.Task{UUID: taskID, DueDate: &dueDate, Recurrence: rule}, nil)
+		r.On("SaveOccurrence", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return("", unexpected)
+		r.On("Resume", ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
+		res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, listID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, unexpected)
+		r.AssertCalled(t, "Resume", ownerID.String(), listID.String(), taskID.String())
+	})
+
+	t.Run("could not read the completed task", func(t *testing.T) {
+		var (
+			r          = mocks.NewTaskRepositoryMock()
+			unexpected = errors.New("unexpected error")
+		)
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+		r.On("FetchByID", mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
+		r.On("Resume", ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
+		res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, listID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, unexpected)
+		r.AssertCalled(t, "Resume", ownerID.String(), listID.String(), taskID.String())
+		r.AssertNotCalled(t, "SaveOccurrence")
 	})
 
 	t.Run("parameters are not uuid.Nil", func(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Complete(uuid.Nil, listID, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Complete(uuid.Nil, listID, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Complete", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -966,7 +1411,7 @@ func TestTaskService_Complete(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Complete(ownerID, uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Complete", "listID").Error())
 			assert.False(t, res)
 		})
@@ -974,7 +1419,7 @@ func TestTaskService_Complete(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Complete(ownerID, listID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, listID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Complete", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -984,7 +1429,7 @@ func TestTaskService_Complete(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Complete(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Complete(ownerID, listID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
@@ -1002,7 +1447,7 @@ func TestTaskService_Resume(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Resume(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Resume(ownerID, listID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1011,7 +1456,7 @@ func TestTaskService_Resume(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Resume(uuid.Nil, listID, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Resume(uuid.Nil, listID, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Resume", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1019,7 +1464,7 @@ func TestTaskService_Resume(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Resume(ownerID, uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Resume(ownerID, uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Resume", "listID").Error())
 			assert.False(t, res)
 		})
@@ -1027,7 +1472,7 @@ func TestTaskService_Resume(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Resume(ownerID, listID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Resume(ownerID, listID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Resume", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1037,7 +1482,7 @@ func TestTaskService_Resume(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Resume(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Resume(ownerID, listID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
@@ -1055,7 +1500,7 @@ func TestTaskService_Pin(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Pin(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Pin(ownerID, listID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1064,7 +1509,7 @@ func TestTaskService_Pin(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Pin(uuid.Nil, listID, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Pin(uuid.Nil, listID, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Pin", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1072,7 +1517,7 @@ func TestTaskService_Pin(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Pin(ownerID, uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Pin(ownerID, uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Pin", "listID").Error())
 			assert.False(t, res)
 		})
@@ -1080,7 +1525,7 @@ func TestTaskService_Pin(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Pin(ownerID, listID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Pin(ownerID, listID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Pin", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1090,7 +1535,7 @@ func TestTaskService_Pin(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Pin(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Pin(ownerID, listID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
@@ -1108,7 +1553,7 @@ func TestTaskService_Unpin(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Unpin(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Unpin(ownerID, listID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1117,7 +1562,7 @@ func TestTaskService_Unpin(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Unpin(uuid.Nil, listID, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Unpin(uuid.Nil, listID, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Unpin", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1125,7 +1570,7 @@ func TestTaskService_Unpin(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Unpin(ownerID, uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Unpin(ownerID, uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Unpin", "listID").Error())
 			assert.False(t, res)
 		})
@@ -1133,7 +1578,7 @@ func TestTaskService_Unpin(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Unpin(ownerID, listID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Unpin(ownerID, listID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Unpin", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1143,7 +1588,7 @@ func TestTaskService_Unpin(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Unpin(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Unpin(ownerID, listID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
@@ -1153,15 +1598,16 @@ func TestTaskService_Move(t *testing.T) {
 	defer beQuiet()()
 	const routine = "Move"
 	var (
-		ownerID, taskID, targetListID = uuid.New(), uuid.New(), uuid.New()
-		res                           bool
-		err                           error
+		ownerID, listID, taskID, targetListID = uuid.New(), uuid.New(), uuid.New(), uuid.New()
+		res                                   bool
+		err                                   error
 	)
 
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, ownerID.String(), taskID.String(), targetListID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Move(ownerID, taskID, targetListID)
+		res, err = NewTaskService(r, soleOwner{}).Move(ownerID, taskID, targetListID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1170,7 +1616,7 @@ func TestTaskService_Move(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Move(uuid.Nil, taskID, targetListID)
+			res, err = NewTaskService(r, soleOwner{}).Move(uuid.Nil, taskID, targetListID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Move", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1178,7 +1624,7 @@ func TestTaskService_Move(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Move(ownerID, uuid.Nil, targetListID)
+			res, err = NewTaskService(r, soleOwner{}).Move(ownerID, uuid.Nil, targetListID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Move", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1186,7 +1632,7 @@ func TestTaskService_Move(t *testing.T) {
 		t.Run("\"targetListID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Move(ownerID, taskID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Move(ownerID, taskID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Move", "targetListID").Error())
 			assert.False(t, res)
 		})
@@ -1195,26 +1641,79 @@ func TestTaskService_Move(t *testing.T) {
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Move(ownerID, taskID, targetListID)
+		res, err = NewTaskService(r, soleOwner{}).Move(ownerID, taskID, targetListID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
+
+	t.Run("an editor moves between lists of the owner", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		members.On("FetchListAccess", memberID.String(), targetListID.String()).
+			Return(&model.ListAccess{ListUUID: targetListID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		r.On(routine, ownerID.String(), taskID.String(), targetListID.String()).Return(true, nil)
+		res, err = NewTaskService(r, members).Move(memberID, taskID, targetListID)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a viewer of the target list cannot move", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		members.On("FetchListAccess", memberID.String(), targetListID.String()).
+			Return(&model.ListAccess{ListUUID: targetListID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		res, err = NewTaskService(r, members).Move(memberID, taskID, targetListID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		r.AssertNotCalled(t, routine)
+	})
+
+	t.Run("lists of different owners", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		members.On("FetchListAccess", memberID.String(), targetListID.String()).
+			Return(&model.ListAccess{ListUUID: targetListID, OwnerUUID: memberID, Role: types.MemberRoleOwner}, nil)
+		res, err = NewTaskService(r, members).Move(memberID, taskID, targetListID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrCrossOwnerMove)
+		r.AssertNotCalled(t, routine)
+	})
 }
 
 func TestTaskService_Today(t *testing.T) {
 	defer beQuiet()()
 	const routine = "Today"
 	var (
-		ownerID, taskID = uuid.New(), uuid.New()
-		res             bool
-		err             error
+		ownerID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		res                     bool
+		err                     error
 	)
 
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, ownerID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Today(ownerID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Today(ownerID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1223,7 +1722,7 @@ func TestTaskService_Today(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Today(uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Today(uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Today", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1231,7 +1730,7 @@ func TestTaskService_Today(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Today(ownerID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Today(ownerID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Today", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1240,26 +1739,58 @@ func TestTaskService_Today(t *testing.T) {
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Today(ownerID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Today(ownerID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
+
+	t.Run("an editor cannot take a task out of a shared list", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		res, err = NewTaskService(r, members).Today(memberID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrCrossOwnerMove)
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a viewer cannot act", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		res, err = NewTaskService(r, members).Today(memberID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
 }
 
 func TestTaskService_Tomorrow(t *testing.T) {
 	defer beQuiet()()
 	const routine = "Tomorrow"
 	var (
-		ownerID, taskID = uuid.New(), uuid.New()
-		res             bool
-		err             error
+		ownerID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		res                     bool
+		err                     error
 	)
 
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, ownerID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Tomorrow(ownerID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Tomorrow(ownerID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1268,7 +1799,7 @@ func TestTaskService_Tomorrow(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Tomorrow(uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Tomorrow(uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Tomorrow", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1276,7 +1807,7 @@ func TestTaskService_Tomorrow(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Tomorrow(ownerID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Tomorrow(ownerID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Tomorrow", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1285,26 +1816,58 @@ func TestTaskService_Tomorrow(t *testing.T) {
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Tomorrow(ownerID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Tomorrow(ownerID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
+
+	t.Run("an editor cannot take a task out of a shared list", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		res, err = NewTaskService(r, members).Tomorrow(memberID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrCrossOwnerMove)
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a viewer cannot act", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		res, err = NewTaskService(r, members).Tomorrow(memberID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
 }
 
 func TestTaskService_Defer(t *testing.T) {
 	defer beQuiet()()
 	const routine = "Defer"
 	var (
-		ownerID, taskID = uuid.New(), uuid.New()
-		res             bool
-		err             error
+		ownerID, listID, taskID = uuid.New(), uuid.New(), uuid.New()
+		res                     bool
+		err                     error
 	)
 
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, ownerID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Defer(ownerID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Defer(ownerID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1313,7 +1876,7 @@ func TestTaskService_Defer(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Defer(uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Defer(uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Defer", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1321,7 +1884,7 @@ func TestTaskService_Defer(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Defer(ownerID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Defer(ownerID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Defer", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1330,11 +1893,42 @@ func TestTaskService_Defer(t *testing.T) {
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
 		r.On(routine, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Defer(ownerID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Defer(ownerID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
+
+	t.Run("an editor cannot take a task out of a shared list", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleEditor}, nil)
+		res, err = NewTaskService(r, members).Defer(memberID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrCrossOwnerMove)
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a viewer cannot act", func(t *testing.T) {
+		var (
+			memberID = uuid.New()
+			r        = mocks.NewTaskRepositoryMock()
+			members  = mocks.NewMemberRepositoryMock()
+		)
+		r.On("Locate", taskID.String()).Return(listID.String(), nil)
+		members.On("FetchListAccess", memberID.String(), listID.String()).
+			Return(&model.ListAccess{ListUUID: listID, OwnerUUID: ownerID, Role: types.MemberRoleViewer}, nil)
+		res, err = NewTaskService(r, members).Defer(memberID, taskID)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrInsufficientRole)
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
 }
 
 func TestTaskService_Trash(t *testing.T) {
@@ -1349,7 +1943,7 @@ func TestTaskService_Trash(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).Trash(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Trash(ownerID, listID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1358,7 +1952,7 @@ func TestTaskService_Trash(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Trash(uuid.Nil, listID, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Trash(uuid.Nil, listID, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Trash", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1366,7 +1960,7 @@ func TestTaskService_Trash(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Trash(ownerID, uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).Trash(ownerID, uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Trash", "listID").Error())
 			assert.False(t, res)
 		})
@@ -1374,7 +1968,7 @@ func TestTaskService_Trash(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).Trash(ownerID, listID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).Trash(ownerID, listID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Trash", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1384,7 +1978,7 @@ func TestTaskService_Trash(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).Trash(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).Trash(ownerID, listID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
@@ -1402,7 +1996,7 @@ func TestTaskService_RestoreFromTrash(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String()).Return(true, nil)
-		res, err = NewTaskService(r).RestoreFromTrash(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).RestoreFromTrash(ownerID, listID, taskID)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -1411,7 +2005,7 @@ func TestTaskService_RestoreFromTrash(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).RestoreFromTrash(uuid.Nil, listID, taskID)
+			res, err = NewTaskService(r, soleOwner{}).RestoreFromTrash(uuid.Nil, listID, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("RestoreFromTrash", "ownerID").Error())
 			assert.False(t, res)
 		})
@@ -1419,7 +2013,7 @@ func TestTaskService_RestoreFromTrash(t *testing.T) {
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).RestoreFromTrash(ownerID, uuid.Nil, taskID)
+			res, err = NewTaskService(r, soleOwner{}).RestoreFromTrash(ownerID, uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("RestoreFromTrash", "listID").Error())
 			assert.False(t, res)
 		})
@@ -1427,7 +2021,7 @@ func TestTaskService_RestoreFromTrash(t *testing.T) {
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			res, err = NewTaskService(r).RestoreFromTrash(ownerID, listID, uuid.Nil)
+			res, err = NewTaskService(r, soleOwner{}).RestoreFromTrash(ownerID, listID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("RestoreFromTrash", "taskID").Error())
 			assert.False(t, res)
 		})
@@ -1437,7 +2031,7 @@ func TestTaskService_RestoreFromTrash(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(false, unexpected)
-		res, err = NewTaskService(r).RestoreFromTrash(ownerID, listID, taskID)
+		res, err = NewTaskService(r, soleOwner{}).RestoreFromTrash(ownerID, listID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 		assert.False(t, res)
 	})
@@ -1454,7 +2048,7 @@ func TestTaskService_Delete(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, ownerID.String(), listID.String(), taskID.String()).Return(nil)
-		err = NewTaskService(r).Delete(ownerID, listID, taskID)
+		err = NewTaskService(r, soleOwner{}).Delete(ownerID, listID, taskID)
 		assert.NoError(t, err)
 	})
 
@@ -1462,21 +2056,21 @@ func TestTaskService_Delete(t *testing.T) {
 		t.Run("\"ownerID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			err = NewTaskService(r).Delete(uuid.Nil, listID, taskID)
+			err = NewTaskService(r, soleOwner{}).Delete(uuid.Nil, listID, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Delete", "ownerID").Error())
 		})
 
 		t.Run("\"listID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			err = NewTaskService(r).Delete(ownerID, uuid.Nil, taskID)
+			err = NewTaskService(r, soleOwner{}).Delete(ownerID, uuid.Nil, taskID)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Delete", "listID").Error())
 		})
 
 		t.Run("\"taskID\" != uuid.Nil", func(t *testing.T) {
 			var r = mocks.NewTaskRepositoryMock()
 			r.AssertNotCalled(t, routine)
-			err = NewTaskService(r).Delete(ownerID, listID, uuid.Nil)
+			err = NewTaskService(r, soleOwner{}).Delete(ownerID, listID, uuid.Nil)
 			assert.ErrorContains(t, err, failure.NewNilParameterError("Delete", "taskID").Error())
 		})
 	})
@@ -1485,7 +2079,7 @@ func TestTaskService_Delete(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewTaskRepositoryMock()
 		r.On(routine, mock.Anything, mock.Anything, mock.Anything).Return(unexpected)
-		err = NewTaskService(r).Delete(ownerID, listID, taskID)
+		err = NewTaskService(r, soleOwner{}).Delete(ownerID, listID, taskID)
 		assert.ErrorIs(t, err, unexpected)
 	})
 }
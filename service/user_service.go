@@ -3,14 +3,18 @@ package service
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"noda/data/model"
 	"noda/data/transfer"
 	"noda/data/types"
 	"noda/failure"
+	"noda/global"
+	"noda/notify"
 	"noda/repository"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/google/uuid"
@@ -22,27 +26,37 @@ type UserService interface {
 	FetchByID(id uuid.UUID) (user *transfer.User, err error)
 	FetchByEmail(email string) (user *transfer.User, err error)
 	FetchRawUserByEmail(email string) (user *model.User, err error)
+	AssertActive(id uuid.UUID) (role types.Role, err error)
 	Fetch(pagination *types.Pagination, needle, sortExpr string) (result *types.Result[transfer.User], err error)
 	FetchBlocked(pagination *types.Pagination, needle, sortExpr string) (result *types.Result[transfer.User], err error)
 	FetchSettings(userID uuid.UUID, pagination *types.Pagination, needle, sortExpr string) (result *types.Result[transfer.UserSetting], err error)
 	FetchOneSetting(userID uuid.UUID, settingKey string) (setting *transfer.UserSetting, err error)
 	Search(pagination *types.Pagination, needle, sortExpr string) (users *types.Result[transfer.User], err error)
 	Update(id uuid.UUID, update *transfer.UserUpdate) (ok bool, err error)
+	ChangePassword(id uuid.UUID, change *transfer.PasswordChange) (ok bool, err error)
+	SetPassword(id uuid.UUID, password string) (ok bool, err error)
 	UpdateUserSetting(userID uuid.UUID, settingKey string, update *transfer.UserSettingUpdate) (ok bool, err error)
-	Block(id uuid.UUID) (ok bool, err error)
+	Block(id uuid.UUID, block *transfer.UserBlock) (ok bool, err error)
 	Unblock(id uuid.UUID) (ok bool, err error)
 	PromoteToAdmin(id uuid.UUID) (ok bool, err error)
 	DegradeToUser(id uuid.UUID) (ok bool, err error)
 	RemoveHardly(id uuid.UUID) error
 	RemoveSoftly(id uuid.UUID) error
+	Restore(id uuid.UUID) (ok bool, err error)
+	FetchDeleted(pagination *types.Pagination, needle, sortExpr string) (result *types.Result[transfer.DeletedUser], err error)
+	PurgeDeleted() (purged int64, err error)
 }
 
 type userService struct {
-	r repository.UserRepository
+	r        repository.UserRepository
+	statuses *userStatusCache
 }
 
 func NewUserService(repository repository.UserRepository) UserService {
-	return &userService{repository}
+	return &userService{
+		r:        repository,
+		statuses: newUserStatusCache(),
+	}
 }
 
 func (s *userService) Save(creation *transfer.UserCreation) (insertedID uuid.UUID, err error) {
@@ -162,10 +176,131 @@ func (s *userService) Update(userID uuid.UUID, update *transfer.UserUpdate) (ok
 	return s.r.Update(userID.String(), update)
 }
 
+// ChangePassword sets a new password for the user after checking the current
+// one.
+func (s *userService) ChangePassword(userID uuid.UUID, change *transfer.PasswordChange) (ok bool, err error) {
+	switch {
+	case uuid.Nil == userID:
+		err = failure.NewNilParameterError("ChangePassword", "userID")
+		log.Println(err)
+		return false, err
+	case nil == change:
+		err = failure.NewNilParameterError("ChangePassword", "change")
+		log.Println(err)
+		return false, err
+	}
+	doTrim(&change.OldPassword, &change.NewPassword)
+	switch {
+	case 72 < len(change.OldPassword):
+		return false, failure.ErrTooLong.Clone().FormatDetails("OldPassword", "password change", 72)
+	case 72 < len(change.NewPassword):
+		return false, failure.ErrTooLong.Clone().FormatDetails("NewPassword", "password change", 72)
+	}
+	user, err := s.r.FetchByID(userID.String())
+	if nil != err {
+		return false, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(change.OldPassword)); err != nil {
+		switch {
+		default:
+			log.Println(err)
+			return false, err
+		case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+			return false, failure.ErrIncorrectPassword.Clone().SetMessage("Password change refused.")
+		}
+	}
+	if change.OldPassword == change.NewPassword {
+		return false, failure.ErrBadRequest.Clone().SetDetails("The new password must be different from the old one.")
+	}
+	return s.doSetPassword(user, change.NewPassword)
+}
+
+// SetPassword sets a new password for the user without checking the current
+// one, as when the user forgot it.
+func (s *userService) SetPassword(userID uuid.UUID, password string) (ok bool, err error) {
+	if uuid.Nil == userID {
+		err = failure.NewNilParameterError("SetPassword", "userID")
+		log.Println(err)
+		return false, err
+	}
+	doTrim(&password)
+	if 72 < len(password) {
+		return false, failure.ErrTooLong.Clone().FormatDetails("Password", "user", 72)
+	}
+	user, err := s.r.FetchByID(userID.String())
+	if nil != err {
+		return false, err
+	}
+	return s.doSetPassword(user, password)
+}
+
+func (s *userService) doSetPassword(user *model.User, password string) (ok bool, err error) {
+	if err := assertPasswordIsValid(&password, &user.Email); err != nil {
+		return false, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		switch {
+		default:
+			log.Println(err)
+			return false, err
+		case errors.Is(err, bcrypt.ErrPasswordTooLong):
+			return false, failure.ErrPasswordTooLong
+		}
+	}
+	return s.r.UpdatePassword(user.UUID.String(), string(hashedPassword))
+}
+
+// AssertActive makes sure the user still exists and is not blocked, and
+// returns its current role. Since it is meant to be called on every
+// authorized request, the status of the user is cached for a while.
+func (s *userService) AssertActive(userID uuid.UUID) (role types.Role, err error) {
+	if uuid.Nil == userID {
+		err = failure.NewNilParameterError("AssertActive", "userID")
+		log.Println(err)
+		return 0, err
+	}
+	status, generation, ok := s.statuses.get(userID)
+	if !ok {
+		status, err = s.r.FetchStatus(userID.String())
+		if nil != err {
+			return 0, err
+		}
+		s.statuses.put(userID, status, generation)
+	}
+	if status.Deleted() {
+		return 0, deletedUserError(status)
+	}
+	if status.BlockedAt(time.Now()) {
+		return 0, blockedUserError(status)
+	}
+	return status.Role, nil
+}
+
+func deletedUserError(status *model.UserStatus) *failure.Error {
+	var e = failure.ErrUserDeleted.Clone()
+	if nil != status.PurgeAt {
+		e.SetHint(fmt.Sprintf("The account can be restored until %s.", status.PurgeAt.UTC().Format(time.RFC3339)))
+	}
+	return e
+}
+
+func blockedUserError(status *model.UserStatus) *failure.Error {
+	var e = failure.ErrUserBlocked.Clone()
+	if nil != status.BlockReason && "" != *status.BlockReason {
+		e.SetDetails(fmt.Sprintf("This user account has been blocked: %s", *status.BlockReason))
+	}
+	if nil != status.BlockedUntil {
+		e.SetHint(fmt.Sprintf("The block lasts until %s.", status.BlockedUntil.UTC().Format(time.RFC3339)))
+	}
+	return e
+}
+
 func (s *userService) PromoteToAdmin(userID uuid.UUID) (ok bool, err error) {
 	if uuid.Nil == userID {
 		return false, failure.NewNilParameterError("PromoteToAdmin", "userID")
 	}
+	defer s.statuses.invalidate(userID)
 	return s.r.PromoteToAdmin(userID.String())
 }
 
@@ -173,20 +308,35 @@ func (s *userService) DegradeToUser(userID uuid.UUID) (ok bool, err error) {
 	if uuid.Nil == userID {
 		return false, failure.NewNilParameterError("DegradeToUser", "userID")
 	}
+	defer s.statuses.invalidate(userID)
 	return s.r.DegradeToUser(userID.String())
 }
 
-func (s *userService) Block(userID uuid.UUID) (ok bool, err error) {
+// Block blocks the user, for a reason and until a given time if block says
+// so. A nil block blocks the user until it is unblocked.
+func (s *userService) Block(userID uuid.UUID, block *transfer.UserBlock) (ok bool, err error) {
 	if uuid.Nil == userID {
 		return false, failure.NewNilParameterError("Block", "userID")
 	}
-	return s.r.Block(userID.String())
+	if nil == block {
+		block = new(transfer.UserBlock)
+	}
+	doTrim(&block.Reason)
+	switch {
+	case 512 < len(block.Reason):
+		return false, failure.ErrTooLong.Clone().FormatDetails("Reason", "block", 512)
+	case nil != block.Until && !block.Until.After(time.Now()):
+		return false, failure.ErrBadRequest.Clone().SetDetails("The block must last until a future date.")
+	}
+	defer s.statuses.invalidate(userID)
+	return s.r.Block(userID.String(), block.Reason, block.Until)
 }
 
 func (s *userService) Unblock(userID uuid.UUID) (ok bool, err error) {
 	if uuid.Nil == userID {
 		return false, failure.NewNilParameterError("Unblock", "userID")
 	}
+	defer s.statuses.invalidate(userID)
 	return s.r.Unblock(userID.String())
 }
 
@@ -357,6 +507,22 @@ func (s *userService) UpdateUserSetting(
 		doTrim(&v)
 		update.Value = v
 	}
+	switch settingKey {
+	case "timezone":
+		/* The daily rollover of the lists happens at the local midnight.  */
+		if _, err = time.LoadLocation(v); !yeah || "" == v || nil != err {
+			return false, failure.ErrBadRequest.Clone().SetDetails(fmt.Sprintf("Unknown time zone: %v.", update.Value))
+		}
+	case "webhook_url":
+		/* The reminders are posted to it; an empty string stops them.  */
+		if !yeah || ("" != v && nil != notify.CheckWebhookURL(v)) {
+			return false, failure.ErrBadRequest.Clone().SetDetails(fmt.Sprintf("Invalid webhook URL: %v.", update.Value))
+		}
+	case "email_reminders":
+		if _, isBool := update.Value.(bool); !isBool {
+			return false, failure.ErrBadRequest.Clone().SetDetails("The \"email_reminders\" setting must be either true or false.")
+		}
+	}
 	buf, err := json.Marshal(update.Value)
 	if err != nil {
 		log.Println(err)
@@ -369,12 +535,55 @@ func (s *userService) RemoveHardly(id uuid.UUID) error {
 	if uuid.Nil == id {
 		return failure.NewNilParameterError("RemoveHardly", "id")
 	}
+	defer s.statuses.invalidate(id)
 	return s.r.RemoveHardly(id.String())
 }
 
+// RemoveSoftly deletes the user, which can be restored within the grace
+// period configured with ACCOUNT_DELETION_GRACE_PERIOD. Once it is over, the
+// user is purged by PurgeDeleted.
 func (s *userService) RemoveSoftly(id uuid.UUID) error {
 	if uuid.Nil == id {
 		return failure.NewNilParameterError("RemoveSoftly", "id")
 	}
-	return s.r.RemoveSoftly(id.String())
+	defer s.statuses.invalidate(id)
+	return s.r.RemoveSoftly(id.String(), time.Now().Add(global.AccountDeletionGracePeriod()))
+}
+
+func (s *userService) Restore(id uuid.UUID) (ok bool, err error) {
+	if uuid.Nil == id {
+		err = failure.NewNilParameterError("Restore", "id")
+		log.Println(err)
+		return false, err
+	}
+	defer s.statuses.invalidate(id)
+	return s.r.Restore(id.String())
+}
+
+func (s *userService) FetchDeleted(
+	pagination *types.Pagination,
+	needle, sortExpr string,
+) (result *types.Result[transfer.DeletedUser], err error) {
+	if nil == pagination {
+		err = failure.NewNilParameterError("FetchDeleted", "pagination")
+		log.Println(err)
+		return nil, err
+	}
+	doTrim(&needle, &sortExpr)
+	doDefaultPagination(pagination)
+	users, err := s.r.FetchDeleted(pagination.Page, pagination.RPP, needle, sortExpr)
+	if err != nil {
+		return nil, err
+	}
+	result = &types.Result[transfer.DeletedUser]{
+		Page:      pagination.Page,
+		RPP:       pagination.RPP,
+		Retrieved: int64(len(users)),
+		Payload:   users,
+	}
+	return result, nil
+}
+
+func (s *userService) PurgeDeleted() (purged int64, err error) {
+	return s.r.PurgeDeleted()
 }
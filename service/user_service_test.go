@@ -11,9 +11,11 @@ import (
 	"noda/data/transfer"
 	"noda/data/types"
 	"noda/failure"
+	"noda/global"
 	"noda/mocks"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestUserService_Save(t *testing.T) {
@@ -788,6 +790,46 @@ func TestUserService_UpdateUserSetting(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("\"timezone\" must be a known time zone", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String(), "timezone", `"America/Managua"`).Return(true, nil)
+		res, err = NewUserService(r).UpdateUserSetting(userID, "timezone", &transfer.UserSettingUpdate{Value: blankset + "America/Managua" + blankset})
+		assert.True(t, res)
+		assert.NoError(t, err)
+		for _, value := range []any{"Mars/Olympus_Mons", 42} {
+			res, err = NewUserService(r).UpdateUserSetting(userID, "timezone", &transfer.UserSettingUpdate{Value: value})
+			assert.False(t, res)
+			assert.ErrorContains(t, err, "Unknown time zone")
+		}
+	})
+
+	t.Run("\"webhook_url\" must be a public HTTP URL or empty", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String(), "webhook_url", `"https://example.com/hook"`).Return(true, nil)
+		r.On(routine, userID.String(), "webhook_url", `""`).Return(true, nil)
+		for _, value := range []string{"https://example.com/hook", ""} {
+			res, err = NewUserService(r).UpdateUserSetting(userID, "webhook_url", &transfer.UserSettingUpdate{Value: value})
+			assert.True(t, res)
+			assert.NoError(t, err)
+		}
+		for _, value := range []any{"ftp://example.com", "example.com", true, "http://localhost:8080", "http://169.254.169.254/"} {
+			res, err = NewUserService(r).UpdateUserSetting(userID, "webhook_url", &transfer.UserSettingUpdate{Value: value})
+			assert.False(t, res)
+			assert.ErrorContains(t, err, "Invalid webhook URL")
+		}
+	})
+
+	t.Run("\"email_reminders\" must be a boolean", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String(), "email_reminders", "false").Return(true, nil)
+		res, err = NewUserService(r).UpdateUserSetting(userID, "email_reminders", &transfer.UserSettingUpdate{Value: false})
+		assert.True(t, res)
+		assert.NoError(t, err)
+		res, err = NewUserService(r).UpdateUserSetting(userID, "email_reminders", &transfer.UserSettingUpdate{Value: "no"})
+		assert.False(t, res)
+		assert.ErrorContains(t, err, "either true or false")
+	})
+
 	t.Run("50 < settingKey", func(t *testing.T) {
 		var r = mocks.NewUserRepositoryMock()
 		r.AssertNotCalled(t, routine)
@@ -826,6 +868,122 @@ func TestUserService_UpdateUserSetting(t *testing.T) {
 	})
 }
 
+func TestUserService_ChangePassword(t *testing.T) {
+	defer beQuiet()()
+	const (
+		routine     = "UpdatePassword"
+		oldPassword = "x@e8[a+*GAUsKBZ!d}>3&"
+		newPassword = "Gq7!vR2#pLk9@wZ"
+	)
+	var hash, _ = bcrypt.GenerateFromPassword([]byte(oldPassword), bcrypt.MinCost)
+	var (
+		userID = uuid.New()
+		user   = &model.User{UUID: userID, Email: "izs16833@zslsz.com", Password: string(hash)}
+		res    bool
+		err    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var change = &transfer.PasswordChange{OldPassword: blankset + oldPassword, NewPassword: newPassword + blankset}
+		var r = mocks.NewUserRepositoryMock()
+		r.On("FetchByID", userID.String()).Return(user, nil)
+		r.On(routine, userID.String(), mock.MatchedBy(func(hashed string) bool {
+			return nil == bcrypt.CompareHashAndPassword([]byte(hashed), []byte(newPassword))
+		})).Return(true, nil)
+		res, err = NewUserService(r).ChangePassword(userID, change)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("parameters cannot be nil", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		res, err = NewUserService(r).ChangePassword(uuid.Nil, &transfer.PasswordChange{})
+		assert.ErrorContains(t, err, failure.NewNilParameterError("ChangePassword", "userID").Error())
+		res, err = NewUserService(r).ChangePassword(userID, nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("ChangePassword", "change").Error())
+		assert.False(t, res)
+		r.AssertNotCalled(t, "FetchByID", mock.Anything)
+	})
+
+	t.Run("old password does not match", func(t *testing.T) {
+		var change = &transfer.PasswordChange{OldPassword: "wrong", NewPassword: newPassword}
+		var r = mocks.NewUserRepositoryMock()
+		r.On("FetchByID", userID.String()).Return(user, nil)
+		res, err = NewUserService(r).ChangePassword(userID, change)
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.ErrIncorrectPassword.Error())
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("new password must be different", func(t *testing.T) {
+		var change = &transfer.PasswordChange{OldPassword: oldPassword, NewPassword: oldPassword}
+		var r = mocks.NewUserRepositoryMock()
+		r.On("FetchByID", userID.String()).Return(user, nil)
+		res, err = NewUserService(r).ChangePassword(userID, change)
+		assert.False(t, res)
+		assert.ErrorContains(t, err, "The new password must be different from the old one.")
+	})
+
+	t.Run("new password must meet the restrictions", func(t *testing.T) {
+		var change = &transfer.PasswordChange{OldPassword: oldPassword, NewPassword: "short"}
+		var r = mocks.NewUserRepositoryMock()
+		r.On("FetchByID", userID.String()).Return(user, nil)
+		res, err = NewUserService(r).ChangePassword(userID, change)
+		assert.False(t, res)
+		var aggregate *failure.AggregateDetails
+		assert.ErrorAs(t, err, &aggregate)
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var change = &transfer.PasswordChange{OldPassword: oldPassword, NewPassword: newPassword}
+		var r = mocks.NewUserRepositoryMock()
+		r.On("FetchByID", userID.String()).Return(nil, failure.ErrUserNotFound)
+		res, err = NewUserService(r).ChangePassword(userID, change)
+		assert.False(t, res)
+		assert.ErrorIs(t, err, failure.ErrUserNotFound)
+	})
+}
+
+func TestUserService_SetPassword(t *testing.T) {
+	defer beQuiet()()
+	const (
+		routine  = "UpdatePassword"
+		password = "Gq7!vR2#pLk9@wZ"
+	)
+	var (
+		userID = uuid.New()
+		user   = &model.User{UUID: userID, Email: "izs16833@zslsz.com"}
+		res    bool
+		err    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On("FetchByID", userID.String()).Return(user, nil)
+		r.On(routine, userID.String(), mock.Anything).Return(true, nil)
+		res, err = NewUserService(r).SetPassword(userID, password)
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("parameter \"userID\" cannot be uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		res, err = NewUserService(r).SetPassword(uuid.Nil, password)
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("SetPassword", "userID").Error())
+	})
+
+	t.Run("password must meet the restrictions", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On("FetchByID", userID.String()).Return(user, nil)
+		res, err = NewUserService(r).SetPassword(userID, "izs16833")
+		assert.False(t, res)
+		assert.ErrorContains(t, err, "Password seems to be similar to email.")
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything)
+	})
+}
+
 func TestUserService_Block(t *testing.T) {
 	const routine = "Block"
 	var (
@@ -835,9 +993,18 @@ func TestUserService_Block(t *testing.T) {
 	)
 
 	t.Run("success", func(t *testing.T) {
+		var until = time.Now().Add(24 * time.Hour)
 		var r = mocks.NewUserRepositoryMock()
-		r.On(routine, userID.String()).Return(true, nil)
-		res, err = NewUserService(r).Block(userID)
+		r.On(routine, userID.String(), "spam", &until).Return(true, nil)
+		res, err = NewUserService(r).Block(userID, &transfer.UserBlock{Reason: blankset + "spam" + blankset, Until: &until})
+		assert.True(t, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("without reason nor expiry", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String(), "", (*time.Time)(nil)).Return(true, nil)
+		res, err = NewUserService(r).Block(userID, nil)
 		assert.True(t, res)
 		assert.NoError(t, err)
 	})
@@ -845,21 +1012,165 @@ func TestUserService_Block(t *testing.T) {
 	t.Run("parameter \"userID\" cannot be uuid.Nil", func(t *testing.T) {
 		var r = mocks.NewUserRepositoryMock()
 		r.AssertNotCalled(t, routine)
-		res, err = NewUserService(r).Block(uuid.Nil)
+		res, err = NewUserService(r).Block(uuid.Nil, nil)
 		assert.False(t, res)
 		assert.ErrorContains(t, err, failure.NewNilParameterError("Block", "userID").Error())
 	})
 
+	t.Run("expiry must be in the future", func(t *testing.T) {
+		var until = time.Now().Add(-time.Minute)
+		var r = mocks.NewUserRepositoryMock()
+		res, err = NewUserService(r).Block(userID, &transfer.UserBlock{Until: &until})
+		assert.False(t, res)
+		assert.ErrorContains(t, err, "The block must last until a future date.")
+		r.AssertNotCalled(t, routine, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reason is too long", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		res, err = NewUserService(r).Block(userID, &transfer.UserBlock{Reason: strings.Repeat("x", 513)})
+		assert.False(t, res)
+		assert.ErrorContains(t, err, failure.ErrTooLong.Clone().FormatDetails("Reason", "block", 512).Error())
+	})
+
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewUserRepositoryMock()
-		r.On(routine, userID.String()).Return(false, unexpected)
-		res, err = NewUserService(r).Block(userID)
+		r.On(routine, userID.String(), "", (*time.Time)(nil)).Return(false, unexpected)
+		res, err = NewUserService(r).Block(userID, nil)
 		assert.False(t, res)
 		assert.ErrorIs(t, err, unexpected)
 	})
 }
 
+func TestUserService_AssertActive(t *testing.T) {
+	defer beQuiet()()
+	const routine = "FetchStatus"
+	var (
+		userID = uuid.New()
+		role   types.Role
+		err    error
+	)
+
+	t.Run("active user and cached status", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleAdmin}, nil).Once()
+		var s = NewUserService(r)
+		role, err = s.AssertActive(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, types.RoleAdmin, role)
+		role, err = s.AssertActive(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, types.RoleAdmin, role)
+		r.AssertNumberOfCalls(t, routine, 1)
+	})
+
+	t.Run("blocked user", func(t *testing.T) {
+		var (
+			reason = "spam"
+			until  = time.Now().Add(time.Hour)
+			r      = mocks.NewUserRepositoryMock()
+		)
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser, IsBlocked: true, BlockReason: &reason, BlockedUntil: &until}, nil)
+		_, err = NewUserService(r).AssertActive(userID)
+		var e *failure.Error
+		if assert.ErrorAs(t, err, &e) {
+			assert.Equal(t, failure.ErrUserBlocked.Status(), e.Status())
+			assert.Contains(t, e.Details(), reason)
+			assert.Contains(t, e.Hint(), until.UTC().Format(time.RFC3339))
+		}
+	})
+
+	t.Run("expired block", func(t *testing.T) {
+		var (
+			until = time.Now().Add(-time.Hour)
+			r     = mocks.NewUserRepositoryMock()
+		)
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser, IsBlocked: true, BlockedUntil: &until}, nil)
+		role, err = NewUserService(r).AssertActive(userID)
+		assert.NoError(t, err)
+		assert.Equal(t, types.RoleUser, role)
+	})
+
+	t.Run("blocking invalidates the cached status", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser}, nil).Once()
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser, IsBlocked: true}, nil).Once()
+		r.On("Block", userID.String(), "", (*time.Time)(nil)).Return(true, nil)
+		var s = NewUserService(r)
+		_, err = s.AssertActive(userID)
+		assert.NoError(t, err)
+		_, err = s.Block(userID, nil)
+		assert.NoError(t, err)
+		_, err = s.AssertActive(userID)
+		assert.ErrorContains(t, err, failure.ErrUserBlocked.Details())
+	})
+
+	t.Run("does not cache a status that changed while it was looked up", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		var s = NewUserService(r)
+		r.On(routine, userID.String()).
+			Run(func(mock.Arguments) { s.(*userService).statuses.invalidate(userID) }).
+			Return(&model.UserStatus{Role: types.RoleUser}, nil).Once()
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser, IsBlocked: true}, nil).Once()
+		_, err = s.AssertActive(userID)
+		assert.NoError(t, err)
+		_, err = s.AssertActive(userID)
+		assert.ErrorContains(t, err, failure.ErrUserBlocked.Details())
+	})
+
+	t.Run("deleted user", func(t *testing.T) {
+		var (
+			deletedAt = time.Now()
+			purgeAt   = deletedAt.Add(time.Hour)
+			r         = mocks.NewUserRepositoryMock()
+		)
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser, DeletedAt: &deletedAt, PurgeAt: &purgeAt}, nil)
+		_, err = NewUserService(r).AssertActive(userID)
+		var e *failure.Error
+		if assert.ErrorAs(t, err, &e) {
+			assert.Equal(t, failure.ErrUserDeleted.Details(), e.Details())
+			assert.Contains(t, e.Hint(), purgeAt.UTC().Format(time.RFC3339))
+		}
+	})
+
+	t.Run("restoring invalidates the cached status", func(t *testing.T) {
+		var deletedAt = time.Now()
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser, DeletedAt: &deletedAt}, nil).Once()
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser}, nil).Once()
+		r.On("Restore", userID.String()).Return(true, nil)
+		var s = NewUserService(r)
+		_, err = s.AssertActive(userID)
+		assert.ErrorContains(t, err, failure.ErrUserDeleted.Details())
+		_, err = s.Restore(userID)
+		assert.NoError(t, err)
+		_, err = s.AssertActive(userID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("degrading invalidates the cached status", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleAdmin}, nil).Once()
+		r.On(routine, userID.String()).Return(&model.UserStatus{Role: types.RoleUser}, nil).Once()
+		r.On("DegradeToUser", userID.String()).Return(true, nil)
+		var s = NewUserService(r)
+		role, _ = s.AssertActive(userID)
+		assert.Equal(t, types.RoleAdmin, role)
+		_, err = s.DegradeToUser(userID)
+		assert.NoError(t, err)
+		role, _ = s.AssertActive(userID)
+		assert.Equal(t, types.RoleUser, role)
+	})
+
+	t.Run("user no longer exists", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String()).Return(nil, failure.ErrUserNotFound)
+		_, err = NewUserService(r).AssertActive(userID)
+		assert.ErrorIs(t, err, failure.ErrUserNotFound)
+	})
+}
+
 func TestUserService_Unblock(t *testing.T) {
 	const routine = "Unblock"
 	var (
@@ -1001,7 +1312,10 @@ func TestUserService_RemoveSoftly(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		var r = mocks.NewUserRepositoryMock()
-		r.On(routine, userID.String()).Return(nil)
+		var before = time.Now().Add(global.AccountDeletionGracePeriod())
+		r.On(routine, userID.String(), mock.MatchedBy(func(purgeAt time.Time) bool {
+			return !purgeAt.Before(before) && purgeAt.Before(before.Add(time.Minute))
+		})).Return(nil)
 		err = NewUserService(r).RemoveSoftly(userID)
 		assert.NoError(t, err)
 	})
@@ -1016,8 +1330,86 @@ func TestUserService_RemoveSoftly(t *testing.T) {
 	t.Run("got a repository error", func(t *testing.T) {
 		var unexpected = errors.New("unexpected error")
 		var r = mocks.NewUserRepositoryMock()
-		r.On(routine, userID.String()).Return(unexpected)
+		r.On(routine, userID.String(), mock.Anything).Return(unexpected)
 		err = NewUserService(r).RemoveSoftly(userID)
 		assert.ErrorIs(t, err, unexpected)
 	})
 }
+
+func TestUserService_Restore(t *testing.T) {
+	defer beQuiet()()
+	const routine = "Restore"
+	var (
+		userID = uuid.New()
+		res    bool
+		err    error
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String()).Return(true, nil)
+		res, err = NewUserService(r).Restore(userID)
+		assert.NoError(t, err)
+		assert.True(t, res)
+	})
+
+	t.Run("parameter \"id\" cannot be uuid.Nil", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewUserService(r).Restore(uuid.Nil)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("Restore", "id").Error())
+		assert.False(t, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, userID.String()).Return(false, failure.ErrUserNotFound)
+		res, err = NewUserService(r).Restore(userID)
+		assert.ErrorIs(t, err, failure.ErrUserNotFound)
+		assert.False(t, res)
+	})
+}
+
+func TestUserService_FetchDeleted(t *testing.T) {
+	defer beQuiet()()
+	const routine = "FetchDeleted"
+	var (
+		res        *types.Result[transfer.DeletedUser]
+		err        error
+		needle     = "user"
+		sortExpr   = "+purge_at"
+		users      = make([]*transfer.DeletedUser, 2)
+		pagination = &types.Pagination{Page: 1, RPP: 10}
+	)
+
+	t.Run("success", func(t *testing.T) {
+		var result = &types.Result[transfer.DeletedUser]{
+			Page:      pagination.Page,
+			RPP:       pagination.RPP,
+			Retrieved: int64(len(users)),
+			Payload:   users,
+		}
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, pagination.Page, pagination.RPP, needle, sortExpr).Return(users, nil)
+		res, err = NewUserService(r).FetchDeleted(pagination, blankset+needle+blankset, sortExpr)
+		assert.Equal(t, result, res)
+		assert.NoError(t, err)
+	})
+
+	t.Run("parameter \"pagination\" cannot be nil", func(t *testing.T) {
+		var r = mocks.NewUserRepositoryMock()
+		r.AssertNotCalled(t, routine)
+		res, err = NewUserService(r).FetchDeleted(nil, needle, sortExpr)
+		assert.ErrorContains(t, err, failure.NewNilParameterError("FetchDeleted", "pagination").Error())
+		assert.Nil(t, res)
+	})
+
+	t.Run("got a repository error", func(t *testing.T) {
+		var unexpected = errors.New("unexpected error")
+		var r = mocks.NewUserRepositoryMock()
+		r.On(routine, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, unexpected)
+		res, err = NewUserService(r).FetchDeleted(pagination, needle, sortExpr)
+		assert.ErrorIs(t, err, unexpected)
+		assert.Nil(t, res)
+	})
+}
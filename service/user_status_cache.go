@@ -0,0 +1,81 @@
+package service
+
+import (
+	"noda/data/model"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// userStatusCacheTTL bounds how long a status can be stale when it changes
+// through another instance of the server, which cannot invalidate the cache
+// of this one.
+const userStatusCacheTTL = 30 * time.Second
+
+type cachedUserStatus struct {
+	status  *model.UserStatus
+	expires time.Time
+}
+
+// userStatusCache keeps the recently looked up statuses of the users so that
+// authorizing a request does not query the database every time.
+//
+// A status looked up while the one of a user changes may be the old one, so
+// it is only kept if no status was invalidated since it was asked for: get
+// tells the generation of the cache, which every invalidation moves on, and
+// put leaves out the statuses of a past generation.
+type userStatusCache struct {
+	mu         sync.Mutex
+	entries    map[uuid.UUID]cachedUserStatus
+	generation uint64
+	swept      time.Time
+	now        func() time.Time
+}
+
+func newUserStatusCache() *userStatusCache {
+	return &userStatusCache{
+		entries: make(map[uuid.UUID]cachedUserStatus),
+		now:     time.Now,
+	}
+}
+
+func (c *userStatusCache) get(userID uuid.UUID) (status *model.UserStatus, generation uint64, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[userID]
+	if !ok {
+		return nil, c.generation, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, userID)
+		return nil, c.generation, false
+	}
+	return entry.status, c.generation, true
+}
+
+// put keeps the status of the user looked up after get told generation.
+func (c *userStatusCache) put(userID uuid.UUID, status *model.UserStatus, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	var now = c.now()
+	if now.Sub(c.swept) >= userStatusCacheTTL {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		c.swept = now
+	}
+	c.entries[userID] = cachedUserStatus{status: status, expires: now.Add(userStatusCacheTTL)}
+}
+
+func (c *userStatusCache) invalidate(userID uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+	c.generation++
+}
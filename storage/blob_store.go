@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrBlobNotFound is returned when there is no blob under the given key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// ErrInvalidKey is returned when a key is empty, absolute or tries to escape
+// the namespace of the store.
+var ErrInvalidKey = errors.New("invalid blob key")
+
+// BlobStore abstracts a place where files are kept. Keys are slash-separated
+// relative paths such as "owner/task/attachment".
+type BlobStore interface {
+	// Put stores everything read from content under key. If size is negative,
+	// the length of content is unknown.
+	Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error
+
+	// Get opens the blob stored under key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the blob stored under key. Deleting a missing blob is not
+	// an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// checkKey makes sure key is a clean relative path.
+func checkKey(key string) error {
+	if "" == key ||
+		strings.HasPrefix(key, "/") ||
+		strings.Contains(key, "\\") ||
+		path.Clean(key) != key ||
+		".." == key ||
+		strings.HasPrefix(key, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+type localBlobStore struct {
+	root string
+}
+
+// NewLocalBlobStore returns a BlobStore that keeps every blob as a file under
+// the directory root, which is created if it does not exist.
+func NewLocalBlobStore(root string) (BlobStore, error) {
+	root, err := filepath.Abs(root)
+	if nil != err {
+		return nil, err
+	}
+	err = os.MkdirAll(root, 0750)
+	if nil != err {
+		return nil, err
+	}
+	return &localBlobStore{root: root}, nil
+}
+
+func (s *localBlobStore) path(key string) (string, error) {
+	if err := checkKey(key); nil != err {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (s *localBlobStore) Put(ctx context.Context, key string, content io.Reader, _ int64, _ string) (err error) {
+	name, err := s.path(key)
+	if nil != err {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(name), 0750)
+	if nil != err {
+		return err
+	}
+	/* Write to a temporary file first so that a failed upload never leaves
+	   a partial blob behind.  */
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if nil != err {
+		return err
+	}
+	defer func() {
+		if nil != err {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	_, err = io.Copy(tmp, &contextReader{ctx: ctx, r: content})
+	if nil != err {
+		return err
+	}
+	err = tmp.Close()
+	if nil != err {
+		return err
+	}
+	err = os.Rename(tmp.Name(), name)
+	return err
+}
+
+func (s *localBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	name, err := s.path(key)
+	if nil != err {
+		return nil, err
+	}
+	file, err := os.Open(name)
+	if nil != err {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		log.Println(err)
+		return nil, err
+	}
+	return file, nil
+}
+
+func (s *localBlobStore) Delete(_ context.Context, key string) error {
+	name, err := s.path(key)
+	if nil != err {
+		return err
+	}
+	err = os.Remove(name)
+	if nil != err && !errors.Is(err, fs.ErrNotExist) {
+		log.Println(err)
+		return err
+	}
+	return nil
+}
+
+// contextReader stops reading from r as soon as ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); nil != err {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config holds what is needed to talk to an S3-compatible object storage.
+type S3Config struct {
+	Endpoint        string // Endpoint is the base URL of the service, e.g. "https://s3.us-east-1.amazonaws.com".
+	Region          string // Region is used to sign the requests.
+	Bucket          string // Bucket is where the blobs are kept.
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool // PathStyle addresses the bucket in the path instead of in the host name.
+}
+
+type s3BlobStore struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3BlobStore returns a BlobStore backed by an S3-compatible service. If
+// client is nil, http.DefaultClient is used.
+func NewS3BlobStore(config S3Config, client *http.Client) BlobStore {
+	if nil == client {
+		client = http.DefaultClient
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &s3BlobStore{config: config, client: client, now: time.Now}
+}
+
+// objectURL returns the location of the object stored under key.
+func (s *s3BlobStore) objectURL(key string) (*url.URL, error) {
+	endpoint, err := url.Parse(s.config.Endpoint)
+	if nil != err {
+		return nil, err
+	}
+	var escaped = endpoint.EscapedPath()
+	if s.config.PathStyle {
+		endpoint.Path += "/" + s.config.Bucket
+		escaped += "/" + awsEscape(s.config.Bucket)
+	} else {
+		endpoint.Host = s.config.Bucket + "." + endpoint.Host
+	}
+	endpoint.Path += "/" + key
+	for _, segment := range strings.Split(key, "/") {
+		escaped += "/" + awsEscape(segment)
+	}
+	endpoint.RawPath = escaped
+	return endpoint, nil
+}
+
+func (s *s3BlobStore) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if err := checkKey(key); nil != err {
+		return nil, err
+	}
+	location, err := s.objectURL(key)
+	if nil != err {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, location.String(), body)
+}
+
+func (s *s3BlobStore) do(request *http.Request) (*http.Response, error) {
+	s.sign(request, s.now())
+	response, err := s.client.Do(request)
+	if nil != err {
+		log.Println(err)
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *s3BlobStore) Put(ctx context.Context, key string, content io.Reader, size int64, contentType string) error {
+	if 0 > size {
+		/* S3 needs to know the length of the object upfront, so spool it to a
+		   temporary file instead of holding it in memory.  */
+		tmp, err := os.CreateTemp("", "noda-s3-*")
+		if nil != err {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		defer tmp.Close()
+		size, err = io.Copy(tmp, &contextReader{ctx: ctx, r: content})
+		if nil != err {
+			return err
+		}
+		_, err = tmp.Seek(0, io.SeekStart)
+		if nil != err {
+			return err
+		}
+		content = tmp
+	}
+	request, err := s.newRequest(ctx, http.MethodPut, key, io.NopCloser(content))
+	if nil != err {
+		return err
+	}
+	request.ContentLength = size
+	if 0 == size {
+		request.Body = http.NoBody
+	}
+	if "" != contentType {
+		request.Header.Set("Content-Type", contentType)
+	}
+	response, err := s.do(request)
+	if nil != err {
+		return err
+	}
+	defer response.Body.Close()
+	if http.StatusOK != response.StatusCode {
+		return unexpectedS3Response(response)
+	}
+	return nil
+}
+
+func (s *s3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	request, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if nil != err {
+		return nil, err
+	}
+	response, err := s.do(request)
+	if nil != err {
+		return nil, err
+	}
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response.Body, nil
+	case http.StatusNotFound:
+		response.Body.Close()
+		return nil, ErrBlobNotFound
+	default:
+		defer response.Body.Close()
+		return nil, unexpectedS3Response(response)
+	}
+}
+
+func (s *s3BlobStore) Delete(ctx context.Context, key string) error {
+	request, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if nil != err {
+		return err
+	}
+	response, err := s.do(request)
+	if nil != err {
+		return err
+	}
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return unexpectedS3Response(response)
+	}
+}
+
+func unexpectedS3Response(response *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1<<10))
+	var err = fmt.Errorf("object storage responded %s: %s", response.Status, strings.TrimSpace(string(body)))
+	log.Println(err)
+	return err
+}
+
+// unsignedPayload tells S3 the body is not part of the signature, which lets
+// uploads be streamed without being hashed first.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// sign adds an AWS Signature Version 4 to request. The host, the x-amz-*
+// headers and the range and content headers that are set are signed.
+func (s *s3BlobStore) sign(request *http.Request, at time.Time) {
+	var (
+		amzDate   = at.UTC().Format("20060102T150405Z")
+		shortDate = at.UTC().Format("20060102")
+		scope     = shortDate + "/" + s.config.Region + "/s3/aws4_request"
+	)
+	request.Header.Set("X-Amz-Date", amzDate)
+	if "" == request.Header.Get("X-Amz-Content-Sha256") {
+		request.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	}
+
+	var headers = map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") ||
+			"range" == name ||
+			"content-type" == name ||
+			"content-md5" == name {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	var names = make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	var signedHeaders = strings.Join(names, ";")
+
+	var canonicalRequest = strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		canonicalQuery(request.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		request.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	var stringToSign = strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	var key = hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), shortDate)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	var signature = hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", "AWS4-HMAC-SHA256 "+
+		"Credential="+s.config.AccessKeyID+"/"+scope+", "+
+		"SignedHeaders="+signedHeaders+", "+
+		"Signature="+signature)
+}
+
+func canonicalQuery(query url.Values) string {
+	var keys = make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs = make([]string, 0)
+	for _, key := range keys {
+		var values = query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes s the way SigV4 expects it: every byte except the
+// unreserved characters is encoded, spaces included.
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		var c = s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			'-' == c || '_' == c || '.' == c || '~' == c {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString("%" + strings.ToUpper(strconv.FormatInt(int64(c)|0x100, 16)[1:]))
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	var h = hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	var sum = sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,297 @@
+// Package websocket speaks the server side of the WebSocket protocol (RFC
+// 6455), as far as pushing messages to clients needs.
+//
+// Upgrade answers the opening handshake and takes over the connection of the
+// request. The messages of the client are read with ReadMessage, which also
+// answers its pings and its closing handshake; reading them is how a closed
+// connection is noticed. Extensions and subprotocols are not supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// acceptGUID is appended to the key of the client to compute the accept
+// header of the answer.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// MaxMessageSize is the size of the largest message read from a client.
+const MaxMessageSize = 64 * 1024
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// The status codes of the closing handshake.
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	ClosePolicyViolation = 1008
+	CloseMessageTooBig   = 1009
+	CloseTryAgainLater   = 1013
+)
+
+var (
+	ErrBadHandshake    = errors.New("websocket: bad handshake")
+	ErrClosed          = errors.New("websocket: connection closed")
+	ErrProtocol        = errors.New("websocket: protocol error")
+	ErrMessageTooLarge = errors.New("websocket: message too large")
+)
+
+// IsUpgrade tells whether the request asks to switch to WebSocket.
+func IsUpgrade(r *http.Request) bool {
+	return headerHas(r.Header, "Connection", "upgrade") && headerHas(r.Header, "Upgrade", "websocket")
+}
+
+func headerHas(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Accept computes the accept header that answers the key of a client.
+func Accept(key string) string {
+	var sum = sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// Upgrade answers the opening handshake of the request and takes over its
+// connection, which no longer has the deadlines of the server. Nothing is
+// written when the handshake is refused.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if http.MethodGet != r.Method || !IsUpgrade(r) {
+		return nil, fmt.Errorf("%w: the request does not ask to switch to WebSocket", ErrBadHandshake)
+	}
+	if "13" != r.Header.Get("Sec-WebSocket-Version") {
+		return nil, fmt.Errorf("%w: only version 13 of the protocol is supported", ErrBadHandshake)
+	}
+	var key = r.Header.Get("Sec-WebSocket-Key")
+	if nonce, err := base64.StdEncoding.DecodeString(key); nil != err || 16 != len(nonce) {
+		return nil, fmt.Errorf("%w: the key is not a base64 nonce of 16 bytes", ErrBadHandshake)
+	}
+	netConn, rw, err := http.NewResponseController(w).Hijack()
+	if nil != err {
+		return nil, err
+	}
+	if err = netConn.SetDeadline(time.Time{}); nil == err {
+		_, err = fmt.Fprintf(rw.Writer, "HTTP/1.1 101 Switching Protocols\r\n"+
+			"Upgrade: websocket\r\n"+
+			"Connection: Upgrade\r\n"+
+			"Sec-WebSocket-Accept: %s\r\n\r\n", Accept(key))
+	}
+	if nil == err {
+		err = rw.Writer.Flush()
+	}
+	if nil != err {
+		netConn.Close()
+		return nil, err
+	}
+	return &Conn{conn: netConn, reader: rw.Reader, writer: rw.Writer}, nil
+}
+
+// Conn is a WebSocket connection. Its writes may be made concurrently, but
+// only one goroutine may read its messages.
+type Conn struct {
+	// ReadTimeout is how long to wait for each frame of the client, pongs
+	// included; zero means forever.
+	ReadTimeout time.Duration
+	// WriteTimeout is how long each frame may take to be sent; zero means
+	// forever.
+	WriteTimeout time.Duration
+
+	conn   net.Conn
+	reader *bufio.Reader
+	mu     sync.Mutex
+	writer *bufio.Writer
+	closed bool
+}
+
+// WriteText sends a text message, which must be valid UTF-8.
+func (c *Conn) WriteText(data []byte) error {
+	return c.writeFrame(opText, data)
+}
+
+// Ping sends a ping, which the client answers with a pong.
+func (c *Conn) Ping() error {
+	return c.writeFrame(opPing, nil)
+}
+
+// Close sends a close frame with the status code and the reason, then closes
+// the connection without waiting for the answer. Closing a closed connection
+// does nothing.
+func (c *Conn) Close(code uint16, reason string) error {
+	var payload = binary.BigEndian.AppendUint16(nil, code)
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+	payload = append(payload, reason...)
+	var err = c.writeFrame(opClose, payload)
+	if errors.Is(err, ErrClosed) {
+		return nil
+	}
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	if closeErr := c.conn.Close(); nil == err {
+		err = closeErr
+	}
+	return err
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	if 0 < c.WriteTimeout {
+		c.conn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+	}
+	var header = []byte{0x80 | opcode}
+	switch length := len(payload); {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = binary.BigEndian.AppendUint16(append(header, 126), uint16(length))
+	default:
+		header = binary.BigEndian.AppendUint64(append(header, 127), uint64(length))
+	}
+	if _, err := c.writer.Write(header); nil != err {
+		return err
+	}
+	if _, err := c.writer.Write(payload); nil != err {
+		return err
+	}
+	return c.writer.Flush()
+}
+
+// ReadMessage reads the next text or binary message of the client. Pings are
+// answered and pongs are skipped on the way. When the client starts the
+// closing handshake, it is answered and ErrClosed is returned; when the client
+// breaks the protocol, the connection is closed.
+func (c *Conn) ReadMessage() (data []byte, err error) {
+	defer func() {
+		switch {
+		case errors.Is(err, ErrProtocol):
+			c.Close(CloseProtocolError, "")
+		case errors.Is(err, ErrMessageTooLarge):
+			c.Close(CloseMessageTooBig, "")
+		}
+	}()
+	var (
+		started, fin bool
+		opcode       byte
+		payload      []byte
+	)
+	for {
+		fin, opcode, payload, err = c.readFrame()
+		if nil != err {
+			return nil, err
+		}
+		switch opcode {
+		case opPing:
+			if err = c.writeFrame(opPong, payload); nil != err {
+				return nil, err
+			}
+		case opPong:
+		case opClose:
+			var code uint16 = CloseNormal
+			if 2 <= len(payload) {
+				code = binary.BigEndian.Uint16(payload)
+			}
+			c.Close(code, "")
+			return nil, ErrClosed
+		case opText, opBinary:
+			if started {
+				return nil, fmt.Errorf("%w: new message before the end of the previous one", ErrProtocol)
+			}
+			started, data = true, payload
+		case opContinuation:
+			if !started {
+				return nil, fmt.Errorf("%w: continuation without a message", ErrProtocol)
+			}
+			if len(data)+len(payload) > MaxMessageSize {
+				return nil, ErrMessageTooLarge
+			}
+			data = append(data, payload...)
+		default:
+			return nil, fmt.Errorf("%w: unknown opcode %#x", ErrProtocol, opcode)
+		}
+		if started && fin {
+			return data, nil
+		}
+	}
+}
+
+// readFrame reads a frame of the client, which must be masked.
+func (c *Conn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	if 0 < c.ReadTimeout {
+		c.conn.SetReadDeadline(time.Now().Add(c.ReadTimeout))
+	}
+	var header [2]byte
+	if _, err = io.ReadFull(c.reader, header[:]); nil != err {
+		return false, 0, nil, err
+	}
+	fin, opcode = 0 != header[0]&0x80, header[0]&0x0F
+	if 0 != header[0]&0x70 {
+		return false, 0, nil, fmt.Errorf("%w: reserved bits are set", ErrProtocol)
+	}
+	if 0 == header[1]&0x80 {
+		return false, 0, nil, fmt.Errorf("%w: the frame is not masked", ErrProtocol)
+	}
+	var length = uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err = io.ReadFull(c.reader, extended[:]); nil != err {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err = io.ReadFull(c.reader, extended[:]); nil != err {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended[:])
+	}
+	if opClose <= opcode && (125 < length || !fin) {
+		return false, 0, nil, fmt.Errorf("%w: control frames must be short and whole", ErrProtocol)
+	}
+	if MaxMessageSize < length {
+		return false, 0, nil, ErrMessageTooLarge
+	}
+	var mask [4]byte
+	if _, err = io.ReadFull(c.reader, mask[:]); nil != err {
+		return false, 0, nil, err
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.reader, payload); nil != err {
+		return false, 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return fin, opcode, payload, nil
+}
@@ -0,0 +1,183 @@
+package websocket
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const key = "dGhlIHNhbXBsZSBub25jZQ=="
+
+func TestAccept(t *testing.T) {
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", Accept(key))
+}
+
+// dial opens a WebSocket connection to a server whose connections are handled
+// by serve, which is given the error of Upgrade.
+func dial(t *testing.T, serve func(c *Conn, err error)) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	var server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		serve(c, err)
+	}))
+	t.Cleanup(server.Close)
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	_, err = io.WriteString(conn, "GET / HTTP/1.1\r\n"+
+		"Host: "+server.Listener.Addr().String()+"\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Key: "+key+"\r\n\r\n")
+	require.NoError(t, err)
+	var reader = bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusSwitchingProtocols, response.StatusCode)
+	require.Equal(t, Accept(key), response.Header.Get("Sec-WebSocket-Accept"))
+	return conn, reader
+}
+
+// send writes a frame as a client does, masked.
+func send(t *testing.T, conn net.Conn, first byte, payload []byte) {
+	t.Helper()
+	var mask = []byte{1, 2, 3, 4}
+	var frame = []byte{first}
+	if len(payload) < 126 {
+		frame = append(frame, 0x80|byte(len(payload)))
+	} else {
+		frame = binary.BigEndian.AppendUint16(append(frame, 0x80|126), uint16(len(payload)))
+	}
+	frame = append(frame, mask...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	_, err := conn.Write(frame)
+	require.NoError(t, err)
+}
+
+// receive reads a frame as a client does, unmasked.
+func receive(t *testing.T, reader *bufio.Reader) (first byte, payload []byte) {
+	t.Helper()
+	var header [2]byte
+	_, err := io.ReadFull(reader, header[:])
+	require.NoError(t, err)
+	var length = int(header[1] & 0x7F)
+	if 126 == length {
+		var extended [2]byte
+		_, err = io.ReadFull(reader, extended[:])
+		require.NoError(t, err)
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload = make([]byte, length)
+	_, err = io.ReadFull(reader, payload)
+	require.NoError(t, err)
+	return header[0], payload
+}
+
+func TestUpgrade(t *testing.T) {
+	t.Run("refuses a plain request", func(t *testing.T) {
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest("GET", "/", nil)
+		_, err := Upgrade(recorder, request)
+		assert.ErrorIs(t, err, ErrBadHandshake)
+	})
+
+	t.Run("refuses another version", func(t *testing.T) {
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Connection", "Upgrade")
+		request.Header.Set("Upgrade", "websocket")
+		request.Header.Set("Sec-WebSocket-Version", "8")
+		request.Header.Set("Sec-WebSocket-Key", key)
+		_, err := Upgrade(recorder, request)
+		assert.ErrorContains(t, err, "version 13")
+	})
+
+	t.Run("refuses a bad key", func(t *testing.T) {
+		var recorder = httptest.NewRecorder()
+		var request = httptest.NewRequest("GET", "/", nil)
+		request.Header.Set("Connection", "Upgrade")
+		request.Header.Set("Upgrade", "websocket")
+		request.Header.Set("Sec-WebSocket-Version", "13")
+		request.Header.Set("Sec-WebSocket-Key", "c2hvcnQ=")
+		_, err := Upgrade(recorder, request)
+		assert.ErrorContains(t, err, "16 bytes")
+	})
+}
+
+func TestConn(t *testing.T) {
+	t.Run("messages both ways", func(t *testing.T) {
+		var done = make(chan error, 1)
+		conn, reader := dial(t, func(c *Conn, err error) {
+			require.NoError(t, err)
+			data, err := c.ReadMessage()
+			if nil == err {
+				err = c.WriteText([]byte(strings.ToUpper(string(data))))
+			}
+			if nil == err {
+				err = c.WriteText([]byte(strings.Repeat("a", 300)))
+			}
+			if nil == err {
+				_, err = c.ReadMessage()
+			}
+			done <- err
+		})
+		send(t, conn, opText, []byte("hel"))
+		send(t, conn, 0x80|opContinuation, []byte("lo"))
+		first, payload := receive(t, reader)
+		assert.Equal(t, byte(0x80|opText), first)
+		assert.Equal(t, "HELLO", string(payload))
+		_, payload = receive(t, reader)
+		assert.Len(t, payload, 300)
+		send(t, conn, 0x80|opPing, []byte("hi"))
+		first, payload = receive(t, reader)
+		assert.Equal(t, byte(0x80|opPong), first)
+		assert.Equal(t, "hi", string(payload))
+		send(t, conn, 0x80|opClose, binary.BigEndian.AppendUint16(nil, CloseGoingAway))
+		first, payload = receive(t, reader)
+		assert.Equal(t, byte(0x80|opClose), first)
+		assert.Equal(t, uint16(CloseGoingAway), binary.BigEndian.Uint16(payload))
+		assert.ErrorIs(t, <-done, ErrClosed)
+	})
+
+	t.Run("unmasked frame", func(t *testing.T) {
+		var done = make(chan error, 1)
+		conn, reader := dial(t, func(c *Conn, err error) {
+			require.NoError(t, err)
+			_, err = c.ReadMessage()
+			done <- err
+		})
+		_, err := conn.Write([]byte{0x80 | opText, 2, 'h', 'i'})
+		require.NoError(t, err)
+		first, payload := receive(t, reader)
+		assert.Equal(t, byte(0x80|opClose), first)
+		assert.Equal(t, uint16(CloseProtocolError), binary.BigEndian.Uint16(payload))
+		assert.ErrorIs(t, <-done, ErrProtocol)
+	})
+
+	t.Run("server closes", func(t *testing.T) {
+		var done = make(chan error, 1)
+		_, reader := dial(t, func(c *Conn, err error) {
+			require.NoError(t, err)
+			c.Close(CloseTryAgainLater, "lagged")
+			done <- c.WriteText([]byte("late"))
+		})
+		first, payload := receive(t, reader)
+		assert.Equal(t, byte(0x80|opClose), first)
+		assert.Equal(t, uint16(CloseTryAgainLater), binary.BigEndian.Uint16(payload))
+		assert.Equal(t, "lagged", string(payload[2:]))
+		assert.ErrorIs(t, <-done, ErrClosed)
+		_, err := reader.ReadByte()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}